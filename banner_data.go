@@ -3,96 +3,1279 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/anomaly"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/banner"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/command"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/daemon"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/status"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/terminal"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
 )
 
-// bnMaxCacheAge is the maximum age of a cache file before it is considered
-// stale and skipped in banner rendering.
+// bnMaxCacheAge is the default maximum age of a cache file before it is
+// considered stale and skipped in banner rendering. Collectors with a
+// configured cache TTL use that instead.
 const bnMaxCacheAge = 5 * time.Minute
 
+// bnCacheTTL returns the staleness cutoff for key from ttls, falling back to
+// bnMaxCacheAge for keys without one.
+func bnCacheTTL(ttls map[string]time.Duration, key string) time.Duration {
+	if ttl := ttls[key]; ttl > 0 {
+		return ttl
+	}
+	return bnMaxCacheAge
+}
+
+// bnQuietHours returns the configured quiet hours, or nil when none are
+// set or they are invalid; the daemon reports an invalid window.
+func bnQuietHours(cfg *config.Config) *collectors.QuietHours {
+	qc := cfg.General.QuietHours
+	if !qc.Enabled() {
+		return nil
+	}
+	q, err := collectors.ParseQuietHours(qc.Start, qc.End, qc.Days, qc.Factor, qc.Pause)
+	if err != nil {
+		return nil
+	}
+	return q
+}
+
+// bnCacheTTLs returns the configured staleness cutoffs as they apply at
+// now, stretched while quiet hours slow the collectors down.
+func bnCacheTTLs(cfg *config.Config, now time.Time) map[string]time.Duration {
+	return bnQuietHours(cfg).CacheTTLs(cfg.Collectors.CacheTTLs(), now)
+}
+
+// bnOptions carries the config-derived settings that shape banner content.
+// The zero value uses defaults throughout.
+type bnOptions struct {
+	// CacheTTLs holds per-key staleness cutoffs (see
+	// config.CollectorsConfig.CacheTTLs). Quiet stretches them at read
+	// time while the collectors run less often.
+	CacheTTLs map[string]time.Duration
+	Quiet     *collectors.QuietHours
+
+	// ClaudeSort orders the per-account Claude lines.
+	ClaudeSort claude.SortMode
+
+	// PrimaryAccount is listed first among the Claude accounts, ahead of
+	// the ClaudeSort order.
+	PrimaryAccount string
+
+	// PIDFile is the daemon PID file consulted for the freshness line.
+	// Empty skips the daemon check.
+	PIDFile string
+
+	// Hyperlinks wraps node, cluster, and provider names in OSC 8 links
+	// to their dashboards.
+	Hyperlinks bool
+
+	// SnoozedUntil is the end of an active daemon SNOOZE. While it lies in
+	// the future, warnings are shown with the muted Snoozed glyph instead
+	// of Warn. It is read from the cache directory on every build.
+	SnoozedUntil time.Time
+
+	// Glyphs is the set of status and alert markers. The zero value is
+	// components.GlyphEmoji.
+	Glyphs components.GlyphSet
+
+	// Commands names the configured command collectors, in config order.
+	// Each with fresh cached output gets a key/value section.
+	Commands []string
+
+	// TailscaleAddress selects which of this node's addresses the
+	// Tailscale section shows. The zero value shows the IPv4 address.
+	TailscaleAddress tailscale.AddressDisplay
+
+	// Compact drops detail lines that do not fit the single-column
+	// compact layout, such as the Claude window progress bars, and labels
+	// Claude accounts with their short names.
+	Compact bool
+
+	// Money formats dollar amounts. The zero value shows cents without
+	// digit grouping.
+	Money components.MoneyFormat
+
+	// GraphStyle draws the per-account Claude history graphs. The zero
+	// value uses block sparklines.
+	GraphStyle components.GraphStyle
+
+	// CompareLastMonth adds a trend line to the billing section comparing
+	// this month's cumulative spend with last month's (see bnTrendLine).
+	CompareLastMonth bool
+
+	// HideZeroProviders leaves billing providers with no spend this month
+	// out of the provider line, noting how many were hidden.
+	HideZeroProviders bool
+
+	// SparklinePoints caps the points in the billing trend sparkline. Zero
+	// leaves only the width cap.
+	SparklinePoints int
+
+	// CriticalFirst moves widgets at warning or critical level to the
+	// front (see bnCriticalFirst).
+	CriticalFirst bool
+
+	// ResetStyle shows when Claude budget windows reset. The zero value
+	// shows the time left.
+	ResetStyle components.ResetStyle
+
+	// Clock12h formats wall-clock times as "3:45pm". The zero value uses
+	// a 24-hour clock, "15:45".
+	Clock12h bool
+
+	// ShowPlanName labels Claude accounts and organizations with the
+	// display name of their tier, except in the compact banner.
+	ShowPlanName bool
+
+	// ClaudeHeatmap adds a row with one cell per Claude account to the
+	// Claude section (see bnClaudeHeatmap).
+	ClaudeHeatmap bool
+
+	// DashboardURLs replaces the dashboard links of named providers,
+	// clusters and nodes (see config.DashboardURLs).
+	DashboardURLs config.DashboardURLs
+
+	// StatusRules grades the widgets for CriticalFirst. The zero value is
+	// status.DefaultEvaluatorConfig.
+	StatusRules status.EvaluatorConfig
+
+	// Width is the terminal width the banner is drawn at, which caps
+	// sparkline lengths. Zero means unknown: no width cap.
+	Width int
+
+	// Sections lists the widget IDs to show, in display order. Empty
+	// shows every section in the default order.
+	Sections []string
+
+	// HideSections lists widget IDs never to show.
+	HideSections []string
+}
+
+// bannerOptions derives bnOptions from cfg for the terminal prompt-pulse
+// runs in (see bnConfigOptions). Invalid settings are reported on stderr.
+// Hyperlinks are only emitted when display.enable_hyperlinks is set and
+// the terminal is known to support OSC 8, and display.glyphs "auto" or an
+// invalid value uses the set detected for the terminal.
+func bannerOptions(cfg *config.Config) bnOptions {
+	opts, warnings := bnConfigOptions(cfg)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "prompt-pulse: %s\n", w)
+	}
+	opts.Hyperlinks = opts.Hyperlinks && terminal.Detect().SupportsOSC8Hyperlinks()
+	if opts.Glyphs == "" {
+		opts.Glyphs = components.DetectGlyphSet()
+	}
+	return opts
+}
+
+// bnConfigOptions derives bnOptions from cfg alone, without printing or
+// looking at the terminal, for the daemon, which renders banners for
+// terminals it cannot see. An invalid display.claude_sort,
+// display.primary_account, display.graph_style, display.glyphs,
+// display.reset_time_format, collectors.tailscale.address_display, money
+// or banner section setting falls back to the default, so a typo never
+// blanks the banner, and is returned as a warning. Hyperlinks follows
+// display.enable_hyperlinks, and display.glyphs "auto" leaves Glyphs
+// unset, which draws GlyphEmoji.
+func bnConfigOptions(cfg *config.Config) (bnOptions, []string) {
+	var warnings []string
+	mode, err := claude.ParseSortMode(cfg.Display.ClaudeSort)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("display.claude_sort: %v", err))
+		mode = claude.SortConfig
+	}
+	primary := cfg.Display.PrimaryAccount
+	for _, d := range config.ValidatePrimaryAccount(cfg) {
+		warnings = append(warnings, "display."+d.Message)
+		primary = ""
+	}
+	graph, err := components.ParseGraphStyle(cfg.Display.GraphStyle)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("display.graph_style: %v", err))
+		graph = components.GraphBlock
+	}
+	reset, err := components.ParseResetStyle(cfg.Display.ResetTimeFormat)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("display.reset_time_format: %v", err))
+		reset = components.ResetRelative
+	}
+	addr, err := tailscale.ParseAddressDisplay(cfg.Collectors.Tailscale.AddressDisplay)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("collectors.tailscale.address_display: %v", err))
+		addr = tailscale.AddressIPv4
+	}
+	// "auto" is left to the caller that can see the terminal; until then
+	// the zero value means GlyphEmoji.
+	var glyphs components.GlyphSet
+	if g := cfg.Display.Glyphs; g != "" && !strings.EqualFold(g, "auto") {
+		if glyphs, err = components.ParseGlyphSet(g); err != nil {
+			warnings = append(warnings, fmt.Sprintf("display.glyphs: %v", err))
+		}
+	}
+	money, moneyWarnings := bnConfigMoneyFormat(cfg)
+	warnings = append(warnings, moneyWarnings...)
+	var commands []string
+	for _, cc := range cfg.Collectors.Commands {
+		commands = append(commands, cc.Name)
+	}
+	sections, hidden, sectionWarnings := bnSections(cfg, commands)
+	warnings = append(warnings, sectionWarnings...)
+	return bnOptions{
+		CacheTTLs:         cfg.Collectors.CacheTTLs(),
+		Quiet:             bnQuietHours(cfg),
+		ClaudeSort:        mode,
+		PrimaryAccount:    primary,
+		PIDFile:           daemon.DefaultConfig().PIDFile,
+		Hyperlinks:        cfg.Display.EnableHyperlinks,
+		Commands:          commands,
+		TailscaleAddress:  addr,
+		Money:             money,
+		GraphStyle:        graph,
+		CompareLastMonth:  cfg.Display.CompareLastMonth,
+		HideZeroProviders: cfg.Display.HideZeroProviders,
+		SparklinePoints:   cfg.Display.SparklinePoints,
+		Glyphs:            glyphs,
+		CriticalFirst:     cfg.Display.CriticalFirst,
+		ResetStyle:        reset,
+		Clock12h:          !cfg.Display.Clock24h,
+		ShowPlanName:      cfg.Display.ShowPlanName,
+		ClaudeHeatmap:     cfg.Display.ClaudeHeatmap,
+		DashboardURLs:     cfg.Display.DashboardURLs,
+		StatusRules:       daemon.StatusRules(cfg.Status),
+		Sections:          sections,
+		HideSections:      hidden,
+	}, warnings
+}
+
+// bnConfigWarnings returns the warnings bnConfigOptions finds in cfg. The
+// daemon logs them when it loads a config instead of on every banner it
+// builds.
+func bnConfigWarnings(cfg *config.Config) []string {
+	_, warnings := bnConfigOptions(cfg)
+	return warnings
+}
+
+// bnSections resolves banner.sections and banner.hide_sections, dropping
+// names that are neither a built-in section nor one of commands and
+// returning a warning for each.
+func bnSections(cfg *config.Config, commands []string) (sections, hidden, warnings []string) {
+	for _, d := range config.ValidateBannerSections(cfg) {
+		warnings = append(warnings, "banner."+d.Message)
+	}
+	unknown := func(name string) bool {
+		return !slices.Contains(config.BannerSections, name) && !slices.Contains(commands, name)
+	}
+	sections = slices.DeleteFunc(slices.Clone(cfg.Banner.Sections), unknown)
+	hidden = slices.DeleteFunc(slices.Clone(cfg.Banner.HideSections), unknown)
+	return sections, hidden, warnings
+}
+
+// bnShowSection reports whether the widget id passes opts.Sections and
+// opts.HideSections. Sections that fail it are not read from the cache.
+func bnShowSection(opts bnOptions, id string) bool {
+	if len(opts.Sections) > 0 && !slices.Contains(opts.Sections, id) {
+		return false
+	}
+	return !slices.Contains(opts.HideSections, id)
+}
+
+// bnOrderSections puts widgets in opts.Sections order. Without an
+// allowlist they keep their default order.
+func bnOrderSections(widgets []banner.WidgetData, opts bnOptions) []banner.WidgetData {
+	if len(opts.Sections) == 0 {
+		return widgets
+	}
+	slices.SortStableFunc(widgets, func(a, b banner.WidgetData) int {
+		return slices.Index(opts.Sections, a.ID) - slices.Index(opts.Sections, b.ID)
+	})
+	return widgets
+}
+
+// bnGlyphSet resolves display.glyphs, reporting an invalid value on stderr
+// and detecting the set in its place.
+func bnGlyphSet(cfg *config.Config) components.GlyphSet {
+	g, err := components.ParseGlyphSet(cfg.Display.Glyphs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "prompt-pulse: display.glyphs: %v\n", err)
+		g = components.DetectGlyphSet()
+	}
+	return g
+}
+
+// bnMoneyFormat derives the dollar-amount format from the display config,
+// reporting invalid values on stderr and using the default in their place.
+func bnMoneyFormat(cfg *config.Config) components.MoneyFormat {
+	f, warnings := bnConfigMoneyFormat(cfg)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "prompt-pulse: %s\n", w)
+	}
+	return f
+}
+
+// bnConfigMoneyFormat is bnMoneyFormat without the printing: invalid
+// values are returned as warnings.
+func bnConfigMoneyFormat(cfg *config.Config) (components.MoneyFormat, []string) {
+	var warnings []string
+	sep, err := components.ParseThousandsSeparator(cfg.Display.ThousandsSeparator)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("display.thousands_separator: %v", err))
+		sep = components.SeparatorNone
+	}
+	f := components.MoneyFormat{Separator: sep}
+	switch cfg.Display.MoneyDecimals {
+	case 0:
+		f.Whole = true
+	case 2:
+	default:
+		warnings = append(warnings, fmt.Sprintf("display.money_decimals: unsupported value %d (supported: 0, 2)", cfg.Display.MoneyDecimals))
+	}
+	return f, warnings
+}
+
+// bnWarmData builds the banner the daemon pre-renders for a width x height
+// terminal (see banner.warm_sizes). It uses bnConfigOptions, so it prints
+// nothing and ignores the daemon's own environment; the daemon reports the
+// config's warnings through bnConfigWarnings instead.
+func bnWarmData(cfg *config.Config, width, height int) banner.BannerData {
+	preset := banner.SelectPreset(width, height)
+	opts, _ := bnConfigOptions(cfg)
+	opts.Compact = preset == banner.Compact || preset == banner.Portrait
+	opts.Width = width
+	return buildBannerFromCache(cfg.General.CacheDir, opts, version, commit)
+}
+
 // buildBannerFromCache reads cached collector JSON files written by the daemon
 // and assembles them into BannerData widgets for the banner renderer.
-func buildBannerFromCache(cacheDir, ver, commit string) banner.BannerData {
-	widgets := []banner.WidgetData{
-		{
+func buildBannerFromCache(cacheDir string, opts bnOptions, ver, commit string) banner.BannerData {
+	now := time.Now()
+	ttls := opts.Quiet.CacheTTLs(opts.CacheTTLs, now)
+	if until := daemon.ReadSnooze(cacheDir); now.Before(until) {
+		opts.SnoozedUntil = until
+	}
+	var widgets []banner.WidgetData
+	if bnShowSection(opts, "status") {
+		status := []string{
+			fmt.Sprintf("prompt-pulse v%s (%s)", ver, commit),
+			bnFreshnessLine(cacheDir, ttls, opts.PIDFile, now, opts.Glyphs.Glyphs()),
+		}
+		if !opts.SnoozedUntil.IsZero() {
+			status = append(status, bnStatusColor(opts.Glyphs.Glyphs().Snoozed+" alerts snoozed until "+bnClock(opts.SnoozedUntil, opts), theme.Current.Dim))
+		}
+		widgets = append(widgets, banner.WidgetData{
 			ID:      "status",
 			Title:   "System Status",
-			Content: fmt.Sprintf("prompt-pulse v%s (%s)", ver, commit),
+			Content: strings.Join(status, "\n"),
 			MinW:    30,
-			MinH:    3,
-		},
-	}
-
-	if m, err := bnReadCache[sysmetrics.Metrics](cacheDir, "sysmetrics"); err == nil && m != nil {
-		content := fmt.Sprintf("CPU: %.0f%%  RAM: %.0f%%\nLoad: %.1f / %.1f / %.1f\nUptime: %s",
-			m.CPU.Total, m.Memory.UsedPercent,
-			m.Load.Load1, m.Load.Load5, m.Load.Load15,
-			bnFormatUptime(m.Uptime))
-		widgets = append(widgets, banner.WidgetData{
-			ID: "system", Title: "System", Content: content, MinW: 30, MinH: 5,
+			MinH:    len(status) + 2,
 		})
 	}
+	// reports maps widget IDs to the report each was drawn from, for
+	// bnCriticalFirst.
+	reports := make(map[string]interface{})
 
-	if s, err := bnReadCache[tailscale.Status](cacheDir, "tailscale"); err == nil && s != nil {
-		content := fmt.Sprintf("Peers: %d/%d online\nNet: %s",
-			s.OnlinePeers, s.TotalPeers, s.TailnetName)
-		widgets = append(widgets, banner.WidgetData{
-			ID: "tailscale", Title: "Tailscale", Content: content, MinW: 25, MinH: 4,
-		})
+	if bnShowSection(opts, "system") {
+		if m, err := bnReadCache[sysmetrics.Metrics](cacheDir, "sysmetrics", bnCacheTTL(ttls, "sysmetrics")); err == nil && m != nil {
+			content := fmt.Sprintf("CPU: %.0f%%  RAM: %.0f%%\nLoad: %.1f / %.1f / %.1f\nUptime: %s",
+				m.CPU.Total, m.Memory.UsedPercent,
+				m.Load.Load1, m.Load.Load5, m.Load.Load15,
+				bnFormatUptime(m.Uptime))
+			widgets = append(widgets, banner.WidgetData{
+				ID: "system", Title: "System", Content: content, MinW: 30, MinH: 5,
+			})
+			reports["system"] = m
+		}
 	}
 
-	if cs, err := bnReadCache[k8s.ClusterStatus](cacheDir, "k8s"); err == nil && cs != nil {
-		var total, running, failed int
-		for _, c := range cs.Clusters {
-			if c.Connected {
-				total += c.TotalPods
-				running += c.RunningPods
-				failed += c.FailedPods
+	if bnShowSection(opts, "tailscale") {
+		if s, err := bnReadCache[tailscale.Status](cacheDir, "tailscale", bnCacheTTL(ttls, "tailscale")); err == nil && s != nil {
+			lines := []string{
+				fmt.Sprintf("Peers: %d/%d online", s.OnlinePeers, s.TotalPeers),
+				"Net: " + s.TailnetName,
+			}
+			if a := s.Self.Address(opts.TailscaleAddress); a != "" {
+				lines = append(lines, "Addr: "+a)
+			}
+			if s.ExitNode != nil {
+				lines = append(lines, "Exit: ↗ "+bnHyperlink(opts, s.ExitNode.Hostname,
+					opts.DashboardURLs.Resolve("tailscale", s.ExitNode.Hostname, "", s.ExitNode.DashboardURL())))
+			}
+			if n := bnSubnetRouters(s); n > 0 {
+				lines = append(lines, fmt.Sprintf("Subnet routers: %d", n))
 			}
+			if stale := s.StalePeers(); len(stale) > 0 {
+				names := make([]string, len(stale))
+				for i, p := range stale {
+					names[i] = p.Hostname
+				}
+				lines = append(lines, bnStatusColor(bnAlertGlyph(opts)+" stale: "+strings.Join(names, ", "), theme.Current.StatusWarn))
+			}
+			widgets = append(widgets, banner.WidgetData{
+				ID: "tailscale", Title: "Tailscale", Content: strings.Join(lines, "\n"),
+				MinW: 25, MinH: len(lines) + 2,
+			})
+			reports["tailscale"] = s
 		}
-		if total > 0 {
-			content := fmt.Sprintf("Pods: %d/%d running", running, total)
-			if failed > 0 {
-				content += fmt.Sprintf(" (%d failed)", failed)
+	}
+
+	if bnShowSection(opts, "k8s") {
+		if cs, err := bnReadCache[k8s.ClusterStatus](cacheDir, "k8s", bnCacheTTL(ttls, "k8s")); err == nil && cs != nil {
+			var total, running, failed int
+			for _, c := range cs.Clusters {
+				if c.Connected {
+					total += c.TotalPods
+					running += c.RunningPods
+					failed += c.FailedPods
+				}
 			}
+			if total > 0 {
+				content := fmt.Sprintf("Pods: %d/%d running", running, total)
+				if failed > 0 {
+					content += fmt.Sprintf(" (%d failed)", failed)
+				}
+				lines := append([]string{content}, bnPodPressureLines(cs, opts)...)
+				widgets = append(widgets, banner.WidgetData{
+					ID: "k8s", Title: "Kubernetes", Content: strings.Join(lines, "\n"),
+					MinW: 25, MinH: len(lines) + 2,
+				})
+				reports["k8s"] = cs
+			}
+		}
+	}
+
+	if bnShowSection(opts, "claude") {
+		if r, err := bnReadCache[claude.UsageReport](cacheDir, "claude", bnCacheTTL(ttls, "claude")); err == nil && r != nil {
+			lines := append([]string{"Cost: " + components.FormatMoney(r.TotalCostUSD, opts.Money)},
+				bnClaudeAccountLines(cacheDir, r, opts, now)...)
 			widgets = append(widgets, banner.WidgetData{
-				ID: "k8s", Title: "Kubernetes", Content: content, MinW: 25, MinH: 3,
+				ID: "claude", Title: "Claude", Content: strings.Join(lines, "\n"),
+				MinW: 20, MinH: len(lines) + 2,
 			})
+			reports["claude"] = r
 		}
 	}
 
-	if r, err := bnReadCache[claude.UsageReport](cacheDir, "claude"); err == nil && r != nil {
-		content := fmt.Sprintf("Cost: $%.2f", r.TotalCostUSD)
-		widgets = append(widgets, banner.WidgetData{
-			ID: "claude", Title: "Claude", Content: content, MinW: 20, MinH: 3,
-		})
+	if bnShowSection(opts, "billing") {
+		if b, err := bnReadCache[billing.BillingReport](cacheDir, "billing", bnCacheTTL(ttls, "billing")); err == nil && b != nil {
+			content := "Spend: " + components.FormatMoney(b.TotalMonthlyUSD, opts.Money) + "/mo"
+			if b.HasCredits() {
+				content = "Spend: " + components.FormatMoney(b.NetMonthlyUSD, opts.Money) + "/mo (net)"
+			}
+			if b.BudgetUSD > 0 {
+				if phrase := bnPacePhrases[b.BudgetPace]; phrase != "" {
+					content += fmt.Sprintf(" (%.0f%% of budget, %s)", b.BudgetPercent, phrase)
+				} else {
+					content += fmt.Sprintf(" (%.0f%% of budget)", b.BudgetPercent)
+				}
+			}
+			lines := []string{content}
+			if line := bnProviderLine(b, opts); line != "" {
+				lines = append(lines, line)
+			}
+			lines = append(lines, bnShareLines(bnProviderShares(b), opts)...)
+			if opts.CompareLastMonth && !opts.Compact {
+				if line := bnTrendLine(cacheDir, now, bnSparkPoints(opts)); line != "" {
+					lines = append(lines, line)
+				}
+			}
+			if line := bnVelocityLine(cacheDir, now, opts); line != "" {
+				lines = append(lines, line)
+			}
+			if b.Anomaly != nil {
+				lines = append(lines, bnAlertGlyph(opts)+" spike "+b.Anomaly.String())
+			}
+			if b.ProjectedOverBudget {
+				usd, _ := b.ProjectedOverage()
+				lines = append(lines, fmt.Sprintf("📈 projected %s, %s over budget",
+					components.FormatMoney(b.ForecastUSD, opts.Money), components.FormatMoney(usd, opts.Money)))
+			}
+			widgets = append(widgets, banner.WidgetData{
+				ID: "billing", Title: "Cloud Billing", Content: strings.Join(lines, "\n"),
+				MinW: 25, MinH: len(lines) + 2,
+			})
+			reports["billing"] = b
+		}
 	}
 
-	if b, err := bnReadCache[billing.BillingReport](cacheDir, "billing"); err == nil && b != nil {
-		content := fmt.Sprintf("Spend: $%.2f/mo", b.TotalMonthlyUSD)
-		if b.BudgetUSD > 0 {
-			content += fmt.Sprintf(" (%.0f%% of budget)", b.BudgetPercent)
+	for _, name := range opts.Commands {
+		if !bnShowSection(opts, name) {
+			continue
+		}
+		if r, err := bnReadCache[command.Result](cacheDir, name, bnCacheTTL(ttls, name)); err == nil && r != nil && len(r.Data) > 0 {
+			lines := bnKeyValueLines(r.Data)
+			title := r.Title
+			if title == "" {
+				title = name
+			}
+			widgets = append(widgets, banner.WidgetData{
+				ID: name, Title: title, Content: strings.Join(lines, "\n"),
+				MinW: 20, MinH: len(lines) + 2,
+			})
 		}
-		widgets = append(widgets, banner.WidgetData{
-			ID: "billing", Title: "Cloud Billing", Content: content, MinW: 25, MinH: 3,
-		})
 	}
 
+	widgets = bnOrderSections(widgets, opts)
+	if opts.CriticalFirst {
+		widgets = bnCriticalFirst(widgets, reports, opts)
+	}
 	return banner.BannerData{Widgets: widgets}
 }
 
+// bnCriticalFirst moves the widgets whose report status.Evaluator grades,
+// by opts.StatusRules, at warning or critical level to the front, critical
+// before warning, and marks their titles with the glyph set's Crit or
+// bnAlertGlyph. The rest, including the status widget, keep their order
+// after them. reports maps widget IDs to the report each was drawn from.
+func bnCriticalFirst(widgets []banner.WidgetData, reports map[string]interface{}, opts bnOptions) []banner.WidgetData {
+	levels := make(map[string]status.Level, len(reports))
+	for id, data := range reports {
+		ev := status.NewEvaluator(opts.StatusRules)
+		ev.Observe(id, data)
+		if lvl := ev.Evaluate().Level; lvl != status.LevelHealthy {
+			levels[id] = lvl
+		}
+	}
+	if len(levels) == 0 {
+		return widgets
+	}
+
+	out := make([]banner.WidgetData, 0, len(widgets))
+	for _, lvl := range []status.Level{status.LevelCritical, status.LevelWarning} {
+		glyph := bnAlertGlyph(opts)
+		if lvl == status.LevelCritical {
+			glyph = opts.Glyphs.Glyphs().Crit
+		}
+		for _, w := range widgets {
+			if levels[w.ID] == lvl {
+				w.Title = glyph + " " + w.Title
+				out = append(out, w)
+			}
+		}
+	}
+	for _, w := range widgets {
+		if _, ok := levels[w.ID]; !ok {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// bnKeyValueLines renders a command collector's data as "key: value"
+// lines sorted by key, with keys padded to a common width.
+func bnKeyValueLines(data map[string]interface{}) []string {
+	keys := make([]string, 0, len(data))
+	keyW := 0
+	for k := range data {
+		keys = append(keys, k)
+		if n := components.VisibleLen(k); n > keyW {
+			keyW = n
+		}
+	}
+	sort.Strings(keys)
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		v := data[k]
+		if v == nil {
+			v = "—"
+		}
+		lines[i] = fmt.Sprintf("%s %v", components.PadRight(k+":", keyW+1), v)
+	}
+	return lines
+}
+
+// bnCacheKeys lists the cache entries the banner reads, in widget order.
+var bnCacheKeys = []string{"sysmetrics", "tailscale", "k8s", "claude", "billing"}
+
+// bnFreshnessLine summarizes how current the cached data is: the age of the
+// freshest entry while everything is within its TTL, the age of the stalest
+// entry once any has expired, or a warning that the daemon is not running
+// when the data is stale or missing. Fresh data without a daemon, as kept
+// by -collect-once from cron, is not a problem. Warnings use the active
+// theme's status colors.
+func bnFreshnessLine(cacheDir string, ttls map[string]time.Duration, pidFile string, now time.Time, g components.Glyphs) string {
+	var freshest, stalest time.Duration
+	found, stale := false, false
+	for _, key := range bnCacheKeys {
+		info, err := os.Stat(filepath.Join(cacheDir, key+".json"))
+		if err != nil {
+			continue
+		}
+		age := now.Sub(info.ModTime())
+		if age < 0 {
+			age = 0
+		}
+		if !found || age < freshest {
+			freshest = age
+		}
+		if age > bnCacheTTL(ttls, key) && (!stale || age > stalest) {
+			stalest, stale = age, true
+		}
+		found = true
+	}
+
+	if pidFile != "" && (stale || !found) && !bnDaemonRunning(pidFile) {
+		line := g.Warn + " daemon not running"
+		if found {
+			line += fmt.Sprintf(" (updated %s ago)", bnFormatAge(freshest))
+		}
+		return bnStatusColor(line, theme.Current.StatusError)
+	}
+	switch {
+	case !found:
+		return bnStatusColor("no cached data yet", theme.Current.StatusWarn)
+	case stale:
+		return bnStatusColor(g.Warn+" stale "+bnFormatAge(stalest), theme.Current.StatusWarn)
+	default:
+		return fmt.Sprintf("updated %s ago", bnFormatAge(freshest))
+	}
+}
+
+// bnDaemonRunning reports whether the PID file names a live process.
+func bnDaemonRunning(pidFile string) bool {
+	pid, err := daemon.ReadPID(pidFile)
+	return err == nil && daemon.IsProcessAlive(pid)
+}
+
+// bnStatusColor wraps s in the given theme color when color is enabled.
+func bnStatusColor(s, hex string) string {
+	if !bnColorEnabled() {
+		return s
+	}
+	if c := components.Color(hex); c != "" {
+		return c + s + components.Reset()
+	}
+	return s
+}
+
+// bnFormatAge formats a cache age like "45s", "2m" or "1h 12m".
+func bnFormatAge(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return bnFormatUptime(d)
+}
+
+// bnSubnetRouters counts the online peers that advertise subnet routes.
+func bnSubnetRouters(s *tailscale.Status) int {
+	n := 0
+	for _, p := range s.Peers {
+		if p.Online && len(p.AdvertisedRoutes) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// bnPodPressureLines flags each cluster near its pod capacity together with
+// its fullest node.
+func bnPodPressureLines(cs *k8s.ClusterStatus, opts bnOptions) []string {
+	var lines []string
+	for _, c := range cs.Clusters {
+		if !c.UnderPodPressure(cs.PodPressureThreshold) {
+			continue
+		}
+		line := fmt.Sprintf("%s %s pods %.0f%%", bnAlertGlyph(opts), bnHyperlink(opts, c.Context,
+			opts.DashboardURLs.Resolve("k8s", c.Context, c.Region, c.DashboardURL)), c.PodPressure())
+		if node, _, ok := c.BusiestNode(); ok {
+			line += fmt.Sprintf(" (%s %d/%d)", node.Name, node.PodCount, node.MaxPods)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// bnProviderLine lists each connected billing provider with its
+// month-to-date spend, or returns "" when none are connected. A provider
+// with monthly credits shows its spend after credits, marked "(net)". A
+// provider with no spend shows as "$0" whatever the money format; with
+// opts.HideZeroProviders it is left out and counted in a trailing
+// "+N at $0" note instead.
+func bnProviderLine(b *billing.BillingReport, opts bnOptions) string {
+	var parts []string
+	hidden := 0
+	for _, p := range b.Providers {
+		if !p.Connected {
+			continue
+		}
+		usd, suffix := p.MonthToDate, ""
+		if p.CreditsUSD > 0 {
+			usd, suffix = p.NetSpendUSD, " (net)"
+		}
+		amount := components.FormatMoney(usd, opts.Money)
+		if bnZeroSpend(usd) {
+			if opts.HideZeroProviders {
+				hidden++
+				continue
+			}
+			amount = "$0"
+		}
+		amount += suffix
+		parts = append(parts, bnHyperlink(opts, p.Name,
+			opts.DashboardURLs.Resolve("billing", p.Name, p.Region, p.DashboardURL))+" "+amount)
+	}
+	if hidden > 0 {
+		parts = append(parts, fmt.Sprintf("+%d at $0", hidden))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// bnZeroSpend reports whether usd rounds to nothing at cent precision.
+func bnZeroSpend(usd float64) bool {
+	return math.Abs(usd) < 0.005
+}
+
+// bnProviderShare is one billing provider's part of the month's spend.
+type bnProviderShare struct {
+	Name    string
+	USD     float64
+	Percent float64
+}
+
+// bnProviderShares returns each connected provider's share of
+// TotalMonthlyUSD, largest first. It returns nil when the total is zero,
+// so there is nothing to divide.
+func bnProviderShares(b *billing.BillingReport) []bnProviderShare {
+	if b.TotalMonthlyUSD <= 0 {
+		return nil
+	}
+	var shares []bnProviderShare
+	for _, p := range b.Providers {
+		if p.Connected && p.MonthToDate > 0 && !bnZeroSpend(p.MonthToDate) {
+			shares = append(shares, bnProviderShare{
+				Name:    p.Name,
+				USD:     p.MonthToDate,
+				Percent: p.MonthToDate / b.TotalMonthlyUSD * 100,
+			})
+		}
+	}
+	sort.SliceStable(shares, func(i, j int) bool { return shares[i].USD > shares[j].USD })
+	return shares
+}
+
+// bnShareBarWidth is the width of the provider share bar, the content
+// width of the billing widget at its minimum size.
+const bnShareBarWidth = 20
+
+// bnShareGlyphs fill the segments of the provider share bar, one per
+// provider in order, so adjacent segments stay distinct without color.
+var bnShareGlyphs = []string{"█", "▓", "▒", "░"}
+
+// bnShareLines shows how the month's spend splits across providers: a
+// stacked bar and a legend of percentages, or only the percentages when
+// opts.Compact is set. A single provider is its whole bill, so fewer than
+// two shares show nothing.
+func bnShareLines(shares []bnProviderShare, opts bnOptions) []string {
+	if len(shares) < 2 {
+		return nil
+	}
+	legend := make([]string, len(shares))
+	for i, sh := range shares {
+		legend[i] = fmt.Sprintf("%s %.0f%%", sh.Name, sh.Percent)
+		if !opts.Compact {
+			legend[i] = bnShareGlyphs[i%len(bnShareGlyphs)] + " " + legend[i]
+		}
+	}
+	if opts.Compact {
+		return []string{strings.Join(legend, " · ")}
+	}
+	return []string{bnShareBar(shares, bnShareBarWidth), strings.Join(legend, " · ")}
+}
+
+// bnShareBar draws shares as a stacked bar of width cells. Cells are
+// apportioned by largest remainder, so the segments always fill the bar
+// exactly; a provider with a very small share may get no cell.
+func bnShareBar(shares []bnProviderShare, width int) string {
+	var total float64
+	for _, sh := range shares {
+		total += sh.USD
+	}
+	cells := make([]int, len(shares))
+	rem := make([]float64, len(shares))
+	used := 0
+	for i, sh := range shares {
+		exact := sh.USD / total * float64(width)
+		cells[i] = int(exact)
+		rem[i] = exact - float64(cells[i])
+		used += cells[i]
+	}
+	order := make([]int, len(shares))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return rem[order[a]] > rem[order[b]] })
+	for i := 0; used < width; i++ {
+		cells[order[i%len(order)]]++
+		used++
+	}
+	var b strings.Builder
+	for i, n := range cells {
+		b.WriteString(strings.Repeat(bnShareGlyphs[i%len(bnShareGlyphs)], n))
+	}
+	return b.String()
+}
+
+// bnTrendBlocks are the sparkline levels of the billing trend line, lowest
+// first.
+var bnTrendBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// bnTrendLine renders the billing trend from the persisted spend history:
+// this month's cumulative spend per day, overlaid on last month's curve
+// with the difference at the same day of the month, e.g.
+// "Trend ▁▂▃▄▅▆▆▆ +12% vs last month". Without a complete last month it
+// shows this month's curve alone. It returns "" until two days of this
+// month have been recorded. When the month has more days than points (if
+// positive), both curves are averaged down by the same factor so they stay
+// aligned; the comparison still uses the daily figures.
+func bnTrendLine(cacheDir string, now time.Time, points int) string {
+	h, err := billing.LoadSpendHistory(billing.SpendHistoryPath(cacheDir))
+	if err != nil {
+		return ""
+	}
+	current, baseline := h.MonthCurves(now)
+	if len(current) < 2 {
+		return ""
+	}
+	var cmp string
+	if day := len(current) - 1; day < len(baseline) && baseline[day] > 0 {
+		cmp = fmt.Sprintf(" %+.0f%% vs last month", (current[day]/baseline[day]-1)*100)
+	}
+	if n := max(len(current), len(baseline)); points > 0 && n > points {
+		scale := func(values []float64) []float64 {
+			return components.Downsample(values, (len(values)*points+n-1)/n)
+		}
+		current, baseline = scale(current), scale(baseline)
+	}
+	return "Trend " + renderOverlaySparkline(current, baseline) + cmp
+}
+
+// bnVelocityLine renders today's spend against the average day from the
+// persisted spend history, e.g. "Today $9.00, 1.8× avg pace" (see
+// anomaly.Velocity), in the warning color from anomaly.VelocityWarn unless
+// alerts are snoozed. It returns "" until the history can tell.
+func bnVelocityLine(cacheDir string, now time.Time, opts bnOptions) string {
+	h, err := billing.LoadSpendHistory(billing.SpendHistoryPath(cacheDir))
+	if err != nil {
+		return ""
+	}
+	ratio, ok := anomaly.Velocity(h.Days, now)
+	if !ok {
+		return ""
+	}
+	line := fmt.Sprintf("Today %s, %.1f× avg pace", components.FormatMoney(h.Days[len(h.Days)-1].SpendUSD, opts.Money), ratio)
+	if ratio >= anomaly.VelocityWarn && opts.SnoozedUntil.IsZero() {
+		return bnStatusColor(line, theme.Current.StatusWarn)
+	}
+	return line
+}
+
+// bnTrendReserve is the width the trend line's label, comparison and
+// widget border take around the sparkline.
+const bnTrendReserve = 30
+
+// bnMinSparkPoints is the fewest points the width cap leaves a sparkline.
+const bnMinSparkPoints = 4
+
+// bnSparkPoints returns how many points the billing trend sparkline may
+// draw: opts.SparklinePoints, capped by the room the billing widget has at
+// opts.Width. Outside the compact layout the widget shares its row, so it
+// is assumed to get half the width. Zero means no limit.
+func bnSparkPoints(opts bnOptions) int {
+	points := opts.SparklinePoints
+	if opts.Width > 0 {
+		room := opts.Width
+		if !opts.Compact {
+			room /= 2
+		}
+		room = max(room-bnTrendReserve, bnMinSparkPoints)
+		if points <= 0 || points > room {
+			points = room
+		}
+	}
+	return points
+}
+
+// renderOverlaySparkline draws current as a block sparkline over
+// baseline, one cell per value on a shared scale from zero, so equal
+// spend reaches equal heights. With color, cells where current runs above
+// baseline take the warning color and the rest the OK color, and the
+// cells baseline has beyond the end of current show its remaining curve
+// in the theme's dim color. Without color a faint baseline cannot be told
+// apart, so only current is drawn. An empty baseline draws current alone.
+func renderOverlaySparkline(current, baseline []float64) string {
+	color := bnColorEnabled()
+	n := len(current)
+	if color && len(baseline) > n {
+		n = len(baseline)
+	}
+	top := 0.0
+	for _, v := range current {
+		top = math.Max(top, v)
+	}
+	for _, v := range baseline {
+		top = math.Max(top, v)
+	}
+	level := func(v float64) string {
+		if top <= 0 {
+			return string(bnTrendBlocks[0])
+		}
+		i := int(math.Round(math.Max(v, 0) / top * float64(len(bnTrendBlocks)-1)))
+		return string(bnTrendBlocks[i])
+	}
+
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		switch {
+		case i >= len(current):
+			b.WriteString(bnStatusColor(level(baseline[i]), theme.Current.Dim))
+		case !color:
+			b.WriteString(level(current[i]))
+		case i < len(baseline) && current[i] > baseline[i]:
+			b.WriteString(bnStatusColor(level(current[i]), theme.Current.StatusWarn))
+		default:
+			b.WriteString(bnStatusColor(level(current[i]), theme.Current.StatusOK))
+		}
+	}
+	return b.String()
+}
+
+// bnHyperlink wraps text in an OSC 8 link to url when opts enables
+// hyperlinks, and returns it unchanged otherwise or when url is empty. The
+// escapes take no cells, so layout is the same either way.
+func bnHyperlink(opts bnOptions, text, url string) string {
+	if !opts.Hyperlinks {
+		return text
+	}
+	return components.Hyperlink(text, url)
+}
+
+// bnAlertGlyph is the marker for a widget warning: the glyph set's Warn
+// normally, or its muted Snoozed while alerts are snoozed.
+func bnAlertGlyph(opts bnOptions) string {
+	if !opts.SnoozedUntil.IsZero() {
+		return opts.Glyphs.Glyphs().Snoozed
+	}
+	return opts.Glyphs.Glyphs().Warn
+}
+
+// bnPacePhrases maps billing budget pace classifications to the short phrase
+// shown in the billing widget.
+var bnPacePhrases = map[string]string{
+	billing.PaceOverPace: "over pace",
+	billing.PaceOnTrack:  "on track",
+	billing.PaceAhead:    "under pace",
+}
+
+// bnClaudeSparkWidth is the number of cells used for per-account Claude
+// history sparklines in the banner.
+const bnClaudeSparkWidth = 12
+
+// bnSortAccounts orders accounts by opts.ClaudeSort with
+// opts.PrimaryAccount pinned first.
+func bnSortAccounts(accounts []claude.AccountUsage, opts bnOptions) []claude.AccountUsage {
+	return claude.PinPrimary(claude.SortAccounts(accounts, opts.ClaudeSort), opts.PrimaryAccount)
+}
+
+// bnClaudeAccountLines renders one line per account in the report with its
+// month-to-date cost, its input and output tokens abbreviated by
+// components.FormatCount unless opts.Compact is set, and, once at least
+// two samples have been recorded, a
+// sparkline of the persisted cost history, in the given sort order. For accounts with a budget the
+// sparkline is graded against the warn and crit thresholds in theme colors
+// unless NO_COLOR is set or alerts are snoozed; with opts.GraphStyle set to
+// braille it is drawn as a Braille line colored by the account's level
+// instead. When the recent burn rate would spend the budget before it
+// resets, "~runs out in 40m" follows (see claude.ProjectExhaustion). A
+// bnWindowBar line
+// follows unless opts.Compact is set, in which case accounts are labelled
+// with their short names; otherwise, with opts.ShowPlanName, names carry
+// their plan (see bnPlanLabel). Organizations follow as "org" rows
+// (see bnClaudeOrgLine). Accounts and organizations at or above their
+// warning threshold are marked with bnAlertGlyph. With opts.ClaudeHeatmap
+// a bnClaudeHeatmap row, and outside compact a legend, come first.
+func bnClaudeAccountLines(cacheDir string, r *claude.UsageReport, opts bnOptions, now time.Time) []string {
+	if len(r.Accounts) == 0 && len(r.Orgs) == 0 {
+		return nil
+	}
+
+	hist, err := claude.LoadHistory(claude.HistoryPath(cacheDir))
+	if err != nil {
+		hist = claude.NewHistory()
+	}
+
+	color := bnColorEnabled() && opts.SnoozedUntil.IsZero()
+	var lines []string
+	if opts.ClaudeHeatmap && len(r.Accounts) > 0 {
+		lines = append(lines, "Heat: "+bnClaudeHeatmap(bnSortAccounts(r.Accounts, opts), opts.Glyphs, color))
+		if !opts.Compact {
+			lines = append(lines, "      "+bnClaudeHeatmapLegend(opts.Glyphs, color))
+		}
+	}
+
+	label := func(a claude.AccountUsage) string { return bnPlanLabel(a.Name, a.Tier, opts) }
+	if opts.Compact {
+		short := r.ShortNames()
+		label = func(a claude.AccountUsage) string { return short[a.Name] }
+	}
+
+	nameW := 0
+	for _, a := range r.Accounts {
+		if n := components.VisibleLen(label(a)); n > nameW {
+			nameW = n
+		}
+	}
+	for _, o := range r.Orgs {
+		if n := components.VisibleLen(bnPlanLabel(o.Name, o.Tier, opts)); n > nameW {
+			nameW = n
+		}
+	}
+
+	spark := components.NewSparkline(components.SparklineStyle{Width: bnClaudeSparkWidth})
+	graded := components.NewSparkline(components.SparklineStyle{
+		Width:     bnClaudeSparkWidth,
+		Color:     theme.Current.StatusOK,
+		WarnColor: theme.Current.StatusWarn,
+		CritColor: theme.Current.StatusError,
+	})
+	for _, a := range bnSortAccounts(r.Accounts, opts) {
+		line := components.PadRight(label(a), nameW)
+		if !a.Connected {
+			lines = append(lines, line+"  offline")
+			continue
+		}
+		line += "  " + components.FormatMoney(a.CurrentMonth.CostUSD, opts.Money)
+		if a.BudgetUSD > 0 {
+			line += fmt.Sprintf(" (%.0f%%)", a.Utilization)
+		}
+		if tokens := a.CurrentMonth.InputTokens + a.CurrentMonth.OutputTokens; tokens > 0 && !opts.Compact {
+			line += " " + components.FormatCount(tokens) + " tok"
+		}
+		if values := hist.Values(a.Name); len(values) >= 2 {
+			switch {
+			case opts.GraphStyle == components.GraphBraille:
+				graph := components.Braille(values, bnClaudeSparkWidth, 1)[0]
+				if color && a.BudgetUSD > 0 {
+					graph = bnStatusColor(graph, bnLevelColor(a.Level()))
+				}
+				line += " " + graph
+			case color && a.BudgetUSD > 0:
+				warn, crit := a.Thresholds()
+				line += " " + graded.RenderGraded(values, bnClaudeSparkWidth,
+					a.BudgetUSD*warn/100, a.BudgetUSD*crit/100)
+			default:
+				line += " " + spark.Render(values, bnClaudeSparkWidth)
+			}
+		}
+		if at := claude.ProjectExhaustion(hist.Accounts[a.Name], a.BudgetUSD, a.ResetsAt); at != nil {
+			line += " ~runs out in " + bnFormatAge(max(at.Sub(now), 0))
+		}
+		if a.Level() != claude.LevelOK {
+			line += " " + bnAlertGlyph(opts)
+		}
+		lines = append(lines, line)
+		if a.BudgetUSD > 0 && !a.ResetsAt.IsZero() && !opts.Compact {
+			lines = append(lines, components.PadRight("", nameW)+"  "+bnWindowBar(a, now, color, opts))
+		}
+	}
+	for _, o := range r.Orgs {
+		lines = append(lines, bnClaudeOrgLine(o, nameW, opts))
+	}
+	return lines
+}
+
+// bnClaudeOrgLine renders an organization's shared limits, e.g.
+// "team  org 5h 42% · 7d 75% · 12 seats", with its name padded to nameW.
+func bnClaudeOrgLine(o claude.OrgUsage, nameW int, opts bnOptions) string {
+	line := components.PadRight(bnPlanLabel(o.Name, o.Tier, opts), nameW) + "  org"
+	if !o.Connected {
+		return line + " offline"
+	}
+	var parts []string
+	for _, w := range []struct {
+		label string
+		win   *claude.UsageWindow
+	}{{"5h", o.FiveHour}, {"7d", o.SevenDay}} {
+		if w.win != nil {
+			parts = append(parts, fmt.Sprintf("%s %.0f%%", w.label, w.win.Utilization))
+		}
+	}
+	if o.Seats > 0 {
+		parts = append(parts, fmt.Sprintf("%d seats", o.Seats))
+	}
+	if len(parts) > 0 {
+		line += " " + strings.Join(parts, " · ")
+	}
+	if o.Level() != claude.LevelOK {
+		line += " " + bnAlertGlyph(opts)
+	}
+	return line
+}
+
+// bnPlanLabel returns name followed by the display name of tier, e.g.
+// "work (Max 5×)", when opts.ShowPlanName is set outside the compact
+// banner, and name alone otherwise or without a tier.
+func bnPlanLabel(name, tier string, opts bnOptions) string {
+	if !opts.ShowPlanName || opts.Compact || tier == "" {
+		return name
+	}
+	return name + " (" + claude.PlanName(tier) + ")"
+}
+
+// bnHeatCell is one kind of bnClaudeHeatmap cell: its symbol in the
+// Unicode and ASCII glyph sets, the theme color it is drawn in, and its
+// legend label.
+type bnHeatCell struct {
+	symbol, ascii string
+	color         func() string
+	label         string
+}
+
+// bnHeatCells lists the bnClaudeHeatmap cells in legend order. The shade
+// deepens with utilization so the row reads without color too.
+var bnHeatCells = []bnHeatCell{
+	{"░", "-", func() string { return theme.Current.StatusOK }, "ok"},
+	{"▓", "=", func() string { return theme.Current.StatusWarn }, "warn"},
+	{"█", "#", func() string { return theme.Current.StatusError }, "crit"},
+	{"·", ".", func() string { return theme.Current.Dim }, "no budget"},
+	{"×", "x", func() string { return theme.Current.StatusError }, "offline"},
+}
+
+// bnHeatCellFor returns the bnHeatCells entry for account a.
+func bnHeatCellFor(a claude.AccountUsage) bnHeatCell {
+	switch {
+	case !a.Connected || a.Error != "":
+		return bnHeatCells[4]
+	case a.BudgetUSD <= 0:
+		return bnHeatCells[3]
+	}
+	return bnHeatCells[a.Level()]
+}
+
+// render draws the cell's symbol for glyphs, in its color when color is
+// set.
+func (c bnHeatCell) render(glyphs components.GlyphSet, color bool) string {
+	s := c.symbol
+	if glyphs == components.GlyphASCII {
+		s = c.ascii
+	}
+	if color {
+		return bnStatusColor(s, c.color())
+	}
+	return s
+}
+
+// bnClaudeHeatmap renders accounts as one cell each, in the given order:
+// a shade colored by utilization level for accounts with a budget, a dot
+// for those without, and a cross for offline or failing ones. Five
+// accounts take five columns.
+func bnClaudeHeatmap(accounts []claude.AccountUsage, glyphs components.GlyphSet, color bool) string {
+	var b strings.Builder
+	for _, a := range accounts {
+		b.WriteString(bnHeatCellFor(a).render(glyphs, color))
+	}
+	return b.String()
+}
+
+// bnClaudeHeatmapLegend explains the bnClaudeHeatmap cells, e.g.
+// "░ ok ▓ warn █ crit · no budget × offline".
+func bnClaudeHeatmapLegend(glyphs components.GlyphSet, color bool) string {
+	parts := make([]string, len(bnHeatCells))
+	for i, c := range bnHeatCells {
+		parts[i] = c.render(glyphs, color) + " " + c.label
+	}
+	return strings.Join(parts, " ")
+}
+
+// bnWindowBar renders how far through its monthly budget window an account
+// is, colored by the account's utilization level when color is set, and
+// when the window resets (see bnFormatReset). High utilization early in the
+// window is the case to worry about; late in the window it is about to
+// reset.
+func bnWindowBar(a claude.AccountUsage, now time.Time, color bool, opts bnOptions) string {
+	p := a.WindowProgress(a.MonthWindow(), now)
+	filled := int(p*bnClaudeSparkWidth + 0.5)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", bnClaudeSparkWidth-filled)
+	if color {
+		bar = bnStatusColor(bar, bnLevelColor(a.Level()))
+	}
+	return fmt.Sprintf("%s %.0f%% of month, resets %s", bar, p*100, bnFormatReset(a.ResetsAt, now, opts))
+}
+
+// bnResetBothMinWidth is the narrowest banner that shows both the time
+// left and the wall-clock time of a reset; narrower ones show the time
+// left alone.
+const bnResetBothMinWidth = 120
+
+// bnFormatReset renders when a window resets at, as seen from now, in
+// opts.ResetStyle: "in 2d 3h", "Nov 1 00:00", or "in 2d 3h (Nov 1
+// 00:00)". A reset that is due reads "now". The wall-clock time is local
+// and carries the date unless it is today.
+func bnFormatReset(at, now time.Time, opts bnOptions) string {
+	relative := "now"
+	if left := at.Sub(now); left > 0 {
+		relative = "in " + bnFormatAge(left)
+	}
+	absolute := bnClock(at, opts)
+	if at, now := at.Local(), now.Local(); at.YearDay() != now.YearDay() || at.Year() != now.Year() {
+		absolute = at.Format("Jan 2") + " " + absolute
+	}
+	switch opts.ResetStyle {
+	case components.ResetAbsolute:
+		return absolute
+	case components.ResetBoth:
+		if opts.Width > 0 && opts.Width < bnResetBothMinWidth {
+			return relative
+		}
+		return relative + " (" + absolute + ")"
+	default:
+		return relative
+	}
+}
+
+// bnClock formats the local time of day of t on the clock opts selects,
+// e.g. "15:45" or "3:45pm".
+func bnClock(t time.Time, opts bnOptions) string {
+	if opts.Clock12h {
+		return t.Local().Format("3:04pm")
+	}
+	return t.Local().Format("15:04")
+}
+
+// bnLevelColor returns the theme color for a Claude utilization level.
+func bnLevelColor(l claude.Level) string {
+	switch l {
+	case claude.LevelWarn:
+		return theme.Current.StatusWarn
+	case claude.LevelCrit:
+		return theme.Current.StatusError
+	}
+	return theme.Current.StatusOK
+}
+
+// bnColorEnabled reports whether the banner may emit ANSI colors, honoring
+// the NO_COLOR convention.
+func bnColorEnabled() bool {
+	return os.Getenv("NO_COLOR") == ""
+}
+
 // bnReadCache reads a JSON cache file for the given collector key.
 // Returns nil if the file does not exist, cannot be parsed, or is stale.
-func bnReadCache[T any](cacheDir, key string) (*T, error) {
+func bnReadCache[T any](cacheDir, key string, maxAge time.Duration) (*T, error) {
 	path := filepath.Join(cacheDir, key+".json")
 
 	info, err := os.Stat(path)
@@ -103,7 +1286,7 @@ func bnReadCache[T any](cacheDir, key string) (*T, error) {
 		return nil, err
 	}
 
-	if time.Since(info.ModTime()) > bnMaxCacheAge {
+	if time.Since(info.ModTime()) > maxAge {
 		return nil, nil
 	}
 
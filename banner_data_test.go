@@ -2,17 +2,28 @@ package main
 
 import (
 	"encoding/json"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/anomaly"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/banner"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/command"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
 )
 
 func bnWriteFixture(t *testing.T, dir, key string, v interface{}) {
@@ -28,7 +39,7 @@ func bnWriteFixture(t *testing.T, dir, key string, v interface{}) {
 
 func TestBuildBannerFromCache_Empty(t *testing.T) {
 	dir := t.TempDir()
-	data := buildBannerFromCache(dir, "2.0.5", "abc123")
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
 
 	if len(data.Widgets) != 1 {
 		t.Fatalf("expected 1 widget (status only), got %d", len(data.Widgets))
@@ -50,7 +61,7 @@ func TestBuildBannerFromCache_WithSysmetrics(t *testing.T) {
 		Uptime: 3 * time.Hour,
 	})
 
-	data := buildBannerFromCache(dir, "2.0.5", "abc123")
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
 
 	if len(data.Widgets) != 2 {
 		t.Fatalf("expected 2 widgets (status + system), got %d", len(data.Widgets))
@@ -68,6 +79,63 @@ func TestBuildBannerFromCache_WithSysmetrics(t *testing.T) {
 	}
 }
 
+func TestBuildBannerFromCache_DefaultTTLsFreshBetweenWrites(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "sysmetrics", sysmetrics.Metrics{Uptime: time.Hour})
+	bnWriteFixture(t, dir, "tailscale", tailscale.Status{OnlinePeers: 1, TotalPeers: 2})
+	// Older than both default intervals (1s and 30s), as between writes or
+	// under a five-minute cron -collect-once.
+	written := time.Now().Add(-4 * time.Minute)
+	for _, key := range []string{"sysmetrics", "tailscale"} {
+		if err := os.Chtimes(filepath.Join(dir, key+".json"), written, written); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := bnOptions{CacheTTLs: config.DefaultConfig().Collectors.CacheTTLs()}
+	ids := map[string]bool{}
+	for _, w := range buildBannerFromCache(dir, opts, "2.0.5", "abc123").Widgets {
+		ids[w.ID] = true
+	}
+	if !ids["system"] || !ids["tailscale"] {
+		t.Errorf("widgets = %v, want system and tailscale shown as fresh", ids)
+	}
+}
+
+func TestBuildBannerFromCache_QuietHoursStretchTTLs(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "tailscale", tailscale.Status{OnlinePeers: 1, TotalPeers: 2})
+	// Written 25 minutes ago: stale at the 10-minute default, fresh once
+	// quiet hours run the collector four times less often.
+	written := time.Now().Add(-25 * time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "tailscale.json"), written, written); err != nil {
+		t.Fatal(err)
+	}
+
+	shown := func(opts bnOptions) bool {
+		for _, w := range buildBannerFromCache(dir, opts, "2.0.5", "abc123").Widgets {
+			if w.ID == "tailscale" {
+				return true
+			}
+		}
+		return false
+	}
+	opts := bnOptions{CacheTTLs: config.DefaultConfig().Collectors.CacheTTLs()}
+	if shown(opts) {
+		t.Fatal("tailscale shown outside quiet hours, want it stale")
+	}
+	now := time.Now()
+	clock := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+	opts.Quiet = &collectors.QuietHours{
+		Start:  (clock + 23*time.Hour) % (24 * time.Hour), // an hour ago
+		End:    (clock + time.Hour) % (24 * time.Hour),
+		Factor: 4,
+	}
+	if !shown(opts) {
+		t.Error("tailscale hidden during quiet hours, want its TTL stretched")
+	}
+}
+
 func TestBuildBannerFromCache_WithAll(t *testing.T) {
 	dir := t.TempDir()
 
@@ -100,7 +168,7 @@ func TestBuildBannerFromCache_WithAll(t *testing.T) {
 		BudgetPercent:   23.45,
 	})
 
-	data := buildBannerFromCache(dir, "2.0.5", "abc123")
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
 
 	// status + system + tailscale + k8s + claude + billing = 6
 	if len(data.Widgets) != 6 {
@@ -118,40 +186,1445 @@ func TestBuildBannerFromCache_WithAll(t *testing.T) {
 	}
 }
 
-func TestBuildBannerFromCache_StaleCache(t *testing.T) {
+// bnWriteSectionFixtures caches a report for every built-in section.
+func bnWriteSectionFixtures(t *testing.T, dir string) {
+	t.Helper()
+	bnWriteFixture(t, dir, "sysmetrics", sysmetrics.Metrics{Uptime: time.Hour})
+	bnWriteFixture(t, dir, "tailscale", tailscale.Status{OnlinePeers: 1, TotalPeers: 1})
+	bnWriteFixture(t, dir, "k8s", k8s.ClusterStatus{Clusters: []k8s.ClusterInfo{
+		{Context: "civo", Connected: true, TotalPods: 4, RunningPods: 4},
+	}})
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{TotalCostUSD: 12})
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{TotalMonthlyUSD: 3})
+}
+
+func TestBuildBannerFromCache_HideSections(t *testing.T) {
 	dir := t.TempDir()
+	bnWriteSectionFixtures(t, dir)
 
-	// Write a fixture then backdate it beyond the staleness threshold.
-	bnWriteFixture(t, dir, "sysmetrics", sysmetrics.Metrics{
-		CPU: sysmetrics.CPUMetrics{Total: 50},
+	data := buildBannerFromCache(dir, bnOptions{HideSections: []string{"k8s"}}, "2.0.5", "abc123")
+	var ids []string
+	for _, w := range data.Widgets {
+		ids = append(ids, w.ID)
+	}
+	if got := strings.Join(ids, ","); got != "status,system,tailscale,claude,billing" {
+		t.Errorf("widgets = %s, want every section but k8s in default order", got)
+	}
+	if out := banner.Render(data, banner.SelectPreset(200, 50)); strings.Contains(out, "Kubernetes") || strings.Contains(out, "Pods:") {
+		t.Errorf("hidden k8s section rendered:\n%s", out)
+	}
+}
+
+func TestBuildBannerFromCache_SectionsOrder(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteSectionFixtures(t, dir)
+
+	opts := bnOptions{Sections: []string{"billing", "claude", "status", "k8s"}, HideSections: []string{"k8s"}}
+	data := buildBannerFromCache(dir, opts, "2.0.5", "abc123")
+	var ids []string
+	for _, w := range data.Widgets {
+		ids = append(ids, w.ID)
+	}
+	if got := strings.Join(ids, ","); got != "billing,claude,status" {
+		t.Errorf("widgets = %s, want billing,claude,status", got)
+	}
+}
+
+func TestBannerOptions_UnknownSectionsIgnored(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Collectors.Commands = []config.CommandCollectorConfig{{Name: "gpu"}}
+	cfg.Banner.Sections = []string{"gpu", "infrastructure", "claude"}
+	cfg.Banner.HideSections = []string{"kubernetes"}
+	opts := bannerOptions(cfg)
+	if got := strings.Join(opts.Sections, ","); got != "gpu,claude" {
+		t.Errorf("Sections = %v, want [gpu claude]", opts.Sections)
+	}
+	if len(opts.HideSections) != 0 {
+		t.Errorf("HideSections = %v, want none", opts.HideSections)
+	}
+}
+
+func TestBuildBannerFromCache_PrimaryAccountPinned(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{
+		TotalCostUSD: 110,
+		Accounts: []claude.AccountUsage{
+			{Name: "work", Connected: true, BudgetUSD: 100, Utilization: 95, CurrentMonth: claude.MonthUsage{CostUSD: 95}},
+			{Name: "personal", Connected: true, BudgetUSD: 100, Utilization: 15, CurrentMonth: claude.MonthUsage{CostUSD: 15}},
+		},
 	})
-	staleTime := time.Now().Add(-10 * time.Minute)
-	path := filepath.Join(dir, "sysmetrics.json")
-	if err := os.Chtimes(path, staleTime, staleTime); err != nil {
-		t.Fatalf("chtimes: %v", err)
+
+	opts := bnOptions{ClaudeSort: claude.SortUtilization, PrimaryAccount: "personal"}
+	w := buildBannerFromCache(dir, opts, "2.0.5", "abc123").Widgets
+	content := w[len(w)-1].Content
+	if p, k := strings.Index(content, "personal"), strings.Index(content, "work"); p < 0 || k < 0 || p > k {
+		t.Errorf("primary account should be listed first despite lower utilization, got %q", content)
 	}
+}
 
-	data := buildBannerFromCache(dir, "2.0.5", "abc123")
+func TestBannerOptions_UnknownPrimaryAccountIgnored(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Collectors.Claude.Accounts = []config.ClaudeAccountConfig{{Name: "work"}, {Name: "personal"}}
+	cfg.Display.PrimaryAccount = "persnal"
+	if diags := config.ValidatePrimaryAccount(cfg); len(diags) != 1 || diags[0].Severity != config.SeverityWarn {
+		t.Errorf("ValidatePrimaryAccount() = %+v, want one warning", diags)
+	}
+	if opts := bannerOptions(cfg); opts.PrimaryAccount != "" {
+		t.Errorf("PrimaryAccount = %q, want the unknown name dropped", opts.PrimaryAccount)
+	}
 
-	// Stale cache should be skipped — only status widget.
-	if len(data.Widgets) != 1 {
-		t.Fatalf("expected 1 widget (stale cache skipped), got %d", len(data.Widgets))
+	cfg.Display.PrimaryAccount = "personal"
+	if diags := config.ValidatePrimaryAccount(cfg); len(diags) != 0 {
+		t.Errorf("ValidatePrimaryAccount() = %+v, want none for a configured account", diags)
+	}
+	if opts := bannerOptions(cfg); opts.PrimaryAccount != "personal" {
+		t.Errorf("PrimaryAccount = %q, want personal", opts.PrimaryAccount)
 	}
 }
 
-func TestBnFormatUptime(t *testing.T) {
+func TestBuildBannerFromCache_ClaudeAccountSparklines(t *testing.T) {
+	dir := t.TempDir()
+	report := claude.UsageReport{
+		TotalCostUSD: 30,
+		Accounts: []claude.AccountUsage{
+			{Name: "personal", Connected: true, CurrentMonth: claude.MonthUsage{CostUSD: 30}},
+			{Name: "work", Connected: false, Error: "unauthorized"},
+		},
+	}
+	bnWriteFixture(t, dir, "claude", report)
+
+	h := claude.NewHistory()
+	now := time.Now()
+	for i, cost := range []float64{5, 15, 30} {
+		r := report
+		r.Timestamp = now.Add(time.Duration(i) * time.Minute)
+		r.Accounts = []claude.AccountUsage{
+			{Name: "personal", Connected: true, CurrentMonth: claude.MonthUsage{CostUSD: cost}},
+		}
+		h.Record(&r, 0)
+	}
+	if err := h.Save(claude.HistoryPath(dir)); err != nil {
+		t.Fatalf("save history: %v", err)
+	}
+
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	var content string
+	for _, w := range data.Widgets {
+		if w.ID == "claude" {
+			content = w.Content
+		}
+	}
+
+	lines := strings.Split(content, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected total + 2 account lines, got %q", content)
+	}
+	if !strings.Contains(lines[1], "personal") || !strings.Contains(lines[1], "$30.00") || !strings.ContainsRune(lines[1], '\u2588') {
+		t.Errorf("personal line should include cost and sparkline, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "work") || !strings.Contains(lines[2], "offline") {
+		t.Errorf("work line should show offline, got %q", lines[2])
+	}
+}
+
+func TestBuildBannerFromCache_ClaudeBrailleGraph(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	dir := t.TempDir()
+	report := claude.UsageReport{Accounts: []claude.AccountUsage{
+		{Name: "personal", Connected: true, CurrentMonth: claude.MonthUsage{CostUSD: 30}},
+	}}
+	bnWriteFixture(t, dir, "claude", report)
+
+	h := claude.NewHistory()
+	now := time.Now()
+	costs := []float64{5, 15, 30}
+	for i, cost := range costs {
+		report.Timestamp = now.Add(time.Duration(i) * time.Minute)
+		report.Accounts[0].CurrentMonth.CostUSD = cost
+		h.Record(&report, 0)
+	}
+	if err := h.Save(claude.HistoryPath(dir)); err != nil {
+		t.Fatalf("save history: %v", err)
+	}
+
+	opts := bnOptions{GraphStyle: components.GraphBraille}
+	var line string
+	for _, w := range buildBannerFromCache(dir, opts, "2.0.5", "abc123").Widgets {
+		if w.ID == "claude" {
+			line = strings.Split(w.Content, "\n")[1]
+		}
+	}
+	want := components.Braille(costs, bnClaudeSparkWidth, 1)[0]
+	if !strings.HasSuffix(line, " "+want) {
+		t.Errorf("personal line = %q, want Braille graph %q", line, want)
+	}
+	if strings.ContainsRune(line, '\u2588') {
+		t.Errorf("braille style should not draw block sparklines, got %q", line)
+	}
+}
+
+func TestBuildBannerFromCache_ClaudeGradedSparkline(t *testing.T) {
+	dir := t.TempDir()
+	report := claude.UsageReport{Accounts: []claude.AccountUsage{
+		{Name: "personal", Connected: true, BudgetUSD: 100, Utilization: 95, CurrentMonth: claude.MonthUsage{CostUSD: 95}},
+	}}
+	bnWriteFixture(t, dir, "claude", report)
+
+	h := claude.NewHistory()
+	now := time.Now()
+	for i, cost := range []float64{10, 75, 95} {
+		report.Timestamp = now.Add(time.Duration(i) * time.Minute)
+		report.Accounts[0].CurrentMonth.CostUSD = cost
+		h.Record(&report, 0)
+	}
+	if err := h.Save(claude.HistoryPath(dir)); err != nil {
+		t.Fatalf("save history: %v", err)
+	}
+
+	claudeLine := func() string {
+		for _, w := range buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123").Widgets {
+			if w.ID == "claude" {
+				return strings.Split(w.Content, "\n")[1]
+			}
+		}
+		t.Fatal("claude widget missing")
+		return ""
+	}
+
+	t.Setenv("NO_COLOR", "")
+	colored := claudeLine()
+	if n := strings.Count(colored, "\x1b[38;2;"); n != 3 {
+		t.Errorf("expected ok, warn and crit runs in %q", colored)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	plain := claudeLine()
+	if strings.Contains(plain, "\x1b[") {
+		t.Errorf("NO_COLOR should disable sparkline colors, got %q", plain)
+	}
+	// Escape sequences must not count toward the layout width.
+	if got, want := components.VisibleLen(colored), components.VisibleLen(plain); got != want {
+		t.Errorf("colored VisibleLen = %d, plain %d", got, want)
+	}
+}
+
+func TestBuildBannerFromCache_ClaudeWindowBar(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	dir := t.TempDir()
+	resets := time.Now().Add(3 * 24 * time.Hour).Truncate(time.Hour)
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{Accounts: []claude.AccountUsage{
+		{Name: "work", Connected: true, BudgetUSD: 100, Utilization: 95, ResetsAt: resets},
+		{Name: "nobudget", Connected: true, ResetsAt: resets},
+	}})
+
+	content := func(opts bnOptions) string {
+		for _, w := range buildBannerFromCache(dir, opts, "2.0.5", "abc123").Widgets {
+			if w.ID == "claude" {
+				return w.Content
+			}
+		}
+		t.Fatal("claude widget missing")
+		return ""
+	}
+
+	lines := strings.Split(content(bnOptions{}), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("want cost, work, work bar, nobudget lines; got %q", lines)
+	}
+	bar := lines[2]
+	if !strings.Contains(bar, "% of month, resets in 2d 23h") && !strings.Contains(bar, "% of month, resets in 3d 0h") {
+		t.Errorf("bar line should show elapsed share and reset time, got %q", bar)
+	}
+	if !strings.Contains(bar, components.Color(theme.Current.StatusError)) {
+		t.Errorf("bar for a critical account should use the error color, got %q", bar)
+	}
+	if got := strings.Count(bar, "█") + strings.Count(bar, "░"); got != bnClaudeSparkWidth {
+		t.Errorf("bar width = %d, want %d: %q", got, bnClaudeSparkWidth, bar)
+	}
+
+	if c := content(bnOptions{Compact: true}); strings.Contains(c, "of month") {
+		t.Errorf("compact layout should hide window bars, got %q", c)
+	}
+}
+
+func TestBnFormatReset(t *testing.T) {
+	now := time.Date(2026, 10, 31, 13, 15, 0, 0, time.Local)
+	soon := time.Date(2026, 10, 31, 15, 45, 0, 0, time.Local)
+	monthly := time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)
 	tests := []struct {
-		d    time.Duration
+		name string
+		at   time.Time
+		opts bnOptions
 		want string
 	}{
-		{30 * time.Minute, "30m"},
-		{2*time.Hour + 15*time.Minute, "2h 15m"},
-		{49*time.Hour + 30*time.Minute, "2d 1h"},
+		{"relative", soon, bnOptions{}, "in 2h 30m"},
+		{"absolute", soon, bnOptions{ResetStyle: components.ResetAbsolute}, "15:45"},
+		{"absolute 12h", soon, bnOptions{ResetStyle: components.ResetAbsolute, Clock12h: true}, "3:45pm"},
+		{"absolute another day", monthly, bnOptions{ResetStyle: components.ResetAbsolute}, "Nov 1 00:00"},
+		{"both", soon, bnOptions{ResetStyle: components.ResetBoth, Clock12h: true}, "in 2h 30m (3:45pm)"},
+		{"both too narrow", soon, bnOptions{ResetStyle: components.ResetBoth, Width: 80}, "in 2h 30m"},
+		{"due now", now, bnOptions{}, "now"},
+		{"past relative", now.Add(-time.Hour), bnOptions{}, "now"},
+		{"past both", now.Add(-time.Hour), bnOptions{ResetStyle: components.ResetBoth}, "now (12:15)"},
 	}
 	for _, tt := range tests {
-		got := bnFormatUptime(tt.d)
-		if got != tt.want {
-			t.Errorf("bnFormatUptime(%v) = %q, want %q", tt.d, got, tt.want)
+		if got := bnFormatReset(tt.at, now, tt.opts); got != tt.want {
+			t.Errorf("%s: bnFormatReset() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildBannerFromCache_ClaudeRunsOut(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	resets := now.Add(3 * 24 * time.Hour)
+	report := claude.UsageReport{Accounts: []claude.AccountUsage{
+		{Name: "work", Connected: true, BudgetUSD: 100, Utilization: 75, ResetsAt: resets,
+			CurrentMonth: claude.MonthUsage{CostUSD: 75}},
+		{Name: "steady", Connected: true, BudgetUSD: 100, Utilization: 20, ResetsAt: resets,
+			CurrentMonth: claude.MonthUsage{CostUSD: 20}},
+	}}
+	bnWriteFixture(t, dir, "claude", report)
+
+	// work spends $1 a minute with $25 left; steady has stopped spending.
+	h := claude.NewHistory()
+	for i := 0; i < 6; i++ {
+		r := report
+		r.Timestamp = now.Add(time.Duration(i-5) * 5 * time.Minute)
+		r.Accounts = []claude.AccountUsage{
+			{Name: "work", Connected: true, CurrentMonth: claude.MonthUsage{CostUSD: float64(50 + 5*i)}},
+			{Name: "steady", Connected: true, CurrentMonth: claude.MonthUsage{CostUSD: 20}},
+		}
+		h.Record(&r, 0)
+	}
+	if err := h.Save(claude.HistoryPath(dir)); err != nil {
+		t.Fatalf("save history: %v", err)
+	}
+
+	var content string
+	for _, w := range buildBannerFromCache(dir, bnOptions{Compact: true}, "2.0.5", "abc123").Widgets {
+		if w.ID == "claude" {
+			content = w.Content
+		}
+	}
+	lines := strings.Split(content, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected total + 2 account lines, got %q", content)
+	}
+	if !strings.Contains(lines[1], "~runs out in 2") {
+		t.Errorf("work line should project exhaustion in about 25m, got %q", lines[1])
+	}
+	if strings.Contains(lines[2], "runs out") {
+		t.Errorf("flat series should not project, got %q", lines[2])
+	}
+}
+
+func TestBuildBannerFromCache_ClaudeTokens(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{Accounts: []claude.AccountUsage{
+		{Name: "work", Connected: true, BudgetUSD: 300, Utilization: 33.5,
+			CurrentMonth: claude.MonthUsage{CostUSD: 100.5, InputTokens: 1_000_000, OutputTokens: 250_000}},
+	}})
+
+	content := func(opts bnOptions) string {
+		for _, w := range buildBannerFromCache(dir, opts, "2.0.5", "abc123").Widgets {
+			if w.ID == "claude" {
+				return w.Content
+			}
+		}
+		return ""
+	}
+	// Tokens are abbreviated, while utilization keeps the exact figure.
+	if c := content(bnOptions{}); !strings.Contains(c, "(34%) 1.2M tok") {
+		t.Errorf("claude widget should show exact utilization and abbreviated tokens, got %q", c)
+	}
+	if c := content(bnOptions{Compact: true}); strings.Contains(c, "tok") {
+		t.Errorf("compact layout should hide token counts, got %q", c)
+	}
+}
+
+func TestBuildBannerFromCache_ClaudePlanName(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{
+		Accounts: []claude.AccountUsage{
+			{Name: "work", Connected: true, Tier: "max_5x"},
+			{Name: "lab", Connected: true, Tier: "tier_9"},
+			{Name: "home", Connected: true},
+		},
+		Orgs: []claude.OrgUsage{{Name: "team", Connected: true, Tier: "tier_3"}},
+	})
+
+	content := func(opts bnOptions) string {
+		for _, w := range buildBannerFromCache(dir, opts, "2.0.5", "abc123").Widgets {
+			if w.ID == "claude" {
+				return w.Content
+			}
+		}
+		return ""
+	}
+	c := content(bnOptions{ShowPlanName: true})
+	for _, want := range []string{"work (Max 5×)", "lab (tier_9)", "team (API Tier 3)"} {
+		if !strings.Contains(c, want) {
+			t.Errorf("claude widget should label %q, got %q", want, c)
+		}
+	}
+	if strings.Contains(c, "home (") {
+		t.Errorf("an account without a tier should keep its bare name, got %q", c)
+	}
+	for _, opts := range []bnOptions{{}, {ShowPlanName: true, Compact: true}} {
+		if c := content(opts); strings.Contains(c, "Max 5×") {
+			t.Errorf("plan names should show only with ShowPlanName outside compact, got %q for %+v", c, opts)
+		}
+	}
+}
+
+func TestBnClaudeHeatmap(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	accounts := []claude.AccountUsage{
+		{Name: "healthy", Connected: true, BudgetUSD: 100, Utilization: 20},
+		{Name: "high", Connected: true, BudgetUSD: 100, Utilization: 80},
+		{Name: "maxed", Connected: true, BudgetUSD: 100, Utilization: 95},
+		{Name: "broken", Error: "401 unauthorized"},
+		{Name: "unbudgeted", Connected: true},
+	}
+	paint := func(s, hex string) string { return components.Color(hex) + s + components.Reset() }
+	want := paint("░", theme.Current.StatusOK) + paint("▓", theme.Current.StatusWarn) +
+		paint("█", theme.Current.StatusError) + paint("×", theme.Current.StatusError) +
+		paint("·", theme.Current.Dim)
+	if got := bnClaudeHeatmap(accounts, components.GlyphEmoji, true); got != want {
+		t.Errorf("heatmap = %q, want %q", got, want)
+	}
+	if got := bnClaudeHeatmap(accounts, components.GlyphEmoji, false); got != "░▓█×·" {
+		t.Errorf("uncolored heatmap = %q, want ░▓█×·", got)
+	}
+	if got := bnClaudeHeatmap(accounts, components.GlyphASCII, false); got != "-=#x." {
+		t.Errorf("ASCII heatmap = %q, want -=#x.", got)
+	}
+	if got := bnClaudeHeatmapLegend(components.GlyphASCII, false); got != "- ok = warn # crit . no budget x offline" {
+		t.Errorf("legend = %q", got)
+	}
+}
+
+func TestBuildBannerFromCache_ClaudeHeatmap(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{Accounts: []claude.AccountUsage{
+		{Name: "work", Connected: true, BudgetUSD: 100, Utilization: 20},
+		{Name: "alpha", Connected: true, BudgetUSD: 100, Utilization: 95},
+		{Name: "lab", Connected: false},
+	}})
+	content := func(opts bnOptions) string {
+		for _, w := range buildBannerFromCache(dir, opts, "2.0.5", "abc123").Widgets {
+			if w.ID == "claude" {
+				return w.Content
+			}
+		}
+		return ""
+	}
+
+	if c := content(bnOptions{}); strings.Contains(c, "Heat:") {
+		t.Errorf("heatmap should be off by default, got %q", c)
+	}
+	c := content(bnOptions{ClaudeHeatmap: true, ClaudeSort: claude.SortName})
+	if !strings.Contains(c, "Heat: █×░\n") || !strings.Contains(c, "░ ok ▓ warn █ crit") {
+		t.Errorf("heatmap in name order with legend missing, got %q", c)
+	}
+	c = content(bnOptions{ClaudeHeatmap: true, Compact: true})
+	if !strings.Contains(c, "Heat: ░█×") || strings.Contains(c, "no budget") {
+		t.Errorf("compact heatmap in config order without legend, got %q", c)
+	}
+}
+
+func TestBuildBannerFromCache_ClaudeSort(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{Accounts: []claude.AccountUsage{
+		{Name: "work", Connected: true, BudgetUSD: 100, Utilization: 20},
+		{Name: "alpha", Connected: true, BudgetUSD: 100, Utilization: 80},
+	}})
+
+	names := func(opts bnOptions) string {
+		for _, w := range buildBannerFromCache(dir, opts, "2.0.5", "abc123").Widgets {
+			if w.ID == "claude" {
+				var out []string
+				for _, l := range strings.Split(w.Content, "\n")[1:] {
+					out = append(out, strings.Fields(l)[0])
+				}
+				return strings.Join(out, ",")
+			}
+		}
+		return ""
+	}
+	if got := names(bnOptions{}); got != "work,alpha" {
+		t.Errorf("default order = %s, want config order", got)
+	}
+	if got := names(bnOptions{ClaudeSort: claude.SortName}); got != "alpha,work" {
+		t.Errorf("name order = %s", got)
+	}
+}
+
+func TestBuildBannerFromCache_ClaudeCompactShortNames(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{Accounts: []claude.AccountUsage{
+		{Name: "work-a", Connected: true},
+		{Name: "work-b", Connected: true},
+		{Name: "personal", ShortName: "me", Connected: true},
+	}})
+
+	names := func(opts bnOptions) string {
+		for _, w := range buildBannerFromCache(dir, opts, "2.0.5", "abc123").Widgets {
+			if w.ID == "claude" {
+				var out []string
+				for _, l := range strings.Split(w.Content, "\n")[1:] {
+					out = append(out, strings.Fields(l)[0])
+				}
+				return strings.Join(out, ",")
+			}
+		}
+		return ""
+	}
+	if got := names(bnOptions{}); got != "work-a,work-b,personal" {
+		t.Errorf("standard labels = %s, want full names", got)
+	}
+	if got := names(bnOptions{Compact: true}); got != "wor1,wor2,me" {
+		t.Errorf("compact labels = %s, want short names", got)
+	}
+}
+
+func TestBannerOptions_InvalidClaudeSortFallsBack(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Display.ClaudeSort = "priority"
+	if got := bannerOptions(cfg).ClaudeSort; got != claude.SortConfig {
+		t.Errorf("ClaudeSort = %q, want config", got)
+	}
+}
+
+func TestBannerOptions_InvalidGraphStyleFallsBack(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Display.GraphStyle = "ascii"
+	if got := bannerOptions(cfg).GraphStyle; got != components.GraphBlock {
+		t.Errorf("GraphStyle = %q, want block", got)
+	}
+	cfg.Display.GraphStyle = "braille"
+	if got := bannerOptions(cfg).GraphStyle; got != components.GraphBraille {
+		t.Errorf("GraphStyle = %q, want braille", got)
+	}
+}
+
+func TestBnConfigOptions_WarnsWithoutPrinting(t *testing.T) {
+	t.Setenv("TERM", "dumb")
+	cfg := config.DefaultConfig()
+	cfg.General.CacheDir = t.TempDir()
+	cfg.Display.ClaudeSort = "priority"
+	cfg.Display.Glyphs = "runes"
+	cfg.Display.MoneyDecimals = 3
+	cfg.Display.EnableHyperlinks = true
+	cfg.Banner.Sections = []string{"billing", "weather"}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	bnWarmData(cfg, 120, 40)
+	os.Stderr = stderr
+	w.Close()
+	if out, _ := io.ReadAll(r); len(out) != 0 {
+		t.Errorf("warming a banner printed %q", out)
+	}
+
+	opts, warnings := bnConfigOptions(cfg)
+	want := []string{"display.claude_sort", "display.glyphs", "display.money_decimals", "banner.sections"}
+	if len(warnings) != len(want) {
+		t.Fatalf("warnings = %q, want one each for %v", warnings, want)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(warnings[i], prefix) {
+			t.Errorf("warning %d = %q, want one for %s", i, warnings[i], prefix)
+		}
+	}
+	// The daemon cannot see the terminal: it follows the config for
+	// hyperlinks and draws the default glyphs.
+	if !opts.Hyperlinks || opts.Glyphs.Glyphs() != components.GlyphEmoji.Glyphs() {
+		t.Errorf("config options: hyperlinks %v, glyphs %v", opts.Hyperlinks, opts.Glyphs)
+	}
+	for _, g := range []string{"runes", "auto"} {
+		cfg.Display.Glyphs = g
+		if got := bannerOptions(cfg).Glyphs; got != components.GlyphASCII {
+			t.Errorf("terminal glyphs for %q = %v, want ASCII detected for TERM=dumb", g, got)
+		}
+		if opts, _ := bnConfigOptions(cfg); opts.Glyphs != "" {
+			t.Errorf("config glyphs for %q = %v, want the default", g, opts.Glyphs)
+		}
+	}
+}
+
+func TestBuildBannerFromCache_ClaudeThresholdMarker(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{
+		TotalCostUSD: 120,
+		Accounts: []claude.AccountUsage{
+			{Name: "personal", Connected: true, BudgetUSD: 100, Utilization: 60, CurrentMonth: claude.MonthUsage{CostUSD: 60}},
+			{Name: "work", Connected: true, BudgetUSD: 100, Utilization: 60, WarnThreshold: 50, CurrentMonth: claude.MonthUsage{CostUSD: 60}},
+		},
+	})
+
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	var content string
+	for _, w := range data.Widgets {
+		if w.ID == "claude" {
+			content = w.Content
+		}
+	}
+	lines := strings.Split(content, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected total + 2 account lines, got %q", content)
+	}
+	if strings.Contains(lines[1], "⚠️") {
+		t.Errorf("personal at 60%% with default thresholds should not warn, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "(60%)") || !strings.Contains(lines[2], "⚠️") {
+		t.Errorf("work at 60%% with warn 50 should warn, got %q", lines[2])
+	}
+}
+
+func TestBuildBannerFromCache_ClaudeOrgRow(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{
+		TotalCostUSD: 30,
+		Accounts: []claude.AccountUsage{
+			{Name: "personal", Connected: true, CurrentMonth: claude.MonthUsage{CostUSD: 30}},
+		},
+		Orgs: []claude.OrgUsage{
+			{Name: "team", Connected: true, Seats: 12,
+				FiveHour: &claude.UsageWindow{Utilization: 42}, SevenDay: &claude.UsageWindow{Utilization: 75}},
+			{Name: "lab", Error: "unauthorized"},
+		},
+	})
+
+	var content string
+	for _, w := range buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123").Widgets {
+		if w.ID == "claude" {
+			content = w.Content
+		}
+	}
+	lines := strings.Split(content, "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected total, account, and 2 org lines, got %q", content)
+	}
+	if want := "team      org 5h 42% · 7d 75% · 12 seats ⚠️"; lines[2] != want {
+		t.Errorf("team line = %q, want %q", lines[2], want)
+	}
+	if want := "lab       org offline"; lines[3] != want {
+		t.Errorf("lab line = %q, want %q", lines[3], want)
+	}
+}
+
+func TestBuildBannerFromCache_K8sPodPressure(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "k8s", k8s.ClusterStatus{Clusters: []k8s.ClusterInfo{{
+		Context:     "home",
+		Connected:   true,
+		TotalPods:   57,
+		RunningPods: 57,
+		Nodes:       []k8s.NodeInfo{{Name: "pi-1", Ready: true, MaxPods: 60, PodCount: 57}},
+	}}})
+
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	var content string
+	for _, w := range data.Widgets {
+		if w.ID == "k8s" {
+			content = w.Content
 		}
 	}
+	if !strings.Contains(content, "home pods 95%") || !strings.Contains(content, "pi-1 57/60") {
+		t.Errorf("k8s widget should flag the near-capacity node, got %q", content)
+	}
+}
+
+func TestBuildBannerFromCache_TailscaleExitNode(t *testing.T) {
+	dir := t.TempDir()
+	exit := tailscale.PeerInfo{Hostname: "honey", Online: true, ExitNode: true}
+	bnWriteFixture(t, dir, "tailscale", tailscale.Status{
+		OnlinePeers: 2,
+		TotalPeers:  2,
+		Peers: []tailscale.PeerInfo{
+			exit,
+			{Hostname: "router", Online: true, AdvertisedRoutes: []string{"10.0.0.0/24"}},
+		},
+		ExitNode: &exit,
+	})
+
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	w := data.Widgets[1]
+	if w.ID != "tailscale" {
+		t.Fatalf("expected tailscale widget, got %s", w.ID)
+	}
+	if !strings.Contains(w.Content, "Exit: ↗ honey") {
+		t.Errorf("tailscale widget should mark exit node, got %q", w.Content)
+	}
+	if !strings.Contains(w.Content, "Subnet routers: 1") {
+		t.Errorf("tailscale widget should count subnet routers, got %q", w.Content)
+	}
+}
+
+func TestBuildBannerFromCache_TailscaleStale(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	bnWriteFixture(t, dir, "tailscale", tailscale.Status{
+		OnlinePeers: 2,
+		TotalPeers:  2,
+		Peers: []tailscale.PeerInfo{
+			{Hostname: "fresh", Online: true, LastSeen: now.Add(-time.Minute)},
+			{Hostname: "wedged", Online: true, LastSeen: now.Add(-time.Hour)},
+		},
+		StaleThreshold: 10 * time.Minute,
+	})
+
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	w := data.Widgets[1]
+	if !strings.Contains(w.Content, "stale: wedged") {
+		t.Errorf("tailscale widget should mark the stale node, got %q", w.Content)
+	}
+	if strings.Contains(w.Content, "fresh") {
+		t.Errorf("recently seen node should not be marked, got %q", w.Content)
+	}
+}
+
+func TestBuildBannerFromCache_TailscaleAddressDisplay(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "tailscale", tailscale.Status{
+		Self: tailscale.PeerInfo{
+			Hostname: "xoxd-bates",
+			DNSName:  "xoxd-bates.tinyland.ts.net.",
+			IP:       "100.64.0.1",
+			IPv6:     "fd7a:115c:a1e0::1",
+		},
+	})
+
+	for mode, want := range map[tailscale.AddressDisplay]string{
+		"":                       "Addr: 100.64.0.1",
+		tailscale.AddressIPv6:    "Addr: fd7a:115c:a1e0::1",
+		tailscale.AddressDNSName: "Addr: xoxd-bates.tinyland.ts.net",
+	} {
+		data := buildBannerFromCache(dir, bnOptions{TailscaleAddress: mode}, "2.0.5", "abc123")
+		if w := data.Widgets[1]; !strings.Contains(w.Content, want+"\n") && !strings.HasSuffix(w.Content, want) {
+			t.Errorf("address_display %q: want %q, got %q", mode, want, w.Content)
+		}
+	}
+}
+
+func TestBuildBannerFromCache_MoneyFormat(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{
+		TotalMonthlyUSD: 1234567.5,
+		Providers: []billing.ProviderBilling{
+			{Name: "civo", Connected: true, MonthToDate: 1234.5},
+		},
+	})
+
+	tests := []struct {
+		money components.MoneyFormat
+		want  []string
+	}{
+		{components.MoneyFormat{}, []string{"Spend: $1234567.50/mo", "civo $1234.50"}},
+		{components.MoneyFormat{Separator: components.SeparatorComma}, []string{"Spend: $1,234,567.50/mo", "civo $1,234.50"}},
+		{components.MoneyFormat{Whole: true, Separator: components.SeparatorSpace}, []string{"Spend: $1 234 568/mo", "civo $1 234"}},
+	}
+	for _, tt := range tests {
+		data := buildBannerFromCache(dir, bnOptions{Money: tt.money}, "2.0.5", "abc123")
+		w := data.Widgets[len(data.Widgets)-1]
+		for _, want := range tt.want {
+			if !strings.Contains(w.Content, want) {
+				t.Errorf("money %+v: want %q in %q", tt.money, want, w.Content)
+			}
+		}
+	}
+}
+
+func TestBnMoneyFormat(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if got := bnMoneyFormat(cfg); got != (components.MoneyFormat{Separator: components.SeparatorNone}) {
+		t.Errorf("default money format = %+v", got)
+	}
+	cfg.Display.MoneyDecimals = 0
+	cfg.Display.ThousandsSeparator = "comma"
+	if got := bnMoneyFormat(cfg); !got.Whole || got.Separator != components.SeparatorComma {
+		t.Errorf("money format = %+v, want whole dollars with commas", got)
+	}
+}
+
+func TestBuildBannerFromCache_BillingPace(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{
+		TotalMonthlyUSD: 40,
+		BudgetUSD:       200,
+		BudgetPercent:   20,
+		BudgetPace:      billing.PaceAhead,
+	})
+
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	w := data.Widgets[len(data.Widgets)-1]
+	if w.ID != "billing" {
+		t.Fatalf("expected billing widget, got %s", w.ID)
+	}
+	if !strings.Contains(w.Content, "20% of budget, under pace") {
+		t.Errorf("billing widget should show pace phrase, got %q", w.Content)
+	}
+}
+
+func TestBuildBannerFromCache_BillingCredits(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{
+		Providers: []billing.ProviderBilling{
+			{Name: "civo", Connected: true, MonthToDate: 60, CreditsUSD: 50, NetSpendUSD: 10},
+			{Name: "digitalocean", Connected: true, MonthToDate: 30, NetSpendUSD: 30},
+		},
+		TotalMonthlyUSD: 90, NetMonthlyUSD: 40, BudgetUSD: 200, BudgetPercent: 20,
+	})
+
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	w := data.Widgets[len(data.Widgets)-1]
+	for _, want := range []string{"Spend: $40.00/mo (net) (20% of budget)", "civo $10.00 (net)", "digitalocean $30.00"} {
+		if !strings.Contains(w.Content, want) {
+			t.Errorf("billing widget missing %q, got %q", want, w.Content)
+		}
+	}
+	if strings.Contains(w.Content, "digitalocean $30.00 (net)") {
+		t.Errorf("provider without credits should show gross spend, got %q", w.Content)
+	}
+}
+
+func TestBuildBannerFromCache_BillingSpike(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{
+		TotalMonthlyUSD: 140,
+		Anomaly:         &anomaly.Anomaly{Date: "2026-03-14", SpendUSD: 52.1, MeanUSD: 6.2},
+	})
+
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	w := data.Widgets[len(data.Widgets)-1]
+	if !strings.Contains(w.Content, "⚠️ spike Mar 14 $52.10 (8.4× avg)") {
+		t.Errorf("billing widget should show the spike, got %q", w.Content)
+	}
+	if w.MinH != 4 {
+		t.Errorf("MinH = %d, want 4 for two lines", w.MinH)
+	}
+}
+
+func TestBuildBannerFromCache_BillingProjectedOverBudget(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{
+		TotalMonthlyUSD: 90, BudgetUSD: 200, BudgetPercent: 45,
+		ForecastUSD: 232.5, ProjectedOverBudget: true,
+	})
+
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	w := data.Widgets[len(data.Widgets)-1]
+	if !strings.Contains(w.Content, "📈 projected $232.50, $32.50 over budget") {
+		t.Errorf("billing widget should flag the projected overage, got %q", w.Content)
+	}
+	if strings.Contains(w.Content, "⚠️") {
+		t.Errorf("projected overage should not use the over-budget marker, got %q", w.Content)
+	}
+}
+
+func TestBuildBannerFromCache_CommandCollector(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "buildfarm", command.Result{
+		Title: "Build farm",
+		Data:  map[string]interface{}{"workers": "3/4", "queue": 4, "last_failure": nil},
+	})
+	bnWriteFixture(t, dir, "empty", command.Result{Data: map[string]interface{}{}})
+
+	data := buildBannerFromCache(dir, bnOptions{Commands: []string{"buildfarm", "empty", "absent"}}, "2.0.5", "abc123")
+	if len(data.Widgets) != 2 {
+		t.Fatalf("expected status + buildfarm widgets, got %d", len(data.Widgets))
+	}
+	w := data.Widgets[1]
+	want := "last_failure: —\nqueue:        4\nworkers:      3/4"
+	if w.ID != "buildfarm" || w.Title != "Build farm" || w.Content != want || w.MinH != 5 {
+		t.Errorf("widget = %s %q %q (MinH %d), want content %q", w.ID, w.Title, w.Content, w.MinH, want)
+	}
+}
+
+func TestBuildBannerFromCache_CriticalFirst(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "sysmetrics", sysmetrics.Metrics{Memory: sysmetrics.MemoryMetrics{UsedPercent: 50}})
+	bnWriteFixture(t, dir, "tailscale", tailscale.Status{OnlinePeers: 3, TotalPeers: 5})
+	bnWriteFixture(t, dir, "k8s", k8s.ClusterStatus{Clusters: []k8s.ClusterInfo{
+		{Context: "home", Connected: true, TotalPods: 12, RunningPods: 12},
+		{Context: "civo-prod"},
+	}})
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{TotalCostUSD: 12})
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{TotalMonthlyUSD: 23.45})
+
+	ids := func(data banner.BannerData) string {
+		var out []string
+		for _, w := range data.Widgets {
+			out = append(out, w.ID)
+		}
+		return strings.Join(out, ",")
+	}
+	const normal = "status,system,tailscale,k8s,claude,billing"
+	if got := ids(buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")); got != normal {
+		t.Errorf("without critical_first: order = %s, want %s", got, normal)
+	}
+
+	data := buildBannerFromCache(dir, bnOptions{CriticalFirst: true}, "2.0.5", "abc123")
+	if got, want := ids(data), "k8s,status,system,tailscale,claude,billing"; got != want {
+		t.Errorf("order = %s, want %s", got, want)
+	}
+	if got := data.Widgets[0].Title; got != "🔴 Kubernetes" {
+		t.Errorf("critical title = %q, want %q", got, "🔴 Kubernetes")
+	}
+	for _, w := range data.Widgets[1:] {
+		if strings.Contains(w.Title, "🔴") || strings.Contains(w.Title, "⚠️") {
+			t.Errorf("%s: healthy title marked: %q", w.ID, w.Title)
+		}
+	}
+
+	// Warnings follow criticals; everything healthy keeps the normal order.
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{TotalMonthlyUSD: 85, BudgetUSD: 100, BudgetPercent: 85})
+	data = buildBannerFromCache(dir, bnOptions{CriticalFirst: true}, "2.0.5", "abc123")
+	if got, want := ids(data), "k8s,billing,status,system,tailscale,claude"; got != want {
+		t.Errorf("with a billing warning: order = %s, want %s", got, want)
+	}
+	if got := data.Widgets[1].Title; got != "⚠️ Cloud Billing" {
+		t.Errorf("warning title = %q, want %q", got, "⚠️ Cloud Billing")
+	}
+	bnWriteFixture(t, dir, "k8s", k8s.ClusterStatus{Clusters: []k8s.ClusterInfo{
+		{Context: "home", Connected: true, TotalPods: 12, RunningPods: 12},
+	}})
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{TotalMonthlyUSD: 23.45})
+	if got := ids(buildBannerFromCache(dir, bnOptions{CriticalFirst: true}, "2.0.5", "abc123")); got != normal {
+		t.Errorf("all healthy: order = %s, want %s", got, normal)
+	}
+}
+
+func TestBuildBannerFromCache_SnoozeMutesWarnings(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{
+		TotalMonthlyUSD: 140,
+		Anomaly:         &anomaly.Anomaly{Date: "2026-03-14", SpendUSD: 52.1, MeanUSD: 6.2},
+	})
+	bnWriteFixture(t, dir, "k8s", k8s.ClusterStatus{Clusters: []k8s.ClusterInfo{{
+		Context: "home", Connected: true, TotalPods: 57, RunningPods: 57,
+		Nodes: []k8s.NodeInfo{{Name: "pi-1", Ready: true, MaxPods: 60, PodCount: 57}},
+	}}})
+
+	until := time.Now().Add(90 * time.Minute)
+	bnWriteFixture(t, dir, "snooze", map[string]time.Time{"until": until})
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	status := data.Widgets[0]
+	if want := "💤 alerts snoozed until " + until.Format("15:04"); !strings.Contains(status.Content, want) || status.MinH != 5 {
+		t.Errorf("status widget = %q (MinH %d), want %q", status.Content, status.MinH, want)
+	}
+	for _, w := range data.Widgets[1:] {
+		if strings.Contains(w.Content, "⚠️") || !strings.Contains(w.Content, "💤") {
+			t.Errorf("%s: snoozed warnings should use 💤, got %q", w.ID, w.Content)
+		}
+	}
+
+	// An expired snooze has no effect.
+	bnWriteFixture(t, dir, "snooze", map[string]time.Time{"until": time.Now().Add(-time.Minute)})
+	for _, w := range buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123").Widgets {
+		if strings.Contains(w.Content, "💤") {
+			t.Errorf("%s: expired snooze still shown in %q", w.ID, w.Content)
+		}
+	}
+}
+
+func TestBuildBannerFromCache_BillingProviders(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{
+		TotalMonthlyUSD: 35,
+		Providers: []billing.ProviderBilling{
+			{Name: "civo", Connected: true, MonthToDate: 20},
+			{Name: "digitalocean", Connected: true, MonthToDate: 15},
+			{Name: "aws", Connected: false},
+		},
+	})
+
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	w := data.Widgets[len(data.Widgets)-1]
+	if !strings.Contains(w.Content, "\ncivo $20.00 · digitalocean $15.00") {
+		t.Errorf("billing widget should list connected providers, got %q", w.Content)
+	}
+	if strings.Contains(w.Content, "aws") {
+		t.Errorf("disconnected provider should be omitted, got %q", w.Content)
+	}
+}
+
+func TestBnProviderLine_ZeroSpend(t *testing.T) {
+	b := &billing.BillingReport{
+		TotalMonthlyUSD: 35,
+		Providers: []billing.ProviderBilling{
+			{Name: "civo", Connected: true, MonthToDate: 20},
+			{Name: "hetzner", Connected: true},
+			{Name: "digitalocean", Connected: true, MonthToDate: 15},
+			{Name: "aws", Connected: false},
+		},
+	}
+	if got, want := bnProviderLine(b, bnOptions{}), "civo $20.00 · hetzner $0 · digitalocean $15.00"; got != want {
+		t.Errorf("hide off = %q, want %q", got, want)
+	}
+	whole := bnOptions{Money: components.MoneyFormat{Whole: true}}
+	if got, want := bnProviderLine(b, whole), "civo $20 · hetzner $0 · digitalocean $15"; got != want {
+		t.Errorf("hide off, whole dollars = %q, want %q", got, want)
+	}
+	if got, want := bnProviderLine(b, bnOptions{HideZeroProviders: true}), "civo $20.00 · digitalocean $15.00 · +1 at $0"; got != want {
+		t.Errorf("hide on = %q, want %q", got, want)
+	}
+
+	b.Providers = b.Providers[1:2]
+	if got := bnProviderLine(b, bnOptions{HideZeroProviders: true}); got != "+1 at $0" {
+		t.Errorf("hide on, only a zero provider = %q", got)
+	}
+}
+
+func TestBnProviderShares(t *testing.T) {
+	b := &billing.BillingReport{
+		TotalMonthlyUSD: 200,
+		Providers: []billing.ProviderBilling{
+			{Name: "digitalocean", Connected: true, MonthToDate: 50},
+			{Name: "civo", Connected: true, MonthToDate: 120},
+			{Name: "manual", Connected: true},
+			{Name: "aws", Connected: false, MonthToDate: 99},
+			{Name: "hetzner", Connected: true, MonthToDate: 30},
+		},
+	}
+	shares := bnProviderShares(b)
+	var names []string
+	var sum float64
+	for _, sh := range shares {
+		names = append(names, sh.Name)
+		sum += sh.Percent
+	}
+	if got := strings.Join(names, ","); got != "civo,digitalocean,hetzner" {
+		t.Errorf("share order = %s, want civo,digitalocean,hetzner", got)
+	}
+	if math.Abs(sum-100) > 0.01 {
+		t.Errorf("shares sum to %.3f%%, want 100%%", sum)
+	}
+	if shares[0].Percent != 60 {
+		t.Errorf("civo share = %.1f%%, want 60%%", shares[0].Percent)
+	}
+
+	bar := bnShareBar(shares, bnShareBarWidth)
+	if n := utf8.RuneCountInString(bar); n != bnShareBarWidth {
+		t.Errorf("share bar has %d cells, want %d: %q", n, bnShareBarWidth, bar)
+	}
+	if want := strings.Repeat("█", 12) + strings.Repeat("▓", 5) + strings.Repeat("▒", 3); bar != want {
+		t.Errorf("share bar = %q, want %q", bar, want)
+	}
+
+	b.TotalMonthlyUSD = 0
+	if got := bnProviderShares(b); got != nil {
+		t.Errorf("zero total: shares = %+v, want nil", got)
+	}
+	if got := bnShareLines(nil, bnOptions{}); got != nil {
+		t.Errorf("no shares: lines = %q, want none", got)
+	}
+}
+
+func TestBuildBannerFromCache_ProviderShareBar(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{
+		TotalMonthlyUSD: 35,
+		Providers: []billing.ProviderBilling{
+			{Name: "civo", Connected: true, MonthToDate: 20},
+			{Name: "digitalocean", Connected: true, MonthToDate: 15},
+		},
+	})
+
+	w := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123").Widgets[1]
+	if !strings.Contains(w.Content, "\n"+strings.Repeat("█", 11)+strings.Repeat("▓", 9)+"\n") {
+		t.Errorf("standard layout should show the share bar, got %q", w.Content)
+	}
+	if !strings.Contains(w.Content, "█ civo 57% · ▓ digitalocean 43%") {
+		t.Errorf("standard layout should show the share legend, got %q", w.Content)
+	}
+
+	w = buildBannerFromCache(dir, bnOptions{Compact: true}, "2.0.5", "abc123").Widgets[1]
+	if strings.Contains(w.Content, "█") {
+		t.Errorf("compact layout should not show the share bar, got %q", w.Content)
+	}
+	if !strings.Contains(w.Content, "\ncivo 57% · digitalocean 43%") {
+		t.Errorf("compact layout should show share percentages, got %q", w.Content)
+	}
+
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{
+		Providers: []billing.ProviderBilling{
+			{Name: "civo", Connected: true},
+			{Name: "digitalocean", Connected: true},
+		},
+	})
+	w = buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123").Widgets[1]
+	if strings.Contains(w.Content, "%") {
+		t.Errorf("zero total should hide the share bar, got %q", w.Content)
+	}
+}
+
+func TestBuildBannerFromCache_DashboardURLTemplates(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "k8s", k8s.ClusterStatus{
+		PodPressureThreshold: 80,
+		Clusters: []k8s.ClusterInfo{
+			{
+				Context: "civo-prod", Connected: true, TotalPods: 57, RunningPods: 57, Region: "lon1",
+				DashboardURL: "https://k8s.example.com",
+				Nodes:        []k8s.NodeInfo{{Name: "n1", Ready: true, PodCount: 57, MaxPods: 60}},
+			},
+			{
+				Context: "home", Connected: true, TotalPods: 58, RunningPods: 58,
+				DashboardURL: "https://home.example.com",
+				Nodes:        []k8s.NodeInfo{{Name: "pi-1", Ready: true, PodCount: 58, MaxPods: 60}},
+			},
+		},
+	})
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{
+		TotalMonthlyUSD: 20,
+		Providers: []billing.ProviderBilling{
+			{Name: "civo-prod", Connected: true, MonthToDate: 20, DashboardURL: "https://dashboard.civo.com/billing"},
+		},
+	})
+
+	opts := bnOptions{Hyperlinks: true, DashboardURLs: config.DashboardURLs{
+		"k8s:civo-prod": "https://sso.example.com/k8s/{name}?region={region}",
+	}}
+	var content string
+	for _, w := range buildBannerFromCache(dir, opts, "2.0.5", "abc123").Widgets {
+		content += w.Content + "\n"
+	}
+	for _, want := range []string{
+		components.Hyperlink("civo-prod", "https://sso.example.com/k8s/civo-prod?region=lon1"),
+		components.Hyperlink("home", "https://home.example.com"),
+		components.Hyperlink("civo-prod", "https://dashboard.civo.com/billing"),
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected hyperlink %q in banner:\n%q", want, content)
+		}
+	}
+}
+
+func TestBuildBannerFromCache_Hyperlinks(t *testing.T) {
+	dir := t.TempDir()
+	exit := tailscale.PeerInfo{Hostname: "honey", Online: true, ExitNode: true, TailscaleIPs: []string{"100.64.0.7"}}
+	bnWriteFixture(t, dir, "tailscale", tailscale.Status{
+		OnlinePeers: 1, TotalPeers: 1, Peers: []tailscale.PeerInfo{exit}, ExitNode: &exit,
+	})
+	bnWriteFixture(t, dir, "k8s", k8s.ClusterStatus{
+		PodPressureThreshold: 80,
+		Clusters: []k8s.ClusterInfo{{
+			Context: "home", Connected: true, TotalPods: 57, RunningPods: 57,
+			DashboardURL: "https://k8s.example.com",
+			Nodes:        []k8s.NodeInfo{{Name: "pi-1", Ready: true, PodCount: 57, MaxPods: 60}},
+		}},
+	})
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{
+		TotalMonthlyUSD: 20,
+		Providers: []billing.ProviderBilling{
+			{Name: "civo", Connected: true, MonthToDate: 20, DashboardURL: "https://dashboard.civo.com/billing"},
+			{Name: "manual", Connected: true},
+		},
+	})
+
+	plain := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+	linked := buildBannerFromCache(dir, bnOptions{Hyperlinks: true}, "2.0.5", "abc123")
+
+	for _, want := range []string{
+		components.Hyperlink("honey", "https://login.tailscale.com/admin/machines/100.64.0.7"),
+		components.Hyperlink("home", "https://k8s.example.com"),
+		components.Hyperlink("civo", "https://dashboard.civo.com/billing"),
+	} {
+		found := false
+		for _, w := range linked.Widgets {
+			found = found || strings.Contains(w.Content, want)
+		}
+		if !found {
+			t.Errorf("expected hyperlink %q in banner", want)
+		}
+	}
+
+	for i, w := range plain.Widgets {
+		if strings.Contains(w.Content, "\x1b]8;") {
+			t.Errorf("%s: hyperlinks disabled but found OSC 8 in %q", w.ID, w.Content)
+		}
+		lw := linked.Widgets[i]
+		if strings.Contains(lw.Content, "\x1b]8;;\x07manual") {
+			t.Errorf("%s: provider without a URL should stay plain, got %q", lw.ID, lw.Content)
+		}
+		if w.ID == "status" {
+			continue
+		}
+		pl, ll := strings.Split(w.Content, "\n"), strings.Split(lw.Content, "\n")
+		if len(pl) != len(ll) || w.MinW != lw.MinW || w.MinH != lw.MinH {
+			t.Fatalf("%s: layout changed with hyperlinks", w.ID)
+		}
+		for j := range pl {
+			if components.VisibleLen(ll[j]) != components.VisibleLen(pl[j]) {
+				t.Errorf("%s line %d: visible width %d with links, %d without",
+					w.ID, j, components.VisibleLen(ll[j]), components.VisibleLen(pl[j]))
+			}
+		}
+	}
+}
+
+func TestBuildBannerFromCache_StaleCache(t *testing.T) {
+	dir := t.TempDir()
+
+	// Write a fixture then backdate it beyond the staleness threshold.
+	bnWriteFixture(t, dir, "sysmetrics", sysmetrics.Metrics{
+		CPU: sysmetrics.CPUMetrics{Total: 50},
+	})
+	staleTime := time.Now().Add(-10 * time.Minute)
+	path := filepath.Join(dir, "sysmetrics.json")
+	if err := os.Chtimes(path, staleTime, staleTime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	data := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123")
+
+	// Stale cache should be skipped — only status widget.
+	if len(data.Widgets) != 1 {
+		t.Fatalf("expected 1 widget (stale cache skipped), got %d", len(data.Widgets))
+	}
+}
+
+func TestBnFormatUptime(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Minute, "30m"},
+		{2*time.Hour + 15*time.Minute, "2h 15m"},
+		{49*time.Hour + 30*time.Minute, "2d 1h"},
+	}
+	for _, tt := range tests {
+		got := bnFormatUptime(tt.d)
+		if got != tt.want {
+			t.Errorf("bnFormatUptime(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestBnFreshnessLine(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	dir := t.TempDir()
+	now := time.Now()
+	g := components.GlyphEmoji.Glyphs()
+
+	if got := bnFreshnessLine(dir, nil, "", now, g); got != "no cached data yet" {
+		t.Errorf("empty cache = %q", got)
+	}
+
+	bnWriteFixture(t, dir, "sysmetrics", sysmetrics.Metrics{})
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{})
+	os.Chtimes(filepath.Join(dir, "sysmetrics.json"), now.Add(-2*time.Minute), now.Add(-2*time.Minute))
+	os.Chtimes(filepath.Join(dir, "claude.json"), now.Add(-3*time.Minute), now.Add(-3*time.Minute))
+	if got := bnFreshnessLine(dir, nil, "", now, g); got != "updated 2m ago" {
+		t.Errorf("fresh cache = %q", got)
+	}
+
+	// A shorter TTL makes the older entry stale; its age is reported.
+	ttls := map[string]time.Duration{"claude": time.Minute}
+	if got := bnFreshnessLine(dir, ttls, "", now, g); got != "⚠️ stale 3m" {
+		t.Errorf("stale cache = %q", got)
+	}
+
+	// Without a daemon, fresh data (e.g. from -collect-once) is fine; only
+	// stale data warns.
+	missing := filepath.Join(dir, "prompt-pulse.pid")
+	if got := bnFreshnessLine(dir, nil, missing, now, g); got != "updated 2m ago" {
+		t.Errorf("no daemon, fresh cache = %q", got)
+	}
+	if got := bnFreshnessLine(dir, ttls, missing, now, g); got != "⚠️ daemon not running (updated 2m ago)" {
+		t.Errorf("dead daemon = %q", got)
+	}
+	if got := bnFreshnessLine(dir, ttls, missing, now, components.GlyphASCII.Glyphs()); got != "! daemon not running (updated 2m ago)" {
+		t.Errorf("dead daemon, ascii glyphs = %q", got)
+	}
+	pid := filepath.Join(dir, "live.pid")
+	os.WriteFile(pid, []byte(strconv.Itoa(os.Getpid())), 0644)
+	if got := bnFreshnessLine(dir, nil, pid, now, g); got != "updated 2m ago" {
+		t.Errorf("live daemon = %q", got)
+	}
+}
+
+func TestBnFreshnessLine_ThemeColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	got := bnFreshnessLine(t.TempDir(), nil, filepath.Join(t.TempDir(), "none.pid"), time.Now(), components.Glyphs{})
+	if want := components.Color(theme.Current.StatusError); !strings.HasPrefix(got, want) {
+		t.Errorf("daemon warning should use the theme error color, got %q", got)
+	}
+}
+
+// bnWriteSpendHistory persists a spend history with spend(day) recorded for
+// every day from from through to.
+func bnWriteSpendHistory(t *testing.T, dir string, from, to time.Time, spend func(time.Time) float64) {
+	t.Helper()
+	h := &billing.SpendHistory{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		h.Days = append(h.Days, anomaly.DailySpend{Date: d.Format(anomaly.DateLayout), SpendUSD: spend(d)})
+	}
+	if err := h.Save(billing.SpendHistoryPath(dir)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRenderOverlaySparkline(t *testing.T) {
+	current := []float64{1, 2, 4}
+	baseline := []float64{2, 2, 2, 3, 4, 8}
+
+	t.Setenv("NO_COLOR", "1")
+	if got, want := renderOverlaySparkline(current, baseline), "▂▃▅"; got != want {
+		t.Errorf("plain overlay = %q, want %q", got, want)
+	}
+	if got, want := renderOverlaySparkline(current, nil), "▃▅█"; got != want {
+		t.Errorf("without baseline = %q, want %q", got, want)
+	}
+
+	t.Setenv("NO_COLOR", "")
+	colored := renderOverlaySparkline(current, baseline)
+	if got := components.VisibleLen(colored); got != len(baseline) {
+		t.Errorf("colored overlay spans %d cells, want %d: %q", got, len(baseline), colored)
+	}
+	dim := components.Color(theme.Current.Dim)
+	if n := strings.Count(colored, dim); n != 3 {
+		t.Errorf("expected the 3 days past today in the dim color, got %d in %q", n, colored)
+	}
+	if warn := components.Color(theme.Current.StatusWarn); strings.Count(colored, warn) != 1 {
+		t.Errorf("only the last day runs hotter than the baseline, got %q", colored)
+	}
+}
+
+func TestBnTrendLine(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	now := time.Date(2026, 4, 10, 12, 0, 0, 0, time.Local)
+	dir := t.TempDir()
+	bnWriteSpendHistory(t, dir, time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local), now, func(d time.Time) float64 {
+		if d.Month() == time.March {
+			return 1
+		}
+		return 2
+	})
+	line := bnTrendLine(dir, now, 0)
+	if !strings.HasPrefix(line, "Trend ") || !strings.HasSuffix(line, " +100% vs last month") {
+		t.Errorf("trend line = %q", line)
+	}
+	if got := utf8.RuneCountInString(strings.Fields(line)[1]); got != 10 {
+		t.Errorf("sparkline has %d cells, want one per day so far (10)", got)
+	}
+
+	// Without all of March there is no baseline to compare with.
+	dir = t.TempDir()
+	bnWriteSpendHistory(t, dir, time.Date(2026, 3, 20, 0, 0, 0, 0, time.Local), now, func(time.Time) float64 { return 1 })
+	if line := bnTrendLine(dir, now, 0); !strings.HasPrefix(line, "Trend ") || strings.Contains(line, "last month") {
+		t.Errorf("partial history trend line = %q, want the sparkline alone", line)
+	}
+
+	if line := bnTrendLine(t.TempDir(), now, 0); line != "" {
+		t.Errorf("no history should give no trend line, got %q", line)
+	}
+}
+
+func TestBnVelocityLine(t *testing.T) {
+	now := time.Date(2026, 4, 10, 12, 0, 0, 0, time.Local)
+	dir := t.TempDir()
+	bnWriteSpendHistory(t, dir, time.Date(2026, 4, 1, 0, 0, 0, 0, time.Local), now, func(d time.Time) float64 {
+		if d.Day() == 10 {
+			return 9
+		}
+		return 10
+	})
+
+	t.Setenv("NO_COLOR", "1")
+	if got, want := bnVelocityLine(dir, now, bnOptions{}), "Today $9.00, 1.8× avg pace"; got != want {
+		t.Errorf("velocity line = %q, want %q", got, want)
+	}
+	t.Setenv("NO_COLOR", "")
+	if got := bnVelocityLine(dir, now, bnOptions{}); !strings.Contains(got, components.Color(theme.Current.StatusWarn)) {
+		t.Errorf("a 1.8× day should be in the warning color, got %q", got)
+	}
+	if got := bnVelocityLine(dir, now, bnOptions{SnoozedUntil: now.Add(time.Hour)}); strings.Contains(got, "\x1b") {
+		t.Errorf("snoozed velocity line should not be colored, got %q", got)
+	}
+
+	// By 9pm the same spend is about an average day's pace.
+	if got := bnVelocityLine(dir, now.Add(9*time.Hour), bnOptions{}); strings.Contains(got, "\x1b") || !strings.Contains(got, "1.0× avg pace") {
+		t.Errorf("velocity line late in the day = %q, want an uncolored 1.0×", got)
+	}
+	if got := bnVelocityLine(dir, now.AddDate(0, 0, 1), bnOptions{}); got != "" {
+		t.Errorf("no spend recorded today should give no line, got %q", got)
+	}
+}
+
+func TestBnTrendLine_Points(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	now := time.Date(2026, 4, 10, 12, 0, 0, 0, time.Local)
+	dir := t.TempDir()
+	bnWriteSpendHistory(t, dir, time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local), now, func(d time.Time) float64 {
+		if d.Month() == time.March {
+			return 1
+		}
+		return 2
+	})
+
+	// March's 31 days fit in 8 cells; April's 10 days scale by the same
+	// factor so the curves stay aligned.
+	line := bnTrendLine(dir, now, 8)
+	if !strings.HasSuffix(line, " +100% vs last month") {
+		t.Errorf("comparison should use the daily figures, got %q", line)
+	}
+	spark := strings.TrimSuffix(strings.TrimPrefix(line, "Trend "), " +100% vs last month")
+	if got := components.VisibleLen(spark); got != 8 {
+		t.Errorf("sparkline spans %d cells, want 8: %q", got, line)
+	}
+}
+
+func TestBnSparkPoints(t *testing.T) {
+	tests := []struct {
+		opts bnOptions
+		want int
+	}{
+		{bnOptions{}, 0},
+		{bnOptions{SparklinePoints: 12}, 12},
+		{bnOptions{Width: 120}, 30},
+		{bnOptions{Width: 120, SparklinePoints: 12}, 12},
+		{bnOptions{Width: 120, SparklinePoints: 40}, 30},
+		{bnOptions{Width: 60, Compact: true}, 30},
+		{bnOptions{Width: 40}, bnMinSparkPoints},
+	}
+	for _, tt := range tests {
+		if got := bnSparkPoints(tt.opts); got != tt.want {
+			t.Errorf("bnSparkPoints(%+v) = %d, want %d", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestBuildBannerFromCache_CompareLastMonth(t *testing.T) {
+	now := time.Now()
+	if now.Day() < 2 {
+		t.Skip("the trend line needs two days of the current month")
+	}
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "billing", billing.BillingReport{TotalMonthlyUSD: 12})
+	start := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.Local)
+	bnWriteSpendHistory(t, dir, start, now, func(time.Time) float64 { return 1 })
+
+	if w := buildBannerFromCache(dir, bnOptions{}, "2.0.5", "abc123").Widgets[1]; strings.Contains(w.Content, "Trend") {
+		t.Errorf("trend line shown without compare_last_month: %q", w.Content)
+	}
+	w := buildBannerFromCache(dir, bnOptions{CompareLastMonth: true}, "2.0.5", "abc123").Widgets[1]
+	if !strings.Contains(w.Content, "\nTrend ") || !strings.Contains(w.Content, "vs last month") {
+		t.Errorf("compare_last_month should add the trend line, got %q", w.Content)
+	}
+	if w.MinH != strings.Count(w.Content, "\n")+3 {
+		t.Errorf("MinH = %d does not cover %q", w.MinH, w.Content)
+	}
+	w = buildBannerFromCache(dir, bnOptions{CompareLastMonth: true, Compact: true}, "2.0.5", "abc123").Widgets[1]
+	if strings.Contains(w.Content, "Trend") {
+		t.Errorf("compact layout should not show the trend line, got %q", w.Content)
+	}
 }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/daemon"
+)
+
+// blDefaultTimeout bounds -banner -live collection when
+// shell.banner_timeout is unset.
+const blDefaultTimeout = 2 * time.Second
+
+// blStaleKeys returns the cache keys the banner reads, including the
+// configured command collectors, whose entry in cacheDir is missing or
+// older than its TTL.
+func blStaleKeys(cacheDir string, opts bnOptions, now time.Time) []string {
+	var stale []string
+	ttls := opts.Quiet.CacheTTLs(opts.CacheTTLs, now)
+	for _, key := range append(append([]string(nil), bnCacheKeys...), opts.Commands...) {
+		info, err := os.Stat(filepath.Join(cacheDir, key+".json"))
+		if err != nil || now.Sub(info.ModTime()) > bnCacheTTL(ttls, key) {
+			stale = append(stale, key)
+		}
+	}
+	return stale
+}
+
+// blCollectLive refreshes the missing and stale cache entries for -banner
+// -live by running their collectors inline, for at most
+// shell.banner_timeout. Entries that are not refreshed in time keep
+// whatever data they had, so the banner falls back to the cache. It
+// returns the keys it refreshed.
+func blCollectLive(cfg *config.Config, opts bnOptions) []string {
+	stale := blStaleKeys(cfg.General.CacheDir, opts, time.Now())
+	if len(stale) == 0 {
+		return nil
+	}
+	timeout := cfg.Shell.BannerTimeout.Duration
+	if timeout <= 0 {
+		timeout = blDefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return daemon.CollectLive(ctx, cfg, stale)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// blTestConfig returns a config whose only collector is a command that
+// prints a fixed result, caching into a fresh directory.
+func blTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.General.CacheDir = t.TempDir()
+	cfg.Collectors.SysMetrics.Enabled = false
+	cfg.Collectors.Tailscale.Enabled = false
+	cfg.Collectors.Claude.Enabled = false
+	cfg.Collectors.Commands = []config.CommandCollectorConfig{{
+		Name: "buildfarm",
+		Exec: "sh",
+		Args: []string{"-c", `echo '{"title":"Build farm","data":{"queue":3}}'`},
+	}}
+	return cfg
+}
+
+func TestBlCollectLive_PopulatesMissingKey(t *testing.T) {
+	cfg := blTestConfig(t)
+	opts := bannerOptions(cfg)
+
+	if got := blCollectLive(cfg, opts); len(got) != 1 || got[0] != "buildfarm" {
+		t.Fatalf("blCollectLive() = %v, want [buildfarm]", got)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.General.CacheDir, "buildfarm.json")); err != nil {
+		t.Fatalf("buildfarm cache not written: %v", err)
+	}
+	data := buildBannerFromCache(cfg.General.CacheDir, opts, "2.0.5", "abc123")
+	found := false
+	for _, w := range data.Widgets {
+		if w.ID == "buildfarm" && strings.Contains(w.Content, "3") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("banner missing live buildfarm section: %+v", data.Widgets)
+	}
+
+	// A fresh entry is not collected again.
+	if got := blCollectLive(cfg, opts); len(got) != 0 {
+		t.Errorf("second blCollectLive() = %v, want nothing to refresh", got)
+	}
+}
+
+func TestBlStaleKeys(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	bnWriteFixture(t, dir, "claude", map[string]int{})
+	bnWriteFixture(t, dir, "billing", map[string]int{})
+	old := now.Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "billing.json"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	got := blStaleKeys(dir, bnOptions{CacheTTLs: map[string]time.Duration{"billing": 10 * time.Minute}}, now)
+	if want := "sysmetrics,tailscale,k8s,billing"; strings.Join(got, ",") != want {
+		t.Errorf("blStaleKeys() = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/banner"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/terminal"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
+)
+
+// ANSI sequences used to redraw the watched banner in place.
+const (
+	bwHideCursor  = "\x1b[?25l"
+	bwShowCursor  = "\x1b[?25h"
+	bwClearScreen = "\x1b[2J"
+	bwHome        = "\x1b[H"
+	bwClearEOL    = "\x1b[K"
+	bwClearBelow  = "\x1b[J"
+)
+
+// bwDefaultInterval is how often -banner-watch re-reads the cache when
+// -watch-interval is not set.
+const bwDefaultInterval = 2 * time.Second
+
+// bannerWatcher redraws the banner whenever its inputs change. Width and
+// height overrides of zero fall back to the live terminal size.
+type bannerWatcher struct {
+	out            io.Writer
+	cacheDir       string
+	opts           bnOptions
+	widthOverride  int
+	heightOverride int
+	sizeFunc       func() terminal.Size
+
+	// config, when set, reloads the theme and display settings from the
+	// config file whenever it changes (-watch-config).
+	config *bwConfigWatch
+
+	lastKey string
+}
+
+// bwConfigWatch tracks the config file behind -watch-config by its
+// modification time, so no file notification dependency is needed.
+type bwConfigWatch struct {
+	path  string
+	flag  string // -config, loaded alone; empty loads the layered config
+	theme string // -theme override, which wins over the file
+	mtime time.Time
+}
+
+// newBwConfigWatch starts watching path from its current state. Changes
+// reload the config as startup loaded it from configFlag.
+func newBwConfigWatch(path, configFlag, themeOverride string) (*bwConfigWatch, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &bwConfigWatch{path: path, flag: configFlag, theme: themeOverride, mtime: info.ModTime()}, nil
+}
+
+// reloadConfig re-reads the watched config file if its modification time
+// changed and applies its presentation settings: the theme and everything
+// bannerOptions derives. Collectors are not involved; -banner-watch only
+// reads the cache. A file that fails to load is reported on stderr and the
+// previous settings are kept. It reports whether anything was applied.
+func (w *bannerWatcher) reloadConfig() bool {
+	info, err := os.Stat(w.config.path)
+	if err != nil || info.ModTime().Equal(w.config.mtime) {
+		return false
+	}
+	w.config.mtime = info.ModTime()
+
+	cfg, err := config.LoadFrom(w.config.flag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "prompt-pulse: reload config: %v\n", err)
+		return false
+	}
+	if w.config.theme != "" {
+		theme.SetCurrent(w.config.theme)
+	} else if cfg.Theme.Name != "" {
+		theme.SetCurrent(cfg.Theme.Name)
+	}
+	w.opts = bannerOptions(cfg)
+	w.lastKey = ""
+	return true
+}
+
+// size returns the dimensions to render at, honouring the overrides.
+func (w *bannerWatcher) size() (int, int) {
+	width, height := w.widthOverride, w.heightOverride
+	if width <= 0 || height <= 0 {
+		s := w.sizeFunc()
+		if width <= 0 {
+			width = s.Cols
+		}
+		if height <= 0 {
+			height = s.Rows
+		}
+	}
+	return width, height
+}
+
+// step renders one frame if the banner data, preset, or watched config
+// changed since the last frame, and reports whether anything was drawn.
+func (w *bannerWatcher) step() (bool, error) {
+	if w.config != nil {
+		w.reloadConfig()
+	}
+	width, height := w.size()
+	preset := banner.SelectPreset(width, height)
+	opts := w.opts
+	opts.Compact = preset == banner.Compact || preset == banner.Portrait
+	opts.Width = width
+	data := buildBannerFromCache(w.cacheDir, opts, version, commit)
+
+	key := banner.CacheKey(data, preset)
+	if key == w.lastKey {
+		return false, nil
+	}
+	out, err := banner.RenderCached(w.cacheDir, data, preset)
+	if err != nil {
+		return false, err
+	}
+	w.lastKey = key
+	return true, bwWriteFrame(w.out, out)
+}
+
+// bwWriteFrame homes the cursor and overwrites the previous frame line by
+// line, clearing leftovers to the right of each line and below the frame,
+// so the screen never scrolls or flashes blank.
+func bwWriteFrame(out io.Writer, frame string) error {
+	var b strings.Builder
+	b.WriteString(bwHome)
+	lines := strings.Split(strings.TrimRight(frame, "\n"), "\n")
+	for i, line := range lines {
+		b.WriteString(line)
+		b.WriteString(bwClearEOL)
+		if i < len(lines)-1 {
+			b.WriteString("\r\n")
+		}
+	}
+	b.WriteString(bwClearBelow)
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// runBannerWatch redraws the banner every interval and on terminal resize
+// until ctx is cancelled, then restores the cursor.
+func runBannerWatch(ctx context.Context, w *bannerWatcher, interval time.Duration) error {
+	if interval <= 0 {
+		interval = bwDefaultInterval
+	}
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+
+	fmt.Fprint(w.out, bwHideCursor+bwClearScreen)
+	defer fmt.Fprint(w.out, bwShowCursor+"\n")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.step(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-winch:
+			// A resize may shrink the frame; clear so stale cells from the
+			// wider layout do not linger.
+			fmt.Fprint(w.out, bwClearScreen)
+			w.lastKey = ""
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/terminal"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
+)
+
+func TestBannerWatcher_RedrawsOnlyOnChange(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{TotalCostUSD: 10})
+
+	var out bytes.Buffer
+	size := terminal.Size{Cols: 120, Rows: 35}
+	w := &bannerWatcher{out: &out, cacheDir: dir, sizeFunc: func() terminal.Size { return size }}
+
+	if drawn, err := w.step(); err != nil || !drawn {
+		t.Fatalf("first step = %v, %v; want a frame", drawn, err)
+	}
+	if drawn, _ := w.step(); drawn {
+		t.Error("unchanged cache should not redraw")
+	}
+
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{TotalCostUSD: 20})
+	if drawn, _ := w.step(); !drawn {
+		t.Error("changed cache should redraw")
+	}
+
+	size = terminal.Size{Cols: 220, Rows: 60}
+	if drawn, _ := w.step(); !drawn {
+		t.Error("resize to a different preset should redraw")
+	}
+}
+
+func TestBannerWatcher_SizeOverrides(t *testing.T) {
+	w := &bannerWatcher{
+		widthOverride: 100,
+		sizeFunc:      func() terminal.Size { return terminal.Size{Cols: 80, Rows: 24} },
+	}
+	if width, height := w.size(); width != 100 || height != 24 {
+		t.Errorf("size() = %dx%d, want 100x24", width, height)
+	}
+}
+
+func TestBwWriteFrame_RedrawsInPlace(t *testing.T) {
+	var out bytes.Buffer
+	if err := bwWriteFrame(&out, "one\ntwo\n"); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	if !strings.HasPrefix(got, bwHome) || !strings.HasSuffix(got, bwClearBelow) {
+		t.Errorf("frame should start at home and clear below, got %q", got)
+	}
+	if strings.Count(got, bwClearEOL) != 2 {
+		t.Errorf("each line should clear to end of line, got %q", got)
+	}
+}
+
+func TestBannerWatcher_WatchConfigReloadsTheme(t *testing.T) {
+	t.Cleanup(func() { theme.SetCurrent("default") })
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{TotalCostUSD: 10})
+	path := filepath.Join(dir, "config.toml")
+	writeConfig := func(name string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte("[theme]\nname = \""+name+"\"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+	writeConfig("nord", start)
+	theme.SetCurrent("nord")
+
+	cw, err := newBwConfigWatch(path, path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	w := &bannerWatcher{out: &out, cacheDir: dir, config: cw,
+		sizeFunc: func() terminal.Size { return terminal.Size{Cols: 120, Rows: 35} }}
+	if drawn, err := w.step(); err != nil || !drawn {
+		t.Fatalf("first step = %v, %v; want a frame", drawn, err)
+	}
+
+	writeConfig("dracula", start.Add(time.Minute))
+	if drawn, _ := w.step(); !drawn {
+		t.Error("config change should redraw")
+	}
+	if theme.Current.Name != theme.Get("dracula").Name {
+		t.Errorf("theme.Current = %q, want dracula", theme.Current.Name)
+	}
+
+	// A -theme override wins over the file.
+	w.config.theme = "gruvbox"
+	writeConfig("nord", start.Add(2*time.Minute))
+	w.step()
+	if theme.Current.Name != theme.Get("gruvbox").Name {
+		t.Errorf("theme.Current = %q, want the gruvbox override", theme.Current.Name)
+	}
+}
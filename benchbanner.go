@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/banner"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/perf"
+)
+
+// writeBannerBench prints the render-time summary for -bench-banner: one
+// row per preset and color mode, then whether the Standard preset met
+// perf.BannerStandardTarget. widgets is the number of widgets rendered.
+func writeBannerBench(w io.Writer, widgets int, results []perf.BannerBenchResult) error {
+	noun := "widgets"
+	if widgets == 1 {
+		noun = "widget"
+	}
+	fmt.Fprintf(w, "banner render time, %d %s from the current cache\n\n", widgets, noun)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PRESET\tCOLOR\tTIME/OP\tALLOCS/OP\tBYTES/OP")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\n",
+			r.Preset.Name, bbColorLabel(r.Colored), bbDuration(r.NsPerOp()),
+			r.AllocsPerOp(), r.AllocedBytesPerOp())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	target := perf.BannerStandardTarget
+	var worst int64
+	for _, r := range results {
+		if r.Preset == banner.Standard && r.NsPerOp() > worst {
+			worst = r.NsPerOp()
+		}
+	}
+	verdict := "ok"
+	if time.Duration(worst) > target {
+		verdict = "OVER TARGET"
+	}
+	_, err := fmt.Fprintf(w, "\ntarget: %s under %s: %s (%s)\n", banner.Standard.Name, target, verdict, bbDuration(worst))
+	return err
+}
+
+func bbColorLabel(colored bool) string {
+	if colored {
+		return "colored"
+	}
+	return "plain"
+}
+
+// bbDuration formats a per-operation time in microseconds or milliseconds.
+func bbDuration(ns int64) string {
+	d := time.Duration(ns)
+	if d < time.Millisecond {
+		return fmt.Sprintf("%.1fµs", float64(d)/float64(time.Microsecond))
+	}
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/banner"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/perf"
+)
+
+func bbResult(p banner.Preset, colored bool, perOp time.Duration) perf.BannerBenchResult {
+	r := perf.BannerBenchResult{Preset: p, Colored: colored}
+	r.N = 100
+	r.T = 100 * perOp
+	r.MemAllocs = 300 * 100
+	r.MemBytes = 40000 * 100
+	return r
+}
+
+func TestWriteBannerBench(t *testing.T) {
+	results := []perf.BannerBenchResult{
+		bbResult(banner.Compact, true, 300*time.Microsecond),
+		bbResult(banner.Standard, true, 2*time.Millisecond),
+		bbResult(banner.Standard, false, 1500*time.Microsecond),
+	}
+	var b strings.Builder
+	if err := writeBannerBench(&b, 6, results); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"6 widgets",
+		"compact   colored  300.0µs",
+		"standard  plain    1.50ms   300        40000",
+		"target: standard under 5ms: ok (2.00ms)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+
+	results[2] = bbResult(banner.Standard, false, 7*time.Millisecond)
+	b.Reset()
+	if err := writeBannerBench(&b, 6, results); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "OVER TARGET (7.00ms)") {
+		t.Errorf("slow Standard render should miss the target:\n%s", b.String())
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/shell"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/starship"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
+)
+
+// cmFileFlags names the flags whose value is a path.
+var cmFileFlags = []string{"config", "man-dir"}
+
+// cmFlagValues returns the accepted values of the flags that take one from
+// a fixed set. Segment and theme names come from their packages so the
+// completion script stays in sync as they are added.
+func cmFlagValues() map[string][]string {
+	var completions []string
+	for _, sh := range shell.CompletionShells {
+		completions = append(completions, string(sh))
+	}
+	return map[string][]string{
+		"completion": completions,
+		"starship":   append(append([]string{}, starship.AllModules...), "all"),
+		"shell":      {"bash", "zsh", "fish", "ksh", "powershell"},
+		"theme":      theme.Names(),
+		"export":     {"json", "yaml"},
+		"log-format": {"text", "json"},
+	}
+}
+
+// completionScript returns the tab-completion script for sh covering every
+// flag defined in fs.
+func completionScript(sh string, fs *flag.FlagSet) (string, error) {
+	flags := shell.CompletionFlags(fs, cmFlagValues(), cmFileFlags)
+	return shell.Completion(shell.ShellType(sh), "prompt-pulse", flags)
+}
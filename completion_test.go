@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
+)
+
+func TestCompletionScript(t *testing.T) {
+	fs := flag.NewFlagSet("prompt-pulse", flag.ContinueOnError)
+	fs.String("starship", "", "Output Starship segments")
+	fs.String("theme", "", "Theme override")
+	fs.String("config", "", "Path to configuration file")
+	fs.Bool("banner", false, "Display system status banner")
+
+	for _, sh := range []string{"bash", "zsh", "fish"} {
+		script, err := completionScript(sh, fs)
+		if err != nil {
+			t.Fatalf("completionScript(%s) error: %v", sh, err)
+		}
+		for _, want := range append(theme.Names(), "billing", "all", "banner") {
+			if !strings.Contains(script, want) {
+				t.Errorf("%s completion missing %q", sh, want)
+			}
+		}
+	}
+	if _, err := completionScript("ksh", fs); err == nil {
+		t.Error("completionScript(ksh) should fail")
+	}
+}
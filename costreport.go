@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/anomaly"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+)
+
+// crNone marks a figure that cannot be computed, such as the change against
+// a month the daemon did not observe.
+const crNone = "—"
+
+// crRow is one provider, or the grand total, in a cost report. CurrentUSD
+// is gross spend, compared with last month's; NetUSD is that less monthly
+// credits, which the forecast and budget share are computed from.
+type crRow struct {
+	Provider    string   `json:"provider"`
+	Connected   bool     `json:"connected"`
+	CurrentUSD  float64  `json:"current_usd"`
+	NetUSD      float64  `json:"net_usd"`
+	PreviousUSD *float64 `json:"previous_usd"`
+	DeltaUSD    *float64 `json:"delta_usd"`
+	ChangePct   *float64 `json:"change_pct"`
+	ForecastUSD float64  `json:"forecast_usd"`
+	BudgetPct   *float64 `json:"budget_pct"`
+}
+
+// crReport is the -cost-report document.
+type crReport struct {
+	Month      string    `json:"month"`
+	Providers  []crRow   `json:"providers"`
+	Total      crRow     `json:"total"`
+	BudgetUSD  float64   `json:"budget_usd,omitempty"`
+	BudgetPace string    `json:"budget_pace,omitempty"`
+	Credits    bool      `json:"credits,omitempty"`
+	Window     *crWindow `json:"window,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// crWindow summarizes the recorded daily spend over the -since window.
+// TrendPct compares the average day of the window's second half with its
+// first half, and is unset under two days or when the first half spent
+// nothing.
+type crWindow struct {
+	Since        string               `json:"since"`
+	Days         int                  `json:"days"`
+	TotalUSD     float64              `json:"total_usd"`
+	AvgPerDayUSD float64              `json:"avg_per_day_usd"`
+	TrendPct     *float64             `json:"trend_pct"`
+	Clamped      bool                 `json:"clamped,omitempty"`
+	Daily        []anomaly.DailySpend `json:"daily"`
+}
+
+// buildCostReport compares each provider's month-to-date spend with last
+// month. Forecasts extrapolate gross spend and then subtract credits, and
+// the budget share is of spend net of credits. Offline providers are
+// listed but excluded from the total. The total compares with last month
+// only when every provider it sums has a previous month, and then over
+// those providers alone, so a provider that is offline, gone or new does
+// not count as a change in spend.
+func buildCostReport(b *billing.BillingReport) crReport {
+	ts := b.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	rep := crReport{
+		Month:      ts.Format("January 2006"),
+		Providers:  []crRow{},
+		BudgetUSD:  b.BudgetUSD,
+		BudgetPace: b.BudgetPace,
+		Credits:    b.HasCredits(),
+		Timestamp:  b.Timestamp,
+	}
+	var net, forecast, prev float64
+	connected, compared := 0, 0
+	for _, p := range b.Providers {
+		row := crRow{Provider: p.Name, Connected: p.Connected}
+		if p.Connected {
+			row = crMakeRow(p.Name, p.MonthToDate, billing.NetSpend(p.MonthToDate, p.CreditsUSD),
+				billing.NetForecast(p.MonthToDate, p.CreditsUSD, ts), p.PreviousMonthUSD, b.BudgetUSD)
+			net += row.NetUSD
+			forecast += row.ForecastUSD
+			connected++
+			if p.PreviousMonthUSD != nil {
+				prev += *p.PreviousMonthUSD
+				compared++
+			}
+		}
+		rep.Providers = append(rep.Providers, row)
+	}
+	var previous *float64
+	if compared > 0 && compared == connected {
+		previous = &prev
+	}
+	rep.Total = crMakeRow("TOTAL", b.TotalMonthlyUSD, net, forecast, previous, b.BudgetUSD)
+	return rep
+}
+
+// crMakeRow derives the comparison figures for one row: the change from
+// gross spend, the budget share from net. The change is left unset when
+// last month is unknown or zero, where a percentage would only mislead.
+func crMakeRow(name string, current, net, forecast float64, previous *float64, budget float64) crRow {
+	row := crRow{
+		Provider:    name,
+		Connected:   true,
+		CurrentUSD:  current,
+		NetUSD:      net,
+		PreviousUSD: previous,
+		ForecastUSD: forecast,
+	}
+	if previous != nil {
+		delta := current - *previous
+		row.DeltaUSD = &delta
+		if *previous > 0 {
+			pct := delta / *previous * 100
+			row.ChangePct = &pct
+		}
+	}
+	if budget > 0 {
+		pct := net / budget * 100
+		row.BudgetPct = &pct
+	}
+	return row
+}
+
+// crParseSince resolves a -since value to the first day of the window, as
+// an anomaly.DateLayout date. It accepts a date, a number of days ("7d"),
+// or a Go duration ("36h"); durations cover the days they reach into,
+// counting today.
+func crParseSince(s string, now time.Time) (string, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if d, err := time.ParseInLocation(anomaly.DateLayout, s, now.Location()); err == nil {
+		if d.After(today) {
+			return "", fmt.Errorf("-since %s is in the future", s)
+		}
+		return s, nil
+	}
+
+	var days int
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		v, err := strconv.Atoi(n)
+		if err != nil {
+			return "", fmt.Errorf("invalid -since %q: want a date (2006-01-02), days (7d) or a duration (36h)", s)
+		}
+		days = v
+	} else {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid -since %q: want a date (2006-01-02), days (7d) or a duration (36h)", s)
+		}
+		days = int((d + 24*time.Hour - 1) / (24 * time.Hour))
+	}
+	if days <= 0 {
+		return "", fmt.Errorf("-since %s must be positive", s)
+	}
+	return today.AddDate(0, 0, 1-days).Format(anomaly.DateLayout), nil
+}
+
+// crSpendWindow sums the recorded days from since onward. A window that
+// starts before the history does is clamped to the first recorded day and
+// marked Clamped.
+func crSpendWindow(days []anomaly.DailySpend, since string) (*crWindow, error) {
+	if len(days) == 0 {
+		return nil, fmt.Errorf("no daily spend recorded yet")
+	}
+	w := &crWindow{Since: since, Daily: []anomaly.DailySpend{}}
+	if first := days[0].Date; since < first {
+		w.Since, w.Clamped = first, true
+	}
+	for _, d := range days {
+		if d.Date >= w.Since {
+			w.Daily = append(w.Daily, d)
+			w.TotalUSD += d.SpendUSD
+		}
+	}
+	w.Days = len(w.Daily)
+	if w.Days == 0 {
+		return w, nil
+	}
+	w.AvgPerDayUSD = w.TotalUSD / float64(w.Days)
+
+	if w.Days >= 2 {
+		half := w.Days / 2
+		var early, late float64
+		for i, d := range w.Daily {
+			if i < half {
+				early += d.SpendUSD
+			} else if i >= w.Days-half {
+				late += d.SpendUSD
+			}
+		}
+		if early > 0 {
+			pct := (late - early) / early * 100
+			w.TrendPct = &pct
+		}
+	}
+	return w, nil
+}
+
+// writeCostReport prints the report as JSON or as a table. With color, rises
+// in spend are red and drops green. When credits apply, a NET column shows
+// spend after them next to the gross CURRENT one.
+func writeCostReport(w io.Writer, rep crReport, asJSON, color bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rep)
+	}
+
+	title := "Cloud spend, " + rep.Month
+	if rep.BudgetUSD > 0 {
+		title += fmt.Sprintf(" (budget $%.2f", rep.BudgetUSD)
+		if rep.BudgetPace != "" {
+			title += ", " + rep.BudgetPace
+		}
+		title += ")"
+	}
+	fmt.Fprintln(w, title)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	netHeader := ""
+	if rep.Credits {
+		netHeader = "NET\t"
+	}
+	fmt.Fprintf(tw, "PROVIDER\tCURRENT\t%sPREVIOUS\t%s\t%s\tFORECAST\tBUDGET\n",
+		netHeader, crPaint("DELTA", 0, color), crPaint("CHANGE", 0, color))
+	for _, r := range append(rep.Providers, rep.Total) {
+		if !r.Connected {
+			net := ""
+			if rep.Credits {
+				net = crNone + "\t"
+			}
+			fmt.Fprintf(tw, "%s\toffline\t%s%s\t%s\t%s\t%s\t%s\n",
+				r.Provider, net, crNone, crPaint(crNone, 0, color), crPaint(crNone, 0, color), crNone, crNone)
+			continue
+		}
+		net := ""
+		if rep.Credits {
+			net = fmt.Sprintf("$%.2f\t", r.NetUSD)
+		}
+		delta, change := crNone, crNone
+		var sign float64
+		if r.DeltaUSD != nil {
+			sign = *r.DeltaUSD
+			delta = crSignedUSD(*r.DeltaUSD)
+		}
+		if r.ChangePct != nil {
+			change = fmt.Sprintf("%+.1f%%", *r.ChangePct)
+		}
+		budget := crNone
+		if r.BudgetPct != nil {
+			budget = fmt.Sprintf("%.0f%%", *r.BudgetPct)
+		}
+		fmt.Fprintf(tw, "%s\t$%.2f\t%s%s\t%s\t%s\t$%.2f\t%s\n",
+			r.Provider, r.CurrentUSD, net, crUSD(r.PreviousUSD),
+			crPaint(delta, sign, color), crPaint(change, sign, color),
+			r.ForecastUSD, budget)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if win := rep.Window; win != nil {
+		line := fmt.Sprintf("\nDaily spend since %s (%d days): $%.2f, $%.2f/day", win.Since, win.Days, win.TotalUSD, win.AvgPerDayUSD)
+		if win.TrendPct != nil {
+			line += ", trend " + crPaint(fmt.Sprintf("%+.1f%%", *win.TrendPct), *win.TrendPct, color)
+		}
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+// crPaint colors s red for a positive sign and green for a negative one.
+// Every colored column cell, including the header and unchanged values, gets
+// an escape of the same length so tabwriter's byte-based padding still lines
+// the columns up.
+func crPaint(s string, sign float64, color bool) string {
+	if !color {
+		return s
+	}
+	code := "39" // default foreground
+	switch {
+	case sign > 0:
+		code = "31"
+	case sign < 0:
+		code = "32"
+	}
+	return "\033[" + code + "m" + s + "\033[0m"
+}
+
+// crUSD formats an optional amount, using crNone when it is unknown.
+func crUSD(v *float64) string {
+	if v == nil {
+		return crNone
+	}
+	return fmt.Sprintf("$%.2f", *v)
+}
+
+// crSignedUSD formats a delta as "+$12.50" or "-$3.00".
+func crSignedUSD(v float64) string {
+	if v < 0 {
+		return fmt.Sprintf("-$%.2f", -v)
+	}
+	return fmt.Sprintf("+$%.2f", v)
+}
+
+// crColorOutput reports whether stdout is a terminal that accepts color.
+func crColorOutput() bool {
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0 && bnColorEnabled()
+}
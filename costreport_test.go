@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/anomaly"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+)
+
+func crFloat(v float64) *float64 { return &v }
+
+var crANSI = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func crStrip(s string) string { return crANSI.ReplaceAllString(s, "") }
+
+func crFixture() *billing.BillingReport {
+	return &billing.BillingReport{
+		Providers: []billing.ProviderBilling{
+			{Name: "civo", Connected: true, MonthToDate: 120, PreviousMonthUSD: crFloat(100)},
+			{Name: "digitalocean", Connected: true, MonthToDate: 30},
+			{Name: "aws", Connected: false, PreviousMonthUSD: crFloat(60)},
+		},
+		TotalMonthlyUSD:  150,
+		PreviousMonthUSD: crFloat(160),
+		BudgetUSD:        300,
+		BudgetPace:       billing.PaceOnTrack,
+		// Day 15 of a 30-day month: the forecast doubles month-to-date.
+		Timestamp: time.Date(2026, 9, 15, 12, 0, 0, 0, time.Local),
+	}
+}
+
+func TestBuildCostReport(t *testing.T) {
+	rep := buildCostReport(crFixture())
+
+	if rep.Month != "September 2026" || len(rep.Providers) != 3 {
+		t.Fatalf("report = %+v", rep)
+	}
+	civo := rep.Providers[0]
+	if *civo.DeltaUSD != 20 || *civo.ChangePct != 20 || civo.ForecastUSD != 240 || *civo.BudgetPct != 40 {
+		t.Errorf("civo row = %+v", civo)
+	}
+	if do := rep.Providers[1]; do.PreviousUSD != nil || do.DeltaUSD != nil || do.ChangePct != nil {
+		t.Errorf("digitalocean without a previous month should have no change, got %+v", do)
+	}
+	if aws := rep.Providers[2]; aws.Connected || aws.BudgetPct != nil {
+		t.Errorf("offline provider row = %+v", aws)
+	}
+	// digitalocean has no previous month, so the total has nothing to
+	// compare with.
+	if tot := rep.Total; tot.Provider != "TOTAL" || tot.PreviousUSD != nil || tot.DeltaUSD != nil || *tot.BudgetPct != 50 {
+		t.Errorf("total row = %+v", rep.Total)
+	}
+}
+
+func TestBuildCostReport_TotalComparesSameProviders(t *testing.T) {
+	b := crFixture()
+	b.Providers[1].PreviousMonthUSD = crFloat(40)
+	// Last month's $160 included $60 from aws, which is offline now; the
+	// total compares with civo and digitalocean's $140 alone.
+	if tot := buildCostReport(b).Total; tot.PreviousUSD == nil || *tot.PreviousUSD != 140 || *tot.DeltaUSD != 10 {
+		t.Errorf("total row = %+v, want previous 140 and delta 10", tot)
+	}
+}
+
+func TestBuildCostReport_TotalWithoutPreviousMonth(t *testing.T) {
+	b := crFixture()
+	b.Providers[0].PreviousMonthUSD = nil
+	if tot := buildCostReport(b).Total; tot.PreviousUSD != nil || tot.DeltaUSD != nil {
+		t.Errorf("total with no provider seen last month = %+v, want no comparison", tot)
+	}
+}
+
+func TestBuildCostReport_ZeroPreviousMonth(t *testing.T) {
+	b := crFixture()
+	b.Providers[0].PreviousMonthUSD = crFloat(0)
+	row := buildCostReport(b).Providers[0]
+	if row.DeltaUSD == nil || *row.DeltaUSD != 120 {
+		t.Errorf("delta = %v, want 120", row.DeltaUSD)
+	}
+	if row.ChangePct != nil {
+		t.Errorf("change from $0 should be unset, got %v", *row.ChangePct)
+	}
+}
+
+func TestBuildCostReport_Credits(t *testing.T) {
+	b := crFixture()
+	b.Providers[0].CreditsUSD = 50
+	b.Providers[0].NetSpendUSD = 70
+	b.NetMonthlyUSD = 100
+	rep := buildCostReport(b)
+
+	if !rep.Credits {
+		t.Error("report with civo credits should say so")
+	}
+	civo := rep.Providers[0]
+	if civo.CurrentUSD != 120 || civo.NetUSD != 70 || *civo.DeltaUSD != 20 {
+		t.Errorf("civo row = %+v, want gross 120, net 70, delta on gross", civo)
+	}
+	// Gross $120 on day 15 forecasts $240; the fixed $50 credit leaves $190.
+	if civo.ForecastUSD != 190 || math.Round(*civo.BudgetPct) != 23 {
+		t.Errorf("civo forecast %v, budget %v%%; want 190 and 23%% of budget", civo.ForecastUSD, *civo.BudgetPct)
+	}
+	if tot := rep.Total; tot.CurrentUSD != 150 || tot.NetUSD != 100 || tot.ForecastUSD != 250 || math.Round(*tot.BudgetPct) != 33 {
+		t.Errorf("total row = %+v, want gross 150, net 100, forecast 250, 33%% of budget", tot)
+	}
+
+	var buf bytes.Buffer
+	if err := writeCostReport(&buf, rep, false, false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if fields := strings.Fields(lines[1]); len(fields) < 3 || fields[2] != "NET" {
+		t.Errorf("header = %q, want NET after CURRENT", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); len(fields) != 8 || fields[1] != "$120.00" || fields[2] != "$70.00" || fields[6] != "$190.00" || fields[7] != "23%" {
+		t.Errorf("civo line = %q", lines[2])
+	}
+	if fields := strings.Fields(lines[4]); len(fields) != 8 || fields[1] != "offline" {
+		t.Errorf("aws line = %q, want a — in every column", lines[4])
+	}
+}
+
+func TestWriteCostReport_Text(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCostReport(&buf, buildCostReport(crFixture()), false, false); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected title, header, 3 providers and total:\n%s", out)
+	}
+	if !strings.Contains(lines[0], "September 2026") || !strings.Contains(lines[0], "on-track") {
+		t.Errorf("title = %q", lines[0])
+	}
+	for _, want := range []string{"+$20.00", "+20.0%", "$240.00", "40%"} {
+		if !strings.Contains(lines[2], want) {
+			t.Errorf("civo line %q missing %q", lines[2], want)
+		}
+	}
+	if strings.Contains(lines[3], "%+") || !strings.Contains(lines[3], "—") {
+		t.Errorf("digitalocean line should use — for change, got %q", lines[3])
+	}
+	if !strings.Contains(lines[4], "offline") {
+		t.Errorf("aws line = %q", lines[4])
+	}
+	if !strings.HasPrefix(lines[5], "TOTAL") || strings.Contains(lines[5], "+$") {
+		t.Errorf("total line = %q", lines[5])
+	}
+	if strings.Contains(out, "\033[") {
+		t.Error("uncolored output contains escapes")
+	}
+}
+
+func TestWriteCostReport_ColorKeepsAlignment(t *testing.T) {
+	b := crFixture()
+	b.Providers[1].PreviousMonthUSD = crFloat(40)
+	var buf bytes.Buffer
+	if err := writeCostReport(&buf, buildCostReport(b), false, true); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.Contains(lines[2], "\033[31m+$20.00") {
+		t.Errorf("increase should be red, got %q", lines[2])
+	}
+	if !strings.Contains(lines[3], "\033[32m-$10.00") {
+		t.Errorf("decrease should be green, got %q", lines[3])
+	}
+	col := strings.Index(crStrip(lines[1]), "FORECAST")
+	for _, l := range lines[2:] {
+		plain := crStrip(l)
+		rest := string([]rune(plain)[col:])
+		if !strings.HasPrefix(rest, "$") && !strings.HasPrefix(rest, "—") {
+			t.Errorf("FORECAST column misaligned in %q", plain)
+		}
+	}
+}
+
+func TestWriteCostReport_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCostReport(&buf, buildCostReport(crFixture()), true, true); err != nil {
+		t.Fatal(err)
+	}
+	var rep crReport
+	if err := json.Unmarshal(buf.Bytes(), &rep); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if rep.Total.CurrentUSD != 150 || rep.BudgetPace != billing.PaceOnTrack || rep.Providers[1].ChangePct != nil {
+		t.Errorf("JSON report = %+v", rep)
+	}
+}
+
+// crDays returns 30 days of spend ending 2026-09-30, where day i spends i
+// dollars.
+func crDays() []anomaly.DailySpend {
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local)
+	days := make([]anomaly.DailySpend, 30)
+	for i := range days {
+		days[i] = anomaly.DailySpend{Date: start.AddDate(0, 0, i).Format(anomaly.DateLayout), SpendUSD: float64(i + 1)}
+	}
+	return days
+}
+
+func TestCrParseSince(t *testing.T) {
+	now := time.Date(2026, 9, 30, 15, 0, 0, 0, time.Local)
+	for in, want := range map[string]string{
+		"7d":         "2026-09-24",
+		"1d":         "2026-09-30",
+		"36h":        "2026-09-29",
+		"2026-09-10": "2026-09-10",
+	} {
+		if got, err := crParseSince(in, now); err != nil || got != want {
+			t.Errorf("crParseSince(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "week", "0d", "-2h", "2026-10-01"} {
+		if _, err := crParseSince(bad, now); err == nil {
+			t.Errorf("crParseSince(%q): expected error", bad)
+		}
+	}
+}
+
+func TestCrSpendWindow(t *testing.T) {
+	w, err := crSpendWindow(crDays(), "2026-09-24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Days 24..30 spend $24..$30.
+	if w.Days != 7 || len(w.Daily) != 7 || w.TotalUSD != 189 || w.AvgPerDayUSD != 27 || w.Clamped {
+		t.Errorf("7-day window = %+v", w)
+	}
+	// The last three days ($28-30, $87) against the first three ($24-26, $75).
+	if w.TrendPct == nil {
+		t.Error("TrendPct unset, want 16")
+	} else if *w.TrendPct != 16 {
+		t.Errorf("TrendPct = %v, want 16", *w.TrendPct)
+	}
+
+	w, err = crSpendWindow(crDays(), "2026-08-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !w.Clamped || w.Since != "2026-09-01" || w.Days != 30 || w.TotalUSD != 465 {
+		t.Errorf("clamped window = %+v", w)
+	}
+
+	if _, err := crSpendWindow(nil, "2026-09-24"); err == nil {
+		t.Error("expected error without history")
+	}
+}
+
+func TestWriteCostReport_Window(t *testing.T) {
+	rep := buildCostReport(crFixture())
+	rep.Window, _ = crSpendWindow(crDays(), "2026-09-24")
+	var buf bytes.Buffer
+	if err := writeCostReport(&buf, rep, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Daily spend since 2026-09-24 (7 days): $189.00, $27.00/day, trend +16.0%"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, buf.String())
+	}
+}
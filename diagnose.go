@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/daemon"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
+)
+
+// dgCredentialEnv lists the credential environment variables config.Load
+// and config.ResolveSecretCommands read, in the order -diagnose reports
+// them.
+var dgCredentialEnv = []string{
+	"ANTHROPIC_ADMIN_KEY",
+	"ANTHROPIC_ADMIN_KEY_FILE",
+	"ANTHROPIC_ADMIN_KEY_CMD",
+	"ANTHROPIC_ADMIN_KEYS_FILE",
+	"ANTHROPIC_ADMIN_KEYS_CMD",
+	"CIVO_TOKEN",
+	"CIVO_API_KEY_FILE",
+	"CIVO_API_KEY_CMD",
+	"DIGITALOCEAN_TOKEN",
+	"DIGITALOCEAN_TOKEN_FILE",
+	"DIGITALOCEAN_TOKEN_CMD",
+}
+
+// dgDaemonHealth reports whether the daemon is running and, if so, its last
+// health snapshot.
+type dgDaemonHealth func() (running bool, health *daemon.HealthStatus, err error)
+
+// writeDiagnostics prints the -diagnose report: theme registry, config
+// file, credential environment, and daemon status. When the config was
+// layered from several files (see config.Load), they are all listed along
+// with the file that set each value. cfg may be nil when the
+// config failed to load, in which case cfgErr is reported. Unless
+// general.redact_secrets is turned off, the whole report is passed through
+// config.RedactString so it is safe to paste into an issue.
+func writeDiagnostics(w io.Writer, cfg *config.Config, cfgPath string, cfgErr error, daemonHealth dgDaemonHealth) error {
+	var b strings.Builder
+	fmt.Fprintln(&b, "prompt-pulse v2 diagnostics")
+	fmt.Fprintln(&b, "===========================")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "Theme registry:")
+	for _, name := range theme.Names() {
+		marker := "  "
+		if name == theme.Current.Name {
+			marker = "* "
+		}
+		fmt.Fprintf(&b, "  %s%s\n", marker, name)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "Config file:")
+	var prov *config.Provenance
+	if cfg != nil {
+		prov = cfg.Provenance()
+	}
+	switch {
+	case prov != nil && len(prov.Files) > 1:
+		for _, f := range prov.Files {
+			fmt.Fprintf(&b, "  %s\n", f)
+		}
+		fmt.Fprintln(&b, "  (later files override earlier ones)")
+	case cfgPath == "":
+		fmt.Fprintln(&b, "  none found (using defaults)")
+	default:
+		fmt.Fprintf(&b, "  %s\n", cfgPath)
+	}
+	if cfgErr != nil {
+		fmt.Fprintf(&b, "  load error: %v\n", cfgErr)
+	}
+	if prov != nil && len(prov.Files) > 1 {
+		fmt.Fprintln(&b)
+		fmt.Fprintln(&b, "Config values by file:")
+		keys := prov.SortedKeys()
+		w := 0
+		for _, k := range keys {
+			w = max(w, len(k))
+		}
+		for _, k := range keys {
+			fmt.Fprintf(&b, "  %-*s %s\n", w, k, prov.Keys[k])
+		}
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "Credential environment:")
+	for _, name := range dgCredentialEnv {
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			v = "unset"
+		}
+		fmt.Fprintf(&b, "  %-26s %s\n", name, v)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "Daemon status:")
+	running, health, err := daemonHealth()
+	switch {
+	case err != nil:
+		fmt.Fprintf(&b, "  daemon init error: %v\n", err)
+	case !running:
+		fmt.Fprintln(&b, "  not running")
+	default:
+		fmt.Fprintln(&b, "  running")
+		if health != nil {
+			data, _ := json.MarshalIndent(health, "  ", "  ")
+			fmt.Fprintln(&b, "  "+string(data))
+		}
+	}
+
+	out := b.String()
+	if cfg == nil || cfg.General.RedactSecrets {
+		out = config.RedactString(out)
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
+
+// dgLocalDaemonHealth checks the daemon using its default PID and health
+// files.
+func dgLocalDaemonHealth() (bool, *daemon.HealthStatus, error) {
+	d, err := daemon.New(daemon.DefaultConfig())
+	if err != nil {
+		return false, nil, err
+	}
+	if !d.IsRunning() {
+		return false, nil, nil
+	}
+	health, err := d.Health()
+	if err != nil {
+		return true, nil, nil
+	}
+	return true, health, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/daemon"
+)
+
+const dgTestKey = "sk-ant-REDACTED"
+
+func dgNotRunning() (bool, *daemon.HealthStatus, error) { return false, nil, nil }
+
+func TestWriteDiagnostics_RedactsCredentials(t *testing.T) {
+	t.Setenv("ANTHROPIC_ADMIN_KEY", dgTestKey)
+	t.Setenv("DIGITALOCEAN_TOKEN_FILE", "/run/secrets/do-token")
+	t.Setenv("CIVO_TOKEN", "")
+
+	var b strings.Builder
+	if err := writeDiagnostics(&b, config.DefaultConfig(), "/home/me/.config/prompt-pulse/config.toml", nil, dgNotRunning); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if strings.Contains(out, dgTestKey) || !strings.Contains(out, "****Cv5b") {
+		t.Errorf("admin key should be masked as ****Cv5b:\n%s", out)
+	}
+	for _, want := range []string{
+		"/home/me/.config/prompt-pulse/config.toml",
+		"/run/secrets/do-token",
+		"DIGITALOCEAN_TOKEN         unset",
+		"not running",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diagnostics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteDiagnostics_RedactionDisabled(t *testing.T) {
+	t.Setenv("ANTHROPIC_ADMIN_KEY", dgTestKey)
+	cfg := config.DefaultConfig()
+	cfg.General.RedactSecrets = false
+
+	var b strings.Builder
+	if err := writeDiagnostics(&b, cfg, "", nil, dgNotRunning); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), dgTestKey) {
+		t.Errorf("redact_secrets = false should print the key as-is:\n%s", b.String())
+	}
+}
+
+func TestWriteDiagnostics_UnloadableConfigStillRedacted(t *testing.T) {
+	running := func() (bool, *daemon.HealthStatus, error) {
+		return true, &daemon.HealthStatus{PID: 4242}, nil
+	}
+	loadErr := errors.New("parse config: bad value near admin_key = \"" + dgTestKey +
+		"\" and api_key = \"0f3a9c2e7b1d4f6a8e0c2b4d6f8a0c2e\"")
+
+	var b strings.Builder
+	if err := writeDiagnostics(&b, nil, "", loadErr, running); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if strings.Contains(out, dgTestKey) || strings.Contains(out, "0f3a9c2e7b1d4f6a8e0c2b4d6f8a0c2e") {
+		t.Errorf("secrets leaked with an unloadable config:\n%s", out)
+	}
+	for _, want := range []string{"none found (using defaults)", "load error:", "****Cv5b", "****0c2e", `"pid": 4242`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diagnostics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteDiagnostics_LayeredConfig(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.toml")
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(base, []byte("[theme]\nname = \"nord\"\n\n[display]\nglyphs = \"ascii\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("include = [\"base.toml\"]\n\n[general]\nredact_secrets = false\n\n[theme]\nname = \"dracula\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := writeDiagnostics(&b, cfg, path, nil, dgNotRunning); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"  " + base + "\n  " + path + "\n  (later files override earlier ones)",
+		"display.glyphs         " + base,
+		"general.redact_secrets " + path,
+		"theme.name             " + path,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diagnostics missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+)
+
+// dfMinSpendDelta is the smallest spend change, in USD, reported by -diff.
+// Billing APIs round differently between polls; sub-cent movement is noise.
+const dfMinSpendDelta = 0.01
+
+// dfChange is one meaningful difference between two exported snapshots.
+type dfChange struct {
+	Collector string `json:"collector"`
+	Item      string `json:"item"`
+	Message   string `json:"message"`
+}
+
+// dfReport is the JSON document written by -diff -json.
+type dfReport struct {
+	Changes []dfChange `json:"changes"`
+}
+
+// dfLoadSnapshot reads a snapshot written by -export in either format and
+// decodes each known section into its collector type.
+func dfLoadSnapshot(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var raw struct {
+		Collectors map[string]struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"collectors"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if raw.Collectors == nil {
+		return nil, fmt.Errorf("%s: not a prompt-pulse export (no collectors)", path)
+	}
+
+	out := make(map[string]interface{}, len(raw.Collectors))
+	for key, sec := range raw.Collectors {
+		newFn, ok := exDecoders[key]
+		if !ok || len(sec.Data) == 0 {
+			out[key] = nil
+			continue
+		}
+		v := newFn()
+		if err := json.Unmarshal(sec.Data, v); err != nil {
+			return nil, fmt.Errorf("%s: collector %s: %w", path, key, err)
+		}
+		out[key] = v
+	}
+	return out, nil
+}
+
+// diffSnapshots compares two decoded snapshots and returns the meaningful
+// changes ordered by collector. Timestamps and sub-cent spend jitter are
+// ignored.
+func diffSnapshots(old, cur map[string]interface{}) []dfChange {
+	var changes []dfChange
+	keys := make(map[string]bool)
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range cur {
+		keys[k] = true
+	}
+
+	for key := range keys {
+		o, inOld := old[key]
+		n, inNew := cur[key]
+		switch {
+		case !inOld:
+			changes = append(changes, dfChange{key, key, "collector appeared"})
+			continue
+		case !inNew:
+			changes = append(changes, dfChange{key, key, "collector disappeared"})
+			continue
+		}
+		switch ov := o.(type) {
+		case *billing.BillingReport:
+			if nv, ok := n.(*billing.BillingReport); ok {
+				changes = append(changes, dfBilling(ov, nv)...)
+			}
+		case *k8s.ClusterStatus:
+			if nv, ok := n.(*k8s.ClusterStatus); ok {
+				changes = append(changes, dfK8s(ov, nv)...)
+			}
+		case *tailscale.Status:
+			if nv, ok := n.(*tailscale.Status); ok {
+				changes = append(changes, dfTailscale(ov, nv)...)
+			}
+		case *claude.UsageReport:
+			if nv, ok := n.(*claude.UsageReport); ok {
+				changes = append(changes, dfClaude(ov, nv)...)
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Collector != changes[j].Collector {
+			return changes[i].Collector < changes[j].Collector
+		}
+		return changes[i].Item < changes[j].Item
+	})
+	return changes
+}
+
+func dfBilling(o, n *billing.BillingReport) []dfChange {
+	var changes []dfChange
+	oldProv := make(map[string]billing.ProviderBilling, len(o.Providers))
+	for _, p := range o.Providers {
+		oldProv[p.Name] = p
+	}
+	for _, p := range n.Providers {
+		op, ok := oldProv[p.Name]
+		delete(oldProv, p.Name)
+		switch {
+		case !ok:
+			changes = append(changes, dfChange{"billing", p.Name, "provider added"})
+		case op.Connected && !p.Connected:
+			changes = append(changes, dfChange{"billing", p.Name, "went offline"})
+		case !op.Connected && p.Connected:
+			changes = append(changes, dfChange{"billing", p.Name, "came back online"})
+		case math.Abs(p.MonthToDate-op.MonthToDate) >= dfMinSpendDelta:
+			changes = append(changes, dfChange{"billing", p.Name, dfSpend(op.MonthToDate, p.MonthToDate)})
+		}
+	}
+	for name := range oldProv {
+		changes = append(changes, dfChange{"billing", name, "provider removed"})
+	}
+	if math.Abs(n.TotalMonthlyUSD-o.TotalMonthlyUSD) >= dfMinSpendDelta {
+		changes = append(changes, dfChange{"billing", "total", dfSpend(o.TotalMonthlyUSD, n.TotalMonthlyUSD)})
+	}
+	return changes
+}
+
+// dfSpend formats a spend change as "spend $10.00 → $12.50 (+$2.50)".
+func dfSpend(o, n float64) string {
+	sign := "+"
+	if n < o {
+		sign = "-"
+	}
+	return fmt.Sprintf("spend $%.2f → $%.2f (%s$%.2f)", o, n, sign, math.Abs(n-o))
+}
+
+func dfK8s(o, n *k8s.ClusterStatus) []dfChange {
+	var changes []dfChange
+	oldClusters := make(map[string]k8s.ClusterInfo, len(o.Clusters))
+	for _, c := range o.Clusters {
+		oldClusters[c.Context] = c
+	}
+	for _, c := range n.Clusters {
+		oc, ok := oldClusters[c.Context]
+		switch {
+		case !ok:
+			changes = append(changes, dfChange{"k8s", c.Context, "cluster added"})
+			continue
+		case oc.Connected && !c.Connected:
+			changes = append(changes, dfChange{"k8s", c.Context, "went offline"})
+			continue
+		case !oc.Connected && c.Connected:
+			changes = append(changes, dfChange{"k8s", c.Context, "came back online"})
+		}
+
+		oldReady := make(map[string]bool, len(oc.Nodes))
+		for _, nd := range oc.Nodes {
+			oldReady[nd.Name] = nd.Ready
+		}
+		for _, nd := range c.Nodes {
+			item := c.Context + "/" + nd.Name
+			was, seen := oldReady[nd.Name]
+			switch {
+			case !seen:
+				changes = append(changes, dfChange{"k8s", item, "node added"})
+			case was && !nd.Ready:
+				changes = append(changes, dfChange{"k8s", item, "node went NotReady"})
+			case !was && nd.Ready:
+				changes = append(changes, dfChange{"k8s", item, "node became Ready"})
+			}
+		}
+		if c.FailedPods != oc.FailedPods {
+			changes = append(changes, dfChange{"k8s", c.Context,
+				fmt.Sprintf("failed pods %d → %d", oc.FailedPods, c.FailedPods)})
+		}
+	}
+	return changes
+}
+
+func dfTailscale(o, n *tailscale.Status) []dfChange {
+	var changes []dfChange
+	oldOnline := make(map[string]bool, len(o.Peers))
+	for _, p := range o.Peers {
+		oldOnline[p.Hostname] = p.Online
+	}
+	for _, p := range n.Peers {
+		was, seen := oldOnline[p.Hostname]
+		switch {
+		case !seen:
+			changes = append(changes, dfChange{"tailscale", p.Hostname, "peer added"})
+		case was && !p.Online:
+			changes = append(changes, dfChange{"tailscale", p.Hostname, "went offline"})
+		case !was && p.Online:
+			changes = append(changes, dfChange{"tailscale", p.Hostname, "came online"})
+		}
+	}
+	oldExit, newExit := "", ""
+	if o.ExitNode != nil {
+		oldExit = o.ExitNode.Hostname
+	}
+	if n.ExitNode != nil {
+		newExit = n.ExitNode.Hostname
+	}
+	if oldExit != newExit {
+		changes = append(changes, dfChange{"tailscale", "exit node",
+			fmt.Sprintf("%s → %s", dfOrNone(oldExit), dfOrNone(newExit))})
+	}
+	return changes
+}
+
+func dfClaude(o, n *claude.UsageReport) []dfChange {
+	var changes []dfChange
+	oldAccts := make(map[string]claude.AccountUsage, len(o.Accounts))
+	for _, a := range o.Accounts {
+		oldAccts[a.Name] = a
+	}
+	for _, a := range n.Accounts {
+		oa, ok := oldAccts[a.Name]
+		switch {
+		case !ok:
+			changes = append(changes, dfChange{"claude", a.Name, "account added"})
+		case oa.Connected && !a.Connected:
+			changes = append(changes, dfChange{"claude", a.Name, "went offline"})
+		case !oa.Connected && a.Connected:
+			changes = append(changes, dfChange{"claude", a.Name, "came back online"})
+		case oa.Level() != a.Level():
+			changes = append(changes, dfChange{"claude", a.Name,
+				fmt.Sprintf("utilization %s → %s (%.0f%% → %.0f%%)", oa.Level(), a.Level(), oa.Utilization, a.Utilization)})
+		}
+	}
+	return changes
+}
+
+// dfOrNone substitutes "none" for an empty name.
+func dfOrNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// writeDiff prints changes grouped under a header per collector, or as a
+// dfReport when asJSON is set.
+func writeDiff(w io.Writer, changes []dfChange, asJSON bool) error {
+	if asJSON {
+		if changes == nil {
+			changes = []dfChange{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(dfReport{Changes: changes})
+	}
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "no meaningful changes")
+		return err
+	}
+	var b strings.Builder
+	group := ""
+	for _, c := range changes {
+		if c.Collector != group {
+			group = c.Collector
+			fmt.Fprintf(&b, "%s:\n", group)
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", c.Item, c.Message)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
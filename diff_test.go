@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+)
+
+// dfWriteSnapshot exports a cache dir populated with fixtures to a file in
+// the given format, the same way -export would, and returns its path.
+func dfWriteSnapshot(t *testing.T, name, format string, fixtures map[string]interface{}) string {
+	t.Helper()
+	cacheDir := t.TempDir()
+	for key, v := range fixtures {
+		bnWriteFixture(t, cacheDir, key, v)
+	}
+	snap, err := buildExport(cacheDir, nil, time.Now())
+	if err != nil {
+		t.Fatalf("buildExport() error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := writeExport(&buf, snap, format); err != nil {
+		t.Fatalf("writeExport() error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func dfLoadPair(t *testing.T, old, cur map[string]interface{}) []dfChange {
+	t.Helper()
+	o, err := dfLoadSnapshot(dfWriteSnapshot(t, "old.json", "json", old))
+	if err != nil {
+		t.Fatalf("load old: %v", err)
+	}
+	n, err := dfLoadSnapshot(dfWriteSnapshot(t, "new.json", "json", cur))
+	if err != nil {
+		t.Fatalf("load new: %v", err)
+	}
+	return diffSnapshots(o, n)
+}
+
+func TestDiffSnapshots_Identical(t *testing.T) {
+	fixtures := map[string]interface{}{
+		"billing": billing.BillingReport{TotalMonthlyUSD: 12, Providers: []billing.ProviderBilling{
+			{Name: "civo", Connected: true, MonthToDate: 12},
+		}},
+		"tailscale": tailscale.Status{Peers: []tailscale.PeerInfo{{Hostname: "a", Online: true}}},
+	}
+	if changes := dfLoadPair(t, fixtures, fixtures); len(changes) != 0 {
+		t.Errorf("expected no changes between identical snapshots, got %+v", changes)
+	}
+}
+
+func TestDiffSnapshots_IgnoresSubCentJitter(t *testing.T) {
+	old := map[string]interface{}{"billing": billing.BillingReport{
+		TotalMonthlyUSD: 10.001,
+		Providers:       []billing.ProviderBilling{{Name: "civo", Connected: true, MonthToDate: 10.001}},
+	}}
+	cur := map[string]interface{}{"billing": billing.BillingReport{
+		TotalMonthlyUSD: 10.004,
+		Providers:       []billing.ProviderBilling{{Name: "civo", Connected: true, MonthToDate: 10.004}},
+	}}
+	if changes := dfLoadPair(t, old, cur); len(changes) != 0 {
+		t.Errorf("sub-cent jitter should be ignored, got %+v", changes)
+	}
+}
+
+func TestDiffSnapshots_ReportsChanges(t *testing.T) {
+	old := map[string]interface{}{
+		"billing": billing.BillingReport{TotalMonthlyUSD: 10, Providers: []billing.ProviderBilling{
+			{Name: "civo", Connected: true, MonthToDate: 10},
+		}},
+		"k8s": k8s.ClusterStatus{Clusters: []k8s.ClusterInfo{
+			{Context: "prod", Connected: true, Nodes: []k8s.NodeInfo{{Name: "n1", Ready: true}}},
+			{Context: "dev", Connected: true},
+		}},
+		"tailscale": tailscale.Status{Peers: []tailscale.PeerInfo{{Hostname: "nas", Online: true}}},
+		"claude": claude.UsageReport{Accounts: []claude.AccountUsage{
+			{Name: "work", Connected: true, BudgetUSD: 100, Utilization: 40},
+		}},
+	}
+	cur := map[string]interface{}{
+		"billing": billing.BillingReport{TotalMonthlyUSD: 12.5, Providers: []billing.ProviderBilling{
+			{Name: "civo", Connected: true, MonthToDate: 12.5},
+		}},
+		"k8s": k8s.ClusterStatus{Clusters: []k8s.ClusterInfo{
+			{Context: "prod", Connected: true, Nodes: []k8s.NodeInfo{{Name: "n1", Ready: false}}},
+			{Context: "dev", Connected: false},
+		}},
+		"tailscale": tailscale.Status{Peers: []tailscale.PeerInfo{{Hostname: "nas", Online: false}}},
+		"claude": claude.UsageReport{Accounts: []claude.AccountUsage{
+			{Name: "work", Connected: true, BudgetUSD: 100, Utilization: 75},
+		}},
+	}
+
+	changes := dfLoadPair(t, old, cur)
+	want := []string{
+		"billing civo spend $10.00 → $12.50 (+$2.50)",
+		"billing total spend $10.00 → $12.50 (+$2.50)",
+		"claude work utilization ok → warn (40% → 75%)",
+		"k8s dev went offline",
+		"k8s prod/n1 node went NotReady",
+		"tailscale nas went offline",
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Collector+" "+c.Item+" "+c.Message)
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDiffSnapshots_CollectorAddedRemoved(t *testing.T) {
+	old := map[string]interface{}{"billing": billing.BillingReport{}}
+	cur := map[string]interface{}{"tailscale": tailscale.Status{}}
+	changes := dfLoadPair(t, old, cur)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Message != "collector disappeared" || changes[1].Message != "collector appeared" {
+		t.Errorf("unexpected messages: %+v", changes)
+	}
+}
+
+func TestDfLoadSnapshot_YAML(t *testing.T) {
+	path := dfWriteSnapshot(t, "snap.yaml", "yaml", map[string]interface{}{
+		"claude": claude.UsageReport{Accounts: []claude.AccountUsage{{Name: "work"}}},
+	})
+	snap, err := dfLoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("dfLoadSnapshot() error: %v", err)
+	}
+	r, ok := snap["claude"].(*claude.UsageReport)
+	if !ok || len(r.Accounts) != 1 || r.Accounts[0].Name != "work" {
+		t.Errorf("claude section = %#v", snap["claude"])
+	}
+}
+
+func TestDfLoadSnapshot_NotExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.json")
+	if err := os.WriteFile(path, []byte(`{"foo":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dfLoadSnapshot(path); err == nil {
+		t.Error("expected error for a file without collectors")
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	changes := []dfChange{
+		{"billing", "civo", "went offline"},
+		{"k8s", "prod", "went offline"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeDiff(&buf, changes, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "billing:\n  civo: went offline\nk8s:\n  prod: went offline\n"
+	if buf.String() != want {
+		t.Errorf("text output:\n%q\nwant:\n%q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := writeDiff(&buf, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "no meaningful changes") {
+		t.Errorf("empty diff output = %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeDiff(&buf, changes, true); err != nil {
+		t.Fatal(err)
+	}
+	var rep dfReport
+	if err := json.Unmarshal(buf.Bytes(), &rep); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(rep.Changes) != 2 || rep.Changes[1].Collector != "k8s" {
+		t.Errorf("JSON changes = %+v", rep.Changes)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/banner"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/daemon"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/starship"
+)
+
+// drStep is one line of a -doctor report.
+type drStep struct {
+	Step     string          `json:"step"`
+	Severity config.Severity `json:"severity"`
+	Message  string          `json:"message"`
+	Hint     string          `json:"hint,omitempty"`
+}
+
+// drReport is the JSON document written by -doctor -json.
+type drReport struct {
+	OK       bool     `json:"ok"`
+	ExitCode int      `json:"exit_code"`
+	Steps    []drStep `json:"steps"`
+}
+
+// drEnv holds the probes -doctor reaches outside the process with, so tests
+// can substitute them.
+type drEnv struct {
+	tailscale    tailscale.StatusClient
+	contextNames func(kubeconfig string) ([]string, error)
+	daemon       daemon.Config
+	timeout      time.Duration
+	version      string
+	commit       string
+}
+
+// doctorChecks checks the whole pipeline end to end: the config, one run of
+// every enabled collector, the cache directory, the daemon and its socket,
+// and a banner and Starship render from the cache. Each check reuses the
+// code path of the command that owns it (-validate-config, -profile,
+// -health, -banner, -starship). A config that fails to load stops the run
+// after its step.
+func doctorChecks(ctx context.Context, cfg *config.Config, cfgPath string, cfgErr error, env drEnv) []drStep {
+	if cfgErr != nil {
+		return []drStep{{Step: "config", Severity: config.SeverityFail, Message: cfgErr.Error(),
+			Hint: "fix the file named above; -config selects another"}}
+	}
+
+	steps := drConfigSteps(cfg, cfgPath, env)
+	steps = append(steps, drCollectorSteps(ctx, cfg, env.timeout)...)
+	steps = append(steps, drCacheDirStep(cfg.General.CacheDir))
+	steps = append(steps, drDaemonSteps(env.daemon)...)
+	steps = append(steps, drBannerStep(cfg, env), drStarshipStep(cfg))
+	return steps
+}
+
+// drConfigSteps folds the -validate-config diagnostics into doctor steps,
+// listing only the items that need attention.
+func drConfigSteps(cfg *config.Config, cfgPath string, env drEnv) []drStep {
+	var steps []drStep
+	for _, d := range validateConfig(cfg, env.tailscale, env.contextNames) {
+		if d.Severity == config.SeverityOK {
+			continue
+		}
+		steps = append(steps, drStep{Step: "config " + d.Item, Severity: d.Severity, Message: d.Message,
+			Hint: "see -validate-config"})
+	}
+	if len(steps) == 0 {
+		where := cfgPath
+		if where == "" {
+			where = "built-in defaults"
+		}
+		steps = append(steps, drStep{Step: "config", Severity: config.SeverityOK, Message: "valid (" + where + ")"})
+	}
+	return steps
+}
+
+// drCollectorSteps runs every enabled collector once via the -profile code
+// path. Nothing is written to the cache.
+func drCollectorSteps(ctx context.Context, cfg *config.Config, timeout time.Duration) []drStep {
+	reg, cleanup, err := profileRegistry(cfg)
+	if err != nil {
+		return []drStep{{Step: "collectors", Severity: config.SeverityFail, Message: err.Error()}}
+	}
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	results := profileCollectors(ctx, reg)
+	if len(results) == 0 {
+		return []drStep{{Step: "collectors", Severity: config.SeverityWarn, Message: "no collectors enabled",
+			Hint: "enable one under [collectors] in the config"}}
+	}
+	steps := make([]drStep, 0, len(results))
+	for _, r := range results {
+		s := drStep{Step: "collector " + r.Collector, Severity: config.SeverityOK,
+			Message: "collected in " + r.Duration.Round(time.Millisecond).String()}
+		if !r.OK {
+			s.Severity = config.SeverityFail
+			s.Message = r.Error
+			s.Hint = "check its credentials and service with -validate-config, or disable it"
+		}
+		steps = append(steps, s)
+	}
+	return steps
+}
+
+// drCacheDirStep checks that the cache directory exists, or can be created,
+// and accepts writes.
+func drCacheDirStep(dir string) drStep {
+	s := drStep{Step: "cache dir", Severity: config.SeverityFail,
+		Hint: "fix its permissions or set general.cache_dir"}
+	if dir == "" {
+		s.Message = "no cache directory configured"
+		return s
+	}
+	if err := cache.EnsurePrivateDir(dir); err != nil {
+		s.Message = err.Error()
+		return s
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		s.Message = "not writable: " + err.Error()
+		return s
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return drStep{Step: "cache dir", Severity: config.SeverityOK, Message: "writable (" + dir + ")"}
+}
+
+// drDaemonSteps checks that the daemon is running and answers on its IPC
+// socket. A stopped daemon is a warning: banners and prompts then show
+// stale or no data, but nothing is broken.
+func drDaemonSteps(dcfg daemon.Config) []drStep {
+	d, err := daemon.New(dcfg)
+	if err != nil {
+		return []drStep{{Step: "daemon", Severity: config.SeverityFail, Message: err.Error()}}
+	}
+	if !d.IsRunning() {
+		return []drStep{{Step: "daemon", Severity: config.SeverityWarn, Message: "not running",
+			Hint: "start it with -daemon, or -install it as a service"}}
+	}
+	steps := []drStep{{Step: "daemon", Severity: config.SeverityOK, Message: "running"}}
+
+	socket := drStep{Step: "daemon socket", Severity: config.SeverityOK, Message: "reachable (" + dcfg.SocketPath + ")"}
+	resp, err := daemon.NewIPCClient(dcfg.SocketPath).SendCommand("HEALTH")
+	if err == nil {
+		var health daemon.HealthStatus
+		err = json.Unmarshal([]byte(resp), &health)
+	}
+	if err != nil {
+		socket.Severity = config.SeverityFail
+		socket.Message = err.Error()
+		socket.Hint = "restart the daemon; a stale socket file is replaced on start"
+	}
+	return append(steps, socket)
+}
+
+// drBannerStep renders a Standard banner from the cache, as -banner does.
+func drBannerStep(cfg *config.Config, env drEnv) (step drStep) {
+	step = drStep{Step: "banner", Severity: config.SeverityOK}
+	defer func() {
+		if r := recover(); r != nil {
+			step = drStep{Step: "banner", Severity: config.SeverityFail, Message: fmt.Sprintf("render panic: %v", r)}
+		}
+	}()
+	data := buildBannerFromCache(cfg.General.CacheDir, bannerOptions(cfg), env.version, env.commit)
+	if banner.Render(data, banner.Standard) == "" {
+		step.Severity = config.SeverityFail
+		step.Message = "rendered nothing"
+		return step
+	}
+	// The status widget is always there; the rest need cached data.
+	n := 0
+	for _, w := range data.Widgets {
+		if w.ID != "status" {
+			n++
+		}
+	}
+	if n == 0 {
+		step.Severity = config.SeverityWarn
+		step.Message = "rendered, but there is no cached data to show"
+		step.Hint = "start the daemon, or run -collect-once"
+		return step
+	}
+	step.Message = fmt.Sprintf("rendered %d data widgets", n)
+	return step
+}
+
+// drStarshipStep renders every Starship module from the cache, as
+// -starship all does.
+func drStarshipStep(cfg *config.Config) drStep {
+	line := starship.Render(starship.Config{
+		Modules:   starship.AllModules,
+		CacheDir:  cfg.General.CacheDir,
+		CacheTTLs: bnCacheTTLs(cfg, time.Now()),
+		Money:     bnMoneyFormat(cfg),
+	})
+	if line == "" {
+		return drStep{Step: "starship", Severity: config.SeverityWarn, Message: "no segments to show",
+			Hint: "start the daemon, or run -collect-once"}
+	}
+	return drStep{Step: "starship", Severity: config.SeverityOK, Message: "rendered " + line}
+}
+
+// drHasFailures reports whether any step failed.
+func drHasFailures(steps []drStep) bool {
+	for _, s := range steps {
+		if s.Severity == config.SeverityFail {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDoctor prints the steps as an aligned PASS/WARN/FAIL table with a
+// remediation hint under each problem, or as a drReport when asJSON is set.
+func writeDoctor(w io.Writer, steps []drStep, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(drReport{OK: !drHasFailures(steps), ExitCode: drExitCode(steps), Steps: steps})
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, s := range steps {
+		label := string(s.Severity)
+		if s.Severity == config.SeverityOK {
+			label = "PASS"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", label, s.Step, s.Message)
+		if s.Hint != "" {
+			fmt.Fprintf(tw, "\t\t→ %s\n", s.Hint)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/daemon"
+)
+
+// drTestEnv is a drEnv whose daemon files live under dir, so no real
+// daemon is found, and whose probes never leave the process.
+func drTestEnv(dir string) drEnv {
+	return drEnv{
+		tailscale:    vcStubTailscale{err: errors.New("connection refused")},
+		contextNames: func(string) ([]string, error) { return nil, nil },
+		daemon: daemon.Config{
+			PIDFile:         filepath.Join(dir, "d.pid"),
+			HealthFile:      filepath.Join(dir, "health.json"),
+			SocketPath:      filepath.Join(dir, "d.sock"),
+			DataDir:         filepath.Join(dir, "data"),
+			BannerCacheFile: filepath.Join(dir, "banner.json"),
+		},
+		timeout: 10 * time.Second,
+	}
+}
+
+func drSeverities(steps []drStep) map[string]config.Severity {
+	got := make(map[string]config.Severity, len(steps))
+	for _, s := range steps {
+		got[s.Step] = s.Severity
+	}
+	return got
+}
+
+func TestDoctorChecks_Pipeline(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.General.CacheDir = filepath.Join(dir, "cache")
+	cfg.Collectors.SysMetrics.Enabled = false
+	cfg.Collectors.Tailscale.Enabled = false
+	cfg.Collectors.Claude.Enabled = false
+	cfg.Collectors.Waifu.Enabled = false
+	cfg.Collectors.Commands = []config.CommandCollectorConfig{
+		{Name: "good", Exec: "echo", Args: []string{`{"data": {"state": "ok"}}`}},
+		{Name: "bad", Exec: "false"},
+	}
+
+	steps := doctorChecks(context.Background(), cfg, "/etc/pp.toml", nil, drTestEnv(dir))
+	got := drSeverities(steps)
+	want := map[string]config.Severity{
+		"config":         config.SeverityOK,
+		"collector good": config.SeverityOK,
+		"collector bad":  config.SeverityFail,
+		"cache dir":      config.SeverityOK,
+		"daemon":         config.SeverityWarn,
+		"banner":         config.SeverityWarn,
+		"starship":       config.SeverityWarn,
+	}
+	for step, sev := range want {
+		if got[step] != sev {
+			t.Errorf("%s = %q, want %s", step, got[step], sev)
+		}
+	}
+	if _, ok := got["daemon socket"]; ok {
+		t.Error("socket should not be probed when the daemon is not running")
+	}
+	if !drHasFailures(steps) {
+		t.Error("a failing collector should fail the run")
+	}
+}
+
+func TestDoctorChecks_ConfigLoadError(t *testing.T) {
+	steps := doctorChecks(context.Background(), nil, "/etc/pp.toml", errors.New("line 3: bad key"), drTestEnv(t.TempDir()))
+	if len(steps) != 1 || steps[0].Step != "config" || steps[0].Severity != config.SeverityFail {
+		t.Fatalf("steps = %+v, want a single config FAIL", steps)
+	}
+	if steps[0].Hint == "" {
+		t.Error("a failed step should carry a remediation hint")
+	}
+}
+
+func TestWriteDoctor_Formats(t *testing.T) {
+	steps := []drStep{
+		{Step: "config", Severity: config.SeverityOK, Message: "valid"},
+		{Step: "daemon", Severity: config.SeverityWarn, Message: "not running", Hint: "start it with -daemon"},
+	}
+
+	var b bytes.Buffer
+	if err := writeDoctor(&b, steps, false); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{"PASS  config", "WARN  daemon", "→ start it with -daemon"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	b.Reset()
+	if err := writeDoctor(&b, steps, true); err != nil {
+		t.Fatal(err)
+	}
+	var report drReport
+	if err := json.Unmarshal(b.Bytes(), &report); err != nil {
+		t.Fatalf("JSON output: %v", err)
+	}
+	if !report.OK || len(report.Steps) != 2 {
+		t.Errorf("report = %+v, want ok with 2 steps", report)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/daemon"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/status"
+)
+
+// Exit codes of -health, -doctor and -status, so scripts can branch on the
+// outcome without parsing output. They are listed in the man page and the
+// package doc; keep all three in sync.
+const (
+	exitHealthy    = 0 // everything is fine
+	exitWarning    = 1 // something needs attention
+	exitCritical   = 2 // something is failing
+	exitNotRunning = 3 // the daemon is not running
+	exitConfig     = 4 // the config could not be loaded or is invalid
+	exitCache      = 5 // the cache or health file is missing or unusable
+)
+
+// exitCodeForLevel maps an overall status level to its exit code.
+func exitCodeForLevel(lvl status.Level) int {
+	switch lvl {
+	case status.LevelCritical:
+		return exitCritical
+	case status.LevelWarning:
+		return exitWarning
+	default:
+		return exitHealthy
+	}
+}
+
+// drPrecedence orders the -doctor exit codes from the one that wins to the
+// one that loses when steps disagree: a broken config or cache directory
+// explains the failures after it, and a stopped daemon explains missing
+// data.
+var drPrecedence = []int{exitConfig, exitCache, exitCritical, exitNotRunning, exitWarning}
+
+// drExitCode returns the exit code for a -doctor run: the step outcome
+// highest in drPrecedence, or exitHealthy when every step passed.
+func drExitCode(steps []drStep) int {
+	seen := make(map[int]bool)
+	for _, s := range steps {
+		switch {
+		case s.Severity == config.SeverityFail && (s.Step == "config" || strings.HasPrefix(s.Step, "config ")):
+			seen[exitConfig] = true
+		case s.Severity == config.SeverityFail && s.Step == "cache dir":
+			seen[exitCache] = true
+		case s.Severity == config.SeverityFail:
+			seen[exitCritical] = true
+		case s.Severity == config.SeverityWarn && s.Step == "daemon":
+			seen[exitNotRunning] = true
+		case s.Severity == config.SeverityWarn:
+			seen[exitWarning] = true
+		}
+	}
+	for _, c := range drPrecedence {
+		if seen[c] {
+			return c
+		}
+	}
+	return exitHealthy
+}
+
+// hcDaemon is the part of daemon.Daemon that -health queries, so tests can
+// substitute it.
+type hcDaemon interface {
+	IsRunning() bool
+	Health() (*daemon.HealthStatus, error)
+}
+
+// hcReport is the JSON document written by -health -json: the health file
+// with the exit code alongside.
+type hcReport struct {
+	*daemon.HealthStatus
+	ExitCode int `json:"exit_code"`
+}
+
+// runHealthCheck prints the daemon's health to w, or problems to errW, and
+// returns the exit code: exitNotRunning for a stopped daemon, exitCache for
+// an unreadable health file, exitWarning when a collector is unhealthy, and
+// exitHealthy otherwise. A health file written by -collect-once is reported
+// as that run's, with its age, since no daemon is expected then.
+func runHealthCheck(w, errW io.Writer, d hcDaemon, asJSON bool) int {
+	health, err := d.Health()
+	once := err == nil && health != nil && health.Mode == daemon.HealthModeCollectOnce
+	if !once && !d.IsRunning() {
+		if asJSON {
+			fmt.Fprintf(w, `{"status":"not_running","exit_code":%d}`+"\n", exitNotRunning)
+		} else {
+			fmt.Fprintln(errW, "daemon not running")
+		}
+		return exitNotRunning
+	}
+
+	if err != nil {
+		if asJSON {
+			msg, _ := json.Marshal(err.Error())
+			fmt.Fprintf(w, `{"status":"error","error":%s,"exit_code":%d}`+"\n", msg, exitCache)
+		} else {
+			fmt.Fprintf(errW, "health check failed: %v\n", err)
+		}
+		return exitCache
+	}
+
+	code := exitHealthy
+	for _, c := range health.Collectors {
+		if !c.Healthy {
+			code = exitWarning
+		}
+	}
+
+	if asJSON {
+		data, _ := json.MarshalIndent(hcReport{HealthStatus: health, ExitCode: code}, "", "  ")
+		fmt.Fprintln(w, string(data))
+		return code
+	}
+	if once {
+		fmt.Fprintf(w, "collected by -collect-once %s ago, no daemon running\n", bnFormatAge(time.Since(health.LastUpdate)))
+	} else {
+		fmt.Fprintf(w, "daemon healthy (PID %d, uptime %s)\n", health.PID, health.Uptime)
+	}
+	if health.Snoozed && health.SnoozedUntil != nil && time.Now().Before(*health.SnoozedUntil) {
+		fmt.Fprintf(w, "  alerts snoozed until %s\n", health.SnoozedUntil.Local().Format(time.RFC3339))
+	}
+	if len(health.Disabled) > 0 {
+		fmt.Fprintf(w, "  disabled: %s\n", strings.Join(health.Disabled, ", "))
+	}
+	for name, c := range health.Collectors {
+		state := "ok"
+		if !c.Healthy {
+			state = "unhealthy"
+		}
+		fmt.Fprintf(w, "  %s: %s (errors: %d)\n", name, state, c.ErrorCount)
+	}
+	return code
+}
+
+// stReport is the JSON document written by -status -json.
+type stReport struct {
+	status.Result
+	ExitCode int `json:"exit_code"`
+}
+
+// runStatus prints the overall status level of the cached reports with its
+// summary, or as JSON when asJSON is set, and returns the level's exit code.
+// With nothing cached to evaluate it returns exitCache.
+func runStatus(w io.Writer, r slReports, asJSON bool) int {
+	res := r.evaluator().Evaluate()
+	code := exitCodeForLevel(res.Level)
+	empty := r.claude == nil && r.billing == nil && r.tailscale == nil && r.k8s == nil && r.sys == nil
+	if empty {
+		code = exitCache
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(stReport{Result: res, ExitCode: code})
+		return code
+	}
+	if empty {
+		fmt.Fprintln(w, "No cached data to evaluate; start the daemon or run -collect-once.")
+		return code
+	}
+	if sum := res.Summary(); sum != "" {
+		fmt.Fprintf(w, "%s: %s\n", res.Level, sum)
+	} else {
+		fmt.Fprintln(w, res.Level)
+	}
+	return code
+}
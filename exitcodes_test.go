@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/daemon"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/status"
+)
+
+func TestDrExitCode(t *testing.T) {
+	ok := func(step string) drStep { return drStep{Step: step, Severity: config.SeverityOK} }
+	warn := func(step string) drStep { return drStep{Step: step, Severity: config.SeverityWarn} }
+	fail := func(step string) drStep { return drStep{Step: step, Severity: config.SeverityFail} }
+
+	tests := []struct {
+		name  string
+		steps []drStep
+		want  int
+	}{
+		{"all pass", []drStep{ok("config"), ok("cache dir"), ok("daemon")}, exitHealthy},
+		{"warning", []drStep{ok("config"), warn("collectors")}, exitWarning},
+		{"failed collector", []drStep{ok("config"), fail("collector claude"), warn("collectors")}, exitCritical},
+		{"daemon not running", []drStep{ok("config"), warn("banner"), warn("daemon")}, exitNotRunning},
+		{"failure beats stopped daemon", []drStep{warn("daemon"), fail("starship")}, exitCritical},
+		{"config load error", []drStep{fail("config")}, exitConfig},
+		{"invalid config item", []drStep{fail("config claude"), fail("collector claude")}, exitConfig},
+		{"cache dir", []drStep{ok("config"), fail("banner"), fail("cache dir")}, exitCache},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := drExitCode(tt.steps); got != tt.want {
+				t.Errorf("drExitCode = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeHealthDaemon is an hcDaemon with canned answers.
+type fakeHealthDaemon struct {
+	running bool
+	health  *daemon.HealthStatus
+	err     error
+}
+
+func (f fakeHealthDaemon) IsRunning() bool                       { return f.running }
+func (f fakeHealthDaemon) Health() (*daemon.HealthStatus, error) { return f.health, f.err }
+
+func TestRunHealthCheck_ExitCodes(t *testing.T) {
+	healthy := &daemon.HealthStatus{PID: 42, Collectors: map[string]daemon.CollectorHealth{
+		"claude": {Name: "claude", Healthy: true},
+	}}
+	degraded := &daemon.HealthStatus{PID: 42, Collectors: map[string]daemon.CollectorHealth{
+		"claude":  {Name: "claude", Healthy: true},
+		"billing": {Name: "billing", Healthy: false, ErrorCount: 3},
+	}}
+	collected := &daemon.HealthStatus{Mode: daemon.HealthModeCollectOnce, PID: 42, LastUpdate: time.Now(), Collectors: healthy.Collectors}
+	collectedDegraded := &daemon.HealthStatus{Mode: daemon.HealthModeCollectOnce, PID: 42, LastUpdate: time.Now(), Collectors: degraded.Collectors}
+
+	tests := []struct {
+		name string
+		d    fakeHealthDaemon
+		want int
+	}{
+		{"healthy", fakeHealthDaemon{running: true, health: healthy}, exitHealthy},
+		{"unhealthy collector", fakeHealthDaemon{running: true, health: degraded}, exitWarning},
+		{"not running", fakeHealthDaemon{}, exitNotRunning},
+		{"collect-once", fakeHealthDaemon{health: collected}, exitHealthy},
+		{"collect-once unhealthy collector", fakeHealthDaemon{health: collectedDegraded}, exitWarning},
+		{"unreadable health file", fakeHealthDaemon{running: true, err: errors.New(`bad "file"`)}, exitCache},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if got := runHealthCheck(&out, &errOut, tt.d, false); got != tt.want {
+				t.Errorf("exit code = %d, want %d", got, tt.want)
+			}
+
+			out.Reset()
+			if got := runHealthCheck(&out, &errOut, tt.d, true); got != tt.want {
+				t.Errorf("-json exit code = %d, want %d", got, tt.want)
+			}
+			var doc map[string]any
+			if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+				t.Fatalf("-json output is not JSON: %v\n%s", err, out.String())
+			}
+			if got, _ := doc["exit_code"].(float64); int(got) != tt.want {
+				t.Errorf("exit_code = %v, want %d", doc["exit_code"], tt.want)
+			}
+		})
+	}
+}
+
+func TestRunStatus_ExitCodes(t *testing.T) {
+	claudeAt := func(pct float64) *claude.UsageReport {
+		return &claude.UsageReport{Accounts: []claude.AccountUsage{
+			{Name: "personal", Connected: true, BudgetUSD: 100, Utilization: pct},
+		}}
+	}
+	tests := []struct {
+		name string
+		r    slReports
+		want int
+		text string
+	}{
+		{"healthy", slReports{billing: &billing.BillingReport{BudgetUSD: 100, BudgetPercent: 40}}, exitHealthy, "healthy"},
+		{"warning", slReports{claude: claudeAt(84)}, exitWarning, "warning: Claude personal at 84%"},
+		{"critical", slReports{claude: claudeAt(97)}, exitCritical, "critical: Claude personal at 97%"},
+		{"no data", slReports{}, exitCache, "No cached data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.r.rules = status.DefaultEvaluatorConfig()
+			var buf bytes.Buffer
+			if got := runStatus(&buf, tt.r, false); got != tt.want {
+				t.Errorf("exit code = %d, want %d", got, tt.want)
+			}
+			if !strings.HasPrefix(buf.String(), tt.text) {
+				t.Errorf("output = %q, want prefix %q", buf.String(), tt.text)
+			}
+
+			buf.Reset()
+			if got := runStatus(&buf, tt.r, true); got != tt.want {
+				t.Errorf("-json exit code = %d, want %d", got, tt.want)
+			}
+			var doc stReport
+			if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+				t.Fatalf("-json output is not JSON: %v\n%s", err, buf.String())
+			}
+			if doc.ExitCode != tt.want {
+				t.Errorf("exit_code = %d, want %d", doc.ExitCode, tt.want)
+			}
+		})
+	}
+}
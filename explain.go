@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/status"
+)
+
+// exLabel is the label printed for a finding at lvl: OK, WARNING or
+// CRITICAL.
+func exLabel(lvl status.Level) string {
+	if lvl == status.LevelHealthy {
+		return "OK"
+	}
+	return strings.ToUpper(lvl.String())
+}
+
+// writeExplain prints the overall level followed by every finding behind
+// it, most severe first, as an aligned table, or res as JSON when asJSON is
+// set. res comes from status.Evaluator Explain, so healthy findings are
+// listed too.
+func writeExplain(w io.Writer, res status.Result, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	if len(res.Reasons) == 0 {
+		_, err := fmt.Fprintln(w, "No cached data to evaluate; start the daemon or run -collect-once.")
+		return err
+	}
+	fmt.Fprintf(w, "Status: %s\n", exLabel(res.Level))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range res.Reasons {
+		fmt.Fprintf(tw, "%s:\t%s\t%s\n", exLabel(r.Level), r.Subsystem, r.Message)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/status"
+)
+
+func TestWriteExplain(t *testing.T) {
+	r := slReports{
+		claude: &claude.UsageReport{Accounts: []claude.AccountUsage{
+			{Name: "personal", Connected: true, BudgetUSD: 100, Utilization: 84},
+		}},
+		billing: &billing.BillingReport{TotalMonthlyUSD: 62, BudgetUSD: 100, BudgetPercent: 62},
+	}
+	var buf bytes.Buffer
+	if err := writeExplain(&buf, r.evaluator().Explain(), false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 || lines[0] != "Status: WARNING" {
+		t.Fatalf("output =\n%s", buf.String())
+	}
+	for i, want := range [][]string{
+		{"WARNING:", "claude", "Claude personal at 84%"},
+		{"OK:", "billing", "cloud spend at 62% of budget"},
+	} {
+		line := lines[i+1]
+		if !strings.HasPrefix(line, want[0]) || !strings.Contains(line, want[1]) || !strings.HasSuffix(line, want[2]) {
+			t.Errorf("line %d = %q, want %q", i+1, line, strings.Join(want, " "))
+		}
+	}
+
+	buf.Reset()
+	if err := writeExplain(&buf, r.evaluator().Explain(), true); err != nil {
+		t.Fatal(err)
+	}
+	var res status.Result
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("JSON output: %v\n%s", err, buf.String())
+	}
+	if res.Level != status.LevelWarning || len(res.Reasons) != 2 || res.Reasons[0].Subsystem != "claude" {
+		t.Errorf("JSON result = %+v", res)
+	}
+
+	buf.Reset()
+	if err := writeExplain(&buf, slReports{}.evaluator().Explain(), false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "No cached data") {
+		t.Errorf("empty output = %q", buf.String())
+	}
+}
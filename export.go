@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+)
+
+// Freshness labels attached to each exported section.
+const (
+	exFresh = "fresh"
+	exStale = "stale"
+)
+
+// exSnapshot is the combined document emitted by -export.
+type exSnapshot struct {
+	Timestamp  time.Time            `json:"timestamp"`
+	CacheDir   string               `json:"cache_dir"`
+	Collectors map[string]exSection `json:"collectors"`
+}
+
+// exSection holds one cache key's data together with its freshness.
+type exSection struct {
+	Freshness string      `json:"freshness"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	AgeSecs   int64       `json:"age_seconds"`
+	Error     string      `json:"error,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// exDecoders maps known cache keys to constructors for their typed payload,
+// so exported documents use the same field names as the collectors.
+var exDecoders = map[string]func() interface{}{
+	"sysmetrics":           func() interface{} { return new(sysmetrics.Metrics) },
+	"tailscale":            func() interface{} { return new(tailscale.Status) },
+	"k8s":                  func() interface{} { return new(k8s.ClusterStatus) },
+	"claude":               func() interface{} { return new(claude.UsageReport) },
+	claude.HistoryCacheKey: func() interface{} { return new(claude.History) },
+	"billing":              func() interface{} { return new(billing.BillingReport) },
+}
+
+// buildExport reads every <key>.json file in cacheDir and returns a snapshot
+// with one section per key. Sections older than their TTL in ttls (or
+// bnMaxCacheAge) are marked stale but still included. A missing cache
+// directory yields an empty snapshot.
+func buildExport(cacheDir string, ttls map[string]time.Duration, now time.Time) (*exSnapshot, error) {
+	snap := &exSnapshot{
+		Timestamp:  now,
+		CacheDir:   cacheDir,
+		Collectors: make(map[string]exSection),
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snap, nil
+		}
+		return nil, err
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		key := strings.TrimSuffix(name, ".json")
+		path := filepath.Join(cacheDir, name)
+
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		age := now.Sub(info.ModTime())
+		sec := exSection{
+			Freshness: exFresh,
+			UpdatedAt: info.ModTime(),
+			AgeSecs:   int64(age / time.Second),
+		}
+		if age > bnCacheTTL(ttls, key) {
+			sec.Freshness = exStale
+		}
+
+		sec.Data, err = exDecode(key, path)
+		if err != nil {
+			sec.Error = err.Error()
+		}
+		snap.Collectors[key] = sec
+	}
+	return snap, nil
+}
+
+// exDecode reads path into the typed struct registered for key, falling back
+// to raw JSON for keys without a known type.
+func exDecode(key, path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	newFn, ok := exDecoders[key]
+	if !ok {
+		var raw json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+	v := newFn()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// writeExport encodes snap to w in the requested format ("json" or "yaml").
+func writeExport(w io.Writer, snap *exSnapshot, format string) error {
+	var out []byte
+	var err error
+	switch format {
+	case "json":
+		out, err = json.MarshalIndent(snap, "", "  ")
+		out = append(out, '\n')
+	case "yaml", "yml":
+		out, err = yaml.Marshal(snap)
+	default:
+		return fmt.Errorf("unknown export format: %s (supported: json, yaml)", format)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
+)
+
+func TestBuildExport_MarksFreshness(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "sysmetrics", sysmetrics.Metrics{CPU: sysmetrics.CPUMetrics{Total: 12}})
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{TotalCostUSD: 9.5})
+	if err := os.WriteFile(filepath.Join(dir, "custom.json"), []byte(`{"x":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "banner-abc.cache"), []byte("ignored"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "claude.json"), stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := buildExport(dir, nil, time.Now())
+	if err != nil {
+		t.Fatalf("buildExport() error: %v", err)
+	}
+	if len(snap.Collectors) != 3 {
+		t.Fatalf("expected 3 sections, got %d: %v", len(snap.Collectors), snap.Collectors)
+	}
+	if got := snap.Collectors["sysmetrics"].Freshness; got != exFresh {
+		t.Errorf("sysmetrics freshness = %q, want %q", got, exFresh)
+	}
+	if got := snap.Collectors["claude"].Freshness; got != exStale {
+		t.Errorf("claude freshness = %q, want %q", got, exStale)
+	}
+	if _, ok := snap.Collectors["claude"].Data.(*claude.UsageReport); !ok {
+		t.Errorf("claude section should decode to *claude.UsageReport, got %T", snap.Collectors["claude"].Data)
+	}
+}
+
+func TestBuildExport_PerCollectorTTL(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "tailscale", map[string]int{"online_peers": 3})
+	bnWriteFixture(t, dir, "billing", map[string]float64{"total_monthly_usd": 12})
+	tenMinAgo := time.Now().Add(-10 * time.Minute)
+	for _, key := range []string{"tailscale", "billing"} {
+		if err := os.Chtimes(filepath.Join(dir, key+".json"), tenMinAgo, tenMinAgo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ttls := map[string]time.Duration{"tailscale": 30 * time.Second, "billing": time.Hour}
+	snap, err := buildExport(dir, ttls, time.Now())
+	if err != nil {
+		t.Fatalf("buildExport() error: %v", err)
+	}
+	if got := snap.Collectors["tailscale"].Freshness; got != exStale {
+		t.Errorf("tailscale freshness = %q, want %q under a 30s TTL", got, exStale)
+	}
+	if got := snap.Collectors["billing"].Freshness; got != exFresh {
+		t.Errorf("billing freshness = %q, want %q under a 1h TTL", got, exFresh)
+	}
+}
+
+func TestBuildExport_MissingDir(t *testing.T) {
+	snap, err := buildExport(filepath.Join(t.TempDir(), "nope"), nil, time.Now())
+	if err != nil {
+		t.Fatalf("buildExport() error: %v", err)
+	}
+	if len(snap.Collectors) != 0 {
+		t.Errorf("expected empty snapshot, got %d sections", len(snap.Collectors))
+	}
+}
+
+func TestBuildExport_CorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "billing.json"), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := buildExport(dir, nil, time.Now())
+	if err != nil {
+		t.Fatalf("buildExport() error: %v", err)
+	}
+	if snap.Collectors["billing"].Error == "" {
+		t.Error("corrupt section should carry an error")
+	}
+}
+
+func TestWriteExport_Formats(t *testing.T) {
+	dir := t.TempDir()
+	bnWriteFixture(t, dir, "claude", claude.UsageReport{TotalCostUSD: 9.5})
+	snap, err := buildExport(dir, nil, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var jb bytes.Buffer
+	if err := writeExport(&jb, snap, "json"); err != nil {
+		t.Fatalf("writeExport(json) error: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(jb.Bytes(), &decoded); err != nil {
+		t.Fatalf("json output does not parse: %v", err)
+	}
+	if _, ok := decoded["timestamp"]; !ok {
+		t.Error("json output missing timestamp")
+	}
+
+	var yb bytes.Buffer
+	if err := writeExport(&yb, snap, "yaml"); err != nil {
+		t.Fatalf("writeExport(yaml) error: %v", err)
+	}
+	if !strings.Contains(yb.String(), "total_cost_usd: 9.5") {
+		t.Errorf("yaml output missing claude data:\n%s", yb.String())
+	}
+
+	if err := writeExport(&yb, snap, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
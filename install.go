@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/service"
+)
+
+// svConfig resolves the daemon invocation for -install: the running binary,
+// the config file named by -config, and the configured cache dir, all as
+// absolute paths. Without -config the unit names no file, so the daemon
+// loads the layered config (the system file and the user's) like any
+// other invocation.
+func svConfig(cfg *config.Config, configFlag, exe string) (service.Config, error) {
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	sc := service.Config{BinaryPath: exe, CacheDir: cfg.General.CacheDir}
+
+	if configFlag != "" {
+		abs, err := filepath.Abs(configFlag)
+		if err != nil {
+			return sc, fmt.Errorf("resolve config path: %w", err)
+		}
+		sc.ConfigPath = abs
+	}
+	if sc.CacheDir != "" {
+		abs, err := filepath.Abs(sc.CacheDir)
+		if err != nil {
+			return sc, fmt.Errorf("resolve cache dir: %w", err)
+		}
+		sc.CacheDir = abs
+	}
+	return sc, nil
+}
+
+// runServiceCommand handles -install and -uninstall. With dryRun the unit
+// is printed to w and nothing is written. Units are written but not
+// activated; the command that starts them is printed instead.
+func runServiceCommand(w io.Writer, cfg *config.Config, configFlag string, uninstall, dryRun, force bool) error {
+	kind, err := service.Detect(runtime.GOOS)
+	if err != nil {
+		return err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("locate home directory: %w", err)
+	}
+
+	if uninstall {
+		path := service.UnitPath(kind, home)
+		if dryRun {
+			fmt.Fprintf(w, "would remove %s\n", path)
+			return nil
+		}
+		if _, err := service.Uninstall(kind, home); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("no %s unit installed at %s", kind, path)
+			}
+			return err
+		}
+		fmt.Fprintf(w, "removed %s\nif the daemon is still running, stop it with: %s\n", path, service.DeactivateHint(kind, path))
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locate prompt-pulse binary: %w", err)
+	}
+	sc, err := svConfig(cfg, configFlag, exe)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		unit, err := service.Render(kind, sc)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, unit)
+		return err
+	}
+	path, err := service.Install(kind, sc, home, force)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "wrote %s\nstart it with: %s\n", path, service.ActivateHint(kind, path))
+	return nil
+}
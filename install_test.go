@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+func TestSvConfig(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "prompt-pulse")
+	if err := os.WriteFile(exe, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "pp")
+	if err := os.Symlink(exe, link); err != nil {
+		t.Fatal(err)
+	}
+	cfgFile := filepath.Join(dir, "config.toml")
+
+	cfg := config.DefaultConfig()
+	cfg.General.CacheDir = filepath.Join(dir, "cache")
+	sc, err := svConfig(cfg, cfgFile, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantExe, _ := filepath.EvalSymlinks(exe)
+	if sc.BinaryPath != wantExe {
+		t.Errorf("BinaryPath = %s, want symlink resolved to %s", sc.BinaryPath, wantExe)
+	}
+	if sc.ConfigPath != cfgFile || sc.CacheDir != cfg.General.CacheDir {
+		t.Errorf("config = %+v", sc)
+	}
+}
+
+func TestSvConfig_NoConfigFlag(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv("HOME", t.TempDir())
+	// A user config exists, but naming it would make the daemon skip the
+	// system layer, so it stays out of the unit.
+	user := filepath.Join(xdg, "prompt-pulse", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(user), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(user, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sc, err := svConfig(config.DefaultConfig(), "", "/usr/bin/prompt-pulse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.ConfigPath != "" {
+		t.Errorf("ConfigPath = %q, want empty so the daemon loads the layered config", sc.ConfigPath)
+	}
+}
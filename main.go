@@ -12,37 +12,75 @@
 // Flags:
 //
 //	-banner           Display system status banner
+//	-live             With -banner, collect missing or stale data inline instead of waiting for the daemon
+//	-banner-watch     Redraw the banner in place on an interval (see -watch-interval)
+//	-bench-banner     Time banner renders per preset against the 5ms Standard target
 //	-daemon           Run background daemon
-//	-starship string  Output one-line Starship segment (claude|billing|infra|all)
-//	-shell string     Output shell integration script (bash|zsh|fish|ksh)
+//	-collect-once     Run every collector once, write the cache, and exit (for cron)
+//	-starship string  Output Starship segments, e.g. "all" or an ordered list "billing,infra,claude"
+//	-starship-sep     Separator placed between -starship segments
+//	-statusline       Single compact status line for editors and tmux (see -no-color, -max-width)
+//	-explain          List the findings behind the overall status level, most severe first
+//	-status           Print the overall status level and exit with its code (see Exit codes)
+//	-shell string     Output shell integration script (bash|zsh|fish|ksh|powershell)
+//	-completion sh    Output a tab-completion script for the CLI flags (bash|zsh|fish)
 //	-config string    Path to configuration file (default: ~/.config/prompt-pulse/config.toml)
 //	-theme string     Theme override (default|gruvbox|nord|catppuccin|dracula|tokyo-night)
+//	-theme-preview    Render a sample banner in every theme for comparison
 //	-health           Check daemon health status
-//	-diagnose         Claude diagnostics
+//	-tail-logs        Stream the daemon log until interrupted (see -n, -level)
+//	-export string    Dump all cached collector data (json|yaml)
+//	-schema           Print JSON Schemas for the cached collector data and -export
+//	-cost-report      Month-over-month cloud spend per provider from cached billing data
+//	-diff old new     Summarize meaningful changes between two -export snapshots
+//	-validate-config  Check configuration and credentials (OK/WARN/FAIL report)
+//	-profile          Time one Collect per enabled collector, slowest first
+//	-diagnose         Print diagnostics (secrets redacted)
+//	-doctor           Check config, collectors, cache, daemon and rendering end to end (PASS/WARN/FAIL)
 //	-migrate          Run v1-to-v2 config migration
 //	-man              Print man page to stdout in roff format
-//	-verbose          Enable verbose logging
+//	-verbose          Enable debug logging (timings, cache hits and misses)
+//	-log-format fmt   Log output format: text or json (default: text)
 //	-version          Print version and exit
+//
+// Exit codes:
+//
+// -health, -doctor and -status exit with a code scripts can branch on, and
+// their -json output carries the same value as exit_code:
+//
+//	0  healthy
+//	1  warning: something needs attention
+//	2  critical: something is failing
+//	3  daemon not running
+//	4  config error
+//	5  cache error: no cached data, or an unreadable health file or cache dir
 package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/banner"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claudepersonal"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/daemon"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/docs"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/logging"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/migrate"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/perf"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/shell"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/starship"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/terminal"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
 )
 
@@ -50,20 +88,51 @@ func main() {
 	var (
 		configPath     = flag.String("config", "", "Path to configuration file (default: ~/.config/prompt-pulse/config.toml)")
 		runDaemon      = flag.Bool("daemon", false, "Run background daemon")
+		collectOnce    = flag.Bool("collect-once", false, "Run every enabled collector once, write the cache and health file, and exit; skips if the daemon is running")
 		runBanner      = flag.Bool("banner", false, "Display system status banner")
-		starshipMod    = flag.String("starship", "", "Output one-line Starship segment (claude|billing|infra|all)")
-		shellType      = flag.String("shell", "", "Output shell integration script (bash|zsh|fish|ksh)")
+		bannerLive     = flag.Bool("live", false, "With -banner, run the collectors for missing or stale cache entries inline (bounded by shell.banner_timeout) before rendering")
+		benchBanner    = flag.Bool("bench-banner", false, "Time banner rendering at every layout preset, colored and plain, from the current cache and config")
+		bannerWatch    = flag.Bool("banner-watch", false, "Redraw the cached banner in place until Ctrl-C")
+		watchInterval  = flag.Duration("watch-interval", bwDefaultInterval, "Redraw interval for -banner-watch")
+		watchConfig    = flag.Bool("watch-config", false, "With -banner-watch, reload the theme and display settings when the config file changes")
+		starshipMod    = flag.String("starship", "", "Output Starship segments: claude|billing|infra|k8s|system|all, or an ordered list like billing,infra")
+		starshipSep    = flag.String("starship-sep", "", "Separator between -starship segments (default: dim │)")
+		runStatusline  = flag.Bool("statusline", false, "Output a single compact status line for editor/tmux statuslines")
+		noColor        = flag.Bool("no-color", false, "Disable ANSI colors in -statusline output")
+		maxWidth       = flag.Int("max-width", 0, "Maximum width of -statusline output (0 = unlimited)")
+		runExplain     = flag.Bool("explain", false, "List every finding behind the overall status level from cached data, most severe first")
+		runStatusCmd   = flag.Bool("status", false, "Print the overall status level from cached data; exit 0 healthy, 1 warning, 2 critical, 5 no data")
+		shellType      = flag.String("shell", "", "Output shell integration script (bash|zsh|fish|ksh|powershell)")
+		completion     = flag.String("completion", "", "Output a tab-completion script for prompt-pulse's own flags (bash|zsh|fish)")
 		themeFlag      = flag.String("theme", "", "Theme override")
+		themePreview   = flag.Bool("theme-preview", false, "Render a sample banner in every theme (width from -term-width)")
 		runHealth      = flag.Bool("health", false, "Check daemon health status")
-		healthJSON     = flag.Bool("json", false, "Output health check as JSON (with -health)")
-		runDiagnose    = flag.Bool("diagnose", false, "Claude diagnostics")
+		tailLogs       = flag.Bool("tail-logs", false, "Stream the daemon log like tail -f until Ctrl-C, following rotation")
+		tailLines      = flag.Int("n", tlDefaultLines, "With -tail-logs, the number of existing lines to print first")
+		tailLevel      = flag.String("level", "", "With -tail-logs, only show records at or above this level: debug|info|warn|error")
+		healthJSON     = flag.Bool("json", false, "Output as JSON (with -health, -status, -validate-config, -doctor, -diff, -profile, -cost-report, or -explain)")
+		validateCfg    = flag.Bool("validate-config", false, "Check configuration and credentials, exit non-zero on failure")
+		runProfile     = flag.Bool("profile", false, "Run each enabled collector once and print timings (does not touch the cache)")
+		profileTimeout = flag.Duration("profile-timeout", pfDefaultTimeout, "Overall time limit for -profile and the -doctor collector runs")
+		exportFormat   = flag.String("export", "", "Dump all cached collector data (json|yaml)")
+		showSchema     = flag.Bool("schema", false, "Print JSON Schemas for the cached collector data and the -export document")
+		costReport     = flag.Bool("cost-report", false, "Print month-over-month cloud spend per provider from cached billing data")
+		costSince      = flag.String("since", "", "With -cost-report, also sum daily spend from this date (2006-01-02), days (7d) or duration (36h) ago")
+		runDiff        = flag.Bool("diff", false, "Compare two -export snapshots: -diff old.json new.json")
+		runDiagnose    = flag.Bool("diagnose", false, "Print diagnostics with secrets redacted")
+		runDoctor      = flag.Bool("doctor", false, "Check the setup end to end with remediation hints, exit non-zero on failure")
 		runMigrate     = flag.Bool("migrate", false, "Run v1-to-v2 config migration")
+		runInstall     = flag.Bool("install", false, "Install a systemd user unit (Linux) or launchd agent (macOS) for the daemon")
+		runUninstall   = flag.Bool("uninstall", false, "Remove the unit written by -install")
+		dryRun         = flag.Bool("dry-run", false, "With -install, print the unit instead of writing it")
+		forceInstall   = flag.Bool("force", false, "With -install, replace an existing unit")
 		showMan        = flag.Bool("man", false, "Print man page to stdout in roff format")
 		manDir         = flag.String("man-dir", "", "Write all man pages to directory (e.g., /usr/share/man)")
-		verbose        = flag.Bool("verbose", false, "Enable verbose logging")
+		verbose        = flag.Bool("verbose", false, "Enable debug logging (timings, cache hits and misses)")
+		logFormat      = flag.String("log-format", "text", "Log output format: text or json")
 		showVersion    = flag.Bool("version", false, "Print version and exit")
-		termWidth      = flag.Int("term-width", 0, "Terminal width override (0 = auto-detect)")
-		termHeight     = flag.Int("term-height", 0, "Terminal height override (0 = auto-detect)")
+		termWidth      = flag.Int("term-width", 0, "Terminal width override (0 = $COLUMNS, then the terminal, then 80)")
+		termHeight     = flag.Int("term-height", 0, "Terminal height override (0 = $LINES, then the terminal, then 24)")
 		waifuMode      = flag.Bool("waifu", false, "Enable waifu image in banner")
 		sessionID      = flag.String("session-id", "", "Session ID for per-session waifu caching")
 		claudeMsg      = flag.Bool("claude-msg", false, "Record a Claude personal plan message timestamp")
@@ -109,36 +178,28 @@ func main() {
 		os.Exit(0)
 	}
 
-	if *runDiagnose {
-		fmt.Println("prompt-pulse v2 diagnostics")
-		fmt.Println("===========================")
-		fmt.Println()
-		fmt.Println("Theme registry:")
-		for _, name := range theme.Names() {
-			marker := "  "
-			if name == theme.Current.Name {
-				marker = "* "
-			}
-			fmt.Printf("  %s%s\n", marker, name)
+	if *showSchema {
+		if err := writeSchemas(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "prompt-pulse: %v\n", err)
+			os.Exit(1)
 		}
-		fmt.Println()
-		fmt.Println("Config search paths:")
-		home, _ := os.UserHomeDir()
-		fmt.Printf("  %s\n", filepath.Join(home, ".config", "prompt-pulse", "config.toml"))
-		fmt.Println()
-		fmt.Println("Daemon status:")
-		dcfg := daemon.DefaultConfig()
-		d, err := daemon.New(dcfg)
+		os.Exit(0)
+	}
+
+	if *completion != "" {
+		script, err := completionScript(*completion, flag.CommandLine)
 		if err != nil {
-			fmt.Printf("  daemon init error: %v\n", err)
-		} else if d.IsRunning() {
-			fmt.Println("  running")
-			if health, err := d.Health(); err == nil {
-				data, _ := json.MarshalIndent(health, "  ", "  ")
-				fmt.Println("  " + string(data))
-			}
-		} else {
-			fmt.Println("  not running")
+			fmt.Fprintf(os.Stderr, "prompt-pulse: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(script)
+		os.Exit(0)
+	}
+
+	if *themePreview {
+		if err := writeThemePreview(os.Stdout, *termWidth); err != nil {
+			fmt.Fprintf(os.Stderr, "prompt-pulse: %v\n", err)
+			os.Exit(1)
 		}
 		os.Exit(0)
 	}
@@ -160,14 +221,27 @@ func main() {
 			st = shell.Fish
 		case "ksh":
 			st = shell.Ksh
+		case "powershell", "pwsh":
+			st = shell.PowerShell
 		default:
-			fmt.Fprintf(os.Stderr, "unknown shell: %s (supported: bash, zsh, fish, ksh)\n", *shellType)
+			fmt.Fprintf(os.Stderr, "unknown shell: %s (supported: bash, zsh, fish, ksh, powershell)\n", *shellType)
 			os.Exit(1)
 		}
 		opts := shell.Options{
 			ShowBanner:      *showBanner,
 			DaemonAutoStart: *daemonAutoStart,
 		}
+		// The integration script must be generated even without a usable
+		// config, so a load error just keeps the banner in every session.
+		shellCfg, err := config.LoadFrom(*configPath)
+		if err == nil {
+			on, perr := shell.ParseBannerSession(shellCfg.Shell.ShowBannerOn)
+			if perr != nil {
+				fmt.Fprintf(os.Stderr, "prompt-pulse: shell.show_banner_on: %v\n", perr)
+				on = shell.BannerAlways
+			}
+			opts.ShowBannerOn = on
+		}
 		fmt.Print(shell.Generate(st, opts))
 		os.Exit(0)
 	}
@@ -228,21 +302,96 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *runDiff {
+		if flag.NArg() != 2 {
+			fmt.Fprintln(os.Stderr, "usage: prompt-pulse -diff [-json] <old> <new>")
+			os.Exit(2)
+		}
+		oldSnap, err := dfLoadSnapshot(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+			os.Exit(1)
+		}
+		newSnap, err := dfLoadSnapshot(flag.Arg(1))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+			os.Exit(1)
+		}
+		if err := writeDiff(os.Stdout, diffSnapshots(oldSnap, newSnap), *healthJSON); err != nil {
+			fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// ---------------------------------------------------------------
+	// Logging: redacted until the config says otherwise
+	// ---------------------------------------------------------------
+
+	if err := logging.Setup(config.RedactWriter(os.Stderr), *verbose, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "prompt-pulse: -log-format: %v\n", err)
+		os.Exit(2)
+	}
+
 	// ---------------------------------------------------------------
 	// Load configuration (required for remaining modes)
 	// ---------------------------------------------------------------
 
-	var cfg *config.Config
-	var cfgErr error
+	cfg, cfgErr := config.LoadFrom(*configPath)
+	if *runDiagnose {
+		cfgPath := *configPath
+		if cfgPath == "" {
+			cfgPath = config.FindFile()
+		}
+		if *themeFlag != "" {
+			theme.SetCurrent(*themeFlag)
+		} else if cfg != nil && cfg.Theme.Name != "" {
+			theme.SetCurrent(cfg.Theme.Name)
+		}
+		if err := writeDiagnostics(os.Stdout, cfg, cfgPath, cfgErr, dgLocalDaemonHealth); err != nil {
+			fmt.Fprintf(os.Stderr, "prompt-pulse: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
-	if *configPath != "" {
-		cfg, cfgErr = config.LoadFromFile(*configPath)
-	} else {
-		cfg, cfgErr = config.Load()
+	if *runDoctor {
+		if cfg != nil {
+			config.ResolveSecretCommands(cfg)
+		}
+		cfgPath := *configPath
+		if cfgPath == "" {
+			cfgPath = config.FindFile()
+		}
+		if *themeFlag != "" {
+			theme.SetCurrent(*themeFlag)
+		} else if cfg != nil && cfg.Theme.Name != "" {
+			theme.SetCurrent(cfg.Theme.Name)
+		}
+		steps := doctorChecks(context.Background(), cfg, cfgPath, cfgErr, drEnv{
+			tailscale:    tailscale.NewLocalClient(""),
+			contextNames: vcContextNames,
+			daemon:       daemon.DefaultConfig(),
+			timeout:      *profileTimeout,
+			version:      version,
+			commit:       commit,
+		})
+		if err := writeDoctor(os.Stdout, steps, *healthJSON); err != nil {
+			fmt.Fprintf(os.Stderr, "doctor: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(drExitCode(steps))
 	}
+
 	if cfgErr != nil {
+		if *validateCfg {
+			_ = writeValidation(os.Stdout, []config.Diagnostic{{
+				Item: "config", Severity: config.SeverityFail, Message: cfgErr.Error(),
+			}}, *healthJSON)
+			os.Exit(exitConfig)
+		}
 		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", cfgErr)
-		os.Exit(1)
+		os.Exit(exitConfig)
 	}
 
 	// Apply theme override from CLI flag.
@@ -252,7 +401,12 @@ func main() {
 		theme.SetCurrent(cfg.Theme.Name)
 	}
 
-	_ = *verbose   // reserved for future structured logging
+	// Credentials in daemon and collector logs stay masked unless
+	// general.redact_secrets is turned off.
+	if !cfg.General.RedactSecrets {
+		_ = logging.Setup(os.Stderr, *verbose, *logFormat)
+	}
+
 	_ = *sessionID // reserved for per-session waifu caching
 
 	// Apply CLI waifu override to config.
@@ -261,53 +415,116 @@ func main() {
 	}
 
 	// ---------------------------------------------------------------
-	// Health check
+	// Service install/uninstall
 	// ---------------------------------------------------------------
 
-	if *runHealth {
-		dcfg := daemon.DefaultConfig()
-		d, err := daemon.New(dcfg)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "daemon init error: %v\n", err)
+	if *runInstall || *runUninstall {
+		if err := runServiceCommand(os.Stdout, cfg, *configPath, *runUninstall, *dryRun, *forceInstall); err != nil {
+			fmt.Fprintf(os.Stderr, "prompt-pulse: %v\n", err)
 			os.Exit(1)
 		}
+		os.Exit(0)
+	}
 
-		if !d.IsRunning() {
-			if *healthJSON {
-				fmt.Println(`{"status":"not_running"}`)
-			} else {
-				fmt.Fprintln(os.Stderr, "daemon not running")
-			}
+	// ---------------------------------------------------------------
+	// Config validation
+	// ---------------------------------------------------------------
+
+	if *validateCfg {
+		diags := validateConfig(cfg, tailscale.NewLocalClient(""), vcContextNames)
+		if err := writeValidation(os.Stdout, diags, *healthJSON); err != nil {
+			fmt.Fprintf(os.Stderr, "validate-config: %v\n", err)
+			os.Exit(1)
+		}
+		if config.HasFailures(diags) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// ---------------------------------------------------------------
+	// Collector profiling (live collection, never writes the cache)
+	// ---------------------------------------------------------------
+
+	if *runProfile {
+		config.ResolveSecretCommands(cfg)
+		reg, cleanup, err := profileRegistry(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "profile: %v\n", err)
+			os.Exit(1)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), *profileTimeout)
+		results := profileCollectors(ctx, reg)
+		cancel()
+		cleanup()
+		if err := writeProfile(os.Stdout, results, *profileTimeout, *healthJSON); err != nil {
+			fmt.Fprintf(os.Stderr, "profile: %v\n", err)
 			os.Exit(1)
 		}
+		os.Exit(0)
+	}
 
-		health, err := d.Health()
+	// ---------------------------------------------------------------
+	// Cache export (read-only, works without the daemon)
+	// ---------------------------------------------------------------
+
+	if *exportFormat != "" {
+		snap, err := buildExport(cfg.General.CacheDir, bnCacheTTLs(cfg, time.Now()), time.Now())
 		if err != nil {
-			if *healthJSON {
-				fmt.Printf(`{"status":"error","error":"%s"}`, err.Error())
-				fmt.Println()
-			} else {
-				fmt.Fprintf(os.Stderr, "health check failed: %v\n", err)
-			}
+			fmt.Fprintf(os.Stderr, "export failed: %v\n", err)
+			os.Exit(1)
+		}
+		if err := writeExport(os.Stdout, snap, *exportFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "export failed: %v\n", err)
 			os.Exit(1)
 		}
+		os.Exit(0)
+	}
 
-		if *healthJSON {
-			data, _ := json.MarshalIndent(health, "", "  ")
-			fmt.Println(string(data))
-		} else {
-			fmt.Printf("daemon healthy (PID %d, uptime %s)\n", health.PID, health.Uptime)
-			for name, c := range health.Collectors {
-				status := "ok"
-				if !c.Healthy {
-					status = "unhealthy"
+	if *costReport {
+		b, err := bnReadCache[billing.BillingReport](cfg.General.CacheDir, "billing", bnCacheTTL(bnCacheTTLs(cfg, time.Now()), "billing"))
+		if err != nil || b == nil {
+			fmt.Fprintln(os.Stderr, "cost-report: no fresh cached billing data (is the daemon running with billing enabled?)")
+			os.Exit(1)
+		}
+		rep := buildCostReport(b)
+		if *costSince != "" {
+			since, err := crParseSince(*costSince, time.Now())
+			if err == nil {
+				var h *billing.SpendHistory
+				if h, err = billing.LoadSpendHistory(billing.SpendHistoryPath(cfg.General.CacheDir)); err == nil {
+					rep.Window, err = crSpendWindow(h.Days, since)
 				}
-				fmt.Printf("  %s: %s (errors: %d)\n", name, status, c.ErrorCount)
 			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "cost-report: %v\n", err)
+				os.Exit(1)
+			}
+			if rep.Window.Clamped {
+				fmt.Fprintf(os.Stderr, "cost-report: warning: -since %s reaches past the recorded spend; showing from %s\n",
+					*costSince, rep.Window.Since)
+			}
+		}
+		if err := writeCostReport(os.Stdout, rep, *healthJSON, crColorOutput()); err != nil {
+			fmt.Fprintf(os.Stderr, "cost-report: %v\n", err)
+			os.Exit(1)
 		}
 		os.Exit(0)
 	}
 
+	// ---------------------------------------------------------------
+	// Health check
+	// ---------------------------------------------------------------
+
+	if *runHealth {
+		d, err := daemon.New(daemon.DefaultConfig())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "daemon init error: %v\n", err)
+			os.Exit(exitConfig)
+		}
+		os.Exit(runHealthCheck(os.Stdout, os.Stderr, d, *healthJSON))
+	}
+
 	// ---------------------------------------------------------------
 	// Context with signal handling
 	// ---------------------------------------------------------------
@@ -322,35 +539,69 @@ func main() {
 		cancel()
 	}()
 
+	// ---------------------------------------------------------------
+	// Statusline mode
+	// ---------------------------------------------------------------
+
+	if *runStatusline {
+		line := renderStatusline(slLoadReports(cfg.General.CacheDir, bnCacheTTLs(cfg, time.Now()), daemon.StatusRules(cfg.Status)), *maxWidth, !*noColor,
+			bnGlyphSet(cfg).Glyphs())
+		if line != "" {
+			fmt.Println(line)
+		}
+		os.Exit(0)
+	}
+
+	// ---------------------------------------------------------------
+	// Explain mode
+	// ---------------------------------------------------------------
+
+	if *runExplain {
+		res := slLoadReports(cfg.General.CacheDir, bnCacheTTLs(cfg, time.Now()), daemon.StatusRules(cfg.Status)).evaluator().Explain()
+		if err := writeExplain(os.Stdout, res, *healthJSON); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// ---------------------------------------------------------------
+	// Status mode
+	// ---------------------------------------------------------------
+
+	if *runStatusCmd {
+		reports := slLoadReports(cfg.General.CacheDir, bnCacheTTLs(cfg, time.Now()), daemon.StatusRules(cfg.Status))
+		os.Exit(runStatus(os.Stdout, reports, *healthJSON))
+	}
+
 	// ---------------------------------------------------------------
 	// Starship mode
 	// ---------------------------------------------------------------
 
 	if *starshipMod != "" {
 		scfg := starship.Config{
-			CacheDir: cfg.General.CacheDir,
-		}
-		switch *starshipMod {
-		case "claude":
-			scfg.ShowClaude = true
-		case "billing":
-			scfg.ShowBilling = true
-		case "infra", "tailscale":
-			scfg.ShowTailscale = true
-		case "k8s", "kubernetes":
-			scfg.ShowK8s = true
-		case "system", "sys":
-			scfg.ShowSystem = true
-		case "all":
-			scfg.ShowClaude = true
-			scfg.ShowBilling = true
-			scfg.ShowTailscale = true
-			scfg.ShowK8s = true
-			scfg.ShowSystem = true
-		default:
-			fmt.Fprintf(os.Stderr, "unknown starship segment: %s (supported: claude, billing, infra, k8s, system, all)\n", *starshipMod)
+			CacheDir:  cfg.General.CacheDir,
+			CacheTTLs: bnCacheTTLs(cfg, time.Now()),
+			Separator: *starshipSep,
+			Money:     bnMoneyFormat(cfg),
+			MemoTTL:   cfg.Shell.StarshipMemoTTL.Duration,
+			Loading:   cfg.Shell.StarshipLoading,
+
+			SparklinePoints: cfg.Display.SparklinePoints,
+		}
+		mods, err := starship.ParseModules(*starshipMod)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
+		scfg.Modules = mods
+		for _, m := range mods {
+			if m == starship.ModuleClaude {
+				scfg.ClaudeSparkline = true
+				scfg.ClaudeWeekly = cfg.Shell.StarshipClaudeWeekly
+				scfg.PrimaryAccount = cfg.Display.PrimaryAccount
+			}
+		}
 
 		result := starship.Render(scfg)
 		if result != "" {
@@ -359,10 +610,68 @@ func main() {
 		os.Exit(0)
 	}
 
+	// ---------------------------------------------------------------
+	// Daemon log
+	// ---------------------------------------------------------------
+
+	if *tailLogs {
+		if err := runTailLogs(ctx, os.Stdout, tlLogPath(cfg), *tailLines, *tailLevel); err != nil {
+			fmt.Fprintf(os.Stderr, "tail-logs: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// ---------------------------------------------------------------
+	// Banner watch mode
+	// ---------------------------------------------------------------
+
+	if *bannerWatch {
+		w := &bannerWatcher{
+			out:            os.Stdout,
+			cacheDir:       cfg.General.CacheDir,
+			opts:           bannerOptions(cfg),
+			widthOverride:  *termWidth,
+			heightOverride: *termHeight,
+			sizeFunc:       terminal.GetSize,
+		}
+		if *watchConfig {
+			path := *configPath
+			if path == "" {
+				path = config.FindFile()
+			}
+			if path == "" {
+				fmt.Fprintln(os.Stderr, "watch-config: no config file to watch; create one or pass -config")
+				os.Exit(1)
+			}
+			cw, err := newBwConfigWatch(path, *configPath, *themeFlag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "watch-config: %v\n", err)
+				os.Exit(1)
+			}
+			w.config = cw
+		}
+		if err := runBannerWatch(ctx, w, *watchInterval); err != nil {
+			fmt.Fprintf(os.Stderr, "banner watch failed: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// ---------------------------------------------------------------
 	// Banner mode
 	// ---------------------------------------------------------------
 
+	if *benchBanner {
+		data := buildBannerFromCache(cfg.General.CacheDir, bannerOptions(cfg), version, commit)
+		fmt.Fprintln(os.Stderr, "timing banner renders (about a second per row)...")
+		if err := writeBannerBench(os.Stdout, len(data.Widgets), perf.BenchBanner(data)); err != nil {
+			fmt.Fprintf(os.Stderr, "prompt-pulse: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	if *runBanner {
 		defer func() {
 			if r := recover(); r != nil {
@@ -372,19 +681,21 @@ func main() {
 		}()
 
 		// Determine terminal dimensions.
-		width := *termWidth
-		height := *termHeight
-		if width <= 0 {
-			width = 120 // sensible default
-		}
-		if height <= 0 {
-			height = 35
-		}
+		size := terminal.ResolveSize(*termWidth, *termHeight)
+		width, height := size.Cols, size.Rows
+		slog.Debug("banner: terminal size", "cols", width, "cols_from", size.ColsFrom,
+			"rows", height, "rows_from", size.RowsFrom)
 
 		preset := banner.SelectPreset(width, height)
 
 		// Build widget data from cached collector data.
-		data := buildBannerFromCache(cfg.General.CacheDir, version, commit)
+		opts := bannerOptions(cfg)
+		opts.Compact = preset == banner.Compact || preset == banner.Portrait
+		opts.Width = width
+		if *bannerLive {
+			blCollectLive(cfg, opts)
+		}
+		data := buildBannerFromCache(cfg.General.CacheDir, opts, version, commit)
 
 		result, err := banner.RenderCached(cfg.General.CacheDir, data, preset)
 		if err != nil {
@@ -399,6 +710,39 @@ func main() {
 	// Daemon mode
 	// ---------------------------------------------------------------
 
+	if *collectOnce {
+		dcfg := daemon.DefaultConfig()
+		if cfg.General.CacheDir != "" {
+			dcfg.DataDir = cfg.General.CacheDir
+		}
+
+		d, err := daemon.New(dcfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "daemon init failed: %v\n", err)
+			os.Exit(1)
+		}
+		d.SetAppConfig(cfg)
+		d.SetBannerData(bnWarmData)
+		d.SetBannerWarnings(bnConfigWarnings)
+
+		result, err := d.CollectOnce(ctx)
+		if errors.Is(err, daemon.ErrAlreadyRunning) {
+			// The running daemon keeps the cache fresh; nothing to do.
+			fmt.Fprintf(os.Stderr, "collect-once: skipped: %v\n", err)
+			os.Exit(0)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "collect-once: %v\n", err)
+			os.Exit(1)
+		}
+		for _, o := range result.Collectors {
+			if !o.Success {
+				fmt.Fprintf(os.Stderr, "collect-once: %s: %s\n", o.Name, o.Error)
+			}
+		}
+		os.Exit(0)
+	}
+
 	if *runDaemon {
 		dcfg := daemon.DefaultConfig()
 		if cfg.General.CacheDir != "" {
@@ -411,6 +755,9 @@ func main() {
 			os.Exit(1)
 		}
 		d.SetAppConfig(cfg)
+		d.SetConfigPath(*configPath)
+		d.SetBannerData(bnWarmData)
+		d.SetBannerWarnings(bnConfigWarnings)
 
 		fmt.Fprintf(os.Stderr, "starting prompt-pulse daemon v%s\n", version)
 		if err := d.Start(ctx); err != nil && err != context.Canceled {
@@ -0,0 +1,132 @@
+// Package anomaly flags days whose cloud spend is far outside the recent
+// norm. It compares the latest day against the mean and standard deviation
+// of the days before it, so a runaway resource shows up within a day rather
+// than when the monthly budget finally crosses a threshold.
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DateLayout is the format of DailySpend.Date.
+const DateLayout = "2006-01-02"
+
+const (
+	// DefaultSigma is how many standard deviations above the mean the latest
+	// day must be to count as an anomaly.
+	DefaultSigma = 3.0
+
+	// MinHistoryDays is the number of prior days needed before detection is
+	// enabled. With less history the baseline is too noisy to trust.
+	MinHistoryDays = 7
+
+	// Window is the maximum number of prior days in the baseline.
+	Window = 28
+)
+
+// Floors for the baseline standard deviation. Perfectly flat history (a
+// fixed set of instances) has a deviation of zero, which would turn any
+// increase, however small, into an infinite-sigma spike.
+const (
+	minRelStdDev = 0.10 // fraction of the mean
+	minAbsStdDev = 0.50 // USD
+)
+
+// DailySpend is the cloud spend attributed to one calendar day.
+type DailySpend struct {
+	Date     string  `json:"date"` // DateLayout
+	SpendUSD float64 `json:"spend_usd"`
+}
+
+// Anomaly describes a day whose spend is an outlier against its baseline.
+type Anomaly struct {
+	Date      string  `json:"date"`
+	SpendUSD  float64 `json:"spend_usd"`
+	MeanUSD   float64 `json:"mean_usd"`
+	StdDevUSD float64 `json:"stddev_usd"`
+	Sigma     float64 `json:"sigma"`
+}
+
+// Magnitude returns the day's spend as a multiple of the baseline mean, or
+// zero when the mean is zero.
+func (a *Anomaly) Magnitude() float64 {
+	if a == nil || a.MeanUSD <= 0 {
+		return 0
+	}
+	return a.SpendUSD / a.MeanUSD
+}
+
+// String renders the anomaly as e.g. "Mar 14 $52.10 (8.4× avg)".
+func (a *Anomaly) String() string {
+	if a == nil {
+		return ""
+	}
+	s := a.Date
+	if t, err := time.Parse(DateLayout, a.Date); err == nil {
+		s = t.Format("Jan 2")
+	}
+	s += fmt.Sprintf(" $%.2f", a.SpendUSD)
+	if m := a.Magnitude(); m > 0 {
+		s += fmt.Sprintf(" (%.1f× avg)", m)
+	}
+	return s
+}
+
+// Detect checks the latest day in history (oldest first) against the prior
+// days using DefaultSigma. It returns nil when there is no anomaly or when
+// fewer than MinHistoryDays prior days are available.
+func Detect(history []DailySpend) *Anomaly {
+	return DetectSigma(history, DefaultSigma)
+}
+
+// DetectSigma is Detect with a custom threshold. A non-positive sigma uses
+// DefaultSigma. Only spikes are reported; unusually quiet days are not.
+func DetectSigma(history []DailySpend, sigma float64) *Anomaly {
+	if sigma <= 0 {
+		sigma = DefaultSigma
+	}
+	if len(history) < MinHistoryDays+1 {
+		return nil
+	}
+
+	latest := history[len(history)-1]
+	prior := history[:len(history)-1]
+	if len(prior) > Window {
+		prior = prior[len(prior)-Window:]
+	}
+
+	mean, sd := meanStdDev(prior)
+	if floor := math.Max(mean*minRelStdDev, minAbsStdDev); sd < floor {
+		sd = floor
+	}
+	z := (latest.SpendUSD - mean) / sd
+	if z < sigma {
+		return nil
+	}
+	return &Anomaly{
+		Date:      latest.Date,
+		SpendUSD:  latest.SpendUSD,
+		MeanUSD:   mean,
+		StdDevUSD: sd,
+		Sigma:     z,
+	}
+}
+
+// meanStdDev returns the mean and population standard deviation of the
+// days' spend.
+func meanStdDev(days []DailySpend) (float64, float64) {
+	var sum float64
+	for _, d := range days {
+		sum += d.SpendUSD
+	}
+	mean := sum / float64(len(days))
+
+	var sq float64
+	for _, d := range days {
+		diff := d.SpendUSD - mean
+		sq += diff * diff
+	}
+	return mean, math.Sqrt(sq / float64(len(days)))
+}
@@ -0,0 +1,70 @@
+package anomaly
+
+import (
+	"fmt"
+	"testing"
+)
+
+// days builds a history with consecutive dates in March 2026.
+func days(spend ...float64) []DailySpend {
+	out := make([]DailySpend, len(spend))
+	for i, s := range spend {
+		out[i] = DailySpend{Date: fmt.Sprintf("2026-03-%02d", i+1), SpendUSD: s}
+	}
+	return out
+}
+
+func TestDetect_FlagsSpike(t *testing.T) {
+	a := Detect(days(5, 6, 5, 4, 5, 6, 5, 50))
+	if a == nil {
+		t.Fatal("expected a spike to be flagged")
+	}
+	if a.Date != "2026-03-08" || a.SpendUSD != 50 {
+		t.Errorf("anomaly = %+v", a)
+	}
+	if m := a.Magnitude(); m < 9 || m > 11 {
+		t.Errorf("Magnitude() = %v, want ~10", m)
+	}
+	if got, want := a.String(), "Mar 8 $50.00 (9.7× avg)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestDetect_NormalDay(t *testing.T) {
+	if a := Detect(days(5, 6, 5, 4, 5, 6, 5, 6)); a != nil {
+		t.Errorf("normal day flagged: %+v", a)
+	}
+	// Quiet days are not anomalies.
+	if a := Detect(days(5, 6, 5, 4, 5, 6, 5, 0)); a != nil {
+		t.Errorf("drop flagged: %+v", a)
+	}
+}
+
+func TestDetect_SparseHistoryDisabled(t *testing.T) {
+	if a := Detect(days(5, 5, 5, 500)); a != nil {
+		t.Errorf("detection should be disabled with %d prior days, got %+v", 3, a)
+	}
+	if a := Detect(nil); a != nil {
+		t.Errorf("nil history flagged: %+v", a)
+	}
+}
+
+func TestDetect_FlatHistoryUsesFloor(t *testing.T) {
+	// Zero variance must not turn a few cents into an infinite-sigma spike.
+	if a := Detect(days(10, 10, 10, 10, 10, 10, 10, 10.5)); a != nil {
+		t.Errorf("small rise over flat history flagged: %+v", a)
+	}
+	if a := Detect(days(10, 10, 10, 10, 10, 10, 10, 20)); a == nil {
+		t.Error("doubling over flat history should be flagged")
+	}
+}
+
+func TestDetectSigma_Custom(t *testing.T) {
+	h := days(4, 6, 5, 3, 7, 5, 6, 8)
+	if a := DetectSigma(h, 3); a != nil {
+		t.Errorf("sigma 3 should not flag $8, got %+v", a)
+	}
+	if a := DetectSigma(h, 2); a == nil {
+		t.Error("sigma 2 should flag $8")
+	}
+}
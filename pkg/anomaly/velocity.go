@@ -0,0 +1,52 @@
+package anomaly
+
+import "time"
+
+const (
+	// MinVelocityDays is the number of prior days Velocity needs before it
+	// trusts their average.
+	MinVelocityDays = 3
+
+	// VelocityWarn is the spend velocity at which today counts as
+	// unusually expensive.
+	VelocityWarn = 1.5
+
+	// minVelocityElapsed is how far into the day Velocity waits before
+	// prorating the average: earlier, a single charge swings the ratio
+	// wildly.
+	minVelocityElapsed = time.Hour
+)
+
+// Velocity returns today's spend velocity: the spend accrued so far today,
+// the latest day in history (oldest first), as a multiple of the average
+// of up to Window prior days. Today is only partly over, so the average
+// is prorated to the time of day at now: 1.0 means today is spending at
+// an average day's pace. ok is false when history has no entry for today,
+// fewer than MinVelocityDays prior days, or no prior spend, and during the
+// first hour of the day.
+func Velocity(history []DailySpend, now time.Time) (ratio float64, ok bool) {
+	if len(history) < MinVelocityDays+1 {
+		return 0, false
+	}
+	now = now.Local()
+	today := history[len(history)-1]
+	if today.Date != now.Format(DateLayout) {
+		return 0, false
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	elapsed := now.Sub(midnight)
+	if elapsed < minVelocityElapsed {
+		return 0, false
+	}
+
+	prior := history[:len(history)-1]
+	if len(prior) > Window {
+		prior = prior[len(prior)-Window:]
+	}
+	mean, _ := meanStdDev(prior)
+	if mean <= 0 {
+		return 0, false
+	}
+	expected := mean * elapsed.Hours() / 24
+	return today.SpendUSD / expected, true
+}
@@ -0,0 +1,33 @@
+package anomaly
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestVelocity(t *testing.T) {
+	// days dates 2026-03-01 onward, so the fifth entry is March 5.
+	noon := time.Date(2026, 3, 5, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		name    string
+		history []DailySpend
+		now     time.Time
+		want    float64
+		ok      bool
+	}{
+		{"average pace at noon", days(10, 10, 10, 10, 5), noon, 1, true},
+		{"partial day running hot", days(8, 12, 10, 10, 9), noon, 1.8, true},
+		{"quiet day", days(10, 10, 10, 10, 0), noon, 0, true},
+		{"too little history", days(10, 10, 5), time.Date(2026, 3, 3, 12, 0, 0, 0, time.Local), 0, false},
+		{"no point for today", days(10, 10, 10, 10, 5), noon.Add(24 * time.Hour), 0, false},
+		{"first hour of the day", days(10, 10, 10, 10, 1), noon.Add(-11*time.Hour - 30*time.Minute), 0, false},
+		{"no prior spend", days(0, 0, 0, 0, 5), noon, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := Velocity(tt.history, tt.now)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Velocity() = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
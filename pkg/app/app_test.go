@@ -1,10 +1,15 @@
 package app
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
 )
 
 // helper to create a model with 3 placeholder widgets for testing.
@@ -456,3 +461,129 @@ type testError struct {
 func (e *testError) Error() string {
 	return e.msg
 }
+
+// ---------- Tailscale node detail ----------
+
+func tsTestStatus() *tailscale.Status {
+	rx, tx := 1536.0, 300.0
+	return &tailscale.Status{
+		Self: tailscale.PeerInfo{Hostname: "laptop", Online: true, OS: "linux", TailscaleIPs: []string{"100.64.0.1"}},
+		Peers: []tailscale.PeerInfo{
+			{
+				Hostname: "nas", OS: "linux", Tags: []string{"tag:storage", "tag:home"},
+				DNSName: "nas.tail1234.ts.net.", TailscaleIPs: []string{"100.64.0.2", "fd7a::2"},
+				LastSeen: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), RxBytesPerSec: &rx, TxBytesPerSec: &tx,
+			},
+		},
+	}
+}
+
+func newTailscaleTestModel() AppModel {
+	m := NewAppModel(DefaultConfig(), NewTailscaleWidget(), NewPlaceholder("cpu", "CPU"))
+	m, _ = update(m, tea.WindowSizeMsg{Width: 100, Height: 30})
+	m, _ = update(m, DataUpdateEvent{Source: "tailscale", Data: tsTestStatus()})
+	return m
+}
+
+func TestEnterOpensNodeDetailAndEscReturns(t *testing.T) {
+	m := newTailscaleTestModel()
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.Detail() == nil {
+		t.Fatal("Enter on the tailscale widget should open a detail pane")
+	}
+	if m.ExpandedWidgetID() != "" {
+		t.Error("opening a detail pane should not expand the widget")
+	}
+	view := m.View()
+	for _, want := range []string{
+		"nas", "100.64.0.2, fd7a::2", "tag:storage, tag:home", "nas.tail1234.ts.net",
+		"2026-10-01", "https://login.tailscale.com/admin/machines/100.64.0.2", "metrics unavailable",
+		"↓ 1.5 KB/s  ↑ 300 B/s",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("detail view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Tab is swallowed by the pane rather than moving focus underneath.
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.FocusedWidgetID() != "tailscale" {
+		t.Errorf("focus moved while detail open: %q", m.FocusedWidgetID())
+	}
+
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.Detail() != nil {
+		t.Error("Esc should close the detail pane")
+	}
+	if strings.Contains(m.View(), "metrics unavailable") {
+		t.Error("overview should be shown after Esc")
+	}
+}
+
+func TestEnterExpandsWidgetsWithoutDetail(t *testing.T) {
+	m := newTailscaleTestModel()
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyTab})
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Detail() != nil || m.ExpandedWidgetID() != "cpu" {
+		t.Errorf("placeholder should expand, got detail=%v expanded=%q", m.Detail(), m.ExpandedWidgetID())
+	}
+}
+
+func TestTailscaleSelectionWrapsAndSurvivesRefresh(t *testing.T) {
+	w := NewTailscaleWidget()
+	w.Update(DataUpdateEvent{Source: "tailscale", Data: tsTestStatus()})
+	w.HandleKey(tea.KeyMsg{Type: tea.KeyUp})
+	if n, _ := w.Selected(); n.Hostname != "nas" {
+		t.Fatalf("up from the first node should wrap to the last, got %q", n.Hostname)
+	}
+
+	// A refresh that reorders peers keeps the same host selected.
+	st := tsTestStatus()
+	st.Peers = append([]tailscale.PeerInfo{{Hostname: "router"}}, st.Peers...)
+	w.Update(DataUpdateEvent{Source: "tailscale", Data: st})
+	if n, _ := w.Selected(); n.Hostname != "nas" {
+		t.Errorf("selection after refresh = %q, want nas", n.Hostname)
+	}
+}
+
+func TestNodeDetailDashboardURLTemplate(t *testing.T) {
+	w := NewTailscaleWidget()
+	w.SetDashboardURLs(config.DashboardURLs{"nas": "https://proxy.example.com/ts/{name}"})
+	w.Update(DataUpdateEvent{Source: "tailscale", Data: tsTestStatus()})
+
+	if view := w.OpenDetail().View(100, 30); !strings.Contains(view, "https://login.tailscale.com/admin/machines/100.64.0.1") {
+		t.Errorf("laptop detail should keep the default dashboard:\n%s", view)
+	}
+	w.HandleKey(tea.KeyMsg{Type: tea.KeyDown})
+	if view := w.OpenDetail().View(100, 30); !strings.Contains(view, "https://proxy.example.com/ts/nas") {
+		t.Errorf("nas detail should use the template:\n%s", view)
+	}
+}
+
+func TestNodeDetailMetricsReflowOnResize(t *testing.T) {
+	w := NewTailscaleWidget()
+	w.Update(DataUpdateEvent{Source: "tailscale", Data: tsTestStatus()})
+	w.Update(DataUpdateEvent{Source: NodeMetricsSource, Data: map[string]NodeMetrics{
+		"laptop": {CPUPercent: 42, RAMPercent: 61, DiskPercent: 80, CPUHistory: []float64{10, 30, 42}},
+	}})
+
+	pane := w.OpenDetail()
+	pane.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	wide := pane.View(0, 0)
+	if strings.Contains(wide, "metrics unavailable") || !strings.Contains(wide, "42%") {
+		t.Fatalf("detail should show gauges:\n%s", wide)
+	}
+
+	pane.Update(tea.WindowSizeMsg{Width: 30, Height: 30})
+	narrow := pane.View(0, 0)
+	for _, l := range strings.Split(narrow, "\n") {
+		if components.VisibleLen(l) > 30 {
+			t.Errorf("line wider than resized pane: %q", l)
+		}
+	}
+	if !strings.Contains(narrow, "42%") {
+		t.Errorf("narrow pane lost the CPU gauge:\n%s", narrow)
+	}
+}
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
+)
+
+// DetailPane is a full-screen sub-model opened from a widget. While a pane
+// is open it receives every key except Esc (which closes it) and the quit
+// keys, plus window resizes.
+type DetailPane interface {
+	// Title returns the pane heading.
+	Title() string
+
+	// Update handles messages while the pane is open.
+	Update(msg tea.Msg) tea.Cmd
+
+	// View renders the pane into width x height cells.
+	View(width, height int) string
+}
+
+// DetailOpener is implemented by widgets that can drill into their current
+// selection. When the focused widget implements it, Enter opens the pane
+// it returns instead of expanding the widget. A nil pane means there is
+// nothing to open.
+type DetailOpener interface {
+	OpenDetail() DetailPane
+}
+
+// NodeMetrics is resource usage for one tailnet node, in percent, with
+// optional history for sparklines (oldest first).
+type NodeMetrics struct {
+	CPUPercent  float64
+	RAMPercent  float64
+	DiskPercent float64
+
+	CPUHistory  []float64
+	RAMHistory  []float64
+	DiskHistory []float64
+}
+
+// NodeDetail is the detail pane for a single Tailscale node.
+type NodeDetail struct {
+	node    tailscale.PeerInfo
+	metrics *NodeMetrics
+	now     func() time.Time
+
+	// dashboard is the node's dashboard link, node.DashboardURL unless
+	// the widget that opened the pane overrides it.
+	dashboard string
+
+	// Size from the most recent WindowSizeMsg, used when View is called
+	// without explicit dimensions.
+	width  int
+	height int
+}
+
+// NewNodeDetail creates a detail pane for node. metrics may be nil when no
+// usage data is available for the node.
+func NewNodeDetail(node tailscale.PeerInfo, metrics *NodeMetrics) *NodeDetail {
+	return &NodeDetail{node: node, metrics: metrics, now: time.Now, dashboard: node.DashboardURL()}
+}
+
+// Title returns the node hostname.
+func (d *NodeDetail) Title() string {
+	return d.node.Hostname
+}
+
+// Update records the terminal size so the gauges reflow on resize.
+func (d *NodeDetail) Update(msg tea.Msg) tea.Cmd {
+	if ws, ok := msg.(tea.WindowSizeMsg); ok {
+		d.width, d.height = ws.Width, ws.Height
+	}
+	return nil
+}
+
+// View renders the node fields followed by its resource gauges. Zero
+// dimensions fall back to the last WindowSizeMsg.
+func (d *NodeDetail) View(width, height int) string {
+	if width <= 0 {
+		width = d.width
+	}
+	if height <= 0 {
+		height = d.height
+	}
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+
+	label := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
+	field := func(name, value string) string {
+		if value == "" {
+			value = "—"
+		}
+		return label.Render(components.PadRight(name, 11)) + value
+	}
+
+	status := "offline"
+	if d.node.Online {
+		status = "online"
+	}
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7C3AED")).Render(d.node.Hostname) + "  " + status,
+		"",
+		field("DNS name", strings.TrimSuffix(d.node.DNSName, ".")),
+		field("IP", strings.Join(d.node.TailscaleIPs, ", ")),
+		field("OS", d.node.OS),
+		field("Tags", strings.Join(d.node.Tags, ", ")),
+		field("Last seen", d.lastSeen()),
+		field("Traffic", d.traffic()),
+		field("Dashboard", d.dashboard),
+		"",
+	}
+	lines = append(lines, d.metricLines(width)...)
+
+	for i, l := range lines {
+		lines[i] = components.Truncate(l, width)
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lastSeen describes when the node was last seen, or that it is online now.
+func (d *NodeDetail) lastSeen() string {
+	if d.node.Online {
+		return "now"
+	}
+	if d.node.LastSeen.IsZero() {
+		return ""
+	}
+	ago := d.now().Sub(d.node.LastSeen).Round(time.Minute)
+	return fmt.Sprintf("%s (%s ago)", d.node.LastSeen.Local().Format("2006-01-02 15:04"), ago)
+}
+
+// traffic describes the node's current receive and transmit rates, or ""
+// before the collector has two samples for it.
+func (d *NodeDetail) traffic() string {
+	if d.node.RxBytesPerSec == nil || d.node.TxBytesPerSec == nil {
+		return ""
+	}
+	return "↓ " + components.FormatRate(*d.node.RxBytesPerSec) + "  ↑ " + components.FormatRate(*d.node.TxBytesPerSec)
+}
+
+// metricLines renders one gauge per resource, with a sparkline when there
+// is history, sized to width.
+func (d *NodeDetail) metricLines(width int) []string {
+	if d.metrics == nil {
+		return []string{lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render("metrics unavailable")}
+	}
+
+	const labelW, sparkW = 6, 16
+	gaugeW := width - labelW - 6 // room for " 100%"
+	hasHistory := len(d.metrics.CPUHistory) >= 2 || len(d.metrics.RAMHistory) >= 2 || len(d.metrics.DiskHistory) >= 2
+	if hasHistory && gaugeW > sparkW+10 {
+		gaugeW -= sparkW + 1
+	} else {
+		hasHistory = false
+	}
+	if gaugeW > 40 {
+		gaugeW = 40
+	}
+	if gaugeW < 5 {
+		gaugeW = 5
+	}
+
+	gauge := components.NewGauge(components.DefaultGaugeStyle())
+	spark := components.NewSparkline(components.DefaultSparklineStyle())
+	rows := []struct {
+		name    string
+		value   float64
+		history []float64
+	}{
+		{"CPU", d.metrics.CPUPercent, d.metrics.CPUHistory},
+		{"RAM", d.metrics.RAMPercent, d.metrics.RAMHistory},
+		{"Disk", d.metrics.DiskPercent, d.metrics.DiskHistory},
+	}
+	lines := make([]string, 0, len(rows))
+	for _, r := range rows {
+		line := components.PadRight(r.name, labelW) + gauge.Render(r.value, 100, gaugeW)
+		if hasHistory && len(r.history) >= 2 {
+			// Pad past the percent label so the sparklines line up.
+			line = components.PadRight(line, labelW+gaugeW+5) + " " + spark.Render(r.history, sparkW)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
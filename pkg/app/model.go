@@ -78,6 +78,9 @@ type AppModel struct {
 	helpVisible bool
 	quitting    bool
 
+	// detail is the drill-down pane opened from the focused widget, or nil.
+	detail DetailPane
+
 	// Application configuration.
 	config *Config
 }
@@ -125,6 +128,9 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.layoutDirty = true
+		if m.detail != nil {
+			return m, m.detail.Update(msg)
+		}
 		return m, nil
 
 	case tea.KeyMsg:
@@ -174,6 +180,18 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // handleKey processes keyboard input: global keys first, then delegates to
 // the focused widget.
 func (m AppModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.detail != nil {
+		switch msg.String() {
+		case "q", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case "esc":
+			m.detail = nil
+			return m, nil
+		}
+		return m, m.detail.Update(msg)
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c":
 		m.quitting = true
@@ -192,6 +210,12 @@ func (m AppModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "enter":
+		if o, ok := m.widgets[m.focusedWidget].(DetailOpener); ok {
+			if pane := o.OpenDetail(); pane != nil {
+				m.detail = pane
+				return m, pane.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+			}
+		}
 		m.ToggleExpand()
 		m.layoutDirty = true
 		return m, nil
@@ -230,6 +254,15 @@ func (m AppModel) View() string {
 		m.computeLayout()
 	}
 
+	// An open detail pane takes the whole screen.
+	if m.detail != nil {
+		content := m.detail.View(m.width, m.height-1) // Reserve 1 line for status.
+		status := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6B7280")).
+			Render(m.detail.Title() + " | Esc to go back | q to quit")
+		return lipgloss.JoinVertical(lipgloss.Left, content, status)
+	}
+
 	// If a widget is expanded, render only that widget.
 	if m.expandedWidget != "" {
 		if w, ok := m.widgets[m.expandedWidget]; ok {
@@ -400,8 +433,9 @@ func (m AppModel) renderHelp() string {
 		lipgloss.NewStyle().Bold(true).Render("Keybindings"),
 		"",
 		"  Tab / Shift+Tab   Cycle widget focus",
-		"  Enter             Expand focused widget",
-		"  Esc               Collapse expanded widget",
+		"  Enter             Expand focused widget, or open the selection",
+		"  j / k             Move the selection in list widgets",
+		"  Esc               Collapse expanded widget or close detail",
 		"  ?                 Toggle this help",
 		"  q / Ctrl+C        Quit",
 	}
@@ -439,6 +473,11 @@ func (m AppModel) ExpandedWidgetID() string {
 	return m.expandedWidget
 }
 
+// Detail returns the open detail pane, or nil.
+func (m AppModel) Detail() DetailPane {
+	return m.detail
+}
+
 // HelpVisible returns whether the help overlay is shown.
 func (m AppModel) HelpVisible() bool {
 	return m.helpVisible
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// NodeMetricsSource is the DataUpdateEvent source carrying per-node usage
+// as a map[string]NodeMetrics keyed by hostname.
+const NodeMetricsSource = "nodemetrics"
+
+// TailscaleWidget lists the tailnet's nodes, this machine first, with a
+// movable selection. Enter opens a NodeDetail for the selected node.
+type TailscaleWidget struct {
+	status   *tailscale.Status
+	metrics  map[string]NodeMetrics
+	selected int
+	urls     config.DashboardURLs
+}
+
+// NewTailscaleWidget creates an empty TailscaleWidget; it fills in from
+// "tailscale" DataUpdateEvents.
+func NewTailscaleWidget() *TailscaleWidget {
+	return &TailscaleWidget{}
+}
+
+// ID returns "tailscale".
+func (w *TailscaleWidget) ID() string { return "tailscale" }
+
+// Title returns the widget's display title.
+func (w *TailscaleWidget) Title() string { return "Tailscale" }
+
+// MinSize returns the minimum dimensions for the widget.
+func (w *TailscaleWidget) MinSize() (int, int) { return 30, 4 }
+
+// Update stores Tailscale status and node metrics updates. The selection
+// follows the selected hostname across refreshes when it still exists.
+func (w *TailscaleWidget) Update(msg tea.Msg) tea.Cmd {
+	ev, ok := msg.(DataUpdateEvent)
+	if !ok || ev.Err != nil {
+		return nil
+	}
+	switch data := ev.Data.(type) {
+	case *tailscale.Status:
+		prev, hadPrev := w.Selected()
+		w.status = data
+		w.selected = 0
+		if hadPrev {
+			for i, n := range w.nodes() {
+				if n.Hostname == prev.Hostname {
+					w.selected = i
+					break
+				}
+			}
+		}
+	case map[string]NodeMetrics:
+		w.metrics = data
+	}
+	return nil
+}
+
+// HandleKey moves the selection with the arrow keys or j/k.
+func (w *TailscaleWidget) HandleKey(key tea.KeyMsg) tea.Cmd {
+	n := len(w.nodes())
+	if n == 0 {
+		return nil
+	}
+	switch key.String() {
+	case "down", "j":
+		w.selected = (w.selected + 1) % n
+	case "up", "k":
+		w.selected = (w.selected - 1 + n) % n
+	}
+	return nil
+}
+
+// SetDashboardURLs replaces the dashboard links shown in node details with
+// the matching templates from urls (see config.DashboardURLs).
+func (w *TailscaleWidget) SetDashboardURLs(urls config.DashboardURLs) {
+	w.urls = urls
+}
+
+// Selected returns the selected node, if there is one.
+func (w *TailscaleWidget) Selected() (tailscale.PeerInfo, bool) {
+	nodes := w.nodes()
+	if w.selected < 0 || w.selected >= len(nodes) {
+		return tailscale.PeerInfo{}, false
+	}
+	return nodes[w.selected], true
+}
+
+// OpenDetail implements DetailOpener for the selected node.
+func (w *TailscaleWidget) OpenDetail() DetailPane {
+	node, ok := w.Selected()
+	if !ok {
+		return nil
+	}
+	var m *NodeMetrics
+	if nm, ok := w.metrics[node.Hostname]; ok {
+		m = &nm
+	}
+	d := NewNodeDetail(node, m)
+	d.dashboard = w.urls.Resolve("tailscale", node.Hostname, "", d.dashboard)
+	return d
+}
+
+// View renders one line per node, scrolled so the selection stays visible.
+func (w *TailscaleWidget) View(width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	nodes := w.nodes()
+	if len(nodes) == 0 {
+		return "no tailscale data"
+	}
+
+	start := 0
+	if w.selected >= height {
+		start = w.selected - height + 1
+	}
+	end := start + height
+	if end > len(nodes) {
+		end = len(nodes)
+	}
+
+	online := lipgloss.NewStyle().Foreground(lipgloss.Color("#4CAF50"))
+	offline := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		n := nodes[i]
+		marker := "  "
+		if i == w.selected {
+			marker = "› "
+		}
+		dot := offline.Render("○")
+		if n.Online {
+			dot = online.Render("●")
+		}
+		ip := ""
+		if len(n.TailscaleIPs) > 0 {
+			ip = n.TailscaleIPs[0]
+		}
+		line := fmt.Sprintf("%s%s %s  %s  %s", marker, dot, n.Hostname, ip, n.OS)
+		lines = append(lines, components.Truncate(line, width))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nodes returns this machine followed by its peers.
+func (w *TailscaleWidget) nodes() []tailscale.PeerInfo {
+	if w.status == nil {
+		return nil
+	}
+	nodes := make([]tailscale.PeerInfo, 0, len(w.status.Peers)+1)
+	if w.status.Self.Hostname != "" {
+		nodes = append(nodes, w.status.Self)
+	}
+	return append(nodes, w.status.Peers...)
+}
@@ -20,12 +20,23 @@ var (
 	Wide = Preset{"wide", 160, 45}
 	// UltraWide is a three-column layout with wider waifu for ultra-wide terminals.
 	UltraWide = Preset{"ultrawide", 200, 50}
+	// Portrait is a narrow, tall single-column layout for split panes
+	// that are taller than they are wide.
+	Portrait = Preset{"portrait", 50, 60}
 )
 
 // SelectPreset chooses the best preset for the given terminal dimensions.
-// The selected preset is the largest one whose width and height both fit
-// within the terminal. If none fit, Compact is returned.
+// A terminal too narrow for Standard that fits Portrait and is taller than
+// it is wide on screen (a cell is about twice as tall as it is wide) gets
+// Portrait. Otherwise the selected preset is the largest one whose width
+// and height both fit within the terminal. If none fit, Compact is
+// returned.
 func SelectPreset(termWidth, termHeight int) Preset {
+	if termWidth < Standard.Width && termWidth >= Portrait.Width &&
+		termHeight >= Portrait.Height && termHeight*2 > termWidth {
+		return Portrait
+	}
+
 	// Order from largest to smallest; return the first that fits.
 	presets := []Preset{UltraWide, Wide, Standard, Compact}
 	for _, p := range presets {
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/x/ansi"
+
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
 )
 
@@ -69,6 +71,29 @@ func TestSelectPreset_TinyTerminal(t *testing.T) {
 	}
 }
 
+func TestSelectPreset_Portrait(t *testing.T) {
+	for _, sz := range [][2]int{{50, 80}, {50, 60}, {80, 60}, {119, 70}} {
+		if p := SelectPreset(sz[0], sz[1]); p != Portrait {
+			t.Errorf("expected portrait for %dx%d, got %s", sz[0], sz[1], p.Name)
+		}
+	}
+	// Too short, too narrow, or not tall relative to the width: the usual
+	// width thresholds apply.
+	for _, tt := range []struct {
+		w, h int
+		want Preset
+	}{
+		{50, 59, Compact},
+		{49, 80, Compact},
+		{80, 24, Compact},
+		{120, 80, Standard},
+	} {
+		if p := SelectPreset(tt.w, tt.h); p != tt.want {
+			t.Errorf("expected %s for %dx%d, got %s", tt.want.Name, tt.w, tt.h, p.Name)
+		}
+	}
+}
+
 // --- Render tests ---
 
 func TestRender_EmptyBannerData(t *testing.T) {
@@ -232,6 +257,31 @@ func TestBnArrangeWidgets_CompactSingleColumn(t *testing.T) {
 	}
 }
 
+func TestBnArrangeWidgets_PortraitStacksWaifuLast(t *testing.T) {
+	widgets := []WidgetData{
+		{ID: "waifu-main", Title: "Waifu", Content: "img", MinH: 20},
+		{ID: "a", Title: "A", Content: "alpha", MinH: 15},
+		{ID: "b", Title: "B", Content: "beta", MinH: 15},
+	}
+	placements := bnArrangeWidgets(widgets, Portrait.Width, Portrait.Height)
+	if len(placements) != 3 {
+		t.Fatalf("expected 3 placements, got %d", len(placements))
+	}
+	var ids []string
+	for _, p := range placements {
+		if p.X != 0 || p.W != Portrait.Width {
+			t.Errorf("portrait: widget %s at X=%d W=%d, want a full-width single column", p.Widget.ID, p.X, p.W)
+		}
+		ids = append(ids, p.Widget.ID)
+	}
+	if got := strings.Join(ids, ","); got != "a,b,waifu-main" {
+		t.Errorf("portrait order = %s, want data sections before the image", got)
+	}
+	if w := placements[2]; w.Y != 30 || w.H != 20 {
+		t.Errorf("waifu placed at Y=%d H=%d, want Y=30 H=20", w.Y, w.H)
+	}
+}
+
 func TestBnArrangeWidgets_StandardWaifuInLeftColumn(t *testing.T) {
 	widgets := []WidgetData{
 		{ID: "waifu-main", Title: "Waifu", Content: "img", MinW: 40, MinH: 10},
@@ -350,6 +400,51 @@ func TestBnCompose_EmptyPlacements(t *testing.T) {
 	}
 }
 
+func TestBnCompose_SideBySideBoxesKeepWidth(t *testing.T) {
+	placements := []bnPlacement{
+		{Widget: WidgetData{ID: "left", Title: "L", Content: "left"}, X: 0, Y: 0, W: 20, H: 4},
+		{Widget: WidgetData{ID: "right", Title: "R", Content: "right"}, X: 20, Y: 0, W: 20, H: 4},
+	}
+	lines := strings.Split(bnCompose(placements, 40, 4), "\n")
+	for i, line := range lines {
+		if vis := components.VisibleLen(line); vis != 40 {
+			t.Errorf("line %d: expected visible width 40, got %d: %q", i, vis, line)
+		}
+	}
+	if !strings.HasPrefix(lines[0], "╭─ L ") || !strings.Contains(lines[0], "╮╭─ R ") {
+		t.Errorf("expected both box tops on line 0, got %q", lines[0])
+	}
+}
+
+func TestRender_HyperlinksDoNotChangeVisibleLayout(t *testing.T) {
+	widgets := func(link func(text, url string) string) BannerData {
+		return BannerData{Widgets: []WidgetData{
+			{ID: "waifu", Title: "Waifu", Content: "image " + link("host", "https://example.com/a"), MinW: 40, MinH: 10},
+			{ID: "net", Title: "Net", Content: "Exit: " + link("exit-node", "https://example.com/b"), MinW: 30, MinH: 4},
+			{ID: "k8s", Title: "K8s", Content: link(strings.Repeat("cluster", 20), "https://example.com/c"), MinW: 30, MinH: 4},
+		}}
+	}
+	plain := Render(widgets(func(text, _ string) string { return text }), Standard)
+	linked := Render(widgets(components.Hyperlink), Standard)
+
+	if !strings.Contains(linked, "\x1b]8;;https://example.com/b") {
+		t.Fatal("expected OSC 8 hyperlink in rendered banner")
+	}
+	plainLines := strings.Split(plain, "\n")
+	linkedLines := strings.Split(linked, "\n")
+	if len(linkedLines) != len(plainLines) {
+		t.Fatalf("line count changed: %d vs %d", len(linkedLines), len(plainLines))
+	}
+	for i := range plainLines {
+		if got := ansi.Strip(linkedLines[i]); got != plainLines[i] {
+			t.Errorf("line %d differs once escapes are stripped:\n got %q\nwant %q", i, got, plainLines[i])
+		}
+		if vis := components.VisibleLen(linkedLines[i]); vis != Standard.Width {
+			t.Errorf("line %d: visible width %d, want %d", i, vis, Standard.Width)
+		}
+	}
+}
+
 // --- RenderCached tests ---
 
 func TestRenderCached_WritesCacheFile(t *testing.T) {
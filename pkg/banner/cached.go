@@ -4,10 +4,13 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
 )
 
 // bnCacheTTL is the maximum age of a cached banner file before it is
@@ -20,6 +23,7 @@ const bnCacheTTL = 30 * time.Second
 // fast <1ms path). Otherwise the banner is rendered fresh, written to the
 // cache atomically (temp file + rename), and the result is returned.
 func RenderCached(cacheDir string, data BannerData, preset Preset) (string, error) {
+	start := time.Now()
 	key := bnCacheKey(data, preset)
 	path := filepath.Join(cacheDir, "banner-"+key+".cache")
 
@@ -29,6 +33,7 @@ func RenderCached(cacheDir string, data BannerData, preset Preset) (string, erro
 		if age < bnCacheTTL {
 			content, err := os.ReadFile(path)
 			if err == nil {
+				slog.Debug("banner: cache hit", "key", key, "age", age, "elapsed", time.Since(start))
 				return string(content), nil
 			}
 			// Fall through on read error.
@@ -41,9 +46,10 @@ func RenderCached(cacheDir string, data BannerData, preset Preset) (string, erro
 	// Write to cache atomically.
 	if err := bnAtomicWriteCache(cacheDir, path, result); err != nil {
 		// Cache write failure is non-fatal; return the rendered result.
-		return result, nil
+		slog.Warn("banner: write cache", "err", err)
 	}
 
+	slog.Debug("banner: cache miss", "key", key, "preset", preset.Name, "elapsed", time.Since(start))
 	return result, nil
 }
 
@@ -73,7 +79,7 @@ func bnCacheKey(data BannerData, preset Preset) string {
 // bnAtomicWriteCache writes content to path via a temporary file and rename,
 // ensuring readers never see a partial file.
 func bnAtomicWriteCache(dir, path, content string) error {
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := os.MkdirAll(dir, cache.DirMode); err != nil {
 		return fmt.Errorf("banner cache: mkdir %s: %w", dir, err)
 	}
 
@@ -106,6 +112,12 @@ func bnAtomicWriteCache(dir, path, content string) error {
 	return nil
 }
 
+// CacheKey returns the key RenderCached uses for data and preset. Callers
+// that redraw periodically can compare keys to skip unchanged frames.
+func CacheKey(data BannerData, preset Preset) string {
+	return bnCacheKey(data, preset)
+}
+
 // bnCacheKeyExported is an exported wrapper for testing. Tests in the same
 // package can call bnCacheKey directly, but this provides a public entry
 // point if needed from external test packages.
@@ -21,9 +21,9 @@ func bnCompose(placements []bnPlacement, width, height int) string {
 	}
 
 	// Initialize grid with spaces.
-	grid := make([][]byte, height)
+	grid := make([]string, height)
 	for i := range grid {
-		grid[i] = []byte(strings.Repeat(" ", width))
+		grid[i] = strings.Repeat(" ", width)
 	}
 
 	// Stamp each widget onto the grid.
@@ -32,19 +32,16 @@ func bnCompose(placements []bnPlacement, width, height int) string {
 		bnStampOnGrid(grid, rendered, p.X, p.Y, p.W, p.H, width)
 	}
 
-	// Join grid lines.
-	lines := make([]string, height)
-	for i, row := range grid {
-		lines[i] = string(row)
-	}
-	return strings.Join(lines, "\n")
+	return strings.Join(grid, "\n")
 }
 
 // bnStampOnGrid writes the rendered box content onto the grid at position
 // (x, y). Each line of the rendered content replaces the corresponding
 // segment of the grid row. Lines are truncated or padded to fit within
-// the allocated width, and clipped to the grid boundaries.
-func bnStampOnGrid(grid [][]byte, rendered string, x, y, w, h, gridWidth int) {
+// the allocated width, and clipped to the grid boundaries. Rows are sliced
+// by visible cell, not byte, so box-drawing characters, colors, and OSC 8
+// hyperlinks stamped by earlier placements survive intact.
+func bnStampOnGrid(grid []string, rendered string, x, y, w, h, gridWidth int) {
 	if rendered == "" {
 		return
 	}
@@ -77,19 +74,8 @@ func bnStampOnGrid(grid [][]byte, rendered string, x, y, w, h, gridWidth int) {
 		}
 
 		// Build new row: prefix + clipped + suffix.
-		prefix := ""
-		if x > 0 {
-			prefix = string(grid[row][:x])
-		}
-
-		suffixStart := x + visLen
-		suffix := ""
-		if suffixStart < gridWidth {
-			suffix = string(grid[row][suffixStart:])
-		}
-
-		// Since our grid is plain ASCII spaces (no ANSI in the grid itself
-		// except what we stamp), we can safely concatenate.
+		prefix := components.Cut(grid[row], 0, x)
+		suffix := components.Cut(grid[row], x+visLen, gridWidth)
 		newRow := prefix + clipped + suffix
 
 		// Ensure the row is exactly gridWidth visible characters.
@@ -100,7 +86,7 @@ func bnStampOnGrid(grid [][]byte, rendered string, x, y, w, h, gridWidth int) {
 			newRow = components.Truncate(newRow, gridWidth)
 		}
 
-		grid[row] = []byte(newRow)
+		grid[row] = newRow
 	}
 }
 
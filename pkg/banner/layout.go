@@ -22,6 +22,10 @@ type bnColumnLayout struct {
 	// WaifuCol is the column index for waifu widgets (or -1 if no
 	// dedicated waifu column).
 	WaifuCol int
+	// WaifuLast stacks waifu widgets below every data widget instead of
+	// in their given order when there is no waifu column, so the image
+	// only takes rows the data sections leave free.
+	WaifuLast bool
 }
 
 // bnLayoutForPreset returns the column layout configuration for a given preset.
@@ -67,6 +71,15 @@ func bnLayoutForPreset(preset Preset) bnColumnLayout {
 			},
 			WaifuCol: 0,
 		}
+	case "portrait":
+		return bnColumnLayout{
+			Columns: 1,
+			ColWidths: func(w int) []int {
+				return []int{w}
+			},
+			WaifuCol:  -1,
+			WaifuLast: true,
+		}
 	default:
 		// Fallback to compact.
 		return bnColumnLayout{
@@ -109,12 +122,15 @@ func bnArrangeWidgets(widgets []WidgetData, width, height int) []bnPlacement {
 	// Separate waifu widgets from data widgets.
 	var waifuWidgets, dataWidgets []WidgetData
 	for _, w := range widgets {
-		if bnIsWaifuWidget(w) && layout.WaifuCol >= 0 {
+		if bnIsWaifuWidget(w) && (layout.WaifuCol >= 0 || layout.WaifuLast) {
 			waifuWidgets = append(waifuWidgets, w)
 		} else {
 			dataWidgets = append(dataWidgets, w)
 		}
 	}
+	if layout.WaifuCol < 0 {
+		dataWidgets = append(dataWidgets, waifuWidgets...)
+	}
 
 	// Place waifu widgets in the waifu column.
 	if layout.WaifuCol >= 0 {
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"os"
@@ -484,6 +485,33 @@ func TestGetTypedInvalidJSONReturnsFalse(t *testing.T) {
 	}
 }
 
+func TestGetTypedWithTTLPerKey(t *testing.T) {
+	s := newTestStore(t)
+
+	type Report struct {
+		Value int `json:"value"`
+	}
+	if err := PutTyped(s, "tailscale", Report{Value: 1}); err != nil {
+		t.Fatalf("PutTyped: %v", err)
+	}
+	if err := PutTyped(s, "billing", Report{Value: 2}); err != nil {
+		t.Fatalf("PutTyped: %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if _, ok := GetTypedWithTTL[Report](s, "tailscale", 20*time.Millisecond); ok {
+		t.Error("tailscale should be stale under a 20ms TTL")
+	}
+	if got, ok := GetTypedWithTTL[Report](s, "billing", time.Hour); !ok || got.Value != 2 {
+		t.Errorf("billing should still be fresh under a 1h TTL, got %+v, %v", got, ok)
+	}
+	// A short-TTL miss must not evict the entry for other readers.
+	if _, ok := GetTyped[Report](s, "tailscale"); !ok {
+		t.Error("entry should survive a max-age miss")
+	}
+}
+
 func TestPutTypedWithNonSerializableReturnsError(t *testing.T) {
 	s := newTestStore(t)
 
@@ -798,6 +826,91 @@ func TestNewStoreCreatesDirectory(t *testing.T) {
 	}
 }
 
+func TestNewStoreCreatesPrivateFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	s, err := NewStore(StoreConfig{Dir: dir, CleanupInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	if err := s.Put("billing", []byte(`{"total":12.5}`)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if got := info.Mode().Perm(); got != DirMode {
+		t.Errorf("directory mode = %o, want %o", got, DirMode)
+	}
+	h := hashKey("billing")
+	for _, path := range []string{s.dataPath(h), s.metaPath(h)} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat: %v", err)
+		}
+		if got := info.Mode().Perm(); got != FileMode {
+			t.Errorf("%s mode = %o, want %o", filepath.Base(path), got, FileMode)
+		}
+	}
+}
+
+func TestNewStoreTightensExistingDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewStore(StoreConfig{Dir: dir, CleanupInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if got := info.Mode().Perm(); got != DirMode {
+		t.Errorf("directory mode = %o, want %o", got, DirMode)
+	}
+}
+
+func TestEnsurePrivateDirLeavesStickyDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "shared")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(dir, 0o777|os.ModeSticky); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsurePrivateDir(dir); err != nil {
+		t.Fatalf("EnsurePrivateDir: %v", err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if got := info.Mode().Perm(); got != 0o777 {
+		t.Errorf("sticky directory mode = %o, want it left at 777", got)
+	}
+}
+
+func TestStoreFileModeOverride(t *testing.T) {
+	s := newTestStore(t, func(c *StoreConfig) { c.FileMode = 0o640 })
+	if err := s.Put("k", []byte("v")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	info, err := os.Stat(s.dataPath(hashKey("k")))
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if got := info.Mode().Perm(); got != 0o640 {
+		t.Errorf("data file mode = %o, want 640", got)
+	}
+}
+
 func TestStatsEvictionCount(t *testing.T) {
 	s := newTestStore(t, func(cfg *StoreConfig) {
 		cfg.MaxSizeMB = 1
@@ -888,3 +1001,159 @@ func TestMetaFileIntegrity(t *testing.T) {
 		t.Error("meta created should not be zero")
 	}
 }
+
+func TestGetTypedQuarantinesCorruptFile(t *testing.T) {
+	s := newTestStore(t)
+	type payload struct{ N int }
+	if err := PutTyped(s, "claude", payload{N: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a truncated write by overwriting the data file and its
+	// recorded size with garbage of the same length.
+	h := hashKey("claude")
+	garbage := []byte("{\"N\":")
+	if err := os.WriteFile(s.dataPath(h), garbage, 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, _ := s.readMeta(h)
+	meta.Size = int64(len(garbage))
+	mb, _ := json.Marshal(meta)
+	if err := os.WriteFile(s.metaPath(h), mb, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := GetTyped[payload](s, "claude"); ok {
+		t.Fatal("GetTyped should miss on corrupt JSON")
+	}
+	if _, err := os.Stat(s.dataPath(h) + corruptSuffix); err != nil {
+		t.Errorf("corrupt file should be renamed to .corrupt: %v", err)
+	}
+	if _, err := os.Stat(s.dataPath(h)); !os.IsNotExist(err) {
+		t.Error("original data file should be gone")
+	}
+	if s.Has("claude") {
+		t.Error("quarantined key should no longer be in the index")
+	}
+
+	// The key is usable again after a fresh write.
+	if err := PutTyped(s, "claude", payload{N: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := GetTyped[payload](s, "claude"); !ok || v.N != 2 {
+		t.Errorf("GetTyped after rewrite = %+v, %v", v, ok)
+	}
+}
+
+func TestGetTypedKeepsMismatchedJSON(t *testing.T) {
+	s := newTestStore(t)
+	// Valid JSON of another shape, e.g. from a newer writer.
+	if err := s.PutString("claude", `{"N":"two"}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := GetTyped[struct{ N int }](s, "claude"); ok {
+		t.Fatal("GetTyped should miss on a type mismatch")
+	}
+	if _, err := os.Stat(s.dataPath(hashKey("claude")) + corruptSuffix); !os.IsNotExist(err) {
+		t.Error("a type mismatch should not be quarantined")
+	}
+	if v, ok := GetTyped[struct{ N string }](s, "claude"); !ok || v.N != "two" {
+		t.Errorf("a reader of the right type got %+v, %v", v, ok)
+	}
+}
+
+func TestQuarantineIfUnchanged(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.PutString("k", "{bad"); err != nil {
+		t.Fatal(err)
+	}
+	read, _ := s.Get("k")
+	// A writer replaces the entry between the read and the quarantine.
+	if err := s.PutString("k", `{"ok":true}`); err != nil {
+		t.Fatal(err)
+	}
+	if done, err := s.QuarantineIfUnchanged("k", read, errors.New("syntax")); done || err != nil {
+		t.Errorf("QuarantineIfUnchanged after a rewrite = %v, %v; want false", done, err)
+	}
+	if v, ok := s.GetString("k"); !ok || v != `{"ok":true}` {
+		t.Errorf("rewritten entry = %q, %v; want it kept", v, ok)
+	}
+
+	read, _ = s.Get("k")
+	if done, err := s.QuarantineIfUnchanged("k", read, errors.New("syntax")); !done || err != nil {
+		t.Errorf("QuarantineIfUnchanged on unchanged data = %v, %v; want true", done, err)
+	}
+	if s.Has("k") {
+		t.Error("quarantined key should no longer be in the index")
+	}
+}
+
+func TestGetQuarantinesTruncatedData(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.PutString("k", "0123456789"); err != nil {
+		t.Fatal(err)
+	}
+	h := hashKey("k")
+	if err := os.WriteFile(s.dataPath(h), []byte("01234"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get("k"); ok {
+		t.Fatal("Get should miss when the data file is shorter than recorded")
+	}
+	if _, err := os.Stat(s.dataPath(h) + corruptSuffix); err != nil {
+		t.Errorf("truncated file should be quarantined: %v", err)
+	}
+}
+
+func TestCompactRemovesOrphans(t *testing.T) {
+	s := newTestStore(t)
+	for _, k := range []string{"claude", "billing", "openai", "old-provider"} {
+		if err := s.PutString(k, k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	leftovers := []string{hashKey("gone") + ".cache" + corruptSuffix, ".tmp-12345"}
+	unrelated := []string{"notes.txt", "claude.json", "snooze.json"}
+	for _, name := range append(leftovers, unrelated...) {
+		if err := os.WriteFile(filepath.Join(s.cfg.Dir, name), []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := s.Compact([]string{"claude", "billing"})
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if len(removed) != 6 {
+		t.Errorf("removed %d files, want 6 (two entries, two leftovers): %v", len(removed), removed)
+	}
+
+	for _, k := range []string{"claude", "billing"} {
+		if v, ok := s.GetString(k); !ok || v != k {
+			t.Errorf("active key %q lost: %q, %v", k, v, ok)
+		}
+	}
+	for _, k := range []string{"openai", "old-provider"} {
+		if s.Has(k) {
+			t.Errorf("orphan key %q survived", k)
+		}
+		for _, path := range []string{s.dataPath(hashKey(k)), s.metaPath(hashKey(k))} {
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("orphan file %s survived", filepath.Base(path))
+			}
+		}
+	}
+	for _, name := range leftovers {
+		if _, err := os.Stat(filepath.Join(s.cfg.Dir, name)); !os.IsNotExist(err) {
+			t.Errorf("leftover %s survived", name)
+		}
+	}
+	for _, name := range unrelated {
+		if _, err := os.Stat(filepath.Join(s.cfg.Dir, name)); err != nil {
+			t.Errorf("unrelated file %s was touched: %v", name, err)
+		}
+	}
+	if got := s.Stats().Entries; got != 2 {
+		t.Errorf("entries after compact = %d, want 2", got)
+	}
+}
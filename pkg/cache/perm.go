@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+)
+
+// Permissions for cache directories and the files in them. Cached reports
+// include spend figures and account names, so on a shared host they must
+// not be readable by other users.
+const (
+	DirMode  os.FileMode = 0o700
+	FileMode os.FileMode = 0o600
+)
+
+// EnsurePrivateDir creates dir with DirMode if it does not exist. An
+// existing directory that grants any access to group or others, such as
+// one created by an older release with 0755, is narrowed to DirMode.
+// Shared sticky directories such as /tmp are left as they are.
+func EnsurePrivateDir(dir string) error {
+	if err := os.MkdirAll(dir, DirMode); err != nil {
+		return fmt.Errorf("cache: create directory %s: %w", dir, err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("cache: stat directory %s: %w", dir, err)
+	}
+	if info.Mode().Perm()&0o077 != 0 && info.Mode()&os.ModeSticky == 0 {
+		if err := os.Chmod(dir, DirMode); err != nil {
+			return fmt.Errorf("cache: restrict directory %s: %w", dir, err)
+		}
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package cache
 
 import (
+	"bytes"
 	"container/list"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -26,6 +28,10 @@ type StoreConfig struct {
 	// CleanupInterval is how often the background goroutine sweeps for
 	// expired entries. Default: 5 minutes.
 	CleanupInterval time.Duration
+
+	// FileMode is the permission of the data and metadata files. Default:
+	// FileMode (0600). Set it to share the cache with a group, e.g. 0640.
+	FileMode os.FileMode
 }
 
 // CacheStats holds runtime statistics for a cache Store.
@@ -71,8 +77,8 @@ type Store struct {
 	wg        sync.WaitGroup
 }
 
-// NewStore creates a new cache Store. The cache directory is created with
-// 0755 permissions if it does not exist. Existing entries are loaded from
+// NewStore creates a new cache Store. The cache directory is created, or
+// narrowed, with EnsurePrivateDir. Existing entries are loaded from
 // disk to rebuild the LRU state.
 func NewStore(cfg StoreConfig) (*Store, error) {
 	if cfg.MaxSizeMB <= 0 {
@@ -87,9 +93,12 @@ func NewStore(cfg StoreConfig) (*Store, error) {
 	if cfg.CleanupInterval <= 0 {
 		cfg.CleanupInterval = 5 * time.Minute
 	}
+	if cfg.FileMode == 0 {
+		cfg.FileMode = FileMode
+	}
 
-	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
-		return nil, fmt.Errorf("cache: create directory %s: %w", cfg.Dir, err)
+	if err := EnsurePrivateDir(cfg.Dir); err != nil {
+		return nil, err
 	}
 
 	s := &Store{
@@ -112,6 +121,13 @@ func NewStore(cfg StoreConfig) (*Store, error) {
 // Get retrieves the raw bytes for key. Returns (nil, false) if the key is
 // missing or expired. On a hit, the entry is promoted to the front of the LRU.
 func (s *Store) Get(key string) ([]byte, bool) {
+	return s.GetWithMaxAge(key, 0)
+}
+
+// GetWithMaxAge is like Get but also misses when the entry was written more
+// than maxAge ago. Unlike TTL expiry the entry is kept, since readers with a
+// longer cutoff may still use it. A non-positive maxAge disables the check.
+func (s *Store) GetWithMaxAge(key string, maxAge time.Duration) ([]byte, bool) {
 	h := hashKey(key)
 
 	s.mu.Lock()
@@ -126,6 +142,9 @@ func (s *Store) Get(key string) ([]byte, bool) {
 	// Check TTL
 	meta, err := s.readMeta(h)
 	if err != nil {
+		if !os.IsNotExist(err) {
+			s.quarantineLocked(h, key, err)
+		}
 		s.misses++
 		return nil, false
 	}
@@ -134,12 +153,23 @@ func (s *Store) Get(key string) ([]byte, bool) {
 		s.misses++
 		return nil, false
 	}
+	if maxAge > 0 && time.Since(time.Unix(0, meta.Created)) > maxAge {
+		s.misses++
+		return nil, false
+	}
 
 	data, err := os.ReadFile(s.dataPath(h))
 	if err != nil {
 		s.misses++
 		return nil, false
 	}
+	if int64(len(data)) != meta.Size {
+		// A short data file means a write was cut off after the rename of
+		// an earlier, larger entry's meta; never serve a partial value.
+		s.quarantineLocked(h, key, fmt.Errorf("size %d, want %d", len(data), meta.Size))
+		s.misses++
+		return nil, false
+	}
 
 	// Promote in LRU
 	s.lru.MoveToFront(elem)
@@ -180,12 +210,12 @@ func (s *Store) PutWithTTL(key string, value []byte, ttl time.Duration) error {
 	}
 
 	// Atomic write: data file
-	if err := atomicWrite(s.dataPath(h), value, s.cfg.Dir); err != nil {
+	if err := atomicWrite(s.dataPath(h), value, s.cfg.Dir, s.cfg.FileMode); err != nil {
 		return fmt.Errorf("cache: write data for %q: %w", key, err)
 	}
 
 	// Atomic write: meta file
-	if err := atomicWrite(s.metaPath(h), metaBytes, s.cfg.Dir); err != nil {
+	if err := atomicWrite(s.metaPath(h), metaBytes, s.cfg.Dir, s.cfg.FileMode); err != nil {
 		// Best effort: remove the data file we just wrote
 		_ = os.Remove(s.dataPath(h))
 		return fmt.Errorf("cache: write meta for %q: %w", key, err)
@@ -308,7 +338,8 @@ func (s *Store) Clear() error {
 			continue
 		}
 		name := e.Name()
-		if strings.HasSuffix(name, ".cache") || strings.HasSuffix(name, ".meta") || strings.HasPrefix(name, ".tmp-") {
+		if strings.HasSuffix(name, ".cache") || strings.HasSuffix(name, ".meta") ||
+			strings.HasSuffix(name, corruptSuffix) || strings.HasPrefix(name, ".tmp-") {
 			_ = os.Remove(filepath.Join(s.cfg.Dir, name))
 		}
 	}
@@ -320,6 +351,65 @@ func (s *Store) Clear() error {
 	return nil
 }
 
+// Compact removes every entry whose key is not in keep, along with
+// quarantined .corrupt files and temporary files left by interrupted
+// writes, and returns the names of the files it removed. Only files in the
+// store's own format are considered; anything else in the directory is
+// left alone.
+func (s *Store) Compact(keep []string) ([]string, error) {
+	active := make(map[string]bool, len(keep))
+	for _, k := range keep {
+		active[hashKey(k)] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.cfg.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cache: compact read dir: %w", err)
+	}
+
+	var removed []string
+	remove := func(name string) {
+		if err := os.Remove(filepath.Join(s.cfg.Dir, name)); err == nil {
+			removed = append(removed, name)
+		}
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(name, corruptSuffix), strings.HasPrefix(name, ".tmp-"):
+			slog.Info("cache: removed leftover file", "file", name)
+			remove(name)
+		case strings.HasSuffix(name, ".meta"):
+			hash := strings.TrimSuffix(name, ".meta")
+			if active[hash] {
+				continue
+			}
+			key := hash
+			if meta, err := s.readMeta(hash); err == nil {
+				key = meta.Key
+			}
+			if elem, ok := s.items[hash]; ok {
+				s.removeLocked(hash, elem)
+				removed = append(removed, hash+".cache", name)
+			} else {
+				remove(hash + ".cache")
+				remove(name)
+			}
+			slog.Info("cache: removed unused entry", "key", key)
+		}
+	}
+	return removed, nil
+}
+
 // Size returns the current total size of cached data in bytes.
 func (s *Store) Size() int64 {
 	s.mu.RLock()
@@ -350,6 +440,54 @@ func (s *Store) Close() error {
 	return nil
 }
 
+// corruptSuffix is appended to the data file of a quarantined entry.
+const corruptSuffix = ".corrupt"
+
+// Quarantine removes key from the cache because its contents could not be
+// decoded. The data file is renamed to {hash}.cache.corrupt rather than
+// deleted so it can be inspected later; the next read is a cache miss.
+func (s *Store) Quarantine(key string, reason error) error {
+	h := hashKey(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.quarantineLocked(h, key, reason)
+}
+
+// QuarantineIfUnchanged is Quarantine for an entry that was read as data:
+// the entry is only quarantined while its data file still holds exactly
+// those bytes, so a value rewritten since the read is left alone. It
+// reports whether the entry was quarantined.
+func (s *Store) QuarantineIfUnchanged(key string, data []byte, reason error) (bool, error) {
+	h := hashKey(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, err := os.ReadFile(s.dataPath(h))
+	if err != nil || !bytes.Equal(current, data) {
+		return false, nil
+	}
+	return true, s.quarantineLocked(h, key, reason)
+}
+
+// quarantineLocked implements Quarantine. Caller must hold s.mu write lock.
+func (s *Store) quarantineLocked(hash, key string, reason error) error {
+	if elem, ok := s.items[hash]; ok {
+		entry := elem.Value.(*lruEntry)
+		s.curSize -= entry.size
+		s.lru.Remove(elem)
+		delete(s.items, hash)
+	}
+	_ = os.Remove(s.metaPath(hash))
+
+	dest := s.dataPath(hash) + corruptSuffix
+	if err := os.Rename(s.dataPath(hash), dest); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cache: quarantine %q: %w", key, err)
+	}
+	slog.Warn("cache: quarantined corrupt entry", "key", key, "reason", reason, "file", filepath.Base(dest))
+	return nil
+}
+
 // --- internal helpers ---
 
 func (s *Store) dataPath(hash string) string {
@@ -534,8 +672,9 @@ func (s *Store) sweepExpired() {
 	}
 }
 
-// atomicWrite writes data to path via a temporary file and rename.
-func atomicWrite(path string, data []byte, tmpDir string) error {
+// atomicWrite writes data to path with the given permission via a
+// temporary file and rename.
+func atomicWrite(path string, data []byte, tmpDir string, mode os.FileMode) error {
 	tmp, err := os.CreateTemp(tmpDir, ".tmp-*")
 	if err != nil {
 		return err
@@ -549,11 +688,23 @@ func atomicWrite(path string, data []byte, tmpDir string) error {
 		}
 	}()
 
+	if err := tmp.Chmod(mode); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
 	if _, err := tmp.Write(data); err != nil {
 		_ = tmp.Close()
 		return err
 	}
 
+	// Flush before the rename so a crash cannot leave a renamed but empty
+	// or truncated file behind.
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
 	if err := tmp.Close(); err != nil {
 		return err
 	}
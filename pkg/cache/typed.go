@@ -2,21 +2,36 @@ package cache
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 )
 
 // GetTyped deserializes a cached JSON value into the given type T.
 // Returns the zero value of T and false if the key is missing, expired,
-// or the stored data is not valid JSON for type T.
+// or the stored data is not valid JSON for type T. Entries that are not
+// JSON at all are quarantined so later reads do not keep failing on the
+// same bytes; valid JSON of another shape, such as one written by a newer
+// version, is left for the readers that understand it.
 func GetTyped[T any](s *Store, key string) (T, bool) {
-	data, ok := s.Get(key)
+	return GetTypedWithTTL[T](s, key, 0)
+}
+
+// GetTypedWithTTL is like GetTyped but treats entries older than ttl as
+// missing, so each collector's data can be held to its own staleness
+// cutoff. A non-positive ttl applies only the entry's stored TTL.
+func GetTypedWithTTL[T any](s *Store, key string, ttl time.Duration) (T, bool) {
+	data, ok := s.GetWithMaxAge(key, ttl)
 	if !ok {
 		var zero T
 		return zero, false
 	}
 	var v T
 	if err := json.Unmarshal(data, &v); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			_, _ = s.QuarantineIfUnchanged(key, data, err)
+		}
 		var zero T
 		return zero, false
 	}
@@ -2,9 +2,13 @@ package billing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/anomaly"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
 )
 
 // Default configuration values.
@@ -12,6 +16,12 @@ const (
 	DefaultInterval = 15 * time.Minute
 )
 
+// Provider billing consoles, reported as ProviderBilling.DashboardURL.
+const (
+	civoDashboardURL = "https://dashboard.civo.com/billing"
+	doDashboardURL   = "https://cloud.digitalocean.com/account/billing"
+)
+
 // civoFallbackPricing contains known CIVO instance type monthly costs.
 // Used when both the cluster API and sizes API return $0.
 var civoFallbackPricing = map[string]float64{
@@ -45,26 +55,66 @@ type Config struct {
 	// BudgetUSD is the monthly budget for percentage calculation. Zero means
 	// no budget is set, and BudgetPercent will be 0 in the report.
 	BudgetUSD float64
+
+	// ProjectedOverage enables the projected-overage check: while spend is
+	// still under BudgetUSD but the month-end forecast exceeds it by at
+	// least ProjectedOverageThreshold percent of the budget, the report's
+	// ProjectedOverBudget is set.
+	ProjectedOverage          bool
+	ProjectedOverageThreshold float64
 }
 
 // CivoConfig holds authentication details for the Civo API.
 type CivoConfig struct {
 	APIKey string
 	Region string
+
+	// CreditsUSD is the monthly credit subtracted from Civo spend for
+	// NetSpendUSD. Zero means none.
+	CreditsUSD float64
 }
 
 // DOConfig holds authentication details for the DigitalOcean API.
 type DOConfig struct {
 	APIToken string
+
+	// CreditsUSD is the monthly credit subtracted from DigitalOcean spend
+	// for NetSpendUSD. Zero means none.
+	CreditsUSD float64
 }
 
 // BillingReport is the top-level data returned by Collect.
 type BillingReport struct {
 	Providers       []ProviderBilling `json:"providers"`
 	TotalMonthlyUSD float64           `json:"total_monthly_usd"`
-	BudgetUSD       float64           `json:"budget_usd"`
-	BudgetPercent   float64           `json:"budget_percent"`
-	Timestamp       time.Time         `json:"timestamp"`
+
+	// NetMonthlyUSD is the sum of the providers' NetSpendUSD, the spend
+	// left after monthly credits. BudgetPercent and BudgetPace are
+	// computed from it; TotalMonthlyUSD stays gross.
+	NetMonthlyUSD float64 `json:"net_monthly_usd"`
+
+	BudgetUSD     float64   `json:"budget_usd"`
+	BudgetPercent float64   `json:"budget_percent"`
+	BudgetPace    string    `json:"budget_pace,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+
+	// PreviousMonthUSD is last month's total as last observed by the
+	// daemon. Nil when the daemon was not running at the end of last month.
+	PreviousMonthUSD *float64 `json:"previous_month_usd,omitempty"`
+
+	// Anomaly is set by the daemon when the latest day's spend is an
+	// outlier against the recorded daily history.
+	Anomaly *anomaly.Anomaly `json:"anomaly,omitempty"`
+
+	// ForecastUSD is the month-end spend after credits: each connected
+	// provider's gross spend extrapolated linearly to the end of the
+	// month, less its credits (see NetForecast), summed. It is only set
+	// when a budget is configured.
+	ForecastUSD float64 `json:"forecast_usd,omitempty"`
+
+	// ProjectedOverBudget is set when spend is under budget today but the
+	// forecast exceeds it by at least the configured threshold.
+	ProjectedOverBudget bool `json:"projected_over_budget,omitempty"`
 }
 
 // ProviderBilling contains billing data for a single cloud provider.
@@ -75,6 +125,23 @@ type ProviderBilling struct {
 	MonthToDate float64        `json:"month_to_date"`
 	Balance     float64        `json:"balance"`
 	Resources   []ResourceCost `json:"resources"`
+
+	// DashboardURL is the provider's billing console.
+	DashboardURL string `json:"dashboard_url,omitempty"`
+
+	// Region is the provider region the collector is configured for, if
+	// any.
+	Region string `json:"region,omitempty"`
+
+	// CreditsUSD is the configured monthly credit for this provider.
+	CreditsUSD float64 `json:"credits_usd,omitempty"`
+
+	// NetSpendUSD is MonthToDate less CreditsUSD, floored at zero.
+	NetSpendUSD float64 `json:"net_spend_usd"`
+
+	// PreviousMonthUSD is set by the daemon from recorded history; nil when
+	// last month's spend for this provider is unknown.
+	PreviousMonthUSD *float64 `json:"previous_month_usd,omitempty"`
 }
 
 // ResourceCost represents the cost of a single cloud resource.
@@ -93,6 +160,10 @@ type Collector struct {
 	civoClient CivoClient
 	doClient   DOClient
 
+	// circuits skips providers that keep failing (see
+	// SetPartCircuitBreaker).
+	circuits collectors.PartCircuits
+
 	mu      sync.Mutex
 	healthy bool
 }
@@ -183,7 +254,7 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			pb := c.collectCivo(ctx)
+			pb := c.collectProvider(ctx, "civo", civoDashboardURL, c.collectCivo)
 			civoResult = &providerResult{billing: pb}
 		}()
 	}
@@ -193,7 +264,7 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			pb := c.collectDO(ctx)
+			pb := c.collectProvider(ctx, "digitalocean", doDashboardURL, c.collectDO)
 			doResult = &providerResult{billing: pb}
 		}()
 	}
@@ -213,6 +284,7 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 		report.Providers = append(report.Providers, civoResult.billing)
 		if civoResult.billing.Connected {
 			report.TotalMonthlyUSD += civoResult.billing.MonthToDate
+			report.NetMonthlyUSD += civoResult.billing.NetSpendUSD
 		} else {
 			failedCount++
 		}
@@ -223,6 +295,7 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 		report.Providers = append(report.Providers, doResult.billing)
 		if doResult.billing.Connected {
 			report.TotalMonthlyUSD += doResult.billing.MonthToDate
+			report.NetMonthlyUSD += doResult.billing.NetSpendUSD
 		} else {
 			failedCount++
 		}
@@ -235,7 +308,17 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 
 	// Calculate budget percentage.
 	if c.cfg.BudgetUSD > 0 {
-		report.BudgetPercent = (report.TotalMonthlyUSD / c.cfg.BudgetUSD) * 100
+		report.BudgetPercent = (report.NetMonthlyUSD / c.cfg.BudgetUSD) * 100
+		report.BudgetPace = BudgetPace(report.NetMonthlyUSD, c.cfg.BudgetUSD, report.Timestamp)
+		for _, p := range report.Providers {
+			if p.Connected {
+				report.ForecastUSD += NetForecast(p.MonthToDate, p.CreditsUSD, report.Timestamp)
+			}
+		}
+		if c.cfg.ProjectedOverage {
+			_, pct := report.ProjectedOverage()
+			report.ProjectedOverBudget = pct > 0 && pct >= c.cfg.ProjectedOverageThreshold
+		}
 	}
 
 	// Mark unhealthy only if all configured providers failed.
@@ -248,6 +331,49 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 	return report, nil
 }
 
+// SetPartCircuitBreaker opens a provider's circuit after it fails
+// b.Threshold collections in a row, so it is not queried again until
+// b.Cooldown has passed while the other providers keep their schedule.
+func (c *Collector) SetPartCircuitBreaker(b *collectors.CircuitBreaker) {
+	c.circuits.SetBreaker(b)
+}
+
+// PartCircuits returns the circuit state of each provider that has failed
+// since it last connected.
+func (c *Collector) PartCircuits(now time.Time) map[string]collectors.CircuitState {
+	return c.circuits.States(now)
+}
+
+// collectProvider runs collect for the named provider unless its circuit is
+// open, in which case the provider is reported disconnected with the
+// circuit's error and its API is not called.
+func (c *Collector) collectProvider(
+	ctx context.Context,
+	name, dashboardURL string,
+	collect func(context.Context) ProviderBilling,
+) ProviderBilling {
+	now := time.Now()
+	if err := c.circuits.Allow(name, now); err != nil {
+		return ProviderBilling{
+			Name:         name,
+			Resources:    []ResourceCost{},
+			DashboardURL: dashboardURL,
+			Error:        err.Error(),
+		}
+	}
+	pb := collect(ctx)
+	if ctx.Err() != nil {
+		// A cancelled collection says nothing about the provider.
+		return pb
+	}
+	var err error
+	if !pb.Connected {
+		err = errors.New(pb.Error)
+	}
+	c.circuits.Record(name, err, now)
+	return pb
+}
+
 // collectCivo queries the Civo API and returns a ProviderBilling result.
 // It first attempts to use the /v2/charges endpoint for actual spend data.
 // If charges are available and non-empty, sum(TotalCost) is used as the
@@ -255,8 +381,13 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 // runs to populate the Resources breakdown regardless of charges availability.
 func (c *Collector) collectCivo(ctx context.Context) ProviderBilling {
 	pb := ProviderBilling{
-		Name:      "civo",
-		Resources: []ResourceCost{},
+		Name:         "civo",
+		Resources:    []ResourceCost{},
+		DashboardURL: civoDashboardURL,
+	}
+	if c.cfg.Civo != nil {
+		pb.Region = c.cfg.Civo.Region
+		pb.CreditsUSD = c.cfg.Civo.CreditsUSD
 	}
 
 	// Try charges API first for actual spend data.
@@ -327,6 +458,7 @@ func (c *Collector) collectCivo(ctx context.Context) ProviderBilling {
 		pb.MonthToDate = estimatedTotal
 	}
 
+	pb.NetSpendUSD = NetSpend(pb.MonthToDate, pb.CreditsUSD)
 	pb.Connected = true
 	return pb
 }
@@ -372,8 +504,12 @@ func (c *Collector) estimateClusterCost(cluster CivoK8sCluster, sizePrices map[s
 // collectDO queries the DigitalOcean API and returns a ProviderBilling result.
 func (c *Collector) collectDO(ctx context.Context) ProviderBilling {
 	pb := ProviderBilling{
-		Name:      "digitalocean",
-		Resources: []ResourceCost{},
+		Name:         "digitalocean",
+		Resources:    []ResourceCost{},
+		DashboardURL: doDashboardURL,
+	}
+	if c.cfg.DigitalOcean != nil {
+		pb.CreditsUSD = c.cfg.DigitalOcean.CreditsUSD
 	}
 
 	// Fetch account balance (month-to-date and credits).
@@ -436,6 +572,7 @@ func (c *Collector) collectDO(ctx context.Context) ProviderBilling {
 		}
 	}
 
+	pb.NetSpendUSD = NetSpend(pb.MonthToDate, pb.CreditsUSD)
 	pb.Connected = true
 	return pb
 }
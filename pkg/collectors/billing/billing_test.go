@@ -4,8 +4,13 @@ import (
 	"context"
 	"errors"
 	"math"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/anomaly"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
 )
 
 // ---------------------------------------------------------------------------
@@ -206,6 +211,9 @@ func TestCollect_CivoOnly(t *testing.T) {
 	if prov.Name != "civo" {
 		t.Errorf("Provider.Name = %q, want %q", prov.Name, "civo")
 	}
+	if prov.DashboardURL != civoDashboardURL {
+		t.Errorf("Provider.DashboardURL = %q, want %q", prov.DashboardURL, civoDashboardURL)
+	}
 	if !prov.Connected {
 		t.Error("Provider.Connected = false, want true")
 	}
@@ -470,6 +478,50 @@ func TestCollect_BudgetPercentage(t *testing.T) {
 	if !floatEqual(report.BudgetPercent, expectedPercent) {
 		t.Errorf("BudgetPercent = %f, want %f", report.BudgetPercent, expectedPercent)
 	}
+	if report.ForecastUSD < report.TotalMonthlyUSD {
+		t.Errorf("ForecastUSD = %f, want at least month-to-date %f", report.ForecastUSD, report.TotalMonthlyUSD)
+	}
+}
+
+func TestCollect_Credits(t *testing.T) {
+	tests := []struct {
+		name             string
+		gross, credits   float64
+		wantNet, wantPct float64
+	}{
+		{"credits cover spend", 30, 50, 0, 0},
+		{"spend beyond credits", 60, 50, 10, 10},
+		{"no credits", 60, 0, 60, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			civo := buildCivoMock()
+			civo.charges = &CivoChargesResponse{Items: []CivoCharge{{Code: "k8s", TotalCost: tt.gross}}}
+			c := newWithClients(Config{
+				Civo:      &CivoConfig{APIKey: "key", CreditsUSD: tt.credits},
+				BudgetUSD: 100,
+			}, civo, nil)
+
+			result, err := c.Collect(context.Background())
+			if err != nil {
+				t.Fatalf("Collect() error: %v", err)
+			}
+			report := result.(*BillingReport)
+			p := report.Providers[0]
+			if !floatEqual(p.MonthToDate, tt.gross) || !floatEqual(p.NetSpendUSD, tt.wantNet) {
+				t.Errorf("civo spend = (gross %v, net %v), want (%v, %v)", p.MonthToDate, p.NetSpendUSD, tt.gross, tt.wantNet)
+			}
+			if !floatEqual(report.TotalMonthlyUSD, tt.gross) || !floatEqual(report.NetMonthlyUSD, tt.wantNet) {
+				t.Errorf("report spend = (gross %v, net %v), want (%v, %v)", report.TotalMonthlyUSD, report.NetMonthlyUSD, tt.gross, tt.wantNet)
+			}
+			if !floatEqual(report.BudgetPercent, tt.wantPct) {
+				t.Errorf("BudgetPercent = %v, want %v", report.BudgetPercent, tt.wantPct)
+			}
+			if got := report.HasCredits(); got != (tt.credits > 0) {
+				t.Errorf("HasCredits() = %v, want %v", got, tt.credits > 0)
+			}
+		})
+	}
 }
 
 func TestCollect_ZeroBudget(t *testing.T) {
@@ -493,6 +545,89 @@ func TestCollect_ZeroBudget(t *testing.T) {
 	if report.BudgetUSD != 0 {
 		t.Errorf("BudgetUSD = %f, want 0", report.BudgetUSD)
 	}
+	if report.BudgetPace != "" {
+		t.Errorf("BudgetPace = %q, want empty (no budget set)", report.BudgetPace)
+	}
+}
+
+func TestBudgetPace(t *testing.T) {
+	mid := time.Date(2026, time.June, 15, 12, 0, 0, 0, time.UTC) // day 15 of 30
+	tests := []struct {
+		name   string
+		spent  float64
+		budget float64
+		now    time.Time
+		want   string
+	}{
+		{"zero budget", 50, 0, mid, ""},
+		{"on track mid-month", 100, 200, mid, PaceOnTrack},
+		{"over pace mid-month", 150, 200, mid, PaceOverPace},
+		{"ahead mid-month", 40, 200, mid, PaceAhead},
+		{"first day small spend", 5, 300, time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC), PaceOnTrack},
+		{"first day heavy spend", 60, 300, time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC), PaceOverPace},
+		{"last day full budget", 200, 200, time.Date(2026, time.February, 28, 23, 0, 0, 0, time.UTC), PaceOnTrack},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BudgetPace(tt.spent, tt.budget, tt.now); got != tt.want {
+				t.Errorf("BudgetPace(%v, %v) = %q, want %q", tt.spent, tt.budget, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBillingReport_ProjectedOverage(t *testing.T) {
+	tests := []struct {
+		name                    string
+		spent, forecast, budget float64
+		wantUSD, wantPercent    float64
+	}{
+		{"no budget", 50, 150, 0, 0, 0},
+		{"forecast within budget", 50, 95, 100, 0, 0},
+		{"projected over", 60, 120, 100, 20, 20},
+		{"already over", 110, 220, 100, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &BillingReport{TotalMonthlyUSD: tt.spent, NetMonthlyUSD: tt.spent, ForecastUSD: tt.forecast, BudgetUSD: tt.budget}
+			usd, pct := r.ProjectedOverage()
+			if !floatEqual(usd, tt.wantUSD) || !floatEqual(pct, tt.wantPercent) {
+				t.Errorf("ProjectedOverage() = (%v, %v), want (%v, %v)", usd, pct, tt.wantUSD, tt.wantPercent)
+			}
+		})
+	}
+}
+
+func TestNetForecast(t *testing.T) {
+	day10 := time.Date(2026, 9, 10, 12, 0, 0, 0, time.UTC) // a third of September
+	tests := []struct {
+		name                 string
+		gross, credits, want float64
+	}{
+		{"no credits", 30, 0, 90},
+		// Credits cover today's spend but not the month's.
+		{"credits ahead of spend", 30, 50, 40},
+		{"credits cover the month", 10, 50, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NetForecast(tt.gross, tt.credits, day10); !floatEqual(got, tt.want) {
+				t.Errorf("NetForecast(%v, %v) = %v, want %v", tt.gross, tt.credits, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonthProgress_MonthBoundaries(t *testing.T) {
+	if got := MonthProgress(time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC)); got != 1 {
+		t.Errorf("MonthProgress(Feb 28) = %v, want 1", got)
+	}
+	if got := MonthProgress(time.Date(2028, time.February, 28, 0, 0, 0, 0, time.UTC)); !floatEqual(got, 28.0/29.0) {
+		t.Errorf("MonthProgress(leap Feb 28) = %v, want 28/29", got)
+	}
+	if got := MonthProgress(time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)); got != 1 {
+		t.Errorf("MonthProgress(Dec 31) = %v, want 1", got)
+	}
 }
 
 func TestCollect_BothProvidersDisabled(t *testing.T) {
@@ -606,6 +741,64 @@ func TestHealthy_RecoverAfterFailure(t *testing.T) {
 	}
 }
 
+// countingCivoClient counts the Kubernetes queries made through it.
+type countingCivoClient struct {
+	CivoClient
+	calls int
+}
+
+func (c *countingCivoClient) GetKubernetes(ctx context.Context) (*CivoK8sResponse, error) {
+	c.calls++
+	return c.CivoClient.GetKubernetes(ctx)
+}
+
+// countingDOClient counts the balance queries made through it.
+type countingDOClient struct {
+	DOClient
+	calls int
+}
+
+func (c *countingDOClient) GetBalance(ctx context.Context) (*DOBalanceResponse, error) {
+	c.calls++
+	return c.DOClient.GetBalance(ctx)
+}
+
+func TestCollect_FailingProviderBacksOff(t *testing.T) {
+	civo := &countingCivoClient{CivoClient: &mockCivoClient{k8sErr: errors.New("401 revoked key")}}
+	do := &countingDOClient{DOClient: buildDOMock()}
+	c := newWithClients(Config{
+		Civo:         &CivoConfig{APIKey: "key"},
+		DigitalOcean: &DOConfig{APIToken: "token"},
+	}, civo, do)
+	c.SetPartCircuitBreaker(&collectors.CircuitBreaker{Threshold: 2, Cooldown: time.Hour})
+
+	var report *BillingReport
+	for i := 0; i < 5; i++ {
+		result, err := c.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect() error: %v", err)
+		}
+		report = result.(*BillingReport)
+	}
+
+	if civo.calls != 2 {
+		t.Errorf("civo queried %d times, want 2 before its circuit opened", civo.calls)
+	}
+	if do.calls != 5 {
+		t.Errorf("digitalocean queried %d times, want every collection", do.calls)
+	}
+	if got := c.PartCircuits(time.Now()); got["civo"] != collectors.CircuitOpen || len(got) != 1 {
+		t.Errorf("PartCircuits = %v, want only civo open", got)
+	}
+	civoProv := report.Providers[0]
+	if civoProv.Connected || !strings.Contains(civoProv.Error, "circuit open") || civoProv.DashboardURL == "" {
+		t.Errorf("skipped civo = %+v, want disconnected with the circuit error", civoProv)
+	}
+	if !floatEqual(report.TotalMonthlyUSD, 45.67) || !c.Healthy() {
+		t.Errorf("TotalMonthlyUSD = %f, healthy %v; want DO's 45.67, healthy", report.TotalMonthlyUSD, c.Healthy())
+	}
+}
+
 func TestCollect_ContextCancellation(t *testing.T) {
 	civo := buildCivoMock()
 	c := newWithClients(Config{
@@ -1125,3 +1318,161 @@ func TestCollect_CivoEmptyChargesFallback(t *testing.T) {
 		t.Errorf("MonthToDate = %f, want 25.00 (empty charges = fallback)", prov.MonthToDate)
 	}
 }
+
+func spendReport(ts time.Time, mtd float64) *BillingReport {
+	return &BillingReport{
+		Providers:       []ProviderBilling{{Name: "civo", Connected: true, MonthToDate: mtd}},
+		TotalMonthlyUSD: mtd,
+		Timestamp:       ts,
+	}
+}
+
+func TestSpendHistory_DailyDeltas(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2026, 3, d, h, 0, 0, 0, time.Local) }
+	h := &SpendHistory{}
+
+	h.Record(spendReport(day(10, 9), 100)) // seed only
+	if len(h.Days) != 0 {
+		t.Fatalf("first report should only seed, got %+v", h.Days)
+	}
+	h.Record(spendReport(day(10, 18), 104))
+	h.Record(spendReport(day(11, 9), 110))
+	// Three days off: the increase is spread over Mar 12-14.
+	h.Record(spendReport(day(14, 9), 125))
+	// Outage: skipped entirely.
+	h.Record(&BillingReport{Providers: []ProviderBilling{{Name: "civo"}}, Timestamp: day(14, 12)})
+
+	want := map[string]float64{"2026-03-10": 4, "2026-03-11": 6, "2026-03-12": 5, "2026-03-13": 5, "2026-03-14": 5}
+	if len(h.Days) != len(want) {
+		t.Fatalf("days = %+v", h.Days)
+	}
+	for _, d := range h.Days {
+		if diff := d.SpendUSD - want[d.Date]; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s spend = %v, want %v", d.Date, d.SpendUSD, want[d.Date])
+		}
+	}
+}
+
+func TestSpendHistory_PartialOutage(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2026, 3, 10, h, 0, 0, 0, time.Local) }
+	report := func(h int, civo, do float64, doUp bool) *BillingReport {
+		r := &BillingReport{
+			Providers: []ProviderBilling{
+				{Name: "civo", Connected: true, MonthToDate: civo},
+				{Name: "digitalocean", Connected: doUp},
+			},
+			TotalMonthlyUSD: civo,
+			Timestamp:       at(h),
+		}
+		if doUp {
+			r.Providers[1].MonthToDate = do
+			r.TotalMonthlyUSD += do
+		}
+		return r
+	}
+	h := &SpendHistory{}
+
+	h.Record(report(8, 100, 50, true)) // seed
+	h.Record(report(9, 101, 51, true)) // +2
+	// DigitalOcean drops out: Civo's +2 counts, not DO's -$51.
+	h.Record(report(10, 103, 0, false))
+	// It comes back: Civo's +1 counts, not DO's $55 jump.
+	h.Record(report(11, 104, 55, true))
+	// Connected in both reports, DO counts again: +1 and +2.
+	h.Record(report(12, 105, 57, true))
+
+	if len(h.Days) != 1 {
+		t.Fatalf("days = %+v, want only Mar 10", h.Days)
+	}
+	if got := h.Days[0].SpendUSD; math.Abs(got-8) > 1e-9 {
+		t.Errorf("spend = %v, want 8 without the outage and recovery", got)
+	}
+}
+
+func TestSpendHistory_MonthRollover(t *testing.T) {
+	h := &SpendHistory{}
+	h.Record(spendReport(time.Date(2026, 3, 31, 20, 0, 0, 0, time.Local), 300))
+	h.Record(spendReport(time.Date(2026, 4, 1, 8, 0, 0, 0, time.Local), 7))
+	if len(h.Days) != 1 || h.Days[0].Date != "2026-04-01" || h.Days[0].SpendUSD != 7 {
+		t.Errorf("rollover days = %+v, want Apr 1 at $7", h.Days)
+	}
+}
+
+func TestSpendHistory_PreviousMonth(t *testing.T) {
+	h := &SpendHistory{}
+	h.Record(spendReport(time.Date(2026, 3, 30, 20, 0, 0, 0, time.Local), 280))
+	h.Record(spendReport(time.Date(2026, 3, 31, 20, 0, 0, 0, time.Local), 300))
+	h.Record(spendReport(time.Date(2026, 4, 1, 8, 0, 0, 0, time.Local), 7))
+
+	if h.PreviousMonth == nil || h.PreviousMonth.Month != "2026-03" || h.PreviousMonth.TotalUSD != 300 {
+		t.Fatalf("PreviousMonth = %+v, want March at $300", h.PreviousMonth)
+	}
+
+	report := spendReport(time.Date(2026, 4, 2, 8, 0, 0, 0, time.Local), 12)
+	report.Providers = append(report.Providers, ProviderBilling{Name: "digitalocean", Connected: true, MonthToDate: 3})
+	h.ApplyPreviousMonth(report)
+	if report.PreviousMonthUSD == nil || *report.PreviousMonthUSD != 300 {
+		t.Errorf("report PreviousMonthUSD = %v, want 300", report.PreviousMonthUSD)
+	}
+	if p := report.Providers[0].PreviousMonthUSD; p == nil || *p != 300 {
+		t.Errorf("civo PreviousMonthUSD = %v, want 300", p)
+	}
+	if p := report.Providers[1].PreviousMonthUSD; p != nil {
+		t.Errorf("digitalocean was not seen last month, got %v", *p)
+	}
+
+	// A month later the stored totals are no longer "previous".
+	stale := spendReport(time.Date(2026, 5, 2, 8, 0, 0, 0, time.Local), 12)
+	h.ApplyPreviousMonth(stale)
+	if stale.PreviousMonthUSD != nil {
+		t.Errorf("stale previous month applied: %v", *stale.PreviousMonthUSD)
+	}
+}
+
+func TestSpendHistory_SaveLoad(t *testing.T) {
+	path := SpendHistoryPath(t.TempDir())
+	h, err := LoadSpendHistory(path)
+	if err != nil || len(h.Days) != 0 {
+		t.Fatalf("missing file should load empty, got %+v, %v", h, err)
+	}
+	h.Record(spendReport(time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local), 1))
+	h.Record(spendReport(time.Date(2026, 3, 10, 10, 0, 0, 0, time.Local), 3))
+	if err := h.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadSpendHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Days) != 1 || got.Days[0].SpendUSD != 2 || got.LastMTD != 3 {
+		t.Errorf("round trip = %+v", got)
+	}
+}
+
+func TestSpendHistory_MonthCurves(t *testing.T) {
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.Local)
+	h := &SpendHistory{Days: []anomaly.DailySpend{
+		{Date: "2026-02-01", SpendUSD: 1},
+		{Date: "2026-02-27", SpendUSD: 2},
+		{Date: "2026-03-01", SpendUSD: 3},
+		{Date: "2026-03-03", SpendUSD: 4},
+	}}
+	current, baseline := h.MonthCurves(now)
+	if want := []float64{3, 3, 7, 7}; !reflect.DeepEqual(current, want) {
+		t.Errorf("current = %v, want %v", current, want)
+	}
+	if len(baseline) != 28 || baseline[0] != 1 || baseline[25] != 1 || baseline[26] != 3 || baseline[27] != 3 {
+		t.Errorf("baseline = %v, want February's 28 days reaching $3", baseline)
+	}
+
+	// History starting after February 1st is no baseline.
+	h.Days = h.Days[1:]
+	if _, baseline := h.MonthCurves(now); baseline != nil {
+		t.Errorf("partial previous month gave baseline %v", baseline)
+	}
+
+	// Nothing recorded this month yet.
+	if current, _ := h.MonthCurves(time.Date(2026, 4, 2, 9, 0, 0, 0, time.Local)); current != nil {
+		t.Errorf("month without spend gave current %v", current)
+	}
+}
@@ -0,0 +1,267 @@
+package billing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/anomaly"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
+)
+
+// SpendHistoryCacheKey is the cache key (file name without .json) under
+// which the daemon persists daily spend derived from billing reports.
+const SpendHistoryCacheKey = "billing-history"
+
+// spendHistoryDays is how many days of spend are kept. Two 31-day months
+// hold all of the previous month alongside the current one for
+// MonthCurves, which also covers the anomaly baseline window.
+const spendHistoryDays = 62
+
+// monthLayout is the format of MonthTotals.Month.
+const monthLayout = "2006-01"
+
+// SpendHistory turns successive month-to-date totals into per-day spend.
+// Each report's increase over the previous one is attributed to the day of
+// the newer report; when reports are more than a day apart the increase is
+// spread evenly over the days in between, so a machine that was off over a
+// weekend does not produce a false spike on Monday. The increase is taken
+// per provider, over the providers connected in both reports, so a
+// provider dropping out and coming back does not look like spend.
+type SpendHistory struct {
+	Days []anomaly.DailySpend `json:"days"`
+
+	// LastMTD and LastTimestamp are the previous report's total and time.
+	// The first report only seeds them, since the spend before it is
+	// unknown.
+	LastMTD       float64   `json:"last_mtd"`
+	LastTimestamp time.Time `json:"last_timestamp"`
+
+	// LastProviders holds the month-to-date total of each provider that
+	// was connected in the previous report. When the month rolls over these
+	// become PreviousMonth.
+	LastProviders map[string]float64 `json:"last_providers,omitempty"`
+	PreviousMonth *MonthTotals       `json:"previous_month,omitempty"`
+}
+
+// MonthTotals is the last observed spend of a finished month.
+type MonthTotals struct {
+	Month     string             `json:"month"` // "2006-01"
+	TotalUSD  float64            `json:"total_usd"`
+	Providers map[string]float64 `json:"providers"`
+}
+
+// SpendHistoryPath returns the path of the spend history file in cacheDir.
+func SpendHistoryPath(cacheDir string) string {
+	return filepath.Join(cacheDir, SpendHistoryCacheKey+".json")
+}
+
+// LoadSpendHistory reads a SpendHistory from path. A missing file yields an
+// empty history and no error.
+func LoadSpendHistory(path string) (*SpendHistory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &SpendHistory{}, nil
+		}
+		return nil, err
+	}
+	h := &SpendHistory{}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("billing: parse spend history: %w", err)
+	}
+	return h, nil
+}
+
+// Save writes the history to path via a temporary file and rename.
+func (h *SpendHistory) Save(path string) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("billing: marshal spend history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), cache.DirMode); err != nil {
+		return fmt.Errorf("billing: create spend history dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, cache.FileMode); err != nil {
+		return fmt.Errorf("billing: write spend history: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Record folds a report's month-to-date total into the daily series.
+// Reports with no connected provider are skipped so an outage does not
+// look like the month resetting.
+func (h *SpendHistory) Record(report *BillingReport) {
+	if report == nil || !report.anyConnected() {
+		return
+	}
+	ts := report.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	ts = ts.Local()
+	mtd := report.TotalMonthlyUSD
+
+	if h.LastTimestamp.IsZero() || !ts.After(h.LastTimestamp) {
+		if h.LastTimestamp.IsZero() {
+			h.LastMTD, h.LastTimestamp = mtd, ts
+			h.recordProviders(report)
+		}
+		return
+	}
+
+	last := h.LastTimestamp.Local()
+	var delta float64
+	if ts.Year() != last.Year() || ts.Month() != last.Month() {
+		// The provider totals restarted at the beginning of the month.
+		delta = mtd
+		h.PreviousMonth = &MonthTotals{
+			Month:     last.Format(monthLayout),
+			TotalUSD:  h.LastMTD,
+			Providers: h.LastProviders,
+		}
+	} else {
+		delta = h.providerDelta(report)
+	}
+
+	days := spanDays(last, ts)
+	share := delta / float64(len(days))
+	for _, d := range days {
+		h.add(d, share)
+	}
+	h.LastMTD, h.LastTimestamp = mtd, ts
+	h.recordProviders(report)
+
+	if len(h.Days) > spendHistoryDays {
+		h.Days = append([]anomaly.DailySpend(nil), h.Days[len(h.Days)-spendHistoryDays:]...)
+	}
+}
+
+// providerDelta returns the month-to-date increase since the previous
+// report of the providers connected in both. A provider missing from either
+// contributes nothing: its total is unknown on one side, and counting it
+// would turn an outage into a drop and the recovery into a spike.
+func (h *SpendHistory) providerDelta(report *BillingReport) float64 {
+	delta := 0.0
+	for _, p := range report.Providers {
+		last, ok := h.LastProviders[p.Name]
+		if !p.Connected || !ok {
+			continue
+		}
+		// Credits and corrections lower the total; they are not spend.
+		if d := p.MonthToDate - last; d > 0 {
+			delta += d
+		}
+	}
+	return delta
+}
+
+// recordProviders remembers the month-to-date total of each provider
+// connected in report, forgetting those that are not.
+func (h *SpendHistory) recordProviders(report *BillingReport) {
+	h.LastProviders = make(map[string]float64, len(report.Providers))
+	for _, p := range report.Providers {
+		if p.Connected {
+			h.LastProviders[p.Name] = p.MonthToDate
+		}
+	}
+}
+
+// ApplyPreviousMonth fills in the report's previous-month totals when the
+// history holds the month before the report's. Providers that were not
+// seen last month are left without one.
+func (h *SpendHistory) ApplyPreviousMonth(report *BillingReport) {
+	if h.PreviousMonth == nil || report == nil {
+		return
+	}
+	ts := report.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	ts = ts.Local()
+	prev := time.Date(ts.Year(), ts.Month()-1, 1, 0, 0, 0, 0, ts.Location())
+	if h.PreviousMonth.Month != prev.Format(monthLayout) {
+		return
+	}
+	total := h.PreviousMonth.TotalUSD
+	report.PreviousMonthUSD = &total
+	for i := range report.Providers {
+		if v, ok := h.PreviousMonth.Providers[report.Providers[i].Name]; ok {
+			report.Providers[i].PreviousMonthUSD = &v
+		}
+	}
+}
+
+// MonthCurves returns the cumulative spend of now's month through today,
+// one value per day of the month, and the same curve for the whole of the
+// previous month as a baseline to compare it with. Days without recorded
+// spend add nothing, and current is nil when nothing has been recorded
+// this month. The baseline is nil unless the history reaches back
+// to the first of the previous month, since a partial month would look
+// cheaper than it was.
+func (h *SpendHistory) MonthCurves(now time.Time) (current, baseline []float64) {
+	now = now.Local()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	prev := start.AddDate(0, -1, 0)
+
+	if n := len(h.Days); n == 0 || h.Days[n-1].Date < start.Format(anomaly.DateLayout) {
+		return nil, nil
+	}
+	spend := make(map[string]float64, len(h.Days))
+	for _, d := range h.Days {
+		spend[d.Date] = d.SpendUSD
+	}
+	curve := func(from time.Time, days int) []float64 {
+		out := make([]float64, days)
+		total := 0.0
+		for i := range out {
+			total += spend[from.AddDate(0, 0, i).Format(anomaly.DateLayout)]
+			out[i] = total
+		}
+		return out
+	}
+
+	current = curve(start, now.Day())
+	if h.Days[0].Date <= prev.Format(anomaly.DateLayout) {
+		baseline = curve(prev, start.AddDate(0, 0, -1).Day())
+	}
+	return current, baseline
+}
+
+// add credits usd to date, appending a new day when needed.
+func (h *SpendHistory) add(date string, usd float64) {
+	if n := len(h.Days); n > 0 && h.Days[n-1].Date == date {
+		h.Days[n-1].SpendUSD += usd
+		return
+	}
+	h.Days = append(h.Days, anomaly.DailySpend{Date: date, SpendUSD: usd})
+}
+
+// spanDays returns the dates a report interval (from, to] is attributed to:
+// just to's date within a day, otherwise every date after from's through
+// to's.
+func spanDays(from, to time.Time) []string {
+	end := to.Format(anomaly.DateLayout)
+	if from.Format(anomaly.DateLayout) == end {
+		return []string{end}
+	}
+	var days []string
+	d := time.Date(from.Year(), from.Month(), from.Day()+1, 0, 0, 0, 0, to.Location())
+	for ; d.Format(anomaly.DateLayout) != end; d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format(anomaly.DateLayout))
+	}
+	return append(days, end)
+}
+
+// anyConnected reports whether at least one provider returned data.
+func (r *BillingReport) anyConnected() bool {
+	for _, p := range r.Providers {
+		if p.Connected {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package billing
+
+import "time"
+
+// Budget pace classifications reported in BillingReport.BudgetPace.
+const (
+	// PaceAhead means spend is below the linear month-to-date target.
+	PaceAhead = "ahead"
+
+	// PaceOnTrack means spend is within paceTolerance of the target.
+	PaceOnTrack = "on-track"
+
+	// PaceOverPace means spend is above the linear month-to-date target.
+	PaceOverPace = "over-pace"
+)
+
+// paceTolerance is the band around the ideal spend, as a fraction of the
+// full budget, that still counts as on-track. Five percent keeps small
+// day-to-day fluctuations from flipping the indicator.
+const paceTolerance = 0.05
+
+// BudgetPace compares spent against the ideal linear spend for now's day of
+// the month and returns PaceAhead, PaceOnTrack, or PaceOverPace. Day d of an
+// n-day month has an ideal spend of budget*d/n, so the first day already
+// allows 1/n of the budget. It returns "" when budget is not positive.
+func BudgetPace(spent, budget float64, now time.Time) string {
+	if budget <= 0 {
+		return ""
+	}
+	ideal := budget * MonthProgress(now)
+	diff := (spent - ideal) / budget
+	switch {
+	case diff > paceTolerance:
+		return PaceOverPace
+	case diff < -paceTolerance:
+		return PaceAhead
+	default:
+		return PaceOnTrack
+	}
+}
+
+// Forecast extrapolates spent linearly to the end of now's month.
+func Forecast(spent float64, now time.Time) float64 {
+	return spent / MonthProgress(now)
+}
+
+// NetForecast forecasts gross spend to the end of now's month and then
+// subtracts the month's credits. Credits are a fixed amount per month, so
+// extrapolating net spend instead would scale them with spend.
+func NetForecast(gross, credits float64, now time.Time) float64 {
+	return NetSpend(Forecast(gross, now), credits)
+}
+
+// NetSpend returns gross spend less a monthly credit, floored at zero: credits
+// cover spend but do not carry over as negative spend.
+func NetSpend(gross, credits float64) float64 {
+	return max(gross-credits, 0)
+}
+
+// HasCredits reports whether any connected provider has monthly credits
+// configured, so NetMonthlyUSD differs in meaning from TotalMonthlyUSD.
+func (r *BillingReport) HasCredits() bool {
+	for _, p := range r.Providers {
+		if p.Connected && p.CreditsUSD > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectedOverage returns how far ForecastUSD exceeds the budget, in
+// dollars and as a percentage of BudgetUSD. Both are zero when no budget is
+// set, the forecast is within budget, or net spend is already at or over
+// the budget, which BudgetPercent reports on its own.
+func (r *BillingReport) ProjectedOverage() (usd, percent float64) {
+	if r.BudgetUSD <= 0 || r.NetMonthlyUSD >= r.BudgetUSD || r.ForecastUSD <= r.BudgetUSD {
+		return 0, 0
+	}
+	usd = r.ForecastUSD - r.BudgetUSD
+	return usd, usd / r.BudgetUSD * 100
+}
+
+// MonthProgress returns the fraction of now's calendar month covered through
+// the end of the current day, in (0, 1].
+func MonthProgress(now time.Time) float64 {
+	days := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location()).Day()
+	return float64(now.Day()) / float64(days)
+}
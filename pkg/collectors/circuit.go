@@ -0,0 +1,186 @@
+package collectors
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultCircuitThreshold is the number of consecutive failed collections
+// after which a collector's circuit opens when none is configured.
+const DefaultCircuitThreshold = 5
+
+// DefaultCircuitCooldown is how long an open circuit skips its collector
+// before retrying when none is configured.
+const DefaultCircuitCooldown = 10 * time.Minute
+
+// CircuitState is the state of a collector's circuit breaker.
+type CircuitState string
+
+// Circuit states.
+const (
+	// CircuitClosed means the collector runs on its schedule.
+	CircuitClosed CircuitState = "closed"
+
+	// CircuitOpen means the collector failed too often in a row and is
+	// skipped until its cooldown ends.
+	CircuitOpen CircuitState = "open"
+
+	// CircuitHalfOpen means the cooldown has ended: the next collection is
+	// a trial that closes the circuit on success and reopens it on
+	// failure.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreaker stops a collector that keeps failing, such as one with a
+// revoked key, from being run every cycle. After Threshold consecutive
+// failures its circuit opens and scheduled collections are skipped for
+// Cooldown, so its cached data ages out to stale instead of being
+// refreshed; then one collection is tried again. RunOnce is never held
+// back and closes the circuit when it succeeds.
+type CircuitBreaker struct {
+	// Threshold is the number of consecutive failures that opens the
+	// circuit. Zero or less never opens it.
+	Threshold int
+
+	// Cooldown is how long the circuit stays open.
+	Cooldown time.Duration
+}
+
+// Circuit returns the state of the collector's circuit at now.
+func (s CollectorStatus) Circuit(now time.Time) CircuitState {
+	switch {
+	case s.CircuitRetryAt.IsZero():
+		return CircuitClosed
+	case now.Before(s.CircuitRetryAt):
+		return CircuitOpen
+	default:
+		return CircuitHalfOpen
+	}
+}
+
+// SetCircuitBreaker makes the runner skip collectors that keep failing as
+// b describes, or never skip them when b is nil. Registered collectors that
+// implement PartBreaker get the same settings for their parts. It may be
+// called while the runner is running.
+func (r *Runner) SetCircuitBreaker(b *CircuitBreaker) {
+	r.breaker.Store(b)
+	for _, name := range r.registry.List() {
+		if c, ok := r.registry.Get(name); ok {
+			if pb, ok := c.(PartBreaker); ok {
+				pb.SetPartCircuitBreaker(b)
+			}
+		}
+	}
+}
+
+// PartBreaker is implemented by collectors whose parts, such as billing
+// providers or Claude accounts, fail on their own while the collection as
+// a whole succeeds, so the collector's own circuit never opens for them.
+// Such a collector keeps a circuit per part (see PartCircuits).
+type PartBreaker interface {
+	// SetPartCircuitBreaker sets the threshold and cooldown of the part
+	// circuits; nil never opens them.
+	SetPartCircuitBreaker(b *CircuitBreaker)
+
+	// PartCircuits returns the circuit state at now of each part that has
+	// failed since it last succeeded.
+	PartCircuits(now time.Time) map[string]CircuitState
+}
+
+// PartCircuits keeps a circuit per named part of a collector. A part whose
+// circuit is open is not queried until its cooldown ends; the collector
+// reports it as failed meanwhile, and the other parts keep their schedule.
+// The zero value never opens a circuit until SetBreaker is called. It is
+// safe for concurrent use.
+type PartCircuits struct {
+	mu      sync.Mutex
+	breaker *CircuitBreaker
+	parts   map[string]*CollectorStatus
+}
+
+// SetBreaker sets the threshold and cooldown; nil never opens a circuit.
+func (p *PartCircuits) SetBreaker(b *CircuitBreaker) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.breaker = b
+}
+
+// Allow returns nil when part may be queried at now, or, while its circuit
+// is open, an error naming the last failure and when it is retried.
+func (p *PartCircuits) Allow(part string, now time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, ok := p.parts[part]
+	if !ok || s.Circuit(now) != CircuitOpen {
+		return nil
+	}
+	return fmt.Errorf("circuit open after %d failures, retrying at %s: %w",
+		s.ConsecutiveFailures, s.CircuitRetryAt.Format("15:04"), s.LastError)
+}
+
+// Record notes the outcome of querying part at now, failed when err is
+// set, and reports whether that opened its circuit.
+func (p *PartCircuits) Record(part string, err error, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err == nil {
+		delete(p.parts, part)
+		return false
+	}
+	if p.parts == nil {
+		p.parts = make(map[string]*CollectorStatus)
+	}
+	s := p.parts[part]
+	if s == nil {
+		s = &CollectorStatus{Name: part}
+		p.parts[part] = s
+	}
+	s.LastError = err
+	s.ConsecutiveFailures++
+	b := p.breaker
+	if b == nil || b.Threshold <= 0 || s.ConsecutiveFailures < b.Threshold {
+		return false
+	}
+	s.CircuitRetryAt = now.Add(b.Cooldown)
+	return true
+}
+
+// States returns the circuit state at now of each part that has failed
+// since it last succeeded, or nil when none has.
+func (p *PartCircuits) States(now time.Time) map[string]CircuitState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.parts) == 0 {
+		return nil
+	}
+	states := make(map[string]CircuitState, len(p.parts))
+	for name, s := range p.parts {
+		states[name] = s.Circuit(now)
+	}
+	return states
+}
+
+// circuitOpen reports whether the named collector's circuit is open at
+// now, so its scheduled collection should be skipped.
+func (r *Runner) circuitOpen(name string, now time.Time) bool {
+	s, ok := r.registry.Status(name)
+	return ok && s.Circuit(now) == CircuitOpen
+}
+
+// recordCircuit updates the circuit of status s after a collection that
+// failed when failed is set, and reports whether it opened the circuit.
+func (r *Runner) recordCircuit(s *CollectorStatus, failed bool, now time.Time) bool {
+	if !failed {
+		s.ConsecutiveFailures = 0
+		s.CircuitRetryAt = time.Time{}
+		return false
+	}
+	s.ConsecutiveFailures++
+	b := r.breaker.Load()
+	if b == nil || b.Threshold <= 0 || s.ConsecutiveFailures < b.Threshold {
+		return false
+	}
+	s.CircuitRetryAt = now.Add(b.Cooldown)
+	return true
+}
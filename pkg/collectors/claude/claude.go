@@ -2,10 +2,13 @@ package claude
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
 )
 
 // Default configuration values.
@@ -27,11 +30,34 @@ type AccountConfig struct {
 	// Name is a human-readable label (e.g., "personal", "work").
 	Name string
 
+	// ShortName is the label used where space is tight. Empty derives one
+	// from Name (see UsageReport.ShortNames).
+	ShortName string
+
 	// AdminAPIKey is the Anthropic Admin API key for this account.
 	AdminAPIKey string
 
 	// OrganizationID is the Anthropic organization identifier.
 	OrganizationID string
+
+	// BudgetUSD is the monthly budget used to compute utilization. Zero
+	// disables utilization levels for this account.
+	BudgetUSD float64
+
+	// WarnThreshold and CritThreshold are utilization percentages at which
+	// the account is flagged. Zero uses DefaultWarnThreshold and
+	// DefaultCritThreshold.
+	WarnThreshold float64
+	CritThreshold float64
+
+	// Type selects per-account spend (AccountTypeAccount, the zero value's
+	// meaning) or the organization's shared limits (AccountTypeOrg).
+	Type AccountType
+
+	// Tier is the account's plan or API usage tier code, e.g. "max_5x" or
+	// "tier_3". The Admin API does not report it, so it comes from the
+	// config; PlanName turns it into a display name.
+	Tier string
 }
 
 // UsageReport is the top-level data returned by a single Collect call.
@@ -39,11 +65,16 @@ type UsageReport struct {
 	Accounts    []AccountUsage `json:"accounts"`
 	TotalCostUSD float64       `json:"total_cost_usd"`
 	Timestamp   time.Time      `json:"timestamp"`
+
+	// Orgs holds the accounts configured with AccountTypeOrg. Caches
+	// written before it existed decode as empty.
+	Orgs []OrgUsage `json:"orgs,omitempty"`
 }
 
 // AccountUsage holds usage data for a single Anthropic account.
 type AccountUsage struct {
 	Name             string           `json:"name"`
+	ShortName        string           `json:"short_name,omitempty"`
 	OrganizationID   string           `json:"organization_id"`
 	Connected        bool             `json:"connected"`
 	Error            string           `json:"error,omitempty"`
@@ -54,6 +85,23 @@ type AccountUsage struct {
 	DailyBurnRate    float64          `json:"daily_burn_rate"`
 	ProjectedMonthly float64          `json:"projected_monthly"`
 	DaysRemaining    int              `json:"days_remaining"`
+	BudgetUSD        float64          `json:"budget_usd,omitempty"`
+	Utilization      float64          `json:"utilization,omitempty"`
+	WarnThreshold    float64          `json:"warn_threshold,omitempty"`
+	CritThreshold    float64          `json:"crit_threshold,omitempty"`
+
+	// SevenDayCostUSD is the spend over the trailing seven days, including
+	// today. SevenDayUtilization measures it against the monthly budget
+	// pro-rated to a week, so a heavy week shows up before the month does.
+	SevenDayCostUSD     float64 `json:"seven_day_cost_usd"`
+	SevenDayUtilization float64 `json:"seven_day_utilization,omitempty"`
+
+	// ResetsAt is when the monthly budget window restarts: midnight on the
+	// first of next month. Caches written before it existed decode as zero.
+	ResetsAt time.Time `json:"resets_at"`
+
+	// Tier is the configured tier code, unchanged (see PlanName).
+	Tier string `json:"tier,omitempty"`
 }
 
 // MonthUsage aggregates token counts and cost for a calendar month.
@@ -93,6 +141,10 @@ type Collector struct {
 	// nowFunc allows tests to inject a deterministic clock.
 	nowFunc func() time.Time
 
+	// circuits skips accounts that keep failing (see
+	// SetPartCircuitBreaker).
+	circuits collectors.PartCircuits
+
 	mu      sync.Mutex
 	healthy bool
 }
@@ -141,7 +193,8 @@ func (c *Collector) setHealthy(v bool) {
 	c.healthy = v
 }
 
-// Collect queries all configured accounts and returns a UsageReport. Accounts
+// Collect queries all configured accounts and returns a UsageReport, with
+// AccountTypeOrg accounts reported in Orgs. Accounts
 // that fail are marked as disconnected; the collector continues to the next.
 // The collector is healthy as long as at least one account succeeds.
 func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
@@ -156,6 +209,7 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 	now := c.nowFunc()
 	curStart, curEnd := currentMonthRange(now)
 	prevStart, prevEnd := previousMonthRange(now)
+	weekStart := sevenDayStart(now)
 
 	report := &UsageReport{
 		Accounts:  make([]AccountUsage, 0, len(c.accounts)),
@@ -170,7 +224,21 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 			return nil, fmt.Errorf("claude collect: %w", err)
 		}
 
-		au := c.collectAccount(ctx, acct, curStart, curEnd, prevStart, prevEnd)
+		if err := c.circuits.Allow(acct.Name, now); err != nil {
+			appendSkipped(report, acct, err)
+			continue
+		}
+
+		if acct.Type == AccountTypeOrg {
+			ou := c.collectOrg(ctx, acct)
+			c.recordAccount(ctx, acct.Name, ou.Connected, ou.Error, now)
+			anyConnected = anyConnected || ou.Connected
+			report.Orgs = append(report.Orgs, ou)
+			continue
+		}
+
+		au := c.collectAccount(ctx, acct, curStart, curEnd, prevStart, prevEnd, weekStart)
+		c.recordAccount(ctx, acct.Name, au.Connected, au.Error, now)
 		if au.Connected {
 			anyConnected = true
 			c.calculateBurnRate(&au, now)
@@ -183,6 +251,58 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 	return report, nil
 }
 
+// SetPartCircuitBreaker opens an account's circuit after it fails
+// b.Threshold collections in a row, so it is not queried again until
+// b.Cooldown has passed while the other accounts keep their schedule.
+func (c *Collector) SetPartCircuitBreaker(b *collectors.CircuitBreaker) {
+	c.circuits.SetBreaker(b)
+}
+
+// PartCircuits returns the circuit state of each account that has failed
+// since it last connected.
+func (c *Collector) PartCircuits(now time.Time) map[string]collectors.CircuitState {
+	return c.circuits.States(now)
+}
+
+// recordAccount notes the outcome of querying the named account. A
+// cancelled collection says nothing about the account and is not recorded.
+func (c *Collector) recordAccount(ctx context.Context, name string, connected bool, errMsg string, now time.Time) {
+	if ctx.Err() != nil {
+		return
+	}
+	var err error
+	if !connected {
+		err = errors.New(errMsg)
+	}
+	c.circuits.Record(name, err, now)
+}
+
+// appendSkipped adds acct to report as disconnected with err, for an
+// account whose circuit is open and which was therefore not queried.
+func appendSkipped(report *UsageReport, acct AccountConfig, err error) {
+	if acct.Type == AccountTypeOrg {
+		report.Orgs = append(report.Orgs, OrgUsage{
+			Name:           acct.Name,
+			OrganizationID: acct.OrganizationID,
+			WarnThreshold:  acct.WarnThreshold,
+			CritThreshold:  acct.CritThreshold,
+			Tier:           acct.Tier,
+			Error:          err.Error(),
+		})
+		return
+	}
+	report.Accounts = append(report.Accounts, AccountUsage{
+		Name:           acct.Name,
+		ShortName:      acct.ShortName,
+		OrganizationID: acct.OrganizationID,
+		BudgetUSD:      acct.BudgetUSD,
+		WarnThreshold:  acct.WarnThreshold,
+		CritThreshold:  acct.CritThreshold,
+		Tier:           acct.Tier,
+		Error:          err.Error(),
+	})
+}
+
 // resolveOrgIDs auto-discovers organization IDs for accounts missing them.
 func (c *Collector) resolveOrgIDs(ctx context.Context) {
 	for i := range c.accounts {
@@ -210,11 +330,16 @@ func (c *Collector) resolveOrgIDs(ctx context.Context) {
 func (c *Collector) collectAccount(
 	ctx context.Context,
 	acct AccountConfig,
-	curStart, curEnd, prevStart, prevEnd string,
+	curStart, curEnd, prevStart, prevEnd, weekStart string,
 ) AccountUsage {
 	au := AccountUsage{
 		Name:           acct.Name,
+		ShortName:      acct.ShortName,
 		OrganizationID: acct.OrganizationID,
+		BudgetUSD:      acct.BudgetUSD,
+		WarnThreshold:  acct.WarnThreshold,
+		CritThreshold:  acct.CritThreshold,
+		Tier:           acct.Tier,
 	}
 
 	// Admin API requires admin keys (sk-ant-admin01-*). Regular API keys
@@ -234,11 +359,18 @@ func (c *Collector) collectAccount(
 	au.Connected = true
 	au.CurrentMonth = aggregateMonth(curResp)
 	au.Models = aggregateModels(curResp)
+	au.SevenDayCostUSD = costSince(curResp, weekStart)
+	if au.BudgetUSD > 0 {
+		au.Utilization = au.CurrentMonth.CostUSD / au.BudgetUSD * 100
+	}
 
 	// Fetch previous month usage (best-effort).
 	prevResp, err := c.client.GetUsage(ctx, acct.OrganizationID, acct.AdminAPIKey, prevStart, prevEnd)
 	if err == nil {
 		au.PreviousMonth = aggregateMonth(prevResp)
+		if weekStart < curStart {
+			au.SevenDayCostUSD += costSince(prevResp, weekStart)
+		}
 	}
 
 	return au
@@ -246,7 +378,8 @@ func (c *Collector) collectAccount(
 
 // calculateBurnRate populates the daily burn rate, projected monthly cost,
 // and days remaining on the given AccountUsage based on current month cost
-// and elapsed days.
+// and elapsed days, along with the seven-day utilization against the budget
+// pro-rated to the length of this month and the time the month resets.
 func (c *Collector) calculateBurnRate(au *AccountUsage, now time.Time) {
 	year, month, day := now.Date()
 	loc := now.Location()
@@ -264,6 +397,12 @@ func (c *Collector) calculateBurnRate(au *AccountUsage, now time.Time) {
 	au.DailyBurnRate = au.CurrentMonth.CostUSD / float64(daysElapsed)
 	au.ProjectedMonthly = au.DailyBurnRate * float64(daysInMonth)
 	au.DaysRemaining = daysInMonth - day
+	au.ResetsAt = firstOfNext
+
+	if au.BudgetUSD > 0 {
+		weekly := au.BudgetUSD * 7 / float64(daysInMonth)
+		au.SevenDayUtilization = au.SevenDayCostUSD / weekly * 100
+	}
 }
 
 // aggregateMonth sums all entries in an API response into a single MonthUsage.
@@ -288,6 +427,27 @@ func aggregateMonth(resp *APIUsageResponse) MonthUsage {
 	return mu
 }
 
+// costSince sums the cost of entries dated on or after start (YYYY-MM-DD).
+func costSince(resp *APIUsageResponse, start string) float64 {
+	if resp == nil {
+		return 0
+	}
+	var cost float64
+	for _, entry := range resp.Data {
+		if entry.Date < start {
+			continue
+		}
+		cost += CalculateCost(
+			entry.Model,
+			entry.InputTokens,
+			entry.OutputTokens,
+			entry.CacheCreationTokens,
+			entry.CacheReadTokens,
+		)
+	}
+	return cost
+}
+
 // aggregateModels builds per-model usage summaries from the API response.
 func aggregateModels(resp *APIUsageResponse) []ModelUsage {
 	if resp == nil {
@@ -343,6 +503,12 @@ func currentMonthRange(now time.Time) (start, end string) {
 	return first.Format("2006-01-02"), now.Format("2006-01-02")
 }
 
+// sevenDayStart returns the first date, as YYYY-MM-DD, of the seven-day
+// window ending today.
+func sevenDayStart(now time.Time) string {
+	return now.AddDate(0, 0, -6).Format("2006-01-02")
+}
+
 // previousMonthRange returns the start and end dates for the previous month.
 func previousMonthRange(now time.Time) (start, end string) {
 	year, month, _ := now.Date()
@@ -2,10 +2,14 @@ package claude
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"math"
+	"strings"
 	"testing"
 	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
 )
 
 // mockAPIClient is a test double for APIClient.
@@ -16,6 +20,9 @@ type mockAPIClient struct {
 	errs map[string]error
 	// calls tracks all calls made for assertion.
 	calls []mockCall
+	// orgs maps an organization ID to its utilization response; an
+	// organization without one fails.
+	orgs map[string]*APIOrgUtilization
 }
 
 type mockCall struct {
@@ -46,6 +53,13 @@ func (m *mockAPIClient) GetOrganizations(ctx context.Context, apiKey string) ([]
 	return nil, errors.New("mock: not configured")
 }
 
+func (m *mockAPIClient) GetOrgUtilization(ctx context.Context, orgID, apiKey string) (*APIOrgUtilization, error) {
+	if resp, ok := m.orgs[orgID]; ok {
+		return resp, nil
+	}
+	return nil, errors.New("API returned status 404: not found")
+}
+
 func (m *mockAPIClient) GetUsage(ctx context.Context, orgID, apiKey, startDate, endDate string) (*APIUsageResponse, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -147,7 +161,7 @@ func TestCollect_SingleAccount(t *testing.T) {
 
 	cfg := Config{
 		Accounts: []AccountConfig{
-			{Name: "personal", AdminAPIKey: "sk-ant-admin-test", OrganizationID: "org-personal"},
+			{Name: "personal", AdminAPIKey: "sk-ant-admin-test", OrganizationID: "org-personal", Tier: "max_5x"},
 		},
 	}
 
@@ -172,6 +186,9 @@ func TestCollect_SingleAccount(t *testing.T) {
 	if acct.Name != "personal" {
 		t.Errorf("Account.Name = %q, want %q", acct.Name, "personal")
 	}
+	if acct.Tier != "max_5x" {
+		t.Errorf("Account.Tier = %q, want the raw code max_5x", acct.Tier)
+	}
 	if !acct.Connected {
 		t.Error("Account.Connected = false, want true")
 	}
@@ -378,6 +395,59 @@ func TestCollect_AllAccountsFail(t *testing.T) {
 	}
 }
 
+func TestCollect_FailingAccountBacksOff(t *testing.T) {
+	mock := newMockAPIClient()
+	mock.setError("org-a", "2026-02-01", "2026-02-09", errors.New("401 revoked key"))
+
+	c := New(Config{
+		Accounts: []AccountConfig{
+			{Name: "a", AdminAPIKey: "sk-a", OrganizationID: "org-a"},
+			{Name: "b", AdminAPIKey: "sk-b", OrganizationID: "org-b"},
+		},
+	}, mock)
+	c.nowFunc = fixedNow
+	c.SetPartCircuitBreaker(&collectors.CircuitBreaker{Threshold: 2, Cooldown: time.Hour})
+
+	var report *UsageReport
+	for i := 0; i < 4; i++ {
+		result, err := c.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect() error: %v", err)
+		}
+		report = result.(*UsageReport)
+	}
+
+	queried := map[string]int{}
+	for _, call := range mock.calls {
+		if call.StartDate == "2026-02-01" && call.EndDate == "2026-02-09" {
+			queried[call.OrgID]++
+		}
+	}
+	if queried["org-a"] != 2 || queried["org-b"] != 4 {
+		t.Errorf("current-month queries = %v, want org-a 2 (then backed off), org-b 4", queried)
+	}
+	if got := c.PartCircuits(fixedNow()); len(got) != 1 || got["a"] != collectors.CircuitOpen {
+		t.Errorf("PartCircuits = %v, want only a open", got)
+	}
+	if a := report.Accounts[0]; a.Connected || !strings.Contains(a.Error, "circuit open") {
+		t.Errorf("skipped account = %+v, want disconnected with the circuit error", a)
+	}
+	if !report.Accounts[1].Connected || !c.Healthy() {
+		t.Error("healthy account stopped connecting")
+	}
+
+	// After the cooldown the account is tried again.
+	c.nowFunc = func() time.Time { return fixedNow().Add(time.Hour) }
+	before := len(mock.calls)
+	_, _ = c.Collect(context.Background())
+	if call := mock.calls[before]; call.OrgID != "org-a" {
+		t.Errorf("first query after the cooldown went to %s, want org-a", call.OrgID)
+	}
+	if got := c.PartCircuits(fixedNow().Add(time.Hour)); got["a"] != collectors.CircuitOpen {
+		t.Errorf("circuit after a failed trial = %v, want open again", got)
+	}
+}
+
 func TestCostCalculation_Sonnet(t *testing.T) {
 	// Sonnet: $3/M input, $15/M output
 	cost := CalculateCost("claude-sonnet-4-5-20250929", 1_000_000, 1_000_000, 0, 0)
@@ -875,6 +945,40 @@ func TestCollect_BurnRate(t *testing.T) {
 	if acct.DaysRemaining != 19 {
 		t.Errorf("DaysRemaining = %d, want 19", acct.DaysRemaining)
 	}
+
+	if want := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC); !acct.ResetsAt.Equal(want) {
+		t.Errorf("ResetsAt = %v, want %v", acct.ResetsAt, want)
+	}
+	if got := acct.MonthWindow(); got != 28*24*time.Hour {
+		t.Errorf("MonthWindow() = %v, want 28 days", got)
+	}
+}
+
+func TestAccountUsage_WindowProgress(t *testing.T) {
+	resets := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	a := AccountUsage{ResetsAt: resets}
+	month := a.MonthWindow()
+	tests := []struct {
+		name   string
+		window time.Duration
+		now    time.Time
+		want   float64
+	}{
+		{"month start", month, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), 0},
+		{"mid month", month, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), 0.5},
+		{"five hours, one left", 5 * time.Hour, resets.Add(-time.Hour), 0.8},
+		{"before window clamps", 5 * time.Hour, resets.Add(-6 * time.Hour), 0},
+		{"after reset clamps", month, resets.Add(time.Hour), 1},
+		{"zero window", 0, resets.Add(-time.Hour), 0},
+	}
+	for _, tt := range tests {
+		if got := a.WindowProgress(tt.window, tt.now); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: WindowProgress() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if got := (AccountUsage{}).WindowProgress(month, resets); got != 0 {
+		t.Errorf("WindowProgress() without ResetsAt = %v, want 0", got)
+	}
 }
 
 func TestCollect_BurnRate_DisconnectedAccount(t *testing.T) {
@@ -957,6 +1061,187 @@ func TestCollect_BurnRate_FirstDayOfMonth(t *testing.T) {
 	}
 }
 
+func TestHistoryRecord_CapsAndTracksAccounts(t *testing.T) {
+	h := NewHistory()
+	base := fixedNow()
+	for i := 0; i < 5; i++ {
+		h.Record(&UsageReport{
+			Timestamp:    base.Add(time.Duration(i) * 5 * time.Minute),
+			TotalCostUSD: float64(i * 2),
+			Accounts: []AccountUsage{
+				{Name: "personal", Connected: true, CurrentMonth: MonthUsage{CostUSD: float64(i)}},
+				{Name: "work", Connected: i%2 == 0, CurrentMonth: MonthUsage{CostUSD: 100}},
+			},
+		}, 3)
+	}
+
+	if got := h.Values("personal"); len(got) != 3 || got[0] != 2 || got[2] != 4 {
+		t.Errorf("personal values = %v, want [2 3 4]", got)
+	}
+	// Disconnected runs are skipped rather than recorded as zero.
+	if got := h.Values("work"); len(got) != 3 {
+		t.Errorf("work values = %v, want 3 samples", got)
+	}
+	if got := h.TotalValues(); len(got) != 3 || got[2] != 8 {
+		t.Errorf("total values = %v, want last 8", got)
+	}
+	if got := h.Values("unknown"); got != nil {
+		t.Errorf("unknown account values = %v, want nil", got)
+	}
+}
+
+func TestHistoryRecord_DuplicateTimestamp(t *testing.T) {
+	h := NewHistory()
+	r := &UsageReport{
+		Timestamp: fixedNow(),
+		Accounts:  []AccountUsage{{Name: "a", Connected: true}},
+	}
+	h.Record(r, 10)
+	h.Record(r, 10)
+	if n := len(h.Values("a")); n != 1 {
+		t.Errorf("samples after duplicate record = %d, want 1", n)
+	}
+}
+
+func TestHistoryRecord_PrunesVanishedAccounts(t *testing.T) {
+	h := NewHistory()
+	now := fixedNow()
+	h.Record(&UsageReport{
+		Timestamp: now,
+		Accounts:  []AccountUsage{{Name: "old", Connected: true}, {Name: "keep", Connected: true}},
+	}, 10)
+
+	// "old" disappears; within retention it keeps its series.
+	h.Record(&UsageReport{
+		Timestamp: now.Add(time.Hour),
+		Accounts:  []AccountUsage{{Name: "keep", Connected: true}},
+	}, 10)
+	if h.Values("old") == nil {
+		t.Error("vanished account should be retained within the retention window")
+	}
+
+	h.Record(&UsageReport{
+		Timestamp: now.Add(historyRetention + 2*time.Hour),
+		Accounts:  []AccountUsage{{Name: "keep", Connected: true}},
+	}, 10)
+	if h.Values("old") != nil {
+		t.Error("vanished account should be pruned after the retention window")
+	}
+	if n := len(h.Values("keep")); n != 3 {
+		t.Errorf("keep samples = %d, want 3", n)
+	}
+}
+
+func TestHistorySaveLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := HistoryPath(dir)
+
+	h, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory(missing) error: %v", err)
+	}
+	h.Record(&UsageReport{
+		Timestamp:    fixedNow(),
+		TotalCostUSD: 12.5,
+		Accounts:     []AccountUsage{{Name: "a", Connected: true, CurrentMonth: MonthUsage{CostUSD: 12.5}}},
+	}, 0)
+	if err := h.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	got, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory() error: %v", err)
+	}
+	if v := got.Values("a"); len(v) != 1 || v[0] != 12.5 {
+		t.Errorf("loaded values = %v, want [12.5]", v)
+	}
+}
+
+func TestAccountLevel_Thresholds(t *testing.T) {
+	tests := []struct {
+		name string
+		acct AccountUsage
+		want Level
+	}{
+		{"custom warn at 60pct", AccountUsage{Connected: true, BudgetUSD: 100, Utilization: 60, WarnThreshold: 50}, LevelWarn},
+		{"default at 60pct", AccountUsage{Connected: true, BudgetUSD: 100, Utilization: 60}, LevelOK},
+		{"default warn at 70pct", AccountUsage{Connected: true, BudgetUSD: 100, Utilization: 70}, LevelWarn},
+		{"default crit at 90pct", AccountUsage{Connected: true, BudgetUSD: 100, Utilization: 90}, LevelCrit},
+		{"custom crit at 75pct", AccountUsage{Connected: true, BudgetUSD: 100, Utilization: 75, CritThreshold: 75}, LevelCrit},
+		{"no budget", AccountUsage{Connected: true, Utilization: 99}, LevelOK},
+		{"disconnected", AccountUsage{BudgetUSD: 100, Utilization: 99}, LevelOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.acct.Level(); got != tt.want {
+				t.Errorf("Level() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccountPeakLevel_HigherWindowWins(t *testing.T) {
+	a := AccountUsage{Connected: true, BudgetUSD: 100, Utilization: 45, SevenDayUtilization: 92}
+	if got := a.Level(); got != LevelOK {
+		t.Errorf("Level() = %v, want %v", got, LevelOK)
+	}
+	if got := a.PeakLevel(); got != LevelCrit {
+		t.Errorf("PeakLevel() = %v, want %v", got, LevelCrit)
+	}
+	if got := a.PeakUtilization(); got != 92 {
+		t.Errorf("PeakUtilization() = %v, want 92", got)
+	}
+	r := &UsageReport{Accounts: []AccountUsage{a}}
+	if got := r.PeakLevel(); got != LevelCrit {
+		t.Errorf("report PeakLevel() = %v, want %v", got, LevelCrit)
+	}
+}
+
+func TestReportLevel_WorstAccount(t *testing.T) {
+	r := &UsageReport{Accounts: []AccountUsage{
+		{Name: "a", Connected: true, BudgetUSD: 100, Utilization: 60},
+		{Name: "b", Connected: true, BudgetUSD: 100, Utilization: 60, WarnThreshold: 50},
+	}}
+	if got := r.Level(); got != LevelWarn {
+		t.Errorf("report Level() = %v, want %v", got, LevelWarn)
+	}
+	if !r.HasBudgets() {
+		t.Error("HasBudgets() = false, want true")
+	}
+	if (&UsageReport{Accounts: []AccountUsage{{Name: "a"}}}).HasBudgets() {
+		t.Error("HasBudgets() = true for report without budgets")
+	}
+}
+
+func TestCollect_ThreadsThresholds(t *testing.T) {
+	mock := newMockAPIClient()
+	mock.setResponse("org-1", "2026-03-01", "2026-03-01", &APIUsageResponse{
+		Data: []APIUsageEntry{
+			{Date: "2026-03-01", Model: "claude-sonnet-4-5-20250929", InputTokens: 1_000_000},
+		},
+	})
+
+	c := New(Config{Accounts: []AccountConfig{{
+		Name: "test", AdminAPIKey: "sk-admin", OrganizationID: "org-1",
+		BudgetUSD: 10, WarnThreshold: 20, CritThreshold: 95,
+	}}}, mock)
+	c.nowFunc = func() time.Time { return time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC) }
+
+	result, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error: %v", err)
+	}
+	acct := result.(*UsageReport).Accounts[0]
+	if acct.WarnThreshold != 20 || acct.CritThreshold != 95 || acct.BudgetUSD != 10 {
+		t.Errorf("thresholds not threaded: %+v", acct)
+	}
+	want := acct.CurrentMonth.CostUSD / 10 * 100
+	if math.Abs(acct.Utilization-want) > 0.001 {
+		t.Errorf("Utilization = %f, want %f", acct.Utilization, want)
+	}
+}
+
 // Compile-time check that Collector satisfies the duck-typed interface.
 type collectorIface interface {
 	Name() string
@@ -969,3 +1254,332 @@ var _ collectorIface = (*Collector)(nil)
 
 // Ensure the mock satisfies APIClient.
 var _ APIClient = (*mockAPIClient)(nil)
+
+func TestCollect_SevenDayWindowSpansMonths(t *testing.T) {
+	mock := newMockAPIClient()
+	sonnet := "claude-sonnet-4-5-20250929"
+
+	// $18 per entry: 1M input + 1M output tokens of Sonnet.
+	mock.setResponse("org-1", "2026-02-01", "2026-02-03", &APIUsageResponse{
+		Data: []APIUsageEntry{
+			{Date: "2026-02-02", Model: sonnet, InputTokens: 1_000_000, OutputTokens: 1_000_000},
+		},
+	})
+	mock.setResponse("org-1", "2026-01-01", "2026-01-31", &APIUsageResponse{
+		Data: []APIUsageEntry{
+			{Date: "2026-01-10", Model: sonnet, InputTokens: 1_000_000, OutputTokens: 1_000_000},
+			{Date: "2026-01-28", Model: sonnet, InputTokens: 1_000_000, OutputTokens: 1_000_000},
+		},
+	})
+
+	cfg := Config{
+		Accounts: []AccountConfig{
+			{Name: "test", AdminAPIKey: "sk", OrganizationID: "org-1", BudgetUSD: 280},
+		},
+	}
+	c := New(cfg, mock)
+	c.nowFunc = func() time.Time { return time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC) }
+
+	result, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error: %v", err)
+	}
+	acct := result.(*UsageReport).Accounts[0]
+
+	// Jan 28 through Feb 3: the Jan 10 entry falls outside the window.
+	if math.Abs(acct.SevenDayCostUSD-36) > 0.001 {
+		t.Errorf("SevenDayCostUSD = %f, want 36", acct.SevenDayCostUSD)
+	}
+	// February pro-rates the $280 budget to $70 a week.
+	if math.Abs(acct.SevenDayUtilization-36.0/70*100) > 0.001 {
+		t.Errorf("SevenDayUtilization = %f, want %f", acct.SevenDayUtilization, 36.0/70*100)
+	}
+	// Monthly utilization only counts February.
+	if math.Abs(acct.Utilization-18.0/280*100) > 0.001 {
+		t.Errorf("Utilization = %f, want %f", acct.Utilization, 18.0/280*100)
+	}
+}
+
+// sortFixture is five accounts in config order with ties on every key.
+func sortFixture() []AccountUsage {
+	return []AccountUsage{
+		{Name: "work", Connected: true, BudgetUSD: 100, Utilization: 50},
+		{Name: "Personal", Connected: true, BudgetUSD: 100, Utilization: 95},
+		{Name: "lab", Connected: false},
+		{Name: "alpha", Connected: true, BudgetUSD: 100, Utilization: 50},
+		{Name: "team", Connected: true, BudgetUSD: 100, Utilization: 75},
+	}
+}
+
+func TestSortAccounts(t *testing.T) {
+	tests := []struct {
+		mode SortMode
+		want string
+	}{
+		{SortConfig, "work,Personal,lab,alpha,team"},
+		// Case-insensitive: "Personal" sorts between "lab" and "team".
+		{SortName, "alpha,lab,Personal,team,work"},
+		// work and alpha tie at 50% and keep config order.
+		{SortUtilization, "Personal,team,work,alpha,lab"},
+		// crit, warn, offline, then the two healthy ones in config order.
+		{SortStatus, "Personal,team,lab,work,alpha"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			in := sortFixture()
+			var names []string
+			for _, a := range SortAccounts(in, tt.mode) {
+				names = append(names, a.Name)
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Errorf("SortAccounts(%s) = %s, want %s", tt.mode, got, tt.want)
+			}
+			if in[0].Name != "work" {
+				t.Error("SortAccounts modified its input")
+			}
+		})
+	}
+}
+
+func TestPinPrimary(t *testing.T) {
+	tests := []struct {
+		primary string
+		want    string
+	}{
+		// alpha is pinned ahead of the more utilized Personal and team.
+		{"alpha", "alpha,Personal,team,work,lab"},
+		{"Personal", "Personal,team,work,alpha,lab"},
+		{"", "Personal,team,work,alpha,lab"},
+		{"missing", "Personal,team,work,alpha,lab"},
+	}
+	for _, tt := range tests {
+		in := SortAccounts(sortFixture(), SortUtilization)
+		var names []string
+		for _, a := range PinPrimary(in, tt.primary) {
+			names = append(names, a.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("PinPrimary(%q) = %s, want %s", tt.primary, got, tt.want)
+		}
+		if in[len(in)-1].Name != "lab" {
+			t.Error("PinPrimary modified its input")
+		}
+	}
+}
+
+func TestUsageReportShortNames(t *testing.T) {
+	r := &UsageReport{Accounts: []AccountUsage{
+		{Name: "work-a"},
+		{Name: "personal"},
+		{Name: "work-b"},
+		{Name: "Lab"},
+		{Name: "lab", ShortName: "lab"},
+		{Name: "ops", ShortName: "wor1"},
+	}}
+	want := map[string]string{
+		// Collisions are numbered in config order, skipping the explicit
+		// "wor1", and explicit short names always win.
+		"work-a":   "wor2",
+		"personal": "pers",
+		"work-b":   "wor3",
+		"Lab":      "lab1",
+		"lab":      "lab",
+		"ops":      "wor1",
+	}
+	for i := 0; i < 2; i++ {
+		got := r.ShortNames()
+		for name, w := range want {
+			if got[name] != w {
+				t.Errorf("ShortNames()[%q] = %q, want %q", name, got[name], w)
+			}
+		}
+	}
+
+	two := &UsageReport{Accounts: []AccountUsage{{Name: "work-a"}, {Name: "work-b"}}}
+	if got := two.ShortNames(); got["work-a"] != "wor1" || got["work-b"] != "wor2" {
+		t.Errorf("ShortNames() = %v, want wor1 and wor2", got)
+	}
+}
+
+func TestPlanName(t *testing.T) {
+	tests := []struct {
+		tier, want string
+	}{
+		{"max_5x", "Max 5×"},
+		{"max_20x", "Max 20×"},
+		{"pro", "Pro"},
+		{"tier_3", "API Tier 3"},
+		{"MAX_5X", "Max 5×"},
+		{"tier_9", "tier_9"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := PlanName(tt.tier); got != tt.want {
+			t.Errorf("PlanName(%q) = %q, want %q", tt.tier, got, tt.want)
+		}
+	}
+}
+
+func TestProjectExhaustion(t *testing.T) {
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	series := func(costs ...float64) []HistorySample {
+		out := make([]HistorySample, len(costs))
+		for i, c := range costs {
+			out[i] = HistorySample{Timestamp: base.Add(time.Duration(i) * 10 * time.Minute), CostUSD: c}
+		}
+		return out
+	}
+	resets := base.Add(24 * time.Hour)
+
+	// $2 per 10 minutes with $20 left after the last sample at 12:40.
+	at := ProjectExhaustion(series(72, 74, 76, 78, 80), 100, resets)
+	if want := base.Add(140 * time.Minute); at == nil || !at.Equal(want) {
+		t.Errorf("steady burn: got %v, want %v", at, want)
+	}
+	// Only the newest samples set the pace.
+	at = ProjectExhaustion(series(0, 40, 80, 80, 82, 84, 86, 88, 90), 100, resets)
+	if want := base.Add(130 * time.Minute); at == nil || !at.Equal(want) {
+		t.Errorf("recent pace: got %v, want %v", at, want)
+	}
+
+	tests := []struct {
+		name    string
+		samples []HistorySample
+		resets  time.Time
+	}{
+		{"too few samples", series(72, 74, 76), resets},
+		{"flat", series(80, 80, 80, 80, 80), resets},
+		{"rising then flat", series(10, 30, 50, 80, 80, 80, 80, 80, 80), resets},
+		{"reset leaves too few", series(90, 95, 98, 2, 4, 6), resets},
+		{"budget spent", series(96, 98, 100, 102), resets},
+		{"after reset", series(72, 74, 76, 78, 80), base.Add(2 * time.Hour)},
+		{"unknown reset", series(72, 74, 76, 78, 80), time.Time{}},
+	}
+	for _, tt := range tests {
+		if at := ProjectExhaustion(tt.samples, 100, tt.resets); at != nil {
+			t.Errorf("%s: got %v, want nil", tt.name, at)
+		}
+	}
+	if at := ProjectExhaustion(series(72, 74, 76, 78, 80), 0, resets); at != nil {
+		t.Errorf("no budget: got %v, want nil", at)
+	}
+}
+
+func TestParseSortMode(t *testing.T) {
+	if m, err := ParseSortMode(""); err != nil || m != SortConfig {
+		t.Errorf("ParseSortMode(\"\") = %q, %v; want config", m, err)
+	}
+	if m, err := ParseSortMode(" Utilization "); err != nil || m != SortUtilization {
+		t.Errorf("ParseSortMode(Utilization) = %q, %v", m, err)
+	}
+	if _, err := ParseSortMode("priority"); err == nil || !strings.Contains(err.Error(), "supported: config, name, utilization, status") {
+		t.Errorf("expected error listing modes, got %v", err)
+	}
+}
+
+func TestCollect_OrgAccount(t *testing.T) {
+	mock := newMockAPIClient()
+	now := fixedNow()
+	curStart, curEnd := currentMonthRange(now)
+	mock.setResponse("org-personal", curStart, curEnd, buildSingleAccountUsageResponse())
+	resets := time.Date(2026, 2, 9, 18, 0, 0, 0, time.UTC)
+	mock.orgs = map[string]*APIOrgUtilization{"org-team": {
+		FiveHour:  &APIUtilizationWindow{Utilization: 42, ResetsAt: resets},
+		SevenDay:  &APIUtilizationWindow{Utilization: 75, ResetsAt: resets.AddDate(0, 0, 3)},
+		SeatCount: 12,
+	}}
+
+	c := New(Config{Accounts: []AccountConfig{
+		{Name: "personal", AdminAPIKey: "sk-admin-1", OrganizationID: "org-personal"},
+		{Name: "team", AdminAPIKey: "sk-admin-2", OrganizationID: "org-team", Type: AccountTypeOrg},
+		{Name: "gone", AdminAPIKey: "sk-admin-3", OrganizationID: "org-gone", Type: AccountTypeOrg},
+	}}, mock)
+	c.nowFunc = fixedNow
+
+	result, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error: %v", err)
+	}
+	report := result.(*UsageReport)
+	if len(report.Accounts) != 1 || report.Accounts[0].Name != "personal" {
+		t.Fatalf("Accounts = %+v, want only personal", report.Accounts)
+	}
+	if report.TotalCostUSD != report.Accounts[0].CurrentMonth.CostUSD {
+		t.Errorf("TotalCostUSD = %v, org accounts must not add spend", report.TotalCostUSD)
+	}
+	if len(report.Orgs) != 2 {
+		t.Fatalf("Orgs = %+v, want team and gone", report.Orgs)
+	}
+	team := report.Orgs[0]
+	if !team.Connected || team.Seats != 12 || team.FiveHour == nil || team.FiveHour.Utilization != 42 ||
+		!team.FiveHour.ResetsAt.Equal(resets) || team.SevenDay == nil || team.SevenDay.Utilization != 75 {
+		t.Errorf("team org = %+v", team)
+	}
+	if team.Level() != LevelWarn {
+		t.Errorf("team Level() = %v, want warn from the seven-day window", team.Level())
+	}
+	if gone := report.Orgs[1]; gone.Connected || !strings.Contains(gone.Error, "404") {
+		t.Errorf("gone org = %+v, want disconnected with error", gone)
+	}
+	if report.Level() != LevelWarn {
+		t.Errorf("report Level() = %v, want org level to count", report.Level())
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back UsageReport
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Orgs) != 2 || back.Orgs[0].Seats != 12 || back.Orgs[0].SevenDay.Utilization != 75 ||
+		!back.Orgs[0].FiveHour.ResetsAt.Equal(resets) {
+		t.Errorf("round-tripped Orgs = %+v", back.Orgs)
+	}
+}
+
+func TestUsageReport_JSONWithoutOrgs(t *testing.T) {
+	data, err := json.Marshal(&UsageReport{Accounts: []AccountUsage{{Name: "a"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "orgs") {
+		t.Errorf("report without orgs should omit the field: %s", data)
+	}
+	var r UsageReport
+	if err := json.Unmarshal([]byte(`{"accounts":[{"name":"a","connected":true}],"total_cost_usd":1}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Orgs) != 0 || len(r.Accounts) != 1 {
+		t.Errorf("decoded older cache = %+v", r)
+	}
+}
+
+func TestOrgLevel(t *testing.T) {
+	for _, tt := range []struct {
+		org  OrgUsage
+		want Level
+	}{
+		{OrgUsage{Connected: true, FiveHour: &UsageWindow{Utilization: 50}}, LevelOK},
+		{OrgUsage{Connected: true, FiveHour: &UsageWindow{Utilization: 92}, SevenDay: &UsageWindow{Utilization: 10}}, LevelCrit},
+		{OrgUsage{Connected: true, SevenDay: &UsageWindow{Utilization: 55}, WarnThreshold: 50}, LevelWarn},
+		{OrgUsage{Connected: false, FiveHour: &UsageWindow{Utilization: 99}}, LevelOK},
+		{OrgUsage{Connected: true}, LevelOK},
+	} {
+		if got := tt.org.Level(); got != tt.want {
+			t.Errorf("%+v Level() = %v, want %v", tt.org, got, tt.want)
+		}
+	}
+}
+
+func TestParseAccountType(t *testing.T) {
+	for in, want := range map[string]AccountType{"": AccountTypeAccount, "account": AccountTypeAccount, "Org": AccountTypeOrg} {
+		if got, err := ParseAccountType(in); err != nil || got != want {
+			t.Errorf("ParseAccountType(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseAccountType("team"); err == nil || !strings.Contains(err.Error(), "supported: account, org") {
+		t.Errorf("expected error listing types, got %v", err)
+	}
+}
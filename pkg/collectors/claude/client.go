@@ -29,6 +29,10 @@ type APIClient interface {
 
 	// GetOrganizations lists organizations accessible by the given admin key.
 	GetOrganizations(ctx context.Context, apiKey string) ([]Organization, error)
+
+	// GetOrgUtilization retrieves the organization's shared five-hour and
+	// seven-day limit utilization and its seat count.
+	GetOrgUtilization(ctx context.Context, orgID, apiKey string) (*APIOrgUtilization, error)
 }
 
 // Organization represents an Anthropic organization.
@@ -42,6 +46,21 @@ type OrganizationsResponse struct {
 	Data []Organization `json:"data"`
 }
 
+// APIOrgUtilization represents the organization utilization API response.
+// A window the plan does not limit is omitted.
+type APIOrgUtilization struct {
+	FiveHour  *APIUtilizationWindow `json:"five_hour"`
+	SevenDay  *APIUtilizationWindow `json:"seven_day"`
+	SeatCount int                   `json:"seat_count"`
+}
+
+// APIUtilizationWindow is one rolling limit window: utilization in percent
+// and when it resets.
+type APIUtilizationWindow struct {
+	Utilization float64   `json:"utilization"`
+	ResetsAt    time.Time `json:"resets_at"`
+}
+
 // APIUsageResponse represents the JSON response from the Anthropic usage API.
 // Unknown fields are silently ignored by encoding/json.
 type APIUsageResponse struct {
@@ -144,3 +163,36 @@ func (c *HTTPClient) GetOrganizations(ctx context.Context, apiKey string) ([]Org
 
 	return result.Data, nil
 }
+
+// GetOrgUtilization calls the Anthropic Admin API to retrieve the
+// organization's shared limit utilization.
+func (c *HTTPClient) GetOrgUtilization(ctx context.Context, orgID, apiKey string) (*APIOrgUtilization, error) {
+	url := fmt.Sprintf("%s/v1/organizations/%s/utilization", c.baseURL, orgID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result APIOrgUtilization
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,72 @@
+package claude
+
+import "time"
+
+// MinProjectionSamples is the number of samples since the last reset that
+// ProjectExhaustion needs before it estimates anything.
+const MinProjectionSamples = 4
+
+// projectionWindow is how many of the newest samples set the burn rate, so
+// the projection follows the current pace rather than the month's average.
+const projectionWindow = 6
+
+// ProjectExhaustion estimates when an account spending along samples will
+// reach budgetUSD, extrapolating the burn rate of the newest samples. It
+// returns nil unless that moment falls before resetAt, so a non-nil result
+// means the budget runs out before the window restarts. A drop in cost
+// marks a reset, and only samples after it count. Fewer than
+// MinProjectionSamples samples, a flat or falling recent rate, an unknown
+// reset time or a budget already spent all yield nil.
+func ProjectExhaustion(samples []HistorySample, budgetUSD float64, resetAt time.Time) *time.Time {
+	if budgetUSD <= 0 || resetAt.IsZero() {
+		return nil
+	}
+	start := 0
+	for i := 1; i < len(samples); i++ {
+		if samples[i].CostUSD < samples[i-1].CostUSD {
+			start = i
+		}
+	}
+	samples = samples[start:]
+	if len(samples) < MinProjectionSamples {
+		return nil
+	}
+	if len(samples) > projectionWindow {
+		samples = samples[len(samples)-projectionWindow:]
+	}
+
+	last := samples[len(samples)-1]
+	left := budgetUSD - last.CostUSD
+	rate := burnRate(samples)
+	if left <= 0 || rate <= 0 {
+		return nil
+	}
+	at := last.Timestamp.Add(time.Duration(left / rate * float64(time.Second)))
+	if !at.Before(resetAt) {
+		return nil
+	}
+	return &at
+}
+
+// burnRate returns the least-squares slope of cost over time, in dollars
+// per second.
+func burnRate(samples []HistorySample) float64 {
+	t0 := samples[0].Timestamp
+	var sumT, sumC float64
+	for _, s := range samples {
+		sumT += s.Timestamp.Sub(t0).Seconds()
+		sumC += s.CostUSD
+	}
+	n := float64(len(samples))
+	meanT, meanC := sumT/n, sumC/n
+	var cov, varT float64
+	for _, s := range samples {
+		dt := s.Timestamp.Sub(t0).Seconds() - meanT
+		cov += dt * (s.CostUSD - meanC)
+		varT += dt * dt
+	}
+	if varT == 0 {
+		return 0
+	}
+	return cov / varT
+}
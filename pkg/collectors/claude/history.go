@@ -0,0 +1,160 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
+)
+
+// DefaultHistoryPoints is the number of samples kept per account when no
+// explicit cap is configured. At the default 5-minute collection interval
+// this covers the last four hours.
+const DefaultHistoryPoints = 48
+
+// HistoryCacheKey is the cache key (file name without .json) under which the
+// daemon persists the per-account usage history.
+const HistoryCacheKey = "claude-history"
+
+// historyRetention is how long an account that no longer appears in reports
+// keeps its samples. Accounts that come back within this window resume their
+// existing series; renamed or removed accounts are eventually pruned.
+const historyRetention = 7 * 24 * time.Hour
+
+// HistorySample is a single point in an account's usage history.
+type HistorySample struct {
+	Timestamp time.Time `json:"timestamp"`
+	CostUSD   float64   `json:"cost_usd"`
+}
+
+// History holds capped per-account series of month-to-date cost samples,
+// keyed by account name, plus a series for the report total.
+type History struct {
+	Accounts map[string][]HistorySample `json:"accounts"`
+	Total    []HistorySample            `json:"total"`
+}
+
+// NewHistory returns an empty History.
+func NewHistory() *History {
+	return &History{Accounts: make(map[string][]HistorySample)}
+}
+
+// HistoryPath returns the path of the history file inside cacheDir.
+func HistoryPath(cacheDir string) string {
+	return filepath.Join(cacheDir, HistoryCacheKey+".json")
+}
+
+// LoadHistory reads a History from path. A missing file yields an empty
+// History and no error so that first runs start a fresh series.
+func LoadHistory(path string) (*History, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewHistory(), nil
+		}
+		return nil, err
+	}
+
+	h := NewHistory()
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("claude: parse history: %w", err)
+	}
+	if h.Accounts == nil {
+		h.Accounts = make(map[string][]HistorySample)
+	}
+	return h, nil
+}
+
+// Save writes the History to path via a temporary file and rename so that
+// readers never observe a partially written file.
+func (h *History) Save(path string) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("claude: marshal history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), cache.DirMode); err != nil {
+		return fmt.Errorf("claude: create history dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, cache.FileMode); err != nil {
+		return fmt.Errorf("claude: write history: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Record appends one sample per connected account in report, plus one for
+// the report total, and trims every series to maxPoints. Accounts missing
+// from the report keep their series until historyRetention has passed since
+// their last sample. Re-recording a report with the same timestamp is a
+// no-op for series that already hold that sample.
+func (h *History) Record(report *UsageReport, maxPoints int) {
+	if report == nil {
+		return
+	}
+	if maxPoints <= 0 {
+		maxPoints = DefaultHistoryPoints
+	}
+	if h.Accounts == nil {
+		h.Accounts = make(map[string][]HistorySample)
+	}
+
+	ts := report.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	for _, acct := range report.Accounts {
+		if acct.Name == "" || !acct.Connected {
+			continue
+		}
+		h.Accounts[acct.Name] = appendSample(h.Accounts[acct.Name],
+			HistorySample{Timestamp: ts, CostUSD: acct.CurrentMonth.CostUSD}, maxPoints)
+	}
+	h.Total = appendSample(h.Total,
+		HistorySample{Timestamp: ts, CostUSD: report.TotalCostUSD}, maxPoints)
+
+	for name, samples := range h.Accounts {
+		if len(samples) == 0 || ts.Sub(samples[len(samples)-1].Timestamp) > historyRetention {
+			delete(h.Accounts, name)
+		}
+	}
+}
+
+// Values returns the cost values recorded for the named account, oldest
+// first. It returns nil for unknown accounts.
+func (h *History) Values(name string) []float64 {
+	return sampleValues(h.Accounts[name])
+}
+
+// TotalValues returns the recorded report totals, oldest first.
+func (h *History) TotalValues() []float64 {
+	return sampleValues(h.Total)
+}
+
+// appendSample appends s to samples unless the last sample already carries
+// the same timestamp, then trims the series to the newest maxPoints entries.
+func appendSample(samples []HistorySample, s HistorySample, maxPoints int) []HistorySample {
+	if n := len(samples); n > 0 && samples[n-1].Timestamp.Equal(s.Timestamp) {
+		return samples
+	}
+	samples = append(samples, s)
+	if len(samples) > maxPoints {
+		samples = append([]HistorySample(nil), samples[len(samples)-maxPoints:]...)
+	}
+	return samples
+}
+
+// sampleValues extracts the cost values from a series.
+func sampleValues(samples []HistorySample) []float64 {
+	if len(samples) == 0 {
+		return nil
+	}
+	out := make([]float64, len(samples))
+	for i, s := range samples {
+		out[i] = s.CostUSD
+	}
+	return out
+}
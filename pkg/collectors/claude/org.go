@@ -0,0 +1,126 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AccountType selects what the collector reads for a configured account.
+type AccountType string
+
+// Supported account types.
+const (
+	// AccountTypeAccount reports the account's own token usage and spend.
+	AccountTypeAccount AccountType = "account"
+
+	// AccountTypeOrg reports the organization's shared five-hour and
+	// seven-day limits and its seat count, as on a Team plan, instead of
+	// per-account spend.
+	AccountTypeOrg AccountType = "org"
+)
+
+// AccountTypes lists the supported account types in documentation order.
+var AccountTypes = []AccountType{AccountTypeAccount, AccountTypeOrg}
+
+// ParseAccountType parses an account type name. An empty string selects
+// AccountTypeAccount.
+func ParseAccountType(s string) (AccountType, error) {
+	if s == "" {
+		return AccountTypeAccount, nil
+	}
+	for _, t := range AccountTypes {
+		if AccountType(strings.ToLower(s)) == t {
+			return t, nil
+		}
+	}
+	names := make([]string, len(AccountTypes))
+	for i, t := range AccountTypes {
+		names[i] = string(t)
+	}
+	return "", fmt.Errorf("unknown claude account type %q (supported: %s)", s, strings.Join(names, ", "))
+}
+
+// OrgUsage holds the shared utilization of an organization configured with
+// AccountTypeOrg. Utilization is measured by Anthropic against the
+// organization's plan limits, so it needs no budget.
+type OrgUsage struct {
+	Name           string       `json:"name"`
+	OrganizationID string       `json:"organization_id"`
+	Connected      bool         `json:"connected"`
+	Error          string       `json:"error,omitempty"`
+	FiveHour       *UsageWindow `json:"five_hour,omitempty"`
+	SevenDay       *UsageWindow `json:"seven_day,omitempty"`
+	Seats          int          `json:"seats,omitempty"`
+	WarnThreshold  float64      `json:"warn_threshold,omitempty"`
+	CritThreshold  float64      `json:"crit_threshold,omitempty"`
+	Tier           string       `json:"tier,omitempty"`
+}
+
+// UsageWindow is the utilization of one rolling limit window, in percent,
+// and when the window resets.
+type UsageWindow struct {
+	Utilization float64   `json:"utilization"`
+	ResetsAt    time.Time `json:"resets_at"`
+}
+
+// Thresholds returns the organization's warning and critical thresholds,
+// falling back to DefaultWarnThreshold and DefaultCritThreshold for unset
+// values.
+func (o OrgUsage) Thresholds() (warn, crit float64) {
+	return thresholdsOrDefault(o.WarnThreshold, o.CritThreshold)
+}
+
+// PeakUtilization returns the higher of the five-hour and seven-day
+// utilizations.
+func (o OrgUsage) PeakUtilization() float64 {
+	peak := 0.0
+	for _, w := range []*UsageWindow{o.FiveHour, o.SevenDay} {
+		if w != nil && w.Utilization > peak {
+			peak = w.Utilization
+		}
+	}
+	return peak
+}
+
+// Level classifies the higher of the two window utilizations against the
+// organization's thresholds. A disconnected organization is LevelOK.
+func (o OrgUsage) Level() Level {
+	if !o.Connected {
+		return LevelOK
+	}
+	warn, crit := o.Thresholds()
+	return levelFor(o.PeakUtilization(), warn, crit)
+}
+
+// collectOrg fetches the shared utilization of an AccountTypeOrg account.
+// Errors are captured in the struct rather than propagated.
+func (c *Collector) collectOrg(ctx context.Context, acct AccountConfig) OrgUsage {
+	ou := OrgUsage{
+		Name:           acct.Name,
+		OrganizationID: acct.OrganizationID,
+		WarnThreshold:  acct.WarnThreshold,
+		CritThreshold:  acct.CritThreshold,
+		Tier:           acct.Tier,
+	}
+	if strings.HasPrefix(acct.AdminAPIKey, "sk-ant-api") {
+		ou.Error = "key is not an admin key (requires sk-ant-admin01-*); get one at console.anthropic.com"
+		return ou
+	}
+
+	resp, err := c.client.GetOrgUtilization(ctx, acct.OrganizationID, acct.AdminAPIKey)
+	if err != nil {
+		ou.Error = err.Error()
+		return ou
+	}
+	ou.Connected = true
+	ou.Seats = resp.SeatCount
+	if w := resp.FiveHour; w != nil {
+		ou.FiveHour = &UsageWindow{Utilization: w.Utilization, ResetsAt: w.ResetsAt}
+	}
+	if w := resp.SevenDay; w != nil {
+		ou.SevenDay = &UsageWindow{Utilization: w.Utilization, ResetsAt: w.ResetsAt}
+	}
+	return ou
+}
@@ -0,0 +1,29 @@
+package claude
+
+import "strings"
+
+// planNames maps the tier codes Anthropic uses for plans and API usage
+// tiers to the names people know them by. Add a row for a new tier.
+var planNames = map[string]string{
+	"free":       "Free",
+	"pro":        "Pro",
+	"max_5x":     "Max 5×",
+	"max_20x":    "Max 20×",
+	"team":       "Team",
+	"enterprise": "Enterprise",
+	"tier_1":     "API Tier 1",
+	"tier_2":     "API Tier 2",
+	"tier_3":     "API Tier 3",
+	"tier_4":     "API Tier 4",
+	"scale":      "API Scale",
+}
+
+// PlanName returns the display name of a tier code, e.g. "Max 5×" for
+// "max_5x", matching the code case-insensitively. An unknown code is
+// returned as is.
+func PlanName(tier string) string {
+	if name, ok := planNames[strings.ToLower(strings.TrimSpace(tier))]; ok {
+		return name
+	}
+	return tier
+}
@@ -0,0 +1,86 @@
+package claude
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ShortNameLen is the length of derived account short names.
+const ShortNameLen = 4
+
+// ShortNames returns a short display name for each account in the report,
+// keyed by account name, for formatters that have no room for full names.
+// An account's explicit ShortName always wins. The others use the first
+// ShortNameLen letters and digits of their name, lowercased; accounts whose
+// derived names collide are numbered in report (config) order, so
+// "work-a" and "work-b" become "wor1" and "wor2" on every render.
+func (r *UsageReport) ShortNames() map[string]string {
+	out := make(map[string]string, len(r.Accounts))
+	taken := make(map[string]bool)
+	for _, a := range r.Accounts {
+		if a.ShortName != "" {
+			out[a.Name] = a.ShortName
+			taken[a.ShortName] = true
+		}
+	}
+
+	var derived []AccountUsage
+	count := make(map[string]int)
+	for _, a := range r.Accounts {
+		if _, ok := out[a.Name]; ok {
+			continue
+		}
+		base := shortBase(a.Name)
+		out[a.Name] = base
+		derived = append(derived, a)
+		count[base]++
+	}
+
+	next := make(map[string]int)
+	for _, a := range derived {
+		base := out[a.Name]
+		if count[base] == 1 && !taken[base] {
+			taken[base] = true
+			continue
+		}
+		var name string
+		for name == "" || taken[name] {
+			next[base]++
+			name = numbered(base, next[base])
+		}
+		out[a.Name] = name
+		taken[name] = true
+	}
+	return out
+}
+
+// shortBase derives the undisambiguated short name for name.
+func shortBase(name string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range strings.ToLower(name) {
+		if n == ShortNameLen {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			n++
+		}
+	}
+	if n == 0 {
+		return "acct"
+	}
+	return b.String()
+}
+
+// numbered replaces the tail of base with n so the result stays within
+// ShortNameLen runes.
+func numbered(base string, n int) string {
+	suffix := strconv.Itoa(n)
+	runes := []rune(base)
+	if keep := ShortNameLen - len(suffix); len(runes) > keep {
+		runes = runes[:max(keep, 0)]
+	}
+	return string(runes) + suffix
+}
@@ -0,0 +1,99 @@
+package claude
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortMode selects the order in which accounts are listed for display.
+type SortMode string
+
+// Supported sort modes. SortConfig is the default.
+const (
+	// SortConfig keeps the order accounts appear in the config.
+	SortConfig SortMode = "config"
+
+	// SortName orders accounts alphabetically, ignoring case.
+	SortName SortMode = "name"
+
+	// SortUtilization puts the highest budget utilization first.
+	SortUtilization SortMode = "utilization"
+
+	// SortStatus puts critical accounts first, then warning, then
+	// disconnected, then healthy ones.
+	SortStatus SortMode = "status"
+)
+
+// SortModes lists every accepted SortMode.
+var SortModes = []SortMode{SortConfig, SortName, SortUtilization, SortStatus}
+
+// ParseSortMode validates s. An empty string yields SortConfig.
+func ParseSortMode(s string) (SortMode, error) {
+	if s == "" {
+		return SortConfig, nil
+	}
+	m := SortMode(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range SortModes {
+		if m == known {
+			return m, nil
+		}
+	}
+	names := make([]string, len(SortModes))
+	for i, known := range SortModes {
+		names[i] = string(known)
+	}
+	return "", fmt.Errorf("unknown claude sort mode %q (supported: %s)", s, strings.Join(names, ", "))
+}
+
+// SortAccounts returns a copy of accounts ordered by mode. The sort is
+// stable, so accounts with equal keys keep their config order. Unknown
+// modes behave like SortConfig.
+func SortAccounts(accounts []AccountUsage, mode SortMode) []AccountUsage {
+	out := append([]AccountUsage(nil), accounts...)
+	var less func(a, b AccountUsage) bool
+	switch mode {
+	case SortName:
+		less = func(a, b AccountUsage) bool {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+	case SortUtilization:
+		less = func(a, b AccountUsage) bool { return a.Utilization > b.Utilization }
+	case SortStatus:
+		less = func(a, b AccountUsage) bool { return statusRank(a) < statusRank(b) }
+	default:
+		return out
+	}
+	sort.SliceStable(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out
+}
+
+// PinPrimary returns a copy of accounts with the account called name moved
+// to the front and the rest in their existing order. An empty or unknown
+// name leaves the order unchanged.
+func PinPrimary(accounts []AccountUsage, name string) []AccountUsage {
+	out := append([]AccountUsage(nil), accounts...)
+	for i, a := range out {
+		if name != "" && a.Name == name {
+			copy(out[1:i+1], out[:i])
+			out[0] = a
+			break
+		}
+	}
+	return out
+}
+
+// statusRank orders accounts for SortStatus; lower ranks sort first.
+func statusRank(a AccountUsage) int {
+	if !a.Connected {
+		return 2
+	}
+	switch a.Level() {
+	case LevelCrit:
+		return 0
+	case LevelWarn:
+		return 1
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,140 @@
+package claude
+
+// Default utilization thresholds, in percent of an account's monthly budget.
+const (
+	DefaultWarnThreshold = 70.0
+	DefaultCritThreshold = 90.0
+)
+
+// Level classifies an account's budget utilization.
+type Level int
+
+const (
+	// LevelOK means utilization is below the warning threshold, or the
+	// account has no budget to measure against.
+	LevelOK Level = iota
+
+	// LevelWarn means utilization reached the warning threshold.
+	LevelWarn
+
+	// LevelCrit means utilization reached the critical threshold.
+	LevelCrit
+)
+
+// String returns the lowercase name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelWarn:
+		return "warn"
+	case LevelCrit:
+		return "crit"
+	default:
+		return "ok"
+	}
+}
+
+// Thresholds returns the account's warning and critical thresholds, falling
+// back to DefaultWarnThreshold and DefaultCritThreshold for unset values.
+func (a AccountUsage) Thresholds() (warn, crit float64) {
+	return thresholdsOrDefault(a.WarnThreshold, a.CritThreshold)
+}
+
+// thresholdsOrDefault replaces unset thresholds with the defaults.
+func thresholdsOrDefault(warn, crit float64) (float64, float64) {
+	if warn <= 0 {
+		warn = DefaultWarnThreshold
+	}
+	if crit <= 0 {
+		crit = DefaultCritThreshold
+	}
+	return warn, crit
+}
+
+// Level classifies the account's utilization against its thresholds.
+// Disconnected accounts and accounts without a budget are always LevelOK.
+func (a AccountUsage) Level() Level {
+	return a.levelAt(a.Utilization)
+}
+
+// PeakLevel is Level evaluated against whichever of the monthly and
+// seven-day utilizations is higher.
+func (a AccountUsage) PeakLevel() Level {
+	return a.levelAt(a.PeakUtilization())
+}
+
+// PeakUtilization returns the higher of the monthly and seven-day
+// utilizations.
+func (a AccountUsage) PeakUtilization() float64 {
+	if a.SevenDayUtilization > a.Utilization {
+		return a.SevenDayUtilization
+	}
+	return a.Utilization
+}
+
+// levelAt classifies util against the account's thresholds.
+func (a AccountUsage) levelAt(util float64) Level {
+	if !a.Connected || a.BudgetUSD <= 0 {
+		return LevelOK
+	}
+	warn, crit := a.Thresholds()
+	return levelFor(util, warn, crit)
+}
+
+// levelFor classifies util against warn and crit.
+func levelFor(util, warn, crit float64) Level {
+	switch {
+	case util >= crit:
+		return LevelCrit
+	case util >= warn:
+		return LevelWarn
+	default:
+		return LevelOK
+	}
+}
+
+// Level returns the most severe level across all accounts and
+// organizations in the report.
+func (r *UsageReport) Level() Level {
+	worst := r.orgLevel()
+	for _, a := range r.Accounts {
+		if l := a.Level(); l > worst {
+			worst = l
+		}
+	}
+	return worst
+}
+
+// PeakLevel returns the most severe PeakLevel across all accounts, and
+// the most severe organization level.
+func (r *UsageReport) PeakLevel() Level {
+	worst := r.orgLevel()
+	for _, a := range r.Accounts {
+		if l := a.PeakLevel(); l > worst {
+			worst = l
+		}
+	}
+	return worst
+}
+
+// orgLevel returns the most severe level across the report's
+// organizations. Their levels already cover both windows.
+func (r *UsageReport) orgLevel() Level {
+	worst := LevelOK
+	for _, o := range r.Orgs {
+		if l := o.Level(); l > worst {
+			worst = l
+		}
+	}
+	return worst
+}
+
+// HasBudgets reports whether any account in the report carries a budget, in
+// which case per-account levels are meaningful.
+func (r *UsageReport) HasBudgets() bool {
+	for _, a := range r.Accounts {
+		if a.BudgetUSD > 0 {
+			return true
+		}
+	}
+	return false
+}
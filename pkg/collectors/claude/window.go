@@ -0,0 +1,30 @@
+package claude
+
+import "time"
+
+// MonthWindow returns the length of the monthly budget window that ends at
+// ResetsAt, or zero when ResetsAt is unknown.
+func (a AccountUsage) MonthWindow() time.Duration {
+	if a.ResetsAt.IsZero() {
+		return 0
+	}
+	return a.ResetsAt.Sub(a.ResetsAt.AddDate(0, -1, 0))
+}
+
+// WindowProgress returns how far through a usage window of the given length
+// now lies, as a fraction from 0 (just reset) to 1 (about to reset). The
+// window is taken to end at ResetsAt. It returns 0 when ResetsAt is unknown
+// or window is not positive.
+func (a AccountUsage) WindowProgress(window time.Duration, now time.Time) float64 {
+	if a.ResetsAt.IsZero() || window <= 0 {
+		return 0
+	}
+	p := 1 - float64(a.ResetsAt.Sub(now))/float64(window)
+	switch {
+	case p < 0:
+		return 0
+	case p > 1:
+		return 1
+	}
+	return p
+}
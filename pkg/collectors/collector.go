@@ -39,6 +39,18 @@ type CollectorStatus struct {
 	RunCount    int64
 	ErrorCount  int64
 	LastLatency time.Duration
+
+	// ConsecutiveTimeouts counts watchdog timeouts since the last
+	// collection that finished in time.
+	ConsecutiveTimeouts int
+
+	// ConsecutiveFailures counts failed collections, timeouts included,
+	// since the last successful one.
+	ConsecutiveFailures int
+
+	// CircuitRetryAt is when an open circuit lets the collector run again;
+	// zero while the circuit is closed (see Circuit).
+	CircuitRetryAt time.Time
 }
 
 // Update carries the result of a single collection cycle from a collector
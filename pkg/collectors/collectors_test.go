@@ -1,12 +1,18 @@
 package collectors
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/logging"
 )
 
 // --- Registry Tests ---
@@ -513,6 +519,46 @@ func TestRunnerRunOnceWithError(t *testing.T) {
 	}
 }
 
+func TestRunnerLogsCollectorErrorWhenVerbose(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := logging.New(&buf, true, logging.FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := slog.Default()
+	slog.SetDefault(l)
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	r := NewRegistry()
+	_ = r.Register(NewMockCollector("ok", time.Hour, WithData("ping")))
+	_ = r.Register(NewMockCollector("errorer", time.Hour, WithError(errors.New("auth failed"))))
+	runner := NewRunner(r, make(chan Update, DefaultUpdateBufferSize))
+	for _, name := range []string{"ok", "errorer"} {
+		c, _ := r.Get(name)
+		runner.collectAndSend(context.Background(), c)
+	}
+
+	var sawDebug, sawError bool
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("log line is not JSON: %q", line)
+		}
+		switch {
+		case rec["level"] == "DEBUG" && rec["collector"] == "ok":
+			sawDebug = true
+		case rec["level"] == "ERROR" && rec["collector"] == "errorer":
+			sawError = rec["err"] == "auth failed"
+		}
+	}
+	if !sawError {
+		t.Errorf("expected an ERROR record for the failing collector, got:\n%s", buf.String())
+	}
+	if !sawDebug {
+		t.Errorf("verbose logging should include debug timings, got:\n%s", buf.String())
+	}
+}
+
 func TestRunnerHealth(t *testing.T) {
 	r := NewRegistry()
 	_ = r.Register(NewMockCollector("good", time.Hour, WithData("ok")))
@@ -799,6 +845,358 @@ func TestRunnerRunOnceUpdatesStatus(t *testing.T) {
 	}
 }
 
+func TestRunnerWatchdogCancelsBlockedCollector(t *testing.T) {
+	r := NewRegistry()
+	cancelled := make(chan struct{})
+	_ = r.Register(NewMockCollector("blocker", 10*time.Millisecond,
+		WithCollectFunc(func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		})))
+	runner := NewRunner(r, make(chan Update, DefaultUpdateBufferSize))
+
+	start := time.Now()
+	_, err := runner.RunOnce(context.Background(), "blocker")
+	if !errors.Is(err, ErrCollectTimeout) {
+		t.Fatalf("RunOnce error = %v, want ErrCollectTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("watchdog took %v to cancel a 20ms deadline", elapsed)
+	}
+	select {
+	case <-cancelled:
+	default:
+		t.Error("collector context was not cancelled")
+	}
+
+	s, _ := r.Status("blocker")
+	if s.ErrorCount != 1 || s.ConsecutiveTimeouts != 1 {
+		t.Errorf("ErrorCount = %d, ConsecutiveTimeouts = %d; want 1, 1", s.ErrorCount, s.ConsecutiveTimeouts)
+	}
+	if !errors.Is(s.LastError, ErrCollectTimeout) {
+		t.Errorf("LastError = %v, want ErrCollectTimeout", s.LastError)
+	}
+	if !s.Healthy {
+		t.Error("a single timeout should not mark the collector unhealthy")
+	}
+}
+
+func TestRunnerWatchdogMarksUnhealthyAfterRepeatedTimeouts(t *testing.T) {
+	r := NewRegistry()
+	release := make(chan struct{})
+	var calls callCounter
+	_ = r.Register(NewMockCollector("hung", 10*time.Millisecond,
+		WithCollectFunc(func(ctx context.Context) (interface{}, error) {
+			calls.inc()
+			<-release // ignores ctx
+			return "ok", nil
+		})))
+	runner := NewRunner(r, make(chan Update, DefaultUpdateBufferSize))
+
+	for i := 1; i <= UnhealthyAfterTimeouts; i++ {
+		if _, err := runner.RunOnce(context.Background(), "hung"); !errors.Is(err, ErrCollectTimeout) {
+			t.Fatalf("run %d: error = %v, want ErrCollectTimeout", i, err)
+		}
+		s, _ := r.Status("hung")
+		if want := i < UnhealthyAfterTimeouts; s.Healthy != want {
+			t.Errorf("run %d: Healthy = %v, want %v", i, s.Healthy, want)
+		}
+	}
+	if n := calls.get(); n != 1 {
+		t.Errorf("Collect called %d times while the first call hung, want 1", n)
+	}
+
+	// Once the hung call returns, the watchdog keeps trying and recovers.
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		data, err := runner.RunOnce(context.Background(), "hung")
+		if err == nil {
+			if data != "ok" {
+				t.Errorf("data = %v, want ok", data)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("collector did not recover after release: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	s, _ := r.Status("hung")
+	if !s.Healthy || s.ConsecutiveTimeouts != 0 {
+		t.Errorf("after recovery Healthy = %v, ConsecutiveTimeouts = %d", s.Healthy, s.ConsecutiveTimeouts)
+	}
+	if s.ErrorCount < UnhealthyAfterTimeouts {
+		t.Errorf("ErrorCount = %d, want at least %d", s.ErrorCount, UnhealthyAfterTimeouts)
+	}
+}
+
+func TestRunnerSkipsDisabledCollector(t *testing.T) {
+	r := NewRegistry()
+	off := NewMockCollector("off", 10*time.Millisecond, WithData("x"))
+	on := NewMockCollector("on", 10*time.Millisecond, WithData("y"))
+	_ = r.Register(off)
+	_ = r.Register(on)
+	runner := NewRunner(r, make(chan Update, DefaultUpdateBufferSize))
+
+	if err := runner.SetEnabled("off", false); err != nil {
+		t.Fatalf("SetEnabled error: %v", err)
+	}
+	if err := runner.SetEnabled("missing", false); err == nil {
+		t.Error("SetEnabled on an unknown collector should fail")
+	}
+	if got := runner.Disabled(); len(got) != 1 || got[0] != "off" {
+		t.Errorf("Disabled() = %v, want [off]", got)
+	}
+
+	if err := runner.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	runner.Stop()
+
+	if n := off.CallCount(); n != 0 {
+		t.Errorf("disabled collector ran %d times", n)
+	}
+	if on.CallCount() == 0 {
+		t.Error("enabled collector never ran")
+	}
+	if _, err := runner.RunOnce(context.Background(), "off"); !errors.Is(err, ErrCollectorDisabled) {
+		t.Errorf("RunOnce(disabled) error = %v, want ErrCollectorDisabled", err)
+	}
+
+	_ = runner.SetEnabled("off", true)
+	if _, err := runner.RunOnce(context.Background(), "off"); err != nil {
+		t.Errorf("RunOnce after re-enable error: %v", err)
+	}
+	if len(runner.Disabled()) != 0 {
+		t.Errorf("Disabled() = %v after re-enable", runner.Disabled())
+	}
+}
+
+func TestRunnerCircuitBreaker(t *testing.T) {
+	r := NewRegistry()
+	failing := NewMockCollector("billing", time.Hour, WithError(errors.New("401 revoked key")))
+	_ = r.Register(failing)
+	runner := NewRunner(r, make(chan Update, DefaultUpdateBufferSize))
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	runner.nowFunc = func() time.Time { return now }
+	runner.SetCircuitBreaker(&CircuitBreaker{Threshold: 3, Cooldown: 10 * time.Minute})
+	ctx := context.Background()
+	circuit := func() CircuitState {
+		s, _ := r.Status("billing")
+		return s.Circuit(now)
+	}
+
+	for i := 0; i < 3; i++ {
+		if circuit() != CircuitClosed {
+			t.Fatalf("circuit %s after %d failures, want closed", circuit(), i)
+		}
+		runner.collectAndSend(ctx, failing)
+	}
+	if circuit() != CircuitOpen {
+		t.Fatalf("circuit %s after 3 failures, want open", circuit())
+	}
+
+	// Scheduled collections are skipped until the cooldown ends.
+	now = now.Add(9 * time.Minute)
+	runner.collectAndSend(ctx, failing)
+	if n := failing.CallCount(); n != 3 {
+		t.Errorf("collector ran %d times with the circuit open, want 3", n)
+	}
+
+	// Then one trial runs; failing it reopens the circuit.
+	now = now.Add(time.Minute)
+	if circuit() != CircuitHalfOpen {
+		t.Fatalf("circuit %s after the cooldown, want half-open", circuit())
+	}
+	runner.collectAndSend(ctx, failing)
+	if n := failing.CallCount(); n != 4 || circuit() != CircuitOpen {
+		t.Errorf("after a failed trial: %d calls, circuit %s; want 4, open", n, circuit())
+	}
+
+	// A successful trial closes it.
+	now = now.Add(10 * time.Minute)
+	failing.SetError(nil)
+	runner.collectAndSend(ctx, failing)
+	if s, _ := r.Status("billing"); circuit() != CircuitClosed || s.ConsecutiveFailures != 0 {
+		t.Errorf("after a successful trial: circuit %s, %d failures; want closed, 0", circuit(), s.ConsecutiveFailures)
+	}
+}
+
+func TestRunnerCircuitBreakerDisabled(t *testing.T) {
+	r := NewRegistry()
+	failing := NewMockCollector("billing", time.Hour, WithError(errors.New("boom")))
+	_ = r.Register(failing)
+	runner := NewRunner(r, make(chan Update, DefaultUpdateBufferSize))
+
+	for i := 0; i < 10; i++ {
+		runner.collectAndSend(context.Background(), failing)
+	}
+	if n := failing.CallCount(); n != 10 {
+		t.Errorf("collector ran %d times without a breaker, want 10", n)
+	}
+	if s, _ := r.Status("billing"); s.Circuit(time.Now()) != CircuitClosed {
+		t.Errorf("circuit %s without a breaker, want closed", s.Circuit(time.Now()))
+	}
+}
+
+// partCollector is a MockCollector that keeps a circuit per part.
+type partCollector struct {
+	*MockCollector
+	parts PartCircuits
+}
+
+func (p *partCollector) SetPartCircuitBreaker(b *CircuitBreaker) { p.parts.SetBreaker(b) }
+
+func (p *partCollector) PartCircuits(now time.Time) map[string]CircuitState {
+	return p.parts.States(now)
+}
+
+func TestPartCircuits(t *testing.T) {
+	r := NewRegistry()
+	c := &partCollector{MockCollector: NewMockCollector("billing", time.Hour)}
+	_ = r.Register(c)
+	runner := NewRunner(r, make(chan Update, DefaultUpdateBufferSize))
+	runner.SetCircuitBreaker(&CircuitBreaker{Threshold: 2, Cooldown: 10 * time.Minute})
+	p := &c.parts
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	failed := errors.New("401 revoked key")
+
+	// One part failing opens only its own circuit.
+	for i := 0; i < 2; i++ {
+		if err := p.Allow("civo", now); err != nil {
+			t.Fatalf("Allow after %d failures: %v", i, err)
+		}
+		p.Record("civo", failed, now)
+		p.Record("digitalocean", nil, now)
+	}
+	if err := p.Allow("civo", now.Add(9*time.Minute)); err == nil || !errors.Is(err, failed) {
+		t.Errorf("Allow with the circuit open = %v, want an error wrapping %v", err, failed)
+	}
+	if err := p.Allow("digitalocean", now); err != nil {
+		t.Errorf("Allow for a healthy part = %v", err)
+	}
+	if got := p.States(now); len(got) != 1 || got["civo"] != CircuitOpen {
+		t.Errorf("States = %v, want only civo open", got)
+	}
+
+	// After the cooldown one trial is allowed; success forgets the part.
+	now = now.Add(10 * time.Minute)
+	if err := p.Allow("civo", now); err != nil {
+		t.Fatalf("Allow after the cooldown = %v", err)
+	}
+	p.Record("civo", nil, now)
+	if got := p.States(now); got != nil {
+		t.Errorf("States after recovery = %v, want nil", got)
+	}
+}
+
+func TestQuietHoursCacheTTLs(t *testing.T) {
+	q, err := ParseQuietHours("22:00", "07:00", nil, 4, []string{"billing"})
+	if err != nil {
+		t.Fatalf("ParseQuietHours error: %v", err)
+	}
+	ttls := map[string]time.Duration{"claude": 10 * time.Minute, "billing": 30 * time.Minute}
+	at := func(day, h, m int) time.Time { return time.Date(2026, 3, day, h, m, 0, 0, time.Local) }
+
+	tests := []struct {
+		name            string
+		now             time.Time
+		claude, billing time.Duration
+	}{
+		{"before the window", at(4, 21, 0), 10 * time.Minute, 30 * time.Minute},
+		{"inside, same day", at(4, 23, 0), 40 * time.Minute, 90 * time.Minute},
+		{"inside, after midnight", at(5, 6, 0), 40 * time.Minute, 8*time.Hour + 30*time.Minute},
+		{"catching up after the end", at(5, 7, 5), 40 * time.Minute, 9*time.Hour + 35*time.Minute},
+		{"past claude's grace, inside billing's", at(5, 7, 20), 10 * time.Minute, 9*time.Hour + 50*time.Minute},
+		{"back to normal", at(5, 8, 0), 10 * time.Minute, 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		got := q.CacheTTLs(ttls, tt.now)
+		if got["claude"] != tt.claude || got["billing"] != tt.billing {
+			t.Errorf("%s: claude %v, billing %v; want %v, %v", tt.name, got["claude"], got["billing"], tt.claude, tt.billing)
+		}
+	}
+
+	var none *QuietHours
+	if got := none.CacheTTLs(ttls, at(4, 23, 0)); got["claude"] != 10*time.Minute {
+		t.Errorf("nil quiet hours changed the TTLs: %v", got)
+	}
+}
+
+func TestQuietHoursDue(t *testing.T) {
+	q, err := ParseQuietHours("22:00", "07:00", nil, 4, []string{"billing"})
+	if err != nil {
+		t.Fatalf("ParseQuietHours error: %v", err)
+	}
+	interval := 5 * time.Minute
+	last := time.Date(2026, 3, 4, 23, 0, 0, 0, time.Local)
+
+	// Inside the window a 5m collector waits 4x its interval.
+	for _, n := range []int{1, 2, 3} {
+		now := last.Add(time.Duration(n) * interval)
+		if q.Due("k8s", interval, last, now) {
+			t.Errorf("due after %d ticks during quiet hours, want skipped", n)
+		}
+	}
+	if !q.Due("k8s", interval, last, last.Add(4*interval)) {
+		t.Error("not due after 4 ticks during quiet hours")
+	}
+	if q.Due("billing", interval, last, last.Add(time.Hour)) {
+		t.Error("paused collector due during quiet hours")
+	}
+
+	// Past midnight is still inside; after the end every tick is due.
+	early := time.Date(2026, 3, 5, 6, 0, 0, 0, time.Local)
+	if !q.Active(early) {
+		t.Error("06:00 should be inside a 22:00-07:00 window")
+	}
+	day := time.Date(2026, 3, 5, 9, 0, 0, 0, time.Local)
+	if q.Active(day) || !q.Due("billing", interval, day.Add(-interval), day) {
+		t.Error("collectors should run on their own interval outside quiet hours")
+	}
+	var none *QuietHours
+	if !none.Due("k8s", interval, last, last.Add(interval)) {
+		t.Error("nil QuietHours should never hold a collector back")
+	}
+}
+
+func TestQuietHoursDays(t *testing.T) {
+	q, err := ParseQuietHours("23:00", "06:00", []string{"fri", "Saturday"}, 0, nil)
+	if err != nil {
+		t.Fatalf("ParseQuietHours error: %v", err)
+	}
+	if q.Factor != DefaultQuietFactor {
+		t.Errorf("Factor = %g, want default %d", q.Factor, DefaultQuietFactor)
+	}
+	// 2026-03-06 is a Friday.
+	fri := time.Date(2026, 3, 6, 23, 30, 0, 0, time.Local)
+	if !q.Active(fri) || !q.Active(fri.Add(5*time.Hour)) {
+		t.Error("Friday night through Saturday morning should be quiet")
+	}
+	thu := fri.AddDate(0, 0, -1)
+	if q.Active(thu) {
+		t.Error("Thursday night should not be quiet")
+	}
+	if q.Active(fri.Add(-20 * time.Hour)) {
+		t.Error("early Friday belongs to Thursday's window and should not be quiet")
+	}
+
+	for _, bad := range [][2]string{{"25:00", "06:00"}, {"23:00", "6am"}, {"23:00", "23:00"}} {
+		if _, err := ParseQuietHours(bad[0], bad[1], nil, 0, nil); err == nil {
+			t.Errorf("ParseQuietHours(%q, %q) should fail", bad[0], bad[1])
+		}
+	}
+	if _, err := ParseQuietHours("23:00", "06:00", []string{"someday"}, 0, nil); err == nil {
+		t.Error("unknown day should fail")
+	}
+	if _, err := ParseQuietHours("23:00", "06:00", nil, 0.5, nil); err == nil {
+		t.Error("factor below 1 should fail")
+	}
+}
+
 // --- helpers ---
 
 type callCounter struct {
@@ -0,0 +1,171 @@
+// Package command implements a collector that runs an external program on
+// an interval and reports the JSON it prints. It lets private metrics
+// sources appear on the banner without being built into prompt-pulse.
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default configuration values.
+const (
+	DefaultInterval = 5 * time.Minute
+	DefaultTimeout  = 10 * time.Second
+)
+
+// maxStderr caps how much of a failing command's stderr is logged.
+const maxStderr = 512
+
+// waitDelay bounds how long a run waits for the output pipes once the
+// program has exited or been killed, which a background child that
+// inherited them would otherwise hold open.
+const waitDelay = 500 * time.Millisecond
+
+// Config holds the configuration for one command collector.
+type Config struct {
+	// Name is the collector and cache key name.
+	Name string
+
+	// Exec is the program to run; Args are passed to it unchanged. The
+	// program is run directly, not through a shell.
+	Exec string
+	Args []string
+
+	// Interval is how often the program runs. Zero uses DefaultInterval.
+	Interval time.Duration
+
+	// Timeout bounds each run; the program and every process it started
+	// are killed when it expires. Zero uses DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Result is the JSON a command prints on stdout, and the data the collector
+// reports. Title is the banner section heading (default: the collector
+// name) and Data holds the values shown as key/value lines.
+type Result struct {
+	Title     string                 `json:"title,omitempty"`
+	Data      map[string]interface{} `json:"data"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// Collector runs an external program and decodes its output.
+type Collector struct {
+	cfg      Config
+	interval time.Duration
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	healthy bool
+}
+
+// New creates a command collector, applying defaults for a zero Interval or
+// Timeout.
+func New(cfg Config) *Collector {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Collector{
+		cfg:      cfg,
+		interval: interval,
+		timeout:  timeout,
+		healthy:  true,
+	}
+}
+
+// Name returns the configured collector name.
+func (c *Collector) Name() string { return c.cfg.Name }
+
+// Interval returns how often this collector should run.
+func (c *Collector) Interval() time.Duration { return c.interval }
+
+// Healthy returns whether the last collection succeeded.
+func (c *Collector) Healthy() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.healthy
+}
+
+func (c *Collector) setHealthy(v bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.healthy = v
+}
+
+// Collect runs the program once and decodes its stdout as a Result. A
+// timeout, non-zero exit, or invalid JSON is returned as an error and
+// logged as a warning together with the program's stderr.
+func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
+	res, stderr, err := c.run(ctx)
+	if err != nil {
+		c.setHealthy(false)
+		slog.Warn("command: collector failed", "collector", c.cfg.Name, "exec", c.cfg.Exec,
+			"err", err, "stderr", stderr)
+		return nil, err
+	}
+	c.setHealthy(true)
+	return res, nil
+}
+
+// run executes the program once in its own process group. Whatever is
+// left of the group when the program exits, such as a child it put in the
+// background, is killed, and output written after the program exited is
+// not waited for beyond waitDelay.
+func (c *Collector) run(ctx context.Context) (*Result, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.cfg.Exec, c.cfg.Args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+	start := time.Now()
+	err := cmd.Run()
+	_ = killProcessGroup(cmd)
+	errOut := tail(stderr.String(), maxStderr)
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return nil, errOut, fmt.Errorf("command %s: timed out after %s", c.cfg.Name, c.timeout)
+	case err != nil && !errors.Is(err, exec.ErrWaitDelay):
+		// ErrWaitDelay means the program itself succeeded; a leftover
+		// child kept the pipes open.
+		return nil, errOut, fmt.Errorf("command %s: %w", c.cfg.Name, err)
+	}
+
+	var res Result
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		return nil, errOut, fmt.Errorf("command %s: invalid JSON output: %w", c.cfg.Name, err)
+	}
+	if res.Data == nil {
+		return nil, errOut, fmt.Errorf("command %s: output has no \"data\" object", c.cfg.Name)
+	}
+	if res.Timestamp.IsZero() {
+		res.Timestamp = start
+	}
+	return &res, errOut, nil
+}
+
+// tail returns the last n bytes of s, trimmed of surrounding whitespace.
+func tail(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) > n {
+		s = "…" + s[len(s)-n:]
+	}
+	return s
+}
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollect_DecodesOutput(t *testing.T) {
+	c := New(Config{Name: "buildfarm", Exec: "testdata/ok.sh", Args: []string{"main"}})
+	data, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error: %v", err)
+	}
+	res := data.(*Result)
+	if res.Title != "Build farm" {
+		t.Errorf("Title = %q, want Build farm", res.Title)
+	}
+	if res.Data["queue"] != 4.0 || res.Data["workers"] != "3/4" || res.Data["arg"] != "main" {
+		t.Errorf("Data = %v", res.Data)
+	}
+	if res.Timestamp.IsZero() {
+		t.Error("Timestamp should default to the run time")
+	}
+	if !c.Healthy() || c.Name() != "buildfarm" || c.Interval() != DefaultInterval {
+		t.Errorf("Healthy=%v Name=%q Interval=%v", c.Healthy(), c.Name(), c.Interval())
+	}
+}
+
+func TestCollect_Errors(t *testing.T) {
+	tests := []struct {
+		name, exec, want string
+	}{
+		{"non-zero exit", "testdata/fail.sh", "exit status 3"},
+		{"invalid JSON", "testdata/badjson.sh", "invalid JSON"},
+		{"timeout", "testdata/slow.sh", "timed out"},
+		{"missing program", "testdata/nope.sh", "command buildfarm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{Name: "buildfarm", Exec: tt.exec, Timeout: 200 * time.Millisecond})
+			start := time.Now()
+			_, err := c.Collect(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Collect() error = %v, want one containing %q", err, tt.want)
+			}
+			if c.Healthy() {
+				t.Error("collector should be unhealthy after an error")
+			}
+			if time.Since(start) > 3*time.Second {
+				t.Errorf("Collect took %v; the timeout was not enforced", time.Since(start))
+			}
+		})
+	}
+}
+
+func TestCollect_BackgroundChild(t *testing.T) {
+	// The background sleep inherits stdout; Collect must not wait for it.
+	c := New(Config{Name: "buildfarm", Exec: "sh", Args: []string{"-c", "sleep 600 & echo hi"}})
+	start := time.Now()
+	_, err := c.Collect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+		t.Errorf("Collect() error = %v, want invalid JSON", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Errorf("Collect took %v; it waited on the background child", time.Since(start))
+	}
+
+	c = New(Config{Name: "buildfarm", Exec: "sh", Args: []string{"-c", `sleep 600 & echo '{"data":{"ok":true}}'`}})
+	data, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error: %v", err)
+	}
+	if res := data.(*Result); res.Data["ok"] != true {
+		t.Errorf("Data = %v", res.Data)
+	}
+}
+
+func TestCollect_TimeoutKillsProcessGroup(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "late")
+	c := New(Config{
+		Name:    "buildfarm",
+		Exec:    "sh",
+		Args:    []string{"-c", `(sleep 1; touch "$0") & sleep 600`, marker},
+		Timeout: 200 * time.Millisecond,
+	})
+	start := time.Now()
+	if _, err := c.Collect(context.Background()); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Collect() error = %v, want a timeout", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Errorf("Collect took %v; the timeout was not enforced", time.Since(start))
+	}
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("the background child outlived the timeout")
+	}
+}
+
+func TestTail(t *testing.T) {
+	if got := tail("  short\n", 10); got != "short" {
+		t.Errorf("tail(short) = %q", got)
+	}
+	if got := tail("0123456789", 4); got != "…6789" {
+		t.Errorf("tail(long) = %q", got)
+	}
+}
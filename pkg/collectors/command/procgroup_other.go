@@ -0,0 +1,12 @@
+//go:build !unix
+
+package command
+
+import "os/exec"
+
+// setProcessGroup is a no-op on platforms without process groups; only the
+// program itself is killed on cancel.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup is a no-op on platforms without process groups.
+func killProcessGroup(cmd *exec.Cmd) error { return nil }
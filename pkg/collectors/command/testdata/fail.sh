@@ -0,0 +1,3 @@
+#!/bin/sh
+echo "buildfarm: connection refused" >&2
+exit 3
@@ -0,0 +1,3 @@
+#!/bin/sh
+# Prints a command collector result, echoing the first argument as a value.
+printf '{"title": "Build farm", "data": {"queue": 4, "workers": "3/4", "arg": "%s"}}\n' "$1"
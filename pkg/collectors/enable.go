@@ -0,0 +1,49 @@
+package collectors
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrCollectorDisabled is wrapped by the error RunOnce returns for a
+// collector switched off with SetEnabled.
+var ErrCollectorDisabled = errors.New("collector is disabled")
+
+// SetEnabled switches the named collector on or off. A disabled collector
+// keeps its goroutine but skips every collection until it is enabled again,
+// so its cached data ages out instead of being refreshed. The setting lives
+// only in the runner and is not persisted.
+func (r *Runner) SetEnabled(name string, enabled bool) error {
+	if _, ok := r.registry.Get(name); !ok {
+		return fmt.Errorf("collector %q not found", name)
+	}
+	r.disabledMu.Lock()
+	defer r.disabledMu.Unlock()
+	if enabled {
+		delete(r.disabled, name)
+	} else {
+		r.disabled[name] = true
+	}
+	return nil
+}
+
+// Enabled reports whether the named collector runs on its schedule. All
+// collectors start enabled.
+func (r *Runner) Enabled(name string) bool {
+	r.disabledMu.RLock()
+	defer r.disabledMu.RUnlock()
+	return !r.disabled[name]
+}
+
+// Disabled returns the sorted names of the disabled collectors.
+func (r *Runner) Disabled() []string {
+	r.disabledMu.RLock()
+	defer r.disabledMu.RUnlock()
+	names := make([]string, 0, len(r.disabled))
+	for name := range r.disabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
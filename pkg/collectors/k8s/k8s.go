@@ -5,7 +5,9 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -13,13 +15,17 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 // Default values for collector configuration.
 const (
-	defaultInterval = 15 * time.Second
+	defaultInterval       = 15 * time.Second
+	defaultContextTimeout = 10 * time.Second
 )
 
 // ---------- Configuration ----------
@@ -40,27 +46,80 @@ type Config struct {
 	// Namespaces restricts collection to specific namespaces. If empty,
 	// all namespaces are queried.
 	Namespaces []string
+
+	// PodPressureThreshold is the percentage of schedulable pod capacity
+	// at which a cluster is reported as under pod pressure. Defaults to
+	// DefaultPodPressureThreshold.
+	PodPressureThreshold float64
+
+	// ContextTimeout bounds the collection of a single context so one
+	// unreachable cluster cannot stall the others. Defaults to 10s.
+	ContextTimeout time.Duration
+
+	// Overrides holds per-context settings keyed by context name. A
+	// non-empty field replaces the collector-wide Kubeconfig or Namespaces
+	// for that context; DashboardURL is copied to the context's ClusterInfo.
+	Overrides map[string]ContextConfig
+}
+
+// ContextConfig holds settings that apply to a single kubeconfig context.
+type ContextConfig struct {
+	Kubeconfig   string
+	Namespaces   []string
+	DashboardURL string
 }
 
 // ---------- Result types ----------
 
 // ClusterStatus is the top-level data returned by Collect.
 type ClusterStatus struct {
-	Clusters  []ClusterInfo `json:"clusters"`
-	Timestamp time.Time     `json:"timestamp"`
+	Clusters             []ClusterInfo `json:"clusters"`
+	PodPressureThreshold float64       `json:"pod_pressure_threshold,omitempty"`
+	Timestamp            time.Time     `json:"timestamp"`
 }
 
+// Cluster states reported in ClusterInfo.Status.
+const (
+	// StatusOnline means the cluster's nodes could be listed.
+	StatusOnline = "online"
+
+	// StatusOffline means the cluster could not be reached within the
+	// context timeout, or no client could be built for it. Error says why.
+	StatusOffline = "offline"
+)
+
 // ClusterInfo holds status information for a single Kubernetes context.
 type ClusterInfo struct {
-	Context     string          `json:"context"`
-	Connected   bool            `json:"connected"`
-	Error       string          `json:"error,omitempty"`
+	Context   string `json:"context"`
+	Connected bool   `json:"connected"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+
+	// Version is the API server's git version, e.g. "v1.31.2". Empty when
+	// the version endpoint could not be read.
+	Version string `json:"version,omitempty"`
+
+	TotalNodes  int             `json:"total_nodes"`
+	ReadyNodes  int             `json:"ready_nodes"`
 	Nodes       []NodeInfo      `json:"nodes,omitempty"`
 	Namespaces  []NamespaceInfo `json:"namespaces,omitempty"`
 	TotalPods   int             `json:"total_pods"`
 	RunningPods int             `json:"running_pods"`
 	PendingPods int             `json:"pending_pods"`
 	FailedPods  int             `json:"failed_pods"`
+
+	// Warnings lists non-fatal problems, such as metrics-server being
+	// unavailable, that leave some fields at their zero value.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// DashboardURL is the cluster's web console, from the context's
+	// configured override. Empty when none is configured.
+	DashboardURL string `json:"dashboard_url,omitempty"`
+
+	// Region is the cloud region of the cluster's nodes, from the first
+	// node carrying the topology.kubernetes.io/region label. Empty when
+	// none does.
+	Region string `json:"region,omitempty"`
 }
 
 // NodeInfo holds status and resource information for a single node.
@@ -75,7 +134,15 @@ type NodeInfo struct {
 	MemRequests string   `json:"mem_requests"`
 	MemLimits   string   `json:"mem_limits"`
 	PodCount    int      `json:"pod_count"`
+	MaxPods     int      `json:"max_pods,omitempty"`
 	Conditions  []string `json:"conditions,omitempty"`
+
+	// Live usage from metrics-server, and as a percentage of allocatable
+	// resources. Zero when metrics-server is not installed.
+	CPUUsage   string  `json:"cpu_usage,omitempty"`
+	MemUsage   string  `json:"mem_usage,omitempty"`
+	CPUPercent float64 `json:"cpu_percent"`
+	MemPercent float64 `json:"mem_percent"`
 }
 
 // NamespaceInfo holds pod and deployment information for a single namespace.
@@ -115,13 +182,47 @@ type K8sClient interface {
 	ListNamespaces(ctx context.Context) ([]corev1.Namespace, error)
 }
 
-// realClient wraps a kubernetes.Clientset to implement K8sClient.
+// MetricsClient is implemented by clients that can read live node usage from
+// the metrics.k8s.io API served by metrics-server. The result maps node name
+// to its usage.
+type MetricsClient interface {
+	ListNodeMetrics(ctx context.Context) (map[string]corev1.ResourceList, error)
+}
+
+// VersionClient is implemented by clients that can read the API server's
+// version from its /version endpoint.
+type VersionClient interface {
+	ServerVersion(ctx context.Context) (string, error)
+}
+
+// nodeMetricsPath is the metrics-server endpoint listing usage for all nodes.
+const nodeMetricsPath = "/apis/metrics.k8s.io/v1beta1/nodes"
+
+// nodeMetricsList is the subset of metrics.k8s.io/v1beta1 NodeMetricsList
+// the collector reads.
+type nodeMetricsList struct {
+	Items []struct {
+		Metadata metav1.ObjectMeta   `json:"metadata"`
+		Usage    corev1.ResourceList `json:"usage"`
+	} `json:"items"`
+}
+
+// realClient wraps the typed client-go clients of a clientset to implement
+// K8sClient, MetricsClient, and VersionClient. Holding the group clients
+// rather than the clientset lets tests substitute client-go's fakes.
 type realClient struct {
-	cs *kubernetes.Clientset
+	core      corev1client.CoreV1Interface
+	apps      appsv1client.AppsV1Interface
+	discovery discovery.DiscoveryInterface
+}
+
+// newRealClient returns a realClient backed by cs.
+func newRealClient(cs kubernetes.Interface) *realClient {
+	return &realClient{core: cs.CoreV1(), apps: cs.AppsV1(), discovery: cs.Discovery()}
 }
 
 func (r *realClient) ListNodes(ctx context.Context) ([]corev1.Node, error) {
-	list, err := r.cs.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	list, err := r.core.Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +230,7 @@ func (r *realClient) ListNodes(ctx context.Context) ([]corev1.Node, error) {
 }
 
 func (r *realClient) ListPods(ctx context.Context, namespace string) ([]corev1.Pod, error) {
-	list, err := r.cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	list, err := r.core.Pods(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +238,7 @@ func (r *realClient) ListPods(ctx context.Context, namespace string) ([]corev1.P
 }
 
 func (r *realClient) ListDeployments(ctx context.Context, namespace string) ([]appsv1.Deployment, error) {
-	list, err := r.cs.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	list, err := r.apps.Deployments(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -145,13 +246,65 @@ func (r *realClient) ListDeployments(ctx context.Context, namespace string) ([]a
 }
 
 func (r *realClient) ListNamespaces(ctx context.Context) ([]corev1.Namespace, error) {
-	list, err := r.cs.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	list, err := r.core.Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 	return list.Items, nil
 }
 
+// ListNodeMetrics queries metrics-server through the clientset's REST client,
+// which avoids depending on the generated metrics clientset.
+func (r *realClient) ListNodeMetrics(ctx context.Context) (map[string]corev1.ResourceList, error) {
+	rc := r.discovery.RESTClient()
+	if rc == nil {
+		return nil, fmt.Errorf("no REST client for %s", nodeMetricsPath)
+	}
+	raw, err := rc.Get().AbsPath(nodeMetricsPath).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseNodeMetrics(raw)
+}
+
+// ServerVersion reads the API server's version. The discovery client does
+// not take a context, so the call is abandoned rather than cancelled when
+// ctx ends first.
+func (r *realClient) ServerVersion(ctx context.Context) (string, error) {
+	type result struct {
+		version string
+		err     error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		info, err := r.discovery.ServerVersion()
+		if err != nil {
+			ch <- result{err: err}
+			return
+		}
+		ch <- result{version: info.GitVersion}
+	}()
+	select {
+	case res := <-ch:
+		return res.version, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// parseNodeMetrics decodes a NodeMetricsList response body.
+func parseNodeMetrics(raw []byte) (map[string]corev1.ResourceList, error) {
+	var list nodeMetricsList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("decode node metrics: %w", err)
+	}
+	usage := make(map[string]corev1.ResourceList, len(list.Items))
+	for _, item := range list.Items {
+		usage[item.Metadata.Name] = item.Usage
+	}
+	return usage, nil
+}
+
 // ---------- clientFactory ----------
 
 // clientFactory creates K8sClient instances for a given kubeconfig context.
@@ -176,7 +329,27 @@ func defaultClientFactory(kubeconfig, ctxName string) (K8sClient, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create clientset: %w", err)
 	}
-	return &realClient{cs: cs}, nil
+	return newRealClient(cs), nil
+}
+
+// ContextNames returns the context names defined in the kubeconfig at path,
+// or in the default loading chain ($KUBECONFIG, ~/.kube/config) when path is
+// empty. It only reads the file and never contacts a cluster.
+func ContextNames(kubeconfig string) ([]string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		rules.ExplicitPath = kubeconfig
+	}
+	raw, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig: %w", err)
+	}
+	names := make([]string, 0, len(raw.Contexts))
+	for name := range raw.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
 }
 
 // ---------- Collector ----------
@@ -195,6 +368,12 @@ func New(cfg Config) *Collector {
 	if cfg.Interval <= 0 {
 		cfg.Interval = defaultInterval
 	}
+	if cfg.PodPressureThreshold <= 0 {
+		cfg.PodPressureThreshold = DefaultPodPressureThreshold
+	}
+	if cfg.ContextTimeout <= 0 {
+		cfg.ContextTimeout = defaultContextTimeout
+	}
 	return &Collector{
 		cfg:     cfg,
 		factory: defaultClientFactory,
@@ -229,6 +408,8 @@ func (c *Collector) setHealthy(h bool) {
 }
 
 // Collect gathers Kubernetes cluster status from all configured contexts.
+// Contexts are collected concurrently, each bounded by ContextTimeout, and
+// reported in config order.
 // On success, Healthy() returns true. On total failure, Healthy() returns false
 // but a partial ClusterStatus with error details is still returned (not a Go error).
 func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
@@ -239,15 +420,25 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 	}
 
 	status := &ClusterStatus{
-		Clusters:  make([]ClusterInfo, 0, len(contexts)),
-		Timestamp: time.Now(),
+		Clusters:             make([]ClusterInfo, len(contexts)),
+		PodPressureThreshold: c.cfg.PodPressureThreshold,
+		Timestamp:            time.Now(),
 	}
 
-	anyConnected := false
+	var wg sync.WaitGroup
+	for i, ctxName := range contexts {
+		wg.Add(1)
+		go func(i int, ctxName string) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, c.cfg.ContextTimeout)
+			defer cancel()
+			status.Clusters[i] = c.collectContext(cctx, ctxName)
+		}(i, ctxName)
+	}
+	wg.Wait()
 
-	for _, ctxName := range contexts {
-		info := c.collectContext(ctx, ctxName)
-		status.Clusters = append(status.Clusters, info)
+	anyConnected := false
+	for _, info := range status.Clusters {
 		if info.Connected {
 			anyConnected = true
 		}
@@ -261,11 +452,14 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 // collectContext gathers data for a single kubeconfig context.
 func (c *Collector) collectContext(ctx context.Context, ctxName string) ClusterInfo {
 	info := ClusterInfo{
-		Context: ctxName,
+		Context:      ctxName,
+		DashboardURL: c.cfg.Overrides[ctxName].DashboardURL,
 	}
 
-	client, err := c.factory(c.cfg.Kubeconfig, ctxName)
+	kubeconfig, namespaces := c.contextSettings(ctxName)
+	client, err := c.factory(kubeconfig, ctxName)
 	if err != nil {
+		info.Status = StatusOffline
 		info.Error = err.Error()
 		return info
 	}
@@ -273,30 +467,58 @@ func (c *Collector) collectContext(ctx context.Context, ctxName string) ClusterI
 	// Fetch nodes.
 	nodes, err := client.ListNodes(ctx)
 	if err != nil {
+		info.Status = StatusOffline
 		info.Error = fmt.Sprintf("list nodes: %v", err)
 		return info
 	}
 
 	// We have connectivity if we can list nodes.
 	info.Connected = true
+	info.Status = StatusOnline
+	info.TotalNodes = len(nodes)
+	for i := range nodes {
+		if isNodeReady(&nodes[i]) {
+			info.ReadyNodes++
+		}
+		if info.Region == "" {
+			info.Region = nodes[i].Labels[regionLabel]
+		}
+	}
+
+	if vc, ok := client.(VersionClient); ok {
+		if v, err := vc.ServerVersion(ctx); err != nil {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("server version unavailable: %v", err))
+		} else {
+			info.Version = v
+		}
+	}
 
 	// Determine which namespaces to query.
-	namespacesToQuery, err := c.resolveNamespaces(ctx, client)
+	namespacesToQuery, err := resolveNamespaces(ctx, client, namespaces)
 	if err != nil {
 		// Non-fatal: we already proved connectivity via ListNodes.
 		info.Error = fmt.Sprintf("list namespaces: %v", err)
 	}
+	filtered := len(namespaces) > 0
 
 	// Fetch all pods across target namespaces.
-	allPods, podsByNs := c.collectPods(ctx, client, namespacesToQuery)
+	allPods, podsByNs := collectPods(ctx, client, namespacesToQuery, filtered)
 
 	// Fetch all deployments across target namespaces.
-	deploysByNs := c.collectDeployments(ctx, client, namespacesToQuery)
+	deploysByNs := collectDeployments(ctx, client, namespacesToQuery, filtered)
+
+	// Live usage is best-effort: without metrics-server the percentages
+	// stay zero and the cluster carries a warning instead.
+	usage, err := listNodeMetrics(ctx, client)
+	if err != nil {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("metrics-server unavailable: %v", err))
+	}
 
 	// Build node info (with pod counts per node).
 	podCountsByNode := countPodsByNode(allPods)
 	for i := range nodes {
 		ni := buildNodeInfo(&nodes[i], podCountsByNode, allPods)
+		applyNodeUsage(&ni, &nodes[i], usage[nodes[i].Name])
 		info.Nodes = append(info.Nodes, ni)
 	}
 
@@ -320,10 +542,26 @@ func (c *Collector) collectContext(ctx context.Context, ctxName string) ClusterI
 	return info
 }
 
-// resolveNamespaces returns the list of namespaces to query.
-func (c *Collector) resolveNamespaces(ctx context.Context, client K8sClient) ([]string, error) {
-	if len(c.cfg.Namespaces) > 0 {
-		return c.cfg.Namespaces, nil
+// contextSettings returns the kubeconfig path and namespace filter for
+// ctxName, applying any per-context override.
+func (c *Collector) contextSettings(ctxName string) (string, []string) {
+	kubeconfig, namespaces := c.cfg.Kubeconfig, c.cfg.Namespaces
+	if o, ok := c.cfg.Overrides[ctxName]; ok {
+		if o.Kubeconfig != "" {
+			kubeconfig = o.Kubeconfig
+		}
+		if len(o.Namespaces) > 0 {
+			namespaces = o.Namespaces
+		}
+	}
+	return kubeconfig, namespaces
+}
+
+// resolveNamespaces returns the list of namespaces to query: the configured
+// ones, or every namespace in the cluster when none are configured.
+func resolveNamespaces(ctx context.Context, client K8sClient, configured []string) ([]string, error) {
+	if len(configured) > 0 {
+		return configured, nil
 	}
 	nsList, err := client.ListNamespaces(ctx)
 	if err != nil {
@@ -338,11 +576,11 @@ func (c *Collector) resolveNamespaces(ctx context.Context, client K8sClient) ([]
 
 // collectPods fetches pods from all target namespaces. Returns a flat list
 // of all pods and a per-namespace map.
-func (c *Collector) collectPods(ctx context.Context, client K8sClient, namespaces []string) ([]corev1.Pod, map[string][]corev1.Pod) {
+func collectPods(ctx context.Context, client K8sClient, namespaces []string, filtered bool) ([]corev1.Pod, map[string][]corev1.Pod) {
 	var all []corev1.Pod
 	byNs := make(map[string][]corev1.Pod, len(namespaces))
 
-	if len(c.cfg.Namespaces) > 0 {
+	if filtered {
 		// Fetch per-namespace when filtered.
 		for _, ns := range namespaces {
 			pods, err := client.ListPods(ctx, ns)
@@ -368,10 +606,10 @@ func (c *Collector) collectPods(ctx context.Context, client K8sClient, namespace
 }
 
 // collectDeployments fetches deployments from all target namespaces.
-func (c *Collector) collectDeployments(ctx context.Context, client K8sClient, namespaces []string) map[string][]appsv1.Deployment {
+func collectDeployments(ctx context.Context, client K8sClient, namespaces []string, filtered bool) map[string][]appsv1.Deployment {
 	byNs := make(map[string][]appsv1.Deployment, len(namespaces))
 
-	if len(c.cfg.Namespaces) > 0 {
+	if filtered {
 		for _, ns := range namespaces {
 			deps, err := client.ListDeployments(ctx, ns)
 			if err != nil {
@@ -394,6 +632,9 @@ func (c *Collector) collectDeployments(ctx context.Context, client K8sClient, na
 
 // ---------- Node helpers ----------
 
+// regionLabel is the well-known node label holding its cloud region.
+const regionLabel = "topology.kubernetes.io/region"
+
 // buildNodeInfo constructs a NodeInfo from a corev1.Node and pod data.
 func buildNodeInfo(node *corev1.Node, podCountsByNode map[string]int, allPods []corev1.Pod) NodeInfo {
 	ni := NodeInfo{
@@ -411,6 +652,13 @@ func buildNodeInfo(node *corev1.Node, podCountsByNode map[string]int, allPods []
 		if mem, ok := cap[corev1.ResourceMemory]; ok {
 			ni.MemCapacity = mem.String()
 		}
+		if pods, ok := cap[corev1.ResourcePods]; ok {
+			ni.MaxPods = int(pods.Value())
+		}
+	}
+	// Allocatable is what the scheduler actually uses; prefer it.
+	if pods, ok := node.Status.Allocatable[corev1.ResourcePods]; ok {
+		ni.MaxPods = int(pods.Value())
 	}
 
 	// Resource requests and limits from pods on this node.
@@ -450,6 +698,52 @@ func buildNodeInfo(node *corev1.Node, podCountsByNode map[string]int, allPods []
 	return ni
 }
 
+// listNodeMetrics reads node usage when client supports metrics-server.
+func listNodeMetrics(ctx context.Context, client K8sClient) (map[string]corev1.ResourceList, error) {
+	mc, ok := client.(MetricsClient)
+	if !ok {
+		return nil, nil
+	}
+	return mc.ListNodeMetrics(ctx)
+}
+
+// applyNodeUsage fills the live usage fields of ni from metrics-server data.
+// Percentages are relative to allocatable resources, falling back to
+// capacity for nodes that do not report allocatable.
+func applyNodeUsage(ni *NodeInfo, node *corev1.Node, usage corev1.ResourceList) {
+	if usage == nil {
+		return
+	}
+	if cpu, ok := usage[corev1.ResourceCPU]; ok {
+		ni.CPUUsage = cpu.String()
+		if alloc := nodeAllocatable(node, corev1.ResourceCPU); alloc > 0 {
+			ni.CPUPercent = float64(cpu.MilliValue()) / float64(alloc) * 100
+		}
+	}
+	if mem, ok := usage[corev1.ResourceMemory]; ok {
+		ni.MemUsage = mem.String()
+		if alloc := nodeAllocatable(node, corev1.ResourceMemory); alloc > 0 {
+			ni.MemPercent = float64(mem.Value()) / float64(alloc) * 100
+		}
+	}
+}
+
+// nodeAllocatable returns the allocatable amount of res on node, in
+// millicores for CPU and bytes for memory.
+func nodeAllocatable(node *corev1.Node, res corev1.ResourceName) int64 {
+	q, ok := node.Status.Allocatable[res]
+	if !ok {
+		q, ok = node.Status.Capacity[res]
+	}
+	if !ok {
+		return 0
+	}
+	if res == corev1.ResourceCPU {
+		return q.MilliValue()
+	}
+	return q.Value()
+}
+
 // isNodeReady checks whether a node has a Ready condition set to True.
 func isNodeReady(node *corev1.Node) bool {
 	if node == nil {
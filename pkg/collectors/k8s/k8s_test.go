@@ -3,6 +3,8 @@ package k8s
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +12,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakeappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1/fake"
+	fakecorev1 "k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 // ---------- Mock K8sClient ----------
@@ -556,6 +565,59 @@ func TestCollect_NodeResourceCapacity(t *testing.T) {
 	}
 }
 
+func TestCollect_NodeMaxPods(t *testing.T) {
+	node := makeNode("node-1", true, nil, "4", "8Gi")
+	node.Status.Capacity[corev1.ResourcePods] = resource.MustParse("110")
+	node.Status.Allocatable = corev1.ResourceList{corev1.ResourcePods: resource.MustParse("100")}
+	mock := &mockClient{
+		nodes:      []corev1.Node{node},
+		pods:       map[string][]corev1.Pod{"": {}},
+		namespaces: []corev1.Namespace{makeNamespace("default")},
+	}
+
+	c := newWithFactory(Config{}, mockFactory(mock))
+	result, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	status := result.(*ClusterStatus)
+	if got := status.Clusters[0].Nodes[0].MaxPods; got != 100 {
+		t.Errorf("MaxPods = %d, want 100 (allocatable)", got)
+	}
+	if status.PodPressureThreshold != DefaultPodPressureThreshold {
+		t.Errorf("PodPressureThreshold = %v, want default %v", status.PodPressureThreshold, DefaultPodPressureThreshold)
+	}
+}
+
+func TestPodPressure_NotReadyNodesCountAgainstCapacity(t *testing.T) {
+	c := ClusterInfo{
+		Connected:   true,
+		RunningPods: 95,
+		Nodes: []NodeInfo{
+			{Name: "a", Ready: true, MaxPods: 100, PodCount: 95},
+			{Name: "b", Ready: false, MaxPods: 100},
+		},
+	}
+	if got := c.PodCapacity(); got != 100 {
+		t.Errorf("PodCapacity() = %d, want 100", got)
+	}
+	if got := c.PodPressure(); got != 95 {
+		t.Errorf("PodPressure() = %v, want 95", got)
+	}
+	if !c.UnderPodPressure(0) {
+		t.Error("95% should be under pressure at the default threshold")
+	}
+	if c.UnderPodPressure(96) {
+		t.Error("95% should not be under pressure at a 96% threshold")
+	}
+	if node, pct, ok := c.BusiestNode(); !ok || node.Name != "a" || pct != 95 {
+		t.Errorf("BusiestNode() = %s, %v, %v", node.Name, pct, ok)
+	}
+	if (ClusterInfo{Connected: true, RunningPods: 5}).UnderPodPressure(0) {
+		t.Error("unknown capacity should never report pressure")
+	}
+}
+
 func TestCollect_ClientError_Unhealthy(t *testing.T) {
 	c := newWithFactory(Config{}, errorFactory(errors.New("connection refused")))
 	result, err := c.Collect(context.Background())
@@ -959,6 +1021,24 @@ func TestCollect_TimestampSet(t *testing.T) {
 	}
 }
 
+func TestCollect_Region(t *testing.T) {
+	mock := &mockClient{
+		nodes: []corev1.Node{
+			makeNode("cp", true, nil, "", ""),
+			makeNode("worker", true, map[string]string{"topology.kubernetes.io/region": "lon1"}, "", ""),
+		},
+		pods:       map[string][]corev1.Pod{"": {}},
+		namespaces: []corev1.Namespace{makeNamespace("default")},
+	}
+	result, err := newWithFactory(Config{}, mockFactory(mock)).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if got := result.(*ClusterStatus).Clusters[0].Region; got != "lon1" {
+		t.Errorf("Region = %q, want lon1", got)
+	}
+}
+
 func TestCollect_NilNodeLabels(t *testing.T) {
 	// Node with nil Labels map should not panic.
 	node := corev1.Node{
@@ -1071,3 +1151,303 @@ func TestCollect_DeploymentNilReplicas(t *testing.T) {
 		t.Errorf("ReadyReplicas = %d, want 1", d.ReadyReplicas)
 	}
 }
+
+// ---------- Metrics-server tests ----------
+
+// fakeMetricsClient adds metrics-server support to mockClient.
+type fakeMetricsClient struct {
+	*mockClient
+	usage map[string]corev1.ResourceList
+	err   error
+}
+
+func (f *fakeMetricsClient) ListNodeMetrics(_ context.Context) (map[string]corev1.ResourceList, error) {
+	return f.usage, f.err
+}
+
+func TestCollect_NodeMetricsAgainstAllocatable(t *testing.T) {
+	node := makeNode("node-1", true, nil, "4", "8Gi")
+	// Allocatable is below capacity; percentages must use it.
+	node.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2"),
+		corev1.ResourceMemory: resource.MustParse("4Gi"),
+	}
+	fake := &fakeMetricsClient{
+		mockClient: &mockClient{
+			nodes:      []corev1.Node{node, makeNode("node-2", true, nil, "4", "8Gi")},
+			pods:       map[string][]corev1.Pod{"": {}},
+			namespaces: []corev1.Namespace{makeNamespace("default")},
+		},
+		usage: map[string]corev1.ResourceList{
+			"node-1": {
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("3Gi"),
+			},
+			// node-2 has no allocatable; capacity is used instead.
+			"node-2": {
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		},
+	}
+
+	c := newWithFactory(Config{}, mockFactory(fake))
+	result, _ := c.Collect(context.Background())
+	cluster := result.(*ClusterStatus).Clusters[0]
+
+	n1, n2 := cluster.Nodes[0], cluster.Nodes[1]
+	if n1.CPUPercent != 25 || n1.MemPercent != 75 {
+		t.Errorf("node-1 usage = %.1f%% CPU, %.1f%% mem; want 25, 75", n1.CPUPercent, n1.MemPercent)
+	}
+	if n1.CPUUsage != "500m" || n1.MemUsage != "3Gi" {
+		t.Errorf("node-1 raw usage = %s, %s", n1.CPUUsage, n1.MemUsage)
+	}
+	if n2.CPUPercent != 25 || n2.MemPercent != 25 {
+		t.Errorf("node-2 usage = %.1f%% CPU, %.1f%% mem; want 25, 25", n2.CPUPercent, n2.MemPercent)
+	}
+	if len(cluster.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", cluster.Warnings)
+	}
+}
+
+func TestCollect_MetricsServerMissing(t *testing.T) {
+	fake := &fakeMetricsClient{
+		mockClient: &mockClient{
+			nodes:      []corev1.Node{makeNode("node-1", true, nil, "4", "8Gi")},
+			pods:       map[string][]corev1.Pod{"": {}},
+			namespaces: []corev1.Namespace{makeNamespace("default")},
+		},
+		err: errors.New("the server could not find the requested resource"),
+	}
+
+	c := newWithFactory(Config{}, mockFactory(fake))
+	result, _ := c.Collect(context.Background())
+	cluster := result.(*ClusterStatus).Clusters[0]
+
+	if !cluster.Connected || cluster.Error != "" {
+		t.Errorf("missing metrics-server should not fail the cluster: %+v", cluster)
+	}
+	if len(cluster.Warnings) != 1 || !strings.Contains(cluster.Warnings[0], "metrics-server unavailable") {
+		t.Errorf("Warnings = %v", cluster.Warnings)
+	}
+	if n := cluster.Nodes[0]; n.CPUPercent != 0 || n.MemPercent != 0 {
+		t.Errorf("usage should stay zero, got %+v", n)
+	}
+}
+
+func TestParseNodeMetrics(t *testing.T) {
+	raw := []byte(`{"kind":"NodeMetricsList","items":[
+		{"metadata":{"name":"node-1"},"usage":{"cpu":"250m","memory":"1Gi"}}]}`)
+	usage, err := parseNodeMetrics(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpu := usage["node-1"][corev1.ResourceCPU]
+	if cpu.MilliValue() != 250 {
+		t.Errorf("cpu = %s, want 250m", cpu.String())
+	}
+	if _, err := parseNodeMetrics([]byte("not json")); err == nil {
+		t.Error("expected decode error")
+	}
+}
+
+func TestCollect_ContextOverrides(t *testing.T) {
+	var mu sync.Mutex
+	kubeconfigs := map[string]string{}
+	mock := &mockClient{
+		nodes: []corev1.Node{makeNode("node-1", true, nil, "4", "8Gi")},
+		pods:  map[string][]corev1.Pod{"team": {}, "": {}},
+		// Namespaces are only listed for contexts without a filter.
+		namespaces: []corev1.Namespace{makeNamespace("default")},
+	}
+	factory := func(kubeconfig, ctxName string) (K8sClient, error) {
+		mu.Lock()
+		kubeconfigs[ctxName] = kubeconfig
+		mu.Unlock()
+		return mock, nil
+	}
+
+	c := newWithFactory(Config{
+		Kubeconfig: "/etc/kube/shared",
+		Contexts:   []string{"prod", "lab"},
+		Overrides: map[string]ContextConfig{
+			"lab": {Kubeconfig: "/home/me/lab.yaml", Namespaces: []string{"team"}, DashboardURL: "https://lab.example.com"},
+		},
+	}, factory)
+	result, _ := c.Collect(context.Background())
+	status := result.(*ClusterStatus)
+
+	if kubeconfigs["prod"] != "/etc/kube/shared" || kubeconfigs["lab"] != "/home/me/lab.yaml" {
+		t.Errorf("kubeconfigs = %v", kubeconfigs)
+	}
+	if ns := status.Clusters[0].Namespaces; len(ns) != 1 || ns[0].Name != "default" {
+		t.Errorf("prod namespaces = %+v", ns)
+	}
+	if ns := status.Clusters[1].Namespaces; len(ns) != 1 || ns[0].Name != "team" {
+		t.Errorf("lab namespaces = %+v", ns)
+	}
+	if status.Clusters[0].DashboardURL != "" || status.Clusters[1].DashboardURL != "https://lab.example.com" {
+		t.Errorf("dashboard URLs = %q, %q", status.Clusters[0].DashboardURL, status.Clusters[1].DashboardURL)
+	}
+}
+
+// hangingClient blocks ListNodes until its context is cancelled.
+type hangingClient struct{ mockClient }
+
+func (h *hangingClient) ListNodes(ctx context.Context) ([]corev1.Node, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestCollect_ContextTimeoutIsolatesClusters(t *testing.T) {
+	healthy := &mockClient{
+		nodes:      []corev1.Node{makeNode("node-1", true, nil, "4", "8Gi")},
+		pods:       map[string][]corev1.Pod{"": {}},
+		namespaces: []corev1.Namespace{makeNamespace("default")},
+	}
+	c := newWithFactory(Config{
+		Contexts:       []string{"down", "up"},
+		ContextTimeout: 20 * time.Millisecond,
+	}, contextFactory(map[string]K8sClient{"down": &hangingClient{}, "up": healthy}))
+
+	start := time.Now()
+	result, _ := c.Collect(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Collect took %v; timeout not applied", elapsed)
+	}
+	status := result.(*ClusterStatus)
+	if status.Clusters[0].Context != "down" || status.Clusters[0].Connected {
+		t.Errorf("hung context = %+v", status.Clusters[0])
+	}
+	if status.Clusters[1].Context != "up" || !status.Clusters[1].Connected {
+		t.Errorf("healthy context = %+v", status.Clusters[1])
+	}
+	if !c.Healthy() {
+		t.Error("collector should be healthy while one context is reachable")
+	}
+}
+
+// ---------- client-go fakes ----------
+
+// newFakeRealClient returns a realClient backed by client-go's fake typed
+// clients, serving objs and reporting serverVersion from /version.
+func newFakeRealClient(t *testing.T, serverVersion string, objs ...runtime.Object) *realClient {
+	t.Helper()
+	tracker := k8stesting.NewObjectTracker(scheme.Scheme, scheme.Codecs.UniversalDecoder())
+	for _, o := range objs {
+		if err := tracker.Add(o); err != nil {
+			t.Fatalf("add %T: %v", o, err)
+		}
+	}
+	fake := &k8stesting.Fake{}
+	fake.AddReactor("*", "*", k8stesting.ObjectReaction(tracker))
+	return &realClient{
+		core: &fakecorev1.FakeCoreV1{Fake: fake},
+		apps: &fakeappsv1.FakeAppsV1{Fake: fake},
+		discovery: &fakediscovery.FakeDiscovery{
+			Fake:               fake,
+			FakedServerVersion: &version.Info{GitVersion: serverVersion},
+		},
+	}
+}
+
+func TestCollect_FakeClientset(t *testing.T) {
+	nodes := []corev1.Node{
+		makeNode("cp-1", true, nil, "4", "8Gi"),
+		makeNode("worker-1", true, nil, "8", "16Gi"),
+		makeNode("worker-2", false, nil, "8", "16Gi"),
+	}
+	pods := []corev1.Pod{
+		makePod("api", "default", "worker-1", corev1.PodRunning, "", ""),
+		makePod("web", "default", "worker-1", corev1.PodRunning, "", ""),
+		makePod("dns", "kube-system", "cp-1", corev1.PodRunning, "", ""),
+		makePod("job", "default", "worker-2", corev1.PodSucceeded, "", ""),
+		makePod("new", "default", "", corev1.PodPending, "", ""),
+		makePod("crash", "kube-system", "worker-2", corev1.PodFailed, "", ""),
+	}
+	objs := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	}
+	for i := range nodes {
+		objs = append(objs, &nodes[i])
+	}
+	for i := range pods {
+		objs = append(objs, &pods[i])
+	}
+	client := newFakeRealClient(t, "v1.31.2", objs...)
+
+	result, err := newWithFactory(Config{}, mockFactory(client)).Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := result.(*ClusterStatus).Clusters[0]
+	if info.Status != StatusOnline || !info.Connected {
+		t.Errorf("status = %q, connected = %v; want online", info.Status, info.Connected)
+	}
+	if info.TotalNodes != 3 || info.ReadyNodes != 2 {
+		t.Errorf("nodes = %d ready of %d, want 2 of 3", info.ReadyNodes, info.TotalNodes)
+	}
+	if info.TotalPods != 6 || info.RunningPods != 3 || info.PendingPods != 1 || info.FailedPods != 1 {
+		t.Errorf("pods total/running/pending/failed = %d/%d/%d/%d, want 6/3/1/1",
+			info.TotalPods, info.RunningPods, info.PendingPods, info.FailedPods)
+	}
+	if info.Version != "v1.31.2" {
+		t.Errorf("Version = %q, want v1.31.2", info.Version)
+	}
+	if len(info.Namespaces) != 2 {
+		t.Errorf("namespaces = %+v, want default and kube-system", info.Namespaces)
+	}
+}
+
+func TestCollect_FakeClientset_NamespaceFilter(t *testing.T) {
+	pods := []corev1.Pod{
+		makePod("api", "default", "n1", corev1.PodRunning, "", ""),
+		makePod("dns", "kube-system", "n1", corev1.PodRunning, "", ""),
+		makePod("init", "kube-system", "n1", corev1.PodPending, "", ""),
+	}
+	node := makeNode("n1", true, nil, "4", "8Gi")
+	client := newFakeRealClient(t, "v1.30.0", &node, &pods[0], &pods[1], &pods[2])
+
+	result, _ := newWithFactory(Config{Namespaces: []string{"kube-system"}}, mockFactory(client)).Collect(context.Background())
+	info := result.(*ClusterStatus).Clusters[0]
+	if info.TotalPods != 2 || info.RunningPods != 1 {
+		t.Errorf("kube-system pods = %d running of %d, want 1 of 2", info.RunningPods, info.TotalPods)
+	}
+}
+
+func TestCollect_UnreachableClusterOffline(t *testing.T) {
+	down := newFakeRealClient(t, "v1.31.2")
+	down.core.(*fakecorev1.FakeCoreV1).Fake.PrependReactor("list", "nodes",
+		func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("dial tcp 10.0.0.1:6443: connect: connection refused")
+		})
+	node := makeNode("n1", true, nil, "4", "8Gi")
+	up := newFakeRealClient(t, "v1.31.2", &node)
+
+	c := newWithFactory(Config{Contexts: []string{"down", "broken", "up"}}, func(_, ctxName string) (K8sClient, error) {
+		switch ctxName {
+		case "down":
+			return down, nil
+		case "up":
+			return up, nil
+		}
+		return nil, errors.New("context \"broken\" does not exist")
+	})
+	result, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("an unreachable cluster should not fail the collector: %v", err)
+	}
+	clusters := result.(*ClusterStatus).Clusters
+	for _, i := range []int{0, 1} {
+		if clusters[i].Status != StatusOffline || clusters[i].Connected || clusters[i].Error == "" {
+			t.Errorf("%s = %+v, want offline with an error", clusters[i].Context, clusters[i])
+		}
+	}
+	if clusters[2].Status != StatusOnline || clusters[2].ReadyNodes != 1 {
+		t.Errorf("up = %+v, want online with 1 ready node", clusters[2])
+	}
+	if !c.Healthy() {
+		t.Error("collector should stay healthy while one cluster is online")
+	}
+}
@@ -0,0 +1,52 @@
+package k8s
+
+// DefaultPodPressureThreshold is the percentage of schedulable pod capacity
+// at which a cluster is considered under pod pressure.
+const DefaultPodPressureThreshold = 90.0
+
+// PodCapacity returns the number of pods the cluster can schedule: the sum
+// of MaxPods across Ready nodes. NotReady nodes contribute nothing, so a
+// node going down raises the pressure of the rest of the cluster.
+func (c ClusterInfo) PodCapacity() int {
+	total := 0
+	for _, n := range c.Nodes {
+		if n.Ready {
+			total += n.MaxPods
+		}
+	}
+	return total
+}
+
+// PodPressure returns RunningPods as a percentage of PodCapacity, or zero
+// when capacity is unknown.
+func (c ClusterInfo) PodPressure() float64 {
+	capacity := c.PodCapacity()
+	if capacity == 0 {
+		return 0
+	}
+	return float64(c.RunningPods) / float64(capacity) * 100
+}
+
+// BusiestNode returns the node with the highest PodCount/MaxPods ratio and
+// that ratio as a percentage. ok is false when no node reports MaxPods.
+func (c ClusterInfo) BusiestNode() (node NodeInfo, pct float64, ok bool) {
+	for _, n := range c.Nodes {
+		if n.MaxPods <= 0 {
+			continue
+		}
+		p := float64(n.PodCount) / float64(n.MaxPods) * 100
+		if !ok || p > pct {
+			node, pct, ok = n, p, true
+		}
+	}
+	return node, pct, ok
+}
+
+// UnderPodPressure reports whether a connected cluster's pod pressure has
+// reached threshold. A non-positive threshold uses the default.
+func (c ClusterInfo) UnderPodPressure(threshold float64) bool {
+	if threshold <= 0 {
+		threshold = DefaultPodPressureThreshold
+	}
+	return c.Connected && c.PodCapacity() > 0 && c.PodPressure() >= threshold
+}
@@ -0,0 +1,212 @@
+package collectors
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultQuietFactor is the interval multiplier used during quiet hours when
+// none is configured.
+const DefaultQuietFactor = 4
+
+// QuietHours is a daily window, in local time, during which collectors run
+// less often: each collection waits Factor times the collector's interval,
+// and the collectors named in Pause do not run at all. A window whose end
+// is before its start runs past midnight.
+type QuietHours struct {
+	// Start and End are offsets from local midnight.
+	Start, End time.Duration
+
+	// Days restricts the window to the days it starts on; a window from
+	// 22:00 to 07:00 on Friday covers Saturday morning too. Empty means
+	// every day.
+	Days []time.Weekday
+
+	// Factor multiplies collector intervals inside the window. Values
+	// below 1 are treated as 1.
+	Factor float64
+
+	// Pause names collectors that skip every collection inside the window.
+	Pause []string
+}
+
+// ParseQuietHours builds QuietHours from the config strings: start and end
+// as "HH:MM", and days as English weekday names or their three-letter
+// abbreviations. A factor of zero selects DefaultQuietFactor.
+func ParseQuietHours(start, end string, days []string, factor float64, pause []string) (*QuietHours, error) {
+	q := &QuietHours{Factor: factor, Pause: pause}
+	var err error
+	if q.Start, err = parseClock(start); err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+	if q.End, err = parseClock(end); err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+	if q.Start == q.End {
+		return nil, fmt.Errorf("start and end are both %s", start)
+	}
+	for _, d := range days {
+		wd, err := parseWeekday(d)
+		if err != nil {
+			return nil, err
+		}
+		q.Days = append(q.Days, wd)
+	}
+	if q.Factor == 0 {
+		q.Factor = DefaultQuietFactor
+	}
+	if q.Factor < 1 {
+		return nil, fmt.Errorf("factor %g is below 1", factor)
+	}
+	return q, nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// parseWeekday parses a weekday name such as "monday" or "Mon".
+func parseWeekday(s string) (time.Weekday, error) {
+	name := strings.ToLower(s)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || name == full[:3] {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", s)
+}
+
+// Active reports whether now falls inside the window. A nil QuietHours is
+// never active.
+func (q *QuietHours) Active(now time.Time) bool {
+	if q == nil {
+		return false
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	clock := now.Sub(midnight)
+	if q.Start < q.End {
+		return clock >= q.Start && clock < q.End && q.onDay(now.Weekday())
+	}
+	if clock >= q.Start {
+		return q.onDay(now.Weekday())
+	}
+	// The early part of an overnight window belongs to the day before.
+	return clock < q.End && q.onDay(midnight.AddDate(0, 0, -1).Weekday())
+}
+
+// onDay reports whether a window starting on d is in effect.
+func (q *QuietHours) onDay(d time.Weekday) bool {
+	if len(q.Days) == 0 {
+		return true
+	}
+	for _, qd := range q.Days {
+		if qd == d {
+			return true
+		}
+	}
+	return false
+}
+
+// Due reports whether a collector that runs every interval and last ran at
+// last should run again at now. Outside the window every tick is due.
+// Inside it a paused collector never is, and the others are due once
+// Factor intervals have passed. Ticks arrive every interval, so a
+// collection counts as due up to a quarter interval early; otherwise timer
+// jitter could make it wait a whole extra tick.
+func (q *QuietHours) Due(name string, interval time.Duration, last, now time.Time) bool {
+	if !q.Active(now) {
+		return true
+	}
+	if q.paused(name) {
+		return false
+	}
+	factor := q.Factor
+	if factor < 1 {
+		factor = 1
+	}
+	wait := time.Duration(float64(interval)*factor) - interval/4
+	return now.Sub(last) >= wait
+}
+
+// CacheTTLs returns ttls, the staleness cutoff per collector, as they apply
+// to data read at now. While the window is active, and for a cutoff's
+// length after it ends while the collectors catch up, a slowed collector's
+// cutoff is multiplied by Factor and a paused collector's data stays fresh
+// for its cutoff past the window's start. Otherwise, and for a nil
+// QuietHours, ttls is returned unchanged.
+func (q *QuietHours) CacheTTLs(ttls map[string]time.Duration, now time.Time) map[string]time.Duration {
+	if q == nil {
+		return ttls
+	}
+	start, ok := q.lastStart(now)
+	if !ok {
+		return ttls
+	}
+	end := start.Add(q.length())
+	factor := q.Factor
+	if factor < 1 {
+		factor = 1
+	}
+	out := make(map[string]time.Duration, len(ttls))
+	for name, ttl := range ttls {
+		switch {
+		case !now.Before(end.Add(ttl)):
+			out[name] = ttl
+		case q.paused(name):
+			out[name] = ttl + now.Sub(start)
+		default:
+			out[name] = time.Duration(float64(ttl) * factor)
+		}
+	}
+	return out
+}
+
+// lastStart returns the most recent start of the window at or before now,
+// or false when the window has not started in the past week.
+func (q *QuietHours) lastStart(now time.Time) (time.Time, bool) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i := 0; i <= 7; i++ {
+		day := midnight.AddDate(0, 0, -i)
+		if start := day.Add(q.Start); !start.After(now) && q.onDay(day.Weekday()) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// length returns how long the window lasts.
+func (q *QuietHours) length() time.Duration {
+	if q.End > q.Start {
+		return q.End - q.Start
+	}
+	return q.End + 24*time.Hour - q.Start
+}
+
+// paused reports whether the named collector is in Pause.
+func (q *QuietHours) paused(name string) bool {
+	for _, p := range q.Pause {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetQuietHours makes the runner stretch collector intervals during q, or
+// run every collector on its own interval again when q is nil. It may be
+// called while the runner is running; RunOnce is never held back.
+func (r *Runner) SetQuietHours(q *QuietHours) {
+	r.quiet.Store(q)
+}
+
+// QuietActive reports whether the runner's quiet hours are in effect at now.
+func (r *Runner) QuietActive(now time.Time) bool {
+	return r.quiet.Load().Active(now)
+}
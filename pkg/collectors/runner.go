@@ -2,9 +2,11 @@ package collectors
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -36,6 +38,25 @@ type Runner struct {
 	stopped     chan struct{}
 	once        sync.Once
 	errTrackers map[string]*errTracker
+
+	// hung holds, per collector, the done channel of a Collect call the
+	// watchdog abandoned and that has not yet returned.
+	hungMu sync.Mutex
+	hung   map[string]chan struct{}
+
+	// disabled holds the collectors switched off with SetEnabled.
+	disabledMu sync.RWMutex
+	disabled   map[string]bool
+
+	// quiet stretches collector intervals during quiet hours; nil when
+	// none are configured.
+	quiet atomic.Pointer[QuietHours]
+
+	// breaker skips collectors that keep failing; nil when none is set.
+	breaker atomic.Pointer[CircuitBreaker]
+
+	// nowFunc returns the current time for circuit breaker decisions.
+	nowFunc func() time.Time
 }
 
 // NewRunner creates a runner that sends collection results to the provided
@@ -47,6 +68,9 @@ func NewRunner(registry *Registry, updates chan<- Update) *Runner {
 		updates:     updates,
 		stopped:     make(chan struct{}),
 		errTrackers: make(map[string]*errTracker),
+		hung:        make(map[string]chan struct{}),
+		disabled:    make(map[string]bool),
+		nowFunc:     time.Now,
 	}
 }
 
@@ -99,35 +123,26 @@ func (r *Runner) Stop() {
 	case <-r.stopped:
 		// All goroutines finished.
 	case <-time.After(DefaultStopTimeout):
-		log.Printf("collectors: runner stop timed out after %s", DefaultStopTimeout)
+		slog.Warn("collectors: runner stop timed out", "timeout", DefaultStopTimeout)
 	}
 }
 
 // RunOnce manually triggers a single collection cycle for the named collector.
-// It blocks until the collection completes or the context is cancelled.
+// It blocks until the collection completes, the watchdog deadline passes, or
+// the context is cancelled. A disabled collector is not run; RunOnce returns
+// an error wrapping ErrCollectorDisabled.
 func (r *Runner) RunOnce(ctx context.Context, name string) (interface{}, error) {
 	c, ok := r.registry.Get(name)
 	if !ok {
 		return nil, fmt.Errorf("collector %q not found", name)
 	}
+	if !r.Enabled(name) {
+		return nil, fmt.Errorf("collector %q: %w", name, ErrCollectorDisabled)
+	}
 
 	start := time.Now()
-	data, err := c.Collect(ctx)
-	latency := time.Since(start)
-
-	r.registry.updateStatus(name, func(s *CollectorStatus) {
-		s.LastRun = start
-		s.RunCount++
-		s.LastLatency = latency
-		if err != nil {
-			s.ErrorCount++
-			s.LastError = err
-			s.Healthy = false
-		} else {
-			s.LastError = nil
-			s.Healthy = true
-		}
-	})
+	data, err := r.collectWithWatchdog(ctx, c)
+	r.recordRun(name, start, time.Since(start), err)
 
 	return data, err
 }
@@ -155,6 +170,7 @@ func (r *Runner) runCollector(ctx context.Context, c Collector) {
 	}
 
 	// Run immediately on start, then tick.
+	last := time.Now()
 	r.collectAndSend(ctx, c)
 
 	ticker := time.NewTicker(interval)
@@ -164,38 +180,41 @@ func (r *Runner) runCollector(ctx context.Context, c Collector) {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case now := <-ticker.C:
+			if q := r.quiet.Load(); !q.Due(c.Name(), interval, last, now) {
+				slog.Debug("collectors: quiet hours, skipping", "collector", c.Name())
+				continue
+			}
+			last = now
 			r.collectAndSend(ctx, c)
 		}
 	}
 }
 
-// collectAndSend performs one collection cycle and sends the result. It
+// collectAndSend performs one collection cycle and sends the result, or
+// does nothing while the collector is disabled or its circuit is open. It
 // catches panics to prevent one misbehaving collector from crashing the
 // runner.
 func (r *Runner) collectAndSend(ctx context.Context, c Collector) {
 	name := c.Name()
+	if !r.Enabled(name) {
+		slog.Debug("collectors: skipping disabled collector", "collector", name)
+		return
+	}
+	if r.circuitOpen(name, r.nowFunc()) {
+		slog.Debug("collectors: circuit open, skipping", "collector", name)
+		return
+	}
 	start := time.Now()
 
-	data, err := c.Collect(ctx)
+	data, err := r.collectWithWatchdog(ctx, c)
 	latency := time.Since(start)
-
-	r.registry.updateStatus(name, func(s *CollectorStatus) {
-		s.LastRun = start
-		s.RunCount++
-		s.LastLatency = latency
-		if err != nil {
-			s.ErrorCount++
-			s.LastError = err
-			s.Healthy = false
-		} else {
-			s.LastError = nil
-			s.Healthy = true
-		}
-	})
+	r.recordRun(name, start, latency, err)
 
 	if err != nil {
 		r.logCollectorError(name, err)
+	} else {
+		slog.Debug("collectors: collected", "collector", name, "latency", latency)
 	}
 
 	update := Update{
@@ -210,7 +229,52 @@ func (r *Runner) collectAndSend(ctx context.Context, c Collector) {
 	select {
 	case r.updates <- update:
 	default:
-		log.Printf("collectors: update channel full, dropping update from %s", name)
+		slog.Warn("collectors: update channel full, dropping update", "collector", name)
+	}
+}
+
+// recordRun updates the collector's status after one collection. Any error
+// marks the collector unhealthy, except watchdog timeouts, which only do so
+// once UnhealthyAfterTimeouts of them occur in a row. Every error counts
+// toward opening the collector's circuit.
+func (r *Runner) recordRun(name string, start time.Time, latency time.Duration, err error) {
+	var timeouts int
+	var failures int
+	var retryAt time.Time
+	r.registry.updateStatus(name, func(s *CollectorStatus) {
+		if r.recordCircuit(s, err != nil, r.nowFunc()) {
+			failures, retryAt = s.ConsecutiveFailures, s.CircuitRetryAt
+		}
+		s.LastRun = start
+		s.RunCount++
+		s.LastLatency = latency
+		switch {
+		case errors.Is(err, ErrCollectTimeout):
+			s.ErrorCount++
+			s.LastError = err
+			s.ConsecutiveTimeouts++
+			timeouts = s.ConsecutiveTimeouts
+			if timeouts >= UnhealthyAfterTimeouts {
+				s.Healthy = false
+			}
+		case err != nil:
+			s.ErrorCount++
+			s.LastError = err
+			s.ConsecutiveTimeouts = 0
+			s.Healthy = false
+		default:
+			s.LastError = nil
+			s.ConsecutiveTimeouts = 0
+			s.Healthy = true
+		}
+	})
+	if timeouts == UnhealthyAfterTimeouts {
+		slog.Warn("collectors: collector unhealthy after repeated timeouts",
+			"collector", name, "timeouts", timeouts)
+	}
+	if !retryAt.IsZero() {
+		slog.Warn("collectors: circuit open, skipping collector until retry",
+			"collector", name, "failures", failures, "retry_at", retryAt)
 	}
 }
 
@@ -229,14 +293,14 @@ func (r *Runner) logCollectorError(name string, err error) {
 	if msg == tracker.lastMsg && now.Sub(tracker.lastTime) < time.Hour {
 		tracker.suppressed++
 		if tracker.suppressed%100 == 0 {
-			log.Printf("collectors: %s error (repeated %d times): %v", name, tracker.suppressed, err)
+			slog.Error("collectors: collector error", "collector", name, "repeated", tracker.suppressed, "err", err)
 		}
 		return
 	}
 	if tracker.suppressed > 0 {
-		log.Printf("collectors: %s previous error repeated %d times", name, tracker.suppressed)
+		slog.Warn("collectors: previous error repeated", "collector", name, "repeated", tracker.suppressed)
 	}
-	log.Printf("collectors: %s error: %v", name, err)
+	slog.Error("collectors: collector error", "collector", name, "err", err)
 	tracker.lastMsg = msg
 	tracker.lastTime = now
 	tracker.suppressed = 0
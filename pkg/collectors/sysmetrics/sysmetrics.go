@@ -28,6 +28,10 @@ type Config struct {
 	// MonitoredMounts restricts disk collection to these mount paths.
 	// An empty slice means "collect all non-virtual partitions".
 	MonitoredMounts []string
+
+	// Thresholds grade CPU, memory, and disk usage. Unset values use
+	// DefaultThresholds. They are copied into every Metrics report.
+	Thresholds Thresholds
 }
 
 // DefaultConfig returns a Config with sensible defaults.
@@ -88,6 +92,11 @@ type Metrics struct {
 	Load      LoadMetrics   `json:"load"`
 	Uptime    time.Duration `json:"uptime"`
 	Timestamp time.Time     `json:"timestamp"`
+
+	// Thresholds are the collector's utilization thresholds, carried with
+	// the report so consumers grade it the same way (see
+	// UtilizationLevel).
+	Thresholds Thresholds `json:"thresholds"`
 }
 
 // --- Collector implementation ---
@@ -109,6 +118,7 @@ func New(cfg Config) *Collector {
 	if cfg.SlowInterval <= 0 {
 		cfg.SlowInterval = DefaultConfig().SlowInterval
 	}
+	cfg.Thresholds = cfg.Thresholds.OrDefault()
 	return &Collector{
 		cfg:     cfg,
 		healthy: true, // healthy until proven otherwise
@@ -150,7 +160,8 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 	}
 
 	m := Metrics{
-		Timestamp: time.Now(),
+		Timestamp:  time.Now(),
+		Thresholds: c.cfg.Thresholds,
 	}
 
 	var errs []string
@@ -326,3 +326,33 @@ func TestHealthyConcurrency(t *testing.T) {
 	}
 	<-done
 }
+
+func TestUtilizationLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Metrics
+		want Level
+	}{
+		{"idle", Metrics{CPU: CPUMetrics{Total: 20}, Memory: MemoryMetrics{UsedPercent: 40}}, LevelOK},
+		{"cpu 82, thresholds 80/95", Metrics{CPU: CPUMetrics{Total: 82},
+			Thresholds: Thresholds{CPU: Threshold{Warn: 80, Crit: 95}}}, LevelWarn},
+		{"cpu 88, disk 96", Metrics{CPU: CPUMetrics{Total: 88},
+			Disks: []DiskMetrics{{Path: "/", UsedPercent: 50}, {Path: "/data", UsedPercent: 96}}}, LevelCrit},
+		{"memory 92 is critical by default", Metrics{Memory: MemoryMetrics{UsedPercent: 92}}, LevelCrit},
+		{"disk 90 is a warning by default", Metrics{Disks: []DiskMetrics{{Path: "/", UsedPercent: 90}}}, LevelWarn},
+	}
+	for _, tt := range tests {
+		if got := tt.m.UtilizationLevel(); got != tt.want {
+			t.Errorf("%s: UtilizationLevel() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewFillsThresholdDefaults(t *testing.T) {
+	c := New(Config{Thresholds: Thresholds{Disk: Threshold{Warn: 70}}})
+	want := DefaultThresholds()
+	want.Disk.Warn = 70
+	if c.cfg.Thresholds != want {
+		t.Errorf("thresholds = %+v, want %+v", c.cfg.Thresholds, want)
+	}
+}
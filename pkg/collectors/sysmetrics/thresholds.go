@@ -0,0 +1,91 @@
+package sysmetrics
+
+// Level classifies a utilization percentage against its thresholds.
+type Level int
+
+const (
+	// LevelOK means usage is below the warning threshold.
+	LevelOK Level = iota
+
+	// LevelWarn means usage reached the warning threshold.
+	LevelWarn
+
+	// LevelCrit means usage reached the critical threshold.
+	LevelCrit
+)
+
+// String returns the lowercase name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelWarn:
+		return "warn"
+	case LevelCrit:
+		return "crit"
+	default:
+		return "ok"
+	}
+}
+
+// Threshold is the pair of usage percentages at which a metric warns and
+// turns critical.
+type Threshold struct {
+	Warn float64 `json:"warn"`
+	Crit float64 `json:"crit"`
+}
+
+// Level classifies pct against t.
+func (t Threshold) Level(pct float64) Level {
+	switch {
+	case pct >= t.Crit:
+		return LevelCrit
+	case pct >= t.Warn:
+		return LevelWarn
+	default:
+		return LevelOK
+	}
+}
+
+// Thresholds holds the per-metric utilization thresholds. A full disk is
+// more urgent than a busy CPU, so each metric has its own.
+type Thresholds struct {
+	CPU    Threshold `json:"cpu"`
+	Memory Threshold `json:"memory"`
+	Disk   Threshold `json:"disk"`
+}
+
+// DefaultThresholds returns the built-in thresholds: CPU 85/95, memory
+// 80/92, and disk 85/95 percent.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		CPU:    Threshold{Warn: 85, Crit: 95},
+		Memory: Threshold{Warn: 80, Crit: 92},
+		Disk:   Threshold{Warn: 85, Crit: 95},
+	}
+}
+
+// OrDefault returns t with every unset (non-positive) value replaced by
+// its DefaultThresholds counterpart.
+func (t Thresholds) OrDefault() Thresholds {
+	d := DefaultThresholds()
+	for _, p := range []struct{ v, def *Threshold }{{&t.CPU, &d.CPU}, {&t.Memory, &d.Memory}, {&t.Disk, &d.Disk}} {
+		if p.v.Warn <= 0 {
+			p.v.Warn = p.def.Warn
+		}
+		if p.v.Crit <= 0 {
+			p.v.Crit = p.def.Crit
+		}
+	}
+	return t
+}
+
+// UtilizationLevel returns the worst level of the CPU, memory, and disk
+// usage in m against m.Thresholds, with unset thresholds at their
+// defaults.
+func (m Metrics) UtilizationLevel() Level {
+	t := m.Thresholds.OrDefault()
+	worst := max(t.CPU.Level(m.CPU.Total), t.Memory.Level(m.Memory.UsedPercent))
+	for _, d := range m.Disks {
+		worst = max(worst, t.Disk.Level(d.UsedPercent))
+	}
+	return worst
+}
@@ -0,0 +1,60 @@
+package tailscale
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AddressDisplay selects which of a node's addresses is shown for display.
+type AddressDisplay string
+
+// Supported address display modes. AddressIPv4 is the default.
+const (
+	// AddressIPv4 shows the node's Tailscale IPv4 address.
+	AddressIPv4 AddressDisplay = "ipv4"
+
+	// AddressIPv6 shows the node's Tailscale IPv6 address.
+	AddressIPv6 AddressDisplay = "ipv6"
+
+	// AddressDNSName shows the node's MagicDNS name.
+	AddressDNSName AddressDisplay = "dnsname"
+)
+
+// AddressDisplays lists every accepted AddressDisplay.
+var AddressDisplays = []AddressDisplay{AddressIPv4, AddressIPv6, AddressDNSName}
+
+// ParseAddressDisplay validates s. An empty string yields AddressIPv4.
+func ParseAddressDisplay(s string) (AddressDisplay, error) {
+	if s == "" {
+		return AddressIPv4, nil
+	}
+	m := AddressDisplay(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range AddressDisplays {
+		if m == known {
+			return m, nil
+		}
+	}
+	names := make([]string, len(AddressDisplays))
+	for i, known := range AddressDisplays {
+		names[i] = string(known)
+	}
+	return "", fmt.Errorf("unknown tailscale address display %q (supported: %s)", s, strings.Join(names, ", "))
+}
+
+// Address returns the node address selected by mode. The MagicDNS name is
+// shown without its trailing dot. When the selected address is missing, as
+// for a v4-only node in AddressIPv6 mode, it falls back to the IPv4
+// address; unknown modes behave like AddressIPv4.
+func (p PeerInfo) Address(mode AddressDisplay) string {
+	switch mode {
+	case AddressIPv6:
+		if p.IPv6 != "" {
+			return p.IPv6
+		}
+	case AddressDNSName:
+		if name := strings.TrimSuffix(p.DNSName, "."); name != "" {
+			return name
+		}
+	}
+	return p.IP
+}
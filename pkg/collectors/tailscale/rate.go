@@ -0,0 +1,39 @@
+package tailscale
+
+import "time"
+
+// trafficSample is a peer's cumulative traffic counters at one collection.
+type trafficSample struct {
+	rx, tx int64
+	at     time.Time
+}
+
+// applyRates fills in each peer's RxBytesPerSec and TxBytesPerSec from the
+// change in its traffic counters since the previous collection, then
+// remembers the current counters. Peers seen for the first time, or whose
+// counters went backwards (tailscaled restarted), get no rate.
+func (c *Collector) applyRates(s *Status) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next := make(map[string]trafficSample, len(s.Peers))
+	for i := range s.Peers {
+		p := &s.Peers[i]
+		key := p.ID
+		if key == "" {
+			key = p.Hostname
+		}
+		cur := trafficSample{rx: p.RxBytes, tx: p.TxBytes, at: s.Timestamp}
+		next[key] = cur
+
+		prev, ok := c.traffic[key]
+		secs := cur.at.Sub(prev.at).Seconds()
+		if !ok || secs <= 0 || cur.rx < prev.rx || cur.tx < prev.tx {
+			continue
+		}
+		rx := float64(cur.rx-prev.rx) / secs
+		tx := float64(cur.tx-prev.tx) / secs
+		p.RxBytesPerSec, p.TxBytesPerSec = &rx, &tx
+	}
+	c.traffic = next
+}
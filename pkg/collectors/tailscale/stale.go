@@ -0,0 +1,30 @@
+package tailscale
+
+import "time"
+
+// DefaultStaleThreshold is how long an online node may go without checking
+// in before it is considered stale.
+const DefaultStaleThreshold = 10 * time.Minute
+
+// IsStale reports whether the node claims to be online but was last seen
+// more than threshold ago, which usually means a wedged tailscaled. Offline
+// nodes and nodes with no LastSeen are never stale. A non-positive
+// threshold uses DefaultStaleThreshold.
+func (p PeerInfo) IsStale(threshold time.Duration) bool {
+	if threshold <= 0 {
+		threshold = DefaultStaleThreshold
+	}
+	return p.Online && !p.LastSeen.IsZero() && time.Since(p.LastSeen) > threshold
+}
+
+// StalePeers returns the peers that are stale under the status's
+// StaleThreshold, in peer order.
+func (s *Status) StalePeers() []PeerInfo {
+	var stale []PeerInfo
+	for _, p := range s.Peers {
+		if p.IsStale(s.StaleThreshold) {
+			stale = append(stale, p)
+		}
+	}
+	return stale
+}
@@ -7,11 +7,14 @@ package tailscale
 import (
 	"context"
 	"fmt"
+	"net/netip"
+	"sort"
 	"sync"
 	"time"
 
 	"tailscale.com/ipn/ipnstate"
 	"tailscale.com/types/key"
+	"tailscale.com/types/views"
 )
 
 // Default configuration values.
@@ -34,6 +37,10 @@ type Config struct {
 	// SocketPath is an optional custom tailscaled socket path.
 	// When empty, the platform default is used.
 	SocketPath string
+
+	// StaleThreshold is how long an online peer may go unseen before it is
+	// flagged stale. Zero uses DefaultStaleThreshold.
+	StaleThreshold time.Duration
 }
 
 // PeerInfo contains summarised information about a single Tailscale peer.
@@ -45,12 +52,41 @@ type PeerInfo struct {
 	TailscaleIPs   []string      `json:"tailscale_ips"`
 	Online         bool          `json:"online"`
 	LastSeen       time.Time     `json:"last_seen"`
-	ExitNode       bool          `json:"exit_node"`
-	ExitNodeOption bool          `json:"exit_node_option"`
+	ExitNode       bool          `json:"exit_node,omitempty"`
+	ExitNodeOption bool          `json:"exit_node_option,omitempty"`
 	Tags           []string      `json:"tags"`
 	RxBytes        int64         `json:"rx_bytes"`
 	TxBytes        int64         `json:"tx_bytes"`
 	Latency        time.Duration `json:"latency"`
+
+	// RxBytesPerSec and TxBytesPerSec are the rates of RxBytes and TxBytes
+	// since the previous collection; nil until a peer has two samples.
+	RxBytesPerSec *float64 `json:"rx_bytes_per_sec,omitempty"`
+	TxBytesPerSec *float64 `json:"tx_bytes_per_sec,omitempty"`
+
+	// AdvertisedRoutes lists the subnet routes (CIDR notation) this node
+	// serves, excluding its own Tailscale addresses and exit-node default
+	// routes, which are reported via ExitNodeOption instead.
+	AdvertisedRoutes []string `json:"advertised_routes,omitempty"`
+
+	// IP is the node's Tailscale IPv4 address and IPv6 its IPv6 address,
+	// each the first of its family in TailscaleIPs. Either is empty when
+	// the node has no address of that family.
+	IP   string `json:"ip,omitempty"`
+	IPv6 string `json:"ipv6,omitempty"`
+}
+
+// adminMachinesURL is the admin console machine list; a machine's page is
+// addressed by its Tailscale IP.
+const adminMachinesURL = "https://login.tailscale.com/admin/machines"
+
+// DashboardURL returns the node's page in the Tailscale admin console, or
+// "" when the node has no Tailscale IP.
+func (p PeerInfo) DashboardURL() string {
+	if len(p.TailscaleIPs) == 0 {
+		return ""
+	}
+	return adminMachinesURL + "/" + p.TailscaleIPs[0]
 }
 
 // Status is the data returned by a single Collect call.
@@ -63,15 +99,21 @@ type Status struct {
 	TotalPeers     int        `json:"total_peers"`
 	ExitNode       *PeerInfo  `json:"exit_node,omitempty"`
 	Timestamp      time.Time  `json:"timestamp"`
+
+	// StaleThreshold is the collector's configured stale threshold, carried
+	// with the report so readers of the cache flag the same peers.
+	StaleThreshold time.Duration `json:"stale_threshold,omitempty"`
 }
 
 // Collector gathers Tailscale network status from the local daemon.
 type Collector struct {
 	client   StatusClient
 	interval time.Duration
+	stale    time.Duration
 
 	mu      sync.Mutex
 	healthy bool
+	traffic map[string]trafficSample
 }
 
 // New creates a new Tailscale collector. If cfg.Interval is zero,
@@ -83,9 +125,14 @@ func New(cfg Config, client StatusClient) *Collector {
 	if interval <= 0 {
 		interval = DefaultInterval
 	}
+	stale := cfg.StaleThreshold
+	if stale <= 0 {
+		stale = DefaultStaleThreshold
+	}
 	return &Collector{
 		client:   client,
 		interval: interval,
+		stale:    stale,
 		healthy:  true, // healthy until first failure
 	}
 }
@@ -128,6 +175,7 @@ func (c *Collector) Collect(ctx context.Context) (interface{}, error) {
 	}
 
 	status := c.mapStatus(st)
+	c.applyRates(status)
 	c.setHealthy(true)
 	return status, nil
 }
@@ -178,6 +226,7 @@ func (c *Collector) mapStatus(st *ipnstate.Status) *Status {
 		TotalPeers:     len(peers),
 		ExitNode:       exitNode,
 		Timestamp:      now,
+		StaleThreshold: c.stale,
 	}
 }
 
@@ -209,9 +258,17 @@ func (c *Collector) mapPeerStatus(ps *ipnstate.PeerStatus) PeerInfo {
 		pi.TailscaleIPs = make([]string, len(ps.TailscaleIPs))
 		for i, addr := range ps.TailscaleIPs {
 			pi.TailscaleIPs[i] = addr.String()
+			switch {
+			case addr.Is4() && pi.IP == "":
+				pi.IP = addr.String()
+			case addr.Is6() && pi.IPv6 == "":
+				pi.IPv6 = addr.String()
+			}
 		}
 	}
 
+	pi.AdvertisedRoutes = subnetRoutes(ps)
+
 	// Extract tags from the views.Slice if present.
 	if ps.Tags != nil && !ps.Tags.IsNil() {
 		tags := make([]string, ps.Tags.Len())
@@ -224,6 +281,40 @@ func (c *Collector) mapPeerStatus(ps *ipnstate.PeerStatus) PeerInfo {
 	return pi
 }
 
+// subnetRoutes returns the sorted, de-duplicated subnet routes a peer serves,
+// drawn from its primary routes and allowed IPs. Single-address prefixes for
+// the peer's own Tailscale IPs and the 0.0.0.0/0 and ::/0 exit routes are
+// skipped.
+func subnetRoutes(ps *ipnstate.PeerStatus) []string {
+	own := make(map[netip.Addr]bool, len(ps.TailscaleIPs))
+	for _, a := range ps.TailscaleIPs {
+		own[a] = true
+	}
+
+	seen := make(map[string]bool)
+	var routes []string
+	add := func(v *views.Slice[netip.Prefix]) {
+		if v == nil {
+			return
+		}
+		for i := range v.Len() {
+			p := v.At(i)
+			if p.Bits() == 0 || (p.IsSingleIP() && own[p.Addr()]) {
+				continue
+			}
+			if r := p.String(); !seen[r] {
+				seen[r] = true
+				routes = append(routes, r)
+			}
+		}
+	}
+	add(ps.PrimaryRoutes)
+	add(ps.AllowedIPs)
+
+	sort.Strings(routes)
+	return routes
+}
+
 // NewLocalClient creates a StatusClient backed by the real Tailscale local
 // daemon. This is a convenience for production wiring; tests should inject a
 // mock StatusClient instead.
@@ -2,8 +2,11 @@ package tailscale
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/netip"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -343,6 +346,47 @@ func TestCollect_PeerIPsAndDNS(t *testing.T) {
 	if honeyPeer.TailscaleIPs[0] != "100.64.0.2" {
 		t.Errorf("honey.TailscaleIPs[0] = %q, want %q", honeyPeer.TailscaleIPs[0], "100.64.0.2")
 	}
+	if honeyPeer.IP != "100.64.0.2" || honeyPeer.IPv6 != "" {
+		t.Errorf("honey IP/IPv6 = %q/%q, want 100.64.0.2 and no IPv6", honeyPeer.IP, honeyPeer.IPv6)
+	}
+	if status.Self.IP != "100.64.0.1" || status.Self.IPv6 != "fd7a:115c:a1e0::1" {
+		t.Errorf("Self IP/IPv6 = %q/%q, want 100.64.0.1 and fd7a:115c:a1e0::1", status.Self.IP, status.Self.IPv6)
+	}
+}
+
+func TestPeerInfo_Address(t *testing.T) {
+	dual := PeerInfo{DNSName: "honey.tinyland.ts.net.", IP: "100.64.0.2", IPv6: "fd7a:115c:a1e0::2"}
+	v4only := PeerInfo{IP: "100.64.0.3"}
+	tests := []struct {
+		name string
+		p    PeerInfo
+		mode AddressDisplay
+		want string
+	}{
+		{"ipv4", dual, AddressIPv4, "100.64.0.2"},
+		{"ipv6", dual, AddressIPv6, "fd7a:115c:a1e0::2"},
+		{"dnsname trims dot", dual, AddressDNSName, "honey.tinyland.ts.net"},
+		{"ipv6 falls back", v4only, AddressIPv6, "100.64.0.3"},
+		{"dnsname falls back", v4only, AddressDNSName, "100.64.0.3"},
+		{"unknown mode", dual, "bogus", "100.64.0.2"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Address(tt.mode); got != tt.want {
+			t.Errorf("%s: Address(%q) = %q, want %q", tt.name, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestParseAddressDisplay(t *testing.T) {
+	for in, want := range map[string]AddressDisplay{"": AddressIPv4, "IPv6": AddressIPv6, " dnsname ": AddressDNSName} {
+		got, err := ParseAddressDisplay(in)
+		if err != nil || got != want {
+			t.Errorf("ParseAddressDisplay(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseAddressDisplay("fqdn"); err == nil || !strings.Contains(err.Error(), "ipv4, ipv6, dnsname") {
+		t.Errorf("ParseAddressDisplay(fqdn) error = %v, want list of supported modes", err)
+	}
 }
 
 func TestCollect_ErrorSetsUnhealthy(t *testing.T) {
@@ -550,6 +594,50 @@ func TestCollect_TrafficCounters(t *testing.T) {
 	t.Fatal("peer 'honey' not found")
 }
 
+func TestCollect_TrafficRates(t *testing.T) {
+	st := buildTestStatus()
+	c := New(Config{}, &mockClient{status: st})
+
+	first, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error: %v", err)
+	}
+	for _, p := range first.(*Status).Peers {
+		if p.RxBytesPerSec != nil || p.TxBytesPerSec != nil {
+			t.Errorf("%s: first collection should have no rates", p.Hostname)
+		}
+	}
+
+	// Rewind the remembered samples a second so the rate is deterministic.
+	for k, s := range c.traffic {
+		s.at = s.at.Add(-time.Second)
+		c.traffic[k] = s
+	}
+	for _, ps := range st.Peer {
+		if ps.HostName == "honey" {
+			ps.RxBytes += 2048
+			ps.TxBytes += 512
+		}
+	}
+	second, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error: %v", err)
+	}
+	for _, p := range second.(*Status).Peers {
+		if p.Hostname != "honey" {
+			continue
+		}
+		if p.RxBytesPerSec == nil || *p.RxBytesPerSec < 1900 || *p.RxBytesPerSec > 2100 {
+			t.Errorf("honey.RxBytesPerSec = %v, want ~2048", p.RxBytesPerSec)
+		}
+		if p.TxBytesPerSec == nil || *p.TxBytesPerSec < 450 || *p.TxBytesPerSec > 550 {
+			t.Errorf("honey.TxBytesPerSec = %v, want ~512", p.TxBytesPerSec)
+		}
+		return
+	}
+	t.Fatal("peer 'honey' not found")
+}
+
 func TestCollect_ExitNodeOption(t *testing.T) {
 	st := buildTestStatus()
 	mc := &mockClient{status: st}
@@ -582,6 +670,85 @@ func TestCollect_ExitNodeOption(t *testing.T) {
 	}
 }
 
+func TestCollect_AdvertisedRoutes(t *testing.T) {
+	st := buildTestStatus()
+	routes := views.SliceOf([]netip.Prefix{
+		netip.MustParsePrefix("100.64.0.2/32"),
+		netip.MustParsePrefix("192.168.1.0/24"),
+		netip.MustParsePrefix("0.0.0.0/0"),
+		netip.MustParsePrefix("::/0"),
+		netip.MustParsePrefix("10.0.0.0/16"),
+	})
+	primary := views.SliceOf([]netip.Prefix{netip.MustParsePrefix("192.168.1.0/24")})
+	for _, ps := range st.Peer {
+		if ps.HostName == "honey" {
+			ps.AllowedIPs = &routes
+			ps.PrimaryRoutes = &primary
+		}
+	}
+
+	c := New(Config{}, &mockClient{status: st})
+	result, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error: %v", err)
+	}
+
+	for _, p := range result.(*Status).Peers {
+		switch p.Hostname {
+		case "honey":
+			want := []string{"10.0.0.0/16", "192.168.1.0/24"}
+			if len(p.AdvertisedRoutes) != len(want) {
+				t.Fatalf("honey.AdvertisedRoutes = %v, want %v", p.AdvertisedRoutes, want)
+			}
+			for i := range want {
+				if p.AdvertisedRoutes[i] != want[i] {
+					t.Errorf("honey.AdvertisedRoutes[%d] = %q, want %q", i, p.AdvertisedRoutes[i], want[i])
+				}
+			}
+		default:
+			if p.AdvertisedRoutes != nil {
+				t.Errorf("%s.AdvertisedRoutes = %v, want nil", p.Hostname, p.AdvertisedRoutes)
+			}
+		}
+	}
+}
+
+func TestStatus_JSONRoundTrip(t *testing.T) {
+	rx, tx := 1536.0, 0.0
+	orig := Status{
+		Self: PeerInfo{
+			Hostname: "self", DNSName: "self.tinyland.ts.net.", ExitNodeOption: true,
+			IP: "100.64.0.1", IPv6: "fd7a:115c:a1e0::1",
+		},
+		Peers: []PeerInfo{
+			{Hostname: "router", Online: true, AdvertisedRoutes: []string{"10.0.0.0/24"}, RxBytesPerSec: &rx, TxBytesPerSec: &tx},
+			{Hostname: "exit", Online: true, ExitNode: true, ExitNodeOption: true},
+		},
+		TotalPeers: 2,
+	}
+	orig.ExitNode = &orig.Peers[1]
+
+	data, err := json.Marshal(orig)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	var got Status
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, orig) {
+		t.Errorf("round trip mismatch:\n got  %+v\n want %+v", got, orig)
+	}
+
+	// Zero-valued exit/route fields are omitted so older caches stay compatible.
+	plain, _ := json.Marshal(PeerInfo{Hostname: "plain"})
+	for _, field := range []string{"exit_node", "exit_node_option", "advertised_routes", `"ip"`, "ipv6", "bytes_per_sec"} {
+		if strings.Contains(string(plain), field) {
+			t.Errorf("zero PeerInfo JSON should omit %q: %s", field, plain)
+		}
+	}
+}
+
 func TestCollect_Timestamp(t *testing.T) {
 	st := buildTestStatus()
 	mc := &mockClient{status: st}
@@ -646,6 +813,54 @@ var _ collectorIface = (*Collector)(nil)
 var _ StatusClient = (*mockClient)(nil)
 
 // Ensure makePeerKey produces valid non-zero keys that differ.
+func TestPeerInfo_IsStale(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		peer PeerInfo
+		want bool
+	}{
+		{"online but stale", PeerInfo{Online: true, LastSeen: now.Add(-25 * time.Minute)}, true},
+		{"recently seen", PeerInfo{Online: true, LastSeen: now.Add(-2 * time.Minute)}, false},
+		{"offline", PeerInfo{LastSeen: now.Add(-25 * time.Minute)}, false},
+		{"never seen", PeerInfo{Online: true}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.peer.IsStale(10 * time.Minute); got != tt.want {
+			t.Errorf("%s: IsStale() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	old := PeerInfo{Online: true, LastSeen: now.Add(-25 * time.Minute)}
+	if !old.IsStale(0) {
+		t.Error("zero threshold should use DefaultStaleThreshold")
+	}
+	if old.IsStale(time.Hour) {
+		t.Error("25m-old node should not be stale under a 1h threshold")
+	}
+}
+
+func TestCollect_StaleThreshold(t *testing.T) {
+	c := New(Config{StaleThreshold: 5 * time.Minute}, &mockClient{status: &ipnstate.Status{}})
+	data, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := data.(*Status).StaleThreshold; got != 5*time.Minute {
+		t.Errorf("StaleThreshold = %v, want 5m", got)
+	}
+}
+
+func TestPeerInfo_DashboardURL(t *testing.T) {
+	p := PeerInfo{TailscaleIPs: []string{"100.64.0.2", "fd7a:115c:a1e0::2"}}
+	if got, want := p.DashboardURL(), "https://login.tailscale.com/admin/machines/100.64.0.2"; got != want {
+		t.Errorf("DashboardURL() = %q, want %q", got, want)
+	}
+	if got := (PeerInfo{}).DashboardURL(); got != "" {
+		t.Errorf("DashboardURL() without IPs = %q, want empty", got)
+	}
+}
+
 func TestMakePeerKey_Unique(t *testing.T) {
 	k1 := makePeerKey(1)
 	k2 := makePeerKey(2)
@@ -0,0 +1,93 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// UnhealthyAfterTimeouts is the number of consecutive watchdog timeouts after
+// which a collector is marked unhealthy. Fewer are counted as errors but
+// leave the collector healthy, so one slow network call does not flag it.
+const UnhealthyAfterTimeouts = 3
+
+// watchdogGrace is how long a collector has to return once its context
+// expires before the watchdog abandons the call.
+const watchdogGrace = 100 * time.Millisecond
+
+// ErrCollectTimeout is wrapped by the error recorded when a Collect call
+// runs past its watchdog deadline.
+var ErrCollectTimeout = errors.New("collection timed out")
+
+// watchdogDeadline returns the hard deadline for one Collect call: twice the
+// collector's interval.
+func watchdogDeadline(c Collector) time.Duration {
+	interval := c.Interval()
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return 2 * interval
+}
+
+// collectWithWatchdog runs c.Collect under a context that expires at the
+// watchdog deadline. Collectors that honor the context return on their own;
+// one that ignores it is abandoned shortly after the deadline and its
+// eventual result discarded. While an abandoned call is still running, later cycles fail
+// straight away instead of piling up goroutines behind it.
+func (r *Runner) collectWithWatchdog(ctx context.Context, c Collector) (interface{}, error) {
+	name := c.Name()
+	deadline := watchdogDeadline(c)
+
+	r.hungMu.Lock()
+	if hung, ok := r.hung[name]; ok {
+		select {
+		case <-hung:
+			delete(r.hung, name)
+		default:
+			r.hungMu.Unlock()
+			return nil, fmt.Errorf("%s: %w: previous collection still running", name, ErrCollectTimeout)
+		}
+	}
+	r.hungMu.Unlock()
+
+	ctx, cancel := context.WithTimeoutCause(ctx, deadline, ErrCollectTimeout)
+	type result struct {
+		data interface{}
+		err  error
+	}
+	done := make(chan struct{})
+	res := make(chan result, 1)
+	go func() {
+		defer close(done)
+		data, err := c.Collect(ctx)
+		res <- result{data, err}
+	}()
+
+	var rr result
+	select {
+	case rr = <-res:
+	case <-ctx.Done():
+		if !errors.Is(context.Cause(ctx), ErrCollectTimeout) {
+			// The caller gave up or the runner is stopping; this is not
+			// the collector's fault.
+			cancel()
+			return nil, ctx.Err()
+		}
+		select {
+		case rr = <-res:
+		case <-time.After(watchdogGrace):
+			cancel()
+			r.hungMu.Lock()
+			r.hung[name] = done
+			r.hungMu.Unlock()
+			return nil, fmt.Errorf("%s: %w after %s", name, ErrCollectTimeout, deadline)
+		}
+	}
+	timedOut := errors.Is(context.Cause(ctx), ErrCollectTimeout)
+	cancel()
+	if rr.err != nil && timedOut {
+		return nil, fmt.Errorf("%s: %w after %s: %v", name, ErrCollectTimeout, deadline, rr.err)
+	}
+	return rr.data, rr.err
+}
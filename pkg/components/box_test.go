@@ -205,6 +205,51 @@ func TestTruncateWithTailShortString(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Text utility tests: Cut and Hyperlink
+// ---------------------------------------------------------------------------
+
+func TestCutMiddle(t *testing.T) {
+	if r := Cut("hello world", 2, 7); r != "llo w" {
+		t.Errorf("Cut(hello world, 2, 7) = %q, want %q", r, "llo w")
+	}
+}
+
+func TestCutEmptyRange(t *testing.T) {
+	if r := Cut("hello", 3, 3); r != "" {
+		t.Errorf("Cut(hello, 3, 3) = %q, want empty", r)
+	}
+}
+
+func TestCutWideChars(t *testing.T) {
+	r := Cut("╭─ab─╮", 1, 4)
+	if r != "─ab" {
+		t.Errorf("Cut(box chars, 1, 4) = %q, want %q", r, "─ab")
+	}
+}
+
+func TestHyperlinkEmptyURL(t *testing.T) {
+	if r := Hyperlink("host", ""); r != "host" {
+		t.Errorf("Hyperlink(host, \"\") = %q, want plain text", r)
+	}
+}
+
+func TestHyperlinkVisibleWidthUnchanged(t *testing.T) {
+	r := Hyperlink("host", "https://example.com/machines/100.64.0.1")
+	if !strings.Contains(r, "\x1b]8;;https://example.com/machines/100.64.0.1") {
+		t.Errorf("Hyperlink missing OSC 8 opener: %q", r)
+	}
+	if VisibleLen(r) != 4 {
+		t.Errorf("VisibleLen(Hyperlink(host)) = %d, want 4", VisibleLen(r))
+	}
+	if p := PadRight(r, 8); VisibleLen(p) != 8 {
+		t.Errorf("PadRight(link, 8) visible len = %d, want 8", VisibleLen(p))
+	}
+	if tr := Truncate(r+" extra", 4); VisibleLen(tr) != 4 || !strings.HasSuffix(tr, "\x1b]8;;\x07") {
+		t.Errorf("Truncate should keep the link closed, got %q", tr)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Text utility tests: Pad
 // ---------------------------------------------------------------------------
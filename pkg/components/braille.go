@@ -0,0 +1,126 @@
+package components
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// GraphStyle selects how history graphs are drawn.
+type GraphStyle string
+
+// Supported graph styles.
+const (
+	// GraphBlock draws one value per cell with block elements, 8 levels
+	// high.
+	GraphBlock GraphStyle = "block"
+
+	// GraphBraille draws a line through Braille dots: two values per cell
+	// and four dot rows per line.
+	GraphBraille GraphStyle = "braille"
+)
+
+// GraphStyles lists the supported graph styles in documentation order.
+var GraphStyles = []GraphStyle{GraphBlock, GraphBraille}
+
+// ParseGraphStyle parses a graph style name. An empty string selects
+// GraphBlock.
+func ParseGraphStyle(s string) (GraphStyle, error) {
+	if s == "" {
+		return GraphBlock, nil
+	}
+	for _, g := range GraphStyles {
+		if GraphStyle(strings.ToLower(s)) == g {
+			return g, nil
+		}
+	}
+	names := make([]string, len(GraphStyles))
+	for i, g := range GraphStyles {
+		names[i] = string(g)
+	}
+	return "", fmt.Errorf("unknown graph style %q (supported: %s)", s, strings.Join(names, ", "))
+}
+
+// brailleBlank is the empty Braille cell. Blank cells use it rather than
+// a space so every line of a graph is made of the same glyph family.
+const brailleBlank = '⠀'
+
+// Braille renders values as a line graph of exactly height lines, each
+// width cells wide. Every cell holds two samples side by side and each
+// line four dot rows, so the graph has 2*width points across and
+// 4*height levels up. The most recent 2*width values are drawn right
+// aligned and scaled between their minimum and maximum; consecutive
+// points are joined vertically so steep changes read as a line. NaN and
+// infinite values leave a gap, and a series with no finite values renders
+// blank. A flat series is drawn as a horizontal line at mid-height. It
+// returns nil when width or height is not positive.
+func Braille(values []float64, width, height int) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	cols, rows := width*2, height*4
+	if len(values) > cols {
+		values = values[len(values)-cols:]
+	}
+	offset := cols - len(values)
+
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		minY = math.Min(minY, v)
+		maxY = math.Max(maxY, v)
+	}
+
+	// Dot row of each point, counted from the top; -1 marks a gap.
+	ys := make([]int, len(values))
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v) || math.IsInf(v, 0):
+			ys[i] = -1
+		case maxY <= minY:
+			ys[i] = (rows - 1) / 2
+		default:
+			ys[i] = rows - 1 - int(math.Round((v-minY)/(maxY-minY)*float64(rows-1)))
+		}
+	}
+
+	grid := make([][]uint8, height)
+	for i := range grid {
+		grid[i] = make([]uint8, width)
+	}
+	set := func(x, y int) {
+		grid[y/4][x/2] |= brailleBit(x%2, y%4)
+	}
+	for i, y := range ys {
+		if y < 0 {
+			continue
+		}
+		x := offset + i
+		from, to := y, y
+		if i > 0 && ys[i-1] >= 0 {
+			// Fill the rows between the previous point and this one,
+			// leaving the previous point's own row to its column.
+			switch prev := ys[i-1]; {
+			case prev < y:
+				from = prev + 1
+			case prev > y:
+				to = prev - 1
+			}
+		}
+		for dy := from; dy <= to; dy++ {
+			set(x, dy)
+		}
+	}
+
+	lines := make([]string, height)
+	for i, row := range grid {
+		var b strings.Builder
+		for _, bits := range row {
+			b.WriteRune(brailleBlank + rune(bits))
+		}
+		lines[i] = b.String()
+	}
+	return lines
+}
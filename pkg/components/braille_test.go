@@ -0,0 +1,92 @@
+package components
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestBrailleDimensions(t *testing.T) {
+	series := []float64{1, 5, 2, 8, 3, 9, 4, 7, 6, 0, 2, 4, 6, 8, 10, 3, 1}
+	for _, tt := range []struct {
+		name          string
+		values        []float64
+		width, height int
+	}{
+		{"short series", series[:3], 10, 1},
+		{"exact fit", series[:12], 6, 3},
+		{"longer than graph", series, 4, 2},
+		{"empty", nil, 8, 4},
+		{"all NaN", []float64{math.NaN(), math.NaN()}, 5, 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := Braille(tt.values, tt.width, tt.height)
+			if len(lines) != tt.height {
+				t.Fatalf("got %d lines, want %d", len(lines), tt.height)
+			}
+			for i, l := range lines {
+				if n := utf8.RuneCountInString(l); n != tt.width {
+					t.Errorf("line %d has %d cells, want %d: %q", i, n, tt.width, l)
+				}
+			}
+		})
+	}
+	if got := Braille(series, 0, 2); got != nil {
+		t.Errorf("Braille(width 0) = %q, want nil", got)
+	}
+	if got := Braille(series, 4, 0); got != nil {
+		t.Errorf("Braille(height 0) = %q, want nil", got)
+	}
+}
+
+func TestBrailleFlatSeries(t *testing.T) {
+	// Eight points fill four cells; at mid-height of a two-line graph the
+	// line sits on the bottom dot row of the first line.
+	lines := Braille([]float64{3, 3, 3, 3, 3, 3, 3, 3}, 4, 2)
+	want := []string{"⣀⣀⣀⣀", "⠀⠀⠀⠀"}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("flat series = %q, want %q", lines, want)
+	}
+}
+
+func TestBrailleBlankAndGaps(t *testing.T) {
+	for _, l := range Braille(nil, 3, 2) {
+		if l != "⠀⠀⠀" {
+			t.Errorf("empty series line = %q, want blank cells", l)
+		}
+	}
+
+	// A NaN in the middle leaves its column empty instead of joining the
+	// points on either side.
+	lines := Braille([]float64{0, math.NaN(), 1, 0}, 2, 1)
+	if want := "⡀⢱"; lines[0] != want {
+		t.Errorf("series with gap = %q, want %q", lines[0], want)
+	}
+}
+
+func TestBrailleScale(t *testing.T) {
+	// A rising series spans the whole allocated height: the minimum is on
+	// the bottom dot row and the maximum on the top one.
+	lines := Braille([]float64{0, 1, 2, 3, 4, 5, 6, 7}, 4, 2)
+	want := []string{"⠀⠀⡠⠊", "⡠⠊⠀⠀"}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("rising series = %q, want %q", lines, want)
+	}
+}
+
+func TestParseGraphStyle(t *testing.T) {
+	for in, want := range map[string]GraphStyle{
+		"":        GraphBlock,
+		"block":   GraphBlock,
+		"Braille": GraphBraille,
+	} {
+		got, err := ParseGraphStyle(in)
+		if err != nil || got != want {
+			t.Errorf("ParseGraphStyle(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseGraphStyle("ascii"); err == nil {
+		t.Error("ParseGraphStyle(ascii) succeeded, want error")
+	}
+}
@@ -0,0 +1,34 @@
+package components
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// FormatCount renders a count compactly with a decimal suffix, e.g. "999",
+// "1.0k", "350k" or "1.2M". Counts below ten units keep one decimal. A
+// count that rounds up to 1000 of a unit is promoted to the next one, so
+// 999_999 is "1.0M" rather than "1000k". Counts below 1000 are exact.
+func FormatCount(n int64) string {
+	if n < 1000 {
+		return strconv.FormatInt(n, 10)
+	}
+	units := []string{"k", "M", "B", "T"}
+	v := float64(n)
+	for i, unit := range units {
+		v /= 1000
+		r := math.RoundToEven(v)
+		if v < 10 {
+			r = math.RoundToEven(v*10) / 10
+		}
+		if r >= 1000 && i < len(units)-1 {
+			continue
+		}
+		if r < 10 {
+			return fmt.Sprintf("%.1f%s", r, unit)
+		}
+		return fmt.Sprintf("%.0f%s", r, unit)
+	}
+	return "" // unreachable: the last unit always returns
+}
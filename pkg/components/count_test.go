@@ -0,0 +1,28 @@
+package components
+
+import "testing"
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1.0k"},
+		{1050, "1.0k"},
+		{9_960, "10k"},
+		{350_000, "350k"},
+		{999_499, "999k"},
+		{999_500, "1.0M"},
+		{999_999, "1.0M"},
+		{1_250_000, "1.2M"},
+		{2_300_000_000, "2.3B"},
+		{5_000_000_000_000_000, "5000T"},
+	}
+	for _, tt := range tests {
+		if got := FormatCount(tt.in); got != tt.want {
+			t.Errorf("FormatCount(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package components
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// GlyphSet selects the characters used for status and alert markers.
+type GlyphSet string
+
+// Supported glyph sets.
+const (
+	// GlyphEmoji uses emoji and Unicode symbols. It is the zero value's
+	// set.
+	GlyphEmoji GlyphSet = "emoji"
+
+	// GlyphNerdFont uses Font Awesome icons from a patched Nerd Font.
+	GlyphNerdFont GlyphSet = "nerdfont"
+
+	// GlyphASCII uses 7-bit ASCII only, for terminals and fonts without
+	// the symbols.
+	GlyphASCII GlyphSet = "ascii"
+)
+
+// GlyphSets lists the supported glyph sets in documentation order.
+var GlyphSets = []GlyphSet{GlyphEmoji, GlyphNerdFont, GlyphASCII}
+
+// Glyphs holds the marker for each status.
+type Glyphs struct {
+	OK      string
+	Warn    string
+	Crit    string
+	Snoozed string // replaces Warn while alerts are snoozed
+}
+
+// glyphTable maps each set to its markers.
+var glyphTable = map[GlyphSet]Glyphs{
+	GlyphEmoji:    {OK: "✓", Warn: "⚠️", Crit: "🔴", Snoozed: "💤"},
+	GlyphNerdFont: {OK: "\uf00c", Warn: "\uf071", Crit: "\uf057", Snoozed: "\uf1f6"},
+	GlyphASCII:    {OK: "+", Warn: "!", Crit: "X", Snoozed: "z"},
+}
+
+// Glyphs returns the markers of the set. An unknown or empty set uses
+// GlyphEmoji.
+func (s GlyphSet) Glyphs() Glyphs {
+	if g, ok := glyphTable[s]; ok {
+		return g
+	}
+	return glyphTable[GlyphEmoji]
+}
+
+// ParseGlyphSet parses a glyph set name. An empty string or "auto" selects
+// DetectGlyphSet.
+func ParseGlyphSet(s string) (GlyphSet, error) {
+	if s == "" || strings.EqualFold(s, "auto") {
+		return DetectGlyphSet(), nil
+	}
+	for _, g := range GlyphSets {
+		if GlyphSet(strings.ToLower(s)) == g {
+			return g, nil
+		}
+	}
+	names := []string{"auto"}
+	for _, g := range GlyphSets {
+		names = append(names, string(g))
+	}
+	return "", fmt.Errorf("unknown glyph set %q (supported: %s)", s, strings.Join(names, ", "))
+}
+
+// DetectGlyphSet picks a glyph set from the environment: GlyphASCII on the
+// Linux console, dumb and VT terminals, or a locale that is not UTF-8;
+// GlyphNerdFont when NERD_FONT is set to anything but "0" or "false"; and
+// GlyphEmoji otherwise.
+func DetectGlyphSet() GlyphSet {
+	switch term := os.Getenv("TERM"); {
+	case term == "linux", term == "dumb", strings.HasPrefix(term, "vt"):
+		return GlyphASCII
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			v = strings.ToLower(v)
+			if !strings.Contains(v, "utf-8") && !strings.Contains(v, "utf8") {
+				return GlyphASCII
+			}
+			break
+		}
+	}
+	if v := strings.ToLower(os.Getenv("NERD_FONT")); v != "" && v != "0" && v != "false" {
+		return GlyphNerdFont
+	}
+	return GlyphEmoji
+}
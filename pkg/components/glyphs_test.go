@@ -0,0 +1,76 @@
+package components
+
+import "testing"
+
+func TestGlyphSets(t *testing.T) {
+	for set, want := range map[GlyphSet]Glyphs{
+		GlyphEmoji:    {OK: "✓", Warn: "⚠️", Crit: "🔴", Snoozed: "💤"},
+		GlyphNerdFont: {OK: "\uf00c", Warn: "\uf071", Crit: "\uf057", Snoozed: "\uf1f6"},
+		GlyphASCII:    {OK: "+", Warn: "!", Crit: "X", Snoozed: "z"},
+		"":            {OK: "✓", Warn: "⚠️", Crit: "🔴", Snoozed: "💤"},
+	} {
+		if got := set.Glyphs(); got != want {
+			t.Errorf("%q.Glyphs() = %+v, want %+v", set, got, want)
+		}
+	}
+}
+
+func TestGlyphASCIIIsSevenBit(t *testing.T) {
+	g := GlyphASCII.Glyphs()
+	for _, s := range []string{g.OK, g.Warn, g.Crit, g.Snoozed} {
+		if s == "" {
+			t.Error("ascii glyph is empty")
+		}
+		for i := 0; i < len(s); i++ {
+			if s[i] >= 0x80 {
+				t.Errorf("ascii glyph %q has byte %#x", s, s[i])
+			}
+		}
+	}
+}
+
+func TestParseGlyphSet(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_CTYPE", "")
+	t.Setenv("LANG", "en_US.UTF-8")
+	t.Setenv("NERD_FONT", "")
+	for in, want := range map[string]GlyphSet{
+		"":         GlyphEmoji,
+		"auto":     GlyphEmoji,
+		"NerdFont": GlyphNerdFont,
+		"ascii":    GlyphASCII,
+	} {
+		got, err := ParseGlyphSet(in)
+		if err != nil || got != want {
+			t.Errorf("ParseGlyphSet(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseGlyphSet("unicode"); err == nil {
+		t.Error("ParseGlyphSet(unicode) succeeded, want error")
+	}
+}
+
+func TestDetectGlyphSet(t *testing.T) {
+	for _, tt := range []struct {
+		term, lang, nerd string
+		want             GlyphSet
+	}{
+		{"xterm-256color", "en_US.UTF-8", "", GlyphEmoji},
+		{"xterm-256color", "en_US.UTF-8", "1", GlyphNerdFont},
+		{"xterm-256color", "en_US.UTF-8", "false", GlyphEmoji},
+		{"linux", "en_US.UTF-8", "1", GlyphASCII},
+		{"vt100", "", "", GlyphASCII},
+		{"xterm", "C", "", GlyphASCII},
+		{"xterm", "", "", GlyphEmoji},
+	} {
+		t.Setenv("TERM", tt.term)
+		t.Setenv("LC_ALL", "")
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", tt.lang)
+		t.Setenv("NERD_FONT", tt.nerd)
+		if got := DetectGlyphSet(); got != tt.want {
+			t.Errorf("TERM=%q LANG=%q NERD_FONT=%q: got %q, want %q", tt.term, tt.lang, tt.nerd, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package components
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ThousandsSeparator selects how FormatMoney groups the digits of amounts
+// of a thousand or more.
+type ThousandsSeparator string
+
+// Supported thousands separators.
+const (
+	SeparatorNone  ThousandsSeparator = "none"
+	SeparatorComma ThousandsSeparator = "comma"
+	SeparatorSpace ThousandsSeparator = "space"
+)
+
+// ThousandsSeparators lists the supported separators in documentation
+// order.
+var ThousandsSeparators = []ThousandsSeparator{SeparatorNone, SeparatorComma, SeparatorSpace}
+
+// ParseThousandsSeparator parses a separator name. An empty string selects
+// SeparatorNone.
+func ParseThousandsSeparator(s string) (ThousandsSeparator, error) {
+	if s == "" {
+		return SeparatorNone, nil
+	}
+	for _, sep := range ThousandsSeparators {
+		if ThousandsSeparator(strings.ToLower(s)) == sep {
+			return sep, nil
+		}
+	}
+	names := make([]string, len(ThousandsSeparators))
+	for i, sep := range ThousandsSeparators {
+		names[i] = string(sep)
+	}
+	return "", fmt.Errorf("unknown thousands separator %q (supported: %s)", s, strings.Join(names, ", "))
+}
+
+// MoneyFormat controls how FormatMoney renders a USD amount. The zero value
+// renders cents with no digit grouping, e.g. "$1234.50". The separators are
+// chosen by configuration rather than the system locale so output is the
+// same on every host.
+type MoneyFormat struct {
+	// Whole rounds to whole dollars, e.g. "$1235", for compact displays.
+	Whole bool
+
+	// Separator groups the integer digits in threes, e.g. "$1,234.50"
+	// with SeparatorComma or "$1 234.50" with SeparatorSpace.
+	Separator ThousandsSeparator
+}
+
+// FormatMoney formats v as a dollar amount according to f. Negative
+// amounts are prefixed with "-", as in "-$12.00".
+func FormatMoney(v float64, f MoneyFormat) string {
+	prec := 2
+	if f.Whole {
+		prec = 0
+	}
+	s := strconv.FormatFloat(math.Abs(v), 'f', prec, 64)
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i:]
+	}
+
+	var sep string
+	switch f.Separator {
+	case SeparatorComma:
+		sep = ","
+	case SeparatorSpace:
+		sep = " "
+	}
+	if sep != "" && len(intPart) > 3 {
+		var b strings.Builder
+		lead := len(intPart) % 3
+		if lead > 0 {
+			b.WriteString(intPart[:lead])
+		}
+		for i := lead; i < len(intPart); i += 3 {
+			if b.Len() > 0 {
+				b.WriteString(sep)
+			}
+			b.WriteString(intPart[i : i+3])
+		}
+		intPart = b.String()
+	}
+
+	// Amounts that round to zero are never shown as "-$0.00".
+	sign := ""
+	if v < 0 && strings.Trim(s, "0.") != "" {
+		sign = "-"
+	}
+	return sign + "$" + intPart + frac
+}
@@ -0,0 +1,53 @@
+package components
+
+import "testing"
+
+func TestFormatMoney(t *testing.T) {
+	comma := MoneyFormat{Separator: SeparatorComma}
+	space := MoneyFormat{Separator: SeparatorSpace}
+	whole := MoneyFormat{Whole: true, Separator: SeparatorComma}
+	tests := []struct {
+		v    float64
+		f    MoneyFormat
+		want string
+	}{
+		{1234.5, MoneyFormat{}, "$1234.50"},
+		{0, MoneyFormat{}, "$0.00"},
+		{999.99, comma, "$999.99"},
+		{999.995, comma, "$1,000.00"},
+		{1000, comma, "$1,000.00"},
+		{1234.5, comma, "$1,234.50"},
+		{999999.99, comma, "$999,999.99"},
+		{1000000, comma, "$1,000,000.00"},
+		{12345678.9, comma, "$12,345,678.90"},
+		{1234.5, space, "$1 234.50"},
+		{1000000, space, "$1 000 000.00"},
+		{1234.6, whole, "$1,235"},
+		{999.7, whole, "$1,000"},
+		{42.4, MoneyFormat{Whole: true}, "$42"},
+		{-1234.5, comma, "-$1,234.50"},
+		{-0.001, comma, "$0.00"},
+	}
+	for _, tt := range tests {
+		if got := FormatMoney(tt.v, tt.f); got != tt.want {
+			t.Errorf("FormatMoney(%v, %+v) = %q, want %q", tt.v, tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestParseThousandsSeparator(t *testing.T) {
+	for in, want := range map[string]ThousandsSeparator{
+		"":      SeparatorNone,
+		"none":  SeparatorNone,
+		"comma": SeparatorComma,
+		"Space": SeparatorSpace,
+	} {
+		got, err := ParseThousandsSeparator(in)
+		if err != nil || got != want {
+			t.Errorf("ParseThousandsSeparator(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseThousandsSeparator("period"); err == nil {
+		t.Error("ParseThousandsSeparator(\"period\") should fail")
+	}
+}
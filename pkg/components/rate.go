@@ -0,0 +1,19 @@
+package components
+
+import "fmt"
+
+// FormatRate renders a byte rate human-readably in binary units, e.g.
+// "512 B/s", "1.5 KB/s" or "12.3 MB/s".
+func FormatRate(bytesPerSec float64) string {
+	if bytesPerSec < 1024 {
+		return fmt.Sprintf("%.0f B/s", max(bytesPerSec, 0))
+	}
+	units := []string{"KB/s", "MB/s", "GB/s", "TB/s"}
+	v := bytesPerSec / 1024
+	i := 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", v, units[i])
+}
@@ -0,0 +1,22 @@
+package components
+
+import "testing"
+
+func TestFormatRate(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{0, "0 B/s"},
+		{-5, "0 B/s"},
+		{512, "512 B/s"},
+		{1536, "1.5 KB/s"},
+		{12.3 * 1024 * 1024, "12.3 MB/s"},
+		{2 * 1024 * 1024 * 1024, "2.0 GB/s"},
+	}
+	for _, tt := range tests {
+		if got := FormatRate(tt.in); got != tt.want {
+			t.Errorf("FormatRate(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResetStyle selects how the time a limit window resets is shown.
+type ResetStyle string
+
+// Supported reset styles.
+const (
+	// ResetRelative shows the time left, e.g. "in 2h 5m".
+	ResetRelative ResetStyle = "relative"
+
+	// ResetAbsolute shows the local wall-clock time, e.g. "15:45".
+	ResetAbsolute ResetStyle = "absolute"
+
+	// ResetBoth shows the time left followed by the wall-clock time, e.g.
+	// "in 2h 5m (15:45)".
+	ResetBoth ResetStyle = "both"
+)
+
+// ResetStyles lists the supported reset styles in documentation order.
+var ResetStyles = []ResetStyle{ResetRelative, ResetAbsolute, ResetBoth}
+
+// ParseResetStyle parses a reset style name. An empty string selects
+// ResetRelative.
+func ParseResetStyle(s string) (ResetStyle, error) {
+	if s == "" {
+		return ResetRelative, nil
+	}
+	for _, r := range ResetStyles {
+		if ResetStyle(strings.ToLower(s)) == r {
+			return r, nil
+		}
+	}
+	names := make([]string, len(ResetStyles))
+	for i, r := range ResetStyles {
+		names[i] = string(r)
+	}
+	return "", fmt.Errorf("unknown reset time format %q (supported: %s)", s, strings.Join(names, ", "))
+}
@@ -0,0 +1,20 @@
+package components
+
+import "testing"
+
+func TestParseResetStyle(t *testing.T) {
+	for in, want := range map[string]ResetStyle{
+		"":         ResetRelative,
+		"relative": ResetRelative,
+		"Absolute": ResetAbsolute,
+		"both":     ResetBoth,
+	} {
+		got, err := ParseResetStyle(in)
+		if err != nil || got != want {
+			t.Errorf("ParseResetStyle(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseResetStyle("wallclock"); err == nil {
+		t.Error("ParseResetStyle(wallclock) succeeded, want error")
+	}
+}
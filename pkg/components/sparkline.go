@@ -27,6 +27,12 @@ type SparklineStyle struct {
 	MinY       *float64 // optional fixed minimum Y (nil = auto-scale)
 	MaxY       *float64 // optional fixed maximum Y (nil = auto-scale)
 	Label      string   // optional prefix label
+
+	// WarnColor and CritColor color the upper brackets in RenderGraded;
+	// Color is used below the first threshold. Empty values fall back to
+	// the gauge defaults.
+	WarnColor string
+	CritColor string
 }
 
 // Sparkline renders inline sparkline charts using Unicode block elements.
@@ -47,9 +53,44 @@ func NewSparkline(style SparklineStyle) *Sparkline {
 	return &Sparkline{style: style}
 }
 
+// Downsample reduces values to target points by averaging consecutive
+// buckets of near-equal size, so a long history keeps its overall shape
+// instead of losing its oldest points. Values already at or below target
+// are returned unchanged, and a target of 1 yields their mean.
+func Downsample(values []float64, target int) []float64 {
+	if target <= 0 || len(values) <= target {
+		return values
+	}
+	out := make([]float64, target)
+	for i := range out {
+		lo := i * len(values) / target
+		hi := (i + 1) * len(values) / target
+		sum := 0.0
+		for _, v := range values[lo:hi] {
+			sum += v
+		}
+		out[i] = sum / float64(hi-lo)
+	}
+	return out
+}
+
 // Render renders a sparkline at the given width. The width parameter overrides
 // the style width for this call.
 func (s *Sparkline) Render(data []float64, width int) string {
+	return s.render(data, width, nil)
+}
+
+// RenderGraded renders like Render but colors each cell by the bracket its
+// value falls in: below thresholds[0] it uses the style Color, below
+// thresholds[1] WarnColor, and CritColor from there up. One threshold gives
+// two brackets. Without thresholds it is identical to Render. The result
+// carries one escape sequence per run of same-colored cells, so use
+// VisibleLen rather than len when measuring it.
+func (s *Sparkline) RenderGraded(data []float64, width int, thresholds ...float64) string {
+	return s.render(data, width, thresholds)
+}
+
+func (s *Sparkline) render(data []float64, width int, thresholds []float64) string {
 	if len(data) == 0 {
 		return ""
 	}
@@ -79,7 +120,12 @@ func (s *Sparkline) Render(data []float64, width int) string {
 	sparkChars := sparkMapToBlocks(points, minY, maxY)
 
 	// Color the sparkline.
-	colored := sparkColorize(sparkChars, s.style.Color)
+	var colored string
+	if len(thresholds) > 0 {
+		colored = sparkColorizeGraded(points, sparkChars, thresholds, s.gradeColors())
+	} else {
+		colored = sparkColorize(sparkChars, s.style.Color)
+	}
 
 	var b strings.Builder
 
@@ -203,6 +249,46 @@ func sparkColorize(s, hexColor string) string {
 	return fg + s + "\x1b[0m"
 }
 
+// gradeColors returns the ok, warn and crit colors for RenderGraded.
+func (s *Sparkline) gradeColors() [3]string {
+	colors := [3]string{s.style.Color, s.style.WarnColor, s.style.CritColor}
+	for i, def := range [3]string{"#4CAF50", "#FF9800", "#F44336"} {
+		if colors[i] == "" {
+			colors[i] = def
+		}
+	}
+	return colors
+}
+
+// sparkColorizeGraded colors each block in chars by the bracket of the
+// matching value, emitting a new escape only when the color changes.
+func sparkColorizeGraded(values []float64, chars string, thresholds []float64, colors [3]string) string {
+	var b strings.Builder
+	current := ""
+	i := 0
+	for _, r := range chars {
+		bracket := 0
+		for _, t := range thresholds {
+			if bracket < 2 && values[i] >= t {
+				bracket++
+			}
+		}
+		if fg := sparkColorFg(colors[bracket]); fg != current {
+			if current != "" {
+				b.WriteString("\x1b[0m")
+			}
+			b.WriteString(fg)
+			current = fg
+		}
+		b.WriteRune(r)
+		i++
+	}
+	if current != "" {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
 // sparkColorFg returns an ANSI true-color foreground escape from hex.
 func sparkColorFg(hex string) string {
 	r, g, b, ok := sparkParseHexColor(hex)
@@ -1,6 +1,7 @@
 package components
 
 import (
+	"math"
 	"strings"
 	"testing"
 )
@@ -336,3 +337,64 @@ func TestSparklineFixedMinOnly(t *testing.T) {
 		t.Errorf("expected highest block for max value with fixed min, got %q", string(runes[1]))
 	}
 }
+
+func TestSparklineGradedColorsByBracket(t *testing.T) {
+	s := NewSparkline(SparklineStyle{Color: "#00FF00", WarnColor: "#FFFF00", CritColor: "#FF0000"})
+	result := s.RenderGraded([]float64{1, 2, 6, 7, 9}, 5, 5, 8)
+
+	green, yellow, red := "38;2;0;255;0", "38;2;255;255;0", "38;2;255;0;0"
+	gi, yi, ri := strings.Index(result, green), strings.Index(result, yellow), strings.Index(result, red)
+	if gi < 0 || yi < 0 || ri < 0 || !(gi < yi && yi < ri) {
+		t.Errorf("expected green, yellow, red runs in order, got %q", result)
+	}
+	// Adjacent cells in the same bracket share one escape.
+	if n := strings.Count(result, "\x1b[38;2;"); n != 3 {
+		t.Errorf("expected 3 color escapes, got %d in %q", n, result)
+	}
+	if got := VisibleLen(result); got != 5 {
+		t.Errorf("VisibleLen = %d, want 5", got)
+	}
+	if plain := sparkTestStrip(result); plain != sparkTestStrip(s.Render([]float64{1, 2, 6, 7, 9}, 5)) {
+		t.Errorf("graded blocks %q differ from Render", plain)
+	}
+}
+
+func TestSparklineGradedDefaultsAndNoThresholds(t *testing.T) {
+	s := NewSparkline(SparklineStyle{})
+	if got := s.RenderGraded([]float64{1, 10}, 2, 5); !strings.Contains(got, "38;2;255;152;0") {
+		t.Errorf("expected default warning color, got %q", got)
+	}
+	if got, want := s.RenderGraded([]float64{1, 10}, 2), s.Render([]float64{1, 10}, 2); got != want {
+		t.Errorf("RenderGraded without thresholds = %q, want %q", got, want)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		target int
+		want   []float64
+	}{
+		{"target larger than input", []float64{1, 2, 3}, 5, []float64{1, 2, 3}},
+		{"target equal to input", []float64{1, 2, 3}, 3, []float64{1, 2, 3}},
+		{"zero target", []float64{1, 2, 3}, 0, []float64{1, 2, 3}},
+		{"target of one is the mean", []float64{1, 2, 3, 6}, 1, []float64{3}},
+		{"even buckets", []float64{1, 3, 5, 7, 9, 11}, 3, []float64{2, 6, 10}},
+		{"uneven buckets", []float64{1, 2, 3, 4, 5}, 2, []float64{1.5, 4}},
+		{"empty", nil, 4, nil},
+	}
+	for _, tt := range tests {
+		got := Downsample(tt.values, tt.target)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: Downsample() = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+				t.Errorf("%s: Downsample() = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -35,6 +35,26 @@ func TruncateWithTail(s string, maxWidth int, tail string) string {
 	return ansi.Truncate(s, maxWidth, tail)
 }
 
+// Cut returns the cells of s in [left, right), keeping any ANSI escape
+// sequences (colors, OSC 8 hyperlinks) intact so styling and links are not
+// broken when a line is sliced.
+func Cut(s string, left, right int) string {
+	if right <= left {
+		return ""
+	}
+	return ansi.Cut(s, left, right)
+}
+
+// Hyperlink wraps text in an OSC 8 hyperlink to url. The escape bytes have
+// no width, so VisibleLen(Hyperlink(s, u)) == VisibleLen(s). An empty url
+// returns text unchanged.
+func Hyperlink(text, url string) string {
+	if url == "" {
+		return text
+	}
+	return ansi.SetHyperlink(url) + text + ansi.ResetHyperlink()
+}
+
 // PadRight pads s with trailing spaces so that its visible width equals
 // width. If s is already wider than width, it is returned unchanged.
 func PadRight(s string, width int) string {
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // Config is the root configuration for prompt-pulse v2.
 type Config struct {
 	// General settings
@@ -22,6 +24,21 @@ type Config struct {
 
 	// Banner mode settings
 	Banner BannerConfig `toml:"banner"`
+
+	// Status change notifications
+	Notify NotifyConfig `toml:"notify"`
+
+	// Status level rules
+	Status StatusConfig `toml:"status"`
+
+	// Presentation of collector data
+	Display DisplayConfig `toml:"display"`
+
+	// provenance records the files the config was loaded from.
+	provenance *Provenance
+
+	// secretsResolved is set once ResolveSecretCommands has run.
+	secretsResolved bool
 }
 
 // GeneralConfig holds daemon-level general settings.
@@ -37,6 +54,67 @@ type GeneralConfig struct {
 
 	// CacheDir overrides the default cache directory.
 	CacheDir string `toml:"cache_dir"`
+
+	// HTTPAddr enables the daemon's HTTP JSON API on this address, e.g.
+	// ":9090". A missing host binds to 127.0.0.1. Empty disables the API.
+	HTTPAddr string `toml:"http_addr"`
+
+	// HTTPDashboard serves a small web dashboard at / of the HTTP API. It
+	// is only served when http_addr is a loopback address.
+	HTTPDashboard bool `toml:"http_dashboard"`
+
+	// HTTPTLSCert and HTTPTLSKey are PEM files with the certificate and
+	// private key the HTTP API serves HTTPS with. Both empty serves plain
+	// HTTP.
+	HTTPTLSCert string `toml:"http_tls_cert"`
+	HTTPTLSKey  string `toml:"http_tls_key"`
+
+	// HTTPClientCA is a PEM file of CA certificates. When set, the HTTP API
+	// requires mutual TLS: clients must present a certificate signed by one
+	// of them. It needs http_tls_cert and http_tls_key.
+	HTTPClientCA string `toml:"http_client_ca"`
+
+	// RedactSecrets masks API keys and token-like strings in -diagnose
+	// output and daemon logs (see RedactString).
+	RedactSecrets bool `toml:"redact_secrets"`
+
+	// QuietHours slows the daemon's collectors down overnight.
+	QuietHours QuietHoursConfig `toml:"quiet_hours"`
+
+	// CircuitThreshold is the number of consecutive failed collections
+	// after which the daemon stops running a collector for
+	// CircuitCooldown, serving its last cached data until it goes stale,
+	// then tries it once more (default 5; 0 never stops it).
+	CircuitThreshold int `toml:"circuit_threshold"`
+
+	// CircuitCooldown is how long a collector that kept failing is
+	// skipped (default 10m).
+	CircuitCooldown Duration `toml:"circuit_cooldown"`
+}
+
+// QuietHoursConfig is a daily window in local time during which the daemon
+// polls less often. It is off unless Start and End are set.
+type QuietHoursConfig struct {
+	// Start and End bound the window as "HH:MM". An End before Start
+	// runs past midnight.
+	Start string `toml:"start"`
+	End   string `toml:"end"`
+
+	// Days limits the window to the days it starts on, e.g.
+	// ["mon", "tue"]. Empty means every day.
+	Days []string `toml:"days"`
+
+	// Factor multiplies every collector's interval inside the window
+	// (default 4).
+	Factor float64 `toml:"factor"`
+
+	// Pause lists collectors that do not run at all inside the window.
+	Pause []string `toml:"pause"`
+}
+
+// Enabled reports whether a quiet-hours window is configured.
+func (q QuietHoursConfig) Enabled() bool {
+	return q.Start != "" || q.End != ""
 }
 
 // LayoutConfig defines the dashboard layout via presets or custom rows.
@@ -78,6 +156,84 @@ type CollectorsConfig struct {
 	Claude     ClaudeCollectorConfig     `toml:"claude"`
 	Billing    BillingCollectorConfig    `toml:"billing"`
 	Waifu      WaifuCollectorConfig      `toml:"waifu"`
+
+	// Commands are external programs run as collectors, one
+	// [[collectors.command]] table each.
+	Commands []CommandCollectorConfig `toml:"command"`
+}
+
+// CommandCollectorConfig runs an external program that prints a JSON
+// object with a "data" table on stdout. Its output is cached under Name and
+// shown on the banner as a key/value section.
+type CommandCollectorConfig struct {
+	// Name is the collector and cache key name: lowercase letters, digits,
+	// '-' and '_', distinct from the built-in collectors.
+	Name string `toml:"name"`
+
+	// Exec is the program to run, and Args its arguments. It is run
+	// directly, not through a shell.
+	Exec string   `toml:"exec"`
+	Args []string `toml:"args"`
+
+	// Interval is how often the program runs (default: 5m).
+	Interval Duration `toml:"interval"`
+
+	// Timeout bounds each run; the program is killed when it expires
+	// (default: 10s).
+	Timeout Duration `toml:"timeout"`
+
+	// CacheTTL is how long the cached output is shown before it is treated
+	// as stale. Zero uses DefaultCacheTTL(Interval).
+	CacheTTL Duration `toml:"cache_ttl"`
+}
+
+// MinCacheTTL is the shortest staleness cutoff DefaultCacheTTL returns. It
+// covers a five-minute cron -collect-once with room for a slow run, and
+// keeps sub-second collectors from going stale between two writes.
+const MinCacheTTL = 10 * time.Minute
+
+// DefaultCacheTTL is the staleness cutoff for a collector with no
+// cache_ttl: twice its interval, so one late or slow collection does not
+// mark the data stale, and at least MinCacheTTL. A zero interval yields
+// zero.
+func DefaultCacheTTL(interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return 0
+	}
+	return max(2*interval, MinCacheTTL)
+}
+
+// CacheTTLs returns the staleness cutoff for each collector's cache key:
+// cache_ttl when set, otherwise DefaultCacheTTL of the collector's
+// interval. Keys whose resolved TTL is zero are omitted so readers fall
+// back to their default.
+func (c CollectorsConfig) CacheTTLs() map[string]time.Duration {
+	ttls := make(map[string]time.Duration, 5)
+	for key, pair := range map[string][2]Duration{
+		"sysmetrics": {c.SysMetrics.CacheTTL, c.SysMetrics.Interval},
+		"tailscale":  {c.Tailscale.CacheTTL, c.Tailscale.Interval},
+		"k8s":        {c.Kubernetes.CacheTTL, c.Kubernetes.Interval},
+		"claude":     {c.Claude.CacheTTL, c.Claude.Interval},
+		"billing":    {c.Billing.CacheTTL, c.Billing.Interval},
+	} {
+		ttl := pair[0].Duration
+		if ttl <= 0 {
+			ttl = DefaultCacheTTL(pair[1].Duration)
+		}
+		if ttl > 0 {
+			ttls[key] = ttl
+		}
+	}
+	for _, cc := range c.Commands {
+		ttl := cc.CacheTTL.Duration
+		if ttl <= 0 {
+			ttl = DefaultCacheTTL(cc.Interval.Duration)
+		}
+		if ttl > 0 && cc.Name != "" {
+			ttls[cc.Name] = ttl
+		}
+	}
+	return ttls
 }
 
 // WaifuCollectorConfig controls waifu image fetching and local caching.
@@ -94,12 +250,41 @@ type WaifuCollectorConfig struct {
 type SysMetricsCollectorConfig struct {
 	Enabled  bool     `toml:"enabled"`
 	Interval Duration `toml:"interval"`
+
+	// CacheTTL is how long this collector's cached data is shown before it
+	// is treated as stale. Zero uses DefaultCacheTTL(Interval).
+	CacheTTL Duration `toml:"cache_ttl"`
+
+	// CPUWarn and CPUCrit are the CPU usage percentages at which the
+	// node's status becomes warning and critical.
+	CPUWarn float64 `toml:"cpu_warn"`
+	CPUCrit float64 `toml:"cpu_crit"`
+
+	// MemoryWarn and MemoryCrit are the same for memory usage.
+	MemoryWarn float64 `toml:"memory_warn"`
+	MemoryCrit float64 `toml:"memory_crit"`
+
+	// DiskWarn and DiskCrit are the same for the fullest disk.
+	DiskWarn float64 `toml:"disk_warn"`
+	DiskCrit float64 `toml:"disk_crit"`
 }
 
 // TailscaleCollectorConfig controls Tailscale status collection.
 type TailscaleCollectorConfig struct {
 	Enabled  bool     `toml:"enabled"`
 	Interval Duration `toml:"interval"`
+
+	// CacheTTL is how long this collector's cached data is shown before it
+	// is treated as stale. Zero uses DefaultCacheTTL(Interval).
+	CacheTTL Duration `toml:"cache_ttl"`
+
+	// AddressDisplay selects the node address the banner shows: "ipv4"
+	// (default), "ipv6", or "dnsname" for the MagicDNS name.
+	AddressDisplay string `toml:"address_display"`
+
+	// StaleAfter flags a node that reports online but has not been seen
+	// for this long, which usually means a wedged tailscaled.
+	StaleAfter Duration `toml:"stale_after"`
 }
 
 // K8sCollectorConfig controls Kubernetes status collection.
@@ -108,6 +293,36 @@ type K8sCollectorConfig struct {
 	Interval   Duration `toml:"interval"`
 	Contexts   []string `toml:"contexts"`
 	Namespaces []string `toml:"namespaces"`
+
+	// CacheTTL is how long this collector's cached data is shown before it
+	// is treated as stale. Zero uses DefaultCacheTTL(Interval).
+	CacheTTL Duration `toml:"cache_ttl"`
+
+	// PodPressureThreshold is the percentage of schedulable pod capacity
+	// that raises a pod-pressure warning (default: 90).
+	PodPressureThreshold float64 `toml:"pod_pressure_threshold"`
+
+	// Kubeconfig is the kubeconfig path. Empty uses $KUBECONFIG or
+	// ~/.kube/config.
+	Kubeconfig string `toml:"kubeconfig"`
+
+	// ContextTimeout bounds the collection of each context (default: 10s).
+	ContextTimeout Duration `toml:"context_timeout"`
+
+	// Overrides replaces kubeconfig or namespaces for individual contexts,
+	// keyed by context name.
+	Overrides map[string]K8sContextConfig `toml:"overrides"`
+}
+
+// K8sContextConfig holds per-context Kubernetes settings. Empty fields
+// inherit the collector-wide values.
+type K8sContextConfig struct {
+	Kubeconfig string   `toml:"kubeconfig"`
+	Namespaces []string `toml:"namespaces"`
+
+	// DashboardURL is the cluster's web console, linked from the cluster
+	// name in the banner when hyperlinks are enabled.
+	DashboardURL string `toml:"dashboard_url"`
 }
 
 // ClaudeCollectorConfig controls Claude usage collection.
@@ -115,6 +330,10 @@ type ClaudeCollectorConfig struct {
 	Enabled  bool     `toml:"enabled"`
 	Interval Duration `toml:"interval"`
 
+	// CacheTTL is how long this collector's cached data is shown before it
+	// is treated as stale. Zero uses DefaultCacheTTL(Interval).
+	CacheTTL Duration `toml:"cache_ttl"`
+
 	// AdminKey is the Anthropic Admin API key.
 	// Prefer setting via ANTHROPIC_ADMIN_KEY environment variable instead
 	// of storing in the config file.
@@ -122,6 +341,10 @@ type ClaudeCollectorConfig struct {
 
 	// Accounts holds per-account configurations.
 	Accounts []ClaudeAccountConfig `toml:"account"`
+
+	// HistoryPoints caps the number of usage samples kept per account for
+	// sparkline rendering (default: 48).
+	HistoryPoints int `toml:"history_points"`
 }
 
 // ClaudeAccountConfig represents a single Claude account entry.
@@ -129,6 +352,10 @@ type ClaudeAccountConfig struct {
 	// Name is the display name for this account.
 	Name string `toml:"name"`
 
+	// ShortName is the label shown where space is tight, such as the
+	// compact banner. Empty derives a unique four-character name from Name.
+	ShortName string `toml:"short_name"`
+
 	// AdminKey is the per-account admin key.
 	// Prefer setting via environment variable instead of config file.
 	AdminKey string `toml:"admin_key"`
@@ -136,6 +363,34 @@ type ClaudeAccountConfig struct {
 	// OrganizationID is the Anthropic organization identifier.
 	// If empty, auto-discovered via GET /v1/organizations.
 	OrganizationID string `toml:"organization_id"`
+
+	// BudgetUSD is the monthly spend budget for this account. Utilization
+	// warnings are only raised for accounts with a budget.
+	BudgetUSD float64 `toml:"budget_usd"`
+
+	// WarnThreshold is the budget utilization percentage that flags the
+	// account as a warning (default: 70).
+	WarnThreshold float64 `toml:"warn_threshold"`
+
+	// CritThreshold is the budget utilization percentage that flags the
+	// account as critical (default: 90).
+	CritThreshold float64 `toml:"crit_threshold"`
+
+	// Type is "account" (default) for the account's own spend, or "org"
+	// for the organization's shared five-hour and seven-day limits and
+	// seat count, as on a Team plan. The thresholds then apply to the
+	// limit utilization and budget_usd is unused.
+	Type string `toml:"type"`
+
+	// NotifyWeeklyReset posts a notification to the [notify] webhook when
+	// the account's seven-day limit resets. Only "org" accounts report
+	// that window.
+	NotifyWeeklyReset bool `toml:"notify_weekly_reset"`
+
+	// Tier is the account's plan or API usage tier code, such as "pro",
+	// "max_5x", "team" or "tier_3". It is kept in the cached data as is
+	// and, with display.show_plan_name, shown by its display name.
+	Tier string `toml:"tier"`
 }
 
 // BillingCollectorConfig controls billing data collection.
@@ -144,6 +399,27 @@ type BillingCollectorConfig struct {
 	Interval     Duration `toml:"interval"`
 	Civo         CivoConfig `toml:"civo"`
 	DigitalOcean DOConfig   `toml:"digitalocean"`
+
+	// CacheTTL is how long this collector's cached data is shown before it
+	// is treated as stale. Zero uses DefaultCacheTTL(Interval).
+	CacheTTL Duration `toml:"cache_ttl"`
+
+	// BudgetUSD is the monthly cloud budget used for the budget percentage
+	// and pace indicator. Zero disables both.
+	BudgetUSD float64 `toml:"budget_usd"`
+
+	// AnomalySigma is how many standard deviations above the recent daily
+	// mean a day's spend must be to be flagged as a spike (default: 3).
+	AnomalySigma float64 `toml:"anomaly_sigma"`
+
+	// ProjectedOverage warns when spend is under budget_usd but the
+	// month-end forecast is over it (default: true).
+	ProjectedOverage bool `toml:"projected_overage"`
+
+	// ProjectedOverageThreshold is how far, in percent of budget_usd, the
+	// forecast must exceed the budget before warning (default: 10). It
+	// keeps noisy early-month forecasts from flagging.
+	ProjectedOverageThreshold float64 `toml:"projected_overage_threshold"`
 }
 
 // CivoConfig holds Civo cloud billing settings.
@@ -157,6 +433,11 @@ type CivoConfig struct {
 	// Region is the Civo region code (e.g., "nyc1").
 	// Prefer setting via CIVO_REGION environment variable.
 	Region string `toml:"region"`
+
+	// Credits is the monthly credit, in USD, applied to Civo spend. When
+	// set, budget math and the banner use spend after credits. Nil means
+	// no credits.
+	Credits *float64 `toml:"credits"`
 }
 
 // DOConfig holds DigitalOcean billing settings.
@@ -166,6 +447,10 @@ type DOConfig struct {
 	// APIKey for DigitalOcean API access.
 	// Prefer setting via DIGITALOCEAN_TOKEN environment variable.
 	APIKey string `toml:"api_key"`
+
+	// Credits is the monthly credit, in USD, applied to DigitalOcean
+	// spend, as for CivoConfig.Credits.
+	Credits *float64 `toml:"credits"`
 }
 
 // ImageConfig holds image and waifu display settings.
@@ -201,11 +486,28 @@ type ShellConfig struct {
 	// ShowBannerOnStartup shows a banner when a new shell starts.
 	ShowBannerOnStartup bool `toml:"show_banner_on_startup"`
 
+	// ShowBannerOn limits the startup banner to one kind of session:
+	// "ssh" (SSH_CONNECTION or SSH_TTY set), "local", or "always".
+	ShowBannerOn string `toml:"show_banner_on"`
+
 	// BannerTimeout is the max time to wait for banner data.
 	BannerTimeout Duration `toml:"banner_timeout"`
 
 	// InstantBanner uses pre-rendered cache for <1ms display.
 	InstantBanner bool `toml:"instant_banner"`
+
+	// StarshipClaudeWeekly adds monthly and seven-day budget utilization
+	// to the Starship Claude segment, e.g. "45%/82%w".
+	StarshipClaudeWeekly bool `toml:"starship_claude_weekly"`
+
+	// StarshipMemoTTL is how long -starship reuses its previous output
+	// before reading the collector cache again. Zero disables it.
+	StarshipMemoTTL Duration `toml:"starship_memo_ttl"`
+
+	// StarshipLoading shows a dim "…" placeholder for -starship modules
+	// whose collector has not written any data yet, instead of hiding
+	// them.
+	StarshipLoading bool `toml:"starship_loading"`
 }
 
 // BannerConfig holds terminal width threshold overrides for banner modes.
@@ -221,4 +523,169 @@ type BannerConfig struct {
 
 	// UltraWideMinWidth is the min terminal width for ultra-wide mode.
 	UltraWideMinWidth int `toml:"ultrawide_min_width"`
+
+	// WarmSizes lists the terminal configurations the daemon pre-renders a
+	// banner for after collecting, as "WxH" or "WxH/protocol", e.g.
+	// "120x40/kitty". The protocol defaults to halfblocks. Empty disables
+	// pre-rendering.
+	WarmSizes []string `toml:"warm_sizes"`
+
+	// Sections lists the banner sections to show, in display order, by
+	// widget ID (see BannerSections) or command collector name. Sections
+	// not listed are neither read nor drawn. Empty shows every section in
+	// the default order.
+	Sections []string `toml:"sections"`
+
+	// HideSections lists banner sections never to show, applied after
+	// Sections.
+	HideSections []string `toml:"hide_sections"`
+}
+
+// NotifyConfig controls webhook notifications sent by the daemon when the
+// overall status level changes.
+type NotifyConfig struct {
+	// WebhookURL is the Slack or Discord incoming webhook. Empty disables
+	// notifications.
+	WebhookURL string `toml:"webhook_url"`
+
+	// Kind selects the payload format: "slack" or "discord". Empty detects
+	// the format from the webhook host.
+	Kind string `toml:"kind"`
+
+	// MinLevel is the lowest level that triggers a notification:
+	// "warning" (default) or "critical".
+	MinLevel string `toml:"min_level"`
+
+	// Cooldown is the minimum time between two notifications, so a flapping
+	// subsystem does not spam the channel.
+	Cooldown Duration `toml:"cooldown"`
+
+	// NotifyRecovery sends a message when the status returns to healthy
+	// after a notified problem.
+	NotifyRecovery bool `toml:"notify_recovery"`
+}
+
+// StatusConfig sets how much each subsystem weighs in the overall status
+// level behind notifications, the statusline, and -explain.
+type StatusConfig struct {
+	// Ignore lists subsystems that never affect the status level:
+	// "claude", "billing", "k8s", "tailscale", or "sysmetrics".
+	Ignore []string `toml:"ignore"`
+
+	// ClaudeWarn and ClaudeCrit replace the warn_threshold and
+	// crit_threshold of every Claude account and organization when
+	// grading the status. Zero keeps each one's own.
+	ClaudeWarn float64 `toml:"claude_warn"`
+	ClaudeCrit float64 `toml:"claude_crit"`
+
+	// BillingWarn and BillingCrit are the percentages of the cloud budget
+	// at which spend turns the status to warning and critical.
+	BillingWarn float64 `toml:"billing_warn"`
+	BillingCrit float64 `toml:"billing_crit"`
+
+	// Weights caps how severe each subsystem's findings can make the
+	// status, by subsystem name: "healthy", "warning", or "critical".
+	// Unlisted subsystems count in full.
+	Weights map[string]string `toml:"weights"`
+
+	// K8sPodPressure replaces the collector's pod_pressure_threshold when
+	// grading the status. Zero keeps it.
+	K8sPodPressure float64 `toml:"k8s_pod_pressure"`
+
+	// K8sNotReadyCrit is the number of not-ready nodes at which a cluster
+	// turns the status critical instead of warning. Zero never does.
+	K8sNotReadyCrit int `toml:"k8s_not_ready_crit"`
+
+	// TailscaleStaleAfter replaces the collector's stale_after when
+	// grading the status. Zero keeps it.
+	TailscaleStaleAfter Duration `toml:"tailscale_stale_after"`
+
+	// CPUWarn through DiskCrit replace the sysmetrics collector's
+	// thresholds when grading the status. Zero keeps each one.
+	CPUWarn    float64 `toml:"cpu_warn"`
+	CPUCrit    float64 `toml:"cpu_crit"`
+	MemoryWarn float64 `toml:"memory_warn"`
+	MemoryCrit float64 `toml:"memory_crit"`
+	DiskWarn   float64 `toml:"disk_warn"`
+	DiskCrit   float64 `toml:"disk_crit"`
+}
+
+// DisplayConfig controls how collector data is presented.
+type DisplayConfig struct {
+	// ClaudeSort orders Claude accounts in the banner: "config" (default,
+	// config order), "name", "utilization", or "status".
+	ClaudeSort string `toml:"claude_sort"`
+
+	// PrimaryAccount names a Claude account that is always listed first in
+	// the banner and shown by the Starship utilization suffix, whatever
+	// ClaudeSort says. An unknown name is ignored with a warning.
+	PrimaryAccount string `toml:"primary_account"`
+
+	// EnableHyperlinks wraps node hostnames, cluster names, and provider
+	// names in OSC 8 links to their dashboards when the terminal supports
+	// them.
+	EnableHyperlinks bool `toml:"enable_hyperlinks"`
+
+	// MoneyDecimals is the number of decimal places shown for dollar
+	// amounts in the banner and Starship segments: 2 (default) or 0.
+	MoneyDecimals int `toml:"money_decimals"`
+
+	// ThousandsSeparator groups the digits of dollar amounts: "none"
+	// (default), "comma" ($1,234.50), or "space" ($1 234.50).
+	ThousandsSeparator string `toml:"thousands_separator"`
+
+	// GraphStyle draws history graphs such as the Claude cost sparklines:
+	// "block" (default, one value per cell) or "braille" (two values per
+	// cell and four dot rows, for finer detail in the same space).
+	GraphStyle string `toml:"graph_style"`
+
+	// CompareLastMonth adds a trend line to the billing banner section:
+	// this month's cumulative spend as a sparkline, drawn over last
+	// month's curve when the spend history covers it.
+	CompareLastMonth bool `toml:"compare_last_month"`
+
+	// HideZeroProviders leaves billing providers that have spent nothing
+	// this month out of the banner's provider line; a "+N at $0" note
+	// counts them. Otherwise they show as "$0".
+	HideZeroProviders bool `toml:"hide_zero_providers"`
+
+	// SparklinePoints caps the points in the billing trend and Starship
+	// Claude sparklines; longer histories are averaged into this many
+	// buckets. Zero fits the available width. Either way the available
+	// width caps the count.
+	SparklinePoints int `toml:"sparkline_points"`
+
+	// Glyphs selects the status and alert markers: "emoji", "nerdfont"
+	// (icons from a patched Nerd Font), "ascii" (7-bit only), or "auto"
+	// (default) to pick from $TERM, the locale, and $NERD_FONT.
+	Glyphs string `toml:"glyphs"`
+
+	// CriticalFirst moves banner sections whose subsystem is at warning or
+	// critical level to the top, critical first, with the level's glyph in
+	// their title. Healthy sections keep their usual order.
+	CriticalFirst bool `toml:"critical_first"`
+
+	// ResetTimeFormat shows when a Claude budget window resets as the time
+	// left, "relative" (default, "in 2d 3h"), the local wall-clock time,
+	// "absolute" ("Nov 1 00:00"), or "both" ("in 2d 3h (Nov 1 00:00)")
+	// where the banner is wide enough.
+	ResetTimeFormat string `toml:"reset_time_format"`
+
+	// Clock24h shows wall-clock times on a 24-hour clock ("15:45", the
+	// default) instead of a 12-hour one ("3:45pm").
+	Clock24h bool `toml:"clock_24h"`
+
+	// ShowPlanName labels Claude accounts in the banner with the display
+	// name of their configured tier, e.g. "work (Max 5×)".
+	ShowPlanName bool `toml:"show_plan_name"`
+
+	// ClaudeHeatmap adds a row to the Claude banner section with one cell
+	// per account, shaded and colored by its utilization level, and a
+	// legend, so many accounts fit in a few columns.
+	ClaudeHeatmap bool `toml:"claude_heatmap"`
+
+	// DashboardURLs replaces the dashboard links of named billing
+	// providers, clusters and Tailscale nodes, in the banner's hyperlinks
+	// and the TUI's detail panes (see DashboardURLs.Resolve).
+	DashboardURLs DashboardURLs `toml:"dashboard_urls"`
 }
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// DashboardURLs maps provider, cluster and node names to dashboard URL
+// templates that replace the built-in links, for setups behind SSO portals
+// or internal proxies. A key is a name, e.g. "civo-prod", or a name
+// qualified by its kind, e.g. "k8s:civo-prod", to tell apart a billing
+// provider and a cluster that share one. Kinds are "billing", "k8s" and
+// "tailscale". A template may use {name} and {region}.
+type DashboardURLs map[string]string
+
+// DashboardKinds are the kinds a DashboardURLs key may be qualified with.
+var DashboardKinds = []string{"billing", "k8s", "tailscale"}
+
+// Resolve returns the dashboard URL of the kind entity called name: the
+// template under "kind:name", else under name, with {name} and {region}
+// filled in, path-escaped. Without a template it returns def.
+func (m DashboardURLs) Resolve(kind, name, region, def string) string {
+	tmpl, ok := m[kind+":"+name]
+	if !ok {
+		if tmpl, ok = m[name]; !ok {
+			return def
+		}
+	}
+	return strings.NewReplacer(
+		"{name}", url.PathEscape(name),
+		"{region}", url.PathEscape(region),
+	).Replace(tmpl)
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// systemFile is the machine-wide base config that Load reads before the
+// user's config file, so a team can ship shared settings that each user
+// overlays.
+var systemFile = "/etc/prompt-pulse/config.toml"
+
+// namedTables lists the arrays of tables whose entries are merged across
+// files by their name key instead of being replaced as a whole.
+var namedTables = map[string]bool{
+	"collectors.claude.account": true,
+	"collectors.command":        true,
+}
+
+// Provenance records which files a config was loaded from and which of
+// them set each value.
+type Provenance struct {
+	// Files lists the files read, lowest precedence first: every file
+	// comes after the files it includes.
+	Files []string
+
+	// Keys maps each dotted key set by a file to the last file that set
+	// it. Claude accounts and command collectors are keyed by name, e.g.
+	// "collectors.claude.account.team.budget_usd".
+	Keys map[string]string
+}
+
+// SortedKeys returns the keys of p.Keys in order.
+func (p *Provenance) SortedKeys() []string {
+	keys := make([]string, 0, len(p.Keys))
+	for k := range p.Keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Provenance returns where c's values came from, or nil for a config that
+// was not loaded from files.
+func (c *Config) Provenance() *Provenance {
+	return c.provenance
+}
+
+// loadLayers reads paths in order and deep-merges each over the ones
+// before it. A file's include list, resolved relative to the file, is
+// loaded ahead of the file itself, so the including file wins. Tables merge
+// key by key, Claude accounts and command collectors merge by name, and
+// any other value, arrays included, is replaced by the later file.
+func loadLayers(paths []string) (*Config, error) {
+	l := &layerLoader{merged: make(map[string]any), prov: &Provenance{Keys: make(map[string]string)}}
+	for _, p := range paths {
+		if err := l.load(p, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	var cfg *Config
+	var err error
+	if len(l.prov.Files) == 1 {
+		// A lone file decodes as written, so errors keep its line numbers.
+		cfg, err = LoadFromReader(bytes.NewReader(l.raw))
+	} else {
+		var buf bytes.Buffer
+		if err = toml.NewEncoder(&buf).Encode(l.merged); err != nil {
+			return nil, fmt.Errorf("config: merge %s: %w", strings.Join(l.prov.Files, ", "), err)
+		}
+		cfg, err = LoadFromReader(&buf)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", strings.Join(l.prov.Files, ", "), err)
+	}
+	cfg.provenance = l.prov
+	return cfg, nil
+}
+
+// layerLoader accumulates the merged TOML document of a layered load.
+type layerLoader struct {
+	merged map[string]any
+	prov   *Provenance
+	raw    []byte // contents of the last file read
+}
+
+// load merges path, after its includes, into l. stack holds the files
+// whose includes are being loaded, to reject cycles.
+func (l *layerLoader) load(path string, stack []string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	for _, p := range stack {
+		if p == abs {
+			return fmt.Errorf("config: include cycle: %s -> %s", strings.Join(stack, " -> "), abs)
+		}
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return err
+	}
+	var doc map[string]any
+	if _, err := toml.Decode(string(data), &doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	includes, err := includeList(doc["include"])
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	delete(doc, "include")
+	for _, inc := range includes {
+		if !filepath.IsAbs(inc) {
+			inc = filepath.Join(filepath.Dir(abs), inc)
+		}
+		if err := l.load(inc, append(stack, abs)); err != nil {
+			return err
+		}
+	}
+
+	l.merge(l.merged, doc, "", abs)
+	l.prov.Files = append(l.prov.Files, abs)
+	l.raw = data
+	return nil
+}
+
+// includeList returns the paths named by a file's include key, which may be
+// a string or an array of strings.
+func includeList(v any) ([]string, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		paths := make([]string, 0, len(v))
+		for _, p := range v {
+			s, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("include: want file paths, got %v", p)
+			}
+			paths = append(paths, s)
+		}
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("include: want a file path or a list of them, got %v", v)
+	}
+}
+
+// merge copies src into dst under the dotted key prefix, recording file as
+// the source of every value it sets.
+func (l *layerLoader) merge(dst, src map[string]any, prefix, file string) {
+	for k, v := range src {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case map[string]any:
+			sub, ok := dst[k].(map[string]any)
+			if !ok {
+				sub = make(map[string]any)
+				dst[k] = sub
+			}
+			l.merge(sub, v, key, file)
+		case []map[string]any:
+			if namedTables[key] {
+				dst[k] = l.mergeNamed(dst[k], v, key, file)
+				continue
+			}
+			dst[k] = v
+			l.prov.Keys[key] = file
+		default:
+			dst[k] = v
+			l.prov.Keys[key] = file
+		}
+	}
+}
+
+// mergeNamed merges the entries of src into the array of tables dst by
+// their name key: an entry whose name is already present merges into it,
+// and any other is appended.
+func (l *layerLoader) mergeNamed(dst any, src []map[string]any, key, file string) []map[string]any {
+	entries, _ := dst.([]map[string]any)
+	for _, e := range src {
+		name, _ := e["name"].(string)
+		var into map[string]any
+		for _, have := range entries {
+			if n, _ := have["name"].(string); name != "" && n == name {
+				into = have
+				break
+			}
+		}
+		if into == nil {
+			into = make(map[string]any)
+			entries = append(entries, into)
+		}
+		prefix := key
+		if name != "" {
+			prefix += "." + name
+		}
+		l.merge(into, e, prefix, file)
+	}
+	return entries
+}
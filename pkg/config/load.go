@@ -1,8 +1,14 @@
 package config
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -10,33 +16,67 @@ import (
 	"github.com/BurntSushi/toml"
 )
 
-// Load reads configuration from the standard config path.
-// Search order:
+// Load reads configuration from the standard config path, layered over
+// the machine-wide /etc/prompt-pulse/config.toml when that exists.
+// Search order for the user's file:
 //  1. $XDG_CONFIG_HOME/prompt-pulse/config.toml
 //  2. ~/.config/prompt-pulse/config.toml
 //
-// If no file exists, returns DefaultConfig().
+// The user's file deep-merges over the system file (see loadLayers), and
+// either may include others. If no file exists, returns DefaultConfig().
 func Load() (*Config, error) {
-	paths := configSearchPaths()
-	for _, p := range paths {
+	start := time.Now()
+	var paths []string
+	if _, err := os.Stat(systemFile); err == nil {
+		paths = append(paths, systemFile)
+	}
+	if p := FindFile(); p != "" {
+		paths = append(paths, p)
+	}
+	if len(paths) == 0 {
+		slog.Debug("config: no config file, using defaults")
+		return DefaultConfig(), nil
+	}
+	cfg, err := loadLayers(paths)
+	if err == nil {
+		slog.Debug("config: loaded", "files", cfg.provenance.Files, "elapsed", time.Since(start))
+	}
+	return cfg, err
+}
+
+// FindFile returns the config file Load would read, or "" when none of the
+// search paths exists.
+func FindFile() string {
+	for _, p := range configSearchPaths() {
 		if _, err := os.Stat(p); err == nil {
-			return LoadFromFile(p)
+			return p
 		}
 	}
-	return DefaultConfig(), nil
+	return ""
 }
 
-// LoadFromFile reads configuration from a specific file path.
+// LoadFromFile reads configuration from a specific file path, together
+// with the files it includes. The system file is not read: an explicit
+// path names the whole config. A missing file returns DefaultConfig().
 func LoadFromFile(path string) (*Config, error) {
-	f, err := os.Open(path)
-	if err != nil {
+	if _, err := os.Stat(path); err != nil {
 		if os.IsNotExist(err) {
 			return DefaultConfig(), nil
 		}
 		return nil, err
 	}
-	defer f.Close()
-	return LoadFromReader(f)
+	return loadLayers([]string{path})
+}
+
+// LoadFrom loads the config the way the -config flag asks for: from path
+// when it is set, and otherwise through Load's layered search. Everything
+// that re-reads the config after startup uses it so it sees the same
+// files startup did.
+func LoadFrom(path string) (*Config, error) {
+	if path != "" {
+		return LoadFromFile(path)
+	}
+	return Load()
 }
 
 // LoadFromReader reads configuration from an io.Reader.
@@ -60,30 +100,48 @@ func DefaultConfig() *Config {
 			DataRetention:      Duration{10 * time.Minute},
 			LogLevel:           "info",
 			CacheDir:           cacheDir,
+			RedactSecrets:      true,
+			CircuitThreshold:   5,
+			CircuitCooldown:    Duration{10 * time.Minute},
 		},
 		Layout: LayoutConfig{
 			Preset: "dashboard",
 		},
 		Collectors: CollectorsConfig{
 			SysMetrics: SysMetricsCollectorConfig{
-				Enabled:  true,
-				Interval: Duration{1 * time.Second},
+				Enabled:    true,
+				Interval:   Duration{1 * time.Second},
+				CPUWarn:    85,
+				CPUCrit:    95,
+				MemoryWarn: 80,
+				MemoryCrit: 92,
+				DiskWarn:   85,
+				DiskCrit:   95,
 			},
 			Tailscale: TailscaleCollectorConfig{
-				Enabled:  true,
-				Interval: Duration{30 * time.Second},
+				Enabled:        true,
+				Interval:       Duration{30 * time.Second},
+				AddressDisplay: "ipv4",
+				StaleAfter:     Duration{10 * time.Minute},
 			},
 			Kubernetes: K8sCollectorConfig{
-				Enabled:  false,
-				Interval: Duration{60 * time.Second},
+				Enabled:              false,
+				Interval:             Duration{60 * time.Second},
+				PodPressureThreshold: 90,
+				ContextTimeout:       Duration{10 * time.Second},
 			},
 			Claude: ClaudeCollectorConfig{
-				Enabled:  true,
-				Interval: Duration{5 * time.Minute},
+				Enabled:       true,
+				Interval:      Duration{5 * time.Minute},
+				HistoryPoints: 48,
 			},
 			Billing: BillingCollectorConfig{
-				Enabled:  false,
-				Interval: Duration{15 * time.Minute},
+				Enabled:      false,
+				Interval:     Duration{15 * time.Minute},
+				AnomalySigma: 3,
+
+				ProjectedOverage:          true,
+				ProjectedOverageThreshold: 10,
 			},
 		},
 		Image: ImageConfig{
@@ -99,57 +157,61 @@ func DefaultConfig() *Config {
 		Shell: ShellConfig{
 			TUIKeybinding:       `\C-p`,
 			ShowBannerOnStartup: true,
+			ShowBannerOn:        "always",
 			BannerTimeout:       Duration{2 * time.Second},
 			InstantBanner:       true,
+			StarshipMemoTTL:     Duration{2 * time.Second},
 		},
 		Banner: BannerConfig{
 			CompactMaxWidth:   80,
 			StandardMinWidth:  120,
 			WideMinWidth:      160,
 			UltraWideMinWidth: 200,
+			WarmSizes:         []string{"80x24", "120x40", "200x50"},
+		},
+		Status: StatusConfig{
+			BillingWarn: 80,
+			BillingCrit: 100,
+		},
+		Notify: NotifyConfig{
+			MinLevel:       "warning",
+			Cooldown:       Duration{15 * time.Minute},
+			NotifyRecovery: true,
+		},
+		Display: DisplayConfig{
+			ClaudeSort:         "config",
+			MoneyDecimals:      2,
+			ThousandsSeparator: "none",
+			GraphStyle:         "block",
+			Glyphs:             "auto",
+			ResetTimeFormat:    "relative",
+			Clock24h:           true,
 		},
 	}
 }
 
 // applyEnvOverrides checks environment variables and overrides config values.
-// Direct env vars take precedence over _FILE variants (sops-nix pattern).
+// Direct env vars take precedence over _FILE variants (sops-nix pattern),
+// which take precedence over _CMD commands (pass, vault, and the like).
+// The _CMD commands are not run here but by ResolveSecretCommands, so
+// loading the config for a prompt render never spawns them.
 func applyEnvOverrides(cfg *Config) {
-	// Multi-account: ANTHROPIC_ADMIN_KEYS_FILE contains "name:key" lines.
+	// Multi-account: ANTHROPIC_ADMIN_KEYS_FILE yields "name:key" lines.
 	if v := readEnvFile("ANTHROPIC_ADMIN_KEYS_FILE"); v != "" {
-		for _, line := range strings.Split(v, "\n") {
-			line = strings.TrimSpace(line)
-			if line == "" {
-				continue
-			}
-			name, key, ok := strings.Cut(line, ":")
-			if !ok {
-				continue
-			}
-			cfg.Collectors.Claude.Accounts = append(cfg.Collectors.Claude.Accounts, ClaudeAccountConfig{
-				Name:     strings.TrimSpace(name),
-				AdminKey: strings.TrimSpace(key),
-			})
-		}
+		cfg.Collectors.Claude.Accounts = append(cfg.Collectors.Claude.Accounts, parseAdminKeys(v)...)
 	}
-	// Single-account fallback: ANTHROPIC_ADMIN_KEY or _FILE.
-	if len(cfg.Collectors.Claude.Accounts) == 0 {
-		if v := os.Getenv("ANTHROPIC_ADMIN_KEY"); v != "" {
-			cfg.Collectors.Claude.AdminKey = v
-		} else if v := readEnvFile("ANTHROPIC_ADMIN_KEY_FILE"); v != "" {
-			cfg.Collectors.Claude.AdminKey = v
-		}
+	// Single-account fallback, unless ANTHROPIC_ADMIN_KEYS_CMD may still
+	// add accounts.
+	if len(cfg.Collectors.Claude.Accounts) == 0 && !adminKeysCmdPending() {
+		applyAdminKey(cfg, false)
 	}
-	if v := os.Getenv("CIVO_TOKEN"); v != "" {
-		cfg.Collectors.Billing.Civo.APIKey = v
-	} else if v := readEnvFile("CIVO_API_KEY_FILE"); v != "" {
+	if v := envSecret("CIVO_TOKEN", "CIVO_API_KEY_FILE"); v != "" {
 		cfg.Collectors.Billing.Civo.APIKey = v
 	}
 	if v := os.Getenv("CIVO_REGION"); v != "" {
 		cfg.Collectors.Billing.Civo.Region = v
 	}
-	if v := os.Getenv("DIGITALOCEAN_TOKEN"); v != "" {
-		cfg.Collectors.Billing.DigitalOcean.APIKey = v
-	} else if v := readEnvFile("DIGITALOCEAN_TOKEN_FILE"); v != "" {
+	if v := envSecret("DIGITALOCEAN_TOKEN", "DIGITALOCEAN_TOKEN_FILE"); v != "" {
 		cfg.Collectors.Billing.DigitalOcean.APIKey = v
 	}
 	if v := os.Getenv("PPULSE_PROTOCOL"); v != "" {
@@ -163,6 +225,64 @@ func applyEnvOverrides(cfg *Config) {
 	}
 }
 
+// ResolveSecretCommands fills the credentials whose only source is a _CMD
+// environment variable by running those commands. Load leaves them unset so
+// the prompt renderers, which need no credentials, never wait on gpg, pass
+// or a pinentry prompt; the daemon, -collect-once, -profile and -doctor
+// call this before collecting. Later calls on the same config do nothing.
+func ResolveSecretCommands(cfg *Config) {
+	if cfg.secretsResolved {
+		return
+	}
+	cfg.secretsResolved = true
+
+	if adminKeysCmdPending() {
+		if v := readSecretCmd("ANTHROPIC_ADMIN_KEYS_CMD"); v != "" {
+			cfg.Collectors.Claude.Accounts = append(cfg.Collectors.Claude.Accounts, parseAdminKeys(v)...)
+		}
+	}
+	if len(cfg.Collectors.Claude.Accounts) == 0 {
+		applyAdminKey(cfg, true)
+	}
+	if envSecret("CIVO_TOKEN", "CIVO_API_KEY_FILE") == "" {
+		if v := readSecretCmd("CIVO_API_KEY_CMD"); v != "" {
+			cfg.Collectors.Billing.Civo.APIKey = v
+		}
+	}
+	if envSecret("DIGITALOCEAN_TOKEN", "DIGITALOCEAN_TOKEN_FILE") == "" {
+		if v := readSecretCmd("DIGITALOCEAN_TOKEN_CMD"); v != "" {
+			cfg.Collectors.Billing.DigitalOcean.APIKey = v
+		}
+	}
+}
+
+// adminKeysCmdPending reports whether Claude accounts are to come from
+// ANTHROPIC_ADMIN_KEYS_CMD, which only ResolveSecretCommands runs.
+func adminKeysCmdPending() bool {
+	return readEnvFile("ANTHROPIC_ADMIN_KEYS_FILE") == "" && os.Getenv("ANTHROPIC_ADMIN_KEYS_CMD") != ""
+}
+
+// applyAdminKey sets the single-account admin key from ANTHROPIC_ADMIN_KEY,
+// its _FILE variant or, with runCmd, ANTHROPIC_ADMIN_KEY_CMD.
+func applyAdminKey(cfg *Config, runCmd bool) {
+	v := envSecret("ANTHROPIC_ADMIN_KEY", "ANTHROPIC_ADMIN_KEY_FILE")
+	if v == "" && runCmd {
+		v = readSecretCmd("ANTHROPIC_ADMIN_KEY_CMD")
+	}
+	if v != "" {
+		cfg.Collectors.Claude.AdminKey = v
+	}
+}
+
+// envSecret returns the value of envVar or, when that is unset, the
+// secret in the file named by fileVar.
+func envSecret(envVar, fileVar string) string {
+	if v := os.Getenv(envVar); v != "" {
+		return v
+	}
+	return readEnvFile(fileVar)
+}
+
 // readEnvFile reads the content of a file whose path is given by an
 // environment variable. This supports the sops-nix pattern where secrets
 // are decrypted to files and their paths exported as *_FILE env vars.
@@ -184,6 +304,79 @@ func readEnvFile(envVar string) string {
 	return s
 }
 
+// parseAdminKeys parses ANTHROPIC_ADMIN_KEYS "name:key" lines into
+// accounts, skipping blank and malformed lines.
+func parseAdminKeys(v string) []ClaudeAccountConfig {
+	var accounts []ClaudeAccountConfig
+	for _, line := range strings.Split(v, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, key, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		accounts = append(accounts, ClaudeAccountConfig{
+			Name:     strings.TrimSpace(name),
+			AdminKey: strings.TrimSpace(key),
+		})
+	}
+	return accounts
+}
+
+// secretCmdTimeout bounds each *_CMD secret command.
+var secretCmdTimeout = 5 * time.Second
+
+// readSecretCmd returns the output of the command in cmdVar. A failing
+// command is logged and treated as unset, so validation reports the
+// credential as missing along with the command's error.
+func readSecretCmd(cmdVar string) string {
+	v, err := readEnvCmd(cmdVar)
+	if err != nil {
+		slog.Warn("config: secret command failed", "env", cmdVar, "err", err)
+	}
+	return v
+}
+
+// readEnvCmd runs the shell command held in an environment variable and
+// returns its trimmed stdout, e.g. CIVO_API_KEY_CMD="pass show civo/api-key".
+// It returns "" and no error when the env var is unset. The command is
+// killed after secretCmdTimeout; a timeout, non-zero exit, or empty output
+// is an error naming the variable.
+func readEnvCmd(envVar string) (string, error) {
+	cmdline := os.Getenv(envVar)
+	if cmdline == "" {
+		return "", nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), secretCmdTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", cmdline)
+	cmd.Stderr = &stderr
+	// Don't wait on a background child (e.g. a gpg-agent) holding stdout.
+	cmd.WaitDelay = 500 * time.Millisecond
+	out, err := cmd.Output()
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "", fmt.Errorf("%s: command timed out after %s", envVar, secretCmdTimeout)
+	case err != nil:
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			if len(msg) > 200 {
+				msg = msg[:200] + "…"
+			}
+			return "", fmt.Errorf("%s: command failed: %v: %s", envVar, err, msg)
+		}
+		return "", fmt.Errorf("%s: command failed: %v", envVar, err)
+	}
+	s := strings.TrimSpace(string(out))
+	if s == "" {
+		return "", fmt.Errorf("%s: command printed no secret", envVar)
+	}
+	return s, nil
+}
+
 // configSearchPaths returns the ordered list of config file paths to try.
 func configSearchPaths() []string {
 	home, _ := os.UserHomeDir()
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// secretEnvSuffixes marks environment variables whose values are treated as
+// secrets by RedactString, e.g. ANTHROPIC_ADMIN_KEY, CIVO_API_KEY, and
+// DIGITALOCEAN_TOKEN.
+var secretEnvSuffixes = []string{"_KEY", "_TOKEN", "_SECRET", "_PASSWORD"}
+
+// minSecretEnvLen is the shortest secret env value RedactString masks.
+// Shorter values are almost certainly flags or placeholders, and masking
+// them would mangle unrelated text.
+const minSecretEnvLen = 8
+
+// tokenPattern matches long runs of base64/hex-like characters. A match is
+// only masked when it mixes letters and digits, so long identifiers such
+// as config keys are left alone.
+var tokenPattern = regexp.MustCompile(`[A-Za-z0-9_+=-]{32,}`)
+
+// RedactString masks anything in s that looks like a credential, replacing
+// it with "****" and its last four characters. It masks the values of
+// set *_KEY, *_TOKEN, *_SECRET, and *_PASSWORD environment variables
+// wherever they appear, plus any 32+ character base64 or hex run that
+// mixes letters and digits.
+func RedactString(s string) string {
+	for _, v := range secretEnvValues() {
+		s = strings.ReplaceAll(s, v, maskSecret(v))
+	}
+	return tokenPattern.ReplaceAllStringFunc(s, func(m string) string {
+		if !strings.ContainsAny(m, "0123456789") || !strings.ContainsAny(m, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+			return m
+		}
+		return maskSecret(m)
+	})
+}
+
+// RedactWriter returns a writer that passes each Write through
+// RedactString before forwarding it to w. It is meant for line-oriented
+// output such as the standard logger.
+func RedactWriter(w io.Writer) io.Writer {
+	return redactWriter{w: w}
+}
+
+type redactWriter struct {
+	w io.Writer
+}
+
+// Write reports len(p) on success, since the redacted text written to the
+// underlying writer may differ in length.
+func (r redactWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, RedactString(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// maskSecret replaces all but the last four characters of v.
+func maskSecret(v string) string {
+	if len(v) <= 4 {
+		return "****"
+	}
+	return "****" + v[len(v)-4:]
+}
+
+// secretEnvValues returns the values of secret-looking env vars, longest
+// first so a value that contains another is masked whole.
+func secretEnvValues() []string {
+	var vals []string
+	for _, kv := range os.Environ() {
+		name, val, ok := strings.Cut(kv, "=")
+		if !ok || len(val) < minSecretEnvLen || !isSecretEnvName(name) {
+			continue
+		}
+		vals = append(vals, val)
+	}
+	sort.Slice(vals, func(i, j int) bool { return len(vals[i]) > len(vals[j]) })
+	return vals
+}
+
+// isSecretEnvName reports whether name ends in one of secretEnvSuffixes.
+func isSecretEnvName(name string) bool {
+	name = strings.ToUpper(name)
+	for _, suffix := range secretEnvSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
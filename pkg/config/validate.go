@@ -0,0 +1,474 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Severity grades a single validation diagnostic.
+type Severity string
+
+const (
+	// SeverityOK means the item is configured correctly.
+	SeverityOK Severity = "OK"
+
+	// SeverityWarn means the item works but is likely misconfigured.
+	SeverityWarn Severity = "WARN"
+
+	// SeverityFail means the item will not work as configured.
+	SeverityFail Severity = "FAIL"
+)
+
+// Diagnostic is one line of a configuration validation report.
+type Diagnostic struct {
+	Item     string   `json:"item"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// claudeAdminKeyPrefix is the prefix shared by all Anthropic Admin API keys.
+// Regular API keys ("sk-ant-api...") cannot read the usage endpoints.
+const claudeAdminKeyPrefix = "sk-ant-admin"
+
+// Validate runs the static configuration checks: credential presence for
+// every enabled collector, _FILE secret paths and _CMD secret commands, and
+// threshold sanity. It does not touch the network, though a _CMD command
+// may.
+func Validate(cfg *Config) []Diagnostic {
+	var diags []Diagnostic
+	diags = append(diags, ValidateClaudeCredentials(cfg)...)
+	diags = append(diags, ValidateBillingProviders(cfg)...)
+	diags = append(diags, ValidateCommandCollectors(cfg)...)
+	diags = append(diags, ValidateSysMetricsThresholds(cfg)...)
+	diags = append(diags, ValidateStatusRules(cfg)...)
+	diags = append(diags, ValidateBannerSections(cfg)...)
+	diags = append(diags, ValidateHTTPTLS(cfg)...)
+	diags = append(diags, ValidateDashboardURLs(cfg)...)
+	diags = append(diags, ValidatePrimaryAccount(cfg)...)
+	return diags
+}
+
+// ValidatePrimaryAccount warns when display.primary_account names none of
+// the [[collectors.claude.account]] entries. Without account entries the
+// name comes from the discovered organization, so it is not checked.
+func ValidatePrimaryAccount(cfg *Config) []Diagnostic {
+	name := cfg.Display.PrimaryAccount
+	accounts := cfg.Collectors.Claude.Accounts
+	if name == "" || len(accounts) == 0 {
+		return nil
+	}
+	names := make([]string, len(accounts))
+	for i, a := range accounts {
+		names[i] = a.Name
+	}
+	if slices.Contains(names, name) {
+		return nil
+	}
+	return []Diagnostic{{
+		Item:     "display",
+		Severity: SeverityWarn,
+		Message:  fmt.Sprintf("primary_account: unknown claude account %q ignored (configured: %s)", name, strings.Join(names, ", ")),
+	}}
+}
+
+// ValidateDashboardURLs warns about [display.dashboard_urls] keys with an
+// unknown kind and templates that are not absolute http(s) URLs, which
+// terminals would not open.
+func ValidateDashboardURLs(cfg *Config) []Diagnostic {
+	var diags []Diagnostic
+	warn := func(msg string) {
+		diags = append(diags, Diagnostic{Item: "display", Severity: SeverityWarn, Message: msg})
+	}
+	keys := make([]string, 0, len(cfg.Display.DashboardURLs))
+	for key := range cfg.Display.DashboardURLs {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if kind, _, ok := strings.Cut(key, ":"); ok && !slices.Contains(DashboardKinds, kind) {
+			warn(fmt.Sprintf("dashboard_urls: %q has unknown kind %q (known: %s)", key, kind, strings.Join(DashboardKinds, ", ")))
+		}
+		u, err := url.Parse(cfg.Display.DashboardURLs.Resolve("", key, "region", ""))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			warn(fmt.Sprintf("dashboard_urls: %q is not an absolute http(s) URL", key))
+		}
+	}
+	return diags
+}
+
+// ValidateHTTPTLS checks that http_tls_cert and http_tls_key are set
+// together, that http_client_ca is only set with them, and that every
+// configured file is readable. The daemon refuses to start the HTTP API
+// when any of these fail.
+func ValidateHTTPTLS(cfg *Config) []Diagnostic {
+	g := cfg.General
+	if g.HTTPTLSCert == "" && g.HTTPTLSKey == "" && g.HTTPClientCA == "" {
+		return nil
+	}
+	fail := func(msg string) Diagnostic {
+		return Diagnostic{Item: "http", Severity: SeverityFail, Message: msg}
+	}
+	var diags []Diagnostic
+	if g.HTTPTLSCert == "" || g.HTTPTLSKey == "" {
+		diags = append(diags, fail("http_tls_cert and http_tls_key must be set together"))
+	}
+	for _, f := range []struct{ key, path string }{
+		{"http_tls_cert", g.HTTPTLSCert}, {"http_tls_key", g.HTTPTLSKey}, {"http_client_ca", g.HTTPClientCA},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.ReadFile(f.path); err != nil {
+			diags = append(diags, fail(fmt.Sprintf("%s: %v", f.key, err)))
+		}
+	}
+	if len(diags) == 0 && g.HTTPAddr == "" {
+		diags = append(diags, Diagnostic{Item: "http", Severity: SeverityWarn, Message: "TLS is configured but http_addr is empty, so the HTTP API is off"})
+	}
+	return diags
+}
+
+// BannerSections are the IDs of the built-in banner sections, in their
+// default order. [banner] sections and hide_sections also accept command
+// collector names.
+var BannerSections = []string{"status", "system", "tailscale", "k8s", "claude", "billing"}
+
+// ValidateBannerSections warns about names in [banner] sections and
+// hide_sections that are neither a built-in section nor a command
+// collector. The banner ignores them.
+func ValidateBannerSections(cfg *Config) []Diagnostic {
+	known := slices.Clone(BannerSections)
+	for _, cc := range cfg.Collectors.Commands {
+		known = append(known, cc.Name)
+	}
+	var diags []Diagnostic
+	for _, list := range []struct {
+		key   string
+		names []string
+	}{{"sections", cfg.Banner.Sections}, {"hide_sections", cfg.Banner.HideSections}} {
+		for _, name := range list.names {
+			if !slices.Contains(known, name) {
+				diags = append(diags, Diagnostic{
+					Item:     "banner",
+					Severity: SeverityWarn,
+					Message:  fmt.Sprintf("%s: unknown section %q (known: %s)", list.key, name, strings.Join(known, ", ")),
+				})
+			}
+		}
+	}
+	return diags
+}
+
+// statusSubsystems are the names [status] ignore accepts.
+var statusSubsystems = []string{"billing", "claude", "k8s", "sysmetrics", "tailscale"}
+
+// statusLevels are the values [status.weights] accepts.
+var statusLevels = []string{"healthy", "warning", "critical"}
+
+// ValidateStatusRules warns about unknown subsystems in [status] ignore and
+// weights, unknown weight levels, and warning thresholds that are not below
+// their critical ones.
+func ValidateStatusRules(cfg *Config) []Diagnostic {
+	s := cfg.Status
+	var diags []Diagnostic
+	for _, name := range s.Ignore {
+		if !slices.Contains(statusSubsystems, name) {
+			diags = append(diags, Diagnostic{
+				Item:     "status",
+				Severity: SeverityWarn,
+				Message:  fmt.Sprintf("ignore: unknown subsystem %q (known: %s)", name, strings.Join(statusSubsystems, ", ")),
+			})
+		}
+	}
+	names := make([]string, 0, len(s.Weights))
+	for name := range s.Weights {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if !slices.Contains(statusSubsystems, name) {
+			diags = append(diags, Diagnostic{
+				Item:     "status",
+				Severity: SeverityWarn,
+				Message:  fmt.Sprintf("weights: unknown subsystem %q (known: %s)", name, strings.Join(statusSubsystems, ", ")),
+			})
+		}
+		if level := s.Weights[name]; !slices.Contains(statusLevels, level) {
+			diags = append(diags, Diagnostic{
+				Item:     "status",
+				Severity: SeverityWarn,
+				Message:  fmt.Sprintf("weights: %s has unknown level %q (known: %s)", name, level, strings.Join(statusLevels, ", ")),
+			})
+		}
+	}
+	for _, m := range []struct {
+		name       string
+		warn, crit float64
+	}{
+		{"claude", s.ClaudeWarn, s.ClaudeCrit},
+		{"billing", s.BillingWarn, s.BillingCrit},
+		{"cpu", s.CPUWarn, s.CPUCrit},
+		{"memory", s.MemoryWarn, s.MemoryCrit},
+		{"disk", s.DiskWarn, s.DiskCrit},
+	} {
+		if m.warn > 0 && m.crit > 0 && m.warn >= m.crit {
+			diags = append(diags, Diagnostic{
+				Item:     "status",
+				Severity: SeverityWarn,
+				Message:  fmt.Sprintf("%s_warn %.0f is not below %s_crit %.0f", m.name, m.warn, m.name, m.crit),
+			})
+		}
+	}
+	return diags
+}
+
+// ValidateSysMetricsThresholds warns about each sysmetrics metric whose
+// warning threshold is not below its critical one, which would make the
+// warning level unreachable.
+func ValidateSysMetricsThresholds(cfg *Config) []Diagnostic {
+	s := cfg.Collectors.SysMetrics
+	var diags []Diagnostic
+	for _, m := range []struct {
+		name       string
+		warn, crit float64
+	}{{"cpu", s.CPUWarn, s.CPUCrit}, {"memory", s.MemoryWarn, s.MemoryCrit}, {"disk", s.DiskWarn, s.DiskCrit}} {
+		if m.warn > 0 && m.crit > 0 && m.warn >= m.crit {
+			diags = append(diags, Diagnostic{
+				Item:     "sysmetrics",
+				Severity: SeverityWarn,
+				Message:  fmt.Sprintf("%s_warn %.0f is not below %s_crit %.0f", m.name, m.warn, m.name, m.crit),
+			})
+		}
+	}
+	return diags
+}
+
+// ValidateClaudeCredentials checks that the Claude collector has at least one
+// admin key when enabled, that every account key looks like an admin key, and
+// that any ANTHROPIC_ADMIN_KEY(S)_FILE secret exists and parses. Keys that
+// are only available through ANTHROPIC_ADMIN_KEY(S)_CMD are fetched by
+// running the command, so a failing command is reported as such.
+func ValidateClaudeCredentials(cfg *Config) []Diagnostic {
+	cc := cfg.Collectors.Claude
+	if !cc.Enabled {
+		return []Diagnostic{{Item: "claude", Severity: SeverityOK, Message: "collector disabled"}}
+	}
+
+	var diags []Diagnostic
+	for _, env := range []string{"ANTHROPIC_ADMIN_KEY_FILE", "ANTHROPIC_ADMIN_KEYS_FILE"} {
+		if d := checkEnvFile(env); d != nil {
+			diags = append(diags, *d)
+		}
+	}
+	accounts := cc.Accounts
+	keysVar, keys := "ANTHROPIC_ADMIN_KEYS_FILE", readEnvFile("ANTHROPIC_ADMIN_KEYS_FILE")
+	if keys == "" && len(accounts) == 0 {
+		v, err := readEnvCmd("ANTHROPIC_ADMIN_KEYS_CMD")
+		if err != nil {
+			diags = append(diags, Diagnostic{Item: "claude", Severity: SeverityFail, Message: err.Error()})
+		}
+		keysVar, keys = "ANTHROPIC_ADMIN_KEYS_CMD", v
+		accounts = parseAdminKeys(v)
+	}
+	if keys != "" {
+		for i, line := range strings.Split(keys, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			if _, _, ok := strings.Cut(line, ":"); !ok {
+				diags = append(diags, Diagnostic{
+					Item:     "claude." + keysVar,
+					Severity: SeverityFail,
+					Message:  fmt.Sprintf("line %d is not in name:key form", i+1),
+				})
+			}
+		}
+	}
+
+	if len(accounts) == 0 {
+		key := cc.AdminKey
+		if key == "" {
+			v, err := readEnvCmd("ANTHROPIC_ADMIN_KEY_CMD")
+			if err != nil {
+				return append(diags, Diagnostic{Item: "claude", Severity: SeverityFail, Message: err.Error()})
+			}
+			key = v
+		}
+		diags = append(diags, checkClaudeKey("claude", key, "admin_key, ANTHROPIC_ADMIN_KEY, ANTHROPIC_ADMIN_KEY_FILE, or ANTHROPIC_ADMIN_KEY_CMD"))
+		return diags
+	}
+	for _, a := range accounts {
+		item := "claude.account." + a.Name
+		if a.Name == "" {
+			item = "claude.account"
+			diags = append(diags, Diagnostic{Item: item, Severity: SeverityWarn, Message: "account has no name"})
+		}
+		diags = append(diags, checkClaudeKey(item, a.AdminKey, "admin_key, ANTHROPIC_ADMIN_KEYS_FILE, or ANTHROPIC_ADMIN_KEYS_CMD"))
+		if a.WarnThreshold > 0 && a.CritThreshold > 0 && a.WarnThreshold >= a.CritThreshold {
+			diags = append(diags, Diagnostic{
+				Item:     item,
+				Severity: SeverityWarn,
+				Message:  fmt.Sprintf("warn_threshold %.0f is not below crit_threshold %.0f", a.WarnThreshold, a.CritThreshold),
+			})
+		}
+	}
+	return diags
+}
+
+// checkClaudeKey grades a single Claude admin key. sources names where the
+// key can be set, for the failure message.
+func checkClaudeKey(item, key, sources string) Diagnostic {
+	switch {
+	case key == "":
+		return Diagnostic{Item: item, Severity: SeverityFail,
+			Message: "no admin key (set " + sources + ")"}
+	case !strings.HasPrefix(key, claudeAdminKeyPrefix):
+		return Diagnostic{Item: item, Severity: SeverityWarn,
+			Message: "key does not look like an Admin API key (expected " + claudeAdminKeyPrefix + "...)"}
+	default:
+		return Diagnostic{Item: item, Severity: SeverityOK, Message: "admin key present"}
+	}
+}
+
+// ValidateBillingProviders checks that every enabled billing provider has an
+// API key and no negative credits, and warns when billing is enabled with
+// no providers. A key that
+// is only available through a _CMD variable is fetched by running the
+// command, so it counts as configured when the command succeeds.
+func ValidateBillingProviders(cfg *Config) []Diagnostic {
+	bc := cfg.Collectors.Billing
+	if !bc.Enabled {
+		return []Diagnostic{{Item: "billing", Severity: SeverityOK, Message: "collector disabled"}}
+	}
+
+	var diags []Diagnostic
+	providers := []struct {
+		item, key, envVar, fileVar, cmdVar string
+		enabled                            bool
+		credits                            *float64
+	}{
+		{"billing.civo", bc.Civo.APIKey, "CIVO_TOKEN", "CIVO_API_KEY_FILE", "CIVO_API_KEY_CMD", bc.Civo.Enabled, bc.Civo.Credits},
+		{"billing.digitalocean", bc.DigitalOcean.APIKey, "DIGITALOCEAN_TOKEN", "DIGITALOCEAN_TOKEN_FILE", "DIGITALOCEAN_TOKEN_CMD", bc.DigitalOcean.Enabled, bc.DigitalOcean.Credits},
+	}
+	enabled := 0
+	for _, p := range providers {
+		if !p.enabled {
+			continue
+		}
+		enabled++
+		if p.credits != nil && *p.credits < 0 {
+			diags = append(diags, Diagnostic{Item: p.item, Severity: SeverityFail,
+				Message: fmt.Sprintf("credits %g must not be negative", *p.credits)})
+		}
+		if d := checkEnvFile(p.fileVar); d != nil {
+			diags = append(diags, *d)
+		}
+		key := p.key
+		if key == "" {
+			v, err := readEnvCmd(p.cmdVar)
+			if err != nil {
+				diags = append(diags, Diagnostic{Item: p.item, Severity: SeverityFail, Message: err.Error()})
+				continue
+			}
+			key = v
+		}
+		if key == "" {
+			diags = append(diags, Diagnostic{Item: p.item, Severity: SeverityFail,
+				Message: fmt.Sprintf("no API key (set api_key, %s, %s, or %s)", p.envVar, p.fileVar, p.cmdVar)})
+			continue
+		}
+		diags = append(diags, Diagnostic{Item: p.item, Severity: SeverityOK, Message: "API key present"})
+	}
+	if enabled == 0 {
+		diags = append(diags, Diagnostic{Item: "billing", Severity: SeverityWarn,
+			Message: "collector enabled but no providers are enabled"})
+	}
+	return diags
+}
+
+// commandNamePattern restricts command collector names to safe cache keys.
+var commandNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// reservedCollectorNames are cache keys owned by built-in collectors and
+// daemon state, which a command collector must not overwrite.
+var reservedCollectorNames = map[string]bool{
+	"sysmetrics": true, "tailscale": true, "k8s": true, "claude": true,
+	"billing": true, "waifu": true, "infra": true,
+	"claude-history": true, "billing-history": true, "snooze": true,
+}
+
+// CommandNameError returns why name cannot be used for a command
+// collector, or nil when it can. seen holds the names already taken.
+func CommandNameError(name string, seen map[string]bool) error {
+	switch {
+	case !commandNamePattern.MatchString(name):
+		return fmt.Errorf("invalid name %q (use lowercase letters, digits, '-' and '_')", name)
+	case reservedCollectorNames[name]:
+		return fmt.Errorf("name %q is reserved for a built-in collector", name)
+	case seen[name]:
+		return fmt.Errorf("duplicate name %q", name)
+	}
+	return nil
+}
+
+// ValidateCommandCollectors checks each [[collectors.command]] entry for a
+// usable name and an executable that can be found.
+func ValidateCommandCollectors(cfg *Config) []Diagnostic {
+	var diags []Diagnostic
+	seen := make(map[string]bool)
+	for i, cc := range cfg.Collectors.Commands {
+		item := fmt.Sprintf("command[%d]", i)
+		if cc.Name != "" {
+			item = "command." + cc.Name
+		}
+		if err := CommandNameError(cc.Name, seen); err != nil {
+			diags = append(diags, Diagnostic{Item: item, Severity: SeverityFail, Message: err.Error()})
+			continue
+		}
+		seen[cc.Name] = true
+		if cc.Exec == "" {
+			diags = append(diags, Diagnostic{Item: item, Severity: SeverityFail, Message: "no exec set"})
+			continue
+		}
+		if _, err := exec.LookPath(cc.Exec); err != nil {
+			diags = append(diags, Diagnostic{Item: item, Severity: SeverityFail, Message: fmt.Sprintf("cannot run %s: %v", cc.Exec, err)})
+			continue
+		}
+		diags = append(diags, Diagnostic{Item: item, Severity: SeverityOK, Message: "executable found"})
+	}
+	return diags
+}
+
+// checkEnvFile reports a failure when a *_FILE env var is set but its path
+// cannot be read. It returns nil when the variable is unset or readable, so
+// that callers only surface the problem case.
+func checkEnvFile(envVar string) *Diagnostic {
+	path := os.Getenv(envVar)
+	if path == "" {
+		return nil
+	}
+	if _, err := os.ReadFile(path); err != nil {
+		return &Diagnostic{
+			Item:     envVar,
+			Severity: SeverityFail,
+			Message:  fmt.Sprintf("cannot read %s: %v", path, err),
+		}
+	}
+	return nil
+}
+
+// HasFailures reports whether any diagnostic has SeverityFail.
+func HasFailures(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityFail {
+			return true
+		}
+	}
+	return false
+}
@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
 )
 
 // BannerEntry holds a single pre-rendered banner for a specific terminal
@@ -20,6 +24,14 @@ type BannerEntry struct {
 	Protocol  string    `json:"protocol"`
 	Timestamp time.Time `json:"timestamp"`
 	Hash      string    `json:"hash"`
+
+	// Inputs is the banner.CacheKey of the data the entry was rendered
+	// from, so the warmer can skip sizes whose inputs are unchanged.
+	Inputs string `json:"inputs,omitempty"`
+
+	// Sections lists the widget IDs a RENDER request limited the banner
+	// to, sorted. Empty means every section.
+	Sections []string `json:"sections,omitempty"`
 }
 
 // bannerCacheFile is the on-disk representation: a map of cache keys to entries.
@@ -33,6 +45,11 @@ type bannerCacheFile struct {
 type BannerCache struct {
 	path string
 	mu   sync.Mutex
+
+	// limit caps the entries Put keeps besides those for pinned keys;
+	// zero keeps every entry (see SetLimit).
+	limit  int
+	pinned map[string]bool
 }
 
 // NewBannerCache creates a BannerCache backed by the given file path.
@@ -40,15 +57,38 @@ func NewBannerCache(path string) *BannerCache {
 	return &BannerCache{path: path}
 }
 
-// bannerKey returns the cache key for a given terminal configuration.
-func bannerKey(width, height int, protocol string) string {
-	return fmt.Sprintf("%dx%d/%s", width, height, protocol)
+// SetLimit makes Put keep at most limit entries besides the full banners
+// for the pinned sizes, evicting the oldest first. Zero keeps every entry.
+func (bc *BannerCache) SetLimit(limit int, pinned []warmSize) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	bc.limit = limit
+	bc.pinned = make(map[string]bool, len(pinned))
+	for _, s := range pinned {
+		bc.pinned[bannerKey(s.width, s.height, s.protocol, nil)] = true
+	}
+}
+
+// bannerKey returns the cache key for a given terminal configuration and,
+// for a banner limited to some sections, those sections.
+func bannerKey(width, height int, protocol string, sections []string) string {
+	key := fmt.Sprintf("%dx%d/%s", width, height, protocol)
+	if len(sections) > 0 {
+		key += "+" + strings.Join(sections, ",")
+	}
+	return key
 }
 
 // Get retrieves a cached banner entry matching the given terminal dimensions
 // and graphics protocol. Returns the entry and true if found, nil and false
 // otherwise.
 func (bc *BannerCache) Get(width, height int, protocol string) (*BannerEntry, bool) {
+	return bc.GetSections(width, height, protocol, nil)
+}
+
+// GetSections is Get for a banner limited to the given sorted sections, as
+// stored by Put for an entry with those Sections.
+func (bc *BannerCache) GetSections(width, height int, protocol string, sections []string) (*BannerEntry, bool) {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
 
@@ -57,14 +97,15 @@ func (bc *BannerCache) Get(width, height int, protocol string) (*BannerEntry, bo
 		return nil, false
 	}
 
-	key := bannerKey(width, height, protocol)
+	key := bannerKey(width, height, protocol, sections)
 	entry, ok := cf.Entries[key]
 	return entry, ok
 }
 
 // Put stores a pre-rendered banner entry in the cache. The entry is keyed
-// by its Width, Height, and Protocol fields. A content hash is computed
-// automatically if not already set.
+// by its Width, Height, Protocol, and Sections fields. A content hash is computed
+// automatically if not already set. Entries beyond the limit are evicted
+// (see SetLimit).
 func (bc *BannerCache) Put(entry *BannerEntry) error {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
@@ -81,12 +122,36 @@ func (bc *BannerCache) Put(entry *BannerEntry) error {
 		}
 	}
 
-	key := bannerKey(entry.Width, entry.Height, entry.Protocol)
+	key := bannerKey(entry.Width, entry.Height, entry.Protocol, entry.Sections)
 	cf.Entries[key] = entry
+	bc.evict(cf)
 
 	return bc.save(cf)
 }
 
+// evict drops the oldest unpinned entries beyond the limit. Caller must
+// hold bc.mu.
+func (bc *BannerCache) evict(cf *bannerCacheFile) {
+	if bc.limit <= 0 {
+		return
+	}
+	var keys []string
+	for key := range cf.Entries {
+		if !bc.pinned[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) <= bc.limit {
+		return
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cf.Entries[a].Timestamp.Compare(cf.Entries[b].Timestamp)
+	})
+	for _, key := range keys[:len(keys)-bc.limit] {
+		delete(cf.Entries, key)
+	}
+}
+
 // Invalidate clears all entries from the cache by removing the file.
 func (bc *BannerCache) Invalidate() error {
 	bc.mu.Lock()
@@ -145,7 +210,7 @@ func (bc *BannerCache) load() (*bannerCacheFile, error) {
 // save writes the cache file atomically. Caller must hold bc.mu.
 func (bc *BannerCache) save(cf *bannerCacheFile) error {
 	dir := filepath.Dir(bc.path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+	if err := os.MkdirAll(dir, cache.DirMode); err != nil {
 		return fmt.Errorf("create banner cache directory: %w", err)
 	}
 
@@ -155,7 +220,7 @@ func (bc *BannerCache) save(cf *bannerCacheFile) error {
 	}
 
 	tmp := bc.path + ".tmp"
-	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+	if err := os.WriteFile(tmp, data, cache.FileMode); err != nil {
 		return fmt.Errorf("write temp banner cache: %w", err)
 	}
 
@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// applyCircuitBreaker hands the circuit breaker configured in cfg to
+// runner. An unset cooldown uses collectors.DefaultCircuitCooldown.
+func applyCircuitBreaker(runner *collectors.Runner, cfg *config.Config) {
+	cooldown := cfg.General.CircuitCooldown.Duration
+	if cooldown <= 0 {
+		cooldown = collectors.DefaultCircuitCooldown
+	}
+	runner.SetCircuitBreaker(&collectors.CircuitBreaker{
+		Threshold: cfg.General.CircuitThreshold,
+		Cooldown:  cooldown,
+	})
+}
+
+// applyCircuits sets the circuit state of each collector in h, and of its
+// parts, from the running collectors at now. It is read live because an open circuit
+// turns half-open when its cooldown ends, without a collection to record.
+func (d *Daemon) applyCircuits(h *HealthStatus, now time.Time) {
+	d.mu.Lock()
+	reg := d.registry
+	d.mu.Unlock()
+	if reg == nil {
+		return
+	}
+	for name, c := range h.Collectors {
+		if s, ok := reg.Status(name); ok {
+			c.Circuit = s.Circuit(now)
+		}
+		if col, ok := reg.Get(name); ok {
+			if pb, ok := col.(collectors.PartBreaker); ok {
+				c.Parts = pb.PartCircuits(now)
+			}
+		}
+		h.Collectors[name] = c
+	}
+}
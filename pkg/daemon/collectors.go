@@ -3,14 +3,18 @@ package daemon
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
 
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/anomaly"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/command"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
@@ -24,33 +28,55 @@ func BuildRegistry(cfg *config.Config) *collectors.Registry {
 	reg := collectors.NewRegistry()
 
 	if cfg.Collectors.SysMetrics.Enabled {
+		scfg := cfg.Collectors.SysMetrics
 		c := sysmetrics.New(sysmetrics.Config{
-			FastInterval: cfg.Collectors.SysMetrics.Interval.Duration,
+			FastInterval: scfg.Interval.Duration,
 			SlowInterval: 60 * time.Second,
+			Thresholds: sysmetrics.Thresholds{
+				CPU:    sysmetrics.Threshold{Warn: scfg.CPUWarn, Crit: scfg.CPUCrit},
+				Memory: sysmetrics.Threshold{Warn: scfg.MemoryWarn, Crit: scfg.MemoryCrit},
+				Disk:   sysmetrics.Threshold{Warn: scfg.DiskWarn, Crit: scfg.DiskCrit},
+			},
 		})
 		if err := reg.Register(c); err != nil {
-			log.Printf("daemon: register sysmetrics: %v", err)
+			slog.Error("daemon: register collector", "collector", "sysmetrics", "err", err)
 		}
 	}
 
 	if cfg.Collectors.Tailscale.Enabled {
 		c := tailscale.New(
-			tailscale.Config{Interval: cfg.Collectors.Tailscale.Interval.Duration},
+			tailscale.Config{
+				Interval:       cfg.Collectors.Tailscale.Interval.Duration,
+				StaleThreshold: cfg.Collectors.Tailscale.StaleAfter.Duration,
+			},
 			tailscale.NewLocalClient(""),
 		)
 		if err := reg.Register(c); err != nil {
-			log.Printf("daemon: register tailscale: %v", err)
+			slog.Error("daemon: register collector", "collector", "tailscale", "err", err)
 		}
 	}
 
 	if cfg.Collectors.Kubernetes.Enabled {
+		kcfg := cfg.Collectors.Kubernetes
+		overrides := make(map[string]k8s.ContextConfig, len(kcfg.Overrides))
+		for name, o := range kcfg.Overrides {
+			overrides[name] = k8s.ContextConfig{
+				Kubeconfig:   o.Kubeconfig,
+				Namespaces:   o.Namespaces,
+				DashboardURL: o.DashboardURL,
+			}
+		}
 		c := k8s.New(k8s.Config{
-			Interval:   cfg.Collectors.Kubernetes.Interval.Duration,
-			Contexts:   cfg.Collectors.Kubernetes.Contexts,
-			Namespaces: cfg.Collectors.Kubernetes.Namespaces,
+			Interval:             kcfg.Interval.Duration,
+			Kubeconfig:           kcfg.Kubeconfig,
+			Contexts:             kcfg.Contexts,
+			Namespaces:           kcfg.Namespaces,
+			PodPressureThreshold: kcfg.PodPressureThreshold,
+			ContextTimeout:       kcfg.ContextTimeout.Duration,
+			Overrides:            overrides,
 		})
 		if err := reg.Register(c); err != nil {
-			log.Printf("daemon: register k8s: %v", err)
+			slog.Error("daemon: register collector", "collector", "k8s", "err", err)
 		}
 	}
 
@@ -63,10 +89,21 @@ func BuildRegistry(cfg *config.Config) *collectors.Registry {
 			})
 		}
 		for _, a := range cfg.Collectors.Claude.Accounts {
+			typ, err := claude.ParseAccountType(a.Type)
+			if err != nil {
+				slog.Warn("daemon: claude account type", "account", a.Name, "err", err)
+				typ = claude.AccountTypeAccount
+			}
 			accounts = append(accounts, claude.AccountConfig{
 				Name:           a.Name,
+				ShortName:      a.ShortName,
 				AdminAPIKey:    a.AdminKey,
 				OrganizationID: a.OrganizationID,
+				BudgetUSD:      a.BudgetUSD,
+				WarnThreshold:  a.WarnThreshold,
+				CritThreshold:  a.CritThreshold,
+				Type:           typ,
+				Tier:           a.Tier,
 			})
 		}
 		c := claude.New(
@@ -77,7 +114,7 @@ func BuildRegistry(cfg *config.Config) *collectors.Registry {
 			nil, // use default HTTP client
 		)
 		if err := reg.Register(c); err != nil {
-			log.Printf("daemon: register claude: %v", err)
+			slog.Error("daemon: register collector", "collector", "claude", "err", err)
 		}
 	}
 
@@ -93,36 +130,75 @@ func BuildRegistry(cfg *config.Config) *collectors.Registry {
 		}
 		c := waifu.New(wcfg, nil)
 		if err := reg.Register(c); err != nil {
-			log.Printf("daemon: register waifu: %v", err)
+			slog.Error("daemon: register collector", "collector", "waifu", "err", err)
 		}
 	}
 
 	if cfg.Collectors.Billing.Enabled {
 		bcfg := billing.Config{
-			Interval: cfg.Collectors.Billing.Interval.Duration,
+			Interval:  cfg.Collectors.Billing.Interval.Duration,
+			BudgetUSD: cfg.Collectors.Billing.BudgetUSD,
+
+			ProjectedOverage:          cfg.Collectors.Billing.ProjectedOverage,
+			ProjectedOverageThreshold: cfg.Collectors.Billing.ProjectedOverageThreshold,
 		}
 		if cfg.Collectors.Billing.Civo.APIKey != "" {
 			bcfg.Civo = &billing.CivoConfig{
 				APIKey: cfg.Collectors.Billing.Civo.APIKey,
 				Region: cfg.Collectors.Billing.Civo.Region,
+
+				CreditsUSD: creditsUSD(cfg.Collectors.Billing.Civo.Credits),
 			}
 		}
 		if cfg.Collectors.Billing.DigitalOcean.APIKey != "" {
 			bcfg.DigitalOcean = &billing.DOConfig{
 				APIToken: cfg.Collectors.Billing.DigitalOcean.APIKey,
+
+				CreditsUSD: creditsUSD(cfg.Collectors.Billing.DigitalOcean.Credits),
 			}
 		}
 		c := billing.New(bcfg)
 		if err := reg.Register(c); err != nil {
-			log.Printf("daemon: register billing: %v", err)
+			slog.Error("daemon: register collector", "collector", "billing", "err", err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, cc := range cfg.Collectors.Commands {
+		if err := config.CommandNameError(cc.Name, seen); err != nil {
+			slog.Error("daemon: register command collector", "err", err)
+			continue
+		}
+		seen[cc.Name] = true
+		c := command.New(command.Config{
+			Name:     cc.Name,
+			Exec:     cc.Exec,
+			Args:     cc.Args,
+			Interval: cc.Interval.Duration,
+			Timeout:  cc.Timeout.Duration,
+		})
+		if err := reg.Register(c); err != nil {
+			slog.Error("daemon: register collector", "collector", cc.Name, "err", err)
 		}
 	}
 
 	return reg
 }
 
+// creditsUSD returns a provider's configured monthly credit, or zero when
+// none is set.
+func creditsUSD(credits *float64) float64 {
+	if credits == nil {
+		return 0
+	}
+	return *credits
+}
+
 // ConsumeUpdates reads from the updates channel and writes each collector's
-// data to a JSON cache file. It blocks until the context is cancelled.
+// data to a JSON cache file. Failed collections are recorded in the
+// collector's health instead. After each stored update the banners for
+// banner.warm_sizes are pre-rendered (see warmBanners). It blocks until the
+// context is cancelled.
 func ConsumeUpdates(ctx context.Context, updates <-chan collectors.Update, cacheDir string, d *Daemon) {
 	for {
 		select {
@@ -130,28 +206,144 @@ func ConsumeUpdates(ctx context.Context, updates <-chan collectors.Update, cache
 			return
 		case u := <-updates:
 			if u.Error != nil {
+				d.recordCollector(u.Source, u.Error)
 				continue
 			}
-			// Write data to cache file via atomic rename.
-			data, err := json.Marshal(u.Data)
-			if err != nil {
-				log.Printf("daemon: marshal %s data: %v", u.Source, err)
+			if err := storeUpdate(cacheDir, u, d); err != nil {
+				slog.Error("daemon: store update", "collector", u.Source, "err", err)
 				continue
 			}
+			d.observeStatus(ctx, u)
+			d.warmBanners()
+		}
+	}
+}
 
-			dest := filepath.Join(cacheDir, u.Source+".json")
-			tmp := dest + ".tmp"
-			if err := os.WriteFile(tmp, data, 0o644); err != nil {
-				log.Printf("daemon: write %s cache: %v", u.Source, err)
-				continue
-			}
-			if err := os.Rename(tmp, dest); err != nil {
-				log.Printf("daemon: rename %s cache: %v", u.Source, err)
-				continue
-			}
+// storeUpdate writes a successful update to <source>.json in cacheDir via
+// atomic rename, records derived history, and marks the collector healthy.
+// Calls for one daemon are serialized, since the histories are loaded,
+// updated and saved whole. d may be nil when no daemon is tracking
+// collector health.
+func storeUpdate(cacheDir string, u collectors.Update, d *Daemon) error {
+	if d != nil {
+		d.storeMu.Lock()
+		defer d.storeMu.Unlock()
+	}
 
-			// Update daemon health from collector status.
-			d.UpdateCollector(u.Source, true, 0)
-		}
+	// Spend history runs first so the anomaly it finds is part of the
+	// cached report.
+	if report, ok := u.Data.(*billing.BillingReport); ok {
+		recordSpendHistory(cacheDir, report, d.anomalySigma())
+	}
+
+	data, err := json.Marshal(u.Data)
+	if err != nil {
+		return fmt.Errorf("marshal %s data: %w", u.Source, err)
+	}
+
+	if err := writeCacheFile(filepath.Join(cacheDir, u.Source+".json"), data); err != nil {
+		return fmt.Errorf("write %s cache: %w", u.Source, err)
+	}
+
+	if report, ok := u.Data.(*claude.UsageReport); ok {
+		recordClaudeHistory(cacheDir, report, d.claudeHistoryPoints())
+	}
+
+	// Update daemon health from collector status.
+	if d != nil {
+		d.recordCollector(u.Source, nil)
+	}
+	return nil
+}
+
+// writeCacheFile replaces path with data through a uniquely named
+// temporary file in the same directory, so concurrent writers, including
+// other processes, never rename each other's partial writes into place.
+func writeCacheFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	if err := tmp.Chmod(cache.FileMode); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// recordClaudeHistory appends the report's per-account costs to the
+// persisted history used for banner and starship sparklines.
+func recordClaudeHistory(cacheDir string, report *claude.UsageReport, maxPoints int) {
+	path := claude.HistoryPath(cacheDir)
+	h, err := claude.LoadHistory(path)
+	if err != nil {
+		// A corrupt history only loses the sparkline; start over.
+		slog.Warn("daemon: load claude history", "err", err)
+		h = claude.NewHistory()
+	}
+	h.Record(report, maxPoints)
+	if err := h.Save(path); err != nil {
+		slog.Error("daemon: save claude history", "err", err)
+	}
+}
+
+// recordSpendHistory folds the report into the persisted daily spend
+// series, fills in last month's totals, and sets report.Anomaly when the
+// latest day is a spike.
+func recordSpendHistory(cacheDir string, report *billing.BillingReport, sigma float64) {
+	path := billing.SpendHistoryPath(cacheDir)
+	h, err := billing.LoadSpendHistory(path)
+	if err != nil {
+		// A corrupt history only delays anomaly detection; start over.
+		slog.Warn("daemon: load spend history", "err", err)
+		h = &billing.SpendHistory{}
+	}
+	h.Record(report)
+	if err := h.Save(path); err != nil {
+		slog.Error("daemon: save spend history", "err", err)
+	}
+	h.ApplyPreviousMonth(report)
+	report.Anomaly = anomaly.DetectSigma(h.Days, sigma)
+}
+
+// anomalySigma returns the configured spike threshold, or zero to let the
+// anomaly package apply its default.
+func (d *Daemon) anomalySigma() float64 {
+	if d == nil {
+		return 0
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.appCfg == nil {
+		return 0
+	}
+	return d.appCfg.Collectors.Billing.AnomalySigma
+}
+
+// claudeHistoryPoints returns the configured per-account history cap, or
+// zero to let the claude package apply its default.
+func (d *Daemon) claudeHistoryPoints() int {
+	if d == nil {
+		return 0
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.appCfg == nil {
+		return 0
 	}
+	return d.appCfg.Collectors.Claude.HistoryPoints
 }
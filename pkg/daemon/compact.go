@@ -0,0 +1,59 @@
+package daemon
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
+)
+
+// builtinCollectorKeys are the cache keys of the collectors the daemon can
+// run. A file for one of them that is not running holds data nothing will
+// refresh. Command collectors are named by the user, so their files cannot
+// be told apart from unrelated ones and are never removed.
+var builtinCollectorKeys = []string{"sysmetrics", "tailscale", "k8s", "claude", "billing", "waifu"}
+
+// compactCache clears cacheDir of files nothing reads any more: the
+// <key>.json of built-in collectors that are not registered, ".json.tmp"
+// files left by interrupted writes, and cache.Store entries for keys other
+// than the registered collectors (see Store.Compact). Derived files such as
+// the spend and Claude histories are kept, as is anything unrecognized.
+// Every removal is logged.
+func compactCache(cacheDir string, registered []string) {
+	active := make(map[string]bool, len(registered))
+	for _, name := range registered {
+		active[name] = true
+	}
+	for _, key := range builtinCollectorKeys {
+		if active[key] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(cacheDir, key+".json")); err == nil {
+			slog.Info("daemon: removed cache of inactive collector", "collector", key)
+		}
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if name := e.Name(); !e.IsDir() && strings.HasSuffix(name, ".json.tmp") {
+			if err := os.Remove(filepath.Join(cacheDir, name)); err == nil {
+				slog.Info("daemon: removed leftover cache file", "file", name)
+			}
+		}
+	}
+
+	store, err := cache.NewStore(cache.StoreConfig{Dir: cacheDir})
+	if err != nil {
+		slog.Warn("daemon: compact cache", "err", err)
+		return
+	}
+	defer store.Close()
+	if _, err := store.Compact(registered); err != nil {
+		slog.Warn("daemon: compact cache", "err", err)
+	}
+}
@@ -6,16 +6,22 @@ package daemon
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"sync"
+	"syscall"
 	"time"
 
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/status"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
 )
 
 // Config holds all configuration for the daemon process.
@@ -33,6 +39,7 @@ type Config struct {
 	SocketPath string
 
 	// DataDir is the directory for persistent data storage.
+	// Default: $XDG_STATE_HOME/prompt-pulse or ~/.local/state/prompt-pulse
 	DataDir string
 
 	// BannerCacheFile is the path to the pre-rendered banner cache.
@@ -48,11 +55,25 @@ func DefaultConfig() Config {
 		PIDFile:         filepath.Join(base, "prompt-pulse.pid"),
 		HealthFile:      filepath.Join(base, "prompt-pulse-health.json"),
 		SocketPath:      filepath.Join(base, "prompt-pulse.sock"),
-		DataDir:         filepath.Join(base, "data"),
+		DataDir:         defaultDataDir(base),
 		BannerCacheFile: filepath.Join(base, "prompt-pulse-banner.json"),
 	}
 }
 
+// defaultDataDir returns a prompt-pulse subdirectory under XDG_STATE_HOME,
+// or ~/.local/state when it is unset, so collected data survives the
+// runtime directory being cleared at logout. Without a home directory it
+// falls back to base/data.
+func defaultDataDir(base string) string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "prompt-pulse")
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		return filepath.Join(home, ".local", "state", "prompt-pulse")
+	}
+	return filepath.Join(base, "data")
+}
+
 // defaultBasePath returns a prompt-pulse subdirectory under XDG_RUNTIME_DIR
 // (if set), otherwise /tmp/prompt-pulse-{uid}. Using a subdirectory avoids
 // EROFS errors when systemd's ProtectSystem=strict makes the parent read-only.
@@ -65,19 +86,62 @@ func defaultBasePath() string {
 
 // HealthStatus represents the current state of the daemon and its collectors.
 type HealthStatus struct {
+	// Mode is how the writer collects: HealthModeDaemon, or
+	// HealthModeCollectOnce for a single -collect-once run whose process
+	// has exited since. Health files from before modes were recorded are
+	// the daemon's.
+	Mode string `json:"mode,omitempty"`
+
 	PID        int                        `json:"pid"`
 	Uptime     time.Duration              `json:"uptime_ns"`
 	StartedAt  time.Time                  `json:"started_at"`
 	Collectors map[string]CollectorHealth `json:"collectors"`
 	LastUpdate time.Time                  `json:"last_update"`
+
+	// Snoozed reports whether notifications are silenced by SNOOZE, and
+	// SnoozedUntil when the snooze ends.
+	Snoozed      bool       `json:"snoozed"`
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+
+	// Disabled lists the collectors switched off at runtime with the
+	// DISABLE command.
+	Disabled []string `json:"disabled,omitempty"`
+
+	// QuietHours reports whether the configured quiet hours are in effect,
+	// stretching collector intervals.
+	QuietHours bool `json:"quiet_hours,omitempty"`
 }
 
+// Health file modes, recorded in HealthStatus.Mode.
+const (
+	// HealthModeDaemon marks health written by the long-running daemon.
+	HealthModeDaemon = "daemon"
+
+	// HealthModeCollectOnce marks health written by CollectOnce, e.g. from
+	// cron, where no daemon is expected to be running.
+	HealthModeCollectOnce = "collect-once"
+)
+
 // CollectorHealth tracks the health of a single collector within the daemon.
 type CollectorHealth struct {
 	Name       string    `json:"name"`
 	Healthy    bool      `json:"healthy"`
 	LastRun    time.Time `json:"last_run"`
 	ErrorCount int64     `json:"error_count"`
+
+	// LastError is the error from the most recent failed collection, such
+	// as a watchdog timeout. It is cleared by a successful one.
+	LastError string `json:"last_error,omitempty"`
+
+	// Circuit is the state of the collector's circuit breaker: closed,
+	// open while a collector that kept failing is skipped, or half-open
+	// once it may be retried.
+	Circuit collectors.CircuitState `json:"circuit,omitempty"`
+
+	// Parts is the circuit state of each part of the collector, such as a
+	// billing provider or Claude account, that has failed since it last
+	// succeeded. A part's circuit opens while the collector's stays closed.
+	Parts map[string]collectors.CircuitState `json:"parts,omitempty"`
 }
 
 // Daemon is the main background process that orchestrates data collection,
@@ -85,14 +149,55 @@ type CollectorHealth struct {
 type Daemon struct {
 	cfg       Config
 	appCfg    *config.Config
+	cfgPath   string
 	startedAt time.Time
 	running   bool
+	once      bool // collecting for CollectOnce rather than running
 	ipc       *IPCServer
 	banner    *BannerCache
 
+	// bannerData builds the widgets of the banners pre-rendered for
+	// banner.warm_sizes; nil disables warming.
+	bannerData BannerDataFunc
+
+	// bannerWarnings reports invalid banner settings when a config is
+	// loaded; nil reports none.
+	bannerWarnings BannerWarningsFunc
+
 	// collectors tracks health state for registered collectors.
 	collectors map[string]*CollectorHealth
 
+	// storeMu serializes storeUpdate, so the update consumer and REFRESH
+	// never interleave writes of a cache file or the history files.
+	storeMu sync.Mutex
+
+	// registry, runner, and cacheDir are set once collectors start and
+	// allow IPC commands to trigger out-of-band collection.
+	registry *collectors.Registry
+	runner   *collectors.Runner
+	cacheDir string
+
+	// updates carries every runner's results to the one ConsumeUpdates
+	// goroutine, so a reload can swap runners without restarting it.
+	updates chan collectors.Update
+
+	// evaluator rolls collector updates into an overall status level and
+	// notifier reports level changes to a webhook. Both are nil until
+	// collectors start.
+	evaluator *status.Evaluator
+	notifier  *Notifier
+
+	// http serves the optional JSON API; nil when http_addr is unset.
+	http *HTTPServer
+
+	// snoozeUntil silences notifications until this time (SNOOZE). It is
+	// persisted to SnoozeFile in the cache directory.
+	snoozeUntil time.Time
+
+	// weeklyResetAt is the last known seven-day reset time of each Claude
+	// organization that announces its weekly resets.
+	weeklyResetAt map[string]time.Time
+
 	mu sync.Mutex
 }
 
@@ -102,6 +207,12 @@ func (d *Daemon) SetAppConfig(cfg *config.Config) {
 	d.appCfg = cfg
 }
 
+// SetConfigPath sets the file a SIGHUP reloads the application config
+// from. Empty uses the default search paths (see config.Load).
+func (d *Daemon) SetConfigPath(path string) {
+	d.cfgPath = path
+}
+
 // New validates the configuration and returns a Daemon ready to be started.
 // It does not start any background processes.
 func New(cfg Config) (*Daemon, error) {
@@ -121,25 +232,28 @@ func New(cfg Config) (*Daemon, error) {
 		return nil, fmt.Errorf("daemon: BannerCacheFile must not be empty")
 	}
 
+	bc := NewBannerCache(cfg.BannerCacheFile)
+	bc.SetLimit(maxRenderedBanners, nil)
 	return &Daemon{
 		cfg:        cfg,
 		collectors: make(map[string]*CollectorHealth),
-		banner:     NewBannerCache(cfg.BannerCacheFile),
+		banner:     bc,
 	}, nil
 }
 
 // Start acquires the PID lock, starts the IPC server, and enters the main
 // collection loop. It blocks until the context is cancelled or an error occurs.
 func (d *Daemon) Start(ctx context.Context) error {
-	// Ensure directories exist.
+	// Ensure directories exist and only this user can reach the socket
+	// and health file in them.
 	for _, dir := range []string{
 		filepath.Dir(d.cfg.PIDFile),
 		filepath.Dir(d.cfg.HealthFile),
 		filepath.Dir(d.cfg.SocketPath),
 		d.cfg.DataDir,
 	} {
-		if err := os.MkdirAll(dir, 0o755); err != nil {
-			return fmt.Errorf("daemon: create directory %s: %w", dir, err)
+		if err := cache.EnsurePrivateDir(dir); err != nil {
+			return fmt.Errorf("daemon: %w", err)
 		}
 	}
 
@@ -152,6 +266,7 @@ func (d *Daemon) Start(ctx context.Context) error {
 	d.startedAt = time.Now()
 	d.running = true
 	d.mu.Unlock()
+	d.loadSnooze()
 
 	// Start IPC server.
 	d.ipc = NewIPCServer(d.cfg.SocketPath, d)
@@ -170,39 +285,75 @@ func (d *Daemon) Start(ctx context.Context) error {
 	}
 
 	// Start collectors if app config is available.
-	var runner *collectors.Runner
 	if d.appCfg != nil {
-		reg := BuildRegistry(d.appCfg)
-		names := reg.List()
-		if len(names) > 0 {
-			log.Printf("daemon: starting %d collectors: %v", len(names), names)
-			updates := make(chan collectors.Update, collectors.DefaultUpdateBufferSize)
-			runner = collectors.NewRunner(reg, updates)
-			if err := runner.Start(ctx); err != nil {
-				log.Printf("daemon: start collectors: %v", err)
-			} else {
-				cacheDir := d.appCfg.General.CacheDir
-				if cacheDir == "" {
-					cacheDir = d.cfg.DataDir
+		cacheDir := d.appCfg.General.CacheDir
+		if cacheDir == "" {
+			cacheDir = d.cfg.DataDir
+		}
+		if err := cache.EnsurePrivateDir(cacheDir); err != nil {
+			slog.Warn("daemon: cache directory", "err", err)
+		}
+		config.ResolveSecretCommands(d.appCfg)
+		// Before any collector writes, so no in-flight file is removed.
+		compactCache(cacheDir, BuildRegistry(d.appCfg).List())
+		updates := make(chan collectors.Update, collectors.DefaultUpdateBufferSize)
+		d.mu.Lock()
+		d.updates = updates
+		d.cacheDir = cacheDir
+		d.mu.Unlock()
+		d.attachStatus(d.appCfg.Notify, d.appCfg.Status)
+		d.logBannerWarnings(d.appCfg)
+		go ConsumeUpdates(ctx, updates, cacheDir, d)
+		d.startCollectors(ctx, d.appCfg, nil)
+
+		if addr := d.appCfg.General.HTTPAddr; addr != "" {
+			gc := d.appCfg.General
+			srv := NewHTTPServer(addr, d)
+			if gc.HTTPDashboard {
+				if err := srv.EnableDashboard(theme.Current); err != nil {
+					slog.Warn("daemon: web dashboard disabled", "err", err)
 				}
-				go ConsumeUpdates(ctx, updates, cacheDir, d)
 			}
-		} else {
-			log.Printf("daemon: no collectors enabled")
+			var err error
+			if gc.HTTPTLSCert != "" || gc.HTTPTLSKey != "" || gc.HTTPClientCA != "" {
+				err = srv.EnableTLS(gc.HTTPTLSCert, gc.HTTPTLSKey, gc.HTTPClientCA)
+			}
+			if err == nil {
+				err = srv.Start()
+			}
+			if err != nil {
+				slog.Error("daemon: start HTTP API", "err", err)
+			} else {
+				slog.Info("daemon: HTTP API listening", "addr", srv.Addr())
+				d.mu.Lock()
+				d.http = srv
+				d.mu.Unlock()
+			}
 		}
 	}
 
-	// Main loop: write health periodically until context is cancelled.
+	// Main loop: write health periodically and reload the config on
+	// SIGHUP until context is cancelled.
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
 
 	for {
 		select {
 		case <-ctx.Done():
+			d.mu.Lock()
+			runner := d.runner
+			d.mu.Unlock()
 			if runner != nil {
 				runner.Stop()
 			}
 			return d.Stop()
+		case <-hup:
+			if err := d.Reload(ctx); err != nil {
+				slog.Error("daemon: reload config, keeping the running config", "err", err)
+			}
 		case <-ticker.C:
 			_ = d.WriteHealth()
 		}
@@ -225,6 +376,9 @@ func (d *Daemon) Stop() error {
 	if d.ipc != nil {
 		d.ipc.Stop()
 	}
+	if d.http != nil {
+		d.http.Stop()
+	}
 
 	// Remove PID file.
 	if err := ReleasePID(d.cfg.PIDFile); err != nil {
@@ -257,15 +411,24 @@ func (d *Daemon) WriteHealth() error {
 		collectors[k] = *v
 	}
 	startedAt := d.startedAt
+	mode := HealthModeDaemon
+	if d.once {
+		mode = HealthModeCollectOnce
+	}
 	d.mu.Unlock()
 
 	status := &HealthStatus{
+		Mode:       mode,
 		PID:        os.Getpid(),
 		Uptime:     time.Since(startedAt),
 		StartedAt:  startedAt,
 		Collectors: collectors,
 		LastUpdate: time.Now(),
+		Disabled:   d.disabledCollectors(),
+		QuietHours: d.quietActive(time.Now()),
 	}
+	d.applySnooze(status, status.LastUpdate)
+	d.applyCircuits(status, status.LastUpdate)
 
 	return WriteHealthFile(d.cfg.HealthFile, status)
 }
@@ -290,6 +453,27 @@ func (d *Daemon) UpdateCollector(name string, healthy bool, errCount int64) {
 	}
 }
 
+// recordCollector updates the health state for a named collector after a
+// collection, which failed when err is non-nil. Health and the cumulative
+// error count come from the runner's status when collectors are attached,
+// so a watchdog timeout counts as an error without marking the collector
+// unhealthy until it repeats.
+func (d *Daemon) recordCollector(name string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	h := &CollectorHealth{Name: name, Healthy: err == nil, LastRun: time.Now()}
+	if d.registry != nil {
+		if s, ok := d.registry.Status(name); ok {
+			h.Healthy, h.ErrorCount = s.Healthy, s.ErrorCount
+		}
+	}
+	if err != nil {
+		h.LastError = err.Error()
+	}
+	d.collectors[name] = h
+}
+
 // HandleCommand implements the IPCHandler interface, dispatching IPC commands.
 func (d *Daemon) HandleCommand(cmd string, args map[string]string) (string, error) {
 	switch cmd {
@@ -306,6 +490,7 @@ func (d *Daemon) HandleCommand(cmd string, args map[string]string) (string, erro
 			d.mu.Unlock()
 
 			status = &HealthStatus{
+				Mode:       HealthModeDaemon,
 				PID:        os.Getpid(),
 				Uptime:     time.Since(startedAt),
 				StartedAt:  startedAt,
@@ -313,6 +498,11 @@ func (d *Daemon) HandleCommand(cmd string, args map[string]string) (string, erro
 				LastUpdate: time.Now(),
 			}
 		}
+		// The health file may predate the latest SNOOZE or DISABLE.
+		d.applySnooze(status, time.Now())
+		status.Disabled = d.disabledCollectors()
+		status.QuietHours = d.quietActive(time.Now())
+		d.applyCircuits(status, time.Now())
 		return healthStatusToJSON(status)
 
 	case "BANNER":
@@ -325,9 +515,29 @@ func (d *Daemon) HandleCommand(cmd string, args map[string]string) (string, erro
 		}
 		return bannerEntryToJSON(entry)
 
+	case "RENDER":
+		return d.renderCommand(args)
+
 	case "REFRESH":
-		// In a full implementation, this would trigger a collection cycle.
-		return `{"status":"ok","message":"refresh triggered"}`, nil
+		return d.refresh(args["collector"])
+
+	case "STATUS":
+		return d.statusJSON(false)
+
+	case "EXPLAIN":
+		return d.statusJSON(true)
+
+	case "GET":
+		return d.cachedJSON(args["key"])
+
+	case "SNOOZE":
+		return d.snooze(args["duration"])
+
+	case "ENABLE":
+		return d.setCollectorEnabled(args["collector"], true)
+
+	case "DISABLE":
+		return d.setCollectorEnabled(args["collector"], false)
 
 	case "QUIT":
 		go func() {
@@ -341,3 +551,98 @@ func (d *Daemon) HandleCommand(cmd string, args map[string]string) (string, erro
 		return "", fmt.Errorf("unknown command: %s", cmd)
 	}
 }
+
+// cacheKeys are the collector cache entries the GET command may return.
+var cacheKeys = map[string]bool{
+	"claude": true, "billing": true, "tailscale": true, "k8s": true, "sysmetrics": true,
+}
+
+// statusJSON returns the current evaluated status for the STATUS command,
+// or with explain set, the status.Evaluator Explain result for EXPLAIN,
+// which also lists the healthy findings.
+func (d *Daemon) statusJSON(explain bool) (string, error) {
+	d.mu.Lock()
+	ev := d.evaluator
+	d.mu.Unlock()
+	if ev == nil {
+		return "", fmt.Errorf("status not available: collectors not started")
+	}
+	res := ev.Evaluate()
+	if explain {
+		res = ev.Explain()
+	}
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal status: %w", err)
+	}
+	return string(data), nil
+}
+
+// cachedJSON returns the cached report for key as written by storeUpdate.
+// The "infra" key combines tailscale and k8s into one object, with null for
+// whichever is missing.
+func (d *Daemon) cachedJSON(key string) (string, error) {
+	d.mu.Lock()
+	cacheDir := d.cacheDir
+	d.mu.Unlock()
+
+	read := func(k string) (json.RawMessage, error) {
+		if cacheDir == "" {
+			return nil, fmt.Errorf("no cached data for %s", k)
+		}
+		data, err := os.ReadFile(filepath.Join(cacheDir, k+".json"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("no cached data for %s", k)
+			}
+			return nil, err
+		}
+		return data, nil
+	}
+
+	if key == "infra" {
+		ts, tsErr := read("tailscale")
+		ks, ksErr := read("k8s")
+		if tsErr != nil && ksErr != nil {
+			return "", fmt.Errorf("no cached data for infra")
+		}
+		data, err := json.MarshalIndent(map[string]json.RawMessage{"tailscale": orNull(ts), "k8s": orNull(ks)}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshal infra: %w", err)
+		}
+		return string(data), nil
+	}
+
+	if !cacheKeys[key] && !d.isCommandCollector(key) {
+		return "", fmt.Errorf("unknown cache key: %q", key)
+	}
+	data, err := read(key)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// isCommandCollector reports whether name is a configured command
+// collector, whose cache entry GET may also return.
+func (d *Daemon) isCommandCollector(name string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.appCfg == nil {
+		return false
+	}
+	for _, cc := range d.appCfg.Collectors.Commands {
+		if cc.Name == name {
+			return config.CommandNameError(name, nil) == nil
+		}
+	}
+	return false
+}
+
+// orNull substitutes a JSON null for a missing raw message.
+func orNull(m json.RawMessage) json.RawMessage {
+	if m == nil {
+		return json.RawMessage("null")
+	}
+	return m
+}
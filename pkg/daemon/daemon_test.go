@@ -1,15 +1,42 @@
 package daemon
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/banner"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/status"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
 )
 
 // shortSockDir creates a short temporary directory suitable for Unix socket
@@ -482,6 +509,28 @@ func TestBannerCache_MultipleEntries(t *testing.T) {
 	}
 }
 
+func TestBannerCache_EvictsBeyondLimit(t *testing.T) {
+	bc := NewBannerCache(filepath.Join(t.TempDir(), "banner.json"))
+	bc.SetLimit(2, []warmSize{{80, 24, "halfblocks"}})
+	start := time.Now()
+	put := func(width int, age time.Duration) {
+		t.Helper()
+		if err := bc.Put(&BannerEntry{Rendered: "x", Width: width, Height: 24, Protocol: "halfblocks", Timestamp: start.Add(-age)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put(80, time.Hour) // pinned, and the oldest
+	put(90, 3*time.Minute)
+	put(100, 2*time.Minute)
+	put(110, time.Minute)
+
+	for width, want := range map[int]bool{80: true, 90: false, 100: true, 110: true} {
+		if _, ok := bc.Get(width, 24, "halfblocks"); ok != want {
+			t.Errorf("Get(%dx24) cached = %v, want %v", width, ok, want)
+		}
+	}
+}
+
 func TestBannerCache_SurvivesReload(t *testing.T) {
 	dir := t.TempDir()
 	cachePath := filepath.Join(dir, "banner.json")
@@ -710,6 +759,21 @@ func TestDefaultConfig(t *testing.T) {
 	}
 }
 
+func TestDefaultConfig_XDGStateHome(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	if got, want := DefaultConfig().DataDir, filepath.Join(state, "prompt-pulse"); got != want {
+		t.Errorf("DataDir = %q, want %q", got, want)
+	}
+
+	home := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", home)
+	if got, want := DefaultConfig().DataDir, filepath.Join(home, ".local", "state", "prompt-pulse"); got != want {
+		t.Errorf("DataDir without XDG_STATE_HOME = %q, want %q", got, want)
+	}
+}
+
 func TestNew_ValidConfig(t *testing.T) {
 	dir := t.TempDir()
 	cfg := Config{
@@ -973,6 +1037,303 @@ func TestDaemon_HandleCommand_Refresh(t *testing.T) {
 	}
 }
 
+func TestParseIPCCommand_RefreshCollector(t *testing.T) {
+	cmd, args := parseIPCCommand("REFRESH Billing")
+	if cmd != "REFRESH" {
+		t.Errorf("cmd = %q, want REFRESH", cmd)
+	}
+	if args["collector"] != "billing" {
+		t.Errorf("collector = %q, want billing", args["collector"])
+	}
+}
+
+// newRefreshDaemon returns a daemon wired to a registry holding a healthy
+// "billing" mock and a failing "claude" mock.
+func newRefreshDaemon(t *testing.T) (*Daemon, string) {
+	t.Helper()
+	dir := t.TempDir()
+	d, err := New(Config{
+		PIDFile:         filepath.Join(dir, "test.pid"),
+		HealthFile:      filepath.Join(dir, "health.json"),
+		SocketPath:      filepath.Join(dir, "test.sock"),
+		DataDir:         filepath.Join(dir, "data"),
+		BannerCacheFile: filepath.Join(dir, "banner.json"),
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	reg := collectors.NewRegistry()
+	_ = reg.Register(collectors.NewMockCollector("billing", time.Minute,
+		collectors.WithData(map[string]float64{"total_monthly_usd": 12})))
+	_ = reg.Register(collectors.NewMockCollector("claude", time.Minute,
+		collectors.WithError(fmt.Errorf("rate limited"))))
+	d.attachCollectors(reg, collectors.NewRunner(reg, make(chan collectors.Update, 1)), dir)
+	return d, dir
+}
+
+func TestDaemon_RefreshWhileConsumerStores(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	dir := t.TempDir()
+	// A report large enough that writing it takes a while, so the two
+	// writers overlap.
+	report := func() *billing.BillingReport {
+		r := &billing.BillingReport{Timestamp: time.Now()}
+		for i := 0; i < 500; i++ {
+			r.Providers = append(r.Providers, billing.ProviderBilling{
+				Name: fmt.Sprintf("provider-%d", i), Connected: true, MonthToDate: 1,
+			})
+			r.TotalMonthlyUSD++
+		}
+		return r
+	}
+	reg := collectors.NewRegistry()
+	_ = reg.Register(collectors.NewMockCollector("billing", time.Minute,
+		collectors.WithCollectFunc(func(ctx context.Context) (interface{}, error) { return report(), nil })))
+	d.attachCollectors(reg, collectors.NewRunner(reg, nil), dir)
+
+	// The consumer stores a stream of billing updates while REFRESH
+	// stores its own for the same key.
+	updates := make(chan collectors.Update)
+	ctx, cancel := context.WithCancel(context.Background())
+	consumed := make(chan struct{})
+	go func() {
+		ConsumeUpdates(ctx, updates, dir, d)
+		close(consumed)
+	}()
+	go func() {
+		for i := 0; i < 100; i++ {
+			updates <- collectors.Update{Source: "billing", Data: report(), Timestamp: time.Now()}
+		}
+		cancel()
+	}()
+	for ctx.Err() == nil {
+		result, err := d.refreshResult("billing")
+		if err != nil {
+			t.Fatalf("refresh error: %v", err)
+		}
+		if result.Status != "ok" {
+			t.Fatalf("refresh while the consumer stores = %+v, want ok", result)
+		}
+	}
+	<-consumed
+
+	var got billing.BillingReport
+	if data, err := os.ReadFile(filepath.Join(dir, "billing.json")); err != nil {
+		t.Fatal(err)
+	} else if err := json.Unmarshal(data, &got); err != nil {
+		t.Errorf("billing.json is not a whole report: %v", err)
+	}
+	if h, err := billing.LoadSpendHistory(billing.SpendHistoryPath(dir)); err != nil || len(h.Days) != 1 {
+		t.Errorf("spend history = %+v, %v; want one day", h, err)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(tmp) != 0 {
+		t.Errorf("temporary files left behind: %v", tmp)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, ".*.tmp")); len(tmp) != 0 {
+		t.Errorf("temporary files left behind: %v", tmp)
+	}
+}
+
+func TestDaemon_RefreshRecordsWatchdogTimeout(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	reg := collectors.NewRegistry()
+	_ = reg.Register(collectors.NewMockCollector("k8s", 10*time.Millisecond,
+		collectors.WithCollectFunc(func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})))
+	d.attachCollectors(reg, collectors.NewRunner(reg, nil), t.TempDir())
+
+	for i := 1; i <= 2; i++ {
+		if _, err := d.HandleCommand("REFRESH", map[string]string{"collector": "k8s"}); err != nil {
+			t.Fatalf("HandleCommand(REFRESH k8s) error: %v", err)
+		}
+		d.mu.Lock()
+		h := *d.collectors["k8s"]
+		d.mu.Unlock()
+		if h.ErrorCount != int64(i) || !h.Healthy || !strings.Contains(h.LastError, "timed out") {
+			t.Errorf("after %d timeouts health = %+v, want healthy with ErrorCount %d and a timeout error", i, h, i)
+		}
+	}
+}
+
+func TestDaemon_HandleCommand_HealthQuietHours(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	health := func() HealthStatus {
+		t.Helper()
+		resp, err := d.HandleCommand("HEALTH", nil)
+		if err != nil {
+			t.Fatalf("HEALTH error: %v", err)
+		}
+		var hs HealthStatus
+		if err := json.Unmarshal([]byte(resp), &hs); err != nil {
+			t.Fatalf("unmarshal health: %v", err)
+		}
+		return hs
+	}
+
+	if health().QuietHours {
+		t.Error("HEALTH QuietHours = true with none configured")
+	}
+	d.runner.SetQuietHours(&collectors.QuietHours{Start: 0, End: 24 * time.Hour, Factor: 4})
+	if !health().QuietHours {
+		t.Error("HEALTH QuietHours = false inside an all-day window")
+	}
+}
+
+func TestDaemon_HandleCommand_HealthCircuit(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	d.runner.SetCircuitBreaker(&collectors.CircuitBreaker{Threshold: 1, Cooldown: time.Hour})
+	if _, err := d.HandleCommand("REFRESH", nil); err != nil {
+		t.Fatalf("REFRESH error: %v", err)
+	}
+
+	resp, err := d.HandleCommand("HEALTH", nil)
+	if err != nil {
+		t.Fatalf("HEALTH error: %v", err)
+	}
+	var hs HealthStatus
+	if err := json.Unmarshal([]byte(resp), &hs); err != nil {
+		t.Fatalf("unmarshal health: %v", err)
+	}
+	if got := hs.Collectors["claude"].Circuit; got != collectors.CircuitOpen {
+		t.Errorf("claude circuit = %q, want %q", got, collectors.CircuitOpen)
+	}
+	if got := hs.Collectors["billing"].Circuit; got != collectors.CircuitClosed {
+		t.Errorf("billing circuit = %q, want %q", got, collectors.CircuitClosed)
+	}
+}
+
+func TestDaemon_HandleCommand_DisableEnable(t *testing.T) {
+	d, dir := newRefreshDaemon(t)
+	billingMock, _ := d.registry.Get("billing")
+
+	cmd, args := parseIPCCommand("DISABLE Billing")
+	if cmd != "DISABLE" || args["collector"] != "billing" {
+		t.Fatalf("parseIPCCommand(DISABLE Billing) = %q %v", cmd, args)
+	}
+	resp, err := d.HandleCommand(cmd, args)
+	if err != nil {
+		t.Fatalf("DISABLE billing error: %v", err)
+	}
+	var toggled ToggleResult
+	if err := json.Unmarshal([]byte(resp), &toggled); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if len(toggled.Disabled) != 1 || toggled.Disabled[0] != "billing" ||
+		len(toggled.Enabled) != 1 || toggled.Enabled[0] != "claude" {
+		t.Errorf("DISABLE result = %+v", toggled)
+	}
+
+	// A full refresh skips the disabled collector entirely.
+	resp, err = d.HandleCommand("REFRESH", nil)
+	if err != nil {
+		t.Fatalf("REFRESH error: %v", err)
+	}
+	var result RefreshResult
+	if err := json.Unmarshal([]byte(resp), &result); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if len(result.Collectors) != 1 || result.Collectors[0].Name != "claude" {
+		t.Errorf("REFRESH outcomes = %+v, want claude only", result.Collectors)
+	}
+	if n := billingMock.(*collectors.MockCollector).CallCount(); n != 0 {
+		t.Errorf("disabled billing collector ran %d times", n)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "billing.json")); !os.IsNotExist(err) {
+		t.Errorf("disabled collector should not write its cache, stat err = %v", err)
+	}
+	if _, err := d.HandleCommand("REFRESH", map[string]string{"collector": "billing"}); err == nil ||
+		!strings.Contains(err.Error(), "disabled") {
+		t.Errorf("REFRESH billing while disabled error = %v", err)
+	}
+
+	resp, err = d.HandleCommand("HEALTH", nil)
+	if err != nil {
+		t.Fatalf("HEALTH error: %v", err)
+	}
+	var health HealthStatus
+	if err := json.Unmarshal([]byte(resp), &health); err != nil {
+		t.Fatalf("unmarshal health: %v", err)
+	}
+	if len(health.Disabled) != 1 || health.Disabled[0] != "billing" {
+		t.Errorf("HEALTH Disabled = %v, want [billing]", health.Disabled)
+	}
+
+	if _, err := d.HandleCommand("ENABLE", map[string]string{"collector": "billing"}); err != nil {
+		t.Fatalf("ENABLE billing error: %v", err)
+	}
+	if _, err := d.HandleCommand("REFRESH", map[string]string{"collector": "billing"}); err != nil {
+		t.Errorf("REFRESH billing after ENABLE error: %v", err)
+	}
+	resp, _ = d.HandleCommand("HEALTH", nil)
+	if strings.Contains(resp, `"disabled"`) {
+		t.Errorf("HEALTH after ENABLE should not list disabled collectors, got %s", resp)
+	}
+
+	for _, args := range []map[string]string{nil, {"collector": "nope"}} {
+		if _, err := d.HandleCommand("DISABLE", args); err == nil {
+			t.Errorf("DISABLE %v should fail", args)
+		}
+	}
+}
+
+func TestDaemon_HandleCommand_RefreshSingleCollector(t *testing.T) {
+	d, dir := newRefreshDaemon(t)
+
+	resp, err := d.HandleCommand("REFRESH", map[string]string{"collector": "billing"})
+	if err != nil {
+		t.Fatalf("HandleCommand(REFRESH billing) error: %v", err)
+	}
+	var result RefreshResult
+	if err := json.Unmarshal([]byte(resp), &result); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if result.Status != "ok" || len(result.Collectors) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if got := result.Collectors[0]; got.Name != "billing" || !got.Success {
+		t.Errorf("outcome = %+v, want billing success", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "billing.json")); err != nil {
+		t.Errorf("billing cache not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "claude.json")); !os.IsNotExist(err) {
+		t.Error("claude should not run for a single-collector refresh")
+	}
+}
+
+func TestDaemon_HandleCommand_RefreshAllReportsFailures(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+
+	resp, err := d.HandleCommand("REFRESH", map[string]string{})
+	if err != nil {
+		t.Fatalf("HandleCommand(REFRESH) error: %v", err)
+	}
+	var result RefreshResult
+	if err := json.Unmarshal([]byte(resp), &result); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if result.Status != "error" || len(result.Collectors) != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if c := result.Collectors[1]; c.Name != "claude" || c.Success || c.Error == "" {
+		t.Errorf("claude outcome = %+v, want failure with error", c)
+	}
+	if d.collectors["claude"].Healthy {
+		t.Error("failed refresh should mark collector unhealthy")
+	}
+}
+
+func TestDaemon_HandleCommand_RefreshUnknownCollector(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+
+	if _, err := d.HandleCommand("REFRESH", map[string]string{"collector": "aws"}); err == nil {
+		t.Fatal("REFRESH of unknown collector should return error")
+	}
+}
+
 func TestDaemon_HandleCommand_Unknown(t *testing.T) {
 	dir := t.TempDir()
 	cfg := Config{
@@ -1016,6 +1377,72 @@ func TestDaemon_HandleCommand_Banner_NoCachedBanner(t *testing.T) {
 	}
 }
 
+func TestDaemon_HandleCommand_Render(t *testing.T) {
+	dir := t.TempDir()
+	d, err := New(Config{
+		PIDFile:         filepath.Join(dir, "test.pid"),
+		HealthFile:      filepath.Join(dir, "health.json"),
+		SocketPath:      filepath.Join(dir, "test.sock"),
+		DataDir:         filepath.Join(dir, "data"),
+		BannerCacheFile: filepath.Join(dir, "banner.json"),
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if _, err := d.HandleCommand("RENDER", map[string]string{"width": "100", "height": "30"}); err == nil {
+		t.Error("RENDER without a BannerDataFunc should return an error")
+	}
+
+	d.SetAppConfig(config.DefaultConfig())
+	d.SetBannerData(func(_ *config.Config, width, height int) banner.BannerData {
+		return banner.BannerData{Widgets: []banner.WidgetData{
+			{ID: "claude", Title: "Claude", Content: "claude-body"},
+			{ID: "billing", Title: "Billing", Content: "billing-body"},
+		}}
+	})
+
+	// An uncached size renders on the miss and is cached for BANNER.
+	_, args := parseIPCCommand("RENDER 100 30 kitty")
+	resp, err := d.HandleCommand("RENDER", args)
+	if err != nil {
+		t.Fatalf("RENDER error: %v", err)
+	}
+	var entry BannerEntry
+	if err := json.Unmarshal([]byte(resp), &entry); err != nil {
+		t.Fatalf("RENDER response is not a banner entry: %v", err)
+	}
+	if entry.Width != 100 || entry.Height != 30 || entry.Protocol != "kitty" ||
+		!strings.Contains(entry.Rendered, "claude-body") || !strings.Contains(entry.Rendered, "billing-body") {
+		t.Errorf("RENDER entry = %+v", entry)
+	}
+	if _, err := d.HandleCommand("BANNER", args); err != nil {
+		t.Errorf("BANNER after RENDER should hit the cache: %v", err)
+	}
+
+	// Sections limit the banner and are cached separately.
+	_, args = parseIPCCommand("RENDER 100 30 kitty billing")
+	resp, err = d.HandleCommand("RENDER", args)
+	if err != nil {
+		t.Fatalf("RENDER billing error: %v", err)
+	}
+	entry = BannerEntry{}
+	_ = json.Unmarshal([]byte(resp), &entry)
+	if strings.Contains(entry.Rendered, "claude-body") || !strings.Contains(entry.Rendered, "billing-body") {
+		t.Errorf("RENDER billing rendered %q", entry.Rendered)
+	}
+	if full, _ := d.banner.Get(100, 30, "kitty"); full == nil || !strings.Contains(full.Rendered, "claude-body") {
+		t.Error("a sectioned RENDER should not replace the full banner")
+	}
+
+	for _, line := range []string{"RENDER 0 30", "RENDER 100 x", "RENDER 5000 30", "RENDER 100 30 kitty nope",
+		"RENDER 100 30 bogus", "RENDER 100 30 kitty claude nope"} {
+		_, args := parseIPCCommand(line)
+		if _, err := d.HandleCommand("RENDER", args); err == nil {
+			t.Errorf("%q should return an error", line)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Integration: IPC with Daemon handler
 // ---------------------------------------------------------------------------
@@ -1112,6 +1539,32 @@ func TestBuildRegistry_AllEnabled(t *testing.T) {
 	}
 }
 
+func TestBuildRegistry_CommandCollectors(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Collectors.SysMetrics.Enabled = false
+	cfg.Collectors.Tailscale.Enabled = false
+	cfg.Collectors.Claude.Enabled = false
+	cfg.Collectors.Commands = []config.CommandCollectorConfig{
+		{Name: "buildfarm", Exec: "true"},
+		{Name: "billing", Exec: "true"},
+		{Name: "buildfarm", Exec: "true"},
+	}
+
+	reg := BuildRegistry(cfg)
+	if names := reg.List(); len(names) != 1 || names[0] != "buildfarm" {
+		t.Errorf("registered %v, want only buildfarm (reserved and duplicate names skipped)", names)
+	}
+
+	d, dir := newRefreshDaemon(t)
+	d.appCfg = cfg
+	if err := os.WriteFile(filepath.Join(dir, "buildfarm.json"), []byte(`{"data":{"queue":4}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := d.HandleCommand("GET", map[string]string{"key": "buildfarm"}); err != nil || !strings.Contains(resp, `"queue":4`) {
+		t.Errorf("GET buildfarm = %q, %v", resp, err)
+	}
+}
+
 func TestBuildRegistry_DisabledCollectors(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.Collectors.SysMetrics.Enabled = true
@@ -1141,3 +1594,1027 @@ func TestBuildRegistry_NoneEnabled(t *testing.T) {
 		t.Errorf("BuildRegistry(none enabled) registered %d collectors, want 0: %v", len(names), names)
 	}
 }
+
+// notifyRecorder is a webhook endpoint that records every posted body.
+type notifyRecorder struct {
+	mu     sync.Mutex
+	bodies []map[string]string
+}
+
+func (r *notifyRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var body map[string]string
+	_ = json.NewDecoder(req.Body).Decode(&body)
+	r.mu.Lock()
+	r.bodies = append(r.bodies, body)
+	r.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *notifyRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+func newTestNotifier(t *testing.T, cfg config.NotifyConfig) (*Notifier, *notifyRecorder, *time.Time) {
+	t.Helper()
+	rec := &notifyRecorder{}
+	srv := httptest.NewServer(rec)
+	t.Cleanup(srv.Close)
+	cfg.WebhookURL = srv.URL
+	n, err := NewNotifier(cfg)
+	if err != nil {
+		t.Fatalf("NewNotifier() error: %v", err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	n.nowFunc = func() time.Time { return now }
+	n.host = "box"
+	return n, rec, &now
+}
+
+func testResult(l status.Level, msg string) status.Result {
+	if l == status.LevelHealthy {
+		return status.Result{}
+	}
+	return status.Result{Level: l, Reasons: []status.Reason{{Subsystem: "claude", Level: l, Message: msg}}}
+}
+
+func TestNotifier_NoWebhookIsNoop(t *testing.T) {
+	n, err := NewNotifier(config.NotifyConfig{})
+	if err != nil || n != nil {
+		t.Fatalf("NewNotifier(empty) = %v, %v; want nil, nil", n, err)
+	}
+	if err := n.Observe(context.Background(), testResult(status.LevelCritical, "x")); err != nil {
+		t.Errorf("nil notifier Observe() error: %v", err)
+	}
+}
+
+func TestNotifier_TransitionsAndMessage(t *testing.T) {
+	n, rec, _ := newTestNotifier(t, config.NotifyConfig{NotifyRecovery: true})
+	ctx := context.Background()
+
+	_ = n.Observe(ctx, testResult(status.LevelHealthy, ""))
+	if rec.count() != 0 {
+		t.Fatal("healthy at startup should not notify")
+	}
+	if err := n.Observe(ctx, testResult(status.LevelCritical, "Claude personal at 96%")); err != nil {
+		t.Fatalf("Observe() error: %v", err)
+	}
+	_ = n.Observe(ctx, testResult(status.LevelCritical, "Claude personal at 97%"))
+	if rec.count() != 1 {
+		t.Fatalf("expected 1 notification, got %d", rec.count())
+	}
+	want := "🔴 prompt-pulse on box: healthy → critical — Claude personal at 96%"
+	if got := rec.bodies[0]["text"]; got != want {
+		t.Errorf("slack text = %q, want %q", got, want)
+	}
+}
+
+func TestNotifier_CooldownDebouncesFlapping(t *testing.T) {
+	n, rec, now := newTestNotifier(t, config.NotifyConfig{
+		Cooldown:       config.Duration{Duration: 10 * time.Minute},
+		NotifyRecovery: true,
+	})
+	ctx := context.Background()
+
+	_ = n.Observe(ctx, testResult(status.LevelCritical, "civo-prod offline"))
+	_ = n.Observe(ctx, testResult(status.LevelHealthy, ""))
+	_ = n.Observe(ctx, testResult(status.LevelCritical, "civo-prod offline"))
+	if rec.count() != 1 {
+		t.Fatalf("flapping within cooldown should send 1 message, got %d", rec.count())
+	}
+
+	*now = now.Add(11 * time.Minute)
+	_ = n.Observe(ctx, testResult(status.LevelHealthy, ""))
+	if rec.count() != 2 {
+		t.Fatalf("recovery after cooldown should notify, got %d messages", rec.count())
+	}
+	if got := rec.bodies[1]["text"]; !strings.HasPrefix(got, "✅") {
+		t.Errorf("recovery message = %q", got)
+	}
+}
+
+func TestNotifier_MinLevelCritical(t *testing.T) {
+	n, rec, _ := newTestNotifier(t, config.NotifyConfig{MinLevel: "critical", Kind: "discord"})
+	ctx := context.Background()
+
+	_ = n.Observe(ctx, testResult(status.LevelWarning, "Claude personal at 75%"))
+	if rec.count() != 0 {
+		t.Fatal("warning should not notify a critical-only channel")
+	}
+	_ = n.Observe(ctx, testResult(status.LevelCritical, "Claude personal at 95%"))
+	if rec.count() != 1 || rec.bodies[0]["content"] == "" {
+		t.Fatalf("critical should post a discord payload, got %v", rec.bodies)
+	}
+}
+
+func TestNewNotifier_Validation(t *testing.T) {
+	if _, err := NewNotifier(config.NotifyConfig{WebhookURL: "https://hooks.example.com/x", Kind: "teams"}); err == nil {
+		t.Error("unknown kind should fail")
+	}
+	if _, err := NewNotifier(config.NotifyConfig{WebhookURL: "https://hooks.example.com/x", MinLevel: "healthy"}); err == nil {
+		t.Error("min_level healthy should fail")
+	}
+	n, err := NewNotifier(config.NotifyConfig{WebhookURL: "https://discord.com/api/webhooks/1/abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.kind != NotifyDiscord {
+		t.Errorf("kind = %q, want discord (detected from host)", n.kind)
+	}
+}
+
+func TestHTTPServer_Endpoints(t *testing.T) {
+	d, dir := newRefreshDaemon(t)
+	d.attachStatus(config.NotifyConfig{}, config.StatusConfig{})
+	if err := os.WriteFile(filepath.Join(dir, "claude.json"), []byte(`{"total_cost_usd":9.5}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tailscale.json"), []byte(`{"online_peers":3}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewHTTPServer("127.0.0.1:0", d)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer srv.Stop()
+	base := "http://" + srv.Addr()
+
+	get := func(method, path string) (int, map[string]interface{}) {
+		t.Helper()
+		req, _ := http.NewRequest(method, base+path, nil)
+		if method == http.MethodPost {
+			req.Header.Set(HTTPActionHeader, "1")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s %s: decode: %v", method, path, err)
+		}
+		return resp.StatusCode, body
+	}
+
+	if code, body := get(http.MethodGet, "/claude"); code != http.StatusOK || body["total_cost_usd"] != 9.5 {
+		t.Errorf("GET /claude = %d %v", code, body)
+	}
+	if code, body := get(http.MethodGet, "/infra"); code != http.StatusOK || body["k8s"] != nil || body["tailscale"] == nil {
+		t.Errorf("GET /infra = %d %v, want tailscale with null k8s", code, body)
+	}
+	if code, body := get(http.MethodGet, "/billing"); code != http.StatusServiceUnavailable || body["error"] == nil {
+		t.Errorf("GET /billing without cache = %d %v, want 503 error", code, body)
+	}
+	if code, body := get(http.MethodGet, "/status"); code != http.StatusOK || body["level"] != "healthy" {
+		t.Errorf("GET /status = %d %v", code, body)
+	}
+	if code, _ := get(http.MethodGet, "/refresh"); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /refresh = %d, want 405", code)
+	}
+	if code, body := get(http.MethodPost, "/refresh?collector=billing"); code != http.StatusOK || body["status"] != "ok" {
+		t.Errorf("POST /refresh = %d %v", code, body)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "billing.json")); err != nil {
+		t.Errorf("refresh should have written billing.json: %v", err)
+	}
+	if code, _ := get(http.MethodPost, "/refresh?collector=nope"); code != http.StatusBadRequest {
+		t.Errorf("POST /refresh unknown = %d, want 400", code)
+	}
+	if code, body := get(http.MethodPost, "/snooze?duration=30m"); code != http.StatusOK || body["snoozed_until"] == nil {
+		t.Errorf("POST /snooze = %d %v", code, body)
+	}
+	if code, _ := get(http.MethodPost, "/snooze"); code != http.StatusBadRequest {
+		t.Errorf("POST /snooze without duration = %d, want 400", code)
+	}
+}
+
+func TestHTTPServer_RejectsForeignHostAndOrigin(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	srv := NewHTTPServer("127.0.0.1:0", d)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer srv.Stop()
+	base := "http://" + srv.Addr()
+
+	do := func(method, path, host string, header map[string]string) int {
+		t.Helper()
+		req, _ := http.NewRequest(method, base+path, nil)
+		if host != "" {
+			req.Host = host
+		}
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	action := map[string]string{HTTPActionHeader: "1"}
+
+	// A DNS-rebound name resolves here but is not ours.
+	if code := do(http.MethodGet, "/status", "evil.example:80", nil); code != http.StatusForbidden {
+		t.Errorf("GET with a foreign Host = %d, want 403", code)
+	}
+	if code := do(http.MethodGet, "/status", "localhost:9090", nil); code == http.StatusForbidden {
+		t.Error("GET with Host localhost was rejected")
+	}
+
+	// A cross-site page cannot trigger actions.
+	foreign := map[string]string{HTTPActionHeader: "1", "Origin": "https://evil.example"}
+	if code := do(http.MethodPost, "/snooze?duration=1h", "", foreign); code != http.StatusForbidden {
+		t.Errorf("POST with a foreign Origin = %d, want 403", code)
+	}
+	if code := do(http.MethodPost, "/snooze?duration=1h", "", nil); code != http.StatusForbidden {
+		t.Errorf("POST without %s = %d, want 403", HTTPActionHeader, code)
+	}
+	local := map[string]string{HTTPActionHeader: "1", "Origin": "http://127.0.0.1:9090"}
+	if code := do(http.MethodPost, "/snooze?duration=1h", "", local); code != http.StatusOK {
+		t.Errorf("POST with a loopback Origin = %d, want 200", code)
+	}
+	if code := do(http.MethodPost, "/snooze?duration=off", "", action); code != http.StatusOK {
+		t.Errorf("POST without an Origin = %d, want 200", code)
+	}
+
+	// A server bound to a specific address also answers to it.
+	if got := allowedHosts("100.64.0.1:9090"); !got["100.64.0.1"] || len(got) != 1 {
+		t.Errorf("allowedHosts(100.64.0.1:9090) = %v", got)
+	}
+}
+
+func TestNewHTTPServer_DefaultsToLoopback(t *testing.T) {
+	if got := NewHTTPServer(":9090", nil).addr; got != "127.0.0.1:9090" {
+		t.Errorf("addr = %q, want 127.0.0.1:9090", got)
+	}
+	if got := NewHTTPServer("0.0.0.0:9090", nil).addr; got != "0.0.0.0:9090" {
+		t.Errorf("explicit host should be kept, got %q", got)
+	}
+}
+
+// testCert issues a certificate for 127.0.0.1 signed by parent, or
+// self-signed as a CA when parent is nil, and writes it and its key as PEM
+// to dir/name.crt and dir/name.key.
+func testCert(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid = true, true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ext, block := range map[string]*pem.Block{
+		".crt": {Type: "CERTIFICATE", Bytes: der},
+		".key": {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name+ext), pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cert, key
+}
+
+func TestHTTPServer_MutualTLS(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	d.attachStatus(config.NotifyConfig{}, config.StatusConfig{})
+	dir := t.TempDir()
+	ca, caKey := testCert(t, dir, "ca", nil, nil)
+	testCert(t, dir, "server", ca, caKey)
+	testCert(t, dir, "client", ca, caKey)
+	testCert(t, dir, "rogue", nil, nil)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	srv := NewHTTPServer("127.0.0.1:0", d)
+	if err := srv.EnableTLS(path("server.crt"), path("server.key"), path("ca.crt")); err != nil {
+		t.Fatalf("EnableTLS() error: %v", err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer srv.Stop()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	get := func(certs ...tls.Certificate) (*http.Response, error) {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs},
+		}}
+		defer client.CloseIdleConnections()
+		return client.Get("https://" + srv.Addr() + "/status")
+	}
+
+	if resp, err := get(); err == nil {
+		resp.Body.Close()
+		t.Error("GET /status without a client certificate should be rejected")
+	}
+	rogue, err := tls.LoadX509KeyPair(path("rogue.crt"), path("rogue.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := get(rogue); err == nil {
+		resp.Body.Close()
+		t.Error("GET /status with a certificate from another CA should be rejected")
+	}
+	client, err := tls.LoadX509KeyPair(path("client.crt"), path("client.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := get(client)
+	if err != nil {
+		t.Fatalf("GET /status with a client certificate: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /status = %d, want 200", resp.StatusCode)
+	}
+	if resp, err := http.Get("http://" + srv.Addr() + "/status"); err == nil {
+		if resp.StatusCode == http.StatusOK {
+			t.Error("plain HTTP request should not be served")
+		}
+		resp.Body.Close()
+	}
+}
+
+func TestHTTPServer_EnableTLSErrors(t *testing.T) {
+	dir := t.TempDir()
+	testCert(t, dir, "server", nil, nil)
+	testCert(t, dir, "other", nil, nil)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name, cert, key, ca, want string
+	}{
+		{"missing key", path("server.crt"), "", "", "both a certificate and a key"},
+		{"missing file", path("nope.crt"), path("server.key"), "", "nope.crt"},
+		{"mismatched key", path("server.crt"), path("other.key"), "", "private key does not match"},
+		{"missing client CA", path("server.crt"), path("server.key"), path("nope.crt"), "client CA"},
+		{"client CA not PEM", path("server.crt"), path("server.key"), path("server.key"), "no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewHTTPServer("127.0.0.1:0", nil).EnableTLS(tt.cert, tt.key, tt.ca)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("EnableTLS() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPServer_Dashboard(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	if err := NewHTTPServer("0.0.0.0:0", d).EnableDashboard(theme.Get("nord")); err == nil {
+		t.Error("EnableDashboard() on a non-loopback address should fail")
+	}
+
+	srv := NewHTTPServer("127.0.0.1:0", d)
+	if err := srv.EnableDashboard(theme.Get("nord")); err != nil {
+		t.Fatalf("EnableDashboard() error: %v", err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer srv.Stop()
+	base := "http://" + srv.Addr()
+
+	fetch := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(base + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := fetch("/"); code != http.StatusOK || !strings.Contains(body, "dashboard.js") {
+		t.Errorf("GET / = %d, want the dashboard page", code)
+	}
+	if code, _ := fetch("/dashboard.js"); code != http.StatusOK {
+		t.Errorf("GET /dashboard.js = %d, want 200", code)
+	}
+	want := "--bg: " + theme.Get("nord").Background + ";"
+	if code, body := fetch("/theme.css"); code != http.StatusOK || !strings.Contains(body, want) {
+		t.Errorf("GET /theme.css = %d %q, want %q", code, body, want)
+	}
+	if code, body := fetch("/status"); code != http.StatusServiceUnavailable || !strings.Contains(body, "error") {
+		t.Errorf("GET /status = %d %q, want the JSON API alongside the dashboard", code, body)
+	}
+	if code, _ := fetch("/nope"); code != http.StatusNotFound {
+		t.Errorf("GET /nope = %d, want 404", code)
+	}
+}
+
+func TestDaemon_HandleCommand_GetRejectsUnknownKey(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	if _, err := d.HandleCommand("GET", map[string]string{"key": "../etc/passwd"}); err == nil {
+		t.Error("expected error for an unknown cache key")
+	}
+	cmd, args := parseIPCCommand("GET Claude")
+	if cmd != "GET" || args["key"] != "claude" {
+		t.Errorf("parseIPCCommand(GET Claude) = %q %v", cmd, args)
+	}
+}
+
+func TestDaemon_HandleCommand_SnoozePersistsAndReportsInHealth(t *testing.T) {
+	d, dir := newRefreshDaemon(t)
+	d.startedAt = time.Now()
+
+	cmd, args := parseIPCCommand("SNOOZE 2H")
+	if cmd != "SNOOZE" || args["duration"] != "2h" {
+		t.Fatalf("parseIPCCommand(SNOOZE 2H) = %q %v", cmd, args)
+	}
+	resp, err := d.HandleCommand(cmd, args)
+	if err != nil {
+		t.Fatalf("HandleCommand(SNOOZE) error: %v", err)
+	}
+	var res SnoozeResult
+	if err := json.Unmarshal([]byte(resp), &res); err != nil || res.Until == nil {
+		t.Fatalf("SNOOZE response = %q (%v), want an end time", resp, err)
+	}
+	if left := time.Until(*res.Until); left < 119*time.Minute || left > 2*time.Hour {
+		t.Errorf("snoozed until %v, want ~2h from now", res.Until)
+	}
+
+	if got := ReadSnooze(dir); !got.Equal(*res.Until) {
+		t.Errorf("ReadSnooze() = %v, want %v", got, res.Until)
+	}
+	health, err := ReadHealthFile(d.cfg.HealthFile)
+	if err != nil || !health.Snoozed || health.SnoozedUntil == nil || !health.SnoozedUntil.Equal(*res.Until) {
+		t.Errorf("health file = %+v (%v), want snoozed until %v", health, err, res.Until)
+	}
+
+	// A fresh daemon on the same cache directory picks the snooze back up.
+	restarted, _ := newRefreshDaemon(t)
+	restarted.attachCollectors(collectors.NewRegistry(), nil, dir)
+	restarted.loadSnooze()
+	if _, ok := restarted.snoozedUntil(time.Now()); !ok {
+		t.Error("snooze should survive a restart")
+	}
+
+	if _, err := d.HandleCommand("SNOOZE", map[string]string{"duration": "off"}); err != nil {
+		t.Fatalf("SNOOZE off error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, SnoozeFile)); !os.IsNotExist(err) {
+		t.Errorf("SNOOZE off should remove %s, stat err = %v", SnoozeFile, err)
+	}
+	resp, _ = d.HandleCommand("HEALTH", nil)
+	var hs HealthStatus
+	if err := json.Unmarshal([]byte(resp), &hs); err != nil || hs.Snoozed || hs.SnoozedUntil != nil {
+		t.Errorf("HEALTH after SNOOZE off = %q", resp)
+	}
+
+	for _, bad := range []string{"", "soon", "-1h"} {
+		if _, err := d.HandleCommand("SNOOZE", map[string]string{"duration": bad}); err == nil {
+			t.Errorf("SNOOZE %q: expected error", bad)
+		}
+	}
+}
+
+func TestDaemon_SnoozeSuppressesNotifications(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	n, rec, _ := newTestNotifier(t, config.NotifyConfig{})
+	d.evaluator, d.notifier = status.NewEvaluator(status.DefaultEvaluatorConfig()), n
+	ctx := context.Background()
+	critical := collectors.Update{Source: "sysmetrics", Data: &sysmetrics.Metrics{
+		Memory: sysmetrics.MemoryMetrics{UsedPercent: 97},
+	}}
+
+	if _, err := d.HandleCommand("SNOOZE", map[string]string{"duration": "1h"}); err != nil {
+		t.Fatal(err)
+	}
+	d.observeStatus(ctx, critical)
+	if rec.count() != 0 {
+		t.Fatalf("snoozed daemon sent %d notifications", rec.count())
+	}
+
+	// Once the snooze has lapsed, the still-critical status is reported.
+	d.mu.Lock()
+	d.snoozeUntil = time.Now().Add(-time.Second)
+	d.mu.Unlock()
+	d.observeStatus(ctx, critical)
+	if rec.count() != 1 {
+		t.Errorf("expected 1 notification after the snooze ended, got %d", rec.count())
+	}
+}
+
+func TestDaemon_Explain(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	if _, err := d.HandleCommand("EXPLAIN", nil); err == nil {
+		t.Error("EXPLAIN before collectors start: expected error")
+	}
+
+	d.evaluator = status.NewEvaluator(status.DefaultEvaluatorConfig())
+	d.evaluator.Observe("sysmetrics", &sysmetrics.Metrics{Memory: sysmetrics.MemoryMetrics{UsedPercent: 91}})
+	d.evaluator.Observe("billing", &billing.BillingReport{TotalMonthlyUSD: 62, BudgetUSD: 100, BudgetPercent: 62})
+	resp, err := d.HandleCommand("EXPLAIN", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res status.Result
+	if err := json.Unmarshal([]byte(resp), &res); err != nil {
+		t.Fatalf("EXPLAIN response %q: %v", resp, err)
+	}
+	want := []status.Reason{
+		{Subsystem: "sysmetrics", Level: status.LevelWarning, Message: "memory at 91%"},
+		{Subsystem: "billing", Level: status.LevelHealthy, Message: "cloud spend at 62% of budget"},
+		{Subsystem: "sysmetrics", Level: status.LevelHealthy, Message: "CPU at 0%"},
+	}
+	if res.Level != status.LevelWarning || len(res.Reasons) != 3 || res.Reasons[0] != want[0] || res.Reasons[1] != want[1] || res.Reasons[2] != want[2] {
+		t.Errorf("EXPLAIN = %+v, want warning with %+v", res, want)
+	}
+
+	// STATUS still lists only the problems.
+	resp, err = d.HandleCommand("STATUS", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(resp, "cloud spend") {
+		t.Errorf("STATUS should omit healthy findings: %s", resp)
+	}
+}
+
+func TestDaemon_CollectOnce(t *testing.T) {
+	dir := t.TempDir()
+	d, err := New(Config{
+		PIDFile:         filepath.Join(dir, "test.pid"),
+		HealthFile:      filepath.Join(dir, "health.json"),
+		SocketPath:      filepath.Join(dir, "test.sock"),
+		DataDir:         filepath.Join(dir, "data"),
+		BannerCacheFile: filepath.Join(dir, "banner.json"),
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	reg := collectors.NewRegistry()
+	for _, name := range []string{"billing", "claude", "tailscale"} {
+		_ = reg.Register(collectors.NewMockCollector(name, time.Hour,
+			collectors.WithData(map[string]string{"name": name})))
+	}
+
+	result, err := d.collectOnce(context.Background(), reg)
+	if err != nil {
+		t.Fatalf("collectOnce() error: %v", err)
+	}
+	if result.Status != "ok" || len(result.Collectors) != 3 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	for _, name := range reg.List() {
+		info, err := os.Stat(filepath.Join(dir, "data", name+".json"))
+		if err != nil {
+			t.Errorf("cache key %s not written: %v", name, err)
+		} else if info.Mode().Perm() != cache.FileMode {
+			t.Errorf("cache key %s mode = %o, want %o", name, info.Mode().Perm(), cache.FileMode)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dir, "data")); err != nil {
+		t.Errorf("data directory: %v", err)
+	} else if info.Mode().Perm() != cache.DirMode {
+		t.Errorf("data directory mode = %o, want %o", info.Mode().Perm(), cache.DirMode)
+	}
+	if health, err := ReadHealthFile(filepath.Join(dir, "health.json")); err != nil {
+		t.Errorf("health file not written: %v", err)
+	} else if health.Mode != HealthModeCollectOnce {
+		t.Errorf("health mode = %q, want %q", health.Mode, HealthModeCollectOnce)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "test.pid")); !os.IsNotExist(err) {
+		t.Errorf("PID file should be released after collectOnce, stat err = %v", err)
+	}
+}
+
+func TestDaemon_CollectOnce_PrivateRunDirectory(t *testing.T) {
+	// A runtime directory left at 0755 by an older release is narrowed, so
+	// other users cannot reach the PID and health files.
+	run := filepath.Join(t.TempDir(), "run")
+	if err := os.Mkdir(run, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	d, err := New(Config{
+		PIDFile:         filepath.Join(run, "test.pid"),
+		HealthFile:      filepath.Join(run, "health.json"),
+		SocketPath:      filepath.Join(run, "test.sock"),
+		DataDir:         filepath.Join(t.TempDir(), "data"),
+		BannerCacheFile: filepath.Join(run, "banner.json"),
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if _, err := d.collectOnce(context.Background(), collectors.NewRegistry()); err != nil {
+		t.Fatalf("collectOnce() error: %v", err)
+	}
+	if info, err := os.Stat(run); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != cache.DirMode {
+		t.Errorf("run directory mode = %o, want %o", info.Mode().Perm(), cache.DirMode)
+	}
+}
+
+func TestDaemon_CollectOnce_SkipsWhenDaemonRunning(t *testing.T) {
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "test.pid")
+	// The test's parent process stands in for a running daemon.
+	if err := os.WriteFile(pidFile, []byte(fmt.Sprintf("%d\n", os.Getppid())), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := New(Config{
+		PIDFile:         pidFile,
+		HealthFile:      filepath.Join(dir, "health.json"),
+		SocketPath:      filepath.Join(dir, "test.sock"),
+		DataDir:         filepath.Join(dir, "data"),
+		BannerCacheFile: filepath.Join(dir, "banner.json"),
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	reg := collectors.NewRegistry()
+	_ = reg.Register(collectors.NewMockCollector("billing", time.Hour,
+		collectors.WithData(map[string]float64{"total_monthly_usd": 12})))
+
+	if _, err := d.collectOnce(context.Background(), reg); !errors.Is(err, ErrAlreadyRunning) {
+		t.Fatalf("collectOnce() error = %v, want ErrAlreadyRunning", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data", "billing.json")); !os.IsNotExist(err) {
+		t.Errorf("collectOnce should not write the cache while a daemon runs, stat err = %v", err)
+	}
+	if _, err := os.Stat(pidFile); err != nil {
+		t.Errorf("running daemon's PID file should be left alone: %v", err)
+	}
+}
+
+func TestCollectLive_OnlyRequestedKeys(t *testing.T) {
+	dir := t.TempDir()
+	reg := collectors.NewRegistry()
+	for _, name := range []string{"billing", "claude"} {
+		_ = reg.Register(collectors.NewMockCollector(name, time.Hour,
+			collectors.WithData(map[string]string{"name": name})))
+	}
+	_ = reg.Register(collectors.NewMockCollector("tailscale", time.Hour,
+		collectors.WithCollectFunc(func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	got := collectLive(ctx, reg, dir, []string{"tailscale", "claude", "k8s"})
+	if len(got) != 1 || got[0] != "claude" {
+		t.Fatalf("collectLive() = %v, want [claude]", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "claude.json")); err != nil {
+		t.Errorf("claude cache not written: %v", err)
+	}
+	for _, name := range []string{"billing", "tailscale"} {
+		if _, err := os.Stat(filepath.Join(dir, name+".json")); !os.IsNotExist(err) {
+			t.Errorf("%s cache should not be written, stat err = %v", name, err)
+		}
+	}
+}
+
+func TestDaemon_SIGHUPReloadsCollectors(t *testing.T) {
+	dir := shortSockDir(t)
+	cfgPath := filepath.Join(dir, "config.toml")
+	base := fmt.Sprintf(`
+[general]
+cache_dir = %q
+
+[collectors.sysmetrics]
+enabled = false
+
+[collectors.tailscale]
+enabled = false
+
+[collectors.claude]
+enabled = false
+`, filepath.Join(dir, "cache"))
+	if err := os.WriteFile(cfgPath, []byte(base), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadFromFile(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error: %v", err)
+	}
+
+	d, err := New(Config{
+		PIDFile:         filepath.Join(dir, "d.pid"),
+		HealthFile:      filepath.Join(dir, "health.json"),
+		SocketPath:      filepath.Join(dir, "d.sock"),
+		DataDir:         filepath.Join(dir, "data"),
+		BannerCacheFile: filepath.Join(dir, "banner.json"),
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	d.SetAppConfig(cfg)
+	d.SetConfigPath(cfgPath)
+	var warned atomic.Int32
+	d.SetBannerWarnings(func(*config.Config) []string {
+		warned.Add(1)
+		return []string{"display.glyphs: unknown glyph set"}
+	})
+
+	// Catch SIGHUP here too, so one that arrives before the daemon has
+	// registered its handler does not kill the test binary.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Start(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	registered := func(name string) bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.registry == nil {
+			return false
+		}
+		_, ok := d.registry.Get(name)
+		return ok
+	}
+
+	enabled := base + `
+[[collectors.command]]
+name = "echoer"
+exec = "echo"
+args = ['{"data": {"state": "ok"}}']
+interval = "1h"
+`
+	if err := os.WriteFile(cfgPath, []byte(enabled), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !registered("echoer") {
+		if time.Now().After(deadline) {
+			t.Fatal("echoer was not registered after SIGHUP")
+		}
+		_ = syscall.Kill(os.Getpid(), syscall.SIGHUP)
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// The new collector is scheduled: it runs straight away.
+	for {
+		d.mu.Lock()
+		_, ran := d.collectors["echoer"]
+		d.mu.Unlock()
+		if ran {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("echoer never ran after reload")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A config that does not parse is rejected and the running one kept.
+	if err := os.WriteFile(cfgPath, []byte("[general\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	warnedBefore := warned.Load()
+	if err := d.Reload(ctx); err == nil {
+		t.Error("Reload() with an invalid config should fail")
+	}
+	if !registered("echoer") {
+		t.Error("invalid config should keep the running collectors")
+	}
+
+	// Removing the collector from the config stops it.
+	if err := os.WriteFile(cfgPath, []byte(base), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error: %v", err)
+	}
+	if registered("echoer") {
+		t.Error("echoer should stop once removed from the config")
+	}
+	// Banner warnings are reported once per loaded config: at start, on
+	// every reload that applied, and not for the rejected one.
+	if got := warned.Load(); warnedBefore < 2 || got != warnedBefore+1 {
+		t.Errorf("banner warnings reported %d times before the last reloads and %d after, want at least 2 and one more", warnedBefore, got)
+	}
+}
+
+func weeklyReport(resets map[string]time.Time) *claude.UsageReport {
+	r := &claude.UsageReport{}
+	for name, at := range resets {
+		r.Orgs = append(r.Orgs, claude.OrgUsage{Name: name, Connected: true,
+			SevenDay: &claude.UsageWindow{Utilization: 40, ResetsAt: at}})
+	}
+	return r
+}
+
+func TestDaemon_WeeklyResets(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	cfg := config.DefaultConfig()
+	cfg.Collectors.Claude.Accounts = []config.ClaudeAccountConfig{
+		{Name: "personal", Type: "org", NotifyWeeklyReset: true},
+		{Name: "team", Type: "org"},
+	}
+	d.SetAppConfig(cfg)
+
+	reset := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	next := reset.Add(7 * 24 * time.Hour)
+	cycle := func(now time.Time, at time.Time) []string {
+		return d.weeklyResets(weeklyReport(map[string]time.Time{"personal": at, "team": at}), now)
+	}
+
+	if got := cycle(reset.Add(-time.Hour), reset); len(got) != 0 {
+		t.Errorf("first cycle announced %v; it should only record the reset time", got)
+	}
+	// Clock jitter in the reported reset time is not a new window.
+	if got := cycle(reset.Add(-time.Minute), reset.Add(30*time.Second)); len(got) != 0 {
+		t.Errorf("jittered reset time announced %v", got)
+	}
+	// The reset passes and the next cycle reports the following window.
+	got := cycle(reset.Add(5*time.Minute), next)
+	if len(got) != 1 || got[0] != "personal" {
+		t.Fatalf("after the reset passed, announced %v; want [personal]", got)
+	}
+	if got := cycle(reset.Add(10*time.Minute), next); len(got) != 0 {
+		t.Errorf("same window announced twice: %v", got)
+	}
+	if got := cycle(next.Add(time.Minute), next.Add(7*24*time.Hour)); len(got) != 1 {
+		t.Errorf("next week's reset announced %v; want [personal]", got)
+	}
+}
+
+func TestDaemon_WeeklyResetNotification(t *testing.T) {
+	d, _ := newRefreshDaemon(t)
+	n, rec, _ := newTestNotifier(t, config.NotifyConfig{})
+	d.evaluator, d.notifier = status.NewEvaluator(status.DefaultEvaluatorConfig()), n
+	cfg := config.DefaultConfig()
+	cfg.Collectors.Claude.Accounts = []config.ClaudeAccountConfig{{Name: "personal", Type: "org", NotifyWeeklyReset: true}}
+	d.SetAppConfig(cfg)
+	ctx := context.Background()
+
+	passed := time.Now().Add(-time.Minute)
+	d.observeStatus(ctx, collectors.Update{Source: "claude", Data: weeklyReport(map[string]time.Time{"personal": passed})})
+	d.observeStatus(ctx, collectors.Update{Source: "claude",
+		Data: weeklyReport(map[string]time.Time{"personal": passed.Add(7 * 24 * time.Hour)})})
+	if rec.count() != 1 {
+		t.Fatalf("expected 1 notification, got %d", rec.count())
+	}
+	rec.mu.Lock()
+	msg := rec.bodies[0]["text"]
+	rec.mu.Unlock()
+	if msg != "🔄 prompt-pulse on box: Claude weekly limit reset for personal" {
+		t.Errorf("message = %q", msg)
+	}
+}
+
+func TestCompactCache(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]bool{ // name -> kept
+		"claude.json":          true,
+		"billing.json":         true,
+		"tailscale.json":       false, // built-in collector no longer enabled
+		"claude.json.tmp":      false,
+		"buildfarm.json":       true, // command collector: cannot tell it apart
+		"billing-history.json": true,
+		"claude-history.json":  true,
+		SnoozeFile:             true,
+		"notes.txt":            true,
+	}
+	for name := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store, err := cache.NewStore(cache.StoreConfig{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"claude", "openai"} {
+		if err := store.PutString(k, k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.Close()
+
+	compactCache(dir, []string{"claude", "billing"})
+
+	for name, kept := range files {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if kept && err != nil {
+			t.Errorf("%s should be kept: %v", name, err)
+		}
+		if !kept && !os.IsNotExist(err) {
+			t.Errorf("%s should be removed", name)
+		}
+	}
+	store, err = cache.NewStore(cache.StoreConfig{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if keys := store.Keys(); len(keys) != 1 || keys[0] != "claude" {
+		t.Errorf("store keys after compact = %v, want [claude]", keys)
+	}
+}
+
+func TestDaemon_CollectOnce_WarmsBanners(t *testing.T) {
+	dir := t.TempDir()
+	d, err := New(Config{
+		PIDFile:         filepath.Join(dir, "test.pid"),
+		HealthFile:      filepath.Join(dir, "health.json"),
+		SocketPath:      filepath.Join(dir, "test.sock"),
+		DataDir:         filepath.Join(dir, "data"),
+		BannerCacheFile: filepath.Join(dir, "banner.json"),
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.General.CacheDir = filepath.Join(dir, "data")
+	cfg.Banner.WarmSizes = []string{"80x24", "120x40/kitty", "bogus"}
+	d.SetAppConfig(cfg)
+	builds := 0
+	d.SetBannerData(func(_ *config.Config, width, height int) banner.BannerData {
+		builds++
+		return banner.BannerData{Widgets: []banner.WidgetData{{ID: "status", Title: "Status", Content: "ok"}}}
+	})
+
+	reg := collectors.NewRegistry()
+	_ = reg.Register(collectors.NewMockCollector("billing", time.Hour, collectors.WithData(map[string]string{"name": "billing"})))
+	if _, err := d.collectOnce(context.Background(), reg); err != nil {
+		t.Fatalf("collectOnce() error: %v", err)
+	}
+
+	sizes := []warmSize{{80, 24, "halfblocks"}, {120, 40, "kitty"}}
+	var warm []*BannerEntry
+	for _, s := range sizes {
+		entry, ok := d.banner.Get(s.width, s.height, s.protocol)
+		if !ok {
+			t.Fatalf("no warm banner for %+v", s)
+		}
+		if entry.Rendered == "" || entry.Inputs == "" {
+			t.Errorf("warm banner %+v = %+v, want rendered output and inputs", s, entry)
+		}
+		warm = append(warm, entry)
+	}
+
+	// A second cycle over unchanged inputs leaves the entries alone.
+	if _, err := d.collectOnce(context.Background(), reg); err != nil {
+		t.Fatalf("collectOnce() error: %v", err)
+	}
+	for i, s := range sizes {
+		after, _ := d.banner.Get(s.width, s.height, s.protocol)
+		if after == nil || !after.Timestamp.Equal(warm[i].Timestamp) {
+			t.Errorf("%+v re-rendered with unchanged inputs", s)
+		}
+	}
+	if builds != 4 {
+		t.Errorf("banner data built %d times, want 4", builds)
+	}
+}
+
+func TestParseWarmSize(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want warmSize
+		ok   bool
+	}{
+		{"80x24", warmSize{80, 24, "halfblocks"}, true},
+		{" 200x50/kitty ", warmSize{200, 50, "kitty"}, true},
+		{"80", warmSize{}, false},
+		{"0x24", warmSize{}, false},
+		{"80xfoo/sixel", warmSize{}, false},
+		{"80x24/bogus", warmSize{}, false},
+	} {
+		got, err := parseWarmSize(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseWarmSize(%q) = %+v, %v", tt.in, got, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package daemon
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"strings"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
+)
+
+// dashboardAssets holds the web dashboard: one page, a stylesheet and a
+// script that polls the JSON endpoints. Plain files, no build step.
+//
+//go:embed dashboard
+var dashboardAssets embed.FS
+
+// EnableDashboard serves the embedded web dashboard at / next to the JSON
+// API, coloured with th's palette via /theme.css. The dashboard has no
+// authentication, so it refuses to serve on a non-loopback address; put it
+// behind tailscale serve or an SSH tunnel for remote access. Call it before
+// Start.
+func (s *HTTPServer) EnableDashboard(th theme.Theme) error {
+	if host, _, _ := net.SplitHostPort(s.addr); !isLoopback(host) {
+		return fmt.Errorf("dashboard needs a loopback address, not %s", s.addr)
+	}
+	assets, err := fs.Sub(dashboardAssets, "dashboard")
+	if err != nil {
+		return err
+	}
+	css := themeCSS(th)
+	files := http.FileServerFS(assets)
+
+	s.mux.HandleFunc("/theme.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css; charset=utf-8")
+		fmt.Fprint(w, css)
+	})
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET")
+			writeHTTPError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+	return nil
+}
+
+// themeCSS renders th as CSS custom properties for the dashboard stylesheet.
+func themeCSS(th theme.Theme) string {
+	vars := []struct{ name, value string }{
+		{"bg", th.Background},
+		{"fg", th.Foreground},
+		{"dim", th.Dim},
+		{"accent", th.Accent},
+		{"border", th.Border},
+		{"title", th.Title},
+		{"ok", th.StatusOK},
+		{"warn", th.StatusWarn},
+		{"error", th.StatusError},
+		{"unknown", th.StatusUnknown},
+		{"gauge-filled", th.GaugeFilled},
+		{"gauge-empty", th.GaugeEmpty},
+		{"gauge-warn", th.GaugeWarn},
+		{"gauge-crit", th.GaugeCrit},
+	}
+	var b strings.Builder
+	b.WriteString(":root {\n")
+	for _, v := range vars {
+		fmt.Fprintf(&b, "  --%s: %s;\n", v.name, v.value)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
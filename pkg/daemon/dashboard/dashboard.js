@@ -0,0 +1,113 @@
+// prompt-pulse dashboard: polls the daemon's JSON API and renders the
+// banner's widgets. No dependencies and no build step.
+"use strict";
+
+const POLL_MS = 30000;
+
+function el(tag, cls, text) {
+  const e = document.createElement(tag);
+  if (cls) e.className = cls;
+  if (text !== undefined) e.textContent = text;
+  return e;
+}
+
+function row(left, right, cls) {
+  const r = el("div", "row");
+  r.append(el("span", "", left), el("span", cls || "", right));
+  return r;
+}
+
+function gauge(pct, warn, crit) {
+  const g = el("div", "gauge");
+  const fill = el("div", pct >= (crit || 90) ? "crit" : pct >= (warn || 75) ? "warn" : "");
+  fill.style.width = Math.min(Math.max(pct, 0), 100) + "%";
+  g.append(fill);
+  return g;
+}
+
+const usd = (v) => "$" + (v || 0).toFixed(2);
+
+async function fetchJSON(path) {
+  const resp = await fetch(path, { cache: "no-store" });
+  const body = await resp.json();
+  if (!resp.ok) throw new Error(body.error || resp.statusText);
+  return body;
+}
+
+function fill(id, render, data) {
+  const body = document.querySelector("#" + id + " .body");
+  body.replaceChildren();
+  if (data instanceof Error) {
+    body.append(el("span", "dim", data.message));
+    return;
+  }
+  render(body, data);
+}
+
+function renderClaude(body, r) {
+  for (const a of r.accounts || []) {
+    if (!a.connected) {
+      body.append(row(a.name, a.error || "disconnected", "error"));
+      continue;
+    }
+    const spent = a.current_month.cost_usd;
+    body.append(row(a.name, a.budget_usd ? usd(spent) + " / " + usd(a.budget_usd) : usd(spent)));
+    if (a.budget_usd) body.append(gauge(spent / a.budget_usd * 100, a.warn_threshold, a.crit_threshold));
+  }
+}
+
+function renderBilling(body, r) {
+  for (const p of r.providers || []) {
+    body.append(p.connected ? row(p.name, usd(p.month_to_date)) : row(p.name, p.error || "disconnected", "error"));
+  }
+  body.append(row("total", usd(r.total_monthly_usd) + (r.budget_usd ? " / " + usd(r.budget_usd) : "")));
+  if (r.budget_usd) body.append(gauge(r.budget_percent));
+}
+
+function renderTailscale(body, r) {
+  body.append(row(r.tailnet_name || "tailnet", r.online_peers + "/" + r.total_peers + " online"));
+  for (const p of r.peers || []) {
+    body.append(row(p.hostname, p.online ? "online" : "offline", p.online ? "ok" : "dim"));
+  }
+}
+
+function renderK8s(body, r) {
+  for (const c of r.clusters || []) {
+    if (!c.connected) {
+      body.append(row(c.context, c.error || "disconnected", "error"));
+      continue;
+    }
+    body.append(row(c.context, c.ready_nodes + "/" + c.total_nodes + " nodes, " +
+      c.running_pods + "/" + c.total_pods + " pods", c.ready_nodes === c.total_nodes ? "ok" : "warn"));
+  }
+}
+
+async function refresh() {
+  const [status, claude, billing, infra] = await Promise.all(
+    ["status", "claude", "billing", "infra"].map((p) => fetchJSON(p).catch((e) => e)));
+
+  const level = document.getElementById("status");
+  const reasons = document.getElementById("reasons");
+  reasons.replaceChildren();
+  if (status instanceof Error) {
+    level.className = "level unknown";
+    level.textContent = "unknown";
+  } else {
+    level.className = "level " + status.level;
+    level.textContent = status.level;
+    for (const r of status.reasons || []) {
+      reasons.append(el("li", r.level, r.subsystem + ": " + r.message));
+    }
+  }
+
+  fill("claude", renderClaude, claude);
+  fill("billing", renderBilling, billing);
+  const ts = infra instanceof Error ? infra : infra.tailscale || new Error("no cached data for tailscale");
+  const k8s = infra instanceof Error ? infra : infra.k8s || new Error("no cached data for k8s");
+  fill("tailscale", renderTailscale, ts);
+  fill("k8s", renderK8s, k8s);
+  document.getElementById("updated").textContent = "updated " + new Date().toLocaleTimeString();
+}
+
+refresh();
+setInterval(refresh, POLL_MS);
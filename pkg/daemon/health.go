@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
 )
 
 // WriteHealthFile writes the health status as indented JSON to path.
@@ -12,7 +14,7 @@ import (
 // renamed into place to prevent partial reads.
 func WriteHealthFile(path string, status *HealthStatus) error {
 	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+	if err := cache.EnsurePrivateDir(dir); err != nil {
 		return fmt.Errorf("create health directory: %w", err)
 	}
 
@@ -22,7 +24,7 @@ func WriteHealthFile(path string, status *HealthStatus) error {
 	}
 
 	tmp := path + ".tmp"
-	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+	if err := os.WriteFile(tmp, data, cache.FileMode); err != nil {
 		return fmt.Errorf("write temp health file: %w", err)
 	}
 
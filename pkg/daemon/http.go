@@ -0,0 +1,270 @@
+package daemon
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// httpShutdownTimeout bounds how long in-flight HTTP requests may run after
+// the daemon is asked to stop.
+const httpShutdownTimeout = 5 * time.Second
+
+// HTTPActionHeader must be set, to any value, on POST requests. A web page
+// cannot add a custom header to a cross-site request without a CORS
+// preflight, which the API never answers, so it cannot trigger actions.
+const HTTPActionHeader = "X-Prompt-Pulse"
+
+// HTTPServer exposes a read-mostly JSON API over HTTP for dashboards and
+// home-automation tools. Every endpoint is a thin wrapper around an IPC
+// command, so both interfaces always return the same data.
+//
+// Endpoints:
+//   - GET  /status   evaluated status level and reasons (STATUS)
+//   - GET  /claude   cached Claude usage report (GET claude)
+//   - GET  /billing  cached billing report (GET billing)
+//   - GET  /infra    cached Tailscale and Kubernetes status (GET infra)
+//   - POST /refresh  run collectors now; ?collector=name for one (REFRESH)
+//   - POST /snooze   silence notifications; ?duration=2h, or off (SNOOZE)
+//
+// Requests must address the server by a loopback name or the configured
+// host, so a DNS-rebound name cannot reach it from a browser. POSTs also
+// need HTTPActionHeader and, when they carry an Origin, a loopback one.
+//
+// EnableDashboard adds a browser dashboard at / on top of these, and
+// EnableTLS serves them over HTTPS, optionally with mutual TLS.
+type HTTPServer struct {
+	addr    string
+	handler IPCHandler
+	mux     *http.ServeMux
+	srv     *http.Server
+	ln      net.Listener
+
+	// hosts are the non-loopback Host names requests may use.
+	hosts map[string]bool
+}
+
+// NewHTTPServer creates an HTTP API server for addr that dispatches to
+// handler. An address without a host binds to 127.0.0.1 so the API is
+// never exposed beyond the machine unless a host is given explicitly.
+func NewHTTPServer(addr string, handler IPCHandler) *HTTPServer {
+	if strings.HasPrefix(addr, ":") {
+		addr = "127.0.0.1" + addr
+	}
+	s := &HTTPServer{addr: addr, handler: handler, hosts: allowedHosts(addr)}
+
+	mux := http.NewServeMux()
+	s.mux = mux
+	mux.HandleFunc("/status", s.get("STATUS", nil))
+	mux.HandleFunc("/claude", s.get("GET", map[string]string{"key": "claude"}))
+	mux.HandleFunc("/billing", s.get("GET", map[string]string{"key": "billing"}))
+	mux.HandleFunc("/infra", s.get("GET", map[string]string{"key": "infra"}))
+	mux.HandleFunc("/refresh", s.refresh)
+	mux.HandleFunc("/snooze", s.snooze)
+	s.srv = &http.Server{Handler: s.guard(mux), ReadHeaderTimeout: 5 * time.Second}
+	return s
+}
+
+// allowedHosts returns the non-loopback Host names a server on addr
+// answers to: its host, or for a wildcard address the machine's hostname
+// and interface addresses.
+func allowedHosts(addr string) map[string]bool {
+	host, _, _ := net.SplitHostPort(addr)
+	hosts := map[string]bool{}
+	ip := net.ParseIP(host)
+	if host != "" && (ip == nil || !ip.IsUnspecified()) {
+		hosts[strings.ToLower(host)] = true
+		return hosts
+	}
+	if name, err := os.Hostname(); err == nil {
+		hosts[strings.ToLower(name)] = true
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, a := range addrs {
+			if n, ok := a.(*net.IPNet); ok {
+				hosts[n.IP.String()] = true
+			}
+		}
+	}
+	return hosts
+}
+
+// guard rejects requests for another Host and POSTs that a web page could
+// have sent (see HTTPServer) before they reach next.
+func (s *HTTPServer) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(strings.Trim(host, "[]"))
+		if !isLoopback(host) && !s.hosts[host] {
+			writeHTTPError(w, http.StatusForbidden, fmt.Errorf("host %q not allowed", r.Host))
+			return
+		}
+		if r.Method == http.MethodPost {
+			if origin := r.Header.Get("Origin"); origin != "" {
+				u, err := url.Parse(origin)
+				if err != nil || !isLoopback(u.Hostname()) {
+					writeHTTPError(w, http.StatusForbidden, fmt.Errorf("origin %q not allowed", origin))
+					return
+				}
+			}
+			if r.Header.Get(HTTPActionHeader) == "" {
+				writeHTTPError(w, http.StatusForbidden, fmt.Errorf("POST needs the %s header", HTTPActionHeader))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// EnableTLS serves the API over HTTPS with the PEM certificate and key in
+// certFile and keyFile. When clientCAFile is set, it also requires mutual
+// TLS: clients must present a certificate signed by one of the CAs in that
+// PEM file, so only they can reach the API. The files are read now, so a
+// bad path or a mismatched key is reported here rather than on the first
+// request. Call it before Start.
+func (s *HTTPServer) EnableTLS(certFile, keyFile, clientCAFile string) error {
+	if certFile == "" || keyFile == "" {
+		return fmt.Errorf("tls: both a certificate and a key are required")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("tls: load certificate %s and key %s: %w", certFile, keyFile, err)
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if clientCAFile != "" {
+		data, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return fmt.Errorf("tls: read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("tls: client CA %s holds no PEM certificates", clientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	s.srv.TLSConfig = cfg
+	return nil
+}
+
+// Start listens on the configured address and serves in the background.
+func (s *HTTPServer) Start() error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", s.addr, err)
+	}
+	if host, _, _ := net.SplitHostPort(s.addr); !isLoopback(host) && s.srv.TLSConfig == nil {
+		slog.Warn("daemon: HTTP API listening on non-loopback address without TLS", "addr", s.addr)
+	}
+	if s.srv.TLSConfig != nil {
+		ln = tls.NewListener(ln, s.srv.TLSConfig)
+	}
+	s.ln = ln
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("daemon: HTTP API", "err", err)
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the server is listening on, which differs from
+// the configured one when port 0 was requested.
+func (s *HTTPServer) Addr() string {
+	if s.ln == nil {
+		return s.addr
+	}
+	return s.ln.Addr().String()
+}
+
+// Stop shuts the server down, letting in-flight requests finish briefly.
+func (s *HTTPServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+	_ = s.srv.Shutdown(ctx)
+}
+
+// get returns a handler that answers GET requests with the response to the
+// given IPC command.
+func (s *HTTPServer) get(cmd string, args map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET")
+			writeHTTPError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		resp, err := s.handler.HandleCommand(cmd, args)
+		if err != nil {
+			writeHTTPError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		writeHTTPJSON(w, http.StatusOK, resp)
+	}
+}
+
+// refresh triggers out-of-band collection via the REFRESH command.
+func (s *HTTPServer) refresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeHTTPError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	args := map[string]string{}
+	if c := r.URL.Query().Get("collector"); c != "" {
+		args["collector"] = strings.ToLower(c)
+	}
+	resp, err := s.handler.HandleCommand("REFRESH", args)
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeHTTPJSON(w, http.StatusOK, resp)
+}
+
+// snooze sets or clears a notification snooze via the SNOOZE command.
+func (s *HTTPServer) snooze(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeHTTPError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	resp, err := s.handler.HandleCommand("SNOOZE", map[string]string{"duration": r.URL.Query().Get("duration")})
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeHTTPJSON(w, http.StatusOK, resp)
+}
+
+func writeHTTPJSON(w http.ResponseWriter, code int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	fmt.Fprintln(w, body)
+}
+
+func writeHTTPError(w http.ResponseWriter, code int, err error) {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	writeHTTPJSON(w, code, string(data))
+}
+
+// isLoopback reports whether host names the local machine.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
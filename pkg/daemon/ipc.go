@@ -23,7 +23,10 @@ type IPCHandler interface {
 // Protocol:
 //   - Client sends a single line: COMMAND [arg1] [arg2] ...
 //   - Server responds with a JSON line followed by a newline.
-//   - Supported commands: HEALTH, BANNER {width} {height} {protocol}, REFRESH, QUIT
+//   - Supported commands: HEALTH, BANNER {width} {height} {protocol},
+//     RENDER {width} {height} [protocol] [sections...], REFRESH [collector],
+//     STATUS, EXPLAIN, GET {key}, SNOOZE {duration|off}, ENABLE {collector},
+//     DISABLE {collector}, QUIT
 type IPCServer struct {
 	socketPath string
 	handler    IPCHandler
@@ -156,7 +159,15 @@ func (s *IPCServer) handleConn(conn net.Conn) {
 //
 //	HEALTH                              -> cmd="HEALTH", args={}
 //	BANNER 80 24 kitty                  -> cmd="BANNER", args={width:80, height:24, protocol:kitty}
+//	RENDER 100 30 kitty claude billing  -> cmd="RENDER", args={width:100, height:30, protocol:kitty, sections:claude,billing}
 //	REFRESH                             -> cmd="REFRESH", args={}
+//	REFRESH billing                     -> cmd="REFRESH", args={collector:billing}
+//	STATUS                              -> cmd="STATUS", args={}
+//	EXPLAIN                             -> cmd="EXPLAIN", args={}
+//	GET claude                          -> cmd="GET", args={key:claude}
+//	SNOOZE 2h                           -> cmd="SNOOZE", args={duration:2h}
+//	DISABLE billing                     -> cmd="DISABLE", args={collector:billing}
+//	ENABLE billing                      -> cmd="ENABLE", args={collector:billing}
 //	QUIT                                -> cmd="QUIT", args={}
 func parseIPCCommand(line string) (string, map[string]string) {
 	parts := strings.Fields(line)
@@ -168,7 +179,7 @@ func parseIPCCommand(line string) (string, map[string]string) {
 	args := make(map[string]string)
 
 	switch cmd {
-	case "BANNER":
+	case "BANNER", "RENDER":
 		if len(parts) >= 2 {
 			args["width"] = parts[1]
 		}
@@ -178,6 +189,21 @@ func parseIPCCommand(line string) (string, map[string]string) {
 		if len(parts) >= 4 {
 			args["protocol"] = parts[3]
 		}
+		if cmd == "RENDER" && len(parts) >= 5 {
+			args["sections"] = strings.Join(parts[4:], ",")
+		}
+	case "REFRESH", "ENABLE", "DISABLE":
+		if len(parts) >= 2 {
+			args["collector"] = strings.ToLower(parts[1])
+		}
+	case "GET":
+		if len(parts) >= 2 {
+			args["key"] = strings.ToLower(parts[1])
+		}
+	case "SNOOZE":
+		if len(parts) >= 2 {
+			args["duration"] = strings.ToLower(parts[1])
+		}
 	}
 
 	return cmd, args
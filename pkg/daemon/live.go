@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// CollectLive runs the enabled collectors named in keys once, concurrently,
+// and writes their results to the cache directory as the daemon would. It
+// lets a banner render fresh data without a daemon: callers pass only the
+// keys whose cache entry is missing or stale. Keys without an enabled
+// collector are ignored, and collectors still running when ctx is done
+// are abandoned, leaving their cache entries as they were. It returns the
+// keys it wrote, sorted.
+func CollectLive(ctx context.Context, cfg *config.Config, keys []string) []string {
+	return collectLive(ctx, BuildRegistry(cfg), cfg.General.CacheDir, keys)
+}
+
+// collectLive is CollectLive with the collector registry supplied.
+func collectLive(ctx context.Context, reg *collectors.Registry, cacheDir string, keys []string) []string {
+	if err := cache.EnsurePrivateDir(cacheDir); err != nil {
+		slog.Warn("daemon: live collect", "err", err)
+		return nil
+	}
+	runner := collectors.NewRunner(reg, nil)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		written []string
+	)
+	for _, key := range keys {
+		if _, ok := reg.Get(key); !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			start := time.Now()
+			data, err := runner.RunOnce(ctx, key)
+			if err != nil {
+				slog.Debug("daemon: live collect failed", "collector", key, "err", err)
+				return
+			}
+			u := collectors.Update{Source: key, Data: data, Timestamp: time.Now()}
+			mu.Lock()
+			defer mu.Unlock()
+			if err := storeUpdate(cacheDir, u, nil); err != nil {
+				slog.Warn("daemon: live collect", "collector", key, "err", err)
+				return
+			}
+			written = append(written, key)
+			slog.Debug("daemon: live collected", "collector", key, "elapsed", time.Since(start))
+		}(key)
+	}
+	wg.Wait()
+	sort.Strings(written)
+	return written
+}
@@ -0,0 +1,282 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/status"
+)
+
+// Webhook payload formats supported by the Notifier.
+const (
+	NotifySlack   = "slack"
+	NotifyDiscord = "discord"
+)
+
+// notifyTimeout bounds a single webhook POST.
+const notifyTimeout = 10 * time.Second
+
+// Notifier posts a message to a Slack or Discord webhook when the overall
+// status level changes. Escalations are sent immediately; de-escalations and
+// recoveries wait until Cooldown has passed since the last message, which
+// collapses flapping into a single notification. A nil *Notifier is a no-op.
+type Notifier struct {
+	url      string
+	kind     string
+	minLevel status.Level
+	cooldown time.Duration
+	recovery bool
+	host     string
+	client   *http.Client
+	nowFunc  func() time.Time
+
+	mu       sync.Mutex
+	reported status.Level
+	lastSent time.Time
+}
+
+// NewNotifier builds a Notifier from the [notify] config section. It returns
+// nil, nil when no webhook is configured.
+func NewNotifier(cfg config.NotifyConfig) (*Notifier, error) {
+	if cfg.WebhookURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(cfg.WebhookURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("notify: invalid webhook_url %q", cfg.WebhookURL)
+	}
+
+	kind := strings.ToLower(cfg.Kind)
+	if kind == "" {
+		kind = NotifySlack
+		if strings.Contains(u.Host, "discord") {
+			kind = NotifyDiscord
+		}
+	}
+	if kind != NotifySlack && kind != NotifyDiscord {
+		return nil, fmt.Errorf("notify: unknown kind %q (want slack or discord)", cfg.Kind)
+	}
+
+	minLevel := status.LevelWarning
+	if cfg.MinLevel != "" {
+		if minLevel, err = status.ParseLevel(cfg.MinLevel); err != nil {
+			return nil, fmt.Errorf("notify: min_level: %w", err)
+		}
+		if minLevel == status.LevelHealthy {
+			return nil, fmt.Errorf("notify: min_level must be warning or critical")
+		}
+	}
+
+	host, _ := os.Hostname()
+	return &Notifier{
+		url:      cfg.WebhookURL,
+		kind:     kind,
+		minLevel: minLevel,
+		cooldown: cfg.Cooldown.Duration,
+		recovery: cfg.NotifyRecovery,
+		host:     host,
+		client:   &http.Client{Timeout: notifyTimeout},
+		nowFunc:  time.Now,
+	}, nil
+}
+
+// Observe feeds the latest evaluation to the notifier and posts a message if
+// the change is worth reporting.
+func (n *Notifier) Observe(ctx context.Context, res status.Result) error {
+	if n == nil {
+		return nil
+	}
+	msg, ok := n.decide(res)
+	if !ok {
+		return nil
+	}
+	return n.post(ctx, msg)
+}
+
+// decide updates the reported level and returns the message to send, if any.
+func (n *Notifier) decide(res status.Result) (string, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// Levels below the configured minimum are treated as healthy so that,
+	// for example, a warning does not page a critical-only channel.
+	target := res.Level
+	if target < n.minLevel {
+		target = status.LevelHealthy
+	}
+	if target == n.reported {
+		return "", false
+	}
+
+	now := n.nowFunc()
+	if target < n.reported && now.Sub(n.lastSent) < n.cooldown {
+		return "", false
+	}
+
+	prev := n.reported
+	n.reported = target
+	if target == status.LevelHealthy && !n.recovery {
+		return "", false
+	}
+	n.lastSent = now
+	return n.format(prev, res), true
+}
+
+// Announce posts msg as a one-off notice, outside the level tracking that
+// Observe does. Cooldown and min_level do not apply.
+func (n *Notifier) Announce(ctx context.Context, msg string) error {
+	if n == nil {
+		return nil
+	}
+	text := "🔄 prompt-pulse"
+	if n.host != "" {
+		text += " on " + n.host
+	}
+	return n.post(ctx, text+": "+msg)
+}
+
+// notifyIcons prefixes each message with the new level at a glance.
+var notifyIcons = map[status.Level]string{
+	status.LevelHealthy:  "✅",
+	status.LevelWarning:  "⚠️",
+	status.LevelCritical: "🔴",
+}
+
+// format renders the message text for a change from prev to res.Level.
+func (n *Notifier) format(prev status.Level, res status.Result) string {
+	level := res.Level
+	if level < n.minLevel {
+		level = status.LevelHealthy
+	}
+	text := fmt.Sprintf("%s prompt-pulse", notifyIcons[level])
+	if n.host != "" {
+		text += " on " + n.host
+	}
+	text += fmt.Sprintf(": %s → %s", prev, level)
+	if level != status.LevelHealthy {
+		if summary := res.Summary(); summary != "" {
+			text += " — " + summary
+		}
+	}
+	return text
+}
+
+// post sends msg in the configured webhook format.
+func (n *Notifier) post(ctx context.Context, msg string) error {
+	payload := map[string]string{"text": msg}
+	if n.kind == NotifyDiscord {
+		payload = map[string]string{"content": msg}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// attachStatus creates the status evaluator with the rules and, when a
+// webhook is configured, the notifier. A bad [notify] section is logged and
+// disables notifications without stopping the daemon.
+func (d *Daemon) attachStatus(cfg config.NotifyConfig, rules config.StatusConfig) {
+	n, err := NewNotifier(cfg)
+	if err != nil {
+		slog.Error("daemon: notifier disabled", "err", err)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.evaluator = status.NewEvaluator(StatusRules(rules))
+	d.notifier = n
+}
+
+// StatusRules converts the [status] section to the evaluator ruleset.
+// Weights with an unknown level are skipped; config validation warns
+// about them.
+func StatusRules(cfg config.StatusConfig) status.EvaluatorConfig {
+	rules := status.EvaluatorConfig{
+		Disabled:            cfg.Ignore,
+		ClaudeWarn:          cfg.ClaudeWarn,
+		ClaudeCrit:          cfg.ClaudeCrit,
+		BillingWarn:         cfg.BillingWarn,
+		BillingCrit:         cfg.BillingCrit,
+		K8sPodPressure:      cfg.K8sPodPressure,
+		K8sNotReadyCrit:     cfg.K8sNotReadyCrit,
+		TailscaleStaleAfter: cfg.TailscaleStaleAfter.Duration,
+		SysMetrics: sysmetrics.Thresholds{
+			CPU:    sysmetrics.Threshold{Warn: cfg.CPUWarn, Crit: cfg.CPUCrit},
+			Memory: sysmetrics.Threshold{Warn: cfg.MemoryWarn, Crit: cfg.MemoryCrit},
+			Disk:   sysmetrics.Threshold{Warn: cfg.DiskWarn, Crit: cfg.DiskCrit},
+		},
+	}
+	for name, level := range cfg.Weights {
+		if l, err := status.ParseLevel(level); err == nil {
+			if rules.Weights == nil {
+				rules.Weights = make(map[string]status.Level, len(cfg.Weights))
+			}
+			rules.Weights[name] = l
+		}
+	}
+	return rules
+}
+
+// attachNotifier replaces the notifier with one built from cfg, keeping the
+// evaluator's current level.
+func (d *Daemon) attachNotifier(cfg config.NotifyConfig) {
+	n, err := NewNotifier(cfg)
+	if err != nil {
+		slog.Error("daemon: notifier disabled", "err", err)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.notifier = n
+}
+
+// observeStatus feeds a stored update to the evaluator and lets the notifier
+// react to any resulting level change. While snoozed the notifier is not
+// consulted at all, so a change that persists past the snooze is reported
+// on the first update after it ends.
+func (d *Daemon) observeStatus(ctx context.Context, u collectors.Update) {
+	d.mu.Lock()
+	ev, n := d.evaluator, d.notifier
+	d.mu.Unlock()
+	if ev == nil {
+		return
+	}
+	ev.Observe(u.Source, u.Data)
+	_, snoozed := d.snoozedUntil(time.Now())
+	d.announceWeeklyResets(ctx, u, n, snoozed)
+	if snoozed {
+		return
+	}
+	if err := n.Observe(ctx, ev.Evaluate()); err != nil {
+		slog.Error("daemon: notify", "err", err)
+	}
+}
@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// CollectOnce runs every enabled collector for a single cycle, writes
+// their cache files and the health file, and returns, for refreshing data
+// from cron or a systemd timer instead of a long-running daemon. It holds
+// the PID lock while collecting, so it never races a running daemon over
+// the cache: if a daemon is already running, it returns an error wrapping
+// ErrAlreadyRunning without collecting anything. The health file is marked
+// HealthModeCollectOnce so -health and the banner do not expect a daemon.
+// Status-change notifications need the long-running daemon and are not
+// sent.
+func (d *Daemon) CollectOnce(ctx context.Context) (*RefreshResult, error) {
+	if d.appCfg == nil {
+		return nil, fmt.Errorf("daemon: collect once: no application config")
+	}
+	config.ResolveSecretCommands(d.appCfg)
+	return d.collectOnce(ctx, BuildRegistry(d.appCfg))
+}
+
+// collectOnce is CollectOnce with the collector registry supplied.
+func (d *Daemon) collectOnce(ctx context.Context, reg *collectors.Registry) (*RefreshResult, error) {
+	cacheDir := d.cfg.DataDir
+	if d.appCfg != nil && d.appCfg.General.CacheDir != "" {
+		cacheDir = d.appCfg.General.CacheDir
+	}
+	for _, dir := range []string{filepath.Dir(d.cfg.PIDFile), filepath.Dir(d.cfg.HealthFile), cacheDir} {
+		if err := cache.EnsurePrivateDir(dir); err != nil {
+			return nil, fmt.Errorf("daemon: %w", err)
+		}
+	}
+
+	if err := AcquirePID(d.cfg.PIDFile); err != nil {
+		return nil, fmt.Errorf("daemon: acquire PID: %w", err)
+	}
+	defer ReleasePID(d.cfg.PIDFile)
+
+	d.mu.Lock()
+	d.startedAt = time.Now()
+	d.once = true
+	d.mu.Unlock()
+	d.loadSnooze()
+	d.logBannerWarnings(d.appCfg)
+
+	d.attachCollectors(reg, collectors.NewRunner(reg, nil), cacheDir)
+	slog.Debug("daemon: collecting once", "collectors", reg.List())
+	result, err := d.refreshResult("")
+	if err != nil {
+		return nil, err
+	}
+	if err := d.WriteHealth(); err != nil {
+		slog.Warn("daemon: write health", "err", err)
+	}
+	d.warmBanners()
+	return result, ctx.Err()
+}
@@ -1,16 +1,24 @@
 package daemon
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
 )
 
+// ErrAlreadyRunning is returned (wrapped) by AcquirePID when another live
+// process holds the PID file.
+var ErrAlreadyRunning = errors.New("daemon already running")
+
 // AcquirePID creates a PID file at path with the current process PID.
-// It fails if another live process already holds the lock. If the existing
+// It fails with ErrAlreadyRunning if another live process already holds the
+// lock. If the existing
 // PID file points to a dead process, it is removed and re-acquired.
 //
 // The write is atomic: content is written to a temporary file in the same
@@ -18,7 +26,7 @@ import (
 func AcquirePID(path string) error {
 	// Ensure the directory exists.
 	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+	if err := cache.EnsurePrivateDir(dir); err != nil {
 		return fmt.Errorf("create PID directory: %w", err)
 	}
 
@@ -27,7 +35,7 @@ func AcquirePID(path string) error {
 	if err == nil {
 		// PID file exists and is readable. Check if the process is alive.
 		if IsProcessAlive(existingPID) {
-			return fmt.Errorf("daemon already running (PID %d)", existingPID)
+			return fmt.Errorf("%w (PID %d)", ErrAlreadyRunning, existingPID)
 		}
 		// Stale PID file -- remove it.
 		os.Remove(path)
@@ -0,0 +1,141 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
+)
+
+// refreshTimeout bounds how long an IPC REFRESH waits for out-of-band
+// collection before reporting the collector as failed.
+const refreshTimeout = 30 * time.Second
+
+// RefreshOutcome reports the result of refreshing one collector.
+type RefreshOutcome struct {
+	Name      string `json:"name"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// RefreshResult is the JSON response to the REFRESH IPC command.
+type RefreshResult struct {
+	Status     string           `json:"status"`
+	Message    string           `json:"message"`
+	Collectors []RefreshOutcome `json:"collectors"`
+}
+
+// attachCollectors records the live registry and runner so that IPC commands
+// can trigger collection outside the regular schedule.
+func (d *Daemon) attachCollectors(reg *collectors.Registry, runner *collectors.Runner, cacheDir string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.registry = reg
+	d.runner = runner
+	d.cacheDir = cacheDir
+}
+
+// refresh runs the named collector immediately and writes its cache key.
+// An empty name refreshes every enabled collector concurrently. Unknown or
+// disabled names return an error without running anything.
+func (d *Daemon) refresh(name string) (string, error) {
+	result, err := d.refreshResult(name)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// refreshResult does the work of refresh and returns the result unencoded.
+func (d *Daemon) refreshResult(name string) (*RefreshResult, error) {
+	d.mu.Lock()
+	reg, runner, cacheDir := d.registry, d.runner, d.cacheDir
+	d.mu.Unlock()
+
+	var names []string
+	if reg != nil {
+		names = reg.List()
+	}
+	if name != "" {
+		if reg == nil {
+			return nil, fmt.Errorf("unknown collector: %s", name)
+		}
+		if _, ok := reg.Get(name); !ok {
+			return nil, fmt.Errorf("unknown collector: %s (registered: %v)", name, names)
+		}
+		if runner != nil && !runner.Enabled(name) {
+			return nil, fmt.Errorf("collector %s is disabled (ENABLE %s to resume)", name, name)
+		}
+		names = []string{name}
+	} else if runner != nil {
+		var enabled []string
+		for _, n := range names {
+			if runner.Enabled(n) {
+				enabled = append(enabled, n)
+			}
+		}
+		names = enabled
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	outcomes := make([]RefreshOutcome, len(names))
+	var wg sync.WaitGroup
+	for i, n := range names {
+		wg.Add(1)
+		go func(i int, n string) {
+			defer wg.Done()
+			outcomes[i] = d.refreshOne(ctx, runner, cacheDir, n)
+		}(i, n)
+	}
+	wg.Wait()
+	sort.Slice(outcomes, func(i, j int) bool { return outcomes[i].Name < outcomes[j].Name })
+
+	result := RefreshResult{
+		Status:     "ok",
+		Message:    "refresh triggered",
+		Collectors: outcomes,
+	}
+	if name != "" {
+		result.Message = "refreshed " + name
+	}
+	for _, o := range outcomes {
+		if !o.Success {
+			result.Status = "error"
+		}
+	}
+	return &result, nil
+}
+
+// refreshOne runs a single collection cycle and stores the result.
+func (d *Daemon) refreshOne(ctx context.Context, runner *collectors.Runner, cacheDir, name string) RefreshOutcome {
+	start := time.Now()
+	data, err := runner.RunOnce(ctx, name)
+	outcome := RefreshOutcome{
+		Name:      name,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if err == nil {
+		u := collectors.Update{Source: name, Data: data, Timestamp: start}
+		if err = storeUpdate(cacheDir, u, d); err == nil {
+			d.observeStatus(ctx, u)
+		}
+	}
+	if err != nil {
+		outcome.Error = err.Error()
+		d.recordCollector(name, err)
+		return outcome
+	}
+	outcome.Success = true
+	return outcome
+}
@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// startCollectors builds a registry from cfg and starts a runner for it
+// that feeds the daemon's updates channel, then attaches both so IPC
+// commands and HEALTH see them. Collectors named in disabled stay switched
+// off. With no collectors enabled, nothing runs and none are attached.
+func (d *Daemon) startCollectors(ctx context.Context, cfg *config.Config, disabled []string) {
+	d.mu.Lock()
+	updates, cacheDir := d.updates, d.cacheDir
+	d.mu.Unlock()
+
+	reg := BuildRegistry(cfg)
+	names := reg.List()
+	if len(names) == 0 {
+		slog.Warn("daemon: no collectors enabled")
+		d.attachCollectors(nil, nil, cacheDir)
+		return
+	}
+
+	slog.Info("daemon: starting collectors", "count", len(names), "collectors", names)
+	runner := collectors.NewRunner(reg, updates)
+	applyQuietHours(runner, cfg)
+	applyCircuitBreaker(runner, cfg)
+	for _, name := range disabled {
+		// Collectors dropped from the config no longer need switching off.
+		_ = runner.SetEnabled(name, false)
+	}
+	if err := runner.Start(ctx); err != nil {
+		slog.Error("daemon: start collectors", "err", err)
+		return
+	}
+	d.attachCollectors(reg, runner, cacheDir)
+}
+
+// Reload re-reads the application config and restarts the collectors from
+// it without touching the IPC socket or PID lock. Collectors removed from
+// the config stop, new ones start, and changed intervals apply from the
+// next collection; every collector runs once straight away. Collectors
+// switched off with DISABLE stay off. A config that fails to load is
+// rejected and the running one is kept. Changes to cache_dir and the HTTP
+// API settings (http_addr, its TLS files) need a restart.
+func (d *Daemon) Reload(ctx context.Context) error {
+	d.mu.Lock()
+	path, old, updates := d.cfgPath, d.appCfg, d.updates
+	d.mu.Unlock()
+	if updates == nil {
+		return fmt.Errorf("daemon: reload: collectors are not running")
+	}
+
+	cfg, err := config.LoadFrom(path)
+	if err != nil {
+		return fmt.Errorf("daemon: reload: %w", err)
+	}
+	config.ResolveSecretCommands(cfg)
+
+	disabled := d.disabledCollectors()
+	d.mu.Lock()
+	runner := d.runner
+	d.appCfg = cfg
+	d.mu.Unlock()
+	if runner != nil {
+		runner.Stop()
+	}
+
+	if !reflect.DeepEqual(old.Notify, cfg.Notify) {
+		d.attachNotifier(cfg.Notify)
+	}
+	if !reflect.DeepEqual(old.Status, cfg.Status) {
+		d.mu.Lock()
+		if d.evaluator != nil {
+			d.evaluator.SetConfig(StatusRules(cfg.Status))
+		}
+		d.mu.Unlock()
+	}
+	d.logBannerWarnings(cfg)
+	d.startCollectors(ctx, cfg, disabled)
+	d.pruneCollectorHealth()
+	_ = d.WriteHealth()
+
+	slog.Info("daemon: config reloaded", "path", path)
+	return nil
+}
+
+// pruneCollectorHealth drops health entries for collectors that are no
+// longer registered.
+func (d *Daemon) pruneCollectorHealth() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for name := range d.collectors {
+		if d.registry == nil {
+			delete(d.collectors, name)
+			continue
+		}
+		if _, ok := d.registry.Get(name); !ok {
+			delete(d.collectors, name)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/banner"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// maxRenderWidth and maxRenderHeight bound the terminal size a RENDER
+// request may ask for, so a bad client cannot make the daemon lay out and
+// cache an enormous banner.
+const (
+	maxRenderWidth  = 500
+	maxRenderHeight = 200
+)
+
+// renderProtocols are the graphics protocols a RENDER request or a
+// banner.warm_sizes entry may name.
+var renderProtocols = []string{"halfblocks", "kitty", "iterm2", "sixel", "none"}
+
+// maxRenderedBanners is how many banners the banner cache keeps besides
+// the banner.warm_sizes ones, so RENDER requests for many sizes and
+// section lists cannot grow it without bound. The oldest go first.
+const maxRenderedBanners = 16
+
+// renderCommand handles RENDER {width} {height} [protocol] [sections]: it
+// returns the cached banner for that size, protocol and sections when its
+// inputs are unchanged, and otherwise renders one from the current cache
+// data and caches it. Unlike BANNER it never misses. sections is a
+// comma-separated list of widget IDs, e.g. "claude,billing"; empty renders
+// every section. Unknown protocols and sections are rejected.
+func (d *Daemon) renderCommand(args map[string]string) (string, error) {
+	width, err := strconv.Atoi(args["width"])
+	if err != nil || width <= 0 || width > maxRenderWidth {
+		return "", fmt.Errorf("render: width must be 1-%d, got %q", maxRenderWidth, args["width"])
+	}
+	height, err := strconv.Atoi(args["height"])
+	if err != nil || height <= 0 || height > maxRenderHeight {
+		return "", fmt.Errorf("render: height must be 1-%d, got %q", maxRenderHeight, args["height"])
+	}
+	protocol := strings.ToLower(args["protocol"])
+	if protocol == "" {
+		protocol = defaultWarmProtocol
+	}
+	if !slices.Contains(renderProtocols, protocol) {
+		return "", fmt.Errorf("render: unknown protocol %q (known: %s)", args["protocol"], strings.Join(renderProtocols, ", "))
+	}
+
+	d.mu.Lock()
+	cfg, fn := d.appCfg, d.bannerData
+	d.mu.Unlock()
+	if cfg == nil || fn == nil {
+		return "", fmt.Errorf("render: banner rendering is not available")
+	}
+
+	sections := parseSections(args["sections"])
+	known := slices.Clone(config.BannerSections)
+	for _, cc := range cfg.Collectors.Commands {
+		known = append(known, cc.Name)
+	}
+	for _, s := range sections {
+		if !slices.Contains(known, s) {
+			return "", fmt.Errorf("render: unknown section %q (known: %s)", s, strings.Join(known, ", "))
+		}
+	}
+
+	entry, err := d.renderBanner(cfg, fn, warmSize{width: width, height: height, protocol: protocol}, sections)
+	if entry == nil {
+		return "", fmt.Errorf("render: %w", err)
+	}
+	if err != nil {
+		slog.Error("daemon: cache rendered banner", "err", err)
+	}
+	return bannerEntryToJSON(entry)
+}
+
+// parseSections splits a comma-separated section list into sorted,
+// lowercase, unique widget IDs.
+func parseSections(s string) []string {
+	var out []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.ToLower(strings.TrimSpace(f)); f != "" {
+			out = append(out, f)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// renderBanner returns the banner for size limited to sections, reusing
+// the cached entry when it was rendered from the same inputs and otherwise
+// rendering and caching a new one. A failure to write the cache is
+// returned alongside the rendered entry.
+func (d *Daemon) renderBanner(cfg *config.Config, fn BannerDataFunc, size warmSize, sections []string) (*BannerEntry, error) {
+	data := fn(cfg, size.width, size.height)
+	if len(sections) > 0 {
+		var widgets []banner.WidgetData
+		for _, w := range data.Widgets {
+			if slices.Contains(sections, w.ID) {
+				widgets = append(widgets, w)
+			}
+		}
+		if len(widgets) == 0 {
+			return nil, fmt.Errorf("no banner sections match %s", strings.Join(sections, ","))
+		}
+		data.Widgets = widgets
+	}
+
+	preset := banner.SelectPreset(size.width, size.height)
+	inputs := banner.CacheKey(data, preset)
+	if entry, ok := d.banner.GetSections(size.width, size.height, size.protocol, sections); ok && entry.Inputs == inputs {
+		return entry, nil
+	}
+	entry := &BannerEntry{
+		Rendered:  banner.Render(data, preset),
+		Width:     size.width,
+		Height:    size.height,
+		Protocol:  size.protocol,
+		Timestamp: time.Now(),
+		Inputs:    inputs,
+		Sections:  sections,
+	}
+	return entry, d.banner.Put(entry)
+}
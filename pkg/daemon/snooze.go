@@ -0,0 +1,161 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
+)
+
+// SnoozeFile is the cache-directory file recording an active snooze. The
+// daemon reloads it on start, and the banner reads it to mute warnings.
+const SnoozeFile = "snooze.json"
+
+// SnoozeResult is the JSON response to the SNOOZE IPC command.
+type SnoozeResult struct {
+	Status  string     `json:"status"`
+	Message string     `json:"message"`
+	Until   *time.Time `json:"snoozed_until,omitempty"`
+}
+
+type snoozeState struct {
+	Until time.Time `json:"until"`
+}
+
+// ReadSnooze returns the snooze end time recorded in cacheDir, or the zero
+// time when none is recorded. An expired snooze is returned as-is; callers
+// compare it against the current time.
+func ReadSnooze(cacheDir string) time.Time {
+	data, err := os.ReadFile(filepath.Join(cacheDir, SnoozeFile))
+	if err != nil {
+		return time.Time{}
+	}
+	var st snoozeState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return time.Time{}
+	}
+	return st.Until
+}
+
+// writeSnooze persists until to cacheDir atomically. The zero time removes
+// the file.
+func writeSnooze(cacheDir string, until time.Time) error {
+	path := filepath.Join(cacheDir, SnoozeFile)
+	if until.IsZero() {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove snooze: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(cacheDir, cache.DirMode); err != nil {
+		return fmt.Errorf("create snooze directory: %w", err)
+	}
+	data, err := json.Marshal(snoozeState{Until: until})
+	if err != nil {
+		return fmt.Errorf("marshal snooze: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, cache.FileMode); err != nil {
+		return fmt.Errorf("write snooze: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename snooze: %w", err)
+	}
+	return nil
+}
+
+// parseSnoozeDuration parses the SNOOZE argument. "off" and "0" clear an
+// active snooze and return zero.
+func parseSnoozeDuration(arg string) (time.Duration, error) {
+	switch strings.ToLower(arg) {
+	case "":
+		return 0, fmt.Errorf("usage: SNOOZE <duration> (e.g. 2h), or SNOOZE off")
+	case "off", "0":
+		return 0, nil
+	}
+	dur, err := time.ParseDuration(arg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid snooze duration %q: %w", arg, err)
+	}
+	if dur < 0 {
+		return 0, fmt.Errorf("invalid snooze duration %q: must not be negative", arg)
+	}
+	return dur, nil
+}
+
+// stateDir returns the directory holding the collector cache and snooze
+// file: the attached cache dir, else the configured one, else DataDir.
+func (d *Daemon) stateDir() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cacheDir != "" {
+		return d.cacheDir
+	}
+	if d.appCfg != nil && d.appCfg.General.CacheDir != "" {
+		return d.appCfg.General.CacheDir
+	}
+	return d.cfg.DataDir
+}
+
+// loadSnooze restores a snooze persisted by a previous run.
+func (d *Daemon) loadSnooze() {
+	until := ReadSnooze(d.stateDir())
+	d.mu.Lock()
+	d.snoozeUntil = until
+	d.mu.Unlock()
+}
+
+// snooze handles the SNOOZE command: it suppresses status-change
+// notifications until now+duration, persists the end time, and refreshes
+// the health file so HEALTH reports it straight away.
+func (d *Daemon) snooze(arg string) (string, error) {
+	dur, err := parseSnoozeDuration(arg)
+	if err != nil {
+		return "", err
+	}
+	var until time.Time
+	if dur > 0 {
+		until = time.Now().Add(dur).Truncate(time.Second)
+	}
+	if err := writeSnooze(d.stateDir(), until); err != nil {
+		return "", err
+	}
+	d.mu.Lock()
+	d.snoozeUntil = until
+	d.mu.Unlock()
+	_ = d.WriteHealth()
+
+	res := SnoozeResult{Status: "ok", Message: "snooze cleared"}
+	if !until.IsZero() {
+		res.Message = "notifications snoozed until " + until.Format(time.RFC3339)
+		res.Until = &until
+	}
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal snooze result: %w", err)
+	}
+	return string(data), nil
+}
+
+// snoozedUntil returns the active snooze end time, if one is in effect.
+func (d *Daemon) snoozedUntil(now time.Time) (time.Time, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.snoozeUntil, now.Before(d.snoozeUntil)
+}
+
+// applySnooze records the snooze state on a health snapshot.
+func (d *Daemon) applySnooze(h *HealthStatus, now time.Time) {
+	until, ok := d.snoozedUntil(now)
+	h.Snoozed = ok
+	h.SnoozedUntil = nil
+	if ok {
+		h.SnoozedUntil = &until
+	}
+}
@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// ToggleResult is the JSON response to the ENABLE and DISABLE IPC commands.
+type ToggleResult struct {
+	Status   string   `json:"status"`
+	Message  string   `json:"message"`
+	Enabled  []string `json:"enabled"`
+	Disabled []string `json:"disabled"`
+}
+
+// setCollectorEnabled handles ENABLE and DISABLE: it switches a collector on
+// or off in the running daemon and reports the resulting sets. The change
+// is in memory only, so a restart restores the configured collectors.
+func (d *Daemon) setCollectorEnabled(name string, enabled bool) (string, error) {
+	verb := "DISABLE"
+	if enabled {
+		verb = "ENABLE"
+	}
+	if name == "" {
+		return "", fmt.Errorf("usage: %s <collector>", verb)
+	}
+
+	d.mu.Lock()
+	reg, runner := d.registry, d.runner
+	d.mu.Unlock()
+	if reg == nil || runner == nil {
+		return "", fmt.Errorf("collectors not started")
+	}
+	if _, ok := reg.Get(name); !ok {
+		return "", fmt.Errorf("unknown collector: %s (registered: %v)", name, reg.List())
+	}
+	if err := runner.SetEnabled(name, enabled); err != nil {
+		return "", err
+	}
+	_ = d.WriteHealth()
+
+	res := ToggleResult{Status: "ok", Disabled: runner.Disabled()}
+	for _, n := range reg.List() {
+		if runner.Enabled(n) {
+			res.Enabled = append(res.Enabled, n)
+		}
+	}
+	if enabled {
+		res.Message = name + " enabled"
+	} else {
+		res.Message = name + " disabled until ENABLE or restart"
+	}
+	slog.Info("daemon: "+strings.ToLower(verb)+"d collector", "collector", name)
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("marshal toggle result: %w", err)
+	}
+	return string(data), nil
+}
+
+// disabledCollectors returns the collectors currently switched off with
+// DISABLE, or nil when none are or collectors have not started.
+func (d *Daemon) disabledCollectors() []string {
+	d.mu.Lock()
+	runner := d.runner
+	d.mu.Unlock()
+	if runner == nil {
+		return nil
+	}
+	if names := runner.Disabled(); len(names) > 0 {
+		return names
+	}
+	return nil
+}
+
+// applyQuietHours hands the quiet hours configured in cfg to runner. An
+// invalid window is logged and ignored so the daemon keeps its normal
+// intervals.
+func applyQuietHours(runner *collectors.Runner, cfg *config.Config) {
+	if !cfg.General.QuietHours.Enabled() {
+		return
+	}
+	qc := cfg.General.QuietHours
+	q, err := collectors.ParseQuietHours(qc.Start, qc.End, qc.Days, qc.Factor, qc.Pause)
+	if err != nil {
+		slog.Warn("daemon: ignoring quiet_hours", "err", err)
+		return
+	}
+	runner.SetQuietHours(q)
+}
+
+// quietActive reports whether the running collectors are in quiet hours.
+func (d *Daemon) quietActive(now time.Time) bool {
+	d.mu.Lock()
+	runner := d.runner
+	d.mu.Unlock()
+	return runner != nil && runner.QuietActive(now)
+}
@@ -0,0 +1,111 @@
+package daemon
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/banner"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// defaultWarmProtocol is the graphics protocol of a banner.warm_sizes entry
+// that names none: half blocks work in every terminal.
+const defaultWarmProtocol = "halfblocks"
+
+// BannerDataFunc builds the banner widgets for a width x height terminal
+// from the collector cache. The widgets are assembled by the main package,
+// so the daemon is handed this function instead of building them itself.
+type BannerDataFunc func(cfg *config.Config, width, height int) banner.BannerData
+
+// SetBannerData sets the function the daemon pre-renders banners with after
+// collecting, for the sizes in banner.warm_sizes. Without one no banners are
+// pre-rendered. Must be called before Start().
+func (d *Daemon) SetBannerData(fn BannerDataFunc) {
+	d.bannerData = fn
+}
+
+// BannerWarningsFunc returns a message for each banner setting in cfg that
+// is invalid and falls back to its default. Like BannerDataFunc it is
+// supplied by the main package.
+type BannerWarningsFunc func(cfg *config.Config) []string
+
+// SetBannerWarnings sets the function whose warnings the daemon logs each
+// time it loads a config, at start and on reload, so building banners can
+// stay quiet. Must be called before Start().
+func (d *Daemon) SetBannerWarnings(fn BannerWarningsFunc) {
+	d.bannerWarnings = fn
+}
+
+// logBannerWarnings logs the banner warnings for cfg. It is a no-op
+// without a BannerWarningsFunc.
+func (d *Daemon) logBannerWarnings(cfg *config.Config) {
+	if d.bannerWarnings == nil || cfg == nil {
+		return
+	}
+	for _, w := range d.bannerWarnings(cfg) {
+		slog.Warn("daemon: banner config", "warning", w)
+	}
+}
+
+// warmSize is one terminal configuration to pre-render a banner for.
+type warmSize struct {
+	width, height int
+	protocol      string
+}
+
+// parseWarmSize parses a banner.warm_sizes entry: "WxH" or "WxH/protocol".
+func parseWarmSize(s string) (warmSize, error) {
+	dims, protocol, _ := strings.Cut(strings.TrimSpace(s), "/")
+	if protocol == "" {
+		protocol = defaultWarmProtocol
+	}
+	if !slices.Contains(renderProtocols, protocol) {
+		return warmSize{}, fmt.Errorf("warm size %q: unknown protocol %q", s, protocol)
+	}
+	w, h, ok := strings.Cut(dims, "x")
+	if !ok {
+		return warmSize{}, fmt.Errorf("warm size %q: want WxH or WxH/protocol", s)
+	}
+	width, err := strconv.Atoi(w)
+	if err != nil || width <= 0 {
+		return warmSize{}, fmt.Errorf("warm size %q: invalid width", s)
+	}
+	height, err := strconv.Atoi(h)
+	if err != nil || height <= 0 {
+		return warmSize{}, fmt.Errorf("warm size %q: invalid height", s)
+	}
+	return warmSize{width: width, height: height, protocol: protocol}, nil
+}
+
+// warmBanners pre-renders a banner into the banner cache for each size in
+// banner.warm_sizes, so BANNER requests for common terminals hit the cache.
+// A size whose cached entry was rendered from the same inputs is skipped.
+// It runs after collector updates are stored and is a no-op without a
+// BannerDataFunc.
+func (d *Daemon) warmBanners() {
+	d.mu.Lock()
+	cfg, fn := d.appCfg, d.bannerData
+	d.mu.Unlock()
+	if cfg == nil || fn == nil {
+		return
+	}
+
+	var sizes []warmSize
+	for _, s := range cfg.Banner.WarmSizes {
+		size, err := parseWarmSize(s)
+		if err != nil {
+			slog.Warn("daemon: banner.warm_sizes", "err", err)
+			continue
+		}
+		sizes = append(sizes, size)
+	}
+	d.banner.SetLimit(maxRenderedBanners, sizes)
+	for _, size := range sizes {
+		if _, err := d.renderBanner(cfg, fn, size, nil); err != nil {
+			slog.Error("daemon: warm banner", "size", bannerKey(size.width, size.height, size.protocol, nil), "err", err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+)
+
+// weeklyResetJitter is how far an organization's reported seven-day reset
+// time may move between collections without counting as a new window, so
+// small corrections from the API never announce a reset.
+const weeklyResetJitter = time.Hour
+
+// weeklyResets returns the names of the organizations in r, among those
+// opted in with notify_weekly_reset, whose seven-day window has restarted
+// since the previous report. A reset counts once its last known time has
+// passed and the report carries a reset time at least weeklyResetJitter
+// later; the new time then becomes the one tracked, so each window is
+// announced at most once. The first report for an organization only
+// records its reset time.
+func (d *Daemon) weeklyResets(r *claude.UsageReport, now time.Time) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.appCfg == nil {
+		return nil
+	}
+	optIn := make(map[string]bool)
+	for _, a := range d.appCfg.Collectors.Claude.Accounts {
+		if a.NotifyWeeklyReset {
+			optIn[a.Name] = true
+		}
+	}
+
+	var names []string
+	for _, o := range r.Orgs {
+		if !optIn[o.Name] || o.SevenDay == nil || o.SevenDay.ResetsAt.IsZero() {
+			continue
+		}
+		next := o.SevenDay.ResetsAt
+		last, ok := d.weeklyResetAt[o.Name]
+		if ok && (now.Before(last) || next.Sub(last) < weeklyResetJitter) {
+			continue
+		}
+		if d.weeklyResetAt == nil {
+			d.weeklyResetAt = make(map[string]time.Time)
+		}
+		d.weeklyResetAt[o.Name] = next
+		if ok {
+			names = append(names, o.Name)
+		}
+	}
+	return names
+}
+
+// announceWeeklyResets posts a notification for every opted-in Claude
+// organization whose weekly limit reset since the last update. Resets seen
+// while snoozed are tracked but not announced.
+func (d *Daemon) announceWeeklyResets(ctx context.Context, u collectors.Update, n *Notifier, snoozed bool) {
+	r, ok := u.Data.(*claude.UsageReport)
+	if !ok || r == nil {
+		return
+	}
+	for _, name := range d.weeklyResets(r, time.Now()) {
+		if snoozed {
+			continue
+		}
+		if err := n.Announce(ctx, "Claude weekly limit reset for "+name); err != nil {
+			slog.Error("daemon: notify", "err", err)
+		}
+	}
+}
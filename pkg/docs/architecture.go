@@ -324,7 +324,7 @@ func dcAllPackages() []PackageInfo {
 			Name:          "docs",
 			Path:          "pkg/docs",
 			Description:   "Documentation generator: architecture docs, config reference, shell guides, man pages, changelog.",
-			Dependencies:  nil,
+			Dependencies:  []string{"config"},
 			ExportedTypes: []string{"DocGenerator", "Section", "ArchDoc", "ConfigRef", "ShellGuide", "ManPage", "Changelog"},
 		},
 	}
@@ -49,16 +49,23 @@ func dcGenerateConfigRef() *ConfigRef {
 	return &ConfigRef{
 		Sections: []ConfigSection{
 			dcGeneralSection(),
+			dcGeneralQuietHoursSection(),
 			dcLayoutSection(),
 			dcCollectorsSysMetricsSection(),
 			dcCollectorsTailscaleSection(),
 			dcCollectorsK8sSection(),
 			dcCollectorsClaudeSection(),
 			dcCollectorsBillingSection(),
+			dcCollectorsBillingCivoSection(),
+			dcCollectorsBillingDOSection(),
+			dcCollectorsCommandSection(),
 			dcImageSection(),
 			dcThemeSection(),
 			dcShellSection(),
 			dcBannerSection(),
+			dcNotifySection(),
+			dcStatusSection(),
+			dcDisplaySection(),
 		},
 	}
 }
@@ -69,7 +76,10 @@ func dcRenderConfigMarkdown(ref *ConfigRef) string {
 
 	b.WriteString("# Configuration Reference\n\n")
 	b.WriteString("prompt-pulse v2 uses TOML configuration.\n\n")
-	b.WriteString("Config file location: `$XDG_CONFIG_HOME/prompt-pulse/config.toml`\n\n")
+	b.WriteString("Config file location: `$XDG_CONFIG_HOME/prompt-pulse/config.toml`, layered over " +
+		"`/etc/prompt-pulse/config.toml` when present. A top-level `include = [\"base.toml\"]` " +
+		"reads other files first; later files override earlier ones key by key, and Claude " +
+		"accounts and command collectors merge by name.\n\n")
 
 	for _, s := range ref.Sections {
 		b.WriteString(fmt.Sprintf("## `[%s]`\n\n", s.Name))
@@ -123,7 +133,7 @@ func dcGeneralSection() ConfigSection {
 				Name:        "cache_dir",
 				Type:        "string",
 				Default:     "$XDG_CACHE_HOME/prompt-pulse",
-				Description: "Override the default cache directory path",
+				Description: "Override the default cache directory path. The directory is created with mode 0700 and its files with 0600",
 				Example:     `cache_dir = "/tmp/ppulse-cache"`,
 			},
 			{
@@ -140,6 +150,106 @@ func dcGeneralSection() ConfigSection {
 				Description: "How long time-series data is retained in memory",
 				Example:     `data_retention = "10m"`,
 			},
+			{
+				Name:        "http_addr",
+				Type:        "string",
+				Default:     "",
+				Description: "Serve the daemon's HTTP JSON API on this address (host defaults to 127.0.0.1; empty = off). Requests must use a loopback Host or this host; POSTs need an X-Prompt-Pulse header",
+				Example:     `http_addr = ":9090"`,
+			},
+			{
+				Name:        "http_dashboard",
+				Type:        "bool",
+				Default:     "false",
+				Description: "Serve a web dashboard at / of the HTTP API (loopback http_addr only; put it behind tailscale serve for remote access)",
+				Example:     "http_dashboard = true",
+			},
+			{
+				Name:        "http_tls_cert",
+				Type:        "string",
+				Default:     "",
+				Description: "PEM certificate the HTTP API serves HTTPS with; needs http_tls_key (empty = plain HTTP)",
+				Example:     `http_tls_cert = "/etc/prompt-pulse/server.crt"`,
+			},
+			{
+				Name:        "http_tls_key",
+				Type:        "string",
+				Default:     "",
+				Description: "PEM private key for http_tls_cert",
+				Example:     `http_tls_key = "/etc/prompt-pulse/server.key"`,
+			},
+			{
+				Name:        "http_client_ca",
+				Type:        "string",
+				Default:     "",
+				Description: "PEM CA certificates for mutual TLS: only clients with a certificate signed by one of them may call the HTTP API",
+				Example:     `http_client_ca = "/etc/prompt-pulse/clients.crt"`,
+			},
+			{
+				Name:        "redact_secrets",
+				Type:        "bool",
+				Default:     "true",
+				Description: "Mask API keys and token-like strings as ****last4 in -diagnose output and daemon logs",
+				Example:     "redact_secrets = false",
+			},
+			{
+				Name:        "circuit_threshold",
+				Type:        "int",
+				Default:     "5",
+				Description: "Consecutive failed collections after which the daemon skips a collector for circuit_cooldown, serving its cached data until stale (0 = never skip). HEALTH reports each collector's circuit as closed, open or half-open",
+				Example:     "circuit_threshold = 3",
+			},
+			{
+				Name:        "circuit_cooldown",
+				Type:        "duration",
+				Default:     "10m",
+				Description: "How long a collector whose circuit opened is skipped before it is tried again",
+				Example:     `circuit_cooldown = "30m"`,
+			},
+		},
+	}
+}
+
+func dcGeneralQuietHoursSection() ConfigSection {
+	return ConfigSection{
+		Name:        "general.quiet_hours",
+		Description: "A daily window in local time during which the daemon polls less often. Off unless start and end are set. HEALTH reports quiet_hours while it is in effect. Readers stretch cache_ttl to match, so slowed or paused data is not shown as stale",
+		Fields: []ConfigField{
+			{
+				Name:        "start",
+				Type:        "string",
+				Default:     "",
+				Description: "Start of the window as HH:MM",
+				Example:     `start = "23:00"`,
+			},
+			{
+				Name:        "end",
+				Type:        "string",
+				Default:     "",
+				Description: "End of the window as HH:MM; an end before the start runs past midnight",
+				Example:     `end = "07:00"`,
+			},
+			{
+				Name:        "days",
+				Type:        "[]string",
+				Default:     "[]",
+				Description: "Days the window starts on (mon, tue, ... or full names); empty means every day",
+				Example:     `days = ["mon", "tue", "wed", "thu", "fri"]`,
+			},
+			{
+				Name:        "factor",
+				Type:        "float",
+				Default:     "4",
+				Description: "Multiplier applied to every collector's interval inside the window",
+				Example:     `factor = 4`,
+			},
+			{
+				Name:        "pause",
+				Type:        "[]string",
+				Default:     "[]",
+				Description: "Collectors that do not run at all inside the window",
+				Example:     `pause = ["billing", "claude"]`,
+			},
 		},
 	}
 }
@@ -179,6 +289,55 @@ func dcCollectorsSysMetricsSection() ConfigSection {
 				Description: "Collection interval for system metrics",
 				Example:     `interval = "1s"`,
 			},
+			{
+				Name:        "cache_ttl",
+				Type:        "duration",
+				Default:     "max(2×interval, 10m)",
+				Description: "How long cached data is shown before it is marked stale",
+				Example:     `cache_ttl = "5s"`,
+			},
+			{
+				Name:        "cpu_warn",
+				Type:        "float",
+				Default:     "85",
+				Description: "Percent CPU usage at which the node's status becomes warning",
+				Example:     `cpu_warn = 85`,
+			},
+			{
+				Name:        "cpu_crit",
+				Type:        "float",
+				Default:     "95",
+				Description: "Percent CPU usage at which the node's status becomes critical",
+				Example:     `cpu_crit = 95`,
+			},
+			{
+				Name:        "memory_warn",
+				Type:        "float",
+				Default:     "80",
+				Description: "Percent memory usage at which the node's status becomes warning",
+				Example:     `memory_warn = 80`,
+			},
+			{
+				Name:        "memory_crit",
+				Type:        "float",
+				Default:     "92",
+				Description: "Percent memory usage at which the node's status becomes critical",
+				Example:     `memory_crit = 92`,
+			},
+			{
+				Name:        "disk_warn",
+				Type:        "float",
+				Default:     "85",
+				Description: "Percent usage of any one disk at which the node's status becomes warning",
+				Example:     `disk_warn = 85`,
+			},
+			{
+				Name:        "disk_crit",
+				Type:        "float",
+				Default:     "95",
+				Description: "Percent usage of any one disk at which the node's status becomes critical",
+				Example:     `disk_crit = 95`,
+			},
 		},
 	}
 }
@@ -202,6 +361,27 @@ func dcCollectorsTailscaleSection() ConfigSection {
 				Description: "Collection interval for Tailscale status",
 				Example:     `interval = "30s"`,
 			},
+			{
+				Name:        "cache_ttl",
+				Type:        "duration",
+				Default:     "max(2×interval, 10m)",
+				Description: "How long cached data is shown before it is marked stale",
+				Example:     `cache_ttl = "1m"`,
+			},
+			{
+				Name:        "address_display",
+				Type:        "string",
+				Default:     "ipv4",
+				Description: "Node address shown on the banner: ipv4, ipv6, or dnsname (MagicDNS name); falls back to IPv4 when the node lacks the chosen address",
+				Example:     `address_display = "dnsname"`,
+			},
+			{
+				Name:        "stale_after",
+				Type:        "duration",
+				Default:     "10m",
+				Description: "Mark a node stale when it reports online but has not been seen for this long (usually a wedged tailscaled)",
+				Example:     `stale_after = "15m"`,
+			},
 		},
 	}
 }
@@ -225,6 +405,13 @@ func dcCollectorsK8sSection() ConfigSection {
 				Description: "Collection interval for Kubernetes status",
 				Example:     `interval = "60s"`,
 			},
+			{
+				Name:        "cache_ttl",
+				Type:        "duration",
+				Default:     "max(2×interval, 10m)",
+				Description: "How long cached data is shown before it is marked stale",
+				Example:     `cache_ttl = "2m"`,
+			},
 			{
 				Name:        "contexts",
 				Type:        "[]string",
@@ -239,6 +426,34 @@ func dcCollectorsK8sSection() ConfigSection {
 				Description: "Namespaces to monitor (empty = all namespaces)",
 				Example:     `namespaces = ["default", "kube-system"]`,
 			},
+			{
+				Name:        "pod_pressure_threshold",
+				Type:        "float",
+				Default:     "90",
+				Description: "Percent of schedulable pod capacity that raises a pod-pressure warning",
+				Example:     `pod_pressure_threshold = 90`,
+			},
+			{
+				Name:        "kubeconfig",
+				Type:        "string",
+				Default:     `""`,
+				Description: "Kubeconfig path (empty = $KUBECONFIG or ~/.kube/config)",
+				Example:     `kubeconfig = "/home/user/.kube/config"`,
+			},
+			{
+				Name:        "context_timeout",
+				Type:        "duration",
+				Default:     "10s",
+				Description: "Per-context collection timeout; contexts are collected concurrently",
+				Example:     `context_timeout = "10s"`,
+			},
+			{
+				Name:        "overrides.<context>",
+				Type:        "table",
+				Default:     "{}",
+				Description: "Per-context kubeconfig and namespaces, replacing the collector-wide values, plus an optional dashboard_url linked from the cluster name",
+				Example:     `overrides.lab = { kubeconfig = "/home/user/.kube/lab.yaml", namespaces = ["team"] }`,
+			},
 		},
 	}
 }
@@ -262,6 +477,13 @@ func dcCollectorsClaudeSection() ConfigSection {
 				Description: "Collection interval for Claude usage data",
 				Example:     `interval = "5m"`,
 			},
+			{
+				Name:        "cache_ttl",
+				Type:        "duration",
+				Default:     "max(2×interval, 10m)",
+				Description: "How long cached data is shown before it is marked stale",
+				Example:     `cache_ttl = "10m"`,
+			},
 			{
 				Name:        "admin_key",
 				Type:        "string",
@@ -269,6 +491,13 @@ func dcCollectorsClaudeSection() ConfigSection {
 				Description: "Anthropic Admin API key (prefer ANTHROPIC_ADMIN_KEY env var)",
 				Example:     `# admin_key = "sk-ant-admin-..."  # prefer env var`,
 			},
+			{
+				Name:        "history_points",
+				Type:        "int",
+				Default:     "48",
+				Description: "Cost samples kept per account for banner and starship sparklines",
+				Example:     `history_points = 48`,
+			},
 		},
 	}
 }
@@ -292,6 +521,161 @@ func dcCollectorsBillingSection() ConfigSection {
 				Description: "Collection interval for billing data",
 				Example:     `interval = "15m"`,
 			},
+			{
+				Name:        "cache_ttl",
+				Type:        "duration",
+				Default:     "max(2×interval, 10m)",
+				Description: "How long cached data is shown before it is marked stale",
+				Example:     `cache_ttl = "1h"`,
+			},
+			{
+				Name:        "budget_usd",
+				Type:        "float",
+				Default:     "0",
+				Description: "Monthly cloud budget for percentage and pace display (0 = none)",
+				Example:     `budget_usd = 200.0`,
+			},
+			{
+				Name:        "anomaly_sigma",
+				Type:        "float",
+				Default:     "3",
+				Description: "Standard deviations above the recent daily mean that flag a spend spike",
+				Example:     `anomaly_sigma = 3.0`,
+			},
+			{
+				Name:        "projected_overage",
+				Type:        "bool",
+				Default:     "true",
+				Description: "Warn when spend is under budget but the month-end forecast is over it",
+				Example:     `projected_overage = true`,
+			},
+			{
+				Name:        "projected_overage_threshold",
+				Type:        "float",
+				Default:     "10",
+				Description: "Percent of budget the forecast must exceed it by before warning",
+				Example:     `projected_overage_threshold = 10.0`,
+			},
+		},
+	}
+}
+
+func dcCollectorsBillingCivoSection() ConfigSection {
+	return ConfigSection{
+		Name:        "collectors.billing.civo",
+		Description: "Civo spend, from the charges API or estimated from clusters and instances.",
+		Fields: []ConfigField{
+			{
+				Name:        "enabled",
+				Type:        "bool",
+				Default:     "false",
+				Description: "Collect Civo spend",
+				Example:     `enabled = true`,
+			},
+			{
+				Name:        "api_key",
+				Type:        "string",
+				Default:     "",
+				Description: "Civo API key (prefer CIVO_TOKEN env var)",
+				Example:     `# api_key = "..."  # prefer env var`,
+			},
+			{
+				Name:        "region",
+				Type:        "string",
+				Default:     "",
+				Description: "Civo region code (prefer CIVO_REGION env var)",
+				Example:     `region = "nyc1"`,
+			},
+			{
+				Name:        "credits",
+				Type:        "float",
+				Default:     "",
+				Description: "Monthly credit in USD; budget math and the banner use spend after credits, floored at $0",
+				Example:     `credits = 50.0`,
+			},
+		},
+	}
+}
+
+func dcCollectorsBillingDOSection() ConfigSection {
+	return ConfigSection{
+		Name:        "collectors.billing.digitalocean",
+		Description: "DigitalOcean spend from the account balance API.",
+		Fields: []ConfigField{
+			{
+				Name:        "enabled",
+				Type:        "bool",
+				Default:     "false",
+				Description: "Collect DigitalOcean spend",
+				Example:     `enabled = true`,
+			},
+			{
+				Name:        "api_key",
+				Type:        "string",
+				Default:     "",
+				Description: "DigitalOcean API token (prefer DIGITALOCEAN_TOKEN env var)",
+				Example:     `# api_key = "..."  # prefer env var`,
+			},
+			{
+				Name:        "credits",
+				Type:        "float",
+				Default:     "",
+				Description: "Monthly credit in USD; budget math and the banner use spend after credits, floored at $0",
+				Example:     `credits = 100.0`,
+			},
+		},
+	}
+}
+
+func dcCollectorsCommandSection() ConfigSection {
+	return ConfigSection{
+		Name:        "collectors.command",
+		Description: "External programs run as collectors, one [[collectors.command]] table each. The program must print a JSON object such as {\"title\": \"Build farm\", \"data\": {\"queue\": 4}} on stdout; the data is cached under the collector name and shown on the banner as key/value lines. A timeout, non-zero exit, or invalid JSON is a collector error.",
+		Fields: []ConfigField{
+			{
+				Name:        "name",
+				Type:        "string",
+				Default:     `""`,
+				Description: "Collector and cache key name (lowercase letters, digits, '-' and '_'; not a built-in collector name)",
+				Required:    true,
+				Example:     `name = "buildfarm"`,
+			},
+			{
+				Name:        "exec",
+				Type:        "string",
+				Default:     `""`,
+				Description: "Program to run, directly rather than through a shell",
+				Required:    true,
+				Example:     `exec = "/usr/local/bin/buildfarm-status"`,
+			},
+			{
+				Name:        "args",
+				Type:        "[]string",
+				Default:     "[]",
+				Description: "Arguments passed to the program",
+				Example:     `args = ["--json"]`,
+			},
+			{
+				Name:        "interval",
+				Type:        "duration",
+				Default:     "5m",
+				Description: "How often the program runs",
+				Example:     `interval = "5m"`,
+			},
+			{
+				Name:        "timeout",
+				Type:        "duration",
+				Default:     "10s",
+				Description: "Per-run timeout; the program is killed when it expires",
+				Example:     `timeout = "10s"`,
+			},
+			{
+				Name:        "cache_ttl",
+				Type:        "duration",
+				Default:     "max(2×interval, 10m)",
+				Description: "How long cached output is shown before it is marked stale",
+				Example:     `cache_ttl = "15m"`,
+			},
 		},
 	}
 }
@@ -389,6 +773,34 @@ func dcShellSection() ConfigSection {
 				Description: "Use pre-rendered cache for sub-millisecond banner display",
 				Example:     `instant_banner = true`,
 			},
+			{
+				Name:        "starship_claude_weekly",
+				Type:        "bool",
+				Default:     "false",
+				Description: "Show monthly/seven-day Claude budget utilization in -starship (weekly figure dropped first when narrow)",
+				Example:     `starship_claude_weekly = true`,
+			},
+			{
+				Name:        "starship_memo_ttl",
+				Type:        "duration",
+				Default:     "2s",
+				Description: "Reuse the previous -starship output for this long before re-reading the collector cache, so busy prompts stay fast; 0 disables",
+				Example:     `starship_memo_ttl = "5s"`,
+			},
+			{
+				Name:        "starship_loading",
+				Type:        "bool",
+				Default:     "false",
+				Description: "Show a dim \"…\" placeholder in -starship for modules with no cached data yet instead of hiding them; stale data still hides the module",
+				Example:     `starship_loading = true`,
+			},
+			{
+				Name:        "show_banner_on",
+				Type:        "string",
+				Default:     "always",
+				Description: "Show the startup banner only in SSH sessions (ssh), only in local shells (local), or in both (always). SSH sessions are detected by SSH_CONNECTION or SSH_TTY",
+				Example:     `show_banner_on = "ssh"`,
+			},
 		},
 	}
 }
@@ -426,6 +838,306 @@ func dcBannerSection() ConfigSection {
 				Description: "Minimum terminal width for ultra-wide banner mode",
 				Example:     `ultrawide_min_width = 200`,
 			},
+			{
+				Name:        "warm_sizes",
+				Type:        "[]string",
+				Default:     `["80x24", "120x40", "200x50"]`,
+				Description: "Terminal sizes the daemon pre-renders a banner for after collecting, as WxH or WxH/protocol (protocol defaults to halfblocks); empty disables pre-rendering",
+				Example:     `warm_sizes = ["80x24", "120x40/kitty"]`,
+			},
+			{
+				Name:        "sections",
+				Type:        "[]string",
+				Default:     "[]",
+				Description: "Banner sections to show, in display order: status, system, tailscale, k8s, claude, billing, or a command collector name; unlisted sections are not read; empty shows all",
+				Example:     `sections = ["claude", "billing", "status"]`,
+			},
+			{
+				Name:        "hide_sections",
+				Type:        "[]string",
+				Default:     "[]",
+				Description: "Banner sections never to show, e.g. k8s on a machine without clusters",
+				Example:     `hide_sections = ["k8s"]`,
+			},
+		},
+	}
+}
+
+func dcNotifySection() ConfigSection {
+	return ConfigSection{
+		Name:        "notify",
+		Description: "Slack or Discord webhook notifications when the daemon's overall status level changes.",
+		Fields: []ConfigField{
+			{
+				Name:        "webhook_url",
+				Type:        "string",
+				Default:     "",
+				Description: "Incoming webhook URL (empty disables notifications)",
+				Example:     `webhook_url = "https://hooks.slack.com/services/..."`,
+			},
+			{
+				Name:        "kind",
+				Type:        "string",
+				Default:     "",
+				Description: "Payload format: slack or discord (empty detects from the URL host)",
+				Example:     `kind = "slack"`,
+			},
+			{
+				Name:        "min_level",
+				Type:        "string",
+				Default:     "warning",
+				Description: "Lowest level that notifies: warning or critical",
+				Example:     `min_level = "critical"`,
+			},
+			{
+				Name:        "cooldown",
+				Type:        "duration",
+				Default:     "15m",
+				Description: "Minimum time before a de-escalation or recovery is reported",
+				Example:     `cooldown = "15m"`,
+			},
+			{
+				Name:        "notify_recovery",
+				Type:        "bool",
+				Default:     "true",
+				Description: "Send a message when status returns to healthy",
+				Example:     `notify_recovery = true`,
+			},
+		},
+	}
+}
+
+func dcStatusSection() ConfigSection {
+	return ConfigSection{
+		Name:        "status",
+		Description: "How each subsystem weighs in the overall status level used by notifications, the statusline, and -explain.",
+		Fields: []ConfigField{
+			{
+				Name:        "ignore",
+				Type:        "[]string",
+				Default:     "[]",
+				Description: "Subsystems that never affect the status level: claude, billing, k8s, tailscale, or sysmetrics",
+				Example:     `ignore = ["billing"]`,
+			},
+			{
+				Name:        "claude_warn",
+				Type:        "float",
+				Default:     "0",
+				Description: "Claude utilization percentage that warns, for every account and organization (0 keeps each one's warn_threshold)",
+				Example:     `claude_warn = 70`,
+			},
+			{
+				Name:        "claude_crit",
+				Type:        "float",
+				Default:     "0",
+				Description: "Claude utilization percentage that is critical, for every account and organization (0 keeps each one's crit_threshold)",
+				Example:     `claude_crit = 85`,
+			},
+			{
+				Name:        "billing_warn",
+				Type:        "float",
+				Default:     "80",
+				Description: "Percentage of the cloud budget at which spend warns",
+				Example:     `billing_warn = 80`,
+			},
+			{
+				Name:        "billing_crit",
+				Type:        "float",
+				Default:     "100",
+				Description: "Percentage of the cloud budget at which spend is critical",
+				Example:     `billing_crit = 100`,
+			},
+			{
+				Name:        "weights.<subsystem>",
+				Type:        "table",
+				Default:     "{}",
+				Description: "Most severe level a subsystem's findings can give the status: healthy, warning, or critical (unlisted subsystems count in full)",
+				Example:     `weights.k8s = "warning"`,
+			},
+			{
+				Name:        "k8s_pod_pressure",
+				Type:        "float",
+				Default:     "0",
+				Description: "Pod capacity percentage at which a cluster warns (0 keeps the k8s collector's pod_pressure_threshold)",
+				Example:     `k8s_pod_pressure = 80`,
+			},
+			{
+				Name:        "k8s_not_ready_crit",
+				Type:        "int",
+				Default:     "0",
+				Description: "Number of not-ready nodes at which a cluster is critical instead of warning (0 never escalates)",
+				Example:     `k8s_not_ready_crit = 2`,
+			},
+			{
+				Name:        "tailscale_stale_after",
+				Type:        "duration",
+				Default:     "0",
+				Description: "How long an online peer may go unseen before it warns (0 keeps the tailscale collector's stale_after)",
+				Example:     `tailscale_stale_after = "30m"`,
+			},
+			{
+				Name:        "cpu_warn",
+				Type:        "float",
+				Default:     "0",
+				Description: "CPU usage percentage that warns (0 keeps the sysmetrics collector's cpu_warn)",
+				Example:     `cpu_warn = 90`,
+			},
+			{
+				Name:        "cpu_crit",
+				Type:        "float",
+				Default:     "0",
+				Description: "CPU usage percentage that is critical (0 keeps the sysmetrics collector's cpu_crit)",
+				Example:     `cpu_crit = 98`,
+			},
+			{
+				Name:        "memory_warn",
+				Type:        "float",
+				Default:     "0",
+				Description: "Memory usage percentage that warns (0 keeps the sysmetrics collector's memory_warn)",
+				Example:     `memory_warn = 90`,
+			},
+			{
+				Name:        "memory_crit",
+				Type:        "float",
+				Default:     "0",
+				Description: "Memory usage percentage that is critical (0 keeps the sysmetrics collector's memory_crit)",
+				Example:     `memory_crit = 98`,
+			},
+			{
+				Name:        "disk_warn",
+				Type:        "float",
+				Default:     "0",
+				Description: "Disk usage percentage that warns (0 keeps the sysmetrics collector's disk_warn)",
+				Example:     `disk_warn = 90`,
+			},
+			{
+				Name:        "disk_crit",
+				Type:        "float",
+				Default:     "0",
+				Description: "Disk usage percentage that is critical (0 keeps the sysmetrics collector's disk_crit)",
+				Example:     `disk_crit = 98`,
+			},
+		},
+	}
+}
+
+func dcDisplaySection() ConfigSection {
+	return ConfigSection{
+		Name:        "display",
+		Description: "Presentation of collector data in the banner.",
+		Fields: []ConfigField{
+			{
+				Name:        "claude_sort",
+				Type:        "string",
+				Default:     "config",
+				Description: "Claude account order: config, name, utilization (highest first), or status (crit, warn, offline, ok)",
+				Example:     `claude_sort = "utilization"`,
+			},
+			{
+				Name:        "primary_account",
+				Type:        "string",
+				Default:     "",
+				Description: "Claude account pinned first in the banner, and shown by the Starship utilization suffix, whatever claude_sort says; an unknown name is ignored with a warning",
+				Example:     `primary_account = "personal"`,
+			},
+			{
+				Name:        "enable_hyperlinks",
+				Type:        "bool",
+				Default:     "false",
+				Description: "Link node hostnames, cluster names, and billing providers to their dashboards with OSC 8 escapes, on terminals that support them",
+				Example:     "enable_hyperlinks = true",
+			},
+			{
+				Name:        "money_decimals",
+				Type:        "int",
+				Default:     "2",
+				Description: "Decimal places for dollar amounts in the banner and Starship segments: 2 or 0 for whole dollars",
+				Example:     "money_decimals = 0",
+			},
+			{
+				Name:        "thousands_separator",
+				Type:        "string",
+				Default:     "none",
+				Description: "Digit grouping for dollar amounts: none ($1234.50), comma ($1,234.50), or space ($1 234.50). Set here rather than taken from the system locale",
+				Example:     `thousands_separator = "comma"`,
+			},
+			{
+				Name:        "graph_style",
+				Type:        "string",
+				Default:     "block",
+				Description: "History graph style: block (one value per cell) or braille (a line through Braille dots, two values per cell and four levels per row)",
+				Example:     `graph_style = "braille"`,
+			},
+			{
+				Name:        "compare_last_month",
+				Type:        "bool",
+				Default:     "false",
+				Description: "Add a trend line to the billing section: this month's cumulative spend as a sparkline, with last month's curve as a faint baseline once the daemon has recorded all of last month",
+				Example:     `compare_last_month = true`,
+			},
+			{
+				Name:        "hide_zero_providers",
+				Type:        "bool",
+				Default:     "false",
+				Description: "Leave billing providers with no spend this month out of the banner's provider line, with a \"+N at $0\" note; otherwise they show as $0",
+				Example:     `hide_zero_providers = true`,
+			},
+			{
+				Name:        "sparkline_points",
+				Type:        "int",
+				Default:     "0",
+				Description: "Maximum points in the billing trend and Starship Claude sparklines; longer histories are averaged into buckets (0 = fit the available width)",
+				Example:     "sparkline_points = 12",
+			},
+			{
+				Name:        "glyphs",
+				Type:        "string",
+				Default:     "auto",
+				Description: "Status and alert markers: emoji, nerdfont, ascii (7-bit only), or auto to pick from $TERM, the locale, and $NERD_FONT",
+				Example:     `glyphs = "ascii"`,
+			},
+			{
+				Name:        "critical_first",
+				Type:        "bool",
+				Default:     "false",
+				Description: "Move banner sections at warning or critical level to the top, critical first, marked with the level's glyph; healthy sections keep their order",
+				Example:     "critical_first = true",
+			},
+			{
+				Name:        "reset_time_format",
+				Type:        "string",
+				Default:     "relative",
+				Description: "When a Claude budget window resets: relative (in 2d 3h), absolute (local wall-clock time, Nov 1 00:00), or both (in 2d 3h (Nov 1 00:00)) where the banner is wide enough",
+				Example:     `reset_time_format = "both"`,
+			},
+			{
+				Name:        "clock_24h",
+				Type:        "bool",
+				Default:     "true",
+				Description: "Show wall-clock times on a 24-hour clock (15:45) instead of a 12-hour one (3:45pm)",
+				Example:     "clock_24h = false",
+			},
+			{
+				Name:        "show_plan_name",
+				Type:        "bool",
+				Default:     "false",
+				Description: "Label Claude accounts in the banner with their plan, from each account's tier code (max_5x shows as Max 5×)",
+				Example:     "show_plan_name = true",
+			},
+			{
+				Name:        "claude_heatmap",
+				Type:        "bool",
+				Default:     "false",
+				Description: "Add a one-cell-per-account heatmap row and legend to the Claude banner section, in claude_sort order",
+				Example:     "claude_heatmap = true",
+			},
+			{
+				Name:        "dashboard_urls.<name>",
+				Type:        "table",
+				Default:     "{}",
+				Description: "URL templates replacing the dashboard links of billing providers, clusters and Tailscale nodes by name, or kind:name (billing, k8s, tailscale); {name} and {region} are filled in",
+				Example:     `dashboard_urls."k8s:civo-prod" = "https://sso.example.com/k8s/{name}?region={region}"`,
+			},
 		},
 	}
 }
@@ -0,0 +1,228 @@
+package docs
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// SchemaKey documents one key of the config file as derived from the toml
+// struct tags of config.Config.
+type SchemaKey struct {
+	// Table is the dotted TOML table holding the key, e.g.
+	// "collectors.claude". Keys of array tables are listed under the
+	// array's name, and keys of map entries under "<name>".
+	Table string
+
+	// Array is set when Table is an array of tables ([[table]]).
+	Array bool
+
+	// Key is the TOML key name.
+	Key string
+
+	// Type is the TOML value type: string, bool, integer, float,
+	// duration, or array of strings.
+	Type string
+
+	// Default is the built-in default as a TOML literal, or empty when
+	// the key has none (keys of array tables and map entries).
+	Default string
+
+	// Description is taken from the configuration reference, when it
+	// documents the key.
+	Description string
+}
+
+// dcDurationType is the reflected type of config.Duration, written as a
+// duration string in TOML rather than as a table.
+var dcDurationType = reflect.TypeOf(config.Duration{})
+
+// dcConfigSchema walks config.Config and returns every key it accepts, in
+// declaration order. Defaults come from config.DefaultConfig and
+// descriptions from dcGenerateConfigRef. A default that depends on the
+// home directory of whoever generates the page, such as cache_dir, is
+// replaced by the reference's documented default.
+func dcConfigSchema() []SchemaKey {
+	ref := make(map[string]ConfigField)
+	for _, s := range dcGenerateConfigRef().Sections {
+		for _, f := range s.Fields {
+			ref[s.Name+"."+f.Name] = f
+		}
+	}
+	var keys []SchemaKey
+	dcWalkSchema(reflect.ValueOf(config.DefaultConfig()).Elem(), "", false, true, ref, nil, &keys)
+
+	if home, _ := os.UserHomeDir(); home != "" {
+		for i, k := range keys {
+			if !strings.Contains(k.Default, home) {
+				continue
+			}
+			if f, ok := ref[k.Table+"."+k.Key]; ok && f.Default != "" {
+				keys[i].Default = fmt.Sprintf("%q", f.Default)
+			} else {
+				keys[i].Default = strings.ReplaceAll(k.Default, home, "~")
+			}
+		}
+	}
+	return keys
+}
+
+// dcWalkSchema appends the keys of the struct v, which lives in table, to
+// keys, followed by the keys of its subtables so that every table is
+// listed in one piece. Within array tables and map entries v is a zero
+// value and defaults is false: those keys have no built-in default. seen
+// holds the struct types being walked, so self-nesting tables such as
+// layout.row.child are listed once.
+func dcWalkSchema(v reflect.Value, table string, array, defaults bool, ref map[string]ConfigField, seen []reflect.Type, keys *[]SchemaKey) {
+	seen = append(seen, v.Type())
+	var subtables []func()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		name := strings.Split(f.Tag.Get("toml"), ",")[0]
+		if name == "" || name == "-" || !f.IsExported() {
+			continue
+		}
+		child := name
+		if table != "" {
+			child = table + "." + name
+		}
+		fv := v.Field(i)
+
+		switch {
+		case f.Type.Kind() == reflect.Struct && f.Type != dcDurationType:
+			subtables = append(subtables, func() {
+				dcWalkSchema(fv, child, false, defaults, ref, seen, keys)
+			})
+			continue
+		case f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct:
+			if !dcSeen(seen, f.Type.Elem()) {
+				subtables = append(subtables, func() {
+					dcWalkSchema(reflect.New(f.Type.Elem()).Elem(), child, true, false, ref, seen, keys)
+				})
+			}
+			continue
+		case f.Type.Kind() == reflect.Map && f.Type.Elem().Kind() == reflect.Struct:
+			subtables = append(subtables, func() {
+				dcWalkSchema(reflect.New(f.Type.Elem()).Elem(), child+".<name>", false, false, ref, seen, keys)
+			})
+			continue
+		}
+
+		k := SchemaKey{
+			Table:       table,
+			Array:       array,
+			Key:         name,
+			Type:        dcSchemaType(f.Type),
+			Description: ref[child].Description,
+		}
+		if defaults {
+			k.Default = dcTOMLLiteral(fv)
+		}
+		*keys = append(*keys, k)
+	}
+	for _, walk := range subtables {
+		walk()
+	}
+}
+
+// dcSeen reports whether t is one of the struct types being walked.
+func dcSeen(seen []reflect.Type, t reflect.Type) bool {
+	for _, s := range seen {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
+
+// dcSchemaType names the TOML type of a config field.
+func dcSchemaType(t reflect.Type) string {
+	if t == dcDurationType {
+		return "duration"
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return dcSchemaType(t.Elem())
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Slice:
+		return "array of " + dcSchemaType(t.Elem()) + "s"
+	case reflect.Map:
+		return "table of " + dcSchemaType(t.Elem()) + "s"
+	default:
+		return "string"
+	}
+}
+
+// dcTOMLLiteral formats v as it would be written in the config file.
+func dcTOMLLiteral(v reflect.Value) string {
+	if v.Type() == dcDurationType {
+		return fmt.Sprintf("%q", v.Interface().(config.Duration).Duration.String())
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return ""
+		}
+		return dcTOMLLiteral(v.Elem())
+	case reflect.String:
+		return fmt.Sprintf("%q", v.String())
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%g", v.Float())
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = dcTOMLLiteral(v.Index(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Map:
+		return "{}"
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// dcRenderSchemaRoff renders keys as roff, one subsection per table.
+func dcRenderSchemaRoff(keys []SchemaKey) string {
+	var b strings.Builder
+	table := "\x00"
+	for _, k := range keys {
+		if k.Table != table {
+			table = k.Table
+			if k.Array {
+				fmt.Fprintf(&b, ".SS [[%s]]\n", dcRoffEscape(table))
+			} else {
+				fmt.Fprintf(&b, ".SS [%s]\n", dcRoffEscape(table))
+			}
+		}
+		b.WriteString(".TP\n")
+		fmt.Fprintf(&b, ".B %s\n", k.Key)
+		if k.Default != "" {
+			fmt.Fprintf(&b, "%s, default %s.\n", k.Type, dcRoffEscape(k.Default))
+		} else {
+			fmt.Fprintf(&b, "%s.\n", k.Type)
+		}
+		if k.Description != "" {
+			b.WriteString(dcRoffEscape(k.Description) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// dcRoffEscape escapes text for use in a roff line: backslashes are
+// printed literally and a leading control character is neutralized.
+func dcRoffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
@@ -25,6 +25,10 @@ type Section struct {
 	// Order controls the sort position (lower = earlier).
 	Order int
 
+	// ManSection is the man page section the section is written to in
+	// roff format (e.g., "1", "5"). Defaults to "1".
+	ManSection string
+
 	// SubSections are nested sections within this section.
 	SubSections []Section
 }
@@ -60,13 +64,26 @@ func (g *DocGenerator) Add(title, slug, content string, order int) {
 	})
 }
 
+// AddMan appends a new top-level section that is written to man page
+// section manSection in roff format.
+func (g *DocGenerator) AddMan(title, slug, content, manSection string, order int) {
+	g.Sections = append(g.Sections, Section{
+		Title:      title,
+		Slug:       slug,
+		Content:    content,
+		Order:      order,
+		ManSection: manSection,
+	})
+}
+
 // AddSection appends a pre-built Section to the generator.
 func (g *DocGenerator) AddSection(s Section) {
 	g.Sections = append(g.Sections, s)
 }
 
 // Generate writes each section to its own file in OutputDir.
-// Files are named "<slug>.md" for Markdown format.
+// Files are named "<slug>.md" for Markdown format. In roff format they are
+// named "man<N>/<slug>.<N>" after the section's ManSection.
 func (g *DocGenerator) Generate() error {
 	if err := os.MkdirAll(g.OutputDir, 0o755); err != nil {
 		return fmt.Errorf("create output dir: %w", err)
@@ -75,11 +92,18 @@ func (g *DocGenerator) Generate() error {
 	sorted := dcSortedSections(g.Sections)
 
 	for _, s := range sorted {
-		ext := ".md"
+		filename := filepath.Join(g.OutputDir, s.Slug+".md")
 		if g.Format == "roff" {
-			ext = ".1"
+			sec := s.ManSection
+			if sec == "" {
+				sec = "1"
+			}
+			dir := filepath.Join(g.OutputDir, "man"+sec)
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf("create %s: %w", dir, err)
+			}
+			filename = filepath.Join(dir, s.Slug+"."+sec)
 		}
-		filename := filepath.Join(g.OutputDir, s.Slug+ext)
 		body := dcRenderSection(s, 1)
 		if err := os.WriteFile(filename, []byte(body), 0o644); err != nil {
 			return fmt.Errorf("write %s: %w", filename, err)
@@ -297,16 +297,23 @@ func TestConfigRefAllSections(t *testing.T) {
 
 	expected := []string{
 		"general",
+		"general.quiet_hours",
 		"layout",
 		"collectors.sysmetrics",
 		"collectors.tailscale",
 		"collectors.kubernetes",
 		"collectors.claude",
 		"collectors.billing",
+		"collectors.billing.civo",
+		"collectors.billing.digitalocean",
+		"collectors.command",
 		"image",
 		"theme",
 		"shell",
 		"banner",
+		"notify",
+		"status",
+		"display",
 	}
 
 	if len(ref.Sections) != len(expected) {
@@ -513,7 +520,7 @@ func TestManPageAllPagesGenerated(t *testing.T) {
 		"prompt-pulse-daemon.1",
 		"prompt-pulse-banner.1",
 		"prompt-pulse-tui.1",
-		"prompt-pulse.toml.5",
+		"prompt-pulse-config.5",
 	}
 	for _, name := range expected {
 		if !names[name] {
@@ -600,7 +607,7 @@ func TestRenderManMarkdown(t *testing.T) {
 }
 
 func TestManPageConfigSection5(t *testing.T) {
-	mp := dcGenerateManPage("prompt-pulse.toml", "5")
+	mp := dcGenerateManPage("prompt-pulse-config", "5")
 	if mp.Section != "5" {
 		t.Errorf("Section = %q, want 5", mp.Section)
 	}
@@ -834,14 +841,99 @@ func TestGenerateRoffFormat(t *testing.T) {
 	g := New(dir)
 	g.Format = "roff"
 	g.Add("Test", "test", "Content", 1)
+	g.AddMan("Format", "format", "Content", "5", 2)
 
 	if err := g.Generate(); err != nil {
 		t.Fatalf("Generate() error: %v", err)
 	}
 
-	path := filepath.Join(dir, "test.1")
-	if _, err := os.Stat(path); err != nil {
-		t.Errorf("roff file not created at %s: %v", path, err)
+	for _, path := range []string{
+		filepath.Join(dir, "man1", "test.1"),
+		filepath.Join(dir, "man5", "format.5"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("roff file not created at %s: %v", path, err)
+		}
+	}
+}
+
+func TestWriteManPagesSections(t *testing.T) {
+	dir := t.TempDir()
+	n, err := WriteManPages(dir)
+	if err != nil {
+		t.Fatalf("WriteManPages() error: %v", err)
+	}
+	if n != len(dcAllManPages()) {
+		t.Errorf("WriteManPages() = %d, want %d", n, len(dcAllManPages()))
+	}
+
+	for _, tt := range []struct{ path, header string }{
+		{filepath.Join("man1", "prompt-pulse.1"), ".TH PROMPT-PULSE 1 "},
+		{filepath.Join("man5", "prompt-pulse-config.5"), ".TH PROMPT-PULSE-CONFIG 5 "},
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, tt.path))
+		if err != nil {
+			t.Errorf("%s not written: %v", tt.path, err)
+			continue
+		}
+		if !strings.HasPrefix(string(data), tt.header) {
+			t.Errorf("%s header = %q, want prefix %q", tt.path, strings.SplitN(string(data), "\n", 2)[0], tt.header)
+		}
+		if !strings.Contains(string(data), "\n.SH NAME\n") {
+			t.Errorf("%s missing .SH NAME", tt.path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "man1", "prompt-pulse-config.5")); !os.IsNotExist(err) {
+		t.Errorf("config page should only be written to man5, stat err = %v", err)
+	}
+}
+
+func TestConfigSchemaFromStructTags(t *testing.T) {
+	keys := dcConfigSchema()
+	find := func(table, key string) *SchemaKey {
+		for i := range keys {
+			if keys[i].Table == table && keys[i].Key == key {
+				return &keys[i]
+			}
+		}
+		return nil
+	}
+
+	for _, tt := range []struct {
+		table, key, typ, def string
+		array                bool
+	}{
+		{"general", "log_level", "string", `"info"`, false},
+		{"general", "daemon_poll_interval", "duration", `"15m0s"`, false},
+		{"general", "cache_dir", "string", `"$XDG_CACHE_HOME/prompt-pulse"`, false},
+		{"collectors.kubernetes", "contexts", "array of strings", "[]", false},
+		{"collectors.claude.account", "budget_usd", "float", "", true},
+		{"collectors.billing.civo", "credits", "float", "", false},
+		{"collectors.kubernetes.overrides.<name>", "dashboard_url", "string", "", false},
+		{"layout.row.child", "type", "string", "", true},
+	} {
+		k := find(tt.table, tt.key)
+		if k == nil {
+			t.Errorf("schema missing %s.%s", tt.table, tt.key)
+			continue
+		}
+		if k.Type != tt.typ || k.Default != tt.def || k.Array != tt.array {
+			t.Errorf("%s.%s = {%s %q array=%v}, want {%s %q array=%v}",
+				tt.table, tt.key, k.Type, k.Default, k.Array, tt.typ, tt.def, tt.array)
+		}
+	}
+	if k := find("general", "log_level"); k != nil && k.Description == "" {
+		t.Error("general.log_level should take its description from the config reference")
+	}
+	if k := find("layout.row.child.child", "type"); k != nil {
+		t.Error("self-nesting layout.row.child should be listed once")
+	}
+
+	roff := dcRenderManRoff(dcManConfig())
+	for _, want := range []string{".SH FILE FORMAT\n", ".SS [general]\n", ".SS [[collectors.command]]\n", ".B log_level\n"} {
+		if !strings.Contains(roff, want) {
+			t.Errorf("config man page missing %q", want)
+		}
 	}
 }
 
@@ -25,9 +25,16 @@ type ManPage struct {
 	// Description is the full description body.
 	Description string
 
+	// FileFormat documents the keys of a file format, for section 5
+	// pages.
+	FileFormat string
+
 	// Options lists command-line flags and their descriptions.
 	Options string
 
+	// ExitStatus documents the exit codes of the command.
+	ExitStatus string
+
 	// Examples shows usage examples.
 	Examples string
 
@@ -46,7 +53,7 @@ func dcGenerateManPage(name, section string) *ManPage {
 		return dcManBanner()
 	case "prompt-pulse-tui.1":
 		return dcManTUI()
-	case "prompt-pulse.toml.5":
+	case "prompt-pulse-config.5":
 		return dcManConfig()
 	default:
 		return &ManPage{
@@ -69,7 +76,8 @@ func dcAllManPages() []*ManPage {
 }
 
 // WriteManPages writes all man pages as roff files to the given base directory,
-// creating man1/ and man5/ subdirectories as needed. Returns the number of pages written.
+// each in the man<section>/ subdirectory for its section: commands in man1/ and
+// the config file format in man5/. Returns the number of pages written.
 func WriteManPages(baseDir string) (int, error) {
 	pages := dcAllManPages()
 	for _, mp := range pages {
@@ -111,12 +119,24 @@ func dcRenderManRoff(mp *ManPage) string {
 		b.WriteString(mp.Description + "\n")
 	}
 
+	// FILE FORMAT
+	if mp.FileFormat != "" {
+		b.WriteString(".SH FILE FORMAT\n")
+		b.WriteString(mp.FileFormat + "\n")
+	}
+
 	// OPTIONS
 	if mp.Options != "" {
 		b.WriteString(".SH OPTIONS\n")
 		b.WriteString(mp.Options + "\n")
 	}
 
+	// EXIT STATUS
+	if mp.ExitStatus != "" {
+		b.WriteString(".SH EXIT STATUS\n")
+		b.WriteString(mp.ExitStatus + "\n")
+	}
+
 	// EXAMPLES
 	if mp.Examples != "" {
 		b.WriteString(".SH EXAMPLES\n")
@@ -151,11 +171,21 @@ func dcRenderManMarkdown(mp *ManPage) string {
 		b.WriteString(mp.Description + "\n\n")
 	}
 
+	if mp.FileFormat != "" {
+		b.WriteString("## FILE FORMAT\n\n")
+		b.WriteString(mp.FileFormat + "\n\n")
+	}
+
 	if mp.Options != "" {
 		b.WriteString("## OPTIONS\n\n")
 		b.WriteString(mp.Options + "\n\n")
 	}
 
+	if mp.ExitStatus != "" {
+		b.WriteString("## EXIT STATUS\n\n")
+		b.WriteString(mp.ExitStatus + "\n\n")
+	}
+
 	if mp.Examples != "" {
 		b.WriteString("## EXAMPLES\n\n")
 		b.WriteString(mp.Examples + "\n\n")
@@ -199,7 +229,7 @@ Stop the background daemon.
 Show daemon status and collected data summary.
 .TP
 .B shell init <shell>
-Print shell integration snippet for bash, zsh, fish, or ksh.
+Print shell integration snippet for bash, zsh, fish, ksh, or powershell.
 .TP
 .B config
 Show current configuration.
@@ -215,6 +245,27 @@ Override image rendering protocol (auto, kitty, iterm2, sixel, halfblocks, none)
 .TP
 .B \-\-layout <preset>
 Override layout preset (dashboard, minimal, ops, billing).`,
+		ExitStatus: `The \-health, \-doctor and \-status commands exit with a code scripts can
+branch on; with \-json the same value is reported as exit_code.
+.TP
+.B 0
+Healthy.
+.TP
+.B 1
+Warning: something needs attention.
+.TP
+.B 2
+Critical: something is failing.
+.TP
+.B 3
+The daemon is not running.
+.TP
+.B 4
+The configuration could not be loaded or is invalid.
+.TP
+.B 5
+Cache error: no cached data to evaluate, or an unreadable health file or
+cache directory.`,
 		Examples: `.nf
 # Show banner
 prompt-pulse banner
@@ -234,7 +285,7 @@ prompt-pulse config
 		SeeAlso: `.BR prompt-pulse-daemon (1),
 .BR prompt-pulse-banner (1),
 .BR prompt-pulse-tui (1),
-.BR prompt-pulse.toml (5)`,
+.BR prompt-pulse-config (5)`,
 	}
 }
 
@@ -278,7 +329,7 @@ prompt-pulse daemon status
 prompt-pulse daemon start --foreground
 .fi`,
 		SeeAlso: `.BR prompt-pulse (1),
-.BR prompt-pulse.toml (5)`,
+.BR prompt-pulse-config (5)`,
 	}
 }
 
@@ -318,7 +369,7 @@ prompt-pulse banner --width 79
 .fi`,
 		SeeAlso: `.BR prompt-pulse (1),
 .BR prompt-pulse-tui (1),
-.BR prompt-pulse.toml (5)`,
+.BR prompt-pulse-config (5)`,
 	}
 }
 
@@ -357,25 +408,34 @@ prompt-pulse tui --preset ops --no-mouse
 .fi`,
 		SeeAlso: `.BR prompt-pulse (1),
 .BR prompt-pulse-banner (1),
-.BR prompt-pulse.toml (5)`,
+.BR prompt-pulse-config (5)`,
 	}
 }
 
 func dcManConfig() *ManPage {
 	return &ManPage{
-		Name:      "prompt-pulse.toml",
+		Name:      "prompt-pulse-config",
 		Section:   "5",
 		ShortDesc: "prompt-pulse configuration file format",
 		Synopsis:  "$XDG_CONFIG_HOME/prompt-pulse/config.toml",
 		Description: `prompt-pulse uses a TOML configuration file with nested tables for each subsystem.
 The file is searched for in $XDG_CONFIG_HOME/prompt-pulse/config.toml, falling back
-to ~/.config/prompt-pulse/config.toml.
+to ~/.config/prompt-pulse/config.toml. A machine-wide /etc/prompt-pulse/config.toml,
+if present, is read first as a base that the user's file overrides.
+
+Any file may pull in others with a top-level include = ["base.toml"] key; paths are
+relative to the including file, and the including file overrides what it includes.
+Later files deep-merge over earlier ones: tables merge key by key, Claude accounts
+and command collectors merge by name, and other values, arrays included, are
+replaced. -diagnose lists the files read and which one set each value.
 
 If no configuration file is found, built-in defaults are used. Environment variables
 can override specific settings (see ENVIRONMENT section below).
 
 The configuration is organized into these top-level tables: general, layout,
-collectors, image, theme, shell, and banner.`,
+collectors, image, theme, shell, banner, notify, and display. FILE FORMAT lists
+every key with its type and built-in default.`,
+		FileFormat: dcRenderSchemaRoff(dcConfigSchema()),
 		Options: `Environment variable overrides:
 
 .TP
@@ -52,6 +52,7 @@ func itTestBannerResize(t *testing.T) {
 		banner.Standard,
 		banner.Wide,
 		banner.UltraWide,
+		banner.Portrait,
 	}
 
 	for _, p := range presets {
@@ -0,0 +1,48 @@
+// Package logging configures the process-wide slog logger from the
+// -verbose and -log-format flags. Packages log through slog's default
+// logger, so once Setup has run, the daemon, collectors, cache, and
+// renderers all share one level and output format.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Output formats accepted by -log-format.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// New returns a logger writing to w in the given format. It logs warnings
+// and errors only, unless verbose is set, which enables debug output such
+// as render timings and cache hits. An empty format means FormatText.
+func New(w io.Writer, verbose bool, format string) (*slog.Logger, error) {
+	level := slog.LevelWarn
+	if verbose {
+		level = slog.LevelDebug
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (supported: %s, %s)", format, FormatText, FormatJSON)
+	}
+}
+
+// Setup builds a logger with New and installs it as slog's default. The
+// standard log package is routed through it as well, at info level.
+func Setup(w io.Writer, verbose bool, format string) error {
+	l, err := New(w, verbose, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(l)
+	return nil
+}
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNew_DefaultIsQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("cache hit")
+	l.Info("starting")
+	l.Warn("channel full")
+	out := buf.String()
+	if strings.Contains(out, "cache hit") || strings.Contains(out, "starting") {
+		t.Errorf("non-verbose logger should drop debug and info, got %q", out)
+	}
+	if !strings.Contains(out, "level=WARN") {
+		t.Errorf("expected a text WARN record, got %q", out)
+	}
+}
+
+func TestNew_VerboseJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, true, "JSON")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("cache hit", "key", "claude")
+	if out := buf.String(); !strings.Contains(out, `"level":"DEBUG"`) || !strings.Contains(out, `"key":"claude"`) {
+		t.Errorf("expected a JSON debug record, got %q", out)
+	}
+}
+
+func TestNew_UnknownFormat(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, false, "xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
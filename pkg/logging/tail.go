@@ -0,0 +1,220 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultPollInterval is how often Follow checks the log for new lines and
+// rotation when TailOptions.PollInterval is unset.
+const DefaultPollInterval = 250 * time.Millisecond
+
+// TailOptions configures Follow.
+type TailOptions struct {
+	// Lines is the number of existing lines to print before following,
+	// like tail -n. Zero prints none.
+	Lines int
+
+	// MinLevel drops records below it. Lines without a recognisable
+	// level, such as panics or output of the standard log package, are
+	// always kept. Nil keeps every line.
+	MinLevel slog.Leveler
+
+	// PollInterval is how often the file is checked for new lines and
+	// rotation. Zero means DefaultPollInterval.
+	PollInterval time.Duration
+}
+
+// ParseLevel parses a level name as accepted by -level: debug, info, warn
+// (or warning) and error, in any case.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (supported: debug, info, warn, error)", s)
+}
+
+// LineLevel returns the level of a record written by a text or JSON
+// handler from New, reading the level=WARN attribute or the "level" key.
+// It reports false for lines that are not such records.
+func LineLevel(line string) (slog.Level, bool) {
+	var name string
+	if strings.HasPrefix(line, "{") {
+		var rec struct {
+			Level string `json:"level"`
+		}
+		if json.Unmarshal([]byte(line), &rec) != nil {
+			return 0, false
+		}
+		name = rec.Level
+	} else {
+		for _, f := range strings.Fields(line) {
+			if v, ok := strings.CutPrefix(f, "level="); ok {
+				name = v
+				break
+			}
+		}
+	}
+	if name == "" {
+		return 0, false
+	}
+	var l slog.Level
+	if l.UnmarshalText([]byte(name)) != nil {
+		return 0, false
+	}
+	return l, true
+}
+
+// keep reports whether line passes opts.MinLevel.
+func (opts TailOptions) keep(line string) bool {
+	if opts.MinLevel == nil {
+		return true
+	}
+	l, ok := LineLevel(line)
+	return !ok || l >= opts.MinLevel.Level()
+}
+
+// Follow writes the last opts.Lines lines of the log at path to w, then
+// new lines as they are appended, like tail -F, until ctx is cancelled.
+// When the log is rotated, the rest of the old file is written and the
+// new one is followed from its start; a log truncated in place is
+// followed from its start as well. A partial last line is held back until
+// its newline arrives.
+func Follow(ctx context.Context, path string, w io.Writer, opts TailOptions) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { f.Close() }()
+
+	r := bufio.NewReader(f)
+	partial, err := writeLastLines(r, w, opts)
+	if err != nil {
+		return err
+	}
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if partial, err = copyLines(r, w, partial, opts); err != nil {
+			return err
+		}
+
+		if rotated, err := reopened(f, path); err != nil {
+			return err
+		} else if rotated != nil {
+			// Drain what was written to the old file before the switch,
+			// ending an unterminated last line.
+			if partial, err = copyLines(r, w, partial, opts); err == nil && partial != "" && opts.keep(partial) {
+				_, err = io.WriteString(w, partial+"\n")
+			}
+			if err != nil {
+				rotated.Close()
+				return err
+			}
+			f.Close()
+			f, r, partial = rotated, bufio.NewReader(rotated), ""
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeLastLines reads r to its end, writes the last opts.Lines complete
+// lines that pass the level filter to w, and returns the unterminated
+// rest.
+func writeLastLines(r *bufio.Reader, w io.Writer, opts TailOptions) (string, error) {
+	ring := make([]string, 0, max(opts.Lines, 0))
+	var partial string
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF {
+			partial = line
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if opts.Lines > 0 && opts.keep(line) {
+			if len(ring) == opts.Lines {
+				ring = ring[1:]
+			}
+			ring = append(ring, line)
+		}
+	}
+	for _, line := range ring {
+		if _, err := io.WriteString(w, line); err != nil {
+			return "", err
+		}
+	}
+	return partial, nil
+}
+
+// copyLines writes the complete lines available from r that pass the level
+// filter to w, prefixed by partial, and returns the unterminated rest.
+func copyLines(r *bufio.Reader, w io.Writer, partial string, opts TailOptions) (string, error) {
+	for {
+		chunk, err := r.ReadString('\n')
+		partial += chunk
+		if err == io.EOF {
+			return partial, nil
+		}
+		if err != nil {
+			return partial, err
+		}
+		if opts.keep(partial) {
+			if _, err := io.WriteString(w, partial); err != nil {
+				return "", err
+			}
+		}
+		partial = ""
+	}
+}
+
+// reopened checks whether path no longer names the open file f, because
+// the log was rotated, or was truncated below the read offset. It returns
+// the file now at path, or nil while f is still current or no new file
+// exists yet.
+func reopened(f *os.File, path string) (*os.File, error) {
+	cur, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if os.SameFile(cur, info) {
+		if off, err := f.Seek(0, io.SeekCurrent); err == nil && info.Size() < off {
+			return os.Open(path)
+		}
+		return nil, nil
+	}
+	return os.Open(path)
+}
@@ -0,0 +1,172 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for Follow to write while a test reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// appendLog appends s to the log at path, creating it if needed.
+func appendLog(t *testing.T, path, s string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startFollow runs Follow on path in the background and returns its output
+// and a function that stops it and returns its error.
+func startFollow(t *testing.T, path string, opts TailOptions) (*syncBuffer, func() error) {
+	t.Helper()
+	opts.PollInterval = 5 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error, 1)
+	go func() { done <- Follow(ctx, path, out, opts) }()
+	t.Cleanup(cancel)
+	return out, func() error {
+		cancel()
+		return <-done
+	}
+}
+
+// waitFor waits until out holds want.
+func waitFor(t *testing.T, out *syncBuffer, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(out.String(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %q, got %q", want, out.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFollow_StreamsNewLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	appendLog(t, path, "one\ntwo\nthree\n")
+
+	out, stop := startFollow(t, path, TailOptions{Lines: 2})
+	waitFor(t, out, "two\nthree\n")
+	appendLog(t, path, "four\nfi")
+	waitFor(t, out, "three\nfour\n")
+	appendLog(t, path, "ve\n")
+	waitFor(t, out, "four\nfive\n")
+	if err := stop(); err != nil {
+		t.Fatalf("Follow() error: %v", err)
+	}
+	if got := out.String(); got != "two\nthree\nfour\nfive\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestFollow_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "daemon.log")
+	appendLog(t, path, "old\n")
+
+	out, stop := startFollow(t, path, TailOptions{Lines: 1})
+	waitFor(t, out, "old\n")
+
+	// Rotate by rename: a line lands in the old file after the move and
+	// must not be lost, then the new file is followed from its start.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendLog(t, path+".1", "late\n")
+	appendLog(t, path, "new\n")
+	waitFor(t, out, "new\n")
+
+	// Truncation in place restarts from the top as well.
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	appendLog(t, path, "x\n")
+	waitFor(t, out, "new\nx\n")
+
+	if err := stop(); err != nil {
+		t.Fatalf("Follow() error: %v", err)
+	}
+	if got := out.String(); got != "old\nlate\nnew\nx\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestFollow_LevelFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	appendLog(t, path, strings.Join([]string{
+		`time=2026-10-17T10:00:00Z level=DEBUG msg="cache hit"`,
+		`time=2026-10-17T10:00:01Z level=WARN msg="channel full"`,
+		`panic: boom`,
+		`{"time":"2026-10-17T10:00:02Z","level":"INFO","msg":"collected"}`,
+		`{"time":"2026-10-17T10:00:03Z","level":"ERROR","msg":"collect failed"}`,
+	}, "\n")+"\n")
+
+	out, stop := startFollow(t, path, TailOptions{Lines: 10, MinLevel: slog.LevelWarn})
+	waitFor(t, out, "collect failed")
+	appendLog(t, path, "time=2026-10-17T10:00:04Z level=INFO msg=tick\ntime=2026-10-17T10:00:05Z level=ERROR msg=down\n")
+	waitFor(t, out, "msg=down")
+	if err := stop(); err != nil {
+		t.Fatalf("Follow() error: %v", err)
+	}
+	got := out.String()
+	for _, dropped := range []string{"cache hit", "collected", "msg=tick"} {
+		if strings.Contains(got, dropped) {
+			t.Errorf("output should drop %q below warn:\n%s", dropped, got)
+		}
+	}
+	for _, kept := range []string{"channel full", "panic: boom", "collect failed"} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("output should keep %q:\n%s", kept, got)
+		}
+	}
+}
+
+func TestFollow_MissingFile(t *testing.T) {
+	err := Follow(context.Background(), filepath.Join(t.TempDir(), "nope.log"), &bytes.Buffer{}, TailOptions{})
+	if !os.IsNotExist(err) {
+		t.Errorf("Follow() error = %v, want not exist", err)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]slog.Level{
+		"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warning": slog.LevelWarn, " error ": slog.LevelError,
+	} {
+		if got, err := ParseLevel(in); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v", in, got, err)
+		}
+	}
+	if _, err := ParseLevel("fatal"); err == nil {
+		t.Error("ParseLevel(fatal) should fail")
+	}
+}
@@ -0,0 +1,66 @@
+package perf
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/banner"
+)
+
+// BannerStandardTarget is the render-time target for a full banner at the
+// Standard preset. It is the budget the banner_standard threshold enforces
+// and the line -bench-banner measures against: shell startup waits on the
+// banner, so a render should stay well below what a user can perceive.
+const BannerStandardTarget = 5 * time.Millisecond
+
+// BannerPresets lists the banner layout presets, smallest first.
+var BannerPresets = []banner.Preset{banner.Compact, banner.Portrait, banner.Standard, banner.Wide, banner.UltraWide}
+
+// pfAnsiRe matches ANSI CSI escape sequences and OSC 8 hyperlink escapes.
+var pfAnsiRe = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]|\x1b\]8;[^\x1b\x07]*(?:\x1b\\|\x07)`)
+
+// PlainBannerData returns a copy of data with all escape sequences removed
+// from widget content, as rendered with color disabled. Comparing it with
+// data isolates the cost color escapes add to layout and composition.
+func PlainBannerData(data banner.BannerData) banner.BannerData {
+	out := banner.BannerData{Widgets: make([]banner.WidgetData, len(data.Widgets))}
+	for i, w := range data.Widgets {
+		w.Content = pfAnsiRe.ReplaceAllString(w.Content, "")
+		out.Widgets[i] = w
+	}
+	return out
+}
+
+// BannerBenchResult is the measured render cost of one preset and color
+// mode.
+type BannerBenchResult struct {
+	Preset  banner.Preset
+	Colored bool
+	testing.BenchmarkResult
+}
+
+// BenchBanner measures banner.Render of data at every preset in
+// BannerPresets, once with data as given and once with PlainBannerData.
+// It runs each measurement with testing.Benchmark, so it takes about a
+// second per result.
+func BenchBanner(data banner.BannerData) []BannerBenchResult {
+	plain := PlainBannerData(data)
+	var results []BannerBenchResult
+	for _, p := range BannerPresets {
+		for _, colored := range []bool{true, false} {
+			d := plain
+			if colored {
+				d = data
+			}
+			r := testing.Benchmark(func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = banner.Render(d, p)
+				}
+			})
+			results = append(results, BannerBenchResult{Preset: p, Colored: colored, BenchmarkResult: r})
+		}
+	}
+	return results
+}
@@ -132,6 +132,58 @@ func BenchmarkBannerRenderCached(b *testing.B) {
 	}
 }
 
+// pfColorModes pairs each color mode's sub-benchmark name with its data:
+// the realistic widgets as given, and the same widgets with escapes
+// stripped.
+func pfColorModes() []struct {
+	name string
+	data banner.BannerData
+} {
+	data := pfMakeBannerData()
+	return []struct {
+		name string
+		data banner.BannerData
+	}{
+		{"colored", data},
+		{"plain", PlainBannerData(data)},
+	}
+}
+
+// BenchmarkRenderCached benchmarks RenderCached at every preset, colored
+// and plain. Each sub-benchmark uses a fresh cache directory, so the first
+// iteration renders and writes the cache and the rest take the read path.
+func BenchmarkRenderCached(b *testing.B) {
+	for _, p := range BannerPresets {
+		for _, m := range pfColorModes() {
+			b.Run(p.Name+"/"+m.name, func(b *testing.B) {
+				cacheDir := b.TempDir()
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					_, _ = banner.RenderCached(cacheDir, m.data, p)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkResponsiveRender benchmarks the uncached responsive path, preset
+// selection from the terminal size plus a full Render, at each preset's own
+// dimensions, colored and plain. The standard sub-benchmarks should stay
+// under BannerStandardTarget.
+func BenchmarkResponsiveRender(b *testing.B) {
+	for _, p := range BannerPresets {
+		for _, m := range pfColorModes() {
+			b.Run(p.Name+"/"+m.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = banner.Render(m.data, banner.SelectPreset(p.Width, p.Height))
+				}
+			})
+		}
+	}
+}
+
 // BenchmarkBannerSelectPreset benchmarks the preset selection logic across
 // a range of terminal sizes.
 func BenchmarkBannerSelectPreset(b *testing.B) {
@@ -312,6 +312,29 @@ func TestPfMakeBannerDataWidgetsHaveContent(t *testing.T) {
 	}
 }
 
+func TestPlainBannerDataStripsEscapes(t *testing.T) {
+	data := pfMakeBannerData()
+	data.Widgets[1].Content = "\x1b]8;;https://console.anthropic.com\x1b\\Claude\x1b]8;;\x1b\\ $142.30"
+	plain := PlainBannerData(data)
+	if len(plain.Widgets) != len(data.Widgets) {
+		t.Fatalf("PlainBannerData: got %d widgets, want %d", len(plain.Widgets), len(data.Widgets))
+	}
+	for i, w := range plain.Widgets {
+		if strings.Contains(w.Content, "\x1b") {
+			t.Errorf("widget[%d] still has escapes: %q", i, w.Content)
+		}
+	}
+	if got := plain.Widgets[1].Content; got != "Claude $142.30" {
+		t.Errorf("hyperlink not stripped: got %q", got)
+	}
+	if !strings.HasPrefix(plain.Widgets[0].Content, "CPU  ████████████ ") {
+		t.Errorf("gauge text should survive stripping: %q", plain.Widgets[0].Content)
+	}
+	if !strings.Contains(data.Widgets[0].Content, "\x1b[") {
+		t.Error("PlainBannerData modified its input")
+	}
+}
+
 // --- pfMakeTestImage tests --------------------------------------------------
 
 func TestPfMakeTestImageDimensions(t *testing.T) {
@@ -36,6 +36,8 @@ type Violation struct {
 // Budget rationale:
 //   - banner_cached < 1ms: cache read is stat + file read, must be fast
 //   - banner_render < 50ms: full render with 6 widgets, includes grid compose
+//   - banner_standard < 5ms: the same render at the Standard preset, the
+//     BannerStandardTarget that -bench-banner reports against
 //   - layout_6widget < 5ms: constraint solver for typical dashboard
 //   - shell_generate < 1ms: string concatenation only
 //   - starship_render < 20ms: segment assembly with cache reads
@@ -56,6 +58,7 @@ func DefaultThresholds() []Threshold {
 		{Name: "text_truncate", MaxNs: 50_000, MaxAlloc: 4096},
 		{Name: "visible_len", MaxNs: 50_000, MaxAlloc: 2048},
 		{Name: "image_resize", MaxNs: 500_000_000, MaxAlloc: 33_554_432},
+		{Name: "banner_standard", MaxNs: BannerStandardTarget.Nanoseconds(), MaxAlloc: 1_048_576},
 	}
 }
 
@@ -0,0 +1,250 @@
+// Package schema derives JSON Schema documents from Go types, following
+// the rules encoding/json uses to marshal them. prompt-pulse uses it to
+// publish the shape of its cache files, -export sections, and HTTP API
+// responses, so integrators can validate what they consume.
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema. Only the keywords needed
+// to describe encoding/json output are supported.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Types is the value of the type keyword. It marshals as a single string
+// when it holds one type.
+type Types []string
+
+// MarshalJSON implements json.Marshaler.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Has reports whether t allows the named type.
+func (t Types) Has(name string) bool {
+	for _, s := range t {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	scTimeType      = reflect.TypeOf(time.Time{})
+	scJSONMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	scTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	scByteSlice     = reflect.TypeOf([]byte(nil))
+)
+
+// scAnyJSON is the empty schema, which accepts any JSON value.
+var scAnyJSON = &Schema{}
+
+// Generate returns the schema of the JSON encoding of v's type, titled
+// title. Named struct types other than the root are placed in $defs and
+// referenced, so recursive types are supported.
+func Generate(v interface{}, title string) *Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	g := &scGenerator{defs: map[string]*Schema{}, names: map[reflect.Type]string{}}
+	var s *Schema
+	if t.Kind() == reflect.Struct {
+		// The root is described inline, and a reference back to it
+		// points at the document itself.
+		g.names[t] = ""
+		s = g.object(t)
+	} else {
+		s = g.schema(t)
+	}
+	s.Schema = Draft
+	s.Title = title
+	if len(g.defs) > 0 {
+		s.Defs = g.defs
+	}
+	return s
+}
+
+// scGenerator accumulates $defs while walking a type.
+type scGenerator struct {
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+// schema returns the schema of t.
+func (g *scGenerator) schema(t reflect.Type) *Schema {
+	switch {
+	case t == scTimeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	case t.Implements(scJSONMarshaler) || reflect.PointerTo(t).Implements(scJSONMarshaler):
+		// Custom JSON encodings are opaque to reflection.
+		return scAnyJSON
+	case t.Implements(scTextMarshaler) || reflect.PointerTo(t).Implements(scTextMarshaler):
+		return &Schema{Type: Types{"string"}}
+	case t == scByteSlice:
+		return &Schema{Type: Types{"string", "null"}, ContentEncoding: "base64"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Pointer:
+		return scOrNull(g.schema(t.Elem()))
+	case reflect.Slice:
+		return &Schema{Type: Types{"array", "null"}, Items: g.schema(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: Types{"object", "null"}, AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structRef(t)
+	default:
+		// Interfaces hold any JSON value; channels and functions cannot
+		// be marshaled and never appear in the models.
+		return scAnyJSON
+	}
+}
+
+// structRef returns a reference to t's definition, adding it to $defs on
+// first use. Anonymous structs are described inline.
+func (g *scGenerator) structRef(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.object(t)
+	}
+	if name, ok := g.names[t]; ok {
+		if name == "" {
+			return &Schema{Ref: "#"}
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	}
+	name := t.Name()
+	if _, taken := g.defs[name]; taken {
+		name = scPackageName(t) + "." + name
+	}
+	g.names[t] = name
+	g.defs[name] = nil // reserve the name while the fields are walked
+	g.defs[name] = g.object(t)
+	return &Schema{Ref: "#/$defs/" + name}
+}
+
+// object describes the fields of struct t as encoding/json marshals them:
+// exported fields under their json names, "-" fields skipped, embedded
+// structs flattened, and fields without omitempty required.
+func (g *scGenerator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: Types{"object"}, Properties: map[string]*Schema{}}
+	g.fields(t, s)
+	return s
+}
+
+func (g *scGenerator) fields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			et := ft
+			if et.Kind() == reflect.Pointer {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				g.fields(et, s)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, dup := s.Properties[name]; dup {
+			continue
+		}
+		fs := g.schema(ft)
+		if strings.Contains(opts, "string") && scScalar(ft) {
+			fs = &Schema{Type: Types{"string"}}
+		}
+		s.Properties[name] = fs
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// scOrNull allows null in addition to s.
+func scOrNull(s *Schema) *Schema {
+	if s == scAnyJSON {
+		return s
+	}
+	if s.Ref != "" {
+		return &Schema{AnyOf: []*Schema{s, {Type: Types{"null"}}}}
+	}
+	c := *s
+	if !c.Type.Has("null") {
+		c.Type = append(append(Types{}, c.Type...), "null")
+	}
+	return &c
+}
+
+// scScalar reports whether the ",string" tag option applies to t.
+func scScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// scPackageName returns the last element of t's package path.
+func scPackageName(t reflect.Type) string {
+	p := t.PkgPath()
+	return p[strings.LastIndex(p, "/")+1:]
+}
@@ -0,0 +1,268 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+)
+
+// models are the public collector payloads, keyed by cache key.
+var models = map[string]func() interface{}{
+	"claude":     func() interface{} { return new(claude.UsageReport) },
+	"billing":    func() interface{} { return new(billing.BillingReport) },
+	"tailscale":  func() interface{} { return new(tailscale.Status) },
+	"k8s":        func() interface{} { return new(k8s.ClusterStatus) },
+	"sysmetrics": func() interface{} { return new(sysmetrics.Metrics) },
+}
+
+func TestSchemaValidatesModels(t *testing.T) {
+	for name, newModel := range models {
+		t.Run(name, func(t *testing.T) {
+			s := scRoundTrip(t, Generate(newModel(), name))
+
+			// The zero value exercises nulls; the filled value sets every
+			// field, down through pointers, slices, and maps.
+			filled := newModel()
+			scFill(reflect.ValueOf(filled).Elem(), 0)
+			for label, v := range map[string]interface{}{"zero": newModel(), "filled": filled} {
+				doc := scMarshalDoc(t, v)
+				if err := scValidate(s, s, doc, "$"); err != nil {
+					t.Errorf("%s %s: %v", label, name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaRejectsWrongTypes(t *testing.T) {
+	s := Generate(new(billing.BillingReport), "billing")
+	for _, doc := range []string{
+		`{"providers": "civo", "total_monthly_usd": 1, "budget_usd": 0, "budget_percent": 0, "timestamp": "2026-10-01T00:00:00Z"}`,
+		`{"providers": [], "total_monthly_usd": "1", "budget_usd": 0, "budget_percent": 0, "timestamp": "2026-10-01T00:00:00Z"}`,
+		`{"providers": [], "total_monthly_usd": 1, "budget_usd": 0, "budget_percent": 0, "timestamp": "yesterday"}`,
+		`{"providers": [{"name": 3}], "total_monthly_usd": 1, "budget_usd": 0, "budget_percent": 0, "timestamp": "2026-10-01T00:00:00Z"}`,
+		`{"total_monthly_usd": 1}`,
+	} {
+		var v interface{}
+		if err := json.Unmarshal([]byte(doc), &v); err != nil {
+			t.Fatal(err)
+		}
+		if err := scValidate(s, s, v, "$"); err == nil {
+			t.Errorf("document %s validated, want an error", doc)
+		}
+	}
+}
+
+func TestGenerateFollowsJSONTags(t *testing.T) {
+	type node struct {
+		Name     string            `json:"name"`
+		Note     string            `json:"note,omitempty"`
+		Count    int64             `json:"count,string"`
+		Hidden   string            `json:"-"`
+		Children []node            `json:"children"`
+		Parent   *node             `json:"parent,omitempty"`
+		Labels   map[string]string `json:"labels,omitempty"`
+		At       time.Time         `json:"at"`
+		Raw      []byte            `json:"raw,omitempty"`
+		Untagged bool
+		private  int
+	}
+	s := Generate(node{}, "node")
+
+	if s.Schema != Draft || s.Title != "node" {
+		t.Errorf("header = %q %q", s.Schema, s.Title)
+	}
+	var props []string
+	for p := range s.Properties {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+	if got := strings.Join(props, ","); got != "Untagged,at,children,count,labels,name,note,parent,raw" {
+		t.Errorf("properties = %s", got)
+	}
+	if got := strings.Join(s.Required, ","); got != "name,count,children,at,Untagged" {
+		t.Errorf("required = %s", got)
+	}
+	if got := s.Properties["count"].Type; !reflect.DeepEqual(got, Types{"string"}) {
+		t.Errorf(`",string" int type = %v, want string`, got)
+	}
+	if got := s.Properties["children"].Items.Ref; got != "#" {
+		t.Errorf("recursive items $ref = %q, want #", got)
+	}
+	if got := s.Properties["at"].Format; got != "date-time" {
+		t.Errorf("time.Time format = %q, want date-time", got)
+	}
+}
+
+// scRoundTrip marshals s and decodes it again, so tests validate against
+// the document integrators see.
+func scRoundTrip(t *testing.T, s *Schema) *Schema {
+	t.Helper()
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("marshal schema: %v", err)
+	}
+	var out Schema
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unmarshal schema: %v", err)
+	}
+	return &out
+}
+
+// scMarshalDoc returns v as a generic JSON document.
+func scMarshalDoc(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return doc
+}
+
+// scFill sets every field reachable from v to a non-zero value, giving
+// slices and maps one element. depth stops recursive types.
+func scFill(v reflect.Value, depth int) {
+	if depth > 4 {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(7)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		scFill(p.Elem(), depth+1)
+		v.Set(p)
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		scFill(s.Index(0), depth+1)
+		v.Set(s)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		k := reflect.New(v.Type().Key()).Elem()
+		scFill(k, depth+1)
+		e := reflect.New(v.Type().Elem()).Elem()
+		scFill(e, depth+1)
+		m.SetMapIndex(k, e)
+		v.Set(m)
+	case reflect.Struct:
+		if v.Type() == scTimeType {
+			v.Set(reflect.ValueOf(time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				scFill(v.Field(i), depth+1)
+			}
+		}
+	}
+}
+
+// scValidate checks doc against s, resolving $ref against root. It covers
+// the keywords Generate emits.
+func scValidate(root, s *Schema, doc interface{}, path string) error {
+	if s.Ref != "" {
+		if s.Ref == "#" {
+			return scValidate(root, root, doc, path)
+		}
+		def, ok := root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok || def == nil {
+			return fmt.Errorf("%s: unresolved $ref %s", path, s.Ref)
+		}
+		return scValidate(root, def, doc, path)
+	}
+	if len(s.AnyOf) > 0 {
+		var errs []string
+		for _, sub := range s.AnyOf {
+			err := scValidate(root, sub, doc, path)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return fmt.Errorf("%s: matches no anyOf branch: %s", path, strings.Join(errs, "; "))
+	}
+	if len(s.Type) > 0 && !s.Type.Has(scJSONType(doc)) &&
+		!(scJSONType(doc) == "integer" && s.Type.Has("number")) {
+		return fmt.Errorf("%s: %s, want %v", path, scJSONType(doc), []string(s.Type))
+	}
+
+	switch d := doc.(type) {
+	case string:
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, d); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time", path, d)
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range d {
+				if err := scValidate(root, s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]interface{}:
+		for _, r := range s.Required {
+			if _, ok := d[r]; !ok {
+				return fmt.Errorf("%s: missing required %q", path, r)
+			}
+		}
+		for k, v := range d {
+			sub, ok := s.Properties[k]
+			if !ok {
+				sub = s.AdditionalProperties
+			}
+			if sub == nil {
+				continue
+			}
+			if err := scValidate(root, sub, v, path+"."+k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// scJSONType names the JSON type of a decoded value.
+func scJSONType(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if x == math.Trunc(x) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
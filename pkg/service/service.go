@@ -0,0 +1,227 @@
+// Package service generates and installs the per-user service definition
+// that runs the prompt-pulse daemon at login: a systemd user unit on Linux
+// and a launchd agent on macOS.
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/cache"
+)
+
+// Kind identifies a service manager.
+type Kind string
+
+const (
+	// Systemd is a systemd user unit (Linux).
+	Systemd Kind = "systemd"
+
+	// Launchd is a launchd user agent (macOS).
+	Launchd Kind = "launchd"
+)
+
+// Label is the launchd label of the daemon agent.
+const Label = "com.tinyland.prompt-pulse"
+
+// ErrExists is returned by Install when a unit is already present and
+// overwriting was not requested.
+var ErrExists = errors.New("service unit already exists")
+
+// Config describes the daemon invocation a unit runs.
+type Config struct {
+	// BinaryPath is the absolute path to the prompt-pulse binary.
+	BinaryPath string
+
+	// ConfigPath is passed to the daemon with -config. Empty lets the
+	// daemon search the default locations.
+	ConfigPath string
+
+	// CacheDir is the daemon cache directory; its log file lives there.
+	CacheDir string
+}
+
+// LogPath returns the daemon log file inside the cache directory.
+func (c Config) LogPath() string {
+	return filepath.Join(c.CacheDir, "daemon.log")
+}
+
+// Args returns the daemon command line, binary first.
+func (c Config) Args() []string {
+	args := []string{c.BinaryPath, "-daemon"}
+	if c.ConfigPath != "" {
+		args = append(args, "-config", c.ConfigPath)
+	}
+	return args
+}
+
+// Detect returns the service manager for the given GOOS.
+func Detect(goos string) (Kind, error) {
+	switch goos {
+	case "linux":
+		return Systemd, nil
+	case "darwin":
+		return Launchd, nil
+	default:
+		return "", fmt.Errorf("service: no supported service manager on %s", goos)
+	}
+}
+
+// UnitPath returns where the unit for kind is installed under home.
+func UnitPath(kind Kind, home string) string {
+	if kind == Launchd {
+		return filepath.Join(home, "Library", "LaunchAgents", Label+".plist")
+	}
+	return filepath.Join(home, ".config", "systemd", "user", "prompt-pulse.service")
+}
+
+// Render returns the unit file contents for kind.
+func Render(kind Kind, cfg Config) (string, error) {
+	if cfg.BinaryPath == "" {
+		return "", errors.New("service: binary path is required")
+	}
+	if cfg.CacheDir == "" {
+		return "", errors.New("service: cache dir is required")
+	}
+	var tmpl *template.Template
+	switch kind {
+	case Systemd:
+		tmpl = systemdTemplate
+	case Launchd:
+		tmpl = launchdTemplate
+	default:
+		return "", fmt.Errorf("service: unknown kind %q", kind)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, cfg); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Install writes the unit for kind under home and creates the cache
+// directory the unit logs to, private to the user. An existing unit is
+// only replaced when force is set. It returns the path written.
+func Install(kind Kind, cfg Config, home string, force bool) (string, error) {
+	content, err := Render(kind, cfg)
+	if err != nil {
+		return "", err
+	}
+	path := UnitPath(kind, home)
+	if _, err := os.Stat(path); err == nil && !force {
+		return path, fmt.Errorf("%w: %s (use -force to replace it)", ErrExists, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return path, fmt.Errorf("service: create unit directory: %w", err)
+	}
+	if err := cache.EnsurePrivateDir(cfg.CacheDir); err != nil {
+		return path, fmt.Errorf("service: create cache dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return path, fmt.Errorf("service: write unit: %w", err)
+	}
+	return path, nil
+}
+
+// Uninstall removes the unit for kind under home. It returns the path and
+// an error wrapping os.ErrNotExist when no unit is installed.
+func Uninstall(kind Kind, home string) (string, error) {
+	path := UnitPath(kind, home)
+	if err := os.Remove(path); err != nil {
+		return path, fmt.Errorf("service: remove unit: %w", err)
+	}
+	return path, nil
+}
+
+// ActivateHint returns the command that loads and starts an installed unit.
+func ActivateHint(kind Kind, path string) string {
+	if kind == Launchd {
+		return "launchctl load -w " + path
+	}
+	return "systemctl --user daemon-reload && systemctl --user enable --now prompt-pulse.service"
+}
+
+// DeactivateHint returns the command that stops a unit before removal.
+func DeactivateHint(kind Kind, path string) string {
+	if kind == Launchd {
+		return "launchctl unload -w " + path
+	}
+	return "systemctl --user disable --now prompt-pulse.service"
+}
+
+// systemdQuote quotes a word for an ExecStart= line when it contains
+// characters systemd would otherwise split or expand.
+func systemdQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\$%") {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`, `%`, `%%`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// xmlEscape escapes s for use as plist character data.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+var funcs = template.FuncMap{
+	"execStart": func(args []string) string {
+		quoted := make([]string, len(args))
+		for i, a := range args {
+			quoted[i] = systemdQuote(a)
+		}
+		return strings.Join(quoted, " ")
+	},
+	"xml": xmlEscape,
+}
+
+var systemdTemplate = template.Must(template.New("systemd").Funcs(funcs).Parse(`[Unit]
+Description=prompt-pulse status daemon
+After=network-online.target
+
+[Service]
+Type=simple
+ExecStart={{ execStart .Args }}
+Restart=on-failure
+RestartSec=5
+StandardOutput=append:{{ .LogPath }}
+StandardError=append:{{ .LogPath }}
+
+[Install]
+WantedBy=default.target
+`))
+
+var launchdTemplate = template.Must(template.New("launchd").Funcs(funcs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>` + Label + `</string>
+	<key>ProgramArguments</key>
+	<array>
+{{- range .Args }}
+		<string>{{ xml . }}</string>
+{{- end }}
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>StandardOutPath</key>
+	<string>{{ xml .LogPath }}</string>
+	<key>StandardErrorPath</key>
+	<string>{{ xml .LogPath }}</string>
+</dict>
+</plist>
+`))
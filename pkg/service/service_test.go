@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testConfig() Config {
+	return Config{
+		BinaryPath: "/usr/local/bin/prompt-pulse",
+		ConfigPath: "/home/me/.config/prompt-pulse/config.toml",
+		CacheDir:   "/home/me/.cache/prompt-pulse",
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for goos, want := range map[string]Kind{"linux": Systemd, "darwin": Launchd} {
+		if got, err := Detect(goos); err != nil || got != want {
+			t.Errorf("Detect(%s) = %q, %v; want %q", goos, got, err, want)
+		}
+	}
+	if _, err := Detect("windows"); err == nil {
+		t.Error("expected an error for windows")
+	}
+}
+
+func TestRenderSystemd(t *testing.T) {
+	unit, err := Render(Systemd, testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"[Unit]\n",
+		"[Service]\n",
+		"ExecStart=/usr/local/bin/prompt-pulse -daemon -config /home/me/.config/prompt-pulse/config.toml\n",
+		"StandardOutput=append:/home/me/.cache/prompt-pulse/daemon.log\n",
+		"Restart=on-failure\n",
+		"[Install]\nWantedBy=default.target\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit missing %q:\n%s", want, unit)
+		}
+	}
+}
+
+func TestRenderSystemd_QuotesAndOmitsConfig(t *testing.T) {
+	cfg := testConfig()
+	cfg.BinaryPath = "/opt/My Tools/prompt-pulse"
+	cfg.ConfigPath = ""
+	unit, err := Render(Systemd, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(unit, "ExecStart=\"/opt/My Tools/prompt-pulse\" -daemon\n") {
+		t.Errorf("ExecStart not quoted or -config not omitted:\n%s", unit)
+	}
+}
+
+func TestRenderLaunchd(t *testing.T) {
+	cfg := testConfig()
+	cfg.ConfigPath = "/Users/me/R&D/config.toml"
+	plist, err := Render(Launchd, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<string>" + Label + "</string>",
+		"\t\t<string>/usr/local/bin/prompt-pulse</string>\n\t\t<string>-daemon</string>\n\t\t<string>-config</string>\n\t\t<string>/Users/me/R&amp;D/config.toml</string>\n",
+		"<key>StandardOutPath</key>\n\t<string>/home/me/.cache/prompt-pulse/daemon.log</string>",
+		"<key>RunAtLoad</key>\n\t<true/>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist missing %q:\n%s", want, plist)
+		}
+	}
+}
+
+func TestRender_RequiresPaths(t *testing.T) {
+	if _, err := Render(Systemd, Config{CacheDir: "/tmp"}); err == nil {
+		t.Error("expected an error without a binary path")
+	}
+	if _, err := Render(Systemd, Config{BinaryPath: "/bin/pp"}); err == nil {
+		t.Error("expected an error without a cache dir")
+	}
+}
+
+func TestInstallRefusesOverwrite(t *testing.T) {
+	home := t.TempDir()
+	cfg := testConfig()
+	cfg.CacheDir = filepath.Join(home, "cache")
+
+	path, err := Install(Systemd, cfg, home, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(home, ".config", "systemd", "user", "prompt-pulse.service") {
+		t.Errorf("path = %s", path)
+	}
+	if info, err := os.Stat(cfg.CacheDir); err != nil {
+		t.Errorf("cache dir not created: %v", err)
+	} else if mode := info.Mode().Perm(); mode != 0o700 {
+		t.Errorf("cache dir mode = %o, want 700", mode)
+	}
+
+	os.WriteFile(path, []byte("hand edited"), 0o644)
+	if _, err := Install(Systemd, cfg, home, false); !errors.Is(err, ErrExists) {
+		t.Fatalf("second install error = %v, want ErrExists", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "hand edited" {
+		t.Error("existing unit was overwritten without force")
+	}
+	if _, err := Install(Systemd, cfg, home, true); err != nil {
+		t.Fatalf("forced install: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "ExecStart=") {
+		t.Error("forced install did not replace the unit")
+	}
+}
+
+func TestUninstall(t *testing.T) {
+	home := t.TempDir()
+	cfg := testConfig()
+	cfg.CacheDir = filepath.Join(home, "cache")
+	if _, err := Install(Launchd, cfg, home, false); err != nil {
+		t.Fatal(err)
+	}
+	path, err := Uninstall(Launchd, home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("plist still present after uninstall")
+	}
+	if _, err := Uninstall(Launchd, home); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("second uninstall error = %v, want ErrNotExist", err)
+	}
+}
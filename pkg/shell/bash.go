@@ -23,8 +23,12 @@ func shBashBanner(opts Options) string {
 		return ""
 	}
 	bin := shQuote(opts.BinaryPath)
+	cond := `[ "${PROMPT_PULSE_BANNER:-1}" != "0" ]`
+	if guard := shSessionGuard(Bash, opts.ShowBannerOn); guard != "" {
+		cond += " && " + guard
+	}
 	return fmt.Sprintf(`# Display banner on shell startup
-if [ "${PROMPT_PULSE_BANNER:-1}" != "0" ]; then
+if %s; then
     %s -banner 2>/dev/null
 fi
 
@@ -36,7 +40,7 @@ if [[ "$PROMPT_COMMAND" != *"__prompt_pulse_precmd"* ]]; then
     PROMPT_COMMAND="__prompt_pulse_precmd;${PROMPT_COMMAND:-}"
 fi
 
-`, bin)
+`, cond, bin)
 }
 
 // shBashKeybinding generates the keybinding block for Bash.
@@ -0,0 +1,199 @@
+package shell
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// CompletionFlag describes one command-line flag for a completion script.
+type CompletionFlag struct {
+	// Name is the flag name without its leading dash.
+	Name string
+
+	// Usage is the flag's help text, shown by shells that display
+	// descriptions next to candidates.
+	Usage string
+
+	// HasArg is set for flags that take a value.
+	HasArg bool
+
+	// Values lists the accepted values of a flag that takes one.
+	Values []string
+
+	// Files completes the flag's value as a file name.
+	Files bool
+}
+
+// CompletionShells lists the shells Completion can generate scripts for.
+var CompletionShells = []ShellType{Bash, Zsh, Fish}
+
+// CompletionFlags describes every flag defined in fs, in name order. values
+// maps flag names to the values they accept, and files names the flags
+// whose value is a path.
+func CompletionFlags(fs *flag.FlagSet, values map[string][]string, files []string) []CompletionFlag {
+	isFile := make(map[string]bool, len(files))
+	for _, name := range files {
+		isFile[name] = true
+	}
+	var flags []CompletionFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		isBool := false
+		if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok {
+			isBool = bf.IsBoolFlag()
+		}
+		flags = append(flags, CompletionFlag{
+			Name:   f.Name,
+			Usage:  f.Usage,
+			HasArg: !isBool,
+			Values: values[f.Name],
+			Files:  isFile[f.Name],
+		})
+	})
+	return flags
+}
+
+// Completion returns a tab-completion script for the prog command line,
+// completing the given flags and their values. Unlike Generate, it only
+// covers the CLI itself; users load it with, for example,
+// `prompt-pulse -completion bash > /etc/bash_completion.d/prompt-pulse`.
+func Completion(shell ShellType, prog string, flags []CompletionFlag) (string, error) {
+	switch shell {
+	case Bash:
+		return shBashCompletion(prog, flags), nil
+	case Zsh:
+		return shZshCompletion(prog, flags), nil
+	case Fish:
+		return shFishCompletion(prog, flags), nil
+	default:
+		names := make([]string, len(CompletionShells))
+		for i, s := range CompletionShells {
+			names[i] = string(s)
+		}
+		return "", fmt.Errorf("completion for %q is not supported (supported: %s)", shell, strings.Join(names, ", "))
+	}
+}
+
+// shBashCompletion generates a Bash completion function for prog.
+func shBashCompletion(prog string, flags []CompletionFlag) string {
+	fn := "_" + shIdent(prog)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", prog)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur prev\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	b.WriteString("    case \"$prev\" in\n")
+	var fileFlags, freeFlags []string
+	for _, f := range flags {
+		switch {
+		case !f.HasArg:
+		case len(f.Values) > 0:
+			fmt.Fprintf(&b, "        %s)\n", shFlagPatterns(f.Name))
+			fmt.Fprintf(&b, "            COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shQuote(strings.Join(f.Values, " ")))
+			b.WriteString("            return ;;\n")
+		case f.Files:
+			fileFlags = append(fileFlags, shFlagPatterns(f.Name))
+		default:
+			freeFlags = append(freeFlags, shFlagPatterns(f.Name))
+		}
+	}
+	if len(fileFlags) > 0 {
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(fileFlags, "|"))
+		b.WriteString("            COMPREPLY=($(compgen -f -- \"$cur\"))\n")
+		b.WriteString("            return ;;\n")
+	}
+	if len(freeFlags) > 0 {
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(freeFlags, "|"))
+		b.WriteString("            COMPREPLY=()\n")
+		b.WriteString("            return ;;\n")
+	}
+	b.WriteString("    esac\n")
+	names := make([]string, len(flags))
+	for i, f := range flags {
+		names[i] = "-" + f.Name
+	}
+	fmt.Fprintf(&b, "    COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shQuote(strings.Join(names, " ")))
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, prog)
+	return b.String()
+}
+
+// shFlagPatterns returns the case pattern matching both spellings of a
+// flag: Go's flag package accepts -name and --name.
+func shFlagPatterns(name string) string {
+	return "-" + name + "|--" + name
+}
+
+// shZshCompletion generates a Zsh completion function for prog.
+func shZshCompletion(prog string, flags []CompletionFlag) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n\n", prog)
+	fmt.Fprintf(&b, "_%s() {\n", shIdent(prog))
+	b.WriteString("    _arguments \\\n")
+	for i, f := range flags {
+		spec := "-" + f.Name + "[" + shZshEscape(f.Usage) + "]"
+		switch {
+		case !f.HasArg:
+		case len(f.Values) > 0:
+			spec += ":" + f.Name + ":(" + strings.Join(f.Values, " ") + ")"
+		case f.Files:
+			spec += ":" + f.Name + ":_files"
+		default:
+			spec += ":" + f.Name + ": "
+		}
+		b.WriteString("        " + shQuote(spec))
+		if i < len(flags)-1 {
+			b.WriteString(" \\")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n\n")
+	// Autoloaded from $fpath the file is the completion function itself;
+	// sourced, it registers the function instead.
+	fmt.Fprintf(&b, "if [ \"$funcstack[1]\" = \"_%s\" ]; then\n", shIdent(prog))
+	fmt.Fprintf(&b, "    _%s \"$@\"\n", shIdent(prog))
+	b.WriteString("else\n")
+	fmt.Fprintf(&b, "    compdef _%s %s\n", shIdent(prog), prog)
+	b.WriteString("fi\n")
+	return b.String()
+}
+
+// shZshEscape escapes the characters _arguments treats specially inside an
+// option description.
+func shZshEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(s)
+}
+
+// shFishCompletion generates Fish complete commands for prog. Flags are
+// registered as old-style (single-dash) options, matching how they are
+// documented.
+func shFishCompletion(prog string, flags []CompletionFlag) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", prog)
+	fmt.Fprintf(&b, "complete -c %s -f\n", prog)
+	for _, f := range flags {
+		line := fmt.Sprintf("complete -c %s -o %s", prog, f.Name)
+		switch {
+		case f.HasArg && len(f.Values) > 0:
+			line += " -x -a " + shFishQuote(strings.Join(f.Values, " "))
+		case f.HasArg && f.Files:
+			line += " -r -F"
+		case f.HasArg:
+			line += " -x"
+		}
+		line += " -d " + shFishQuote(f.Usage)
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// shIdent turns prog into a shell function name fragment.
+func shIdent(prog string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, prog)
+}
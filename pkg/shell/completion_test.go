@@ -0,0 +1,86 @@
+package shell
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// shTestFlagSet is a small flag set covering every kind of flag the
+// completion generators handle.
+func shTestFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("prompt-pulse", flag.ContinueOnError)
+	fs.Bool("banner", false, "Display system status banner")
+	fs.String("config", "", "Path to configuration file (default: ~/.config/prompt-pulse/config.toml)")
+	fs.String("shell", "", "Output shell integration script (bash|zsh|fish)")
+	fs.String("theme", "", "Theme override")
+	fs.Duration("watch-interval", time.Second, "Redraw interval for [watch] mode")
+	fs.Bool("quote", false, "Don't break on 'quotes'")
+	return fs
+}
+
+func shTestCompletionFlags() []CompletionFlag {
+	return CompletionFlags(shTestFlagSet(), map[string][]string{
+		"shell": {"bash", "zsh", "fish"},
+		"theme": {"catppuccin-mocha", "nord"},
+	}, []string{"config"})
+}
+
+func TestCompletionFlags(t *testing.T) {
+	flags := shTestCompletionFlags()
+	if len(flags) != 6 {
+		t.Fatalf("got %d flags, want 6", len(flags))
+	}
+	byName := map[string]CompletionFlag{}
+	for _, f := range flags {
+		byName[f.Name] = f
+	}
+	if byName["banner"].HasArg || byName["quote"].HasArg {
+		t.Error("bool flags should not take an argument")
+	}
+	if !byName["config"].HasArg || !byName["watch-interval"].HasArg {
+		t.Error("string and duration flags should take an argument")
+	}
+	if got := byName["shell"].Values; len(got) != 3 {
+		t.Errorf("shell values = %v", got)
+	}
+	if !byName["config"].Files || byName["watch-interval"].Files {
+		t.Error("only -config should complete file names")
+	}
+}
+
+func TestCompletion_Golden(t *testing.T) {
+	for _, sh := range CompletionShells {
+		t.Run(string(sh), func(t *testing.T) {
+			got, err := Completion(sh, "prompt-pulse", shTestCompletionFlags())
+			if err != nil {
+				t.Fatalf("Completion(%s) error: %v", sh, err)
+			}
+			path := filepath.Join("testdata", "completion."+string(sh)+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden file (run with -update to create): %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("Completion(%s) differs from %s:\n%s", sh, path, got)
+			}
+		})
+	}
+}
+
+func TestCompletion_UnsupportedShell(t *testing.T) {
+	_, err := Completion(PowerShell, "prompt-pulse", nil)
+	if err == nil || !strings.Contains(err.Error(), "supported: bash, zsh, fish") {
+		t.Errorf("Completion(powershell) error = %v", err)
+	}
+}
@@ -76,7 +76,7 @@ func shDetectDarwinParent(ppid int) ShellType {
 }
 
 // shParseShellName maps a shell binary name (e.g. "zsh", "bash", "fish",
-// "ksh", "ksh93") to a ShellType. Returns empty string if unrecognized.
+// "ksh", "ksh93", "pwsh") to a ShellType. Returns empty string if unrecognized.
 func shParseShellName(name string) ShellType {
 	// Strip leading dash for login shells (e.g., "-zsh").
 	name = strings.TrimPrefix(name, "-")
@@ -91,6 +91,8 @@ func shParseShellName(name string) ShellType {
 		return Fish
 	case "ksh", "ksh93", "mksh", "pdksh":
 		return Ksh
+	case "pwsh", "pwsh.exe", "powershell", "powershell.exe":
+		return PowerShell
 	default:
 		return ""
 	}
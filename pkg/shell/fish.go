@@ -24,16 +24,20 @@ func shFishBanner(opts Options) string {
 		return ""
 	}
 	bin := shFishQuote(opts.BinaryPath)
+	cond := `test "$PROMPT_PULSE_BANNER" != "0"`
+	if guard := shSessionGuard(Fish, opts.ShowBannerOn); guard != "" {
+		cond += "; and " + guard
+	}
 	return fmt.Sprintf(`# Display banner on shell startup via fish_prompt event
 function __prompt_pulse_banner --on-event fish_prompt
-    if test "$PROMPT_PULSE_BANNER" != "0"
+    if %s
         %s -banner 2>/dev/null
     end
     # Only show banner once per session; remove handler after first call
     functions -e __prompt_pulse_banner
 end
 
-`, bin)
+`, cond, bin)
 }
 
 // shFishKeybinding generates the keybinding block for Fish, binding in all
@@ -87,7 +91,7 @@ func shFishCompletions(opts Options) string {
 complete -c prompt-pulse -l banner -d "Display system status banner"
 complete -c prompt-pulse -l tui -d "Launch interactive TUI dashboard"
 complete -c prompt-pulse -l daemon -d "Run background daemon"
-complete -c prompt-pulse -l shell -r -d "Generate shell integration (bash|zsh|fish|ksh)"
+complete -c prompt-pulse -l shell -r -d "Generate shell integration (bash|zsh|fish|ksh|powershell)"
 complete -c prompt-pulse -l version -d "Show version information"
 complete -c prompt-pulse -l health -d "Check daemon health status"
 
@@ -23,15 +23,19 @@ func shKshBanner(opts Options) string {
 		return ""
 	}
 	bin := shQuote(opts.BinaryPath)
+	cond := `[ "${PROMPT_PULSE_BANNER:-1}" != "0" ]`
+	if guard := shSessionGuard(Ksh, opts.ShowBannerOn); guard != "" {
+		cond += " && " + guard
+	}
 	return fmt.Sprintf(`# Display banner on shell startup
-if [ "${PROMPT_PULSE_BANNER:-1}" != "0" ]; then
+if %s; then
     %s -banner 2>/dev/null
 fi
 
 # Inline banner via PS1 command substitution
 PS1='$(prompt-pulse -banner --inline 2>/dev/null)'"${PS1}"
 
-`, bin)
+`, cond, bin)
 }
 
 // shKshKeybinding generates the keybinding block for Ksh93 using the KEYBD
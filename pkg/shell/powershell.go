@@ -0,0 +1,175 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+)
+
+// shGeneratePowerShell produces the PowerShell integration script. The
+// snippet targets both Windows PowerShell 5.1 and PowerShell 7 (pwsh).
+func shGeneratePowerShell(opts Options) string {
+	s := `# prompt-pulse shell integration for PowerShell
+# prompt-pulse -shell powershell | Out-String | Invoke-Expression  (in your $PROFILE)
+
+`
+	s += shPwshBinary(opts)
+	s += shPwshPrompt(opts)
+	s += shPwshBanner(opts)
+	s += shPwshKeybinding(opts)
+	s += shPwshCompletions(opts)
+	s += shPwshDaemonFunctions(opts)
+	s += shPwshDaemonAutoStart(opts)
+	return s
+}
+
+// shPwshBinary resolves the binary once so the remaining blocks can skip
+// themselves quietly when prompt-pulse is not on PATH.
+func shPwshBinary(opts Options) string {
+	return fmt.Sprintf(`# Resolve the prompt-pulse binary; every block below is a no-op without it
+$global:__PromptPulseBin = %s
+$global:__PromptPulseAvailable = [bool](Get-Command $global:__PromptPulseBin -ErrorAction SilentlyContinue)
+
+`, shPwshQuote(opts.BinaryPath))
+}
+
+// shPwshPrompt wraps the existing prompt function so the prompt-pulse status
+// line is printed above whatever prompt the user already has.
+func shPwshPrompt(opts Options) string {
+	return `# Wrap the existing prompt with the prompt-pulse status line
+if (-not $global:__PromptPulseOriginalPrompt) {
+    $global:__PromptPulseOriginalPrompt = $function:prompt
+}
+$function:prompt = {
+    if ($global:__PromptPulseAvailable) {
+        $line = & $global:__PromptPulseBin -starship all 2>$null
+        if ($line) { Write-Host $line }
+    }
+    & $global:__PromptPulseOriginalPrompt
+}
+
+`
+}
+
+// shPwshBanner generates the banner display block for PowerShell.
+func shPwshBanner(opts Options) string {
+	if !opts.ShowBanner {
+		return ""
+	}
+	cond := `$global:__PromptPulseAvailable -and $env:PROMPT_PULSE_BANNER -ne '0'`
+	if guard := shSessionGuard(PowerShell, opts.ShowBannerOn); guard != "" {
+		cond += " -and " + guard
+	}
+	return fmt.Sprintf(`# Display banner on shell startup
+if (%s) {
+    & $global:__PromptPulseBin -banner 2>$null
+}
+
+`, cond)
+}
+
+// shPwshKeybinding binds the TUI launcher through PSReadLine when the module
+// is loaded.
+func shPwshKeybinding(opts Options) string {
+	chord := shPwshChord(opts.Keybinding)
+	return fmt.Sprintf(`# Launch TUI with keybinding (%s)
+if (Get-Module -Name PSReadLine) {
+    Set-PSReadLineKeyHandler -Chord %s -ScriptBlock {
+        if (Get-Command prompt-pulse-tui -ErrorAction SilentlyContinue) {
+            prompt-pulse-tui
+            [Microsoft.PowerShell.PSConsoleReadLine]::InvokePrompt()
+        }
+    }
+}
+
+`, opts.Keybinding, shPwshQuote(chord))
+}
+
+// shPwshCompletions registers a native argument completer for the
+// prompt-pulse flags.
+func shPwshCompletions(opts Options) string {
+	if !opts.EnableCompletions {
+		return ""
+	}
+	return `# Tab completions
+Register-ArgumentCompleter -Native -CommandName prompt-pulse -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    '-banner', '-daemon', '-starship', '-shell', '-health', '-version' |
+        Where-Object { $_ -like "$wordToComplete*" } |
+        ForEach-Object { [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterName', $_) }
+}
+
+`
+}
+
+// shPwshDaemonFunctions generates the pp-start/pp-stop/pp-status functions
+// for PowerShell.
+func shPwshDaemonFunctions(opts Options) string {
+	return `# Daemon management functions
+function global:__PromptPulseStartDaemon {
+    $spArgs = @{ FilePath = $global:__PromptPulseBin; ArgumentList = '-daemon'; PassThru = $true }
+    # -WindowStyle is only supported on Windows.
+    if ($IsWindows -or $PSVersionTable.PSEdition -eq 'Desktop') { $spArgs.WindowStyle = 'Hidden' }
+    Start-Process @spArgs
+}
+
+function pp-start {
+    if (-not $global:__PromptPulseAvailable) { return }
+    $p = __PromptPulseStartDaemon
+    Write-Host "prompt-pulse daemon started (PID $($p.Id))"
+}
+
+function pp-stop {
+    $procs = Get-Process -Name prompt-pulse -ErrorAction SilentlyContinue
+    if ($procs) {
+        $procs | Stop-Process
+        Write-Host 'prompt-pulse daemon stopped'
+    } else {
+        Write-Host 'daemon not running'
+    }
+}
+
+function pp-status {
+    if ($global:__PromptPulseAvailable) { & $global:__PromptPulseBin -health }
+}
+
+function pp-banner {
+    if ($global:__PromptPulseAvailable) { & $global:__PromptPulseBin -banner }
+}
+
+`
+}
+
+// shPwshDaemonAutoStart generates the auto-start check for PowerShell.
+func shPwshDaemonAutoStart(opts Options) string {
+	if !opts.DaemonAutoStart {
+		return ""
+	}
+	return `# Auto-start daemon if not running
+if ($global:__PromptPulseAvailable) {
+    & $global:__PromptPulseBin -health *> $null
+    if ($LASTEXITCODE -ne 0) {
+        __PromptPulseStartDaemon | Out-Null
+    }
+}
+
+`
+}
+
+// shPwshQuote wraps a string in PowerShell single quotes. Embedded single
+// quotes are escaped by doubling them, which is the only escape recognised
+// inside a verbatim string.
+func shPwshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// shPwshChord converts a readline-style keybinding ("\C-p") into the
+// PSReadLine chord syntax ("Ctrl+p"). Other specs pass through unchanged.
+func shPwshChord(kb string) string {
+	if strings.HasPrefix(kb, `\C-`) && len(kb) == 4 {
+		return "Ctrl+" + kb[3:]
+	}
+	if strings.HasPrefix(kb, "ctrl-") && len(kb) == 6 {
+		return "Ctrl+" + kb[5:]
+	}
+	return kb
+}
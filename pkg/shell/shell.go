@@ -12,7 +12,10 @@
 // other packages in the prompt-pulse module.
 package shell
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ShellType identifies a supported shell for integration script generation.
 type ShellType string
@@ -26,6 +29,8 @@ const (
 	Fish ShellType = "fish"
 	// Ksh is the KornShell 93.
 	Ksh ShellType = "ksh"
+	// PowerShell is Windows PowerShell 5.1 or cross-platform PowerShell 7.
+	PowerShell ShellType = "powershell"
 )
 
 // Options controls how the generated shell integration behaves.
@@ -35,7 +40,8 @@ type Options struct {
 	BinaryPath string
 
 	// Keybinding is the key combo to launch TUI.
-	// Defaults vary by shell: "\C-p" for bash/zsh/ksh, "ctrl-p" for fish.
+	// Defaults vary by shell: "\C-p" for bash/zsh/ksh, "ctrl-p" for fish,
+	// "Ctrl+p" for PowerShell.
 	Keybinding string
 
 	// WaifuKeybinding is the key combo to launch TUI in expanded waifu mode.
@@ -46,6 +52,10 @@ type Options struct {
 	// ShowBanner displays the system status banner on shell start.
 	ShowBanner bool
 
+	// ShowBannerOn limits the startup banner to SSH or local sessions.
+	// The zero value shows it in every session.
+	ShowBannerOn BannerSession
+
 	// DaemonAutoStart auto-starts the daemon if not running on shell init.
 	DaemonAutoStart bool
 
@@ -53,6 +63,73 @@ type Options struct {
 	EnableCompletions bool
 }
 
+// BannerSession selects the sessions the startup banner is shown in.
+type BannerSession string
+
+const (
+	// BannerAlways shows the banner in every session.
+	BannerAlways BannerSession = "always"
+	// BannerSSH shows the banner only when SSH_CONNECTION or SSH_TTY is
+	// set, i.e. in a login over SSH.
+	BannerSSH BannerSession = "ssh"
+	// BannerLocal shows the banner only when neither is set.
+	BannerLocal BannerSession = "local"
+)
+
+// BannerSessions lists the supported BannerSession values in documentation
+// order.
+var BannerSessions = []BannerSession{BannerSSH, BannerLocal, BannerAlways}
+
+// ParseBannerSession parses a shell.show_banner_on value. An empty string
+// selects BannerAlways.
+func ParseBannerSession(s string) (BannerSession, error) {
+	if s == "" {
+		return BannerAlways, nil
+	}
+	for _, b := range BannerSessions {
+		if BannerSession(strings.ToLower(s)) == b {
+			return b, nil
+		}
+	}
+	names := make([]string, len(BannerSessions))
+	for i, b := range BannerSessions {
+		names[i] = string(b)
+	}
+	return "", fmt.Errorf("unknown banner session %q (supported: %s)", s, strings.Join(names, ", "))
+}
+
+// shSessionGuard returns the condition, in the syntax of shell, that holds
+// in the sessions on selects, or "" when the banner shows in every
+// session. The banner blocks append it to their PROMPT_PULSE_BANNER check.
+func shSessionGuard(shell ShellType, on BannerSession) string {
+	if on != BannerSSH && on != BannerLocal {
+		return ""
+	}
+	ssh := on == BannerSSH
+	switch shell {
+	case Fish:
+		if ssh {
+			return `test -n "$SSH_CONNECTION$SSH_TTY"`
+		}
+		return `test -z "$SSH_CONNECTION$SSH_TTY"`
+	case PowerShell:
+		if ssh {
+			return `($env:SSH_CONNECTION -or $env:SSH_TTY)`
+		}
+		return `-not ($env:SSH_CONNECTION -or $env:SSH_TTY)`
+	case Zsh:
+		if ssh {
+			return `[[ -n "${SSH_CONNECTION:-}${SSH_TTY:-}" ]]`
+		}
+		return `[[ -z "${SSH_CONNECTION:-}${SSH_TTY:-}" ]]`
+	default:
+		if ssh {
+			return `[ -n "${SSH_CONNECTION:-}${SSH_TTY:-}" ]`
+		}
+		return `[ -z "${SSH_CONNECTION:-}${SSH_TTY:-}" ]`
+	}
+}
+
 // shDefaultOptions returns Options with sensible defaults filled in for the
 // given shell type. Only zero-valued fields in the caller's Options are
 // overwritten.
@@ -64,6 +141,8 @@ func shDefaultOptions(shell ShellType, opts Options) Options {
 		switch shell {
 		case Fish:
 			opts.Keybinding = "ctrl-p"
+		case PowerShell:
+			opts.Keybinding = "Ctrl+p"
 		default:
 			opts.Keybinding = `\C-p`
 		}
@@ -94,6 +173,8 @@ func Generate(shell ShellType, opts Options) string {
 		return shGenerateFish(opts)
 	case Ksh:
 		return shGenerateKsh(opts)
+	case PowerShell:
+		return shGeneratePowerShell(opts)
 	default:
 		return fmt.Sprintf("# prompt-pulse: %s integration is not supported\n", shell)
 	}
@@ -314,6 +314,68 @@ func TestShowBannerFalse_Ksh(t *testing.T) {
 	}
 }
 
+// --- ShowBannerOn gates the banner on the session type ---
+
+func TestShowBannerOn_BashZsh(t *testing.T) {
+	for _, tt := range []struct {
+		shell ShellType
+		on    BannerSession
+		want  string
+	}{
+		{Bash, BannerSSH, `if [ "${PROMPT_PULSE_BANNER:-1}" != "0" ] && [ -n "${SSH_CONNECTION:-}${SSH_TTY:-}" ]; then`},
+		{Bash, BannerLocal, `if [ "${PROMPT_PULSE_BANNER:-1}" != "0" ] && [ -z "${SSH_CONNECTION:-}${SSH_TTY:-}" ]; then`},
+		{Bash, BannerAlways, `if [ "${PROMPT_PULSE_BANNER:-1}" != "0" ]; then`},
+		{Zsh, BannerSSH, `if [[ "${PROMPT_PULSE_BANNER:-1}" != "0" ]] && [[ -n "${SSH_CONNECTION:-}${SSH_TTY:-}" ]]; then`},
+		{Zsh, BannerLocal, `if [[ "${PROMPT_PULSE_BANNER:-1}" != "0" ]] && [[ -z "${SSH_CONNECTION:-}${SSH_TTY:-}" ]]; then`},
+		{Zsh, BannerAlways, `if [[ "${PROMPT_PULSE_BANNER:-1}" != "0" ]]; then`},
+	} {
+		out := Generate(tt.shell, Options{ShowBanner: true, ShowBannerOn: tt.on})
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("%s show_banner_on=%s: missing %q in:\n%s", tt.shell, tt.on, tt.want, out)
+		}
+		if tt.on == BannerAlways && strings.Contains(out, "SSH_CONNECTION") {
+			t.Errorf("%s show_banner_on=always should not test SSH_CONNECTION", tt.shell)
+		}
+	}
+}
+
+func TestShowBannerOn_OtherShells(t *testing.T) {
+	for shell, want := range map[ShellType]string{
+		Fish:       `if test "$PROMPT_PULSE_BANNER" != "0"; and test -n "$SSH_CONNECTION$SSH_TTY"`,
+		Ksh:        `&& [ -n "${SSH_CONNECTION:-}${SSH_TTY:-}" ]; then`,
+		PowerShell: `-and ($env:SSH_CONNECTION -or $env:SSH_TTY)) {`,
+	} {
+		out := Generate(shell, Options{ShowBanner: true, ShowBannerOn: BannerSSH})
+		if !strings.Contains(out, want) {
+			t.Errorf("%s show_banner_on=ssh: missing %q", shell, want)
+		}
+	}
+}
+
+func TestShowBannerOn_DefaultIsAlways(t *testing.T) {
+	out := Generate(Bash, Options{ShowBanner: true})
+	if strings.Contains(out, "SSH_CONNECTION") {
+		t.Error("banner should not be gated on the session type by default")
+	}
+}
+
+func TestParseBannerSession(t *testing.T) {
+	for in, want := range map[string]BannerSession{
+		"":       BannerAlways,
+		"always": BannerAlways,
+		"SSH":    BannerSSH,
+		"local":  BannerLocal,
+	} {
+		got, err := ParseBannerSession(in)
+		if err != nil || got != want {
+			t.Errorf("ParseBannerSession(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseBannerSession("remote"); err == nil {
+		t.Error("ParseBannerSession(remote) succeeded, want error")
+	}
+}
+
 // --- DaemonAutoStart=true includes daemon check ---
 
 func TestDaemonAutoStart_Bash(t *testing.T) {
@@ -467,7 +529,7 @@ func TestBinaryPathWithSingleQuote(t *testing.T) {
 // --- Structural validation ---
 
 func TestAllShells_NoEmptyOutput(t *testing.T) {
-	shells := []ShellType{Bash, Zsh, Fish, Ksh}
+	shells := []ShellType{Bash, Zsh, Fish, Ksh, PowerShell}
 	for _, sh := range shells {
 		out := Generate(sh, Options{
 			ShowBanner:        true,
@@ -481,7 +543,7 @@ func TestAllShells_NoEmptyOutput(t *testing.T) {
 }
 
 func TestAllShells_ContainHeader(t *testing.T) {
-	shells := []ShellType{Bash, Zsh, Fish, Ksh}
+	shells := []ShellType{Bash, Zsh, Fish, Ksh, PowerShell}
 	for _, sh := range shells {
 		out := Generate(sh, Options{})
 		if !strings.HasPrefix(out, "# prompt-pulse shell integration") {
@@ -490,6 +552,56 @@ func TestAllShells_ContainHeader(t *testing.T) {
 	}
 }
 
+// --- PowerShell tests ---
+
+func TestPowerShell_WrapsPromptFunction(t *testing.T) {
+	out := Generate(PowerShell, Options{})
+	if !strings.Contains(out, "$function:prompt = {") {
+		t.Error("PowerShell output should override $function:prompt")
+	}
+	if !strings.Contains(out, "$global:__PromptPulseOriginalPrompt = $function:prompt") {
+		t.Error("PowerShell output should preserve the original prompt")
+	}
+}
+
+func TestPowerShell_DegradesWithoutBinary(t *testing.T) {
+	out := Generate(PowerShell, Options{ShowBanner: true, DaemonAutoStart: true})
+	if !strings.Contains(out, "Get-Command $global:__PromptPulseBin -ErrorAction SilentlyContinue") {
+		t.Error("PowerShell output should probe for the binary quietly")
+	}
+	if strings.Count(out, "$global:__PromptPulseAvailable") < 4 {
+		t.Error("prompt, banner, and autostart blocks should all be guarded by the availability check")
+	}
+}
+
+func TestPowerShell_BannerAndAutoStartOptional(t *testing.T) {
+	out := Generate(PowerShell, Options{})
+	if strings.Contains(out, "-banner 2>$null") {
+		t.Error("banner block should be omitted when ShowBanner is false")
+	}
+	if strings.Contains(out, "Auto-start daemon") {
+		t.Error("autostart block should be omitted when DaemonAutoStart is false")
+	}
+}
+
+func TestPowerShell_KeybindingChord(t *testing.T) {
+	out := Generate(PowerShell, Options{})
+	if !strings.Contains(out, "Set-PSReadLineKeyHandler -Chord 'Ctrl+p'") {
+		t.Errorf("expected default Ctrl+p chord, got: %s", out)
+	}
+	out = Generate(PowerShell, Options{Keybinding: `\C-g`})
+	if !strings.Contains(out, "-Chord 'Ctrl+g'") {
+		t.Error("readline-style keybinding should convert to a PSReadLine chord")
+	}
+}
+
+func TestPowerShell_QuotesBinaryPath(t *testing.T) {
+	out := Generate(PowerShell, Options{BinaryPath: `C:\Program Files\it's\prompt-pulse.exe`})
+	if !strings.Contains(out, `$global:__PromptPulseBin = 'C:\Program Files\it''s\prompt-pulse.exe'`) {
+		t.Errorf("binary path should be single-quoted with doubled quotes, got: %s", out)
+	}
+}
+
 // --- shParseShellName tests ---
 
 func TestParseShellName(t *testing.T) {
@@ -503,6 +615,8 @@ func TestParseShellName(t *testing.T) {
 		{"ksh", Ksh},
 		{"ksh93", Ksh},
 		{"mksh", Ksh},
+		{"pwsh", PowerShell},
+		{"powershell.exe", PowerShell},
 		{"-zsh", Zsh},
 		{"-bash", Bash},
 		{"tcsh", ""},
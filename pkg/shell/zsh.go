@@ -23,8 +23,12 @@ func shZshBanner(opts Options) string {
 		return ""
 	}
 	bin := shQuote(opts.BinaryPath)
+	cond := `[[ "${PROMPT_PULSE_BANNER:-1}" != "0" ]]`
+	if guard := shSessionGuard(Zsh, opts.ShowBannerOn); guard != "" {
+		cond += " && " + guard
+	}
 	return fmt.Sprintf(`# Display banner on shell startup
-if [[ "${PROMPT_PULSE_BANNER:-1}" != "0" ]]; then
+if %s; then
     %s -banner 2>/dev/null
 fi
 
@@ -35,7 +39,7 @@ __prompt_pulse_precmd() {
 }
 add-zsh-hook precmd __prompt_pulse_precmd
 
-`, bin)
+`, cond, bin)
 }
 
 // shZshKeybinding generates the keybinding block for Zsh using a ZLE widget
@@ -5,31 +5,37 @@ package starship
 
 import (
 	"encoding/json"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
 )
 
-// ssMaxCacheAge is the maximum age of a cache file before it is considered
-// stale and ignored. Collectors are expected to refresh more frequently.
+// ssMaxCacheAge is the default maximum age of a cache file before it is
+// considered stale and ignored, used for keys without a configured TTL.
 const ssMaxCacheAge = 5 * time.Minute
 
 // ssReadCachedData reads a JSON cache file for the given collector key from
 // cacheDir. Returns nil if the file does not exist, cannot be parsed, or is
-// older than ssMaxCacheAge.
-func ssReadCachedData[T any](cacheDir, key string) (*T, error) {
+// older than maxAge (ssMaxCacheAge when maxAge is not positive).
+func ssReadCachedData[T any](cacheDir, key string, maxAge time.Duration) (*T, error) {
 	path := filepath.Join(cacheDir, key+".json")
 
 	info, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
+			slog.Debug("starship: cache miss", "key", key)
 			return nil, nil
 		}
 		return nil, err
 	}
 
 	// Reject stale data.
-	if time.Since(info.ModTime()) > ssMaxCacheAge {
+	if maxAge <= 0 {
+		maxAge = ssMaxCacheAge
+	}
+	if age := time.Since(info.ModTime()); age > maxAge {
+		slog.Debug("starship: cache stale", "key", key, "age", age)
 		return nil, nil
 	}
 
@@ -42,6 +48,14 @@ func ssReadCachedData[T any](cacheDir, key string) (*T, error) {
 	if err := json.Unmarshal(data, &v); err != nil {
 		return nil, err
 	}
+	slog.Debug("starship: cache hit", "key", key)
 
 	return &v, nil
 }
+
+// ssCacheMissing reports whether the cache file for key does not exist at
+// all, as opposed to being stale or unreadable.
+func ssCacheMissing(cacheDir, key string) bool {
+	_, err := os.Stat(filepath.Join(cacheDir, key+".json"))
+	return os.IsNotExist(err)
+}
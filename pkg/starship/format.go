@@ -67,10 +67,34 @@ func ssStripAnsi(s string) string {
 	return b.String()
 }
 
-// ssFormatLine joins the given segments with a dim separator, applies ANSI
-// colors, and drops rightmost segments if the total visible width exceeds
-// maxWidth. Returns an empty string if segments is empty.
-func ssFormatLine(segments []*Segment, maxWidth int) string {
+// ssLineWidth returns the visible width ssFormatLine would produce for
+// segments joined by sep if none had to be dropped.
+func ssLineWidth(segments []*Segment, sep string) int {
+	sepWidth := ssVisibleWidth(ssJoiner(sep))
+	total := 0
+	for i, seg := range segments {
+		if i > 0 {
+			total += sepWidth
+		}
+		total += ssVisibleWidth(seg.Icon + " " + seg.Text)
+	}
+	return total
+}
+
+// ssJoiner returns the string placed between two segments: sep padded with
+// a space on each side, or the dim default separator when sep is empty.
+func ssJoiner(sep string) string {
+	if sep == "" {
+		sep = ssSeparator
+	}
+	return " " + sep + " "
+}
+
+// ssFormatLine joins the given segments with sep (the dim separator when
+// empty), applies ANSI colors, and drops rightmost segments if the total
+// visible width exceeds maxWidth. Returns an empty string if segments is
+// empty.
+func ssFormatLine(segments []*Segment, maxWidth int, sep string) string {
 	if len(segments) == 0 {
 		return ""
 	}
@@ -95,8 +119,8 @@ func ssFormatLine(segments []*Segment, maxWidth int) string {
 		})
 	}
 
-	// Separator visible width is 1 character (the pipe).
-	const sepWidth = 1
+	joiner := ssJoiner(sep)
+	sepWidth := ssVisibleWidth(joiner)
 
 	// Greedily include segments left-to-right until maxWidth is exceeded.
 	var included []rendered
@@ -104,7 +128,7 @@ func ssFormatLine(segments []*Segment, maxWidth int) string {
 	for i, p := range parts {
 		needed := p.visibleWidth
 		if i > 0 {
-			needed += sepWidth + 2 // separator + surrounding spaces
+			needed += sepWidth
 		}
 		if totalVisible+needed > maxWidth {
 			break
@@ -120,7 +144,7 @@ func ssFormatLine(segments []*Segment, maxWidth int) string {
 	var b strings.Builder
 	for i, p := range included {
 		if i > 0 {
-			b.WriteString(" " + ssSeparator + " ")
+			b.WriteString(joiner)
 		}
 		b.WriteString(p.text)
 	}
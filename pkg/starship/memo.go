@@ -0,0 +1,77 @@
+package starship
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ssRenderMemo returns the rendered line memoized in cfg.CacheDir when it is
+// younger than cfg.MemoTTL, and otherwise renders it and replaces the memo.
+// Every prompt runs a separate process, so the memo lives on disk. It is
+// written to a temporary file and renamed into place, so concurrent prompts
+// read either the previous line or the new one, never a partial write.
+func ssRenderMemo(cfg Config) string {
+	start := time.Now()
+	key := ssMemoKey(cfg)
+	path := filepath.Join(cfg.CacheDir, "starship-"+key+".cache")
+
+	if info, err := os.Stat(path); err == nil {
+		if age := time.Since(info.ModTime()); age >= 0 && age < cfg.MemoTTL {
+			if content, err := os.ReadFile(path); err == nil {
+				slog.Debug("starship: memo hit", "key", key, "age", age, "elapsed", time.Since(start))
+				return string(content)
+			}
+		}
+	}
+
+	line := ssRender(cfg)
+	if err := ssWriteMemo(cfg.CacheDir, path, line); err != nil {
+		// A missing memo only costs the next prompt a cache read.
+		slog.Warn("starship: write memo", "err", err)
+	}
+	return line
+}
+
+// ssMemoKey hashes everything besides the collector cache that shapes the
+// rendered line, so each distinct -starship invocation gets its own memo.
+func ssMemoKey(cfg Config) string {
+	h := sha256.New()
+	h.Write([]byte(strings.Join(cfg.modules(), ",")))
+	h.Write([]byte{0})
+	h.Write([]byte(cfg.Separator))
+	h.Write([]byte{0})
+	h.Write([]byte(cfg.PrimaryAccount))
+	h.Write([]byte{0})
+	fmt.Fprintf(h, "%d:%t:%t:%t:%s:%t:%d", cfg.MaxWidth, cfg.ClaudeSparkline, cfg.ClaudeWeekly,
+		cfg.Money.Whole, cfg.Money.Separator, cfg.Loading, cfg.SparklinePoints)
+	sum := h.Sum(nil)
+	return hex.EncodeToString(sum[:12])
+}
+
+// ssWriteMemo writes line to path via a temporary file and rename.
+func ssWriteMemo(dir, path, line string) error {
+	tmp, err := os.CreateTemp(dir, ".starship-tmp-*")
+	if err != nil {
+		return fmt.Errorf("starship memo: create temp: %w", err)
+	}
+	if _, err := tmp.WriteString(line); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("starship memo: write temp: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("starship memo: close temp: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("starship memo: rename: %w", err)
+	}
+	return nil
+}
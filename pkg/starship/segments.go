@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/anomaly"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
 )
 
 // ANSI color constants used for segment thresholds.
@@ -19,15 +22,19 @@ const (
 	ssColorRed    = "\033[31m"
 )
 
+// ssColorDim is used for the loading placeholder.
+const ssColorDim = "\033[2m"
+
 // ssBudgetDefault is the assumed monthly Claude API budget in USD when no
 // explicit budget is available. Used for threshold calculation.
 const ssBudgetDefault = 500.0
 
 // ssClaudeSegment renders the Claude/Anthropic cost segment. It shows the
-// current month's total cost and the top model by spend.
+// current month's total cost, formatted with money, and the top model by
+// spend.
 // Example: "🤖 $142.30 opus"
-func ssClaudeSegment(cacheDir string) *Segment {
-	report, err := ssReadCachedData[claude.UsageReport](cacheDir, "claude")
+func ssClaudeSegment(cacheDir string, maxAge time.Duration, money components.MoneyFormat) *Segment {
+	report, err := ssReadCachedData[claude.UsageReport](cacheDir, "claude", maxAge)
 	if err != nil || report == nil {
 		return nil
 	}
@@ -50,13 +57,17 @@ func ssClaudeSegment(cacheDir string) *Segment {
 	// strip version suffixes for brevity.
 	topModel = ssShortModelName(topModel)
 
-	text := fmt.Sprintf("$%.2f", cost)
+	text := components.FormatMoney(cost, money)
 	if topModel != "" {
 		text += " " + topModel
 	}
 
-	// Color based on percentage of budget.
+	// Color by the worst per-account threshold level when any account has
+	// a budget; otherwise fall back to the total against the default budget.
 	color := ssThresholdColor(cost, ssBudgetDefault)
+	if report.HasBudgets() {
+		color = ssLevelColors[report.Level()]
+	}
 
 	return &Segment{
 		Icon:  "🤖",
@@ -65,6 +76,77 @@ func ssClaudeSegment(cacheDir string) *Segment {
 	}
 }
 
+// ssClaudeWindows returns the utilization suffixes for the budgeted account
+// closest to its limit, or for primary when that account is connected and
+// budgeted: full is "45%/82%w" (monthly/seven-day) and short is the monthly
+// figure alone. When several accounts have a budget, both
+// are prefixed with the account's short name, e.g. "wor1 45%/82%w". color
+// reflects the worst level across both windows. Returns empty strings when
+// no account has a budget.
+func ssClaudeWindows(cacheDir string, maxAge time.Duration, primary string) (full, short, color string) {
+	report, err := ssReadCachedData[claude.UsageReport](cacheDir, "claude", maxAge)
+	if err != nil || report == nil || !report.HasBudgets() {
+		return "", "", ""
+	}
+
+	var top *claude.AccountUsage
+	budgeted := 0
+	accounts := claude.PinPrimary(report.Accounts, primary)
+	for i := range accounts {
+		a := &accounts[i]
+		if !a.Connected || a.BudgetUSD <= 0 {
+			continue
+		}
+		budgeted++
+		// A budgeted primary account comes first and is never displaced.
+		if top == nil || (top.Name != primary && a.PeakUtilization() > top.PeakUtilization()) {
+			top = a
+		}
+	}
+	if top == nil {
+		return "", "", ""
+	}
+
+	short = fmt.Sprintf("%.0f%%", top.Utilization)
+	full = fmt.Sprintf("%s/%.0f%%w", short, top.SevenDayUtilization)
+	if budgeted > 1 {
+		name := report.ShortNames()[top.Name]
+		full, short = name+" "+full, name+" "+short
+	}
+	return full, short, ssLevelColors[report.PeakLevel()]
+}
+
+// ssLevelColors maps Claude account threshold levels to segment colors.
+var ssLevelColors = map[claude.Level]string{
+	claude.LevelOK:   ssColorGreen,
+	claude.LevelWarn: ssColorYellow,
+	claude.LevelCrit: ssColorRed,
+}
+
+// ssClaudeSparkWidth is the default number of cells in the condensed
+// Claude cost history sparkline.
+const ssClaudeSparkWidth = 8
+
+// ssClaudeSparkline renders the recorded total Claude cost history as a
+// plain sparkline of at most points cells, averaging the history down to
+// fit. Returns "" until at least two samples exist, or when fewer than two
+// cells are available.
+func ssClaudeSparkline(cacheDir string, points int) string {
+	if points < 2 {
+		return ""
+	}
+	hist, err := claude.LoadHistory(claude.HistoryPath(cacheDir))
+	if err != nil {
+		return ""
+	}
+	values := components.Downsample(hist.TotalValues(), points)
+	if len(values) < 2 {
+		return ""
+	}
+	spark := components.NewSparkline(components.SparklineStyle{Width: len(values)})
+	return spark.Render(values, len(values))
+}
+
 // ssShortModelName shortens a Claude model identifier for display.
 // "claude-3-5-sonnet-20241022" -> "sonnet"
 // "claude-opus-4-20250514" -> "opus"
@@ -92,16 +174,28 @@ func ssShortModelName(model string) string {
 	return model
 }
 
+// ssPaceArrows maps billing budget pace classifications to the arrow shown
+// after the monthly spend.
+var ssPaceArrows = map[string]string{
+	billing.PaceOverPace: "↑",
+	billing.PaceOnTrack:  "→",
+	billing.PaceAhead:    "↓",
+}
+
 // ssBillingSegment renders the cloud billing segment showing total monthly
-// spend across all configured providers.
-// Example: "☁️ $23.45/mo"
-func ssBillingSegment(cacheDir string) *Segment {
-	report, err := ssReadCachedData[billing.BillingReport](cacheDir, "billing")
+// spend across all configured providers, formatted with money, followed by
+// a budget pace arrow when a budget is configured.
+// Example: "☁️ $23.45/mo ↑"
+func ssBillingSegment(cacheDir string, maxAge time.Duration, money components.MoneyFormat) *Segment {
+	report, err := ssReadCachedData[billing.BillingReport](cacheDir, "billing", maxAge)
 	if err != nil || report == nil {
 		return nil
 	}
 
-	text := fmt.Sprintf("$%.2f/mo", report.TotalMonthlyUSD)
+	text := components.FormatMoney(report.TotalMonthlyUSD, money) + "/mo"
+	if arrow, ok := ssPaceArrows[report.BudgetPace]; ok {
+		text += " " + arrow
+	}
 
 	// Use budget-based color if budget is set, otherwise use absolute thresholds.
 	var color string
@@ -118,10 +212,37 @@ func ssBillingSegment(cacheDir string) *Segment {
 	}
 }
 
+// ssBillingProjection returns the projected-over-budget suffix for the
+// billing segment, e.g. "⇡$240", or "" when the forecast is within budget.
+// It is always in whole dollars, grouped with money's separator.
+func ssBillingProjection(cacheDir string, maxAge time.Duration, money components.MoneyFormat) string {
+	report, err := ssReadCachedData[billing.BillingReport](cacheDir, "billing", maxAge)
+	if err != nil || report == nil || !report.ProjectedOverBudget {
+		return ""
+	}
+	money.Whole = true
+	return "⇡" + components.FormatMoney(report.ForecastUSD, money)
+}
+
+// ssBillingVelocity returns today's spend velocity suffix for the billing
+// segment, e.g. "1.8×", when today is spending at anomaly.VelocityWarn
+// times an average day's pace or more (see anomaly.Velocity), else "".
+func ssBillingVelocity(cacheDir string, now time.Time) string {
+	h, err := billing.LoadSpendHistory(billing.SpendHistoryPath(cacheDir))
+	if err != nil {
+		return ""
+	}
+	ratio, ok := anomaly.Velocity(h.Days, now)
+	if !ok || ratio < anomaly.VelocityWarn {
+		return ""
+	}
+	return fmt.Sprintf("%.1f×", ratio)
+}
+
 // ssTailscaleSegment renders the Tailscale peer connectivity segment.
 // Example: "🔗 3/5 peers"
-func ssTailscaleSegment(cacheDir string) *Segment {
-	status, err := ssReadCachedData[tailscale.Status](cacheDir, "tailscale")
+func ssTailscaleSegment(cacheDir string, maxAge time.Duration) *Segment {
+	status, err := ssReadCachedData[tailscale.Status](cacheDir, "tailscale", maxAge)
 	if err != nil || status == nil {
 		return nil
 	}
@@ -156,8 +277,8 @@ func ssTailscaleSegment(cacheDir string) *Segment {
 // ssK8sSegment renders the Kubernetes pod health segment. It aggregates
 // pod counts across all clusters.
 // Example: "⎈ 12/15 pods"
-func ssK8sSegment(cacheDir string) *Segment {
-	status, err := ssReadCachedData[k8s.ClusterStatus](cacheDir, "k8s")
+func ssK8sSegment(cacheDir string, maxAge time.Duration) *Segment {
+	status, err := ssReadCachedData[k8s.ClusterStatus](cacheDir, "k8s", maxAge)
 	if err != nil || status == nil {
 		return nil
 	}
@@ -198,8 +319,8 @@ func ssK8sSegment(cacheDir string) *Segment {
 // ssSystemSegment renders the system metrics segment showing CPU and RAM
 // utilization percentages.
 // Example: "💻 CPU:45% RAM:62%"
-func ssSystemSegment(cacheDir string) *Segment {
-	metrics, err := ssReadCachedData[sysmetrics.Metrics](cacheDir, "sysmetrics")
+func ssSystemSegment(cacheDir string, maxAge time.Duration) *Segment {
+	metrics, err := ssReadCachedData[sysmetrics.Metrics](cacheDir, "sysmetrics", maxAge)
 	if err != nil || metrics == nil {
 		return nil
 	}
@@ -232,6 +353,30 @@ func ssSystemSegment(cacheDir string) *Segment {
 	}
 }
 
+// ssModuleCaches maps each module to its collector cache key and icon.
+var ssModuleCaches = map[string]struct{ key, icon string }{
+	ModuleClaude:  {"claude", "🤖"},
+	ModuleBilling: {"billing", "☁️"},
+	ModuleInfra:   {"tailscale", "🔗"},
+	ModuleK8s:     {"k8s", "⎈"},
+	ModuleSystem:  {"sysmetrics", "💻"},
+}
+
+// ssLoadingSegment renders a dim placeholder for mod when its collector
+// has never written a cache file, or returns nil when the file exists.
+// Example: "🤖 …"
+func ssLoadingSegment(cacheDir, mod string) *Segment {
+	c, ok := ssModuleCaches[mod]
+	if !ok || !ssCacheMissing(cacheDir, c.key) {
+		return nil
+	}
+	return &Segment{
+		Icon:  c.icon,
+		Text:  "…",
+		Color: ssColorDim,
+	}
+}
+
 // ssThresholdColor returns a color code based on the ratio of value to
 // budget. Green for <50%, yellow for 50-80%, red for >=80%.
 func ssThresholdColor(value, budget float64) string {
@@ -1,7 +1,44 @@
 package starship
 
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
+)
+
+// Module names accepted by Config.Modules and ParseModules.
+const (
+	ModuleClaude  = "claude"
+	ModuleBilling = "billing"
+	ModuleInfra   = "infra"
+	ModuleK8s     = "k8s"
+	ModuleSystem  = "system"
+)
+
+// AllModules is the order used by "all" and by the Show* fields.
+var AllModules = []string{ModuleClaude, ModuleBilling, ModuleInfra, ModuleK8s, ModuleSystem}
+
+// ssModuleAliases maps every accepted spelling to its canonical module name.
+var ssModuleAliases = map[string]string{
+	"claude":     ModuleClaude,
+	"billing":    ModuleBilling,
+	"infra":      ModuleInfra,
+	"tailscale":  ModuleInfra,
+	"k8s":        ModuleK8s,
+	"kubernetes": ModuleK8s,
+	"system":     ModuleSystem,
+	"sys":        ModuleSystem,
+}
+
 // Config controls which segments appear in the starship output.
 type Config struct {
+	// Modules lists the segments to render, in order. When empty, the
+	// Show* fields select segments in AllModules order.
+	Modules []string
+
 	ShowClaude    bool
 	ShowBilling   bool
 	ShowTailscale bool
@@ -9,6 +46,110 @@ type Config struct {
 	ShowSystem    bool
 	CacheDir      string // where to read cached collector data
 	MaxWidth      int    // max visible width (default 60)
+
+	// CacheTTLs is the staleness cutoff per cache key ("claude",
+	// "billing", "tailscale", "k8s", "sysmetrics"). Missing keys use a
+	// five-minute default.
+	CacheTTLs map[string]time.Duration
+
+	// Separator is placed between segments, padded by a space on each
+	// side. Empty uses a dim "│".
+	Separator string
+
+	// ClaudeSparkline appends a cost history sparkline to the Claude
+	// segment when the line has room for it without dropping segments.
+	ClaudeSparkline bool
+
+	// SparklinePoints is how many points the Claude sparkline draws; the
+	// whole history is averaged into that many buckets. Zero uses eight.
+	// The room left on the line caps it.
+	SparklinePoints int
+
+	// ClaudeWeekly adds budget utilization to the Claude segment as
+	// "45%/82%w" (monthly/seven-day) and colors the segment by whichever
+	// window is higher. On a tight line the weekly figure is dropped first,
+	// then the monthly one.
+	ClaudeWeekly bool
+
+	// PrimaryAccount is the Claude account whose utilization ClaudeWeekly
+	// shows, instead of the one closest to its limit, while it is
+	// connected and budgeted.
+	PrimaryAccount string
+
+	// Money formats the dollar amounts in the Claude and billing segments.
+	// The projected-overage suffix always uses whole dollars.
+	Money components.MoneyFormat
+
+	// MemoTTL, when positive, lets Render reuse its own output for this
+	// long instead of re-reading the collector cache, so a burst of
+	// prompts costs one set of cache reads. See ssRenderMemo.
+	MemoTTL time.Duration
+
+	// Loading renders a module whose cache file does not exist yet as a
+	// dim "…" placeholder instead of hiding it, so a cold cache reads as
+	// "no data yet" rather than "nothing to show". Stale files still hide
+	// their module.
+	Loading bool
+}
+
+// modules returns the ordered module list, deriving it from the Show*
+// fields when Modules is unset.
+func (c Config) modules() []string {
+	if len(c.Modules) > 0 {
+		return c.Modules
+	}
+	var mods []string
+	for _, m := range []struct {
+		on   bool
+		name string
+	}{
+		{c.ShowClaude, ModuleClaude},
+		{c.ShowBilling, ModuleBilling},
+		{c.ShowTailscale, ModuleInfra},
+		{c.ShowK8s, ModuleK8s},
+		{c.ShowSystem, ModuleSystem},
+	} {
+		if m.on {
+			mods = append(mods, m.name)
+		}
+	}
+	return mods
+}
+
+// ParseModules parses a comma-separated, ordered module list such as
+// "billing,infra,claude". Aliases ("tailscale", "kubernetes", "sys") map to
+// their canonical names, "all" expands to AllModules, and duplicates keep
+// their first position.
+func ParseModules(spec string) ([]string, error) {
+	var mods []string
+	seen := make(map[string]bool)
+	add := func(m string) {
+		if !seen[m] {
+			seen[m] = true
+			mods = append(mods, m)
+		}
+	}
+	for _, part := range strings.Split(spec, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		if name == "all" {
+			for _, m := range AllModules {
+				add(m)
+			}
+			continue
+		}
+		m, ok := ssModuleAliases[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown starship segment: %s (supported: %s, all)", name, strings.Join(AllModules, ", "))
+		}
+		add(m)
+	}
+	if len(mods) == 0 {
+		return nil, fmt.Errorf("no starship segments given (supported: %s, all)", strings.Join(AllModules, ", "))
+	}
+	return mods, nil
 }
 
 // Segment represents a single piece of the status line.
@@ -22,46 +163,90 @@ type Segment struct {
 // starship output line.
 const ssDefaultMaxWidth = 60
 
-// Render reads cached data and produces a single-line starship module string.
-// Returns an empty string if no data is available (starship hides empty
-// modules).
+// Render reads cached data and produces a single-line starship module string
+// with segments in the configured order. Returns an empty string if no data
+// is available (starship hides empty modules). With a positive MemoTTL and a
+// CacheDir, a result rendered within the last MemoTTL for the same modules
+// and options is returned without reading the collector cache.
 func Render(cfg Config) string {
+	if cfg.MemoTTL > 0 && cfg.CacheDir != "" {
+		return ssRenderMemo(cfg)
+	}
+	return ssRender(cfg)
+}
+
+// ssRender renders cfg from the collector cache.
+func ssRender(cfg Config) string {
+	start := time.Now()
 	maxWidth := cfg.MaxWidth
 	if maxWidth <= 0 {
 		maxWidth = ssDefaultMaxWidth
 	}
 
 	var segments []*Segment
+	var claudeSeg, billingSeg *Segment
 
-	if cfg.ShowClaude {
-		if seg := ssClaudeSegment(cfg.CacheDir); seg != nil {
-			segments = append(segments, seg)
+	for _, mod := range cfg.modules() {
+		var seg *Segment
+		switch mod {
+		case ModuleClaude:
+			seg = ssClaudeSegment(cfg.CacheDir, cfg.CacheTTLs["claude"], cfg.Money)
+			claudeSeg = seg
+		case ModuleBilling:
+			seg = ssBillingSegment(cfg.CacheDir, cfg.CacheTTLs["billing"], cfg.Money)
+			billingSeg = seg
+		case ModuleInfra:
+			seg = ssTailscaleSegment(cfg.CacheDir, cfg.CacheTTLs["tailscale"])
+		case ModuleK8s:
+			seg = ssK8sSegment(cfg.CacheDir, cfg.CacheTTLs["k8s"])
+		case ModuleSystem:
+			seg = ssSystemSegment(cfg.CacheDir, cfg.CacheTTLs["sysmetrics"])
 		}
-	}
-
-	if cfg.ShowBilling {
-		if seg := ssBillingSegment(cfg.CacheDir); seg != nil {
+		if seg == nil && cfg.Loading {
+			seg = ssLoadingSegment(cfg.CacheDir, mod)
+		}
+		if seg != nil {
 			segments = append(segments, seg)
 		}
 	}
 
-	if cfg.ShowTailscale {
-		if seg := ssTailscaleSegment(cfg.CacheDir); seg != nil {
-			segments = append(segments, seg)
+	if claudeSeg != nil && cfg.ClaudeWeekly {
+		if full, short, color := ssClaudeWindows(cfg.CacheDir, cfg.CacheTTLs["claude"], cfg.PrimaryAccount); full != "" {
+			claudeSeg.Color = color
+			for _, suffix := range []string{full, short} {
+				if ssLineWidth(segments, cfg.Separator)+1+ssVisibleWidth(suffix) <= maxWidth {
+					claudeSeg.Text += " " + suffix
+					break
+				}
+			}
 		}
 	}
 
-	if cfg.ShowK8s {
-		if seg := ssK8sSegment(cfg.CacheDir); seg != nil {
-			segments = append(segments, seg)
+	if claudeSeg != nil && cfg.ClaudeSparkline {
+		points := cfg.SparklinePoints
+		if points <= 0 {
+			points = ssClaudeSparkWidth
+		}
+		points = min(points, maxWidth-ssLineWidth(segments, cfg.Separator)-1)
+		if spark := ssClaudeSparkline(cfg.CacheDir, points); spark != "" {
+			claudeSeg.Text += " " + spark
 		}
 	}
 
-	if cfg.ShowSystem {
-		if seg := ssSystemSegment(cfg.CacheDir); seg != nil {
-			segments = append(segments, seg)
+	// The projected-overage forecast and a hot spend velocity are shown
+	// only when they fit without dropping a segment.
+	if billingSeg != nil {
+		for _, suffix := range []string{
+			ssBillingProjection(cfg.CacheDir, cfg.CacheTTLs["billing"], cfg.Money),
+			ssBillingVelocity(cfg.CacheDir, time.Now()),
+		} {
+			if suffix != "" && ssLineWidth(segments, cfg.Separator)+1+ssVisibleWidth(suffix) <= maxWidth {
+				billingSeg.Text += " " + suffix
+			}
 		}
 	}
 
-	return ssFormatLine(segments, maxWidth)
+	line := ssFormatLine(segments, maxWidth, cfg.Separator)
+	slog.Debug("starship: rendered", "segments", len(segments), "elapsed", time.Since(start))
+	return line
 }
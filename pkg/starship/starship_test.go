@@ -8,11 +8,13 @@ import (
 	"testing"
 	"time"
 
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/anomaly"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
 	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
 )
 
 // ssWriteFixture writes a JSON fixture to the given cache directory under
@@ -175,6 +177,36 @@ func TestRenderNoCachedData(t *testing.T) {
 	}
 }
 
+func TestRenderLoadingOnColdCache(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{Modules: []string{ModuleClaude, ModuleBilling}, CacheDir: dir, Loading: true}
+
+	stripped := ssStripAnsi(Render(cfg))
+	if stripped != "🤖 … │ ☁️ …" {
+		t.Errorf("cold cache render = %q, want loading placeholders", stripped)
+	}
+
+	// Once the collector has written data the normal segment replaces it.
+	ssWriteFixture(t, dir, "claude", ssClaudeFixture(42.5, nil))
+	stripped = ssStripAnsi(Render(cfg))
+	if !strings.HasPrefix(stripped, "🤖 $42.50") || !strings.HasSuffix(stripped, "☁️ …") {
+		t.Errorf("populated render = %q, want Claude data then billing placeholder", stripped)
+	}
+
+	// A stale file is not a cold cache: the module stays hidden.
+	old := time.Now().Add(-10 * time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "claude.json"), old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if stripped = ssStripAnsi(Render(cfg)); stripped != "☁️ …" {
+		t.Errorf("stale render = %q, want only the billing placeholder", stripped)
+	}
+
+	if got := Render(Config{Modules: cfg.Modules, CacheDir: t.TempDir()}); got != "" {
+		t.Errorf("render without Loading = %q, want empty", got)
+	}
+}
+
 func TestRenderOnlyClaudeEnabled(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "claude", ssClaudeFixture(50.0, []claude.ModelUsage{
@@ -206,6 +238,36 @@ func TestRenderOnlyClaudeEnabled(t *testing.T) {
 	}
 }
 
+func TestRenderClaudeSparklineWhenWidthAllows(t *testing.T) {
+	dir := t.TempDir()
+	ssWriteFixture(t, dir, "claude", ssClaudeFixture(50.0, nil))
+
+	h := claude.NewHistory()
+	now := time.Now()
+	for i, cost := range []float64{10, 20, 50} {
+		h.Record(&claude.UsageReport{
+			Timestamp:    now.Add(time.Duration(i) * time.Minute),
+			TotalCostUSD: cost,
+		}, 0)
+	}
+	if err := h.Save(claude.HistoryPath(dir)); err != nil {
+		t.Fatalf("save history: %v", err)
+	}
+
+	wide := ssStripAnsi(Render(Config{ShowClaude: true, ClaudeSparkline: true, CacheDir: dir, MaxWidth: 60}))
+	if !strings.Contains(wide, "\u2581") || !strings.Contains(wide, "\u2588") {
+		t.Errorf("expected sparkline in wide output, got: %q", wide)
+	}
+
+	narrow := ssStripAnsi(Render(Config{ShowClaude: true, ClaudeSparkline: true, CacheDir: dir, MaxWidth: 10}))
+	if strings.Contains(narrow, "\u2581") {
+		t.Errorf("sparkline should be omitted when width is tight, got: %q", narrow)
+	}
+	if !strings.Contains(narrow, "$50.00") {
+		t.Errorf("narrow output should still show cost, got: %q", narrow)
+	}
+}
+
 func TestRenderRespectsMaxWidthTruncation(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "claude", ssClaudeFixture(142.30, []claude.ModelUsage{
@@ -246,7 +308,7 @@ func TestClaudeSegmentFormatting(t *testing.T) {
 		{Model: "claude-3-5-sonnet-20241022", CostUSD: 42.30},
 	}))
 
-	seg := ssClaudeSegment(dir)
+	seg := ssClaudeSegment(dir, 0, components.MoneyFormat{})
 	if seg == nil {
 		t.Fatal("expected non-nil segment")
 	}
@@ -280,7 +342,7 @@ func TestClaudeSegmentColorThresholds(t *testing.T) {
 			ssWriteFixture(t, dir, "claude", ssClaudeFixture(tt.cost, []claude.ModelUsage{
 				{Model: "claude-opus-4-20250514", CostUSD: tt.cost},
 			}))
-			seg := ssClaudeSegment(dir)
+			seg := ssClaudeSegment(dir, 0, components.MoneyFormat{})
 			if seg == nil {
 				t.Fatal("expected non-nil segment")
 			}
@@ -291,11 +353,41 @@ func TestClaudeSegmentColorThresholds(t *testing.T) {
 	}
 }
 
+func TestClaudeSegmentColorPerAccountThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		warn      float64
+		wantColor string
+	}{
+		{"custom_warn_50", 50, ssColorYellow},
+		{"default_warn", 0, ssColorGreen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			ssWriteFixture(t, dir, "claude", claude.UsageReport{
+				TotalCostUSD: 60,
+				Accounts: []claude.AccountUsage{{
+					Name: "work", Connected: true, BudgetUSD: 100, Utilization: 60,
+					WarnThreshold: tt.warn, CurrentMonth: claude.MonthUsage{CostUSD: 60},
+				}},
+			})
+			seg := ssClaudeSegment(dir, 0, components.MoneyFormat{})
+			if seg == nil {
+				t.Fatal("expected non-nil segment")
+			}
+			if seg.Color != tt.wantColor {
+				t.Errorf("want color %q, got %q", tt.wantColor, seg.Color)
+			}
+		})
+	}
+}
+
 func TestBillingSegmentFormatting(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "billing", ssBillingFixture(23.45, 100))
 
-	seg := ssBillingSegment(dir)
+	seg := ssBillingSegment(dir, 0, components.MoneyFormat{})
 	if seg == nil {
 		t.Fatal("expected non-nil segment")
 	}
@@ -311,11 +403,88 @@ func TestBillingSegmentFormatting(t *testing.T) {
 	}
 }
 
+func TestBillingSegmentPaceArrow(t *testing.T) {
+	dir := t.TempDir()
+	report := ssBillingFixture(150, 200)
+	report.BudgetPace = billing.PaceOverPace
+	ssWriteFixture(t, dir, "billing", report)
+
+	seg := ssBillingSegment(dir, 0, components.MoneyFormat{})
+	if seg == nil {
+		t.Fatal("expected non-nil segment")
+	}
+	if seg.Text != "$150.00/mo ↑" {
+		t.Errorf("expected over-pace arrow, got: %s", seg.Text)
+	}
+}
+
+func TestRenderMoneyFormat(t *testing.T) {
+	dir := t.TempDir()
+	report := ssBillingFixture(1234.56, 1000)
+	report.ForecastUSD, report.ProjectedOverBudget = 2480.4, true
+	ssWriteFixture(t, dir, "billing", report)
+
+	grouped := ssStripAnsi(Render(Config{ShowBilling: true, CacheDir: dir,
+		Money: components.MoneyFormat{Separator: components.SeparatorComma}}))
+	if !strings.Contains(grouped, "$1,234.56/mo ⇡$2,480") {
+		t.Errorf("expected grouped amounts, got: %q", grouped)
+	}
+
+	// Whole dollars keep the prompt compact: no cents, and the forecast
+	// still fits a line that only just holds it.
+	compact := ssStripAnsi(Render(Config{ShowBilling: true, CacheDir: dir, MaxWidth: 19,
+		Money: components.MoneyFormat{Whole: true}}))
+	if !strings.Contains(compact, "$1235/mo ⇡$2480") {
+		t.Errorf("expected whole-dollar amounts, got: %q", compact)
+	}
+}
+
+func TestRenderBillingProjectionWhenWidthAllows(t *testing.T) {
+	dir := t.TempDir()
+	report := ssBillingFixture(80, 200)
+	report.ForecastUSD, report.ProjectedOverBudget = 240, true
+	ssWriteFixture(t, dir, "billing", report)
+
+	wide := ssStripAnsi(Render(Config{ShowBilling: true, CacheDir: dir, MaxWidth: 60}))
+	if !strings.Contains(wide, "$80.00/mo ⇡$240") {
+		t.Errorf("expected projected forecast in wide output, got: %q", wide)
+	}
+	narrow := ssStripAnsi(Render(Config{ShowBilling: true, CacheDir: dir, MaxWidth: 14}))
+	if strings.Contains(narrow, "⇡") || !strings.Contains(narrow, "$80.00/mo") {
+		t.Errorf("projection should be dropped when width is tight, got: %q", narrow)
+	}
+}
+
+func TestBillingVelocity(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 4, 5, 12, 0, 0, 0, time.Local)
+	h := &billing.SpendHistory{Days: []anomaly.DailySpend{
+		{Date: "2026-04-01", SpendUSD: 10},
+		{Date: "2026-04-02", SpendUSD: 10},
+		{Date: "2026-04-03", SpendUSD: 10},
+		{Date: "2026-04-04", SpendUSD: 10},
+		{Date: "2026-04-05", SpendUSD: 9},
+	}}
+	if err := h.Save(billing.SpendHistoryPath(dir)); err != nil {
+		t.Fatal(err)
+	}
+	if got := ssBillingVelocity(dir, now); got != "1.8×" {
+		t.Errorf("ssBillingVelocity at noon = %q, want 1.8×", got)
+	}
+	// An average day's pace stays out of the prompt.
+	if got := ssBillingVelocity(dir, now.Add(9*time.Hour)); got != "" {
+		t.Errorf("ssBillingVelocity at 9pm = %q, want none", got)
+	}
+	if got := ssBillingVelocity(t.TempDir(), now); got != "" {
+		t.Errorf("ssBillingVelocity without history = %q, want none", got)
+	}
+}
+
 func TestTailscaleSegmentAllOnline(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "tailscale", ssTailscaleFixture(5, 5))
 
-	seg := ssTailscaleSegment(dir)
+	seg := ssTailscaleSegment(dir, 0)
 	if seg == nil {
 		t.Fatal("expected non-nil segment")
 	}
@@ -331,7 +500,7 @@ func TestTailscaleSegmentSomeOffline(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "tailscale", ssTailscaleFixture(3, 5))
 
-	seg := ssTailscaleSegment(dir)
+	seg := ssTailscaleSegment(dir, 0)
 	if seg == nil {
 		t.Fatal("expected non-nil segment")
 	}
@@ -348,7 +517,7 @@ func TestTailscaleSegmentMostOffline(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "tailscale", ssTailscaleFixture(1, 5))
 
-	seg := ssTailscaleSegment(dir)
+	seg := ssTailscaleSegment(dir, 0)
 	if seg == nil {
 		t.Fatal("expected non-nil segment")
 	}
@@ -362,7 +531,7 @@ func TestK8sSegmentHealthyPods(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "k8s", ssK8sFixture(15, 15, 0))
 
-	seg := ssK8sSegment(dir)
+	seg := ssK8sSegment(dir, 0)
 	if seg == nil {
 		t.Fatal("expected non-nil segment")
 	}
@@ -378,7 +547,7 @@ func TestK8sSegmentFailedPods(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "k8s", ssK8sFixture(15, 10, 3))
 
-	seg := ssK8sSegment(dir)
+	seg := ssK8sSegment(dir, 0)
 	if seg == nil {
 		t.Fatal("expected non-nil segment")
 	}
@@ -394,7 +563,7 @@ func TestK8sSegmentPendingPods(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "k8s", ssK8sFixture(15, 12, 0))
 
-	seg := ssK8sSegment(dir)
+	seg := ssK8sSegment(dir, 0)
 	if seg == nil {
 		t.Fatal("expected non-nil segment")
 	}
@@ -408,7 +577,7 @@ func TestSystemSegmentNormalValues(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "sysmetrics", ssSysmetricsFixture(30, 40))
 
-	seg := ssSystemSegment(dir)
+	seg := ssSystemSegment(dir, 0)
 	if seg == nil {
 		t.Fatal("expected non-nil segment")
 	}
@@ -424,7 +593,7 @@ func TestSystemSegmentHighCPU(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "sysmetrics", ssSysmetricsFixture(92, 40))
 
-	seg := ssSystemSegment(dir)
+	seg := ssSystemSegment(dir, 0)
 	if seg == nil {
 		t.Fatal("expected non-nil segment")
 	}
@@ -437,7 +606,7 @@ func TestSystemSegmentHighRAM(t *testing.T) {
 	dir := t.TempDir()
 	ssWriteFixture(t, dir, "sysmetrics", ssSysmetricsFixture(30, 85))
 
-	seg := ssSystemSegment(dir)
+	seg := ssSystemSegment(dir, 0)
 	if seg == nil {
 		t.Fatal("expected non-nil segment")
 	}
@@ -451,7 +620,7 @@ func TestFormatLineJoinsWithSeparator(t *testing.T) {
 		{Icon: "A", Text: "one", Color: ""},
 		{Icon: "B", Text: "two", Color: ""},
 	}
-	result := ssFormatLine(segments, 200)
+	result := ssFormatLine(segments, 200, "")
 	stripped := ssStripAnsi(result)
 
 	if !strings.Contains(stripped, "A one") {
@@ -475,7 +644,7 @@ func TestFormatLineDropsSegmentsExceedingMaxWidth(t *testing.T) {
 
 	// Set width that allows first two but not third.
 	// "A short" = 7, " │ " = 3, "B medium-text" = 13 => 23
-	result := ssFormatLine(segments, 25)
+	result := ssFormatLine(segments, 25, "")
 	stripped := ssStripAnsi(result)
 
 	if !strings.Contains(stripped, "A short") {
@@ -490,12 +659,12 @@ func TestFormatLineDropsSegmentsExceedingMaxWidth(t *testing.T) {
 }
 
 func TestFormatLineEmptySegments(t *testing.T) {
-	result := ssFormatLine(nil, 60)
+	result := ssFormatLine(nil, 60, "")
 	if result != "" {
 		t.Errorf("expected empty string for nil segments, got: %q", result)
 	}
 
-	result = ssFormatLine([]*Segment{}, 60)
+	result = ssFormatLine([]*Segment{}, 60, "")
 	if result != "" {
 		t.Errorf("expected empty string for empty segments, got: %q", result)
 	}
@@ -556,7 +725,7 @@ func TestCacheReaderStaleFile(t *testing.T) {
 		t.Fatalf("chtimes: %v", err)
 	}
 
-	result, err := ssReadCachedData[claude.UsageReport](dir, "claude")
+	result, err := ssReadCachedData[claude.UsageReport](dir, "claude", 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -567,7 +736,7 @@ func TestCacheReaderStaleFile(t *testing.T) {
 
 func TestCacheReaderMissingFile(t *testing.T) {
 	dir := t.TempDir()
-	result, err := ssReadCachedData[claude.UsageReport](dir, "nonexistent")
+	result, err := ssReadCachedData[claude.UsageReport](dir, "nonexistent", 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -583,7 +752,7 @@ func TestCacheReaderInvalidJSON(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	result, err := ssReadCachedData[claude.UsageReport](dir, "claude")
+	result, err := ssReadCachedData[claude.UsageReport](dir, "claude", 0)
 	if err == nil {
 		t.Error("expected error for invalid JSON, got nil")
 	}
@@ -631,3 +800,285 @@ func TestVisibleWidth(t *testing.T) {
 		t.Errorf("expected 5 for colored text, got %d", w)
 	}
 }
+
+func TestParseModules(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []string
+	}{
+		{"claude", []string{"claude"}},
+		{"billing,infra,claude", []string{"billing", "infra", "claude"}},
+		{" k8s , sys ", []string{"k8s", "system"}},
+		{"tailscale,kubernetes", []string{"infra", "k8s"}},
+		{"all", AllModules},
+		{"system,all", []string{"system", "claude", "billing", "infra", "k8s"}},
+		{"billing,billing", []string{"billing"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseModules(tt.spec)
+		if err != nil {
+			t.Errorf("ParseModules(%q) error: %v", tt.spec, err)
+			continue
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("ParseModules(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseModulesRejectsUnknown(t *testing.T) {
+	_, err := ParseModules("billing,bogus")
+	if err == nil {
+		t.Fatal("expected error for unknown module")
+	}
+	if !strings.Contains(err.Error(), "bogus") || !strings.Contains(err.Error(), "claude, billing, infra, k8s, system") {
+		t.Errorf("error should name the module and list supported ones, got: %v", err)
+	}
+	if _, err := ParseModules(" , "); err == nil {
+		t.Error("expected error for an empty list")
+	}
+}
+
+func TestRenderModulesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	ssWriteFixture(t, dir, "billing", ssBillingFixture(23.45, 100))
+	ssWriteFixture(t, dir, "tailscale", ssTailscaleFixture(3, 5))
+	ssWriteFixture(t, dir, "sysmetrics", ssSysmetricsFixture(45, 62))
+
+	stripped := ssStripAnsi(Render(Config{
+		Modules:   []string{ModuleInfra, ModuleBilling},
+		CacheDir:  dir,
+		MaxWidth:  200,
+		Separator: "·",
+	}))
+
+	infra := strings.Index(stripped, "3/5 peers")
+	bill := strings.Index(stripped, "$23.45/mo")
+	if infra < 0 || bill < 0 || infra > bill {
+		t.Errorf("expected infra before billing, got: %q", stripped)
+	}
+	if strings.Contains(stripped, "CPU:") {
+		t.Errorf("system should not appear when not listed, got: %q", stripped)
+	}
+	if !strings.Contains(stripped, " · ") || strings.Contains(stripped, "│") {
+		t.Errorf("expected custom separator, got: %q", stripped)
+	}
+}
+
+func TestRenderPerKeyCacheTTL(t *testing.T) {
+	dir := t.TempDir()
+	ssWriteFixture(t, dir, "billing", ssBillingFixture(23.45, 100))
+	ssWriteFixture(t, dir, "tailscale", ssTailscaleFixture(3, 5))
+	old := time.Now().Add(-2 * time.Minute)
+	for _, key := range []string{"billing", "tailscale"} {
+		if err := os.Chtimes(filepath.Join(dir, key+".json"), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stripped := ssStripAnsi(Render(Config{
+		Modules:   []string{ModuleBilling, ModuleInfra},
+		CacheDir:  dir,
+		MaxWidth:  200,
+		CacheTTLs: map[string]time.Duration{"billing": time.Hour, "tailscale": 30 * time.Second},
+	}))
+	if !strings.Contains(stripped, "$23.45/mo") {
+		t.Errorf("billing should be fresh under a 1h TTL, got: %q", stripped)
+	}
+	if strings.Contains(stripped, "peers") {
+		t.Errorf("tailscale should be stale under a 30s TTL, got: %q", stripped)
+	}
+}
+
+func TestRenderClaudeWeeklyWidthFallback(t *testing.T) {
+	dir := t.TempDir()
+	ssWriteFixture(t, dir, "claude", claude.UsageReport{
+		TotalCostUSD: 45,
+		Accounts: []claude.AccountUsage{{
+			Name: "personal", Connected: true, BudgetUSD: 100,
+			Utilization: 45, SevenDayUtilization: 82,
+			CurrentMonth: claude.MonthUsage{CostUSD: 45},
+		}},
+	})
+
+	// "🤖 $45.00" measures 8; " 45%/82%w" adds 9 and " 45%" adds 4.
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{60, "🤖 $45.00 45%/82%w"},
+		{17, "🤖 $45.00 45%/82%w"},
+		{16, "🤖 $45.00 45%"},
+		{11, "🤖 $45.00"},
+	}
+	for _, tt := range tests {
+		cfg := Config{ShowClaude: true, ClaudeWeekly: true, CacheDir: dir, MaxWidth: tt.width}
+		if got := ssStripAnsi(Render(cfg)); got != tt.want {
+			t.Errorf("width %d: got %q, want %q", tt.width, got, tt.want)
+		}
+	}
+
+	// Off by default, so existing prompts are unchanged.
+	if got := ssStripAnsi(Render(Config{ShowClaude: true, CacheDir: dir, MaxWidth: 60})); got != "🤖 $45.00" {
+		t.Errorf("without ClaudeWeekly got %q", got)
+	}
+}
+
+func TestRenderClaudeWeeklyNamesTopAccount(t *testing.T) {
+	dir := t.TempDir()
+	ssWriteFixture(t, dir, "claude", claude.UsageReport{
+		TotalCostUSD: 75,
+		Accounts: []claude.AccountUsage{
+			{Name: "work-a", Connected: true, BudgetUSD: 100, Utilization: 30, CurrentMonth: claude.MonthUsage{CostUSD: 30}},
+			{Name: "work-b", Connected: true, BudgetUSD: 100, Utilization: 45, SevenDayUtilization: 82, CurrentMonth: claude.MonthUsage{CostUSD: 45}},
+		},
+	})
+
+	cfg := Config{ShowClaude: true, ClaudeWeekly: true, CacheDir: dir, MaxWidth: 60}
+	if got := ssStripAnsi(Render(cfg)); got != "🤖 $75.00 wor2 45%/82%w" {
+		t.Errorf("got %q, want the top account's short name", got)
+	}
+}
+
+func TestRenderClaudeWeeklyPrimaryAccount(t *testing.T) {
+	dir := t.TempDir()
+	ssWriteFixture(t, dir, "claude", claude.UsageReport{
+		TotalCostUSD: 75,
+		Accounts: []claude.AccountUsage{
+			{Name: "work-a", Connected: true, BudgetUSD: 100, Utilization: 30, SevenDayUtilization: 40, CurrentMonth: claude.MonthUsage{CostUSD: 30}},
+			{Name: "work-b", Connected: true, BudgetUSD: 100, Utilization: 45, SevenDayUtilization: 82, CurrentMonth: claude.MonthUsage{CostUSD: 45}},
+		},
+	})
+
+	for _, tt := range []struct{ primary, want string }{
+		{"work-a", "🤖 $75.00 wor1 30%/40%w"},
+		{"unknown", "🤖 $75.00 wor2 45%/82%w"},
+	} {
+		cfg := Config{ShowClaude: true, ClaudeWeekly: true, CacheDir: dir, MaxWidth: 60, PrimaryAccount: tt.primary}
+		if got := ssStripAnsi(Render(cfg)); got != tt.want {
+			t.Errorf("primary %q: got %q, want %q", tt.primary, got, tt.want)
+		}
+	}
+}
+
+func TestRenderClaudeWeeklyColorsByHigherWindow(t *testing.T) {
+	dir := t.TempDir()
+	ssWriteFixture(t, dir, "claude", claude.UsageReport{
+		TotalCostUSD: 45,
+		Accounts: []claude.AccountUsage{{
+			Name: "personal", Connected: true, BudgetUSD: 100,
+			Utilization: 45, SevenDayUtilization: 95,
+			CurrentMonth: claude.MonthUsage{CostUSD: 45},
+		}},
+	})
+
+	if out := Render(Config{ShowClaude: true, CacheDir: dir, MaxWidth: 60}); !strings.Contains(out, ssColorGreen) {
+		t.Errorf("monthly-only segment should be green, got %q", out)
+	}
+	if out := Render(Config{ShowClaude: true, ClaudeWeekly: true, CacheDir: dir, MaxWidth: 60}); !strings.Contains(out, ssColorRed) {
+		t.Errorf("weekly 95%% should color the segment red, got %q", out)
+	}
+}
+
+func TestRenderMemoServesWithinTTL(t *testing.T) {
+	dir := t.TempDir()
+	ssWriteFixture(t, dir, "billing", ssBillingFixture(80, 200))
+	cfg := Config{ShowBilling: true, CacheDir: dir, MemoTTL: time.Minute}
+
+	first := ssStripAnsi(Render(cfg))
+	if !strings.Contains(first, "$80.00/mo") {
+		t.Fatalf("first render = %q", first)
+	}
+
+	// A second prompt inside the window must not see the updated cache:
+	// it is served from the memo without reading billing.json.
+	ssWriteFixture(t, dir, "billing", ssBillingFixture(95, 200))
+	if got := ssStripAnsi(Render(cfg)); got != first {
+		t.Errorf("render within MemoTTL = %q, want memoized %q", got, first)
+	}
+
+	// Another module list has its own memo.
+	if got := ssStripAnsi(Render(Config{ShowBilling: true, ShowSystem: true, CacheDir: dir, MemoTTL: time.Minute})); !strings.Contains(got, "$95.00/mo") {
+		t.Errorf("render with other modules = %q, want fresh $95.00", got)
+	}
+
+	// So does another primary account, which can change the Claude figures.
+	primary := cfg
+	primary.PrimaryAccount = "work"
+	if got := ssStripAnsi(Render(primary)); !strings.Contains(got, "$95.00/mo") {
+		t.Errorf("render with a primary account = %q, want fresh $95.00", got)
+	}
+
+	// Once the memo is older than MemoTTL the cache is read again.
+	matches, err := filepath.Glob(filepath.Join(dir, "starship-*.cache"))
+	if err != nil || len(matches) != 3 {
+		t.Fatalf("memo files = %v, %v; want 3", matches, err)
+	}
+	old := time.Now().Add(-2 * time.Minute)
+	for _, m := range matches {
+		if err := os.Chtimes(m, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := ssStripAnsi(Render(cfg)); !strings.Contains(got, "$95.00/mo") {
+		t.Errorf("render after MemoTTL = %q, want fresh $95.00", got)
+	}
+}
+
+func TestRenderWithoutMemoReadsCache(t *testing.T) {
+	dir := t.TempDir()
+	ssWriteFixture(t, dir, "billing", ssBillingFixture(80, 200))
+	cfg := Config{ShowBilling: true, CacheDir: dir}
+	Render(cfg)
+	ssWriteFixture(t, dir, "billing", ssBillingFixture(95, 200))
+	if got := ssStripAnsi(Render(cfg)); !strings.Contains(got, "$95.00/mo") {
+		t.Errorf("render = %q, want fresh $95.00", got)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "starship-*.cache")); len(matches) != 0 {
+		t.Errorf("memo written without MemoTTL: %v", matches)
+	}
+}
+
+func TestRenderClaudeSparklinePoints(t *testing.T) {
+	dir := t.TempDir()
+	ssWriteFixture(t, dir, "claude", ssClaudeFixture(50.0, nil))
+
+	h := claude.NewHistory()
+	now := time.Now()
+	for i := range 24 {
+		h.Record(&claude.UsageReport{
+			Timestamp:    now.Add(time.Duration(i) * time.Minute),
+			TotalCostUSD: float64(i + 1),
+		}, 0)
+	}
+	if err := h.Save(claude.HistoryPath(dir)); err != nil {
+		t.Fatalf("save history: %v", err)
+	}
+
+	cells := func(s string) int {
+		n := 0
+		for _, r := range s {
+			if r >= '▁' && r <= '█' {
+				n++
+			}
+		}
+		return n
+	}
+
+	out := ssStripAnsi(Render(Config{ShowClaude: true, ClaudeSparkline: true, SparklinePoints: 6, CacheDir: dir, MaxWidth: 60}))
+	if got := cells(out); got != 6 {
+		t.Errorf("sparkline cells = %d, want 6: %q", got, out)
+	}
+
+	out = ssStripAnsi(Render(Config{ShowClaude: true, ClaudeSparkline: true, CacheDir: dir, MaxWidth: 60}))
+	if got := cells(out); got != ssClaudeSparkWidth {
+		t.Errorf("default sparkline cells = %d, want %d: %q", got, ssClaudeSparkWidth, out)
+	}
+
+	// The line's remaining room caps the configured count.
+	base := ssVisibleWidth(ssStripAnsi(Render(Config{ShowClaude: true, CacheDir: dir, MaxWidth: 60})))
+	out = ssStripAnsi(Render(Config{ShowClaude: true, ClaudeSparkline: true, SparklinePoints: 20, CacheDir: dir, MaxWidth: base + 5}))
+	if got := cells(out); got != 4 {
+		t.Errorf("capped sparkline cells = %d, want 4: %q", got, out)
+	}
+}
@@ -0,0 +1,100 @@
+package status
+
+import (
+	"slices"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
+)
+
+// Subsystems lists the subsystem names the evaluator reports reasons under,
+// which EvaluatorConfig.Disabled accepts.
+var Subsystems = []string{"billing", "claude", "k8s", "sysmetrics", "tailscale"}
+
+// EvaluatorConfig selects which subsystems count toward the overall level
+// and sets the thresholds some of them are graded against, so users can
+// weight subsystems differently. Unset (zero) thresholds fall back to
+// DefaultEvaluatorConfig.
+type EvaluatorConfig struct {
+	// Disabled lists subsystems whose findings are dropped, e.g. "billing".
+	Disabled []string
+
+	// ClaudeWarn and ClaudeCrit replace the utilization thresholds, in
+	// percent, of every Claude account and organization. Zero keeps each
+	// one's own thresholds.
+	ClaudeWarn float64
+	ClaudeCrit float64
+
+	// BillingWarn and BillingCrit grade cloud spend as a percentage of the
+	// budget.
+	BillingWarn float64
+	BillingCrit float64
+
+	// Weights caps the level each subsystem's findings count at, e.g.
+	// {"k8s": LevelWarning} so an offline cluster only warns. Subsystems
+	// without an entry count in full.
+	Weights map[string]Level
+
+	// K8sPodPressure replaces the pod capacity percentage at which a
+	// cluster is under pressure. Zero keeps the report's own threshold.
+	K8sPodPressure float64
+
+	// K8sNotReadyCrit is the number of not-ready nodes at which a cluster
+	// turns critical rather than warning. Zero never escalates.
+	K8sNotReadyCrit int
+
+	// TailscaleStaleAfter replaces how long an online peer may go unseen
+	// before it warns. Zero keeps the report's own threshold.
+	TailscaleStaleAfter time.Duration
+
+	// SysMetrics replaces the CPU, memory, and disk thresholds of the
+	// report; zero fields keep the report's own.
+	SysMetrics sysmetrics.Thresholds
+}
+
+// DefaultEvaluatorConfig returns the built-in ruleset: every subsystem
+// enabled at full weight, Claude, Kubernetes, Tailscale, and system
+// metrics graded by the thresholds in their own reports, and cloud spend
+// warning at 80% and critical at 100% of budget.
+func DefaultEvaluatorConfig() EvaluatorConfig {
+	return EvaluatorConfig{BillingWarn: 80, BillingCrit: 100}
+}
+
+// orDefault returns c with unset billing thresholds at their defaults.
+func (c EvaluatorConfig) orDefault() EvaluatorConfig {
+	d := DefaultEvaluatorConfig()
+	if c.BillingWarn <= 0 {
+		c.BillingWarn = d.BillingWarn
+	}
+	if c.BillingCrit <= 0 {
+		c.BillingCrit = d.BillingCrit
+	}
+	return c
+}
+
+// weigh caps r at its subsystem's weight.
+func (c EvaluatorConfig) weigh(r Reason) Reason {
+	if w, ok := c.Weights[r.Subsystem]; ok && r.Level > w {
+		r.Level = w
+	}
+	return r
+}
+
+// sysmetricsThresholds returns the report's thresholds t with the
+// configured ones in place where they are set.
+func (c EvaluatorConfig) sysmetricsThresholds(t sysmetrics.Thresholds) sysmetrics.Thresholds {
+	for _, p := range []struct{ v, cfg *sysmetrics.Threshold }{{&t.CPU, &c.SysMetrics.CPU}, {&t.Memory, &c.SysMetrics.Memory}, {&t.Disk, &c.SysMetrics.Disk}} {
+		if p.cfg.Warn > 0 {
+			p.v.Warn = p.cfg.Warn
+		}
+		if p.cfg.Crit > 0 {
+			p.v.Crit = p.cfg.Crit
+		}
+	}
+	return t.OrDefault()
+}
+
+// enabled reports whether findings for subsystem count.
+func (c EvaluatorConfig) enabled(subsystem string) bool {
+	return !slices.Contains(c.Disabled, subsystem)
+}
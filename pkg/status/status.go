@@ -0,0 +1,380 @@
+// Package status rolls the latest collector reports up into a single
+// healthy/warning/critical level, together with the per-subsystem reasons
+// that drove it. The daemon feeds it every collector update; consumers such
+// as the webhook notifier only look at the evaluated Result.
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+)
+
+// Level is the overall health classification.
+type Level int
+
+const (
+	// LevelHealthy means no subsystem reported a problem.
+	LevelHealthy Level = iota
+
+	// LevelWarning means at least one subsystem needs attention.
+	LevelWarning
+
+	// LevelCritical means at least one subsystem is down or over its limit.
+	LevelCritical
+)
+
+// String returns the lowercase name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelWarning:
+		return "warning"
+	case LevelCritical:
+		return "critical"
+	default:
+		return "healthy"
+	}
+}
+
+// MarshalText encodes the level as its name so JSON output stays readable.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText decodes a level name produced by MarshalText.
+func (l *Level) UnmarshalText(text []byte) error {
+	v, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = v
+	return nil
+}
+
+// ParseLevel parses "healthy", "warning", or "critical" (case-insensitive).
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "healthy", "ok":
+		return LevelHealthy, nil
+	case "warning", "warn":
+		return LevelWarning, nil
+	case "critical", "crit":
+		return LevelCritical, nil
+	default:
+		return LevelHealthy, fmt.Errorf("unknown status level %q", s)
+	}
+}
+
+// Reason is one subsystem finding that contributed to a Result.
+type Reason struct {
+	Subsystem string `json:"subsystem"`
+	Level     Level  `json:"level"`
+	Message   string `json:"message"`
+}
+
+// Result is the outcome of an evaluation. Reasons are ordered most severe
+// first, then by subsystem.
+type Result struct {
+	Level   Level    `json:"level"`
+	Reasons []Reason `json:"reasons,omitempty"`
+}
+
+// Summary joins the messages of the reasons at the result's own level,
+// e.g. "Claude personal at 96%, civo-prod offline".
+func (r Result) Summary() string {
+	var msgs []string
+	for _, reason := range r.Reasons {
+		if reason.Level == r.Level {
+			msgs = append(msgs, reason.Message)
+		}
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// Evaluator keeps the most recent report from each collector and evaluates
+// them together. It is safe for concurrent use.
+type Evaluator struct {
+	mu     sync.Mutex
+	cfg    EvaluatorConfig
+	latest map[string]interface{}
+}
+
+// NewEvaluator returns an Evaluator with no observations that grades
+// reports by cfg.
+func NewEvaluator(cfg EvaluatorConfig) *Evaluator {
+	return &Evaluator{cfg: cfg.orDefault(), latest: make(map[string]interface{})}
+}
+
+// SetConfig replaces the ruleset, keeping the observed reports.
+func (e *Evaluator) SetConfig(cfg EvaluatorConfig) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cfg = cfg.orDefault()
+}
+
+// Observe records the latest report from a collector. Unknown report types
+// are kept but do not contribute reasons.
+func (e *Evaluator) Observe(source string, data interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.latest[source] = data
+}
+
+// Evaluate classifies every observed report and returns the worst level,
+// with the reasons for any problems.
+func (e *Evaluator) Evaluate() Result {
+	return e.result(false)
+}
+
+// Explain is Evaluate with the healthy findings kept as LevelHealthy
+// reasons after the problems, such as "cloud spend at 62% of budget", so
+// the result accounts for every observed subsystem.
+func (e *Evaluator) Explain() Result {
+	return e.result(true)
+}
+
+// result evaluates the observed reports, capping each finding at its
+// subsystem's weight and then dropping healthy findings unless healthy is
+// set, and the findings of disabled subsystems always.
+func (e *Evaluator) result(healthy bool) Result {
+	e.mu.Lock()
+	var reasons []Reason
+	for _, data := range e.latest {
+		for _, r := range evaluate(e.cfg, data) {
+			if !e.cfg.enabled(r.Subsystem) {
+				continue
+			}
+			r = e.cfg.weigh(r)
+			if healthy || r.Level != LevelHealthy {
+				reasons = append(reasons, r)
+			}
+		}
+	}
+	e.mu.Unlock()
+
+	sort.SliceStable(reasons, func(i, j int) bool {
+		if reasons[i].Level != reasons[j].Level {
+			return reasons[i].Level > reasons[j].Level
+		}
+		if reasons[i].Subsystem != reasons[j].Subsystem {
+			return reasons[i].Subsystem < reasons[j].Subsystem
+		}
+		return reasons[i].Message < reasons[j].Message
+	})
+
+	res := Result{Level: LevelHealthy, Reasons: reasons}
+	if len(reasons) > 0 {
+		res.Level = reasons[0].Level
+	}
+	return res
+}
+
+// evaluate dispatches on the concrete report type. Findings that need no
+// attention are returned at LevelHealthy.
+func evaluate(cfg EvaluatorConfig, data interface{}) []Reason {
+	switch v := data.(type) {
+	case *claude.UsageReport:
+		return evaluateClaude(v, cfg)
+	case *billing.BillingReport:
+		return evaluateBilling(v, cfg)
+	case *k8s.ClusterStatus:
+		return evaluateK8s(v, cfg)
+	case *tailscale.Status:
+		return evaluateTailscale(v, cfg)
+	case *sysmetrics.Metrics:
+		return evaluateSysMetrics(v, cfg)
+	default:
+		return nil
+	}
+}
+
+// evaluateClaude grades each account and organization by its own
+// thresholds unless cfg overrides them.
+func evaluateClaude(r *claude.UsageReport, cfg EvaluatorConfig) []Reason {
+	var reasons []Reason
+	for _, a := range r.Accounts {
+		if !a.Connected {
+			reasons = append(reasons, Reason{"claude", LevelWarning, fmt.Sprintf("Claude %s offline", a.Name)})
+			continue
+		}
+		a.WarnThreshold, a.CritThreshold = claudeThresholds(cfg, a.WarnThreshold, a.CritThreshold)
+		reasons = append(reasons, Reason{"claude", claudeLevel(a.Level()), fmt.Sprintf("Claude %s at %.0f%%", a.Name, a.Utilization)})
+	}
+	for _, o := range r.Orgs {
+		if !o.Connected {
+			reasons = append(reasons, Reason{"claude", LevelWarning, fmt.Sprintf("Claude org %s offline", o.Name)})
+			continue
+		}
+		o.WarnThreshold, o.CritThreshold = claudeThresholds(cfg, o.WarnThreshold, o.CritThreshold)
+		reasons = append(reasons, Reason{"claude", claudeLevel(o.Level()), fmt.Sprintf("Claude org %s at %.0f%% of its limit", o.Name, o.PeakUtilization())})
+	}
+	return reasons
+}
+
+// claudeThresholds returns the configured Claude thresholds in place of
+// warn and crit where they are set.
+func claudeThresholds(cfg EvaluatorConfig, warn, crit float64) (float64, float64) {
+	if cfg.ClaudeWarn > 0 {
+		warn = cfg.ClaudeWarn
+	}
+	if cfg.ClaudeCrit > 0 {
+		crit = cfg.ClaudeCrit
+	}
+	return warn, crit
+}
+
+// claudeLevel maps a Claude threshold level to a status level.
+func claudeLevel(l claude.Level) Level {
+	switch l {
+	case claude.LevelCrit:
+		return LevelCritical
+	case claude.LevelWarn:
+		return LevelWarning
+	default:
+		return LevelHealthy
+	}
+}
+
+// evaluateBilling grades the budget percentage against cfg's billing
+// thresholds.
+func evaluateBilling(r *billing.BillingReport, cfg EvaluatorConfig) []Reason {
+	var reasons []Reason
+	for _, p := range r.Providers {
+		if !p.Connected {
+			reasons = append(reasons, Reason{"billing", LevelWarning, fmt.Sprintf("%s billing offline", p.Name)})
+		}
+	}
+	if a := r.Anomaly; a != nil {
+		reasons = append(reasons, Reason{"billing", LevelWarning, "cloud spend spike " + a.String()})
+	}
+	if r.BudgetUSD > 0 {
+		switch {
+		case r.BudgetPercent >= cfg.BillingCrit:
+			reasons = append(reasons, Reason{"billing", LevelCritical, fmt.Sprintf("cloud spend at %.0f%% of budget", r.BudgetPercent)})
+		case r.BudgetPercent >= cfg.BillingWarn:
+			reasons = append(reasons, Reason{"billing", LevelWarning, fmt.Sprintf("cloud spend at %.0f%% of budget", r.BudgetPercent)})
+		default:
+			reasons = append(reasons, Reason{"billing", LevelHealthy, fmt.Sprintf("cloud spend at %.0f%% of budget", r.BudgetPercent)})
+		}
+	} else {
+		reasons = append(reasons, Reason{"billing", LevelHealthy, fmt.Sprintf("cloud spend $%.2f this month", r.TotalMonthlyUSD)})
+	}
+	if r.ProjectedOverBudget {
+		usd, pct := r.ProjectedOverage()
+		reasons = append(reasons, Reason{"billing", LevelWarning, fmt.Sprintf("cloud spend projected $%.2f (%.0f%%) over budget by month end", usd, pct)})
+	}
+	return reasons
+}
+
+// evaluateK8s grades each cluster: offline is critical, not-ready nodes
+// warn until cfg.K8sNotReadyCrit of them, and failed pods and pod pressure
+// warn.
+func evaluateK8s(s *k8s.ClusterStatus, cfg EvaluatorConfig) []Reason {
+	pressure := s.PodPressureThreshold
+	if cfg.K8sPodPressure > 0 {
+		pressure = cfg.K8sPodPressure
+	}
+	var reasons []Reason
+	for _, c := range s.Clusters {
+		if !c.Connected {
+			reasons = append(reasons, Reason{"k8s", LevelCritical, c.Context + " offline"})
+			continue
+		}
+		notReady := 0
+		for _, n := range c.Nodes {
+			if !n.Ready {
+				notReady++
+			}
+		}
+		if notReady > 0 {
+			level := LevelWarning
+			if cfg.K8sNotReadyCrit > 0 && notReady >= cfg.K8sNotReadyCrit {
+				level = LevelCritical
+			}
+			reasons = append(reasons, Reason{"k8s", level, fmt.Sprintf("%s: %d node(s) not ready", c.Context, notReady)})
+		}
+		if c.FailedPods > 0 {
+			reasons = append(reasons, Reason{"k8s", LevelWarning, fmt.Sprintf("%s: %d failed pod(s)", c.Context, c.FailedPods)})
+		}
+		if c.UnderPodPressure(pressure) {
+			msg := fmt.Sprintf("%s: pods at %.0f%% of capacity", c.Context, c.PodPressure())
+			if node, _, ok := c.BusiestNode(); ok {
+				msg += fmt.Sprintf(" (%s %d/%d)", node.Name, node.PodCount, node.MaxPods)
+			}
+			reasons = append(reasons, Reason{"k8s", LevelWarning, msg})
+		}
+		if notReady == 0 && c.FailedPods == 0 && !c.UnderPodPressure(pressure) {
+			reasons = append(reasons, Reason{"k8s", LevelHealthy, fmt.Sprintf("%s: %d/%d pods running", c.Context, c.RunningPods, c.TotalPods)})
+		}
+	}
+	return reasons
+}
+
+// evaluateTailscale grades this node being offline as critical and peers
+// stale under cfg.TailscaleStaleAfter, or the report's own threshold, as
+// warnings.
+func evaluateTailscale(s *tailscale.Status, cfg EvaluatorConfig) []Reason {
+	// An empty Self means the collector has no node info yet, not that the
+	// node dropped off the tailnet.
+	if s.Self.Hostname != "" && !s.Self.Online {
+		return []Reason{{"tailscale", LevelCritical, "tailscale offline"}}
+	}
+	var reasons []Reason
+	stale := s.StalePeers()
+	if cfg.TailscaleStaleAfter > 0 {
+		stale = nil
+		for _, p := range s.Peers {
+			if p.IsStale(cfg.TailscaleStaleAfter) {
+				stale = append(stale, p)
+			}
+		}
+	}
+	for _, p := range stale {
+		ago := time.Since(p.LastSeen).Round(time.Minute)
+		reasons = append(reasons, Reason{"tailscale", LevelWarning, fmt.Sprintf("%s online but not seen for %s", p.Hostname, ago)})
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, Reason{"tailscale", LevelHealthy, fmt.Sprintf("%d/%d peers online", s.OnlinePeers, s.TotalPeers)})
+	}
+	return reasons
+}
+
+// evaluateSysMetrics grades CPU, memory, and every disk against cfg's
+// thresholds where set and the report's own otherwise (see
+// sysmetrics.Metrics.UtilizationLevel).
+func evaluateSysMetrics(m *sysmetrics.Metrics, cfg EvaluatorConfig) []Reason {
+	t := cfg.sysmetricsThresholds(m.Thresholds)
+	reasons := []Reason{
+		{"sysmetrics", usageLevel(t.CPU, m.CPU.Total), fmt.Sprintf("CPU at %.0f%%", m.CPU.Total)},
+		{"sysmetrics", usageLevel(t.Memory, m.Memory.UsedPercent), fmt.Sprintf("memory at %.0f%%", m.Memory.UsedPercent)},
+	}
+	for _, d := range m.Disks {
+		reasons = append(reasons, Reason{"sysmetrics", usageLevel(t.Disk, d.UsedPercent), fmt.Sprintf("disk %s at %.0f%%", d.Path, d.UsedPercent)})
+	}
+	return reasons
+}
+
+// usageLevel grades a resource usage percentage against t.
+func usageLevel(t sysmetrics.Threshold, pct float64) Level {
+	return sysmetricsLevel(t.Level(pct))
+}
+
+// sysmetricsLevel maps a sysmetrics utilization level to a status level.
+func sysmetricsLevel(l sysmetrics.Level) Level {
+	switch l {
+	case sysmetrics.LevelCrit:
+		return LevelCritical
+	case sysmetrics.LevelWarn:
+		return LevelWarning
+	default:
+		return LevelHealthy
+	}
+}
@@ -0,0 +1,423 @@
+package status
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/anomaly"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+)
+
+func TestEvaluate_EmptyIsHealthy(t *testing.T) {
+	res := NewEvaluator(DefaultEvaluatorConfig()).Evaluate()
+	if res.Level != LevelHealthy || len(res.Reasons) != 0 {
+		t.Errorf("empty evaluator = %+v, want healthy with no reasons", res)
+	}
+}
+
+func TestEvaluate_WorstLevelWins(t *testing.T) {
+	e := NewEvaluator(DefaultEvaluatorConfig())
+	e.Observe("claude", &claude.UsageReport{Accounts: []claude.AccountUsage{
+		{Name: "personal", Connected: true, BudgetUSD: 100, Utilization: 96},
+	}})
+	e.Observe("k8s", &k8s.ClusterStatus{Clusters: []k8s.ClusterInfo{
+		{Context: "civo-prod", Connected: false},
+		{Context: "home", Connected: true, Nodes: []k8s.NodeInfo{{Name: "n1", Ready: false}}},
+	}})
+	e.Observe("sysmetrics", &sysmetrics.Metrics{Memory: sysmetrics.MemoryMetrics{UsedPercent: 91}})
+
+	res := e.Evaluate()
+	if res.Level != LevelCritical {
+		t.Fatalf("Level = %v, want critical", res.Level)
+	}
+	if got, want := res.Summary(), "Claude personal at 96%, civo-prod offline"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	if len(res.Reasons) != 4 {
+		t.Errorf("expected 4 reasons, got %+v", res.Reasons)
+	}
+	if res.Reasons[len(res.Reasons)-1].Level != LevelWarning {
+		t.Error("reasons should be ordered most severe first")
+	}
+}
+
+func TestExplain_ListsEveryFinding(t *testing.T) {
+	e := NewEvaluator(DefaultEvaluatorConfig())
+	e.Observe("claude", &claude.UsageReport{Accounts: []claude.AccountUsage{
+		{Name: "personal", Connected: true, BudgetUSD: 100, Utilization: 84},
+		{Name: "work", Connected: true, BudgetUSD: 300, Utilization: 20},
+	}})
+	e.Observe("billing", &billing.BillingReport{TotalMonthlyUSD: 62, BudgetUSD: 100, BudgetPercent: 62})
+
+	want := []Reason{
+		{"claude", LevelWarning, "Claude personal at 84%"},
+		{"billing", LevelHealthy, "cloud spend at 62% of budget"},
+		{"claude", LevelHealthy, "Claude work at 20%"},
+	}
+	res := e.Explain()
+	if res.Level != LevelWarning {
+		t.Errorf("Level = %v, want warning", res.Level)
+	}
+	if len(res.Reasons) != len(want) {
+		t.Fatalf("Explain reasons = %+v, want %+v", res.Reasons, want)
+	}
+	for i, r := range res.Reasons {
+		if r != want[i] {
+			t.Errorf("reason %d = %+v, want %+v", i, r, want[i])
+		}
+	}
+
+	// Evaluate keeps only the problems, so summaries are unchanged.
+	if got := e.Evaluate().Reasons; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Evaluate reasons = %+v, want only %+v", got, want[0])
+	}
+}
+
+func TestEvaluate_ClaudeOrgUtilization(t *testing.T) {
+	e := NewEvaluator(DefaultEvaluatorConfig())
+	e.Observe("claude", &claude.UsageReport{
+		Accounts: []claude.AccountUsage{{Name: "personal", Connected: true}},
+		Orgs: []claude.OrgUsage{
+			{Name: "team", Connected: true, Seats: 8, FiveHour: &claude.UsageWindow{Utilization: 93}},
+			{Name: "lab", Error: "unauthorized"},
+		},
+	})
+	res := e.Evaluate()
+	if res.Level != LevelCritical {
+		t.Fatalf("Level = %v, want critical from the org five-hour window", res.Level)
+	}
+	if got, want := res.Summary(), "Claude org team at 93% of its limit"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	if len(res.Reasons) != 2 || res.Reasons[1].Message != "Claude org lab offline" {
+		t.Errorf("expected the offline org as a second reason, got %+v", res.Reasons)
+	}
+}
+
+func TestEvaluate_LatestObservationReplaces(t *testing.T) {
+	e := NewEvaluator(DefaultEvaluatorConfig())
+	e.Observe("billing", &billing.BillingReport{BudgetUSD: 100, BudgetPercent: 120})
+	if e.Evaluate().Level != LevelCritical {
+		t.Fatal("over-budget billing should be critical")
+	}
+	e.Observe("billing", &billing.BillingReport{BudgetUSD: 100, BudgetPercent: 40})
+	if lvl := e.Evaluate().Level; lvl != LevelHealthy {
+		t.Errorf("Level after recovery = %v, want healthy", lvl)
+	}
+}
+
+func TestEvaluate_ProjectedOverBudget(t *testing.T) {
+	e := NewEvaluator(DefaultEvaluatorConfig())
+	e.Observe("billing", &billing.BillingReport{
+		TotalMonthlyUSD: 60, BudgetUSD: 100, BudgetPercent: 60, ForecastUSD: 124, ProjectedOverBudget: true,
+	})
+	res := e.Evaluate()
+	if res.Level != LevelWarning {
+		t.Fatalf("Level = %v, want warning", res.Level)
+	}
+	if got, want := res.Summary(), "cloud spend projected $24.00 (24%) over budget by month end"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestEvaluate_SpendAnomaly(t *testing.T) {
+	e := NewEvaluator(DefaultEvaluatorConfig())
+	e.Observe("billing", &billing.BillingReport{Anomaly: &anomaly.Anomaly{
+		Date: "2026-03-14", SpendUSD: 52.1, MeanUSD: 6.2,
+	}})
+	res := e.Evaluate()
+	if res.Level != LevelWarning {
+		t.Fatalf("Level = %v, want warning", res.Level)
+	}
+	if got, want := res.Summary(), "cloud spend spike Mar 14 $52.10 (8.4× avg)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestEvaluate_PodPressure(t *testing.T) {
+	e := NewEvaluator(DefaultEvaluatorConfig())
+	e.Observe("k8s", &k8s.ClusterStatus{Clusters: []k8s.ClusterInfo{{
+		Context:     "home",
+		Connected:   true,
+		TotalPods:   95,
+		RunningPods: 95,
+		Nodes:       []k8s.NodeInfo{{Name: "n1", Ready: true, MaxPods: 100, PodCount: 95}},
+	}}})
+
+	res := e.Evaluate()
+	if res.Level < LevelWarning {
+		t.Fatalf("Level = %v, want at least warning", res.Level)
+	}
+	if got, want := res.Summary(), "home: pods at 95% of capacity (n1 95/100)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	e.Observe("k8s", &k8s.ClusterStatus{PodPressureThreshold: 99, Clusters: []k8s.ClusterInfo{{
+		Context: "home", Connected: true, RunningPods: 95,
+		Nodes: []k8s.NodeInfo{{Name: "n1", Ready: true, MaxPods: 100, PodCount: 95}},
+	}}})
+	if lvl := e.Evaluate().Level; lvl != LevelHealthy {
+		t.Errorf("Level with 99%% threshold = %v, want healthy", lvl)
+	}
+}
+
+func TestEvaluate_TailscaleSelfOffline(t *testing.T) {
+	e := NewEvaluator(DefaultEvaluatorConfig())
+	e.Observe("tailscale", &tailscale.Status{})
+	if lvl := e.Evaluate().Level; lvl != LevelHealthy {
+		t.Errorf("empty self should not be critical, got %v", lvl)
+	}
+	e.Observe("tailscale", &tailscale.Status{Self: tailscale.PeerInfo{Hostname: "box"}})
+	if lvl := e.Evaluate().Level; lvl != LevelCritical {
+		t.Errorf("offline self = %v, want critical", lvl)
+	}
+}
+
+func TestEvaluate_TailscaleStalePeer(t *testing.T) {
+	now := time.Now()
+	e := NewEvaluator(DefaultEvaluatorConfig())
+	e.Observe("tailscale", &tailscale.Status{
+		Self: tailscale.PeerInfo{Hostname: "box", Online: true},
+		Peers: []tailscale.PeerInfo{
+			{Hostname: "fresh", Online: true, LastSeen: now.Add(-time.Minute)},
+			{Hostname: "wedged", Online: true, LastSeen: now.Add(-30 * time.Minute)},
+		},
+	})
+	res := e.Evaluate()
+	if res.Level != LevelWarning {
+		t.Fatalf("Level = %v, want warning", res.Level)
+	}
+	if len(res.Reasons) != 1 || !strings.Contains(res.Reasons[0].Message, "wedged online but not seen for 30m") {
+		t.Errorf("Reasons = %+v, want one for wedged", res.Reasons)
+	}
+}
+
+func TestEvaluate_SysMetricsThresholds(t *testing.T) {
+	e := NewEvaluator(DefaultEvaluatorConfig())
+	e.Observe("sysmetrics", &sysmetrics.Metrics{
+		CPU:   sysmetrics.CPUMetrics{Total: 88},
+		Disks: []sysmetrics.DiskMetrics{{Path: "/data", UsedPercent: 96}},
+	})
+	res := e.Evaluate()
+	if res.Level != LevelCritical || res.Summary() != "disk /data at 96%" {
+		t.Errorf("CPU 88, disk 96: %+v, want critical for the disk", res)
+	}
+	if len(res.Reasons) != 2 || res.Reasons[1] != (Reason{"sysmetrics", LevelWarning, "CPU at 88%"}) {
+		t.Errorf("reasons = %+v, want the CPU warning after the disk", res.Reasons)
+	}
+
+	// The report's own thresholds apply.
+	e.Observe("sysmetrics", &sysmetrics.Metrics{
+		CPU:        sysmetrics.CPUMetrics{Total: 82},
+		Thresholds: sysmetrics.Thresholds{CPU: sysmetrics.Threshold{Warn: 80, Crit: 95}},
+	})
+	if res := e.Evaluate(); res.Level != LevelWarning || res.Summary() != "CPU at 82%" {
+		t.Errorf("CPU 82 with warn 80: %+v, want a CPU warning", res)
+	}
+}
+
+func TestEvaluate_DisabledSubsystem(t *testing.T) {
+	over := &billing.BillingReport{TotalMonthlyUSD: 150, BudgetUSD: 100, BudgetPercent: 150}
+	e := NewEvaluator(EvaluatorConfig{Disabled: []string{"billing"}})
+	e.Observe("billing", over)
+	if res := e.Evaluate(); res.Level != LevelHealthy || len(res.Reasons) != 0 {
+		t.Errorf("billing disabled: %+v, want healthy with no reasons", res)
+	}
+	if res := e.Explain(); len(res.Reasons) != 0 {
+		t.Errorf("Explain with billing disabled = %+v, want no billing findings", res)
+	}
+
+	e.SetConfig(DefaultEvaluatorConfig())
+	if lvl := e.Evaluate().Level; lvl != LevelCritical {
+		t.Errorf("billing re-enabled: level = %v, want critical", lvl)
+	}
+}
+
+func TestEvaluate_CustomThresholds(t *testing.T) {
+	report := &claude.UsageReport{Accounts: []claude.AccountUsage{
+		{Name: "work", Connected: true, BudgetUSD: 100, Utilization: 60},
+	}}
+	e := NewEvaluator(DefaultEvaluatorConfig())
+	e.Observe("claude", report)
+	if lvl := e.Evaluate().Level; lvl != LevelHealthy {
+		t.Fatalf("60%% with default thresholds: level = %v, want healthy", lvl)
+	}
+
+	e.SetConfig(EvaluatorConfig{ClaudeWarn: 40, ClaudeCrit: 55})
+	if res := e.Evaluate(); res.Level != LevelCritical || res.Summary() != "Claude work at 60%" {
+		t.Errorf("60%% with claude_crit 55: %+v, want critical", res)
+	}
+
+	// Billing thresholds move the same way.
+	e = NewEvaluator(EvaluatorConfig{BillingWarn: 50, BillingCrit: 90})
+	e.Observe("billing", &billing.BillingReport{BudgetUSD: 100, BudgetPercent: 60})
+	if lvl := e.Evaluate().Level; lvl != LevelWarning {
+		t.Errorf("60%% of budget with billing_warn 50: level = %v, want warning", lvl)
+	}
+}
+
+func TestEvaluate_SourceRules(t *testing.T) {
+	now := time.Now()
+	clusters := &k8s.ClusterStatus{Clusters: []k8s.ClusterInfo{
+		{Context: "civo-prod", Connected: false},
+		{Context: "home", Connected: true, RunningPods: 85, Nodes: []k8s.NodeInfo{
+			{Name: "n1", Ready: false, MaxPods: 50, PodCount: 40},
+			{Name: "n2", Ready: true, MaxPods: 100, PodCount: 85},
+		}},
+	}}
+	tailnet := &tailscale.Status{
+		Self: tailscale.PeerInfo{Hostname: "box", Online: true},
+		Peers: []tailscale.PeerInfo{
+			{Hostname: "nas", Online: true, LastSeen: now.Add(-5 * time.Minute)},
+		},
+	}
+	metrics := &sysmetrics.Metrics{
+		CPU:    sysmetrics.CPUMetrics{Total: 70},
+		Memory: sysmetrics.MemoryMetrics{UsedPercent: 50},
+		Disks:  []sysmetrics.DiskMetrics{{Path: "/", UsedPercent: 90}},
+	}
+
+	tests := []struct {
+		name   string
+		cfg    EvaluatorConfig
+		source string
+		data   interface{}
+		want   []Reason
+	}{
+		{
+			name:   "k8s defaults",
+			cfg:    DefaultEvaluatorConfig(),
+			source: "k8s",
+			data:   clusters,
+			want: []Reason{
+				{"k8s", LevelCritical, "civo-prod offline"},
+				{"k8s", LevelWarning, "home: 1 node(s) not ready"},
+			},
+		},
+		{
+			name:   "k8s not-ready nodes escalate",
+			cfg:    EvaluatorConfig{K8sNotReadyCrit: 1},
+			source: "k8s",
+			data:   clusters,
+			want: []Reason{
+				{"k8s", LevelCritical, "civo-prod offline"},
+				{"k8s", LevelCritical, "home: 1 node(s) not ready"},
+			},
+		},
+		{
+			name:   "k8s pod pressure threshold",
+			cfg:    EvaluatorConfig{K8sPodPressure: 80},
+			source: "k8s",
+			data:   clusters,
+			want: []Reason{
+				{"k8s", LevelCritical, "civo-prod offline"},
+				{"k8s", LevelWarning, "home: 1 node(s) not ready"},
+				{"k8s", LevelWarning, "home: pods at 85% of capacity (n2 85/100)"},
+			},
+		},
+		{
+			name:   "k8s weighted to warning",
+			cfg:    EvaluatorConfig{Weights: map[string]Level{"k8s": LevelWarning}},
+			source: "k8s",
+			data:   clusters,
+			want: []Reason{
+				{"k8s", LevelWarning, "civo-prod offline"},
+				{"k8s", LevelWarning, "home: 1 node(s) not ready"},
+			},
+		},
+		{
+			name:   "tailscale defaults",
+			cfg:    DefaultEvaluatorConfig(),
+			source: "tailscale",
+			data:   tailnet,
+			want:   []Reason{{"tailscale", LevelHealthy, "0/0 peers online"}},
+		},
+		{
+			name:   "tailscale stale after",
+			cfg:    EvaluatorConfig{TailscaleStaleAfter: time.Minute},
+			source: "tailscale",
+			data:   tailnet,
+			want:   []Reason{{"tailscale", LevelWarning, "nas online but not seen for 5m0s"}},
+		},
+		{
+			name:   "tailscale weighted to healthy",
+			cfg:    EvaluatorConfig{TailscaleStaleAfter: time.Minute, Weights: map[string]Level{"tailscale": LevelHealthy}},
+			source: "tailscale",
+			data:   tailnet,
+			want:   []Reason{{"tailscale", LevelHealthy, "nas online but not seen for 5m0s"}},
+		},
+		{
+			name:   "sysmetrics defaults",
+			cfg:    DefaultEvaluatorConfig(),
+			source: "sysmetrics",
+			data:   metrics,
+			want: []Reason{
+				{"sysmetrics", LevelWarning, "disk / at 90%"},
+				{"sysmetrics", LevelHealthy, "CPU at 70%"},
+				{"sysmetrics", LevelHealthy, "memory at 50%"},
+			},
+		},
+		{
+			name:   "sysmetrics thresholds",
+			cfg:    EvaluatorConfig{SysMetrics: sysmetrics.Thresholds{CPU: sysmetrics.Threshold{Warn: 60}, Disk: sysmetrics.Threshold{Crit: 90}}},
+			source: "sysmetrics",
+			data:   metrics,
+			want: []Reason{
+				{"sysmetrics", LevelCritical, "disk / at 90%"},
+				{"sysmetrics", LevelWarning, "CPU at 70%"},
+				{"sysmetrics", LevelHealthy, "memory at 50%"},
+			},
+		},
+		{
+			name:   "sysmetrics weight leaves other subsystems alone",
+			cfg:    EvaluatorConfig{Weights: map[string]Level{"k8s": LevelHealthy}},
+			source: "sysmetrics",
+			data:   metrics,
+			want: []Reason{
+				{"sysmetrics", LevelWarning, "disk / at 90%"},
+				{"sysmetrics", LevelHealthy, "CPU at 70%"},
+				{"sysmetrics", LevelHealthy, "memory at 50%"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEvaluator(tt.cfg)
+			e.Observe(tt.source, tt.data)
+			got := e.Explain().Reasons
+			if len(got) != len(tt.want) {
+				t.Fatalf("reasons = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("reason %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLevel_TextRoundTrip(t *testing.T) {
+	for _, l := range []Level{LevelHealthy, LevelWarning, LevelCritical} {
+		data, err := json.Marshal(l)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Level
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("unmarshal %s: %v", data, err)
+		}
+		if got != l {
+			t.Errorf("round trip %v -> %s -> %v", l, data, got)
+		}
+	}
+	if _, err := ParseLevel("bogus"); err == nil {
+		t.Error("ParseLevel(bogus) should fail")
+	}
+}
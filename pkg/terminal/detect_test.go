@@ -529,6 +529,70 @@ func TestGetSizeFromFd_InvalidFd(t *testing.T) {
 	}
 }
 
+// withTTYSize makes ResolveSize see a controlling terminal of size s, or
+// none when s is zero, for the rest of the test.
+func withTTYSize(t *testing.T, s Size) {
+	t.Helper()
+	orig := ttySize
+	ttySize = func() Size { return s }
+	t.Cleanup(func() { ttySize = orig })
+}
+
+func TestResolveSize_NoTTY(t *testing.T) {
+	clearTermEnv(t)
+	withTTYSize(t, Size{})
+
+	r := ResolveSize(0, 0)
+	if r.Cols != 80 || r.Rows != 24 {
+		t.Errorf("ResolveSize without a TTY = %dx%d, want 80x24", r.Cols, r.Rows)
+	}
+	if r.ColsFrom != SizeFromDefault || r.RowsFrom != SizeFromDefault {
+		t.Errorf("sources = %s, %s, want default", r.ColsFrom, r.RowsFrom)
+	}
+}
+
+func TestResolveSize_Precedence(t *testing.T) {
+	tty := Size{Cols: 200, Rows: 60, PixelW: 2000, PixelH: 1200, CellW: 10, CellH: 20}
+	tests := []struct {
+		name               string
+		columns, lines     string
+		cols, rows         int
+		wantCols, wantRows int
+		colsFrom, rowsFrom SizeSource
+		wantPixels         bool
+	}{
+		{"tty", "", "", 0, 0, 200, 60, SizeFromTTY, SizeFromTTY, true},
+		{"env beats tty", "40", "10", 0, 0, 40, 10, SizeFromEnv, SizeFromEnv, false},
+		{"env per dimension", "40", "", 0, 0, 40, 60, SizeFromEnv, SizeFromTTY, false},
+		{"flag beats env", "40", "10", 100, 30, 100, 30, SizeFromFlag, SizeFromFlag, false},
+		{"flag per dimension", "40", "10", 100, 0, 100, 10, SizeFromFlag, SizeFromEnv, false},
+		{"invalid env ignored", "wide", "-1", 0, 0, 200, 60, SizeFromTTY, SizeFromTTY, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTermEnv(t)
+			withTTYSize(t, tty)
+			if tt.columns != "" {
+				t.Setenv("COLUMNS", tt.columns)
+			}
+			if tt.lines != "" {
+				t.Setenv("LINES", tt.lines)
+			}
+
+			r := ResolveSize(tt.cols, tt.rows)
+			if r.Cols != tt.wantCols || r.Rows != tt.wantRows {
+				t.Errorf("size = %dx%d, want %dx%d", r.Cols, r.Rows, tt.wantCols, tt.wantRows)
+			}
+			if r.ColsFrom != tt.colsFrom || r.RowsFrom != tt.rowsFrom {
+				t.Errorf("sources = %s, %s, want %s, %s", r.ColsFrom, r.RowsFrom, tt.colsFrom, tt.rowsFrom)
+			}
+			if got := r.CellW > 0; got != tt.wantPixels {
+				t.Errorf("pixel dimensions present = %v, want %v", got, tt.wantPixels)
+			}
+		})
+	}
+}
+
 func TestEnvInt(t *testing.T) {
 	t.Setenv("TEST_INT_VAR", "42")
 	if got := envInt("TEST_INT_VAR", 10); got != 42 {
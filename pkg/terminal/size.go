@@ -33,6 +33,104 @@ func GetSize() Size {
 	return getSizeFromEnv()
 }
 
+// DefaultCols and DefaultRows are the size ResolveSize assumes when none
+// can be detected, as when run from CI or cron without a terminal: small
+// enough to read in any pipe or log.
+const (
+	DefaultCols = 80
+	DefaultRows = 24
+)
+
+// SizeSource names where ResolveSize took a dimension from.
+type SizeSource string
+
+// Size sources, in the order ResolveSize tries them.
+const (
+	// SizeFromFlag is an explicit override such as -term-width.
+	SizeFromFlag SizeSource = "flag"
+
+	// SizeFromEnv is the COLUMNS or LINES environment variable.
+	SizeFromEnv SizeSource = "env"
+
+	// SizeFromTTY is a TIOCGWINSZ ioctl on the controlling terminal.
+	SizeFromTTY SizeSource = "tty"
+
+	// SizeFromDefault is DefaultCols or DefaultRows.
+	SizeFromDefault SizeSource = "default"
+)
+
+// SizeSources lists the sources ResolveSize tries, in order.
+var SizeSources = []SizeSource{SizeFromFlag, SizeFromEnv, SizeFromTTY, SizeFromDefault}
+
+// Resolved is a terminal size with the source of each dimension.
+type Resolved struct {
+	Size
+	ColsFrom SizeSource
+	RowsFrom SizeSource
+}
+
+// ttySize queries the controlling terminal. Tests replace it to simulate
+// running without one.
+var ttySize = controllingTTYSize
+
+// ResolveSize returns the size to render at. Columns and rows are each
+// taken from the first of SizeSources that has one: the cols or rows
+// override when positive, COLUMNS or LINES, the controlling terminal, and
+// finally DefaultCols by DefaultRows. Unlike GetSize, a size set in the
+// environment wins over the terminal's, so a caller rendering into a pipe
+// can say how wide it is.
+func ResolveSize(cols, rows int) Resolved {
+	var tty *Size
+	probe := func() Size {
+		if tty == nil {
+			s := ttySize()
+			tty = &s
+		}
+		return *tty
+	}
+
+	var r Resolved
+	r.Cols, r.ColsFrom = resolveDim(cols, "COLUMNS", func() int { return probe().Cols }, DefaultCols)
+	r.Rows, r.RowsFrom = resolveDim(rows, "LINES", func() int { return probe().Rows }, DefaultRows)
+	if r.ColsFrom == SizeFromTTY && r.RowsFrom == SizeFromTTY {
+		// The pixel dimensions describe the terminal's own cell grid.
+		r.Size = *tty
+	}
+	return r
+}
+
+// resolveDim returns one dimension and its source: override when
+// positive, else the named environment variable, else tty(), else def.
+func resolveDim(override int, env string, tty func() int, def int) (int, SizeSource) {
+	if override > 0 {
+		return override, SizeFromFlag
+	}
+	if n := envInt(env, 0); n > 0 {
+		return n, SizeFromEnv
+	}
+	if n := tty(); n > 0 {
+		return n, SizeFromTTY
+	}
+	return def, SizeFromDefault
+}
+
+// controllingTTYSize returns the size of the terminal on stdout or stderr,
+// or failing those the process's controlling terminal, /dev/tty. It
+// returns a zero Size when there is none.
+func controllingTTYSize() Size {
+	for _, fd := range []uintptr{os.Stdout.Fd(), os.Stderr.Fd()} {
+		if s := getSizeFromIoctl(fd); s.Cols > 0 && s.Rows > 0 {
+			return s
+		}
+	}
+	f, err := os.Open("/dev/tty")
+	if err != nil {
+		return Size{}
+	}
+	defer f.Close()
+	return getSizeFromIoctl(f.Fd())
+}
+
 // GetSizeFromFd returns terminal size from a specific file descriptor.
 // Falls back to environment variables and then 80x24 defaults if the
 // ioctl fails.
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/daemon"
+)
+
+// pfDefaultTimeout bounds a whole -profile run so a hung API cannot block
+// it indefinitely.
+const pfDefaultTimeout = 30 * time.Second
+
+// pfResult is the timing of one collector's Collect call.
+type pfResult struct {
+	Collector  string        `json:"collector"`
+	Duration   time.Duration `json:"-"`
+	DurationMS float64       `json:"duration_ms"`
+	OK         bool          `json:"ok"`
+	Error      string        `json:"error,omitempty"`
+	Bytes      int           `json:"bytes"`
+}
+
+// pfReport is the -profile -json document.
+type pfReport struct {
+	Timeout    string     `json:"timeout"`
+	Collectors []pfResult `json:"collectors"`
+}
+
+// profileRegistry builds the collector registry for -profile. The waifu
+// collector downloads into its cache directory, so it is pointed at a
+// throwaway directory instead; the returned cleanup removes it.
+func profileRegistry(cfg *config.Config) (*collectors.Registry, func(), error) {
+	pcfg := *cfg
+	tmp, err := os.MkdirTemp("", "prompt-pulse-profile-")
+	if err != nil {
+		return nil, nil, err
+	}
+	pcfg.Collectors.Waifu.CacheDir = tmp
+	return daemon.BuildRegistry(&pcfg), func() { os.RemoveAll(tmp) }, nil
+}
+
+// profileCollectors runs every registered collector once, concurrently, and
+// times each Collect. Results are never written to the cache, so this is
+// safe to run next to the daemon. The slowest collector comes first.
+func profileCollectors(ctx context.Context, reg *collectors.Registry) []pfResult {
+	names := reg.List()
+	results := make([]pfResult, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		c, ok := reg.Get(name)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, c collectors.Collector) {
+			defer wg.Done()
+			results[i] = pfRun(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Duration != results[j].Duration {
+			return results[i].Duration > results[j].Duration
+		}
+		return results[i].Collector < results[j].Collector
+	})
+	return results
+}
+
+// pfRun times a single Collect call. The result size is the length of the
+// JSON the daemon would cache.
+func pfRun(ctx context.Context, c collectors.Collector) pfResult {
+	r := pfResult{Collector: c.Name()}
+	start := time.Now()
+	data, err := c.Collect(ctx)
+	r.Duration = time.Since(start)
+	r.DurationMS = float64(r.Duration.Microseconds()) / 1000
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.OK = true
+	if b, err := json.Marshal(data); err == nil {
+		r.Bytes = len(b)
+	}
+	return r
+}
+
+// writeProfile prints results as an aligned table, or as JSON for CI
+// regression tracking.
+func writeProfile(w io.Writer, results []pfResult, timeout time.Duration, asJSON bool) error {
+	if asJSON {
+		if results == nil {
+			results = []pfResult{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pfReport{Timeout: timeout.String(), Collectors: results})
+	}
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(w, "no collectors enabled")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COLLECTOR\tDURATION\tRESULT\tBYTES")
+	for _, r := range results {
+		status := "ok"
+		if !r.OK {
+			status = "FAIL: " + r.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", r.Collector, r.Duration.Round(100*time.Microsecond), status, r.Bytes)
+	}
+	return tw.Flush()
+}
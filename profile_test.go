@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+func pfSleepCollector(name string, d time.Duration, data interface{}, err error) collectors.Collector {
+	return collectors.NewMockCollector(name, time.Minute, collectors.WithCollectFunc(
+		func(ctx context.Context) (interface{}, error) {
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			return data, err
+		}))
+}
+
+func TestProfileCollectors_SortsSlowestFirst(t *testing.T) {
+	reg := collectors.NewRegistry()
+	for _, c := range []collectors.Collector{
+		pfSleepCollector("fast", 0, map[string]int{"a": 1}, nil),
+		pfSleepCollector("slow", 40*time.Millisecond, nil, nil),
+		pfSleepCollector("broken", 20*time.Millisecond, nil, errors.New("boom")),
+	} {
+		if err := reg.Register(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := profileCollectors(context.Background(), reg)
+	var order []string
+	for _, r := range results {
+		order = append(order, r.Collector)
+	}
+	if got := strings.Join(order, ","); got != "slow,broken,fast" {
+		t.Fatalf("order = %s, want slow,broken,fast", got)
+	}
+	if results[1].OK || results[1].Error != "boom" {
+		t.Errorf("broken result = %+v", results[1])
+	}
+	if !results[2].OK || results[2].Bytes != len(`{"a":1}`) {
+		t.Errorf("fast result = %+v", results[2])
+	}
+}
+
+func TestProfileCollectors_GlobalTimeout(t *testing.T) {
+	reg := collectors.NewRegistry()
+	if err := reg.Register(pfSleepCollector("hung", time.Hour, nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	results := profileCollectors(ctx, reg)
+	if len(results) != 1 || results[0].OK {
+		t.Fatalf("expected a failed result, got %+v", results)
+	}
+	if results[0].Duration > time.Second {
+		t.Errorf("timeout not honored: %v", results[0].Duration)
+	}
+}
+
+func TestProfileRegistry_DoesNotTouchCache(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.General.CacheDir = t.TempDir()
+	cfg.Collectors.Waifu.Enabled = true
+
+	reg, cleanup, err := profileRegistry(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup()
+	if len(reg.List()) == 0 {
+		t.Error("expected enabled collectors to be registered")
+	}
+	if cfg.Collectors.Waifu.CacheDir != "" {
+		t.Errorf("caller config was modified: waifu cache dir %q", cfg.Collectors.Waifu.CacheDir)
+	}
+	entries, err := os.ReadDir(cfg.General.CacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("cache dir should stay empty, has %d entries", len(entries))
+	}
+}
+
+func TestWriteProfile(t *testing.T) {
+	results := []pfResult{
+		{Collector: "claude", Duration: 1200 * time.Millisecond, DurationMS: 1200, OK: true, Bytes: 512},
+		{Collector: "k8s", Duration: 30 * time.Millisecond, DurationMS: 30, Error: "no kubeconfig"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeProfile(&buf, results, time.Minute, false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "COLLECTOR") {
+		t.Fatalf("table output:\n%s", buf.String())
+	}
+	if !strings.Contains(lines[1], "1.2s") || !strings.Contains(lines[1], "512") {
+		t.Errorf("claude row = %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "FAIL: no kubeconfig") {
+		t.Errorf("k8s row = %q", lines[2])
+	}
+
+	buf.Reset()
+	if err := writeProfile(&buf, results, time.Minute, true); err != nil {
+		t.Fatal(err)
+	}
+	var rep pfReport
+	if err := json.Unmarshal(buf.Bytes(), &rep); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if rep.Timeout != "1m0s" || len(rep.Collectors) != 2 || rep.Collectors[0].DurationMS != 1200 {
+		t.Errorf("JSON report = %+v", rep)
+	}
+}
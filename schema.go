@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/schema"
+)
+
+// smExportKey names the schema of the -export document itself in the
+// -schema output. No cache key uses it.
+const smExportKey = "export"
+
+// smSchemas returns a JSON Schema for each cache key -export decodes into
+// a typed payload, plus one for the -export envelope under smExportKey.
+// The same payloads are served by the daemon's HTTP API.
+func smSchemas() map[string]*schema.Schema {
+	out := make(map[string]*schema.Schema, len(exDecoders)+1)
+	for key, newData := range exDecoders {
+		out[key] = schema.Generate(newData(), key)
+	}
+	out[smExportKey] = schema.Generate(exSnapshot{}, "prompt-pulse -export")
+	return out
+}
+
+// writeSchemas writes smSchemas to w as an indented JSON object.
+func writeSchemas(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(smSchemas())
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/schema"
+)
+
+func TestWriteSchemas(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeSchemas(&buf); err != nil {
+		t.Fatalf("writeSchemas: %v", err)
+	}
+	var got map[string]schema.Schema
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not a JSON object of schemas: %v", err)
+	}
+	for key := range exDecoders {
+		s, ok := got[key]
+		if !ok {
+			t.Errorf("missing schema for cache key %s", key)
+			continue
+		}
+		if s.Schema != schema.Draft || !s.Type.Has("object") {
+			t.Errorf("%s: $schema %q type %v, want a %s object schema", key, s.Schema, s.Type, schema.Draft)
+		}
+	}
+	exp, ok := got[smExportKey]
+	if !ok {
+		t.Fatal("missing schema for the -export document")
+	}
+	if _, ok := exp.Properties["collectors"]; !ok {
+		t.Errorf("-export schema lacks the collectors property: %+v", exp.Properties)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/sysmetrics"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/status"
+)
+
+// slSeparator joins statusline parts.
+const slSeparator = " | "
+
+// slColors mark the overall level at the start of the line, together with
+// the glyph from slGlyph.
+var slColors = map[status.Level]string{
+	status.LevelHealthy:  "\033[32m",
+	status.LevelWarning:  "\033[33m",
+	status.LevelCritical: "\033[31m",
+}
+
+// slGlyph returns the marker for lvl from g.
+func slGlyph(lvl status.Level, g components.Glyphs) string {
+	switch lvl {
+	case status.LevelCritical:
+		return g.Crit
+	case status.LevelWarning:
+		return g.Warn
+	default:
+		return g.OK
+	}
+}
+
+// slPart is one statusline segment with a full and a compact rendering.
+type slPart struct {
+	full  string
+	short string
+}
+
+// slReports holds the cached reports a statusline is built from. Nil fields
+// are cache misses and their parts are omitted.
+type slReports struct {
+	claude    *claude.UsageReport
+	billing   *billing.BillingReport
+	tailscale *tailscale.Status
+	k8s       *k8s.ClusterStatus
+	sys       *sysmetrics.Metrics
+
+	// rules grades the reports for the overall level.
+	rules status.EvaluatorConfig
+}
+
+// slLoadReports reads the cache entries used by the statusline that are
+// fresh under ttls, to be graded by rules. Unreadable entries are treated
+// the same as missing ones.
+func slLoadReports(cacheDir string, ttls map[string]time.Duration, rules status.EvaluatorConfig) slReports {
+	r := slReports{rules: rules}
+	r.claude, _ = bnReadCache[claude.UsageReport](cacheDir, "claude", bnCacheTTL(ttls, "claude"))
+	r.billing, _ = bnReadCache[billing.BillingReport](cacheDir, "billing", bnCacheTTL(ttls, "billing"))
+	r.tailscale, _ = bnReadCache[tailscale.Status](cacheDir, "tailscale", bnCacheTTL(ttls, "tailscale"))
+	r.k8s, _ = bnReadCache[k8s.ClusterStatus](cacheDir, "k8s", bnCacheTTL(ttls, "k8s"))
+	r.sys, _ = bnReadCache[sysmetrics.Metrics](cacheDir, "sysmetrics", bnCacheTTL(ttls, "sysmetrics"))
+	return r
+}
+
+// level evaluates the overall status of the loaded reports.
+func (r slReports) level() status.Level {
+	return r.evaluator().Evaluate().Level
+}
+
+// evaluator returns a status.Evaluator that has observed the loaded
+// reports.
+func (r slReports) evaluator() *status.Evaluator {
+	ev := status.NewEvaluator(r.rules)
+	if r.claude != nil {
+		ev.Observe("claude", r.claude)
+	}
+	if r.billing != nil {
+		ev.Observe("billing", r.billing)
+	}
+	if r.tailscale != nil {
+		ev.Observe("tailscale", r.tailscale)
+	}
+	if r.k8s != nil {
+		ev.Observe("k8s", r.k8s)
+	}
+	if r.sys != nil {
+		ev.Observe("sysmetrics", r.sys)
+	}
+	return ev
+}
+
+// parts returns the statusline segments in display order, marking healthy
+// clusters with g.OK.
+func (r slReports) parts(g components.Glyphs) []slPart {
+	var parts []slPart
+
+	if c := r.claude; c != nil && len(c.Accounts) > 0 {
+		if c.HasBudgets() {
+			peak := 0.0
+			for _, a := range c.Accounts {
+				if a.Connected && a.Utilization > peak {
+					peak = a.Utilization
+				}
+			}
+			parts = append(parts, slPart{fmt.Sprintf("claude %.0f%%", peak), fmt.Sprintf("c%.0f%%", peak)})
+		} else {
+			parts = append(parts, slPart{fmt.Sprintf("claude $%.0f", c.TotalCostUSD), fmt.Sprintf("c$%.0f", c.TotalCostUSD)})
+		}
+	}
+
+	if b := r.billing; b != nil && len(b.Providers) > 0 {
+		spend := fmt.Sprintf("$%.0f", b.TotalMonthlyUSD)
+		full := spend
+		if b.BudgetUSD > 0 {
+			full += fmt.Sprintf("/$%.0f", b.BudgetUSD)
+		}
+		parts = append(parts, slPart{full, spend})
+	}
+
+	if ts := r.tailscale; ts != nil && ts.TotalPeers > 0 {
+		peers := fmt.Sprintf("%d/%d", ts.OnlinePeers, ts.TotalPeers)
+		parts = append(parts, slPart{"ts " + peers, "ts" + peers})
+	}
+
+	if ks := r.k8s; ks != nil && len(ks.Clusters) > 0 {
+		down := 0
+		for _, c := range ks.Clusters {
+			if !c.Connected {
+				down++
+				continue
+			}
+			for _, n := range c.Nodes {
+				if !n.Ready {
+					down++
+					break
+				}
+			}
+		}
+		if down == 0 {
+			parts = append(parts, slPart{"k8s ok", "k8s" + g.OK})
+		} else {
+			parts = append(parts, slPart{fmt.Sprintf("k8s %d degraded", down), fmt.Sprintf("k8s!%d", down)})
+		}
+	}
+
+	return parts
+}
+
+// renderStatusline builds the single-line summary. When the line does not
+// fit maxWidth (0 means unlimited) it falls back progressively: compact
+// segments first, then dropping segments from the right, and finally the
+// status glyph alone. Glyphs come from g.
+func renderStatusline(r slReports, maxWidth int, color bool, g components.Glyphs) string {
+	lvl := r.level()
+	glyph := slGlyph(lvl, g)
+	parts := r.parts(g)
+
+	full := make([]string, len(parts))
+	short := make([]string, len(parts))
+	for i, p := range parts {
+		full[i], short[i] = p.full, p.short
+	}
+
+	body := slFit(glyph, full, maxWidth)
+	if body == "" {
+		body = slFit(glyph, short, maxWidth)
+	}
+	for n := len(short) - 1; body == "" && n >= 0; n-- {
+		body = slFit(glyph, short[:n], maxWidth)
+	}
+	if body == "" {
+		return ""
+	}
+
+	if color {
+		body = slColors[lvl] + glyph + "\033[0m" + strings.TrimPrefix(body, glyph)
+	}
+	return body
+}
+
+// slFit joins glyph and segs into one line, or returns "" if the result is
+// wider than maxWidth.
+func slFit(glyph string, segs []string, maxWidth int) string {
+	line := glyph
+	if len(segs) > 0 {
+		line += " " + strings.Join(segs, slSeparator)
+	}
+	if maxWidth > 0 && components.VisibleLen(line) > maxWidth {
+		return ""
+	}
+	return line
+}
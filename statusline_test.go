@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/billing"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
+)
+
+var slTestGlyphs = components.GlyphEmoji.Glyphs()
+
+func slFixture() slReports {
+	return slReports{
+		claude: &claude.UsageReport{Accounts: []claude.AccountUsage{
+			{Name: "work", Connected: true, BudgetUSD: 100, Utilization: 45},
+		}},
+		billing: &billing.BillingReport{
+			TotalMonthlyUSD: 125, BudgetUSD: 200,
+			Providers: []billing.ProviderBilling{{Name: "civo", Connected: true}},
+		},
+		tailscale: &tailscale.Status{
+			Self:        tailscale.PeerInfo{Hostname: "me", Online: true},
+			OnlinePeers: 4, TotalPeers: 5,
+		},
+		k8s: &k8s.ClusterStatus{Clusters: []k8s.ClusterInfo{
+			{Context: "prod", Connected: true, Nodes: []k8s.NodeInfo{{Name: "n1", Ready: true}}},
+		}},
+	}
+}
+
+func TestRenderStatusline_Full(t *testing.T) {
+	got := renderStatusline(slFixture(), 0, false, slTestGlyphs)
+	want := "✓ claude 45% | $125/$200 | ts 4/5 | k8s ok"
+	if got != want {
+		t.Errorf("statusline = %q, want %q", got, want)
+	}
+}
+
+func TestRenderStatusline_LevelGlyphAndColor(t *testing.T) {
+	r := slFixture()
+	r.k8s.Clusters[0].Connected = false
+
+	plain := renderStatusline(r, 0, false, slTestGlyphs)
+	if !strings.HasPrefix(plain, "🔴 ") || !strings.Contains(plain, "k8s 1 degraded") {
+		t.Errorf("expected critical glyph and degraded k8s, got %q", plain)
+	}
+	if strings.Contains(plain, "\033[") {
+		t.Errorf("plain output should have no ANSI codes, got %q", plain)
+	}
+
+	colored := renderStatusline(r, 0, true, slTestGlyphs)
+	if !strings.HasPrefix(colored, "\033[31m🔴\033[0m ") {
+		t.Errorf("expected red glyph, got %q", colored)
+	}
+}
+
+func TestRenderStatusline_OmitsMissing(t *testing.T) {
+	r := slFixture()
+	r.claude, r.k8s = nil, nil
+	if got := renderStatusline(r, 0, false, slTestGlyphs); got != "✓ $125/$200 | ts 4/5" {
+		t.Errorf("statusline = %q", got)
+	}
+	if got := renderStatusline(slReports{}, 0, false, slTestGlyphs); got != "✓" {
+		t.Errorf("empty cache statusline = %q, want bare glyph", got)
+	}
+}
+
+func TestRenderStatusline_ProgressiveFallback(t *testing.T) {
+	r := slFixture()
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{44, "✓ claude 45% | $125/$200 | ts 4/5 | k8s ok"},
+		{35, "✓ c45% | $125 | ts4/5 | k8s✓"},
+		{20, "✓ c45% | $125"},
+		{3, "✓"},
+		{0, "✓ claude 45% | $125/$200 | ts 4/5 | k8s ok"},
+	}
+	for _, tt := range tests {
+		if got := renderStatusline(r, tt.width, false, slTestGlyphs); got != tt.want {
+			t.Errorf("width %d: got %q, want %q", tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestRenderStatusline_ASCIIGlyphs(t *testing.T) {
+	ascii := components.GlyphASCII.Glyphs()
+	r := slFixture()
+	if got := renderStatusline(r, 35, false, ascii); got != "+ c45% | $125 | ts4/5 | k8s+" {
+		t.Errorf("healthy ascii statusline = %q", got)
+	}
+	r.k8s.Clusters[0].Connected = false
+	if got := renderStatusline(r, 0, false, ascii); !strings.HasPrefix(got, "X ") {
+		t.Errorf("critical ascii statusline = %q", got)
+	}
+}
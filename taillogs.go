@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/logging"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/service"
+)
+
+// tlDefaultLines is the number of existing log lines -tail-logs prints
+// before following when -n is not set, as with tail.
+const tlDefaultLines = 10
+
+// tlLogPath returns the daemon log file: the file the -install unit sends
+// the daemon's output to, inside cache_dir.
+func tlLogPath(cfg *config.Config) string {
+	return service.Config{CacheDir: cfg.General.CacheDir}.LogPath()
+}
+
+// runTailLogs writes the last lines of the daemon log at path to w and
+// then streams new ones until ctx is cancelled, following the log across
+// rotation (see logging.Follow). level, when set, drops records below it.
+func runTailLogs(ctx context.Context, w io.Writer, path string, lines int, level string) error {
+	opts := logging.TailOptions{Lines: lines}
+	if level != "" {
+		l, err := logging.ParseLevel(level)
+		if err != nil {
+			return err
+		}
+		opts.MinLevel = l
+	}
+	err := logging.Follow(ctx, path, w, opts)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no daemon log at %s; the daemon writes it when run by the unit from -install", path)
+	}
+	return err
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+func TestRunTailLogs(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.General.CacheDir = t.TempDir()
+	path := tlLogPath(cfg)
+	if path != filepath.Join(cfg.General.CacheDir, "daemon.log") {
+		t.Errorf("tlLogPath = %q", path)
+	}
+
+	var out bytes.Buffer
+	if err := runTailLogs(context.Background(), &out, path, 10, ""); err == nil || !strings.Contains(err.Error(), "no daemon log at") {
+		t.Errorf("missing log error = %v", err)
+	}
+	if err := runTailLogs(context.Background(), &out, path, 10, "loud"); err == nil || !strings.Contains(err.Error(), "unknown log level") {
+		t.Errorf("bad level error = %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("level=INFO msg=a\nlevel=ERROR msg=b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := runTailLogs(ctx, &out, path, 10, "error"); err != nil {
+		t.Fatalf("runTailLogs() error: %v", err)
+	}
+	if got := out.String(); got != "level=ERROR msg=b\n" {
+		t.Errorf("output = %q, want the error record only", got)
+	}
+}
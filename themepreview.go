@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
+)
+
+// tpDefaultWidth is the preview width when -term-width is not given.
+const tpDefaultWidth = 80
+
+// tpMinWidth is the narrowest preview that still fits both sample boxes.
+const tpMinWidth = 40
+
+// tpBoxHeight is the outer height of each sample box: four content lines
+// plus borders.
+const tpBoxHeight = 6
+
+// tpSparkData is the fixed history shown in the sample sparkline.
+var tpSparkData = []float64{3, 5, 4, 6, 8, 7, 9, 6, 5, 7, 10, 12, 9, 11}
+
+// writeThemePreview renders a small sample banner for every registered
+// theme, one after another and labeled by name, so palettes can be
+// compared in the user's own terminal. Each theme is made current while its
+// sample renders; the original theme is restored before returning. It
+// reads no cache and needs no daemon.
+func writeThemePreview(w io.Writer, width int) error {
+	if width <= 0 {
+		width = tpDefaultWidth
+	}
+	if width < tpMinWidth {
+		width = tpMinWidth
+	}
+
+	orig := theme.Current
+	defer func() { theme.Current = orig }()
+
+	for i, name := range theme.Names() {
+		theme.Current = theme.Get(name)
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, tpRenderSample(theme.Current, width)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tpRenderSample renders the label line and two side-by-side sample boxes
+// for t: status lines at each severity and resource gauges at healthy,
+// warning, and critical levels.
+func tpRenderSample(t theme.Theme, width int) string {
+	label := components.Color(t.Title) + components.Bold("▌ "+t.Name) + components.Reset()
+
+	leftW := width / 2
+	rightW := width - leftW
+	boxStyle := func(title string) components.BoxStyle {
+		s := components.DefaultBoxStyle()
+		s.Title = title
+		s.FG = t.Border
+		return s
+	}
+	left := components.RenderBox(tpStatusLines(t), leftW, tpBoxHeight, boxStyle("Services"))
+	right := components.RenderBox(tpUsageLines(t, rightW-2), rightW, tpBoxHeight, boxStyle("Usage"))
+
+	lines := []string{label}
+	ll, rl := strings.Split(left, "\n"), strings.Split(right, "\n")
+	for i := range ll {
+		lines = append(lines, ll[i]+rl[i])
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// tpStatusLines is the sample content for the Services box.
+func tpStatusLines(t theme.Theme) string {
+	rows := []struct{ glyph, name, state, color string }{
+		{"●", "tailscale", "ok", t.StatusOK},
+		{"▲", "billing", "warn", t.StatusWarn},
+		{"✖", "k8s", "crit", t.StatusError},
+		{"○", "claude", "offline", t.StatusUnknown},
+	}
+	lines := make([]string, len(rows))
+	for i, r := range rows {
+		lines[i] = fmt.Sprintf("%s%s%s %-10s %s", components.Color(r.color), r.glyph, components.Reset(), r.name, r.state)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tpUsageLines is the sample content for the Usage box, sized to the
+// box's interior width.
+func tpUsageLines(t theme.Theme, width int) string {
+	gaugeW := width - 10 // "Disk " label plus " 100%"
+	if gaugeW < 5 {
+		gaugeW = 5
+	}
+	gauge := components.NewGauge(components.GaugeStyle{
+		ShowPercent:       true,
+		FilledColor:       t.GaugeFilled,
+		EmptyColor:        t.GaugeEmpty,
+		WarningThreshold:  0.7,
+		CriticalThreshold: 0.9,
+		WarningColor:      t.GaugeWarn,
+		CriticalColor:     t.GaugeCrit,
+	})
+	spark := components.NewSparkline(components.SparklineStyle{Color: t.ChartLine})
+	return strings.Join([]string{
+		"CPU  " + gauge.Render(35, 100, gaugeW),
+		"RAM  " + gauge.Render(78, 100, gaugeW),
+		"Disk " + gauge.Render(95, 100, gaugeW),
+		"Net  " + spark.Render(tpSparkData, gaugeW),
+	}, "\n")
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/components"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/theme"
+)
+
+func TestWriteThemePreview_EveryThemeLabeled(t *testing.T) {
+	theme.SetCurrent("nord")
+	t.Cleanup(func() { theme.SetCurrent("default") })
+
+	var b strings.Builder
+	if err := writeThemePreview(&b, 90); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, name := range theme.Names() {
+		if !strings.Contains(out, "▌ "+name) {
+			t.Errorf("preview missing label for theme %q", name)
+		}
+		if crit := theme.Get(name).StatusError; !strings.Contains(out, components.Color(crit)) {
+			t.Errorf("preview for %q should use its status colors", name)
+		}
+	}
+	if theme.Current.Name != "nord" {
+		t.Errorf("theme.Current = %q after preview, want nord restored", theme.Current.Name)
+	}
+}
+
+func TestWriteThemePreview_Width(t *testing.T) {
+	for _, tt := range []struct{ in, want int }{{0, tpDefaultWidth}, {100, 100}, {10, tpMinWidth}} {
+		var b strings.Builder
+		if err := writeThemePreview(&b, tt.in); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+		perTheme := 1 + tpBoxHeight
+		if want := len(theme.Names())*(perTheme+1) - 1; len(lines) != want {
+			t.Errorf("width %d: %d lines, want %d", tt.in, len(lines), want)
+		}
+		for i, line := range lines {
+			if i%(perTheme+1) == 0 || line == "" {
+				continue // label or separator
+			}
+			if got := components.VisibleLen(line); got != tt.want {
+				t.Errorf("width %d: line %d is %d cells, want %d", tt.in, i, got, tt.want)
+			}
+		}
+	}
+}
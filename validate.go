@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/claude"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/k8s"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/collectors/tailscale"
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// vcProbeTimeout bounds each reachability probe so -validate-config stays
+// quick even when tailscaled is wedged.
+const vcProbeTimeout = 2 * time.Second
+
+// vcReport is the JSON document written by -validate-config -json.
+type vcReport struct {
+	OK          bool                `json:"ok"`
+	Diagnostics []config.Diagnostic `json:"diagnostics"`
+}
+
+// validateConfig runs the static config checks followed by the reachability
+// probes for the enabled infrastructure collectors.
+func validateConfig(cfg *config.Config, ts tailscale.StatusClient, contextNames func(kubeconfig string) ([]string, error)) []config.Diagnostic {
+	diags := config.Validate(cfg)
+	if _, err := claude.ParseSortMode(cfg.Display.ClaudeSort); err != nil {
+		diags = append(diags, config.Diagnostic{Item: "display.claude_sort", Severity: config.SeverityFail, Message: err.Error()})
+	}
+	for _, a := range cfg.Collectors.Claude.Accounts {
+		if t, err := claude.ParseAccountType(a.Type); err != nil {
+			diags = append(diags, config.Diagnostic{Item: "claude.account." + a.Name + ".type", Severity: config.SeverityFail, Message: err.Error()})
+		} else if a.NotifyWeeklyReset && t != claude.AccountTypeOrg {
+			diags = append(diags, config.Diagnostic{Item: "claude.account." + a.Name + ".notify_weekly_reset", Severity: config.SeverityWarn,
+				Message: "only org accounts report a weekly limit; no reset will be announced"})
+		}
+	}
+	if cfg.Collectors.Tailscale.Enabled {
+		diags = append(diags, vcProbeTailscale(ts))
+	}
+	if cfg.Collectors.Kubernetes.Enabled {
+		diags = append(diags, vcProbeKubeContexts(cfg.Collectors.Kubernetes, contextNames)...)
+	}
+	return diags
+}
+
+// vcProbeTailscale checks that the local tailscaled answers a status query.
+// An unreachable daemon is a warning: the collector degrades gracefully.
+func vcProbeTailscale(ts tailscale.StatusClient) config.Diagnostic {
+	ctx, cancel := context.WithTimeout(context.Background(), vcProbeTimeout)
+	defer cancel()
+	st, err := ts.Status(ctx)
+	if err != nil {
+		return config.Diagnostic{Item: "tailscale", Severity: config.SeverityWarn,
+			Message: fmt.Sprintf("tailscaled unreachable: %v", err)}
+	}
+	if st.BackendState != "Running" {
+		return config.Diagnostic{Item: "tailscale", Severity: config.SeverityWarn,
+			Message: "tailscaled reachable but backend is " + st.BackendState}
+	}
+	return config.Diagnostic{Item: "tailscale", Severity: config.SeverityOK, Message: "tailscaled running"}
+}
+
+// vcProbeKubeContexts checks that every configured context exists in the
+// kubeconfig the collector reads it from: its override's, else
+// collectors.kubernetes.kubeconfig, else the default chain. Missing
+// contexts and unreadable kubeconfigs are warnings.
+func vcProbeKubeContexts(kc config.K8sCollectorConfig, contextNames func(kubeconfig string) ([]string, error)) []config.Diagnostic {
+	if len(kc.Contexts) == 0 {
+		names, err := contextNames(kc.Kubeconfig)
+		if err != nil {
+			return []config.Diagnostic{{Item: "kubernetes", Severity: config.SeverityWarn, Message: err.Error()}}
+		}
+		return []config.Diagnostic{{Item: "kubernetes", Severity: config.SeverityOK,
+			Message: fmt.Sprintf("%d contexts in kubeconfig", len(names))}}
+	}
+
+	type loaded struct {
+		have map[string]bool
+		err  error
+	}
+	files := map[string]loaded{}
+	diags := make([]config.Diagnostic, 0, len(kc.Contexts))
+	for _, c := range kc.Contexts {
+		path := kc.Kubeconfig
+		if o, ok := kc.Overrides[c]; ok && o.Kubeconfig != "" {
+			path = o.Kubeconfig
+		}
+		f, ok := files[path]
+		if !ok {
+			names, err := contextNames(path)
+			f = loaded{have: make(map[string]bool, len(names)), err: err}
+			for _, n := range names {
+				f.have[n] = true
+			}
+			files[path] = f
+		}
+		d := config.Diagnostic{Item: "kubernetes." + c, Severity: config.SeverityOK, Message: "context found"}
+		switch {
+		case f.err != nil:
+			d.Severity = config.SeverityWarn
+			d.Message = f.err.Error()
+		case !f.have[c]:
+			d.Severity = config.SeverityWarn
+			d.Message = "context not found in kubeconfig"
+		}
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+// writeValidation prints the diagnostics as an aligned OK/WARN/FAIL table, or
+// as a vcReport when asJSON is set.
+func writeValidation(w io.Writer, diags []config.Diagnostic, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(vcReport{OK: !config.HasFailures(diags), Diagnostics: diags})
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, d := range diags {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", d.Severity, d.Item, d.Message)
+	}
+	return tw.Flush()
+}
+
+// vcContextNames lists the contexts in kubeconfig, or in the default
+// kubeconfig chain when it is empty.
+func vcContextNames(kubeconfig string) ([]string, error) {
+	return k8s.ContextNames(kubeconfig)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"tailscale.com/ipn/ipnstate"
+
+	"gitlab.com/tinyland/lab/prompt-pulse/pkg/config"
+)
+
+// vcStubTailscale is a tailscale.StatusClient returning a canned result.
+type vcStubTailscale struct {
+	st  *ipnstate.Status
+	err error
+}
+
+func (s vcStubTailscale) Status(context.Context) (*ipnstate.Status, error) { return s.st, s.err }
+
+func TestValidateConfig_ReachabilityIsNonFatal(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Collectors.Claude.Enabled = false
+	cfg.Collectors.Billing.Enabled = false
+	cfg.Collectors.Tailscale.Enabled = true
+	cfg.Collectors.Kubernetes.Enabled = true
+	cfg.Collectors.Kubernetes.Contexts = []string{"prod", "gone"}
+
+	diags := validateConfig(cfg,
+		vcStubTailscale{err: errors.New("connection refused")},
+		func(string) ([]string, error) { return []string{"prod"}, nil })
+
+	got := map[string]config.Severity{}
+	for _, d := range diags {
+		got[d.Item] = d.Severity
+	}
+	if got["tailscale"] != config.SeverityWarn {
+		t.Errorf("tailscale = %s, want WARN", got["tailscale"])
+	}
+	if got["kubernetes.prod"] != config.SeverityOK || got["kubernetes.gone"] != config.SeverityWarn {
+		t.Errorf("kubernetes diagnostics = %v", got)
+	}
+	if config.HasFailures(diags) {
+		t.Error("reachability problems should not be failures")
+	}
+}
+
+func TestValidateConfig_ConfiguredKubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	writeKubeconfig := func(name string, contexts ...string) string {
+		t.Helper()
+		var b strings.Builder
+		b.WriteString("apiVersion: v1\nkind: Config\nclusters:\n- name: c\n  cluster:\n    server: https://127.0.0.1:6443\nusers:\n- name: u\n  user: {}\ncontexts:\n")
+		for _, c := range contexts {
+			fmt.Fprintf(&b, "- name: %s\n  context:\n    cluster: c\n    user: u\n", c)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	// The default chain has none of the contexts.
+	t.Setenv("KUBECONFIG", writeKubeconfig("default", "other"))
+
+	kc := config.DefaultConfig().Collectors.Kubernetes
+	kc.Kubeconfig = writeKubeconfig("lab", "prod", "staging")
+	kc.Contexts = []string{"prod", "edge", "gone"}
+	kc.Overrides = map[string]config.K8sContextConfig{"edge": {Kubeconfig: writeKubeconfig("edge", "edge")}}
+
+	got := map[string]config.Severity{}
+	for _, d := range vcProbeKubeContexts(kc, vcContextNames) {
+		got[d.Item] = d.Severity
+	}
+	want := map[string]config.Severity{
+		"kubernetes.prod": config.SeverityOK,
+		"kubernetes.edge": config.SeverityOK,
+		"kubernetes.gone": config.SeverityWarn,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diagnostics = %v, want %v", got, want)
+	}
+
+	kc.Contexts = nil
+	if d := vcProbeKubeContexts(kc, vcContextNames); len(d) != 1 || d[0].Message != "2 contexts in kubeconfig" {
+		t.Errorf("without contexts = %+v, want the 2 in the configured file", d)
+	}
+}
+
+func TestValidateConfig_TailscaleRunning(t *testing.T) {
+	d := vcProbeTailscale(vcStubTailscale{st: &ipnstate.Status{BackendState: "Running"}})
+	if d.Severity != config.SeverityOK {
+		t.Errorf("severity = %s, want OK (%s)", d.Severity, d.Message)
+	}
+}
+
+func TestWriteValidation_Formats(t *testing.T) {
+	diags := []config.Diagnostic{
+		{Item: "claude", Severity: config.SeverityOK, Message: "admin key present"},
+		{Item: "billing.civo", Severity: config.SeverityFail, Message: "no API key"},
+	}
+
+	var text bytes.Buffer
+	if err := writeValidation(&text, diags, false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(text.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "FAIL") {
+		t.Errorf("unexpected text report:\n%s", text.String())
+	}
+
+	var js bytes.Buffer
+	if err := writeValidation(&js, diags, true); err != nil {
+		t.Fatal(err)
+	}
+	var rep vcReport
+	if err := json.Unmarshal(js.Bytes(), &rep); err != nil {
+		t.Fatalf("json output does not parse: %v", err)
+	}
+	if rep.OK || len(rep.Diagnostics) != 2 {
+		t.Errorf("report = %+v, want ok=false with 2 diagnostics", rep)
+	}
+}